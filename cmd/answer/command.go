@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"answer/internal/base/conf"
+	"answer/internal/base/translator"
 	"answer/internal/cli"
 	"answer/internal/install"
 	"answer/internal/migrations"
@@ -17,6 +18,14 @@ var (
 	dataDirPath string
 	// dumpDataPath dump data path
 	dumpDataPath string
+	// i18nBundlePath the directory of the translation bundles to check
+	i18nBundlePath string
+	// recalcDryRun only report the reputation differences without fixing them
+	recalcDryRun bool
+	// recalcAfterUserID resume the recalculation from the users after the user id
+	recalcAfterUserID string
+	// recalcBatchSize the number of the users recalculated in one batch
+	recalcBatchSize int
 )
 
 func init() {
@@ -26,7 +35,14 @@ func init() {
 
 	dumpCmd.Flags().StringVarP(&dumpDataPath, "path", "p", "./", "dump data path, eg: -p ./dump/data/")
 
-	for _, cmd := range []*cobra.Command{initCmd, checkCmd, runCmd, dumpCmd, upgradeCmd} {
+	i18nCheckCmd.Flags().StringVarP(&i18nBundlePath, "path", "p", "", "i18n bundle path, default is the i18n directory in data path, eg: -p ./i18n/")
+	i18nCmd.AddCommand(i18nCheckCmd)
+
+	recalcReputationCmd.Flags().BoolVar(&recalcDryRun, "dry-run", false, "only report the differences, nothing is changed")
+	recalcReputationCmd.Flags().StringVar(&recalcAfterUserID, "after", "0", "resume from the users after the user id, eg: --after 10025")
+	recalcReputationCmd.Flags().IntVar(&recalcBatchSize, "batch-size", 100, "the number of the users in one batch, 1 to 1000")
+
+	for _, cmd := range []*cobra.Command{initCmd, checkCmd, runCmd, dumpCmd, upgradeCmd, i18nCmd, recalcReputationCmd} {
 		rootCmd.AddCommand(cmd)
 	}
 }
@@ -161,6 +177,72 @@ To run answer, use:
 			fmt.Println("check environment all done")
 		},
 	}
+
+	// recalcReputationCmd represents the recalc-reputation command
+	recalcReputationCmd = &cobra.Command{
+		Use:   "recalc-reputation",
+		Short: "recalculate the reputation of all users",
+		Long:  `Recalculate the reputation of all users from their activities under the current rules, report and fix the differences`,
+		Run: func(_ *cobra.Command, _ []string) {
+			cli.FormatAllPath(dataDirPath)
+			c, err := conf.ReadConfig(cli.GetConfigFilePath())
+			if err != nil {
+				fmt.Println("read config failed: ", err.Error())
+				os.Exit(1)
+			}
+			if err = cli.RecalculateReputation(c.Data.Database, recalcDryRun, recalcAfterUserID, recalcBatchSize); err != nil {
+				fmt.Println("recalculate reputation failed: ", err.Error())
+				os.Exit(1)
+			}
+			if recalcDryRun {
+				fmt.Println("dry run done, nothing is changed")
+				return
+			}
+			fmt.Println("recalculate reputation done")
+		},
+	}
+
+	// i18nCmd represents the i18n command
+	i18nCmd = &cobra.Command{
+		Use:   "i18n",
+		Short: "manage the translation bundles",
+		Long:  `Manage the translation bundles`,
+	}
+
+	// i18nCheckCmd represents the i18n check command
+	i18nCheckCmd = &cobra.Command{
+		Use:   "check",
+		Short: "check the keys of the translation bundles",
+		Long:  `Report the missing and extra keys of each translation bundle against en_US.yaml`,
+		Run: func(_ *cobra.Command, _ []string) {
+			if len(i18nBundlePath) == 0 {
+				cli.FormatAllPath(dataDirPath)
+				i18nBundlePath = cli.I18nPath
+			}
+			results, err := translator.CheckBundles(i18nBundlePath)
+			if err != nil {
+				fmt.Println("check i18n bundles failed: ", err.Error())
+				os.Exit(1)
+			}
+			hasMissing := false
+			for _, result := range results {
+				fmt.Printf("[%s] missing keys: %d, extra keys: %d\n", result.Language, len(result.MissingKeys), len(result.ExtraKeys))
+				for _, key := range result.MissingKeys {
+					fmt.Printf("  - %s\n", key)
+				}
+				for _, key := range result.ExtraKeys {
+					fmt.Printf("  + %s\n", key)
+				}
+				if len(result.MissingKeys) > 0 {
+					hasMissing = true
+				}
+			}
+			if hasMissing {
+				os.Exit(1)
+			}
+			fmt.Println("all i18n bundles are complete")
+		},
+	}
 )
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -6,6 +6,7 @@ import (
 
 	"answer/internal/base/conf"
 	"answer/internal/base/constant"
+	"answer/internal/base/scheduler"
 	"answer/internal/cli"
 	"answer/internal/schema"
 
@@ -60,10 +61,10 @@ func runApp() {
 	}
 }
 
-func newApplication(serverConf *conf.Server, server *gin.Engine) *pacman.Application {
+func newApplication(serverConf *conf.Server, server *gin.Engine, scheduler *scheduler.Scheduler) *pacman.Application {
 	return pacman.NewApp(
 		pacman.WithName(Name),
 		pacman.WithVersion(Version),
-		pacman.WithServer(http.NewServer(server, serverConf.HTTP.Addr)),
+		pacman.WithServer(http.NewServer(server, serverConf.HTTP.Addr), scheduler),
 	)
 }
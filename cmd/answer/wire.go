@@ -9,6 +9,7 @@ import (
 	"answer/internal/base/conf"
 	"answer/internal/base/data"
 	"answer/internal/base/middleware"
+	"answer/internal/base/scheduler"
 	"answer/internal/base/server"
 	"answer/internal/base/translator"
 	"answer/internal/controller"
@@ -41,6 +42,7 @@ func initApplication(
 		repo.ProviderSetRepo,
 		translator.ProviderSet,
 		middleware.ProviderSetMiddleware,
+		scheduler.ProviderSet,
 		newApplication,
 	))
 }
@@ -10,6 +10,7 @@ import (
 	"answer/internal/base/conf"
 	"answer/internal/base/data"
 	"answer/internal/base/middleware"
+	"answer/internal/base/scheduler"
 	"answer/internal/base/server"
 	"answer/internal/base/translator"
 	"answer/internal/controller"
@@ -23,13 +24,18 @@ import (
 	"answer/internal/repo/comment"
 	"answer/internal/repo/common"
 	"answer/internal/repo/config"
+	"answer/internal/repo/draft"
 	"answer/internal/repo/export"
+	"answer/internal/repo/mention"
 	"answer/internal/repo/meta"
+	"answer/internal/repo/moderation"
+	"answer/internal/repo/moderator_message"
 	"answer/internal/repo/notification"
 	"answer/internal/repo/question"
 	"answer/internal/repo/rank"
 	"answer/internal/repo/reason"
 	"answer/internal/repo/report"
+	"answer/internal/repo/review"
 	"answer/internal/repo/revision"
 	"answer/internal/repo/search_common"
 	"answer/internal/repo/site_info"
@@ -48,28 +54,45 @@ import (
 	comment2 "answer/internal/service/comment"
 	"answer/internal/service/comment_common"
 	"answer/internal/service/dashboard"
+	draft2 "answer/internal/service/draft"
 	export2 "answer/internal/service/export"
+	"answer/internal/service/feed"
 	"answer/internal/service/follow"
+	mention2 "answer/internal/service/mention"
 	meta2 "answer/internal/service/meta"
+	moderation2 "answer/internal/service/moderation"
+	moderator_message2 "answer/internal/service/moderator_message"
 	notification2 "answer/internal/service/notification"
 	"answer/internal/service/notification_common"
 	"answer/internal/service/object_info"
+	"answer/internal/service/question_close_vote"
 	"answer/internal/service/question_common"
+	"answer/internal/service/question_view"
 	rank2 "answer/internal/service/rank"
 	reason2 "answer/internal/service/reason"
 	report2 "answer/internal/service/report"
 	"answer/internal/service/report_backyard"
 	"answer/internal/service/report_handle_backyard"
+	"answer/internal/service/reputation"
+	review2 "answer/internal/service/review"
 	"answer/internal/service/revision_common"
 	"answer/internal/service/search_parser"
+	"answer/internal/service/seo"
 	"answer/internal/service/service_config"
 	"answer/internal/service/siteinfo"
 	"answer/internal/service/siteinfo_common"
 	tag2 "answer/internal/service/tag"
 	tag_common2 "answer/internal/service/tag_common"
+	"answer/internal/service/tag_moderator"
+	"answer/internal/service/tag_subscription"
+	"answer/internal/service/tag_template"
 	"answer/internal/service/uploader"
 	"answer/internal/service/user_backyard"
 	"answer/internal/service/user_common"
+	"answer/internal/service/user_data"
+	"answer/internal/service/user_invite"
+	"answer/internal/service/user_suspension"
+	"answer/internal/service/user_two_factor"
 	"github.com/segmentfault/pacman"
 	"github.com/segmentfault/pacman/log"
 )
@@ -79,20 +102,25 @@ import (
 // initApplication init application.
 func initApplication(debug bool, serverConf *conf.Server, dbConf *data.Database, cacheConf *data.CacheConf, i18nConf *translator.I18n, swaggerConf *router.SwaggerConfig, serviceConf *service_config.ServiceConfig, logConf log.Logger) (*pacman.Application, func(), error) {
 	staticRouter := router.NewStaticRouter(serviceConf)
-	i18nTranslator, err := translator.NewTranslator(i18nConf)
+	schedulerScheduler, cleanup := scheduler.NewScheduler()
+	i18nTranslator, err := translator.NewTranslator(i18nConf, schedulerScheduler)
 	if err != nil {
+		cleanup()
 		return nil, nil, err
 	}
 	engine, err := data.NewDB(debug, dbConf)
 	if err != nil {
+		cleanup()
 		return nil, nil, err
 	}
-	cache, cleanup, err := data.NewCache(cacheConf)
+	cache, cleanup2, err := data.NewCache(cacheConf)
 	if err != nil {
+		cleanup()
 		return nil, nil, err
 	}
-	dataData, cleanup2, err := data.NewData(engine, cache)
+	dataData, cleanup3, err := data.NewData(engine, cache)
 	if err != nil {
+		cleanup2()
 		cleanup()
 		return nil, nil, err
 	}
@@ -100,7 +128,8 @@ func initApplication(debug bool, serverConf *conf.Server, dbConf *data.Database,
 	siteInfoCommonService := siteinfo_common.NewSiteInfoCommonService(siteInfoRepo)
 	langController := controller.NewLangController(i18nTranslator, siteInfoCommonService)
 	authRepo := auth.NewAuthRepo(dataData)
-	authService := auth2.NewAuthService(authRepo)
+	userSessionRepo := auth.NewUserSessionRepo(dataData)
+	authService := auth2.NewAuthService(authRepo, userSessionRepo)
 	configRepo := config.NewConfigRepo(dataData)
 	userRepo := user.NewUserRepo(dataData, configRepo)
 	uniqueIDRepo := unique.NewUniqueIDRepo(dataData)
@@ -109,7 +138,13 @@ func initApplication(debug bool, serverConf *conf.Server, dbConf *data.Database,
 	userActiveActivityRepo := activity.NewUserActiveActivityRepo(dataData, activityRepo, userRankRepo, configRepo)
 	emailRepo := export.NewEmailRepo(dataData)
 	emailService := export2.NewEmailService(configRepo, emailRepo, siteInfoRepo)
-	userService := service.NewUserService(userRepo, userActiveActivityRepo, emailService, authService, serviceConf, siteInfoCommonService)
+	userInviteRepo := user.NewUserInviteRepo(dataData)
+	userInviteService := user_invite.NewUserInviteService(userInviteRepo, siteInfoCommonService)
+	userTwoFactorRepo := user.NewUserTwoFactorRepo(dataData)
+	userTwoFactorService := user_two_factor.NewUserTwoFactorService(userTwoFactorRepo, userRepo, siteInfoCommonService)
+	userSuspensionRepo := user.NewUserSuspensionRepo(dataData, authRepo)
+	userSuspensionService := user_suspension.NewUserSuspensionService(userSuspensionRepo, schedulerScheduler)
+	userService := service.NewUserService(userRepo, userActiveActivityRepo, emailService, authService, serviceConf, siteInfoCommonService, userInviteService, userTwoFactorService, userSuspensionService)
 	captchaRepo := captcha.NewCaptchaRepo(dataData)
 	captchaService := action.NewCaptchaService(captchaRepo)
 	uploaderService := uploader.NewUploaderService(serviceConf, siteInfoCommonService)
@@ -119,6 +154,7 @@ func initApplication(debug bool, serverConf *conf.Server, dbConf *data.Database,
 	userCommon := usercommon.NewUserCommon(userRepo)
 	answerRepo := answer.NewAnswerRepo(dataData, uniqueIDRepo, userRankRepo, activityRepo)
 	questionRepo := question.NewQuestionRepo(dataData, uniqueIDRepo)
+	questionScoreRepo := question.NewQuestionScoreRepo(dataData)
 	tagCommonRepo := tag_common.NewTagCommonRepo(dataData, uniqueIDRepo)
 	tagRelRepo := tag.NewTagRelRepo(dataData)
 	tagRepo := tag.NewTagRepo(dataData, uniqueIDRepo)
@@ -127,20 +163,26 @@ func initApplication(debug bool, serverConf *conf.Server, dbConf *data.Database,
 	tagCommonService := tag_common2.NewTagCommonService(tagCommonRepo, tagRelRepo, tagRepo, revisionService, siteInfoCommonService)
 	objService := object_info.NewObjService(answerRepo, questionRepo, commentCommonRepo, tagCommonRepo, tagCommonService)
 	voteRepo := activity_common.NewVoteRepo(dataData, activityRepo)
-	commentService := comment2.NewCommentService(commentRepo, commentCommonRepo, userCommon, objService, voteRepo)
+	reportRepo := report.NewReportRepo(dataData, uniqueIDRepo)
+	moderationRepo := moderation.NewModerationRepo(dataData)
+	moderationService := moderation2.NewModerationService(moderationRepo, reportRepo, userCommon, siteInfoCommonService)
+	commentService := comment2.NewCommentService(commentRepo, commentCommonRepo, userCommon, objService, voteRepo, moderationService, siteInfoCommonService, revisionService)
 	rankService := rank2.NewRankService(userCommon, userRankRepo, objService, configRepo)
 	commentController := controller.NewCommentController(commentService, rankService)
-	reportRepo := report.NewReportRepo(dataData, uniqueIDRepo)
 	reportService := report2.NewReportService(reportRepo, objService)
 	reportController := controller.NewReportController(reportService, rankService)
 	serviceVoteRepo := activity.NewVoteRepo(dataData, uniqueIDRepo, configRepo, activityRepo, userRankRepo, voteRepo)
-	voteService := service.NewVoteService(serviceVoteRepo, uniqueIDRepo, configRepo, questionRepo, answerRepo, commentCommonRepo, objService)
+	voteService := service.NewVoteService(serviceVoteRepo, uniqueIDRepo, configRepo, questionRepo, answerRepo, commentCommonRepo, objService, questionScoreRepo)
 	voteController := controller.NewVoteController(voteService, rankService)
 	followRepo := activity_common.NewFollowRepo(dataData, uniqueIDRepo, activityRepo)
-	tagService := tag2.NewTagService(tagRepo, tagCommonService, revisionService, followRepo, siteInfoCommonService)
-	tagController := controller.NewTagController(tagService, tagCommonService, rankService)
+	tagTemplateRepo := tag.NewTagTemplateRepo(dataData)
+	tagModeratorRepo := tag.NewTagModeratorRepo(dataData)
+	tagModeratorService := tag_moderator.NewTagModeratorService(tagModeratorRepo, tagCommonService, userCommon, siteInfoCommonService, schedulerScheduler)
+	tagTemplateService := tag_template.NewTagTemplateService(tagTemplateRepo, tagCommonService, tagModeratorService)
+	tagService := tag2.NewTagService(tagRepo, tagCommonService, revisionService, followRepo, siteInfoCommonService, tagTemplateService, tagModeratorService)
+	tagController := controller.NewTagController(tagService, tagCommonService, rankService, tagTemplateService, tagModeratorService)
 	followFollowRepo := activity.NewFollowRepo(dataData, uniqueIDRepo, activityRepo)
-	followService := follow.NewFollowService(followFollowRepo, followRepo, tagCommonRepo)
+	followService := follow.NewFollowService(followFollowRepo, followRepo, tagCommonRepo, userRepo, userCommon)
 	followController := controller.NewFollowController(followService)
 	collectionRepo := collection.NewCollectionRepo(dataData, uniqueIDRepo)
 	collectionGroupRepo := collection.NewCollectionGroupRepo(dataData)
@@ -150,20 +192,34 @@ func initApplication(debug bool, serverConf *conf.Server, dbConf *data.Database,
 	metaService := meta2.NewMetaService(metaRepo)
 	questionCommon := questioncommon.NewQuestionCommon(questionRepo, answerRepo, voteRepo, followRepo, tagCommonService, userCommon, collectionCommon, answerCommon, metaService, configRepo)
 	collectionService := service.NewCollectionService(collectionRepo, collectionGroupRepo, questionCommon)
-	collectionController := controller.NewCollectionController(collectionService)
+	collectionGroupService := service.NewCollectionGroupService(collectionGroupRepo, collectionRepo, questionCommon, userCommon, siteInfoCommonService)
+	collectionController := controller.NewCollectionController(collectionService, collectionGroupService)
 	answerActivityRepo := activity.NewAnswerActivityRepo(dataData, activityRepo, userRankRepo)
 	questionActivityRepo := activity.NewQuestionActivityRepo(dataData, activityRepo, userRankRepo)
 	answerActivityService := activity2.NewAnswerActivityService(answerActivityRepo, questionActivityRepo)
-	questionService := service.NewQuestionService(questionRepo, tagCommonService, questionCommon, userCommon, revisionService, metaService, collectionCommon, answerActivityService)
-	questionController := controller.NewQuestionController(questionService, rankService)
-	answerService := service.NewAnswerService(answerRepo, questionRepo, questionCommon, userCommon, collectionCommon, userRepo, revisionService, answerActivityService, answerCommon, voteRepo)
+	questionViewRepo := question.NewQuestionViewRepo(dataData)
+	questionViewService, cleanup4 := question_view.NewQuestionViewService(questionViewRepo, questionScoreRepo, schedulerScheduler)
+	tagSubscriptionRepo := tag.NewTagSubscriptionRepo(dataData)
+	tagSubscriptionService := tag_subscription.NewTagSubscriptionService(tagSubscriptionRepo, tagCommonService, questionRepo, userRepo, emailService, schedulerScheduler)
+	mentionRepo := mention.NewMentionRepo(dataData)
+	mentionService := mention2.NewMentionService(mentionRepo, userRepo, userCommon)
+	reviewRepo := review.NewReviewRepo(dataData)
+	reviewService := review2.NewReviewService(reviewRepo, userRepo, userCommon, questionRepo, answerRepo, questionCommon, siteInfoCommonService, rankService, objService, tagModeratorService, mentionService, revisionService, tagCommonService)
+	draftRepo := draft.NewDraftRepo(dataData)
+	draftService := draft2.NewDraftService(draftRepo, schedulerScheduler)
+	answerAcceptLogRepo := answer.NewAnswerAcceptLogRepo(dataData)
+	answerService := service.NewAnswerService(answerRepo, questionRepo, questionCommon, userCommon, collectionCommon, userRepo, revisionService, answerActivityService, answerCommon, voteRepo, reviewService, moderationService, draftService, mentionService, answerAcceptLogRepo, siteInfoCommonService, commentRepo, dataData)
+	questionService := service.NewQuestionService(questionRepo, tagCommonService, questionCommon, userCommon, revisionService, metaService, collectionCommon, answerActivityService, questionViewService, reviewService, moderationService, draftService, mentionService, answerService, tagTemplateService, tagSubscriptionService)
+	questionCloseVoteRepo := question.NewQuestionCloseVoteRepo(dataData)
+	questionCloseVoteService := question_close_vote.NewQuestionCloseVoteService(questionCloseVoteRepo, questionRepo, questionCommon, siteInfoCommonService, dataData)
+	questionController := controller.NewQuestionController(questionService, rankService, questionCloseVoteService, tagModeratorService)
 	dashboardService := dashboard.NewDashboardService(questionRepo, answerRepo, commentCommonRepo, voteRepo, userRepo, reportRepo, configRepo, siteInfoCommonService, serviceConf, dataData)
-	answerController := controller.NewAnswerController(answerService, rankService, dashboardService)
+	answerController := controller.NewAnswerController(answerService, rankService, dashboardService, tagModeratorService)
 	searchParser := search_parser.NewSearchParser(tagCommonService, userCommon)
 	searchRepo := search_common.NewSearchRepo(dataData, uniqueIDRepo, userCommon)
-	searchService := service.NewSearchService(searchParser, searchRepo)
+	searchService := service.NewSearchService(searchParser, searchRepo, userCommon)
 	searchController := controller.NewSearchController(searchService)
-	serviceRevisionService := service.NewRevisionService(revisionRepo, userCommon, questionCommon, answerService, objService, questionRepo, answerRepo, tagRepo, tagCommonService)
+	serviceRevisionService := service.NewRevisionService(revisionRepo, userCommon, questionCommon, answerService, objService, questionRepo, answerRepo, tagRepo, tagCommonService, mentionService)
 	revisionController := controller.NewRevisionController(serviceRevisionService, rankService)
 	rankController := controller.NewRankController(rankService)
 	commonRepo := common.NewCommonRepo(dataData, uniqueIDRepo)
@@ -171,7 +227,7 @@ func initApplication(debug bool, serverConf *conf.Server, dbConf *data.Database,
 	reportBackyardService := report_backyard.NewReportBackyardService(reportRepo, userCommon, commonRepo, answerRepo, questionRepo, commentCommonRepo, reportHandle, configRepo)
 	controller_backyardReportController := controller_backyard.NewReportController(reportBackyardService)
 	userBackyardRepo := user.NewUserBackyardRepo(dataData, authRepo)
-	userBackyardService := user_backyard.NewUserBackyardService(userBackyardRepo)
+	userBackyardService := user_backyard.NewUserBackyardService(userBackyardRepo, authService, userSuspensionService)
 	userBackyardController := controller_backyard.NewUserBackyardController(userBackyardService)
 	reasonRepo := reason.NewReasonRepo(configRepo)
 	reasonService := reason2.NewReasonService(reasonRepo)
@@ -186,19 +242,48 @@ func initApplication(debug bool, serverConf *conf.Server, dbConf *data.Database,
 	notificationController := controller.NewNotificationController(notificationService, rankService)
 	dashboardController := controller.NewDashboardController(dashboardService)
 	uploadController := controller.NewUploadController(uploaderService)
-	activityCommon := activity_common2.NewActivityCommon(activityRepo)
+	activityCommon := activity_common2.NewActivityCommon(activityRepo, questionScoreRepo)
 	activityActivityRepo := activity.NewActivityRepo(dataData)
 	commentCommonService := comment_common.NewCommentCommonService(commentCommonRepo)
-	activityService := activity2.NewActivityService(activityActivityRepo, userCommon, activityCommon, tagCommonService, objService, commentCommonService, revisionService, metaService)
+	activityService := activity2.NewActivityService(activityActivityRepo, userCommon, activityCommon, tagCommonService, objService, commentCommonService, revisionService, metaService, answerAcceptLogRepo)
 	activityController := controller.NewActivityController(activityCommon, activityService)
-	answerAPIRouter := router.NewAnswerAPIRouter(langController, userController, commentController, reportController, voteController, tagController, followController, collectionController, questionController, answerController, searchController, revisionController, rankController, controller_backyardReportController, userBackyardController, reasonController, themeController, siteInfoController, siteinfoController, notificationController, dashboardController, uploadController, activityController)
+	userInviteController := controller.NewUserInviteController(userInviteService)
+	controller_backyardUserInviteController := controller_backyard.NewUserInviteController(userInviteService)
+	userDataRepo := user.NewUserDataRepo(dataData)
+	userDeletionRepo := user.NewUserDeletionRepo(dataData)
+	userDataExportRepo := user.NewUserDataExportRepo(dataData)
+	userDataService := user_data.NewUserDataService(userDataExportRepo, userRepo, userDataRepo, userDeletionRepo, configRepo, authService, schedulerScheduler)
+	userDataController := controller.NewUserDataController(userDataService)
+	userTwoFactorController := controller.NewUserTwoFactorController(userTwoFactorService)
+	controller_backyardUserTwoFactorController := controller_backyard.NewUserTwoFactorController(userTwoFactorService)
+	moderationController := controller_backyard.NewModerationController(moderationService)
+	userSuspensionController := controller_backyard.NewUserSuspensionController(userSuspensionService)
+	reviewController := controller.NewReviewController(reviewService, rankService)
+	moderatorMessageRepo := moderator_message.NewModeratorMessageRepo(dataData)
+	moderatorMessageService := moderator_message2.NewModeratorMessageService(moderatorMessageRepo, userRepo, userCommon, emailService)
+	moderatorMessageController := controller.NewModeratorMessageController(moderatorMessageService)
+	controller_backyardModeratorMessageController := controller_backyard.NewModeratorMessageController(moderatorMessageService)
+	draftController := controller.NewDraftController(draftService)
+	feedRepo := activity.NewFeedRepo(dataData, activityRepo)
+	feedService := feed.NewFeedService(feedRepo, followRepo, activityRepo, questionRepo, answerRepo, userRepo, userCommon, emailService, schedulerScheduler)
+	feedController := controller.NewFeedController(feedService)
+	mentionController := controller.NewMentionController(mentionService)
+	tagSubscriptionController := controller.NewTagSubscriptionController(tagSubscriptionService)
+	reputationRepo := rank.NewReputationRepo(dataData)
+	reputationService := reputation.NewReputationService(reputationRepo, configRepo)
+	reputationController := controller_backyard.NewReputationController(reputationService)
+	answerAPIRouter := router.NewAnswerAPIRouter(langController, userController, commentController, reportController, voteController, tagController, followController, collectionController, questionController, answerController, searchController, revisionController, rankController, controller_backyardReportController, userBackyardController, reasonController, themeController, siteInfoController, siteinfoController, notificationController, dashboardController, uploadController, activityController, userInviteController, controller_backyardUserInviteController, userDataController, userTwoFactorController, controller_backyardUserTwoFactorController, reviewController, moderationController, userSuspensionController, moderatorMessageController, controller_backyardModeratorMessageController, draftController, feedController, mentionController, tagSubscriptionController, reputationController)
 	swaggerRouter := router.NewSwaggerRouter(swaggerConf)
-	uiRouter := router.NewUIRouter()
-	authUserMiddleware := middleware.NewAuthUserMiddleware(authService)
+	seoService := seo.NewSeoService(dataData, siteInfoCommonService, questionRepo, answerRepo, tagCommonService, userCommon)
+	seoController := controller.NewSeoController(seoService)
+	authUserMiddleware := middleware.NewAuthUserMiddleware(authService, siteInfoCommonService)
+	uiRouter := router.NewUIRouter(seoController, authUserMiddleware)
 	avatarMiddleware := middleware.NewAvatarMiddleware(serviceConf, uploaderService)
 	ginEngine := server.NewHTTPServer(debug, staticRouter, answerAPIRouter, swaggerRouter, uiRouter, authUserMiddleware, avatarMiddleware)
-	application := newApplication(serverConf, ginEngine)
+	application := newApplication(serverConf, ginEngine, schedulerScheduler)
 	return application, func() {
+		cleanup4()
+		cleanup3()
 		cleanup2()
 		cleanup()
 	}, nil
@@ -25,7 +25,6 @@ require (
 	github.com/segmentfault/pacman v1.0.1
 	github.com/segmentfault/pacman/contrib/cache/memory v0.0.0-20221207032920-3662d1e32068
 	github.com/segmentfault/pacman/contrib/conf/viper v0.0.0-20221207032920-3662d1e32068
-	github.com/segmentfault/pacman/contrib/i18n v0.0.0-20221207032920-3662d1e32068
 	github.com/segmentfault/pacman/contrib/log/zap v0.0.0-20221207032920-3662d1e32068
 	github.com/segmentfault/pacman/contrib/server/http v0.0.0-20221207032920-3662d1e32068
 	github.com/spf13/cobra v1.6.1
@@ -45,7 +44,6 @@ require (
 require (
 	github.com/Azure/go-ansiterm v0.0.0-20170929234023-d6e3b3328b78 // indirect
 	github.com/KyleBanks/depth v1.2.1 // indirect
-	github.com/LinkinStars/go-i18n/v2 v2.2.2 // indirect
 	github.com/Microsoft/go-winio v0.5.2 // indirect
 	github.com/Nvveen/Gotty v0.0.0-20120604004816-cd527374f1e5 // indirect
 	github.com/PuerkitoBio/purell v1.1.1 // indirect
@@ -114,5 +112,4 @@ require (
 	gopkg.in/alexcesaro/quotedprintable.v3 v3.0.0-20150716171945-2caba252f4dc // indirect
 	gopkg.in/ini.v1 v1.67.0 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
-	sigs.k8s.io/yaml v1.3.0 // indirect
 )
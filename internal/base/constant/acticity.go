@@ -14,6 +14,7 @@ const (
 	ActFollow    = "follow"
 	ActAccepted  = "accepted"
 	ActAccept    = "accept"
+	ActUnaccept  = "unaccept"
 )
 
 const (
@@ -29,6 +30,11 @@ const (
 	ActQuestionRollback  ActivityTypeKey = "question.rollback"
 	ActQuestionDeleted   ActivityTypeKey = "question.deleted"
 	ActQuestionUndeleted ActivityTypeKey = "question.undeleted"
+
+	// ActQuestionCloseVoted the user voted to close the question
+	ActQuestionCloseVoted ActivityTypeKey = "question.close_voted"
+	// ActQuestionReopenVoted the user voted to reopen the question
+	ActQuestionReopenVoted ActivityTypeKey = "question.reopen_voted"
 )
 
 const (
@@ -41,6 +47,9 @@ const (
 	ActAnswerRollback  ActivityTypeKey = "answer.rollback"
 	ActAnswerDeleted   ActivityTypeKey = "answer.deleted"
 	ActAnswerUndeleted ActivityTypeKey = "answer.undeleted"
+
+	// ActAnswerConverted the moderator converted the comment to the answer
+	ActAnswerConverted ActivityTypeKey = "answer.converted"
 )
 
 const (
@@ -50,3 +59,9 @@ const (
 	ActTagDeleted   ActivityTypeKey = "tag.deleted"
 	ActTagUndeleted ActivityTypeKey = "tag.undeleted"
 )
+
+const (
+	ActQuestionFollow ActivityTypeKey = "question.follow"
+	ActTagFollow      ActivityTypeKey = "tag.follow"
+	ActUserFollow     ActivityTypeKey = "user.follow"
+)
@@ -11,6 +11,98 @@ const (
 	AdminTokenCacheKey         = "answer:admin:token:"
 	AdminTokenCacheTime        = 7 * 24 * time.Hour
 	AcceptLanguageFlag         = "Accept-Language"
+	// UserSessionCacheKey the cache of session id to access token, used to revoke the session
+	UserSessionCacheKey = "answer:user:session:"
+	// UserTokenRevokedCacheKey the time of revoking all tokens of user, the tokens issued before it are invalid
+	UserTokenRevokedCacheKey = "answer:user:token:revoked:"
+	// UserSessionActiveInterval the last seen time of session is updated at most once in the interval
+	UserSessionActiveInterval = 5 * time.Minute
+	// VisitCookieName the cookie carries the access token for the requests which can not set authorization header,
+	// such as ui pages and uploaded files, when the site is login required
+	VisitCookieName = "answer_visit"
+)
+
+const (
+	// GhostUsername the content of deleted users can be reassigned to the ghost user
+	GhostUsername          = "ghost"
+	GhostUserEmail         = "ghost@deleted.invalid"
+	GhostUserDisplayName   = "Deleted user"
+	DeletedUserEmailDomain = "deleted.invalid"
+	// UserDeletionGracePeriod the account will be deleted after the grace period, user can cancel it before
+	UserDeletionGracePeriod = 14 * 24 * time.Hour
+	// UserDeletionCheckInterval the interval of checking the user deletions which are due
+	UserDeletionCheckInterval = time.Hour
+	// UserDataExportExpiration the export file can be downloaded until it is expired
+	UserDataExportExpiration = 24 * time.Hour
+	// UserDataExportDirName the directory of the user data export files, under the temporary directory
+	UserDataExportDirName = "exports"
+)
+
+const (
+	// UserTwoFactorPendingTime the pending login token issued after password check, waiting for the second step
+	UserTwoFactorPendingTime = 5 * time.Minute
+	// UserTwoFactorMaxAttempts the pending login token is discarded after too many wrong codes
+	UserTwoFactorMaxAttempts   = 5
+	UserTwoFactorRecoveryCodes = 10
+)
+
+const (
+	// UserSuspensionCheckInterval the interval of checking the suspensions which are ended
+	UserSuspensionCheckInterval = 10 * time.Minute
+)
+
+const (
+	// DraftExpiration the drafts which are not saved again in the period are removed
+	DraftExpiration = 30 * 24 * time.Hour
+	// DraftCleanInterval the interval of removing the expired drafts
+	DraftCleanInterval = time.Hour
+)
+
+const (
+	// CommentMaxDepth the comments are nested in these levels at most, the deeper reply is put beside the replied one
+	CommentMaxDepth = 3
+	// CommentReplyPreviewSize the number of the replies shown under each comment, the others are loaded on demand
+	CommentReplyPreviewSize = 3
+)
+
+const (
+	// TagModeratorAssignInterval the interval of assigning the top answerers of the tags as the moderators
+	TagModeratorAssignInterval = 24 * time.Hour
+)
+
+const (
+	// TagSubscriptionMaxPerUser the max number of the tag expressions which the user watches
+	TagSubscriptionMaxPerUser = 20
+	// TagIgnoreMaxPerUser the max number of the tags which the user ignores
+	TagIgnoreMaxPerUser = 50
+	// TagAlertCheckInterval the interval of sending the alerts which are deferred or batched
+	TagAlertCheckInterval = 10 * time.Minute
+	// TagAlertDigestPeriod the batched alerts are sent once a period
+	TagAlertDigestPeriod = 24 * time.Hour
+)
+
+const (
+	// MentionMaxPerPost the post which mentions more users is rejected
+	MentionMaxPerPost = 10
+	// MentionSearchLimit the max number of the users suggested for the mention
+	MentionSearchLimit = 10
+)
+
+const (
+	// FeedMaxPageSize the max number of the feed items in one page
+	FeedMaxPageSize = 50
+	// FeedDigestPeriod the digest summarizes the feed of the period, it is sent once a period
+	FeedDigestPeriod = 7 * 24 * time.Hour
+	// FeedDigestCheckInterval the interval of checking whether the digest of the period is sent
+	FeedDigestCheckInterval = time.Hour
+	// FeedDigestMaxItems the max number of the feed items in the digest
+	FeedDigestMaxItems = 20
+)
+
+const (
+	// ModerationContentCacheKey the fingerprints of the recent posts, used to detect the repeated content
+	ModerationContentCacheKey  = "answer:moderation:content:"
+	ModerationContentCacheTime = 24 * time.Hour
 )
 
 const (
@@ -21,6 +113,8 @@ const (
 	CollectionObjectType = "collection"
 	CommentObjectType    = "comment"
 	ReportObjectType     = "report"
+	// ModeratorMessageObjectType the object id of it is the thread id, it is not a unique id like the others
+	ModeratorMessageObjectType = "moderator_message"
 )
 
 // ObjectTypeStrMapping key => value
@@ -51,10 +145,36 @@ var (
 )
 
 const (
-	SiteTypeGeneral   = "general"
-	SiteTypeInterface = "interface"
-	SiteTypeBranding  = "branding"
-	SiteTypeWrite     = "write"
-	SiteTypeLegal     = "legal"
+	SiteTypeGeneral      = "general"
+	SiteTypeInterface    = "interface"
+	SiteTypeBranding     = "branding"
+	SiteTypeWrite        = "write"
+	SiteTypeLegal        = "legal"
+	SiteTypeSeo          = "seo"
+	SiteTypeLogin        = "login"
+	SiteTypeReview       = "review"
+	SiteTypeCloseVote    = "close_vote"
+	SiteTypeModeration   = "moderation"
+	SiteTypeAccept       = "accept"
+	SiteTypeComment      = "comment"
+	SiteTypeTagModerator = "tag_moderator"
+)
+
+const (
+	SitemapMaxSize        = 50000 // max urls in one sitemap file, limited by sitemaps protocol
+	SitemapCacheKey       = "answer:sitemap:"
+	SitemapCacheTime      = time.Hour
+	SitemapIndexFileName  = "sitemap.xml"
+	SitemapPageFilePrefix = "question-"
 )
 
+const (
+	// QuestionViewCacheKey the viewer has viewed the question recently, the view is not counted again in the window
+	QuestionViewCacheKey  = "answer:question:view:"
+	QuestionViewCacheTime = 30 * time.Minute
+	// QuestionUniqueViewCacheKey the viewer has been counted as a unique viewer of the question in the window
+	QuestionUniqueViewCacheKey  = "answer:question:unique_view:"
+	QuestionUniqueViewCacheTime = 24 * time.Hour
+	// QuestionViewFlushInterval the interval of flushing the buffered view counts to the database
+	QuestionViewFlushInterval = 30 * time.Second
+)
@@ -25,4 +25,12 @@ const (
 	YourAnswerWasDeleted = "notification.action.your_answer_was_deleted"
 	// YourCommentWasDeleted your comment was deleted
 	YourCommentWasDeleted = "notification.action.your_comment_was_deleted"
+	// YourAccountWasSuspended your account was suspended
+	YourAccountWasSuspended = "notification.action.your_account_was_suspended"
+	// YourSuspensionWasLifted your suspension was lifted
+	YourSuspensionWasLifted = "notification.action.your_suspension_was_lifted"
+	// ModeratorSentYouAMessage moderator sent you a message
+	ModeratorSentYouAMessage = "notification.action.moderator_sent_you_a_message"
+	// UserRepliedToModeratorMessage user replied to moderator message
+	UserRepliedToModeratorMessage = "notification.action.user_replied_to_moderator_message"
 )
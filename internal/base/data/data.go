@@ -1,6 +1,7 @@
 package data
 
 import (
+	"context"
 	"path/filepath"
 	"time"
 
@@ -33,6 +34,28 @@ func NewData(db *xorm.Engine, cache cache.Cache) (*Data, func(), error) {
 	return &Data{DB: db, Cache: cache}, cleanup, nil
 }
 
+type txKey struct{}
+
+// Transaction run the function in one database transaction. The repositories take part in it when they
+// get the session by Session with the context of the function. The nested transaction joins the outer one.
+func (d *Data) Transaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(*xorm.Session); ok {
+		return fn(ctx)
+	}
+	_, err = d.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		return nil, fn(context.WithValue(ctx, txKey{}, session))
+	})
+	return err
+}
+
+// Session get the session of the transaction in the context, or a new session which closes after one operation
+func (d *Data) Session(ctx context.Context) *xorm.Session {
+	if session, ok := ctx.Value(txKey{}).(*xorm.Session); ok {
+		return session
+	}
+	return d.DB.Context(ctx)
+}
+
 // NewDB new database instance
 func NewDB(debug bool, dataConf *Database) (*xorm.Engine, error) {
 	if dataConf.Driver == "" {
@@ -4,22 +4,15 @@ import (
 	"context"
 
 	"answer/internal/base/constant"
+	"answer/internal/base/translator"
 
 	"github.com/gin-gonic/gin"
 	"github.com/segmentfault/pacman/i18n"
 )
 
-// GetLang get language from header
+// GetLang get language from header, the header could be the language of user or the Accept-Language of browser
 func GetLang(ctx *gin.Context) i18n.Language {
-	acceptLanguage := ctx.GetHeader(constant.AcceptLanguageFlag)
-	switch i18n.Language(acceptLanguage) {
-	case i18n.LanguageChinese:
-		return i18n.LanguageChinese
-	case i18n.LanguageEnglish:
-		return i18n.LanguageEnglish
-	default:
-		return i18n.DefaultLang
-	}
+	return translator.MatchLanguage(ctx.GetHeader(constant.AcceptLanguageFlag))
 }
 
 // GetLangByCtx get language from header
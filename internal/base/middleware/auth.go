@@ -1,31 +1,40 @@
 package middleware
 
 import (
+	"net/http"
 	"strings"
 
 	"answer/internal/schema"
 
+	"answer/internal/base/constant"
 	"answer/internal/base/handler"
 	"answer/internal/base/reason"
 	"answer/internal/entity"
 	"answer/internal/service/auth"
+	"answer/internal/service/siteinfo_common"
 	"answer/pkg/converter"
 
 	"github.com/gin-gonic/gin"
 	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
 )
 
 var ctxUUIDKey = "ctxUuidKey"
 
 // AuthUserMiddleware auth user middleware
 type AuthUserMiddleware struct {
-	authService *auth.AuthService
+	authService           *auth.AuthService
+	siteInfoCommonService *siteinfo_common.SiteInfoCommonService
 }
 
 // NewAuthUserMiddleware new auth user middleware
-func NewAuthUserMiddleware(authService *auth.AuthService) *AuthUserMiddleware {
+func NewAuthUserMiddleware(
+	authService *auth.AuthService,
+	siteInfoCommonService *siteinfo_common.SiteInfoCommonService,
+) *AuthUserMiddleware {
 	return &AuthUserMiddleware{
-		authService: authService,
+		authService:           authService,
+		siteInfoCommonService: siteInfoCommonService,
 	}
 }
 
@@ -86,6 +95,70 @@ func (am *AuthUserMiddleware) MustAuth() gin.HandlerFunc {
 	}
 }
 
+// VisitAuth if the site is login required, the anonymous user can not visit, otherwise do nothing.
+// The token can be carried by authorization header or the visit cookie.
+func (am *AuthUserMiddleware) VisitAuth() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if am.CanVisit(ctx) {
+			ctx.Next()
+			return
+		}
+		handler.HandleResponse(ctx, errors.Unauthorized(reason.UnauthorizedError), nil)
+		ctx.Abort()
+	}
+}
+
+// UploadVisitAuth same as VisitAuth, but the branding files such as logo and favicon can always be visited,
+// because they are displayed on the login page
+func (am *AuthUserMiddleware) UploadVisitAuth() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if am.isBrandingFile(ctx) || am.CanVisit(ctx) {
+			ctx.Next()
+			return
+		}
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+	}
+}
+
+func (am *AuthUserMiddleware) isBrandingFile(ctx *gin.Context) bool {
+	branding, err := am.siteInfoCommonService.GetSiteBranding(ctx)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	urlPath := ctx.Request.URL.Path
+	for _, file := range []string{branding.Logo, branding.MobileLogo, branding.SquareIcon, branding.Favicon} {
+		if len(file) > 0 && strings.HasSuffix(file, urlPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanVisit check the request whether can visit the content of the site
+func (am *AuthUserMiddleware) CanVisit(ctx *gin.Context) bool {
+	siteLogin, err := am.siteInfoCommonService.GetSiteLogin(ctx)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	if !siteLogin.LoginRequired {
+		return true
+	}
+	token := ExtractToken(ctx)
+	if len(token) == 0 {
+		token, _ = ctx.Cookie(constant.VisitCookieName)
+	}
+	if len(token) == 0 {
+		return false
+	}
+	userInfo, err := am.authService.GetUserCacheInfo(ctx, token)
+	if err != nil || userInfo == nil {
+		return false
+	}
+	return userInfo.UserStatus != entity.UserStatusDeleted && userInfo.UserStatus != entity.UserStatusSuspended
+}
+
 func (am *AuthUserMiddleware) CmsAuth() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		token := ExtractToken(ctx)
@@ -121,6 +194,15 @@ func GetLoginUserIDFromContext(ctx *gin.Context) (userID string) {
 	return userInfo.UserID
 }
 
+// GetLoginSessionIDFromContext get the session id of current access token from context
+func GetLoginSessionIDFromContext(ctx *gin.Context) (sessionID string) {
+	userInfo := GetUserInfoFromContext(ctx)
+	if userInfo == nil {
+		return ""
+	}
+	return userInfo.SessionID
+}
+
 // GetIsAdminFromContext get user is admin from context
 func GetIsAdminFromContext(ctx *gin.Context) (isAdmin bool) {
 	userInfo := GetUserInfoFromContext(ctx)
@@ -156,3 +238,20 @@ func ExtractToken(ctx *gin.Context) (token string) {
 	}
 	return strings.TrimPrefix(token, "Bearer ")
 }
+
+// SetVisitCookie set the visit cookie with access token, so that ui pages and uploaded files can be visited
+// when the site is login required
+func SetVisitCookie(ctx *gin.Context, accessToken string) {
+	if len(accessToken) == 0 {
+		return
+	}
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(constant.VisitCookieName, accessToken,
+		int(constant.UserTokenCacheTime.Seconds()), "/", "", ctx.Request.TLS != nil, true)
+}
+
+// RemoveVisitCookie remove the visit cookie
+func RemoveVisitCookie(ctx *gin.Context) {
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(constant.VisitCookieName, "", -1, "/", "", ctx.Request.TLS != nil, true)
+}
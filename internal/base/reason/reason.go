@@ -20,10 +20,26 @@ const (
 	QuestionCannotDeleted            = "error.question.cannot_deleted"
 	QuestionCannotClose              = "error.question.cannot_close"
 	QuestionCannotUpdate             = "error.question.cannot_update"
+	QuestionCloseVoteDisabled        = "error.question.close_vote_disabled"
+	QuestionCloseVoteInvalid         = "error.question.close_vote_invalid"
+	QuestionCloseVoteAlready         = "error.question.close_vote_already"
+	QuestionTemplateSectionRequired  = "error.question.template_section_required"
+	MentionTooMany                   = "error.mention.too_many"
+	ModerationRejected               = "error.moderation.rejected"
+	ModerationPatternInvalid         = "error.moderation.pattern_invalid"
+	ModeratorMessageNotFound         = "error.moderator_message.not_found"
+	ModeratorMessageTemplateNotFound = "error.moderator_message.template_not_found"
 	AnswerNotFound                   = "error.answer.not_found"
 	AnswerCannotDeleted              = "error.answer.cannot_deleted"
 	AnswerCannotUpdate               = "error.answer.cannot_update"
+	AnswerCannotAcceptOnBehalf       = "error.answer.cannot_accept_on_behalf"
+	AnswerAcceptChanged              = "error.answer.accept_changed"
+	CollectionNotFound               = "error.collection.not_found"
+	CollectionGroupNotFound          = "error.collection.group_not_found"
+	CollectionGroupCannotRemove      = "error.collection.group_cannot_remove"
 	CommentEditWithoutPermission     = "error.comment.edit_without_permission"
+	CommentEditExpired               = "error.comment.edit_expired"
+	CommentCannotConvert             = "error.comment.cannot_convert"
 	DisallowVote                     = "error.object.disallow_vote"
 	DisallowFollow                   = "error.object.disallow_follow"
 	DisallowVoteYourSelf             = "error.object.disallow_vote_your_self"
@@ -42,9 +58,15 @@ const (
 	TagNotFound                      = "error.tag.not_found"
 	TagNotContainSynonym             = "error.tag.not_contain_synonym_tags"
 	TagCannotUpdate                  = "error.tag.cannot_update"
+	TagSubscriptionExpressionInvalid = "error.tag.subscription_expression_invalid"
+	TagSubscriptionTooMany           = "error.tag.subscription_too_many"
+	TagSubscriptionNotFound          = "error.tag.subscription_not_found"
+	TagAlertTimeZoneInvalid          = "error.tag.alert_time_zone_invalid"
 	RankFailToMeetTheCondition       = "error.rank.fail_to_meet_the_condition"
 	ThemeNotFound                    = "error.theme.not_found"
 	LangNotFound                     = "error.lang.not_found"
+	LangBundleInvalid                = "error.lang.bundle_invalid"
+	LangBundleNotFound               = "error.lang.bundle_not_found"
 	ReportHandleFailed               = "error.report.handle_failed"
 	ReportNotFound                   = "error.report.not_found"
 	ReadConfigFailed                 = "error.config.read_config_failed"
@@ -57,4 +79,21 @@ const (
 	RecommendTagEnter                = "error.tag.recommend_tag_enter"
 	RevisionReviewUnderway           = "error.revision.review_underway"
 	RevisionNoPermission             = "error.revision.no_permission"
+	ReviewNotFound                   = "error.review.not_found"
+	ReviewNoPermission               = "error.review.no_permission"
+	ReviewCompleted                  = "error.review.completed"
+	ReviewCannotEdit                 = "error.review.cannot_edit"
+	EmailIllegalDomainError          = "error.email.illegal_domain_error"
+	UserInviteRequired               = "error.user.invite_required"
+	UserInviteInvalid                = "error.user.invite_invalid"
+	UserInviteEmailMismatch          = "error.user.invite_email_mismatch"
+	UserDataExportNotFound           = "error.user.data_export_not_found"
+	AdminCannotDeleteAccount         = "error.user.admin_cannot_delete_account"
+	TwoFactorNotSetup                = "error.user.two_factor_not_setup"
+	TwoFactorAlreadyEnabled          = "error.user.two_factor_already_enabled"
+	TwoFactorCodeInvalid             = "error.user.two_factor_code_invalid"
+	TwoFactorTokenExpired            = "error.user.two_factor_token_expired"
+	TwoFactorRequiredByAdmin         = "error.user.two_factor_required_by_admin"
+	UserBanned                       = "error.user.banned"
+	UserBanNotFound                  = "error.user.ban_not_found"
 )
@@ -0,0 +1,110 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/segmentfault/pacman/log"
+)
+
+// ProviderSet is providers.
+var ProviderSet = wire.NewSet(NewScheduler)
+
+// Job the background job, the context is cancelled when the scheduler is stopped
+type Job func(ctx context.Context)
+
+type job struct {
+	name       string
+	interval   time.Duration
+	runAtStart bool
+	run        Job
+}
+
+// Scheduler run the background jobs of the services periodically. The services add their jobs when they are
+// created, but no job runs until the server starts the scheduler, so the commands which create the services
+// run nothing in the background. The jobs are stopped when the server shuts down.
+type Scheduler struct {
+	lock    sync.Mutex
+	jobs    []*job
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+}
+
+// NewScheduler new scheduler, the cleanup stops it
+func NewScheduler() (s *Scheduler, cleanup func()) {
+	s = &Scheduler{}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	return s, func() {
+		if err := s.Stop(); err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+// AddJob add the job which runs every interval, and once at the start if runAtStart is true.
+// The job added after the scheduler is started runs at once.
+func (s *Scheduler) AddJob(name string, interval time.Duration, runAtStart bool, run Job) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	j := &job{name: name, interval: interval, runAtStart: runAtStart, run: run}
+	s.jobs = append(s.jobs, j)
+	if s.started {
+		s.startJob(j)
+	}
+}
+
+// Start start running the jobs
+func (s *Scheduler) Start() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.started || s.ctx.Err() != nil {
+		return nil
+	}
+	s.started = true
+	for _, j := range s.jobs {
+		s.startJob(j)
+	}
+	log.Infof("scheduler started with %d jobs", len(s.jobs))
+	return nil
+}
+
+// Stop stop the jobs, and wait for the running ones to return
+func (s *Scheduler) Stop() error {
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
+
+func (s *Scheduler) startJob(j *job) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if j.runAtStart {
+			s.runJob(j)
+		}
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+				s.runJob(j)
+			}
+		}
+	}()
+}
+
+// runJob run the job once, the panic of it does not stop the later runs
+func (s *Scheduler) runJob(j *job) {
+	defer func() {
+		if err := recover(); err != nil {
+			log.Errorf("job %s panic: %v", j.name, err)
+		}
+	}()
+	j.run(s.ctx)
+}
@@ -32,12 +32,17 @@ func NewHTTPServer(debug bool,
 	rootGroup := r.Group("")
 	swaggerRouter.Register(rootGroup)
 	static := r.Group("")
-	static.Use(avatarMiddleware.AvatarThumb())
+	static.Use(authUserMiddleware.UploadVisitAuth(), avatarMiddleware.AvatarThumb())
 	staticRouter.RegisterStaticRouter(static)
 
-	// register api that no need to login
+	// register api that no need to login, even if the site is login required
+	mustUnAuthV1 := r.Group("/answer/api/v1")
+	mustUnAuthV1.Use(authUserMiddleware.Auth())
+	answerRouter.RegisterMustUnAuthAnswerAPIRouter(mustUnAuthV1)
+
+	// register api that no need to login unless the site is login required
 	unAuthV1 := r.Group("/answer/api/v1")
-	unAuthV1.Use(authUserMiddleware.Auth())
+	unAuthV1.Use(authUserMiddleware.Auth(), authUserMiddleware.VisitAuth())
 	answerRouter.RegisterUnAuthAnswerAPIRouter(unAuthV1)
 
 	// register api that must be authenticated
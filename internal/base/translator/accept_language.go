@@ -0,0 +1,67 @@
+package translator
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/segmentfault/pacman/i18n"
+)
+
+// parseAcceptLanguage parse the languages of the Accept-Language header, sorted by the quality from high to low.
+// The language tag is converted to the name of the bundle, such as pt-br -> pt_BR.
+func parseAcceptLanguage(acceptLanguage string) (languages []i18n.Language) {
+	type weighted struct {
+		la      i18n.Language
+		quality float64
+	}
+	items := make([]weighted, 0)
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if params = strings.TrimSpace(params); strings.HasPrefix(params, "q=") {
+			v, err := strconv.ParseFloat(strings.TrimPrefix(params, "q="), 64)
+			if err != nil {
+				continue
+			}
+			quality = v
+		}
+		if quality <= 0 {
+			continue
+		}
+		la, ok := normalizeLanguageTag(tag)
+		if !ok {
+			continue
+		}
+		items = append(items, weighted{la: la, quality: quality})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].quality > items[j].quality
+	})
+	for _, item := range items {
+		languages = append(languages, item.la)
+	}
+	return languages
+}
+
+// normalizeLanguageTag convert the language tag to the name of the bundle, the script subtag is title case
+// and the region subtag is upper case, such as zh-hans -> zh_Hans, en-us -> en_US
+func normalizeLanguageTag(tag string) (la i18n.Language, ok bool) {
+	tag = strings.TrimSpace(strings.ReplaceAll(tag, "-", "_"))
+	base, region, hasRegion := strings.Cut(tag, "_")
+	base = strings.ToLower(base)
+	if hasRegion {
+		if len(region) == 4 {
+			region = strings.ToUpper(region[:1]) + strings.ToLower(region[1:])
+		} else {
+			region = strings.ToUpper(region)
+		}
+		tag = base + "_" + region
+	} else {
+		tag = base
+	}
+	if !IsValidBundleName(tag) {
+		return "", false
+	}
+	return i18n.Language(tag), true
+}
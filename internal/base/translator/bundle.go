@@ -0,0 +1,198 @@
+package translator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/segmentfault/pacman/i18n"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrBundleInvalid the bundle file is not a valid translation bundle
+	ErrBundleInvalid = errors.New("translation bundle is invalid")
+	// ErrBundleNotFound the custom bundle is not found
+	ErrBundleNotFound = errors.New("translation bundle not found")
+
+	bundleNameRegexp = regexp.MustCompile(`^[a-z]{2,3}(_[A-Za-z]{2,4})?$`)
+)
+
+// BundleInfo the information of the translation bundle of the language
+type BundleInfo struct {
+	Language string `json:"language"`
+	Label    string `json:"label"`
+	// Builtin the bundle is shipped with the application
+	Builtin bool `json:"builtin"`
+	// Custom the bundle uploaded by admin exists, it overrides the builtin bundle
+	Custom bool `json:"custom"`
+}
+
+// BundleCheckResult the result of checking the bundle keys against the default language bundle
+type BundleCheckResult struct {
+	Language    string
+	MissingKeys []string
+	ExtraKeys   []string
+}
+
+// IsValidBundleName check the bundle name is a language name, such as en, en_US or zh_Hans
+func IsValidBundleName(name string) bool {
+	return bundleNameRegexp.MatchString(name)
+}
+
+// SaveCustomBundle validate the bundle and save it to the custom bundle directory, then reload the bundles
+func SaveCustomBundle(language string, content []byte) (err error) {
+	if !IsValidBundleName(language) {
+		return ErrBundleInvalid
+	}
+	bundle, err := parseBundle(content)
+	if err != nil {
+		return ErrBundleInvalid
+	}
+	_, hasBackend := bundle["backend"].(map[string]any)
+	_, hasUI := bundle["ui"].(map[string]any)
+	if !hasBackend && !hasUI {
+		return ErrBundleInvalid
+	}
+
+	customDir := filepath.Join(globalTranslator.bundleDir, customBundleDirName)
+	if err = os.MkdirAll(customDir, os.ModePerm); err != nil {
+		return err
+	}
+	if err = os.WriteFile(filepath.Join(customDir, language+".yaml"), content, 0o644); err != nil {
+		return err
+	}
+	return globalTranslator.Reload()
+}
+
+// RemoveCustomBundle remove the custom bundle of the language, then reload the bundles
+func RemoveCustomBundle(language string) (err error) {
+	if !IsValidBundleName(language) {
+		return ErrBundleNotFound
+	}
+	err = os.Remove(filepath.Join(globalTranslator.bundleDir, customBundleDirName, language+".yaml"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ErrBundleNotFound
+		}
+		return err
+	}
+	return globalTranslator.Reload()
+}
+
+// GetBundleList get the information of the bundles of all the available languages
+func GetBundleList() (list []*BundleInfo, err error) {
+	builtin, err := readBundleDir(globalTranslator.bundleDir)
+	if err != nil {
+		return nil, err
+	}
+	custom, err := readBundleDir(filepath.Join(globalTranslator.bundleDir, customBundleDirName))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, option := range GetLanguageOptions() {
+		la := i18n.Language(option.Value)
+		_, isBuiltin := builtin[la]
+		_, isCustom := custom[la]
+		list = append(list, &BundleInfo{
+			Language: option.Value,
+			Label:    option.Label,
+			Builtin:  isBuiltin,
+			Custom:   isCustom,
+		})
+	}
+	return list, nil
+}
+
+// CheckBundles check the keys of all the bundles in the directory against the default language bundle
+func CheckBundles(bundleDir string) (results []*BundleCheckResult, err error) {
+	bundles, err := readBundleDir(bundleDir)
+	if err != nil {
+		return nil, err
+	}
+	defaultBundle, ok := bundles[i18n.DefaultLanguage]
+	if !ok {
+		return nil, fmt.Errorf("default language bundle %s.yaml not found", i18n.DefaultLanguage)
+	}
+	defaultKeys := make(map[string]string)
+	flattenMessages("", defaultBundle, defaultKeys)
+
+	languages := make([]string, 0, len(bundles))
+	for la := range bundles {
+		if la != i18n.DefaultLanguage {
+			languages = append(languages, string(la))
+		}
+	}
+	sort.Strings(languages)
+	for _, la := range languages {
+		keys := make(map[string]string)
+		flattenMessages("", bundles[i18n.Language(la)], keys)
+		result := &BundleCheckResult{Language: la}
+		for key := range defaultKeys {
+			if _, ok := keys[key]; !ok {
+				result.MissingKeys = append(result.MissingKeys, key)
+			}
+		}
+		for key := range keys {
+			if _, ok := defaultKeys[key]; !ok {
+				result.ExtraKeys = append(result.ExtraKeys, key)
+			}
+		}
+		sort.Strings(result.MissingKeys)
+		sort.Strings(result.ExtraKeys)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// parseBundle parse the content of the bundle file
+func parseBundle(content []byte) (bundle map[string]any, err error) {
+	bundle = make(map[string]any)
+	if err = yaml.Unmarshal(content, &bundle); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// mergeBundle merge the src bundle into the dst bundle, the value in src overrides the one in dst.
+// The nested maps of src are copied, so that the dst never shares them with src.
+func mergeBundle(dst, src map[string]any) {
+	for key, value := range src {
+		srcMap, ok := value.(map[string]any)
+		if !ok {
+			dst[key] = value
+			continue
+		}
+		dstMap, ok := dst[key].(map[string]any)
+		if !ok {
+			dstMap = make(map[string]any, len(srcMap))
+			dst[key] = dstMap
+		}
+		mergeBundle(dstMap, srcMap)
+	}
+}
+
+// flattenMessages flatten the nested translations into the message map, such as base.success -> Success.
+// The translation is the value of the "other" field, or the plain string value.
+func flattenMessages(prefix string, node map[string]any, messages map[string]string) {
+	for key, value := range node {
+		id := key
+		if len(prefix) > 0 {
+			id = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case map[string]any:
+			if other, ok := v["other"]; ok {
+				messages[id] = fmt.Sprint(other)
+			}
+			flattenMessages(id, v, messages)
+		case string:
+			if key != "other" {
+				messages[id] = v
+			}
+		}
+	}
+}
@@ -1,14 +1,10 @@
 package translator
 
 import (
-	"fmt"
-	"os"
-	"path/filepath"
+	"answer/internal/base/scheduler"
 
 	"github.com/google/wire"
-	myTran "github.com/segmentfault/pacman/contrib/i18n"
 	"github.com/segmentfault/pacman/i18n"
-	"gopkg.in/yaml.v3"
 )
 
 // ProviderSet is providers.
@@ -24,66 +20,32 @@ type LangOption struct {
 // DefaultLangOption default language option. If user config the language is default, the language option is admin choose.
 const DefaultLangOption = "Default"
 
-var (
-	// LanguageOptions language
-	LanguageOptions []*LangOption
-)
+var globalTranslator = &Translator{}
 
-// NewTranslator new a translator
-func NewTranslator(c *I18n) (tr i18n.Translator, err error) {
-	entries, err := os.ReadDir(c.BundleDir)
+// NewTranslator new a translator, the bundles are reloaded by the scheduler when the files in the bundle directory
+// are changed
+func NewTranslator(c *I18n, scheduler *scheduler.Scheduler) (tr i18n.Translator, err error) {
+	tr, err = LoadTranslator(c)
 	if err != nil {
 		return nil, err
 	}
+	scheduler.AddJob("translation_bundle_reload", bundleCheckInterval, false, globalTranslator.ReloadChangedBundles)
+	return tr, nil
+}
 
-	// read the Bundle resources file from entries
-	for _, file := range entries {
-		// ignore directory
-		if file.IsDir() {
-			continue
-		}
-		// ignore non-YAML file
-		if filepath.Ext(file.Name()) != ".yaml" && file.Name() != "i18n.yaml" {
-			continue
-		}
-		buf, err := os.ReadFile(filepath.Join(c.BundleDir, file.Name()))
-		if err != nil {
-			return nil, fmt.Errorf("read file failed: %s %s", file.Name(), err)
-		}
-
-		// only parse the backend translation
-		translation := struct {
-			Content map[string]interface{} `yaml:"backend"`
-		}{}
-		if err = yaml.Unmarshal(buf, &translation); err != nil {
-			return nil, err
-		}
-		content, err := yaml.Marshal(translation.Content)
-		if err != nil {
-			return nil, fmt.Errorf("marshal translation content failed: %s %s", file.Name(), err)
-		}
-
-		// add translator use backend translation
-		if err = myTran.AddTranslator(content, file.Name()); err != nil {
-			return nil, fmt.Errorf("add translator failed: %s %s", file.Name(), err)
-		}
-	}
-	GlobalTrans = myTran.GlobalTrans
-
-	i18nFile, err := os.ReadFile(filepath.Join(c.BundleDir, "i18n.yaml"))
-	if err != nil {
-		return nil, fmt.Errorf("read i18n file failed: %s", err)
+// LoadTranslator load the bundles once, which are not reloaded when the files are changed
+func LoadTranslator(c *I18n) (tr i18n.Translator, err error) {
+	globalTranslator.bundleDir = c.BundleDir
+	if err = globalTranslator.Reload(); err != nil {
+		return nil, err
 	}
+	GlobalTrans = globalTranslator
+	return GlobalTrans, nil
+}
 
-	s := struct {
-		LangOption []*LangOption `yaml:"language_options"`
-	}{}
-	err = yaml.Unmarshal(i18nFile, &s)
-	if err != nil {
-		return nil, fmt.Errorf("i18n file parsing failed: %s", err)
-	}
-	LanguageOptions = s.LangOption
-	return GlobalTrans, err
+// GetLanguageOptions get the options of all the available languages
+func GetLanguageOptions() []*LangOption {
+	return globalTranslator.LanguageOptions()
 }
 
 // CheckLanguageIsValid check user input language is valid
@@ -91,10 +53,15 @@ func CheckLanguageIsValid(lang string) bool {
 	if lang == DefaultLangOption {
 		return true
 	}
-	for _, option := range LanguageOptions {
-		if option.Value == lang {
-			return true
-		}
-	}
-	return false
+	return globalTranslator.HasLanguage(i18n.Language(lang))
+}
+
+// MatchLanguage negotiate the language from the value of the Accept-Language header
+func MatchLanguage(acceptLanguage string) i18n.Language {
+	return globalTranslator.MatchLanguage(acceptLanguage)
+}
+
+// Reload reload all the translation bundles
+func Reload() error {
+	return globalTranslator.Reload()
 }
@@ -0,0 +1,301 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/segmentfault/pacman/i18n"
+	"github.com/segmentfault/pacman/log"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// languageOptionsFileName the file which lists the labels of the languages
+	languageOptionsFileName = "i18n.yaml"
+	// customBundleDirName the directory under the bundle directory which saves the bundles uploaded by admin
+	customBundleDirName = "custom"
+	// bundleCheckInterval the interval of checking whether the bundle files are changed
+	bundleCheckInterval = 10 * time.Second
+)
+
+// Translator the translator which supports reloading the bundles at runtime.
+// The translation of the language falls back to its base language and then the default language,
+// such as pt_BR -> pt -> en_US.
+type Translator struct {
+	bundleDir string
+	lock      sync.RWMutex
+	// messages the backend messages of the language, the key is the message id
+	messages map[i18n.Language]map[string]string
+	// jsonData the whole translation of the language, dumped for frontend
+	jsonData  map[i18n.Language]map[string]any
+	options   []*LangOption
+	signature string
+}
+
+// Tr translate the key to the language
+func (tr *Translator) Tr(la i18n.Language, key string) string {
+	tr.lock.RLock()
+	defer tr.lock.RUnlock()
+	if msg, ok := tr.messages[tr.resolve(la)][key]; ok {
+		return msg
+	}
+	return key
+}
+
+// Dump dump the whole translation of the language into json format
+func (tr *Translator) Dump(la i18n.Language) ([]byte, error) {
+	tr.lock.RLock()
+	defer tr.lock.RUnlock()
+	return json.Marshal(tr.jsonData[tr.resolve(la)])
+}
+
+// LanguageOptions get the options of all the available languages
+func (tr *Translator) LanguageOptions() []*LangOption {
+	tr.lock.RLock()
+	defer tr.lock.RUnlock()
+	options := make([]*LangOption, 0, len(tr.options))
+	for _, option := range tr.options {
+		options = append(options, &LangOption{Label: option.Label, Value: option.Value})
+	}
+	return options
+}
+
+// HasLanguage whether the language has its own bundle
+func (tr *Translator) HasLanguage(la i18n.Language) bool {
+	tr.lock.RLock()
+	defer tr.lock.RUnlock()
+	_, ok := tr.messages[la]
+	return ok
+}
+
+// MatchLanguage negotiate the language from the value of the Accept-Language header, such as "pt-BR,pt;q=0.9,en;q=0.8".
+// The language which has the highest quality and is available is returned, the region is ignored if no bundle matches it.
+func (tr *Translator) MatchLanguage(acceptLanguage string) i18n.Language {
+	tr.lock.RLock()
+	defer tr.lock.RUnlock()
+	for _, la := range parseAcceptLanguage(acceptLanguage) {
+		if _, ok := tr.messages[la]; ok {
+			return la
+		}
+		if base := i18n.Language(la.Abbr()); len(base) > 0 {
+			if _, ok := tr.messages[base]; ok {
+				return base
+			}
+			for _, option := range tr.options {
+				if i18n.Language(option.Value).Abbr() == string(base) {
+					return i18n.Language(option.Value)
+				}
+			}
+		}
+	}
+	return i18n.DefaultLanguage
+}
+
+// resolve get the nearest loaded language in the fallback chain of the language
+func (tr *Translator) resolve(la i18n.Language) i18n.Language {
+	if _, ok := tr.messages[la]; ok {
+		return la
+	}
+	if base := i18n.Language(la.Abbr()); len(base) > 0 {
+		if _, ok := tr.messages[base]; ok {
+			return base
+		}
+	}
+	return i18n.DefaultLanguage
+}
+
+// ReloadChangedBundles reload the bundles if any of the bundle files is changed
+func (tr *Translator) ReloadChangedBundles(ctx context.Context) {
+	signature, err := bundleDirSignature(tr.bundleDir)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	tr.lock.RLock()
+	changed := signature != tr.signature
+	tr.lock.RUnlock()
+	if !changed {
+		return
+	}
+	if err = tr.Reload(); err != nil {
+		log.Errorf("reload translation bundles failed: %s", err)
+		return
+	}
+	log.Info("translation bundles are reloaded")
+}
+
+// Reload read all the bundles from the bundle directory and the custom bundle directory, and replace the translations
+func (tr *Translator) Reload() (err error) {
+	signature, err := bundleDirSignature(tr.bundleDir)
+	if err != nil {
+		return err
+	}
+	bundles, err := readBundleDir(tr.bundleDir)
+	if err != nil {
+		return err
+	}
+	if _, ok := bundles[i18n.DefaultLanguage]; !ok {
+		return fmt.Errorf("default language bundle %s.yaml not found", i18n.DefaultLanguage)
+	}
+	customBundles, err := readBundleDir(filepath.Join(tr.bundleDir, customBundleDirName))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	// the custom bundle overrides the translation of the same language
+	for la, custom := range customBundles {
+		if bundle, ok := bundles[la]; ok {
+			mergeBundle(bundle, custom)
+		} else {
+			bundles[la] = custom
+		}
+	}
+
+	options, err := readLanguageOptions(tr.bundleDir, bundles)
+	if err != nil {
+		return err
+	}
+
+	messages := make(map[i18n.Language]map[string]string, len(bundles))
+	jsonData := make(map[i18n.Language]map[string]any, len(bundles))
+	for la := range bundles {
+		data := make(map[string]any)
+		for _, fallback := range fallbackChain(la) {
+			if bundle, ok := bundles[fallback]; ok {
+				mergeBundle(data, bundle)
+			}
+		}
+		msg := make(map[string]string)
+		if backend, ok := data["backend"].(map[string]any); ok {
+			flattenMessages("", backend, msg)
+		}
+		messages[la] = msg
+		jsonData[la] = data
+	}
+
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+	tr.messages = messages
+	tr.jsonData = jsonData
+	tr.options = options
+	tr.signature = signature
+	return nil
+}
+
+// fallbackChain the languages whose translations are merged for the language, from the lowest priority to the highest
+func fallbackChain(la i18n.Language) []i18n.Language {
+	chain := []i18n.Language{i18n.DefaultLanguage}
+	if base := i18n.Language(la.Abbr()); base != la && base != i18n.DefaultLanguage {
+		chain = append(chain, base)
+	}
+	if la != i18n.DefaultLanguage {
+		chain = append(chain, la)
+	}
+	return chain
+}
+
+// readBundleDir read all the bundle files in the directory, the key is the language name of the file
+func readBundleDir(bundleDir string) (bundles map[i18n.Language]map[string]any, err error) {
+	entries, err := os.ReadDir(bundleDir)
+	if err != nil {
+		return nil, err
+	}
+	bundles = make(map[i18n.Language]map[string]any)
+	for _, file := range entries {
+		la, ok := bundleLanguage(file)
+		if !ok {
+			continue
+		}
+		buf, err := os.ReadFile(filepath.Join(bundleDir, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read file failed: %s %s", file.Name(), err)
+		}
+		bundle, err := parseBundle(buf)
+		if err != nil {
+			return nil, fmt.Errorf("parse file failed: %s %s", file.Name(), err)
+		}
+		bundles[la] = bundle
+	}
+	return bundles, nil
+}
+
+// bundleLanguage get the language of the bundle file, non-YAML files and the language options file are ignored
+func bundleLanguage(file os.DirEntry) (la i18n.Language, ok bool) {
+	if file.IsDir() || filepath.Ext(file.Name()) != ".yaml" || file.Name() == languageOptionsFileName {
+		return "", false
+	}
+	name := strings.TrimSuffix(file.Name(), filepath.Ext(file.Name()))
+	if !IsValidBundleName(name) {
+		return "", false
+	}
+	return i18n.Language(name), true
+}
+
+// readLanguageOptions read the labels of the languages from the language options file,
+// the languages which are not listed in the file use the language name as the label
+func readLanguageOptions(bundleDir string, bundles map[i18n.Language]map[string]any) (options []*LangOption, err error) {
+	i18nFile, err := os.ReadFile(filepath.Join(bundleDir, languageOptionsFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read i18n file failed: %s", err)
+	}
+	s := struct {
+		LangOption []*LangOption `yaml:"language_options"`
+	}{}
+	if err = yaml.Unmarshal(i18nFile, &s); err != nil {
+		return nil, fmt.Errorf("i18n file parsing failed: %s", err)
+	}
+
+	listed := make(map[string]bool)
+	for _, option := range s.LangOption {
+		if _, ok := bundles[i18n.Language(option.Value)]; !ok {
+			continue
+		}
+		listed[option.Value] = true
+		options = append(options, option)
+	}
+	discovered := make([]string, 0)
+	for la := range bundles {
+		if !listed[string(la)] {
+			discovered = append(discovered, string(la))
+		}
+	}
+	sort.Strings(discovered)
+	for _, la := range discovered {
+		options = append(options, &LangOption{Label: la, Value: la})
+	}
+	return options, nil
+}
+
+// bundleDirSignature the signature of the bundle files, which is changed when any of the files is changed
+func bundleDirSignature(bundleDir string) (signature string, err error) {
+	var sb strings.Builder
+	for _, dir := range []string{bundleDir, filepath.Join(bundleDir, customBundleDirName)} {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) && dir != bundleDir {
+				continue
+			}
+			return "", err
+		}
+		for _, file := range entries {
+			if file.IsDir() || filepath.Ext(file.Name()) != ".yaml" {
+				continue
+			}
+			info, err := file.Info()
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(filepath.Join(dir, file.Name()))
+			sb.WriteString(":" + strconv.FormatInt(info.ModTime().UnixNano(), 10))
+			sb.WriteString(":" + strconv.FormatInt(info.Size(), 10) + ";")
+		}
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"answer/internal/base/data"
+	"answer/internal/repo/config"
+	"answer/internal/repo/rank"
+	"answer/internal/schema"
+	"answer/internal/service/reputation"
+)
+
+// RecalculateReputation recalculate the reputation of all the users after the user id batch by batch, print the
+// differences and fix them unless it is a dry run. If it fails, it can be resumed from the last printed user id.
+func RecalculateReputation(dataConf *data.Database, dryRun bool, afterUserID string, batchSize int) error {
+	if batchSize < 1 || batchSize > reputation.MaxBatchSize {
+		return fmt.Errorf("the batch size must be between 1 and %d", reputation.MaxBatchSize)
+	}
+	db, err := data.NewDB(false, dataConf)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if err = db.Ping(); err != nil {
+		return err
+	}
+
+	d := &data.Data{DB: db}
+	reputationService := reputation.NewReputationService(rank.NewReputationRepo(d), config.NewConfigRepo(d))
+	checked, diffs := 0, 0
+	for {
+		resp, err := reputationService.RecalculateReputation(context.Background(), &schema.RecalculateReputationReq{
+			DryRun:      dryRun,
+			AfterUserID: afterUserID,
+			Limit:       batchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("recalculate the users after %s failed, resume with --after %s: %w",
+				afterUserID, afterUserID, err)
+		}
+		checked += resp.Checked
+		diffs += len(resp.Diffs)
+		for _, diff := range resp.Diffs {
+			fmt.Printf("user %s (%s): %d -> %d, %d activities changed\n",
+				diff.UserID, diff.Username, diff.OldRank, diff.NewRank, diff.ChangedActivities)
+		}
+		if len(resp.NextUserID) == 0 {
+			break
+		}
+		afterUserID = resp.NextUserID
+		fmt.Printf("checked %d users, up to user %s\n", checked, afterUserID)
+	}
+	fmt.Printf("checked %d users, %d users differ\n", checked, diffs)
+	return nil
+}
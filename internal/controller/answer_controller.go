@@ -10,6 +10,7 @@ import (
 	"answer/internal/service"
 	"answer/internal/service/dashboard"
 	"answer/internal/service/rank"
+	"answer/internal/service/tag_moderator"
 
 	"github.com/gin-gonic/gin"
 	"github.com/segmentfault/pacman/errors"
@@ -17,20 +18,23 @@ import (
 
 // AnswerController answer controller
 type AnswerController struct {
-	answerService    *service.AnswerService
-	rankService      *rank.RankService
-	dashboardService *dashboard.DashboardService
+	answerService       *service.AnswerService
+	rankService         *rank.RankService
+	dashboardService    *dashboard.DashboardService
+	tagModeratorService *tag_moderator.TagModeratorService
 }
 
 // NewAnswerController new controller
 func NewAnswerController(answerService *service.AnswerService,
 	rankService *rank.RankService,
 	dashboardService *dashboard.DashboardService,
+	tagModeratorService *tag_moderator.TagModeratorService,
 ) *AnswerController {
 	return &AnswerController{
-		answerService:    answerService,
-		rankService:      rankService,
-		dashboardService: dashboardService,
+		answerService:       answerService,
+		rankService:         rankService,
+		dashboardService:    dashboardService,
+		tagModeratorService: tagModeratorService,
 	}
 }
 
@@ -110,6 +114,8 @@ func (ac *AnswerController) Add(ctx *gin.Context) {
 		return
 	}
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	req.IP = ctx.ClientIP()
+	req.UserAgent = ctx.Request.UserAgent()
 
 	can, err := ac.rankService.CheckOperationPermission(ctx, req.UserID, rank.AnswerAddRank, "")
 	if err != nil {
@@ -162,6 +168,7 @@ func (ac *AnswerController) Update(ctx *gin.Context) {
 	canList, err := ac.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
 		rank.AnswerEditRank,
 		rank.AnswerEditWithoutReviewRank,
+		rank.AnswerEditWikiRank,
 	}, req.ID)
 	if err != nil {
 		handler.HandleResponse(ctx, err, nil)
@@ -169,7 +176,10 @@ func (ac *AnswerController) Update(ctx *gin.Context) {
 	}
 	req.CanEdit = canList[0]
 	req.NoNeedReview = canList[1]
-	if !req.CanEdit {
+	req.CanEditWiki = canList[2]
+	req.IsAdmin = middleware.GetIsAdminFromContext(ctx)
+	// the community wiki has a lower threshold, it is checked with the answer
+	if !req.CanEdit && !req.CanEditWiki {
 		handler.HandleResponse(ctx, errors.Forbidden(reason.RankFailToMeetTheCondition), nil)
 		return
 	}
@@ -247,12 +257,18 @@ func (ac *AnswerController) Adopted(ctx *gin.Context) {
 	}
 
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	req.IsAdmin = middleware.GetIsAdminFromContext(ctx)
 	can, err := ac.rankService.CheckOperationPermission(ctx, req.UserID, rank.AnswerAcceptRank, req.QuestionID)
 	if err != nil {
 		handler.HandleResponse(ctx, err, nil)
 		return
 	}
-	if !can {
+	req.IsTagModerator, err = ac.tagModeratorService.IsQuestionTagModerator(ctx, req.UserID, req.QuestionID)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	if !can && !req.IsTagModerator {
 		handler.HandleResponse(ctx, errors.Forbidden(reason.RankFailToMeetTheCondition), nil)
 		return
 	}
@@ -261,6 +277,43 @@ func (ac *AnswerController) Adopted(ctx *gin.Context) {
 	handler.HandleResponse(ctx, err, nil)
 }
 
+// CancelAdopted godoc
+// @Summary unaccept the answer
+// @Description unaccept the answer, the other accepted answers of the question are kept
+// @Tags api-answer
+// @Accept  json
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param data body schema.AnswerAdoptedReq  true "AnswerAdoptedReq"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/answer/acceptance [delete]
+func (ac *AnswerController) CancelAdopted(ctx *gin.Context) {
+	req := &schema.AnswerAdoptedReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	req.IsAdmin = middleware.GetIsAdminFromContext(ctx)
+	can, err := ac.rankService.CheckOperationPermission(ctx, req.UserID, rank.AnswerAcceptRank, req.QuestionID)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	req.IsTagModerator, err = ac.tagModeratorService.IsQuestionTagModerator(ctx, req.UserID, req.QuestionID)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	if !can && !req.IsTagModerator {
+		handler.HandleResponse(ctx, errors.Forbidden(reason.RankFailToMeetTheCondition), nil)
+		return
+	}
+
+	err = ac.answerService.CancelAdopted(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
 // AdminSetAnswerStatus godoc
 // @Summary AdminSetAnswerStatus
 // @Description Status:[available,deleted]
@@ -282,3 +335,25 @@ func (ac *AnswerController) AdminSetAnswerStatus(ctx *gin.Context) {
 	err := ac.answerService.AdminSetAnswerStatus(ctx, req)
 	handler.HandleResponse(ctx, err, gin.H{})
 }
+
+// ConvertCommentToAnswer godoc
+// @Summary convert the comment to an answer
+// @Description convert the comment on the question or answer to an answer of the question by the comment author
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.ConvertCommentToAnswerReq true "comment"
+// @Success 200 {object} handler.RespBody{data=schema.ConvertCommentToAnswerResp}
+// @Router /answer/admin/api/comment/answer [post]
+func (ac *AnswerController) ConvertCommentToAnswer(ctx *gin.Context) {
+	req := &schema.ConvertCommentToAnswerReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+
+	resp, err := ac.answerService.ConvertCommentToAnswer(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
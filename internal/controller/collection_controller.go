@@ -1,6 +1,8 @@
 package controller
 
 import (
+	"net/http"
+
 	"answer/internal/base/handler"
 	"answer/internal/base/middleware"
 	"answer/internal/base/reason"
@@ -15,12 +17,19 @@ import (
 
 // CollectionController collection controller
 type CollectionController struct {
-	collectionService *service.CollectionService
+	collectionService      *service.CollectionService
+	collectionGroupService *service.CollectionGroupService
 }
 
 // NewCollectionController new controller
-func NewCollectionController(collectionService *service.CollectionService) *CollectionController {
-	return &CollectionController{collectionService: collectionService}
+func NewCollectionController(
+	collectionService *service.CollectionService,
+	collectionGroupService *service.CollectionGroupService,
+) *CollectionController {
+	return &CollectionController{
+		collectionService:      collectionService,
+		collectionGroupService: collectionGroupService,
+	}
 }
 
 // CollectionSwitch add collection
@@ -55,3 +64,194 @@ func (cc *CollectionController) CollectionSwitch(ctx *gin.Context) {
 	resp, err := cc.collectionService.CollectionSwitch(ctx, dto)
 	handler.HandleResponse(ctx, err, resp)
 }
+
+// GetCollectionGroupList get collection groups
+// @Summary get collection groups
+// @Description get the collection groups of the login user in order with the count of the collections
+// @Tags Collection
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=[]schema.GetCollectionGroupResp}
+// @Router /answer/api/v1/collection/groups [get]
+func (cc *CollectionController) GetCollectionGroupList(ctx *gin.Context) {
+	userID := middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := cc.collectionGroupService.GetCollectionGroupList(ctx, userID)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// AddCollectionGroup add collection group
+// @Summary add collection group
+// @Description add collection group, the public group can be shared by the url
+// @Tags Collection
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body schema.AddCollectionGroupReq true "collection group"
+// @Success 200 {object} handler.RespBody{data=schema.GetCollectionGroupResp}
+// @Router /answer/api/v1/collection/group [post]
+func (cc *CollectionController) AddCollectionGroup(ctx *gin.Context) {
+	req := &schema.AddCollectionGroupReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := cc.collectionGroupService.AddCollectionGroup(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// UpdateCollectionGroup update collection group
+// @Summary update collection group
+// @Description rename the collection group or change its visibility
+// @Tags Collection
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body schema.UpdateCollectionGroupReq true "collection group"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/collection/group [put]
+func (cc *CollectionController) UpdateCollectionGroup(ctx *gin.Context) {
+	req := &schema.UpdateCollectionGroupReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := cc.collectionGroupService.UpdateCollectionGroup(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// RemoveCollectionGroup remove collection group
+// @Summary remove collection group
+// @Description remove collection group, the collections in it are moved to the default group
+// @Tags Collection
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body schema.RemoveCollectionGroupReq true "collection group"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/collection/group [delete]
+func (cc *CollectionController) RemoveCollectionGroup(ctx *gin.Context) {
+	req := &schema.RemoveCollectionGroupReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := cc.collectionGroupService.RemoveCollectionGroup(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// SortCollectionGroups sort collection groups
+// @Summary sort collection groups
+// @Description reorder the collection groups of the login user in the order of the ids
+// @Tags Collection
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body schema.SortCollectionGroupReq true "collection groups"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/collection/groups/sort [put]
+func (cc *CollectionController) SortCollectionGroups(ctx *gin.Context) {
+	req := &schema.SortCollectionGroupReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := cc.collectionGroupService.SortCollectionGroups(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// GetCollectionPage get collection page
+// @Summary get collection page
+// @Description get the collections in the group of the login user with the private notes
+// @Tags Collection
+// @Security ApiKeyAuth
+// @Produce json
+// @Param group_id query string true "collection group id"
+// @Param page query int false "page"
+// @Param page_size query int false "page size"
+// @Success 200 {object} handler.RespBody{data=pager.PageModel{list=[]schema.GetCollectionResp}}
+// @Router /answer/api/v1/collection/page [get]
+func (cc *CollectionController) GetCollectionPage(ctx *gin.Context) {
+	req := &schema.GetCollectionPageReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := cc.collectionService.GetCollectionPage(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// MoveCollection move collection
+// @Summary move collection
+// @Description move the collection to another group
+// @Tags Collection
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body schema.MoveCollectionReq true "collection"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/collection/move [put]
+func (cc *CollectionController) MoveCollection(ctx *gin.Context) {
+	req := &schema.MoveCollectionReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := cc.collectionService.MoveCollection(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// UpdateCollectionNote update collection note
+// @Summary update collection note
+// @Description update the private note of the collection
+// @Tags Collection
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body schema.UpdateCollectionNoteReq true "collection note"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/collection/note [put]
+func (cc *CollectionController) UpdateCollectionNote(ctx *gin.Context) {
+	req := &schema.UpdateCollectionNoteReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := cc.collectionService.UpdateCollectionNote(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// GetSharedCollectionGroup get shared collection group
+// @Summary get shared collection group
+// @Description get the questions in the public collection group by the share token
+// @Tags Collection
+// @Produce json
+// @Param token query string true "share token"
+// @Param page query int false "page"
+// @Param page_size query int false "page size"
+// @Success 200 {object} handler.RespBody{data=schema.GetSharedCollectionGroupResp}
+// @Router /answer/api/v1/collection/group/shared [get]
+func (cc *CollectionController) GetSharedCollectionGroup(ctx *gin.Context) {
+	req := &schema.GetSharedCollectionGroupReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := cc.collectionGroupService.GetSharedCollectionGroup(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetCollectionGroupRSS get the rss feed of shared collection group
+// @Summary get the rss feed of shared collection group
+// @Description get the rss feed of the newest questions in the public collection group
+// @Tags Collection
+// @Produce xml
+// @Param token query string true "share token"
+// @Success 200 {string} string ""
+// @Router /answer/api/v1/collection/group/rss [get]
+func (cc *CollectionController) GetCollectionGroupRSS(ctx *gin.Context) {
+	content, err := cc.collectionGroupService.GetCollectionGroupRSS(ctx, ctx.Query("token"))
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	ctx.Data(http.StatusOK, "application/rss+xml; charset=utf-8", []byte(content))
+}
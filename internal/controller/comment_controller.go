@@ -42,6 +42,8 @@ func (cc *CommentController) AddComment(ctx *gin.Context) {
 	}
 
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	req.IP = ctx.ClientIP()
+	req.UserAgent = ctx.Request.UserAgent()
 	canList, err := cc.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
 		rank.CommentAddRank,
 		rank.CommentEditRank,
@@ -121,6 +123,7 @@ func (cc *CommentController) UpdateComment(ctx *gin.Context) {
 		return
 	}
 
+	req.IsAdmin = middleware.GetIsAdminFromContext(ctx)
 	err = cc.commentService.UpdateComment(ctx, req)
 	handler.HandleResponse(ctx, err, nil)
 }
@@ -153,11 +156,45 @@ func (cc *CommentController) GetCommentWithPage(ctx *gin.Context) {
 	}
 	req.CanEdit = canList[0]
 	req.CanDelete = canList[1]
+	req.IsAdmin = middleware.GetIsAdminFromContext(ctx)
 
 	resp, err := cc.commentService.GetCommentWithPage(ctx, req)
 	handler.HandleResponse(ctx, err, resp)
 }
 
+// GetCommentReplyPage get the replies of the comment page
+// @Summary get the replies of the comment page
+// @Description get the replies nested under the comment page, the first replies of each reply are nested in it
+// @Tags Comment
+// @Produce json
+// @Param page query int false "page"
+// @Param page_size query int false "page size"
+// @Param comment_id query string true "comment id"
+// @Success 200 {object} handler.RespBody{data=pager.PageModel{list=[]schema.GetCommentResp}}
+// @Router /answer/api/v1/comment/replies [get]
+func (cc *CommentController) GetCommentReplyPage(ctx *gin.Context) {
+	req := &schema.GetCommentReplyPageReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	canList, err := cc.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+		rank.CommentEditRank,
+		rank.CommentDeleteRank,
+	}, "")
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	req.CanEdit = canList[0]
+	req.CanDelete = canList[1]
+	req.IsAdmin = middleware.GetIsAdminFromContext(ctx)
+
+	resp, err := cc.commentService.GetCommentReplyPage(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
 // GetCommentPersonalWithPage user personal comment list
 // @Summary user personal comment list
 // @Description user personal comment list
@@ -205,6 +242,7 @@ func (cc *CommentController) GetComment(ctx *gin.Context) {
 	}
 	req.CanEdit = canList[0]
 	req.CanDelete = canList[1]
+	req.IsAdmin = middleware.GetIsAdminFromContext(ctx)
 
 	resp, err := cc.commentService.GetComment(ctx, req)
 	handler.HandleResponse(ctx, err, resp)
@@ -23,4 +23,14 @@ var ProviderSetController = wire.NewSet(
 	NewDashboardController,
 	NewUploadController,
 	NewActivityController,
+	NewSeoController,
+	NewUserInviteController,
+	NewUserDataController,
+	NewUserTwoFactorController,
+	NewReviewController,
+	NewModeratorMessageController,
+	NewDraftController,
+	NewFeedController,
+	NewMentionController,
+	NewTagSubscriptionController,
 )
@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"answer/internal/base/handler"
+	"answer/internal/base/middleware"
+	"answer/internal/schema"
+	"answer/internal/service/draft"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DraftController draft controller
+type DraftController struct {
+	draftService *draft.DraftService
+}
+
+// NewDraftController new controller
+func NewDraftController(draftService *draft.DraftService) *DraftController {
+	return &DraftController{draftService: draftService}
+}
+
+// SaveDraft save draft
+// @Summary save draft
+// @Description autosave the draft of the new question, the answer to the question or the edit of the post
+// @Tags Draft
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body schema.SaveDraftReq true "draft"
+// @Success 200 {object} handler.RespBody{data=schema.SaveDraftResp}
+// @Router /answer/api/v1/draft [put]
+func (dc *DraftController) SaveDraft(ctx *gin.Context) {
+	req := &schema.SaveDraftReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := dc.draftService.SaveDraft(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetDraft get draft
+// @Summary get draft
+// @Description get the draft of the context to restore it, the data is null if there is no draft
+// @Tags Draft
+// @Security ApiKeyAuth
+// @Produce json
+// @Param draft_type query string true "draft type" Enums(question, answer, edit)
+// @Param object_id query string true "0 for the new question, the question id for the answer, the post id for the edit"
+// @Success 200 {object} handler.RespBody{data=schema.DraftResp}
+// @Router /answer/api/v1/draft [get]
+func (dc *DraftController) GetDraft(ctx *gin.Context) {
+	req := &schema.GetDraftReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := dc.draftService.GetDraft(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// RemoveDraft remove draft
+// @Summary remove draft
+// @Description discard the draft of the context
+// @Tags Draft
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body schema.RemoveDraftReq true "draft"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/draft [delete]
+func (dc *DraftController) RemoveDraft(ctx *gin.Context) {
+	req := &schema.RemoveDraftReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := dc.draftService.RemoveDraft(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// GetDraftPage get draft page
+// @Summary get draft page
+// @Description get the drafts of the login user, the latest saved first
+// @Tags Draft
+// @Security ApiKeyAuth
+// @Produce json
+// @Param page query int false "page"
+// @Param page_size query int false "page size"
+// @Success 200 {object} handler.RespBody{data=pager.PageModel{list=[]schema.DraftResp}}
+// @Router /answer/api/v1/drafts/page [get]
+func (dc *DraftController) GetDraftPage(ctx *gin.Context) {
+	req := &schema.GetDraftPageReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := dc.draftService.GetDraftPage(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"answer/internal/base/handler"
+	"answer/internal/base/middleware"
+	"answer/internal/schema"
+	"answer/internal/service/feed"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeedController feed controller
+type FeedController struct {
+	feedService *feed.FeedService
+}
+
+// NewFeedController new controller
+func NewFeedController(feedService *feed.FeedService) *FeedController {
+	return &FeedController{feedService: feedService}
+}
+
+// GetFeed get the following feed
+// @Summary get the following feed
+// @Description get the new questions in the followed tags, the new answers and edits on the followed questions
+// @Description and the new posts by the followed users, the newest first
+// @Tags Feed
+// @Security ApiKeyAuth
+// @Produce json
+// @Param cursor query string false "the next cursor of the previous page"
+// @Param page_size query int false "page size"
+// @Success 200 {object} handler.RespBody{data=schema.GetFeedResp}
+// @Router /answer/api/v1/feed [get]
+func (fc *FeedController) GetFeed(ctx *gin.Context) {
+	req := &schema.GetFeedReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := fc.feedService.GetFeed(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetFeedDigest get the digest of the following feed
+// @Summary get the digest of the following feed
+// @Description get the digest of the following feed in the last week, it is the same as the weekly digest email
+// @Tags Feed
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.GetFeedDigestResp}
+// @Router /answer/api/v1/feed/digest [get]
+func (fc *FeedController) GetFeedDigest(ctx *gin.Context) {
+	userID := middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := fc.feedService.GetFeedDigest(ctx, userID)
+	handler.HandleResponse(ctx, err, resp)
+}
@@ -69,3 +69,37 @@ func (fc *FollowController) UpdateFollowTags(ctx *gin.Context) {
 	err := fc.followService.UpdateFollowTags(ctx, req)
 	handler.HandleResponse(ctx, err, nil)
 }
+
+// FollowUser follow user or cancel following
+// @Summary follow user or cancel following
+// @Description follow user or cancel following, the new posts of the followed users are in the feed
+// @Tags Activity
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.FollowUserReq true "follow"
+// @Success 200 {object} handler.RespBody{data=schema.FollowResp}
+// @Router /answer/api/v1/follow/user [post]
+func (fc *FollowController) FollowUser(ctx *gin.Context) {
+	req := &schema.FollowUserReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := fc.followService.FollowUser(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetFollowingUsers get the users followed by the login user
+// @Summary get the users followed by the login user
+// @Description get the users followed by the login user
+// @Tags Activity
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody{data=[]schema.UserBasicInfo}
+// @Router /answer/api/v1/follow/users [get]
+func (fc *FollowController) GetFollowingUsers(ctx *gin.Context) {
+	req := &schema.GetFollowingUsersReq{UserID: middleware.GetLoginUserIDFromContext(ctx)}
+	resp, err := fc.followService.GetFollowingUsers(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
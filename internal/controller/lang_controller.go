@@ -2,15 +2,25 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
 
 	"answer/internal/base/handler"
+	"answer/internal/base/reason"
 	"answer/internal/base/translator"
+	"answer/internal/schema"
 	"answer/internal/service/siteinfo_common"
 
 	"github.com/gin-gonic/gin"
+	myErrors "github.com/segmentfault/pacman/errors"
 	"github.com/segmentfault/pacman/i18n"
 )
 
+// maxLangBundleSize the max size of the uploaded translation bundle file
+const maxLangBundleSize = 5 * 1024 * 1024
+
 type LangController struct {
 	translator      i18n.Translator
 	siteInfoService *siteinfo_common.SiteInfoCommonService
@@ -45,7 +55,7 @@ func (u *LangController) GetLangMapping(ctx *gin.Context) {
 // @Router /answer/api/v1/language/options [get]
 // @Router /answer/admin/api/language/options [get]
 func (u *LangController) GetAdminLangOptions(ctx *gin.Context) {
-	handler.HandleResponse(ctx, nil, translator.LanguageOptions)
+	handler.HandleResponse(ctx, nil, translator.GetLanguageOptions())
 }
 
 // GetUserLangOptions Get language options
@@ -62,7 +72,7 @@ func (u *LangController) GetUserLangOptions(ctx *gin.Context) {
 		return
 	}
 
-	options := translator.LanguageOptions
+	options := translator.GetLanguageOptions()
 	if len(siteInterfaceResp.Language) > 0 {
 		defaultOption := []*translator.LangOption{
 			{Label: translator.DefaultLangOption, Value: translator.DefaultLangOption},
@@ -71,3 +81,88 @@ func (u *LangController) GetUserLangOptions(ctx *gin.Context) {
 	}
 	handler.HandleResponse(ctx, nil, options)
 }
+
+// GetAdminLangBundles get the translation bundles of all the languages
+// @Summary get the translation bundles of all the languages
+// @Description get the translation bundles of all the languages, and whether they are overridden by the custom bundles
+// @Tags admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=[]translator.BundleInfo}
+// @Router /answer/admin/api/language/bundles [get]
+func (u *LangController) GetAdminLangBundles(ctx *gin.Context) {
+	list, err := translator.GetBundleList()
+	if err != nil {
+		handler.HandleResponse(ctx, myErrors.InternalServer(reason.UnknownError).WithError(err).WithStack(), nil)
+		return
+	}
+	handler.HandleResponse(ctx, nil, list)
+}
+
+// UploadLangBundle upload the custom translation bundle
+// @Summary upload the custom translation bundle
+// @Description upload the custom translation bundle, it overrides the translation of the existing language or adds a new language.
+// @Description The language is the name of the file if it is not specified, such as pt_BR.yaml
+// @Tags admin
+// @Security ApiKeyAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "bundle file"
+// @Param language formData string false "language"
+// @Success 200 {object} handler.RespBody{}
+// @Router /answer/admin/api/language/bundle [post]
+func (u *LangController) UploadLangBundle(ctx *gin.Context) {
+	file, err := ctx.FormFile("file")
+	if err != nil || file.Size > maxLangBundleSize {
+		handler.HandleResponse(ctx, myErrors.BadRequest(reason.LangBundleInvalid), nil)
+		return
+	}
+	language := ctx.PostForm("language")
+	if len(language) == 0 {
+		language = strings.TrimSuffix(file.Filename, filepath.Ext(file.Filename))
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		handler.HandleResponse(ctx, myErrors.BadRequest(reason.LangBundleInvalid), nil)
+		return
+	}
+	defer f.Close()
+	content, err := io.ReadAll(f)
+	if err != nil {
+		handler.HandleResponse(ctx, myErrors.BadRequest(reason.LangBundleInvalid), nil)
+		return
+	}
+
+	err = translator.SaveCustomBundle(language, content)
+	if errors.Is(err, translator.ErrBundleInvalid) {
+		err = myErrors.BadRequest(reason.LangBundleInvalid)
+	} else if err != nil {
+		err = myErrors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// RemoveLangBundle remove the custom translation bundle
+// @Summary remove the custom translation bundle
+// @Description remove the custom translation bundle, the builtin translation of the language is restored
+// @Tags admin
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body schema.RemoveLangBundleReq true "bundle"
+// @Success 200 {object} handler.RespBody{}
+// @Router /answer/admin/api/language/bundle [delete]
+func (u *LangController) RemoveLangBundle(ctx *gin.Context) {
+	req := &schema.RemoveLangBundleReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := translator.RemoveCustomBundle(req.Language)
+	if errors.Is(err, translator.ErrBundleNotFound) {
+		err = myErrors.BadRequest(reason.LangBundleNotFound)
+	} else if err != nil {
+		err = myErrors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	handler.HandleResponse(ctx, err, nil)
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"answer/internal/base/handler"
+	"answer/internal/base/middleware"
+	"answer/internal/schema"
+	"answer/internal/service/mention"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MentionController mention controller
+type MentionController struct {
+	mentionService *mention.MentionService
+}
+
+// NewMentionController new controller
+func NewMentionController(mentionService *mention.MentionService) *MentionController {
+	return &MentionController{mentionService: mentionService}
+}
+
+// SearchMentionUsers search the users to mention
+// @Summary search the users to mention
+// @Description search the users by the prefix of the username or display name,
+// @Description the participants of the question are ranked first
+// @Tags Mention
+// @Security ApiKeyAuth
+// @Produce json
+// @Param keyword query string false "the prefix of the username or display name"
+// @Param question_id query string false "the question which is being answered or edited"
+// @Success 200 {object} handler.RespBody{data=[]schema.UserBasicInfo}
+// @Router /answer/api/v1/mention/users [get]
+func (mc *MentionController) SearchMentionUsers(ctx *gin.Context) {
+	req := &schema.SearchMentionUsersReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := mc.mentionService.SearchMentionUsers(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
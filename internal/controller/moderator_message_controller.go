@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"answer/internal/base/handler"
+	"answer/internal/base/middleware"
+	"answer/internal/schema"
+	"answer/internal/service/moderator_message"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ModeratorMessageController moderator message controller
+type ModeratorMessageController struct {
+	moderatorMessageService *moderator_message.ModeratorMessageService
+}
+
+// NewModeratorMessageController new controller
+func NewModeratorMessageController(
+	moderatorMessageService *moderator_message.ModeratorMessageService) *ModeratorMessageController {
+	return &ModeratorMessageController{moderatorMessageService: moderatorMessageService}
+}
+
+// GetModeratorMessageThread get moderator message thread
+// @Summary get moderator message thread
+// @Description get the messages between the moderators and the login user in the thread
+// @Tags User
+// @Security ApiKeyAuth
+// @Produce json
+// @Param thread_id query string true "thread id"
+// @Success 200 {object} handler.RespBody{data=schema.ModeratorMessageThreadResp}
+// @Router /answer/api/v1/moderator/message/thread [get]
+func (mc *ModeratorMessageController) GetModeratorMessageThread(ctx *gin.Context) {
+	req := &schema.GetModeratorMessageThreadReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := mc.moderatorMessageService.GetThread(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// ReplyModeratorMessage reply moderator message
+// @Summary reply moderator message
+// @Description reply the thread, the moderators in the thread are notified
+// @Tags User
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body schema.ReplyModeratorMessageReq true "reply"
+// @Success 200 {object} handler.RespBody{data=schema.ModeratorMessageResp}
+// @Router /answer/api/v1/moderator/message/reply [post]
+func (mc *ModeratorMessageController) ReplyModeratorMessage(ctx *gin.Context) {
+	req := &schema.ReplyModeratorMessageReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := mc.moderatorMessageService.ReplyMessage(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
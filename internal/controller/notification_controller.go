@@ -142,7 +142,7 @@ func (nc *NotificationController) ClearIDUnRead(ctx *gin.Context) {
 // @Security ApiKeyAuth
 // @Param page query int false "page size"
 // @Param page_size query int false "page size"
-// @Param type query string true "type" Enums(inbox,achievement)
+// @Param type query string true "type" Enums(inbox,achievement,moderator_message)
 // @Success 200 {object} handler.RespBody
 // @Router /answer/api/v1/notification/page [get]
 func (nc *NotificationController) GetList(ctx *gin.Context) {
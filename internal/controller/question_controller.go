@@ -9,7 +9,9 @@ import (
 	"answer/internal/entity"
 	"answer/internal/schema"
 	"answer/internal/service"
+	"answer/internal/service/question_close_vote"
 	"answer/internal/service/rank"
+	"answer/internal/service/tag_moderator"
 	"answer/pkg/converter"
 
 	"github.com/gin-gonic/gin"
@@ -18,13 +20,25 @@ import (
 
 // QuestionController question controller
 type QuestionController struct {
-	questionService *service.QuestionService
-	rankService     *rank.RankService
+	questionService     *service.QuestionService
+	rankService         *rank.RankService
+	closeVoteService    *question_close_vote.QuestionCloseVoteService
+	tagModeratorService *tag_moderator.TagModeratorService
 }
 
 // NewQuestionController new controller
-func NewQuestionController(questionService *service.QuestionService, rankService *rank.RankService) *QuestionController {
-	return &QuestionController{questionService: questionService, rankService: rankService}
+func NewQuestionController(
+	questionService *service.QuestionService,
+	rankService *rank.RankService,
+	closeVoteService *question_close_vote.QuestionCloseVoteService,
+	tagModeratorService *tag_moderator.TagModeratorService,
+) *QuestionController {
+	return &QuestionController{
+		questionService:     questionService,
+		rankService:         rankService,
+		closeVoteService:    closeVoteService,
+		tagModeratorService: tagModeratorService,
+	}
 }
 
 // RemoveQuestion delete question
@@ -75,10 +89,105 @@ func (qc *QuestionController) CloseQuestion(ctx *gin.Context) {
 	}
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
 	req.IsAdmin = middleware.GetIsAdminFromContext(ctx)
-	err := qc.questionService.CloseQuestion(ctx, req)
+	isModerator, err := qc.tagModeratorService.IsQuestionTagModerator(ctx, req.UserID, req.ID)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	req.IsTagModerator = isModerator
+	err = qc.questionService.CloseQuestion(ctx, req)
 	handler.HandleResponse(ctx, err, nil)
 }
 
+// GetCloseVote get the close or reopen votes of the question
+// @Summary get the close votes of the available question, or the reopen votes of the closed question
+// @Description get the close votes of the available question, or the reopen votes of the closed question
+// @Tags api-question
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id query string true "question id"
+// @Success 200 {object} handler.RespBody{data=schema.QuestionCloseVoteResp}
+// @Router /answer/api/v1/question/close/vote [get]
+func (qc *QuestionController) GetCloseVote(ctx *gin.Context) {
+	req := &schema.GetQuestionCloseVoteReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := qc.closeVoteService.GetCloseVote(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// VoteClose vote to close question
+// @Summary vote to close question, the question is closed when the votes reach the threshold
+// @Description vote to close question, the question is closed when the votes reach the threshold
+// @Tags api-question
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.QuestionCloseVoteReq true "question"
+// @Success 200 {object} handler.RespBody{data=schema.QuestionCloseVoteResp}
+// @Router /answer/api/v1/question/close/vote [post]
+func (qc *QuestionController) VoteClose(ctx *gin.Context) {
+	req := &schema.QuestionCloseVoteReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	can, err := qc.rankService.CheckOperationPermission(ctx, req.UserID, rank.QuestionCloseVoteRank, "")
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	req.IsTagModerator, err = qc.tagModeratorService.IsQuestionTagModerator(ctx, req.UserID, req.ID)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	if !can && !req.IsTagModerator {
+		handler.HandleResponse(ctx, errors.Forbidden(reason.RankFailToMeetTheCondition), nil)
+		return
+	}
+
+	resp, err := qc.closeVoteService.VoteClose(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// VoteReopen vote to reopen question
+// @Summary vote to reopen question, the question is reopened when the votes reach the threshold
+// @Description vote to reopen question, the question is reopened when the votes reach the threshold
+// @Tags api-question
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.QuestionCloseVoteReq true "question"
+// @Success 200 {object} handler.RespBody{data=schema.QuestionCloseVoteResp}
+// @Router /answer/api/v1/question/reopen/vote [post]
+func (qc *QuestionController) VoteReopen(ctx *gin.Context) {
+	req := &schema.QuestionCloseVoteReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	can, err := qc.rankService.CheckOperationPermission(ctx, req.UserID, rank.QuestionReopenVoteRank, "")
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	req.IsTagModerator, err = qc.tagModeratorService.IsQuestionTagModerator(ctx, req.UserID, req.ID)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	if !can && !req.IsTagModerator {
+		handler.HandleResponse(ctx, errors.Forbidden(reason.RankFailToMeetTheCondition), nil)
+		return
+	}
+
+	resp, err := qc.closeVoteService.VoteReopen(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
 // GetQuestion godoc
 // @Summary GetQuestion Question
 // @Description GetQuestion Question
@@ -96,6 +205,7 @@ func (qc *QuestionController) GetQuestion(ctx *gin.Context) {
 	canList, err := qc.rankService.CheckOperationPermissions(ctx, userID, []string{
 		rank.QuestionEditRank,
 		rank.QuestionDeleteRank,
+		rank.QuestionAuditRank,
 	}, id)
 	if err != nil {
 		handler.HandleResponse(ctx, err, nil)
@@ -103,9 +213,21 @@ func (qc *QuestionController) GetQuestion(ctx *gin.Context) {
 	}
 	req.CanEdit = canList[0]
 	req.CanDelete = canList[1]
+	req.CanReview = canList[2]
 	req.CanClose = middleware.GetIsAdminFromContext(ctx)
+	// the question owner does not get the vote permissions, so the object id is not given
+	voteCanList, err := qc.rankService.CheckOperationPermissions(ctx, userID, []string{
+		rank.QuestionCloseVoteRank,
+		rank.QuestionReopenVoteRank,
+	}, "")
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	req.CanCloseVote = voteCanList[0]
+	req.CanReopenVote = voteCanList[1]
 
-	info, err := qc.questionService.GetQuestionAndAddPV(ctx, id, userID, req)
+	info, err := qc.questionService.GetQuestionAndAddPV(ctx, id, userID, ctx.ClientIP(), ctx.Request.UserAgent(), req)
 	if err != nil {
 		handler.HandleResponse(ctx, err, nil)
 		return
@@ -138,7 +260,7 @@ func (qc *QuestionController) SimilarQuestion(ctx *gin.Context) {
 
 // Index godoc
 // @Summary SearchQuestionList
-// @Description SearchQuestionList <br>  "order"  Enums(newest, active,frequent,score,unanswered)
+// @Description SearchQuestionList <br>  "order"  Enums(newest, active,frequent,score,unanswered,unaccepted,hot,for_you)
 // @Tags api-question
 // @Accept  json
 // @Produce  json
@@ -207,11 +329,14 @@ func (qc *QuestionController) AddQuestion(ctx *gin.Context) {
 		return
 	}
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	req.IP = ctx.ClientIP()
+	req.UserAgent = ctx.Request.UserAgent()
 
 	canList, err := qc.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
 		rank.QuestionAddRank,
 		rank.QuestionEditRank,
 		rank.QuestionDeleteRank,
+		rank.AnswerAddRank,
 	}, "")
 	if err != nil {
 		handler.HandleResponse(ctx, err, nil)
@@ -225,6 +350,11 @@ func (qc *QuestionController) AddQuestion(ctx *gin.Context) {
 		handler.HandleResponse(ctx, errors.Forbidden(reason.RankFailToMeetTheCondition), nil)
 		return
 	}
+	// the answer posted with the question needs the permission to answer as well
+	if len(req.AnswerContent) > 0 && !canList[3] {
+		handler.HandleResponse(ctx, errors.Forbidden(reason.RankFailToMeetTheCondition), nil)
+		return
+	}
 
 	resp, err := qc.questionService.AddQuestion(ctx, req)
 	handler.HandleResponse(ctx, err, resp)
@@ -251,6 +381,7 @@ func (qc *QuestionController) UpdateQuestion(ctx *gin.Context) {
 		rank.QuestionEditRank,
 		rank.QuestionDeleteRank,
 		rank.QuestionEditWithoutReviewRank,
+		rank.QuestionEditWikiRank,
 	}, req.ID)
 	if err != nil {
 		handler.HandleResponse(ctx, err, nil)
@@ -259,10 +390,12 @@ func (qc *QuestionController) UpdateQuestion(ctx *gin.Context) {
 	req.CanEdit = canList[0]
 	req.CanDelete = canList[1]
 	req.NoNeedReview = canList[2]
+	req.CanEditWiki = canList[3]
 
 	req.CanClose = middleware.GetIsAdminFromContext(ctx)
 	req.IsAdmin = middleware.GetIsAdminFromContext(ctx)
-	if !req.CanEdit {
+	// the community wiki has a lower threshold, it is checked with the question
+	if !req.CanEdit && !req.CanEditWiki {
 		handler.HandleResponse(ctx, errors.Forbidden(reason.RankFailToMeetTheCondition), nil)
 		return
 	}
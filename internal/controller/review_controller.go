@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"answer/internal/base/handler"
+	"answer/internal/base/middleware"
+	"answer/internal/schema"
+	"answer/internal/service/rank"
+	"answer/internal/service/review"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReviewController review queue controller
+type ReviewController struct {
+	reviewService *review.ReviewService
+	rankService   *rank.RankService
+}
+
+// NewReviewController new controller
+func NewReviewController(
+	reviewService *review.ReviewService,
+	rankService *rank.RankService,
+) *ReviewController {
+	return &ReviewController{
+		reviewService: reviewService,
+		rankService:   rankService,
+	}
+}
+
+// GetReviewQueues godoc
+// @Summary get review queues
+// @Description get all the review queues with the number of the pending reviews
+// @Tags Review
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody{data=[]schema.GetReviewQueueResp}
+// @Router /answer/api/v1/review/queues [get]
+func (rc *ReviewController) GetReviewQueues(ctx *gin.Context) {
+	req := &schema.GetReviewQueuesReq{}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	permission, err := rc.getReviewPermission(ctx, req.UserID)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	req.ReviewPermission = permission
+
+	resp, err := rc.reviewService.GetReviewQueues(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetReviewPage godoc
+// @Summary get review page
+// @Description get the pending reviews of the queue
+// @Tags Review
+// @Produce json
+// @Security ApiKeyAuth
+// @Param queue query string true "queue" Enums(first_post, late_answer, low_quality, reopen_vote)
+// @Param page query int false "page"
+// @Param page_size query int false "page size"
+// @Success 200 {object} handler.RespBody{data=pager.PageModel{list=[]schema.GetReviewResp}}
+// @Router /answer/api/v1/review/page [get]
+func (rc *ReviewController) GetReviewPage(ctx *gin.Context) {
+	req := &schema.GetReviewPageReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	permission, err := rc.getReviewPermission(ctx, req.UserID)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	req.ReviewPermission = permission
+
+	resp, err := rc.reviewService.GetReviewPage(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// Review godoc
+// @Summary review
+// @Description review the pending post: approve, edit, reject or skip
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.ReviewReq true "review"
+// @Success 200 {object} handler.RespBody{}
+// @Router /answer/api/v1/review [put]
+func (rc *ReviewController) Review(ctx *gin.Context) {
+	req := &schema.ReviewReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	permission, err := rc.getReviewPermission(ctx, req.UserID)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	req.ReviewPermission = permission
+
+	err = rc.reviewService.Review(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+func (rc *ReviewController) getReviewPermission(ctx *gin.Context, userID string) (
+	permission schema.ReviewPermission, err error) {
+	canList, err := rc.rankService.CheckOperationPermissions(ctx, userID, []string{
+		rank.QuestionAuditRank,
+		rank.AnswerAuditRank,
+	}, "")
+	if err != nil {
+		return permission, err
+	}
+	permission.CanReviewQuestion = canList[0]
+	permission.CanReviewAnswer = canList[1]
+	return permission, nil
+}
@@ -54,6 +54,24 @@ func (rc *RevisionController) GetRevisionList(ctx *gin.Context) {
 	handler.HandleResponse(ctx, err, resp)
 }
 
+// GetContributorList godoc
+// @Summary get the contributors of the post
+// @Description get the users who contribute to the post by the revisions, the one with the most revisions first
+// @Tags Revision
+// @Produce json
+// @Param object_id query string true "object id"
+// @Success 200 {object} handler.RespBody{data=[]schema.ContributorInfo}
+// @Router /answer/api/v1/revisions/contributors [get]
+func (rc *RevisionController) GetContributorList(ctx *gin.Context) {
+	req := &schema.GetContributorListReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	resp, err := rc.revisionListService.GetContributors(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
 // GetUnreviewedRevisionList godoc
 // @Summary get unreviewed revision list
 // @Description get unreviewed revision list
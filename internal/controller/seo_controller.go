@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"answer/internal/base/constant"
+	"answer/internal/schema"
+	"answer/internal/service/seo"
+	"answer/pkg/converter"
+	"answer/pkg/htmltext"
+
+	"github.com/gin-gonic/gin"
+	"github.com/segmentfault/pacman/log"
+)
+
+// SeoController seo controller, serve sitemap, robots and page metadata for crawlers
+type SeoController struct {
+	seoService *seo.SeoService
+}
+
+// NewSeoController new seo controller
+func NewSeoController(seoService *seo.SeoService) *SeoController {
+	return &SeoController{seoService: seoService}
+}
+
+// Sitemap get sitemap.xml
+func (sc *SeoController) Sitemap(ctx *gin.Context) {
+	if sc.seoService.IsLoginRequired(ctx) {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	content, err := sc.seoService.GetSitemap(ctx)
+	if err != nil {
+		log.Error(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	ctx.Data(http.StatusOK, "application/xml; charset=utf-8", []byte(content))
+}
+
+// SitemapPage get sitemap page, such as /sitemap/question-2.xml
+func (sc *SeoController) SitemapPage(ctx *gin.Context) {
+	name := ctx.Param("page")
+	if sc.seoService.IsLoginRequired(ctx) ||
+		!strings.HasPrefix(name, constant.SitemapPageFilePrefix) || !strings.HasSuffix(name, ".xml") {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	page := converter.StringToInt(strings.TrimSuffix(strings.TrimPrefix(name, constant.SitemapPageFilePrefix), ".xml"))
+	content, exist, err := sc.seoService.GetSitemapPage(ctx, page)
+	if err != nil {
+		log.Error(err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	if !exist {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	ctx.Data(http.StatusOK, "application/xml; charset=utf-8", []byte(content))
+}
+
+// Robots get robots.txt
+func (sc *SeoController) Robots(ctx *gin.Context) {
+	content, err := sc.seoService.GetRobots(ctx)
+	if err != nil {
+		log.Error(err)
+	}
+	ctx.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(content))
+}
+
+// RenderPage inject the page metadata of the request path into the ui index page and write it
+func (sc *SeoController) RenderPage(ctx *gin.Context, page string) {
+	var (
+		meta  *schema.PageMeta
+		exist bool
+	)
+	paths := strings.Split(strings.Trim(ctx.Request.URL.Path, "/"), "/")
+	switch {
+	case len(paths) >= 2 && paths[0] == "questions" && paths[1] != "ask":
+		meta, exist = sc.seoService.GetQuestionPageMeta(ctx, paths[1])
+	case len(paths) == 2 && paths[0] == "tags":
+		meta, exist = sc.seoService.GetTagPageMeta(ctx, paths[1])
+	}
+	if !exist {
+		meta = sc.seoService.GetDefaultPageMeta(ctx)
+	}
+	title, tags := sc.seoService.RenderPageMeta(meta)
+	ctx.Header("content-type", "text/html;charset=utf-8")
+	ctx.String(http.StatusOK, htmltext.InjectHead(page, title, tags))
+}
@@ -43,6 +43,18 @@ func (sc *SiteinfoController) GetSiteInfo(ctx *gin.Context) {
 	if err != nil {
 		log.Error(err)
 	}
+	resp.Login, err = sc.siteInfoService.GetSiteLogin(ctx)
+	if err != nil {
+		log.Error(err)
+	}
+	resp.Accept, err = sc.siteInfoService.GetSiteAccept(ctx)
+	if err != nil {
+		log.Error(err)
+	}
+	resp.Comment, err = sc.siteInfoService.GetSiteComment(ctx)
+	if err != nil {
+		log.Error(err)
+	}
 	handler.HandleResponse(ctx, nil, resp)
 }
 
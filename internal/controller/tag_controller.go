@@ -8,6 +8,8 @@ import (
 	"answer/internal/service/rank"
 	"answer/internal/service/tag"
 	"answer/internal/service/tag_common"
+	"answer/internal/service/tag_moderator"
+	"answer/internal/service/tag_template"
 
 	"github.com/gin-gonic/gin"
 	"github.com/segmentfault/pacman/errors"
@@ -15,9 +17,11 @@ import (
 
 // TagController tag controller
 type TagController struct {
-	tagService       *tag.TagService
-	tagCommonService *tag_common.TagCommonService
-	rankService      *rank.RankService
+	tagService          *tag.TagService
+	tagCommonService    *tag_common.TagCommonService
+	rankService         *rank.RankService
+	tagTemplateService  *tag_template.TagTemplateService
+	tagModeratorService *tag_moderator.TagModeratorService
 }
 
 // NewTagController new controller
@@ -25,8 +29,11 @@ func NewTagController(
 	tagService *tag.TagService,
 	tagCommonService *tag_common.TagCommonService,
 	rankService *rank.RankService,
+	tagTemplateService *tag_template.TagTemplateService,
+	tagModeratorService *tag_moderator.TagModeratorService,
 ) *TagController {
-	return &TagController{tagService: tagService, tagCommonService: tagCommonService, rankService: rankService}
+	return &TagController{tagService: tagService, tagCommonService: tagCommonService, rankService: rankService,
+		tagTemplateService: tagTemplateService, tagModeratorService: tagModeratorService}
 }
 
 // SearchTagLike get tag list
@@ -102,6 +109,15 @@ func (tc *TagController) UpdateTag(ctx *gin.Context) {
 		handler.HandleResponse(ctx, err, nil)
 		return
 	}
+	if !canList[0] || !canList[1] {
+		// the moderators of the tag edit it without the review
+		isModerator, err := tc.tagModeratorService.IsTagModerator(ctx, req.UserID, req.TagID)
+		if err != nil {
+			handler.HandleResponse(ctx, err, nil)
+			return
+		}
+		canList[0], canList[1] = canList[0] || isModerator, canList[1] || isModerator
+	}
 	if !canList[0] {
 		handler.HandleResponse(ctx, errors.Forbidden(reason.RankFailToMeetTheCondition), nil)
 		return
@@ -234,6 +250,14 @@ func (tc *TagController) UpdateTagSynonym(ctx *gin.Context) {
 		handler.HandleResponse(ctx, err, nil)
 		return
 	}
+	if !can {
+		can, err = tc.tagModeratorService.IsTagModerator(ctx, req.UserID, req.TagID)
+		if err != nil {
+			handler.HandleResponse(ctx, err, nil)
+			return
+		}
+		req.OnlyTagModerator = can
+	}
 	if !can {
 		handler.HandleResponse(ctx, errors.Forbidden(reason.RankFailToMeetTheCondition), nil)
 		return
@@ -242,3 +266,122 @@ func (tc *TagController) UpdateTagSynonym(ctx *gin.Context) {
 	err = tc.tagService.UpdateTagSynonym(ctx, req)
 	handler.HandleResponse(ctx, err, nil)
 }
+
+// GetTagTemplateList get the question templates of the selected tags
+// @Summary get the question templates of the selected tags
+// @Description get the question templates of the selected tags, the tags without template are skipped
+// @Tags Tag
+// @Produce json
+// @Param tag_names query []string true "the slug names of the selected tags"
+// @Success 200 {object} handler.RespBody{data=[]schema.TagTemplateResp}
+// @Router /answer/api/v1/tag/template [get]
+func (tc *TagController) GetTagTemplateList(ctx *gin.Context) {
+	req := &schema.GetTagTemplateReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	resp, err := tc.tagTemplateService.GetTagTemplateList(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// SaveTagTemplate create or replace the question template of the tag
+// @Summary create or replace the question template of the tag
+// @Description create or replace the question template of the tag
+// @Tags Tag
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.SaveTagTemplateReq true "template"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/tag/template [put]
+func (tc *TagController) SaveTagTemplate(ctx *gin.Context) {
+	req := &schema.SaveTagTemplateReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	req.IsAdmin = middleware.GetIsAdminFromContext(ctx)
+	err := tc.tagTemplateService.SaveTagTemplate(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// RemoveTagTemplate remove the question template of the tag
+// @Summary remove the question template of the tag
+// @Description remove the question template of the tag
+// @Tags Tag
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.RemoveTagTemplateReq true "template"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/tag/template [delete]
+func (tc *TagController) RemoveTagTemplate(ctx *gin.Context) {
+	req := &schema.RemoveTagTemplateReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	req.IsAdmin = middleware.GetIsAdminFromContext(ctx)
+	err := tc.tagTemplateService.RemoveTagTemplate(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// GetModeratedTags get the tags which the current user moderates
+// @Summary get the tags which the current user moderates
+// @Description get the tags which the current user moderates
+// @Tags Tag
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody{data=[]schema.GetModeratedTagsResp}
+// @Router /answer/api/v1/tags/moderated [get]
+func (tc *TagController) GetModeratedTags(ctx *gin.Context) {
+	userID := middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := tc.tagModeratorService.GetModeratedTags(ctx, userID)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// AddTagModerator assign the user as the moderator of the tag
+// @Summary assign the user as the moderator of the tag
+// @Description assign the user as the moderator of the tag
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.AddTagModeratorReq true "moderator"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/tag/moderator [post]
+func (tc *TagController) AddTagModerator(ctx *gin.Context) {
+	req := &schema.AddTagModeratorReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.OperatorID = middleware.GetLoginUserIDFromContext(ctx)
+	err := tc.tagModeratorService.AddTagModerator(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// RemoveTagModerator remove the moderator of the tag
+// @Summary remove the moderator of the tag
+// @Description remove the moderator of the tag
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.RemoveTagModeratorReq true "moderator"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/tag/moderator [delete]
+func (tc *TagController) RemoveTagModerator(ctx *gin.Context) {
+	req := &schema.RemoveTagModeratorReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.OperatorID = middleware.GetLoginUserIDFromContext(ctx)
+
+	err := tc.tagModeratorService.RemoveTagModerator(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
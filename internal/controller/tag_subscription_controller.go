@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"answer/internal/base/handler"
+	"answer/internal/base/middleware"
+	"answer/internal/schema"
+	"answer/internal/service/tag_subscription"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TagSubscriptionController tag subscription controller
+type TagSubscriptionController struct {
+	tagSubscriptionService *tag_subscription.TagSubscriptionService
+}
+
+// NewTagSubscriptionController new controller
+func NewTagSubscriptionController(tagSubscriptionService *tag_subscription.TagSubscriptionService,
+) *TagSubscriptionController {
+	return &TagSubscriptionController{tagSubscriptionService: tagSubscriptionService}
+}
+
+// GetTagSubscriptions get tag subscriptions
+// @Summary get tag subscriptions
+// @Description get the tag expressions which the login user watches
+// @Tags TagSubscription
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=[]schema.TagSubscriptionResp}
+// @Router /answer/api/v1/tag/subscriptions [get]
+func (tc *TagSubscriptionController) GetTagSubscriptions(ctx *gin.Context) {
+	userID := middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := tc.tagSubscriptionService.GetSubscriptions(ctx, userID)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// AddTagSubscription add tag subscription
+// @Summary add tag subscription
+// @Description watch the tag expression, such as "go AND NOT beginner", the new questions which match it are emailed
+// @Tags TagSubscription
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body schema.AddTagSubscriptionReq true "tag subscription"
+// @Success 200 {object} handler.RespBody{data=schema.TagSubscriptionResp}
+// @Router /answer/api/v1/tag/subscription [post]
+func (tc *TagSubscriptionController) AddTagSubscription(ctx *gin.Context) {
+	req := &schema.AddTagSubscriptionReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := tc.tagSubscriptionService.AddSubscription(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// UpdateTagSubscription update tag subscription
+// @Summary update tag subscription
+// @Description change the expression or the frequency of the watched tag expression
+// @Tags TagSubscription
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body schema.UpdateTagSubscriptionReq true "tag subscription"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/tag/subscription [put]
+func (tc *TagSubscriptionController) UpdateTagSubscription(ctx *gin.Context) {
+	req := &schema.UpdateTagSubscriptionReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := tc.tagSubscriptionService.UpdateSubscription(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// RemoveTagSubscription remove tag subscription
+// @Summary remove tag subscription
+// @Description stop watching the tag expression
+// @Tags TagSubscription
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body schema.RemoveTagSubscriptionReq true "tag subscription"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/tag/subscription [delete]
+func (tc *TagSubscriptionController) RemoveTagSubscription(ctx *gin.Context) {
+	req := &schema.RemoveTagSubscriptionReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := tc.tagSubscriptionService.RemoveSubscription(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// GetTagAlertSetting get tag alert setting
+// @Summary get tag alert setting
+// @Description get the time zone and the quiet hours of the tag alerts of the login user
+// @Tags TagSubscription
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.GetTagAlertSettingResp}
+// @Router /answer/api/v1/tag/alert/setting [get]
+func (tc *TagSubscriptionController) GetTagAlertSetting(ctx *gin.Context) {
+	userID := middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := tc.tagSubscriptionService.GetAlertSetting(ctx, userID)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// UpdateTagAlertSetting update tag alert setting
+// @Summary update tag alert setting
+// @Description update the time zone and the quiet hours, no tag alert is sent in the quiet hours
+// @Tags TagSubscription
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body schema.UpdateTagAlertSettingReq true "tag alert setting"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/tag/alert/setting [put]
+func (tc *TagSubscriptionController) UpdateTagAlertSetting(ctx *gin.Context) {
+	req := &schema.UpdateTagAlertSettingReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := tc.tagSubscriptionService.UpdateAlertSetting(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// GetIgnoredTags get ignored tags
+// @Summary get ignored tags
+// @Description get the tags which the login user ignores
+// @Tags TagSubscription
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=[]schema.GetIgnoredTagResp}
+// @Router /answer/api/v1/tags/ignored [get]
+func (tc *TagSubscriptionController) GetIgnoredTags(ctx *gin.Context) {
+	userID := middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := tc.tagSubscriptionService.GetIgnoredTags(ctx, userID)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// UpdateIgnoredTags update ignored tags
+// @Summary update ignored tags
+// @Description replace the ignored tags, the questions with them are hidden from the question listings
+// @Tags TagSubscription
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body schema.UpdateIgnoredTagsReq true "ignored tags"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/tags/ignored [put]
+func (tc *TagSubscriptionController) UpdateIgnoredTags(ctx *gin.Context) {
+	req := &schema.UpdateIgnoredTagsReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := tc.tagSubscriptionService.UpdateIgnoredTags(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
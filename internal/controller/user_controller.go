@@ -63,6 +63,9 @@ func (uc *UserController) GetUserInfoByUserID(ctx *gin.Context) {
 	}
 
 	resp, err := uc.userService.GetUserInfoByUserID(ctx, token, userID)
+	if err == nil {
+		middleware.SetVisitCookie(ctx, token)
+	}
 	handler.HandleResponse(ctx, err, resp)
 }
 
@@ -111,6 +114,8 @@ func (uc *UserController) UserEmailLogin(ctx *gin.Context) {
 		return
 	}
 
+	req.IP = ctx.ClientIP()
+	req.UserAgent = ctx.GetHeader("User-Agent")
 	resp, err := uc.userService.EmailLogin(ctx, req)
 	if err != nil {
 		_, _ = uc.actionService.ActionRecordAdd(ctx, schema.ActionRecordTypeLogin, ctx.ClientIP())
@@ -122,6 +127,41 @@ func (uc *UserController) UserEmailLogin(ctx *gin.Context) {
 		return
 	}
 	uc.actionService.ActionRecordDel(ctx, schema.ActionRecordTypeLogin, ctx.ClientIP())
+	middleware.SetVisitCookie(ctx, resp.AccessToken)
+	handler.HandleResponse(ctx, nil, resp)
+}
+
+// UserTwoFactorLogin godoc
+// @Summary UserTwoFactorLogin
+// @Description the second step of login, verify the two factor code or recovery code with the two factor token
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param data body schema.UserTwoFactorLoginReq true "UserTwoFactorLoginReq"
+// @Success 200 {object} handler.RespBody{data=schema.GetUserResp}
+// @Router /answer/api/v1/user/login/2fa [post]
+func (uc *UserController) UserTwoFactorLogin(ctx *gin.Context) {
+	req := &schema.UserTwoFactorLoginReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	if len(req.Code) == 0 && len(req.RecoveryCode) == 0 {
+		errFields := append([]*validator.FormErrorField{}, &validator.FormErrorField{
+			ErrorField: "code",
+			ErrorMsg:   translator.GlobalTrans.Tr(handler.GetLang(ctx), reason.TwoFactorCodeInvalid),
+		})
+		handler.HandleResponse(ctx, errors.BadRequest(reason.TwoFactorCodeInvalid), errFields)
+		return
+	}
+
+	req.IP = ctx.ClientIP()
+	req.UserAgent = ctx.GetHeader("User-Agent")
+	resp, err := uc.userService.TwoFactorLogin(ctx, req)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	middleware.SetVisitCookie(ctx, resp.AccessToken)
 	handler.HandleResponse(ctx, nil, resp)
 }
 
@@ -191,9 +231,60 @@ func (uc *UserController) UseRePassWord(ctx *gin.Context) {
 func (uc *UserController) UserLogout(ctx *gin.Context) {
 	accessToken := middleware.ExtractToken(ctx)
 	_ = uc.authService.RemoveUserCacheInfo(ctx, accessToken)
+	middleware.RemoveVisitCookie(ctx)
 	handler.HandleResponse(ctx, nil, nil)
 }
 
+// GetUserSessionList get user session list
+// @Summary get user session list
+// @Description get the active sessions of the user, such as the devices which are logged in
+// @Tags User
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=[]schema.GetUserSessionResp}
+// @Router /answer/api/v1/user/sessions [get]
+func (uc *UserController) GetUserSessionList(ctx *gin.Context) {
+	userID := middleware.GetLoginUserIDFromContext(ctx)
+	sessionID := middleware.GetLoginSessionIDFromContext(ctx)
+	resp, err := uc.userService.GetUserSessionList(ctx, userID, sessionID)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// RevokeUserSession revoke user session
+// @Summary revoke user session
+// @Description sign out the session, such as a lost device
+// @Tags User
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body schema.RevokeUserSessionReq true "session"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/user/session [delete]
+func (uc *UserController) RevokeUserSession(ctx *gin.Context) {
+	req := &schema.RevokeUserSessionReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := uc.authService.RevokeUserSession(ctx, req.UserID, req.SessionID)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// RevokeOtherUserSessions revoke other user sessions
+// @Summary revoke other user sessions
+// @Description sign out all the sessions except the current one
+// @Tags User
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/user/sessions [delete]
+func (uc *UserController) RevokeOtherUserSessions(ctx *gin.Context) {
+	userID := middleware.GetLoginUserIDFromContext(ctx)
+	sessionID := middleware.GetLoginSessionIDFromContext(ctx)
+	err := uc.authService.RevokeUserSessions(ctx, userID, sessionID)
+	handler.HandleResponse(ctx, err, nil)
+}
+
 // UserRegisterByEmail godoc
 // @Summary UserRegisterByEmail
 // @Description UserRegisterByEmail
@@ -209,9 +300,15 @@ func (uc *UserController) UserRegisterByEmail(ctx *gin.Context) {
 		return
 	}
 	req.IP = ctx.ClientIP()
+	req.UserAgent = ctx.GetHeader("User-Agent")
 
 	resp, err := uc.userService.UserRegisterByEmail(ctx, req)
-	handler.HandleResponse(ctx, err, resp)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	middleware.SetVisitCookie(ctx, resp.AccessToken)
+	handler.HandleResponse(ctx, nil, resp)
 }
 
 // UserVerifyEmail godoc
@@ -236,6 +333,8 @@ func (uc *UserController) UserVerifyEmail(ctx *gin.Context) {
 		return
 	}
 
+	req.IP = ctx.ClientIP()
+	req.UserAgent = ctx.GetHeader("User-Agent")
 	resp, err := uc.userService.UserVerifyEmail(ctx, req)
 	if err != nil {
 		handler.HandleResponse(ctx, err, nil)
@@ -243,6 +342,7 @@ func (uc *UserController) UserVerifyEmail(ctx *gin.Context) {
 	}
 
 	uc.actionService.ActionRecordDel(ctx, schema.ActionRecordTypeEmail, ctx.ClientIP())
+	middleware.SetVisitCookie(ctx, resp.AccessToken)
 	handler.HandleResponse(ctx, err, resp)
 }
 
@@ -300,6 +400,7 @@ func (uc *UserController) UserModifyPassWord(ctx *gin.Context) {
 		return
 	}
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	req.SessionID = middleware.GetLoginSessionIDFromContext(ctx)
 
 	oldPassVerification, err := uc.userService.UserModifyPassWordVerification(ctx, req)
 	if err != nil {
@@ -470,7 +571,7 @@ func (uc *UserController) UserChangeEmailVerify(ctx *gin.Context) {
 		return
 	}
 
-	err := uc.userService.UserChangeEmailVerify(ctx, req.Content)
+	err := uc.userService.UserChangeEmailVerify(ctx, req.Content, middleware.GetLoginSessionIDFromContext(ctx))
 	uc.actionService.ActionRecordDel(ctx, schema.ActionRecordTypeEmail, ctx.ClientIP())
 	handler.HandleResponse(ctx, err, nil)
 }
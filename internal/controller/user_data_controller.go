@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"fmt"
+
+	"answer/internal/base/handler"
+	"answer/internal/base/middleware"
+	"answer/internal/schema"
+	"answer/internal/service/user_data"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserDataController user data controller
+type UserDataController struct {
+	userDataService *user_data.UserDataService
+}
+
+// NewUserDataController new controller
+func NewUserDataController(userDataService *user_data.UserDataService) *UserDataController {
+	return &UserDataController{userDataService: userDataService}
+}
+
+// ExportUserData export user data
+// @Summary export user data
+// @Description start a job to export all the data of the user as a zip file
+// @Tags User
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.GetUserDataExportResp}
+// @Router /answer/api/v1/user/data/export [post]
+func (uc *UserDataController) ExportUserData(ctx *gin.Context) {
+	userID := middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := uc.userDataService.ExportUserData(ctx, userID)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetUserDataExport get user data export
+// @Summary get user data export
+// @Description get the status of the user data export job
+// @Tags User
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.GetUserDataExportResp}
+// @Router /answer/api/v1/user/data/export [get]
+func (uc *UserDataController) GetUserDataExport(ctx *gin.Context) {
+	userID := middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := uc.userDataService.GetUserDataExport(ctx, userID)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// DownloadUserDataExport download user data export
+// @Summary download user data export
+// @Description download the zip file of the completed user data export
+// @Tags User
+// @Security ApiKeyAuth
+// @Produce application/zip
+// @Success 200 {file} file
+// @Router /answer/api/v1/user/data/export/download [get]
+func (uc *UserDataController) DownloadUserDataExport(ctx *gin.Context) {
+	userID := middleware.GetLoginUserIDFromContext(ctx)
+	filePath, err := uc.userDataService.GetUserDataExportFile(ctx, userID)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	ctx.FileAttachment(filePath, fmt.Sprintf("answer-user-data-%s.zip", userID))
+}
+
+// AddUserDeletion request to delete the account
+// @Summary request to delete the account
+// @Description request to delete the account, the account will be deleted after the grace period
+// @Tags User
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body schema.AddUserDeletionReq true "user deletion"
+// @Success 200 {object} handler.RespBody{data=schema.GetUserDeletionResp}
+// @Router /answer/api/v1/user/deletion [post]
+func (uc *UserDataController) AddUserDeletion(ctx *gin.Context) {
+	req := &schema.AddUserDeletionReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+
+	resp, err := uc.userDataService.AddUserDeletion(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetUserDeletion get the pending account deletion
+// @Summary get the pending account deletion
+// @Description get the pending account deletion
+// @Tags User
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.GetUserDeletionResp}
+// @Router /answer/api/v1/user/deletion [get]
+func (uc *UserDataController) GetUserDeletion(ctx *gin.Context) {
+	userID := middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := uc.userDataService.GetUserDeletion(ctx, userID)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// CancelUserDeletion cancel the pending account deletion
+// @Summary cancel the pending account deletion
+// @Description cancel the pending account deletion
+// @Tags User
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/user/deletion [delete]
+func (uc *UserDataController) CancelUserDeletion(ctx *gin.Context) {
+	userID := middleware.GetLoginUserIDFromContext(ctx)
+	err := uc.userDataService.CancelUserDeletion(ctx, userID)
+	handler.HandleResponse(ctx, err, nil)
+}
@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"answer/internal/base/handler"
+	"answer/internal/schema"
+	"answer/internal/service/user_invite"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserInviteController user invite controller
+type UserInviteController struct {
+	userInviteService *user_invite.UserInviteService
+}
+
+// NewUserInviteController new controller
+func NewUserInviteController(userInviteService *user_invite.UserInviteService) *UserInviteController {
+	return &UserInviteController{userInviteService: userInviteService}
+}
+
+// GetUserInvite get user invite
+// @Summary get user invite
+// @Description check the invite code is available for the register page
+// @Tags User
+// @Produce json
+// @Param code query string true "invite code"
+// @Success 200 {object} handler.RespBody{data=schema.CheckUserInviteResp}
+// @Router /answer/api/v1/user/invite [get]
+func (uc *UserInviteController) GetUserInvite(ctx *gin.Context) {
+	req := &schema.CheckUserInviteReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	resp, err := uc.userInviteService.GetUserInvite(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"answer/internal/base/handler"
+	"answer/internal/base/middleware"
+	"answer/internal/schema"
+	"answer/internal/service/user_two_factor"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserTwoFactorController user two factor controller
+type UserTwoFactorController struct {
+	userTwoFactorService *user_two_factor.UserTwoFactorService
+}
+
+// NewUserTwoFactorController new controller
+func NewUserTwoFactorController(userTwoFactorService *user_two_factor.UserTwoFactorService) *UserTwoFactorController {
+	return &UserTwoFactorController{userTwoFactorService: userTwoFactorService}
+}
+
+// GetUserTwoFactor get user two factor
+// @Summary get user two factor
+// @Description get the two factor authentication status of the user
+// @Tags User
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.GetUserTwoFactorResp}
+// @Router /answer/api/v1/user/2fa [get]
+func (uc *UserTwoFactorController) GetUserTwoFactor(ctx *gin.Context) {
+	userID := middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := uc.userTwoFactorService.GetUserTwoFactor(ctx, userID)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// SetupUserTwoFactor setup user two factor
+// @Summary setup user two factor
+// @Description generate a new secret and the otpauth uri for qr-code enrollment
+// @Tags User
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.SetupUserTwoFactorResp}
+// @Router /answer/api/v1/user/2fa/setup [post]
+func (uc *UserTwoFactorController) SetupUserTwoFactor(ctx *gin.Context) {
+	req := &schema.SetupUserTwoFactorReq{}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := uc.userTwoFactorService.SetupUserTwoFactor(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// EnableUserTwoFactor enable user two factor
+// @Summary enable user two factor
+// @Description confirm the setup with a code of the authenticator app, return the recovery codes
+// @Tags User
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body schema.EnableUserTwoFactorReq true "code"
+// @Success 200 {object} handler.RespBody{data=schema.UserTwoFactorRecoveryCodesResp}
+// @Router /answer/api/v1/user/2fa/enable [post]
+func (uc *UserTwoFactorController) EnableUserTwoFactor(ctx *gin.Context) {
+	req := &schema.EnableUserTwoFactorReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := uc.userTwoFactorService.EnableUserTwoFactor(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// DisableUserTwoFactor disable user two factor
+// @Summary disable user two factor
+// @Description disable the two factor authentication, password and a code or recovery code are required
+// @Tags User
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body schema.DisableUserTwoFactorReq true "password and code"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/user/2fa/disable [post]
+func (uc *UserTwoFactorController) DisableUserTwoFactor(ctx *gin.Context) {
+	req := &schema.DisableUserTwoFactorReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := uc.userTwoFactorService.DisableUserTwoFactor(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// RegenerateRecoveryCodes regenerate recovery codes
+// @Summary regenerate recovery codes
+// @Description generate new recovery codes, the old ones will be invalid
+// @Tags User
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body schema.RegenerateRecoveryCodesReq true "code"
+// @Success 200 {object} handler.RespBody{data=schema.UserTwoFactorRecoveryCodesResp}
+// @Router /answer/api/v1/user/2fa/recovery-codes [post]
+func (uc *UserTwoFactorController) RegenerateRecoveryCodes(ctx *gin.Context) {
+	req := &schema.RegenerateRecoveryCodesReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := uc.userTwoFactorService.RegenerateRecoveryCodes(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
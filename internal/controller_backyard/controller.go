@@ -8,4 +8,10 @@ var ProviderSetController = wire.NewSet(
 	NewUserBackyardController,
 	NewThemeController,
 	NewSiteInfoController,
+	NewUserInviteController,
+	NewUserTwoFactorController,
+	NewModerationController,
+	NewUserSuspensionController,
+	NewModeratorMessageController,
+	NewReputationController,
 )
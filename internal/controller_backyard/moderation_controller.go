@@ -0,0 +1,40 @@
+package controller_backyard
+
+import (
+	"answer/internal/base/handler"
+	"answer/internal/schema"
+	"answer/internal/service/moderation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ModerationController moderation controller
+type ModerationController struct {
+	moderationService *moderation.ModerationService
+}
+
+// NewModerationController new controller
+func NewModerationController(moderationService *moderation.ModerationService) *ModerationController {
+	return &ModerationController{moderationService: moderationService}
+}
+
+// GetModerationResultPage get moderation result page
+// @Summary get moderation result page
+// @Description get the results of the automated moderation, explain which rules fired for the post
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param object_id query string false "object id"
+// @Param action query string false "action" Enums(flag, hold, reject)
+// @Param page query int false "page"
+// @Param page_size query int false "page size"
+// @Success 200 {object} handler.RespBody{data=pager.PageModel{list=[]schema.GetModerationResultResp}}
+// @Router /answer/admin/api/moderation/results/page [get]
+func (mc *ModerationController) GetModerationResultPage(ctx *gin.Context) {
+	req := &schema.GetModerationResultPageReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := mc.moderationService.GetModerationResultPage(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
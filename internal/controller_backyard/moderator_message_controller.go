@@ -0,0 +1,72 @@
+package controller_backyard
+
+import (
+	"answer/internal/base/handler"
+	"answer/internal/base/middleware"
+	"answer/internal/schema"
+	"answer/internal/service/moderator_message"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ModeratorMessageController moderator message controller
+type ModeratorMessageController struct {
+	moderatorMessageService *moderator_message.ModeratorMessageService
+}
+
+// NewModeratorMessageController new controller
+func NewModeratorMessageController(
+	moderatorMessageService *moderator_message.ModeratorMessageService) *ModeratorMessageController {
+	return &ModeratorMessageController{moderatorMessageService: moderatorMessageService}
+}
+
+// GetModeratorMessageTemplates get moderator message templates
+// @Summary get moderator message templates
+// @Description get the canned messages which the moderator can start with
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=[]schema.ModeratorMessageTemplate}
+// @Router /answer/admin/api/moderator/message/templates [get]
+func (mc *ModeratorMessageController) GetModeratorMessageTemplates(ctx *gin.Context) {
+	resp := mc.moderatorMessageService.GetTemplates(handler.GetLang(ctx))
+	handler.HandleResponse(ctx, nil, resp)
+}
+
+// AddModeratorMessage add moderator message
+// @Summary add moderator message
+// @Description send the private message to the user, and optionally to the email of the user
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.AddModeratorMessageReq true "message"
+// @Success 200 {object} handler.RespBody{data=schema.ModeratorMessageResp}
+// @Router /answer/admin/api/moderator/message [post]
+func (mc *ModeratorMessageController) AddModeratorMessage(ctx *gin.Context) {
+	req := &schema.AddModeratorMessageReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.SenderID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := mc.moderatorMessageService.AddMessage(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetModeratorMessageThreadList get moderator message thread list
+// @Summary get moderator message thread list
+// @Description get all the message threads between the moderators and the user, the newest first
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param user_id query string true "user id"
+// @Success 200 {object} handler.RespBody{data=[]schema.ModeratorMessageThreadResp}
+// @Router /answer/admin/api/user/moderator/messages [get]
+func (mc *ModeratorMessageController) GetModeratorMessageThreadList(ctx *gin.Context) {
+	req := &schema.GetModeratorMessageThreadListReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := mc.moderatorMessageService.GetThreadList(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
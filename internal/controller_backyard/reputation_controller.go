@@ -0,0 +1,39 @@
+package controller_backyard
+
+import (
+	"answer/internal/base/handler"
+	"answer/internal/schema"
+	"answer/internal/service/reputation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReputationController reputation controller
+type ReputationController struct {
+	reputationService *reputation.ReputationService
+}
+
+// NewReputationController new controller
+func NewReputationController(reputationService *reputation.ReputationService) *ReputationController {
+	return &ReputationController{reputationService: reputationService}
+}
+
+// RecalculateReputation recalculate reputation
+// @Summary recalculate reputation
+// @Description recalculate the reputation of a batch of users from their activities under the current rules,
+// @Description report the differences and fix them unless it is a dry run. Continue with the next_user_id of the response.
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.RecalculateReputationReq true "recalculate reputation"
+// @Success 200 {object} handler.RespBody{data=schema.RecalculateReputationResp}
+// @Router /answer/admin/api/reputation/recalculate [post]
+func (rc *ReputationController) RecalculateReputation(ctx *gin.Context) {
+	req := &schema.RecalculateReputationReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := rc.reputationService.RecalculateReputation(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
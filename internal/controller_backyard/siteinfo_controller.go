@@ -86,6 +86,110 @@ func (sc *SiteInfoController) GetSiteLegal(ctx *gin.Context) {
 	handler.HandleResponse(ctx, err, resp)
 }
 
+// GetSeo get site seo information
+// @Summary get site seo information
+// @Description get site seo information
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.SiteSeoResp}
+// @Router /answer/admin/api/siteinfo/seo [get]
+func (sc *SiteInfoController) GetSeo(ctx *gin.Context) {
+	resp, err := sc.siteInfoService.GetSiteSeo(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetSiteLogin get site login information
+// @Summary get site login information
+// @Description get site login information
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.SiteLoginResp}
+// @Router /answer/admin/api/siteinfo/login [get]
+func (sc *SiteInfoController) GetSiteLogin(ctx *gin.Context) {
+	resp, err := sc.siteInfoService.GetSiteLogin(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetSiteReview get site review queue information
+// @Summary get site review queue information
+// @Description get site review queue information
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.SiteReviewResp}
+// @Router /answer/admin/api/siteinfo/review [get]
+func (sc *SiteInfoController) GetSiteReview(ctx *gin.Context) {
+	resp, err := sc.siteInfoService.GetSiteReview(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetSiteCloseVote get site close vote information
+// @Summary get site close vote information
+// @Description get site close vote information
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.SiteCloseVoteResp}
+// @Router /answer/admin/api/siteinfo/close/vote [get]
+func (sc *SiteInfoController) GetSiteCloseVote(ctx *gin.Context) {
+	resp, err := sc.siteInfoService.GetSiteCloseVote(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetSiteAccept get site accepted answer information
+// @Summary get site accepted answer information
+// @Description get site accepted answer information
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.SiteAcceptResp}
+// @Router /answer/admin/api/siteinfo/accept [get]
+func (sc *SiteInfoController) GetSiteAccept(ctx *gin.Context) {
+	resp, err := sc.siteInfoService.GetSiteAccept(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetSiteComment get site comment information
+// @Summary get site comment information
+// @Description get site comment information
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.SiteCommentResp}
+// @Router /answer/admin/api/siteinfo/comment [get]
+func (sc *SiteInfoController) GetSiteComment(ctx *gin.Context) {
+	resp, err := sc.siteInfoService.GetSiteComment(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetSiteTagModerator get site tag moderator information
+// @Summary get site tag moderator information
+// @Description get site tag moderator information
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.SiteTagModeratorResp}
+// @Router /answer/admin/api/siteinfo/tag/moderator [get]
+func (sc *SiteInfoController) GetSiteTagModerator(ctx *gin.Context) {
+	resp, err := sc.siteInfoService.GetSiteTagModerator(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetSiteModeration get site automated moderation information
+// @Summary get site automated moderation information
+// @Description get site automated moderation information
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.SiteModerationResp}
+// @Router /answer/admin/api/siteinfo/moderation [get]
+func (sc *SiteInfoController) GetSiteModeration(ctx *gin.Context) {
+	resp, err := sc.siteInfoService.GetSiteModeration(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
+
 // UpdateGeneral update site general information
 // @Summary update site general information
 // @Description update site general information
@@ -178,6 +282,150 @@ func (sc *SiteInfoController) UpdateSiteLegal(ctx *gin.Context) {
 	handler.HandleResponse(ctx, err, nil)
 }
 
+// UpdateSeo update site seo information
+// @Summary update site seo information
+// @Description update site seo information
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param data body schema.SiteSeoReq true "seo"
+// @Success 200 {object} handler.RespBody{}
+// @Router /answer/admin/api/siteinfo/seo [put]
+func (sc *SiteInfoController) UpdateSeo(ctx *gin.Context) {
+	req := &schema.SiteSeoReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := sc.siteInfoService.SaveSiteSeo(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// UpdateSiteLogin update site login information
+// @Summary update site login information
+// @Description update site login information
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param data body schema.SiteLoginReq true "login"
+// @Success 200 {object} handler.RespBody{}
+// @Router /answer/admin/api/siteinfo/login [put]
+func (sc *SiteInfoController) UpdateSiteLogin(ctx *gin.Context) {
+	req := &schema.SiteLoginReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := sc.siteInfoService.SaveSiteLogin(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// UpdateSiteReview update site review queue information
+// @Summary update site review queue information
+// @Description update site review queue information
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param data body schema.SiteReviewReq true "review"
+// @Success 200 {object} handler.RespBody{}
+// @Router /answer/admin/api/siteinfo/review [put]
+func (sc *SiteInfoController) UpdateSiteReview(ctx *gin.Context) {
+	req := &schema.SiteReviewReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := sc.siteInfoService.SaveSiteReview(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// UpdateSiteCloseVote update site close vote information
+// @Summary update site close vote information
+// @Description update site close vote information
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param data body schema.SiteCloseVoteReq true "close vote"
+// @Success 200 {object} handler.RespBody{}
+// @Router /answer/admin/api/siteinfo/close/vote [put]
+func (sc *SiteInfoController) UpdateSiteCloseVote(ctx *gin.Context) {
+	req := &schema.SiteCloseVoteReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := sc.siteInfoService.SaveSiteCloseVote(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// UpdateSiteAccept update site accepted answer information
+// @Summary update site accepted answer information
+// @Description update site accepted answer information
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param data body schema.SiteAcceptReq true "accepted answer"
+// @Success 200 {object} handler.RespBody{}
+// @Router /answer/admin/api/siteinfo/accept [put]
+func (sc *SiteInfoController) UpdateSiteAccept(ctx *gin.Context) {
+	req := &schema.SiteAcceptReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := sc.siteInfoService.SaveSiteAccept(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// UpdateSiteComment update site comment information
+// @Summary update site comment information
+// @Description update site comment information
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param data body schema.SiteCommentReq true "comment"
+// @Success 200 {object} handler.RespBody{}
+// @Router /answer/admin/api/siteinfo/comment [put]
+func (sc *SiteInfoController) UpdateSiteComment(ctx *gin.Context) {
+	req := &schema.SiteCommentReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := sc.siteInfoService.SaveSiteComment(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// UpdateSiteTagModerator update site tag moderator information
+// @Summary update site tag moderator information
+// @Description update site tag moderator information
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param data body schema.SiteTagModeratorReq true "tag moderator"
+// @Success 200 {object} handler.RespBody{}
+// @Router /answer/admin/api/siteinfo/tag/moderator [put]
+func (sc *SiteInfoController) UpdateSiteTagModerator(ctx *gin.Context) {
+	req := &schema.SiteTagModeratorReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := sc.siteInfoService.SaveSiteTagModerator(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// UpdateSiteModeration update site automated moderation information
+// @Summary update site automated moderation information
+// @Description update site automated moderation information
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param data body schema.SiteModerationReq true "moderation"
+// @Success 200 {object} handler.RespBody{}
+// @Router /answer/admin/api/siteinfo/moderation [put]
+func (sc *SiteInfoController) UpdateSiteModeration(ctx *gin.Context) {
+	req := &schema.SiteModerationReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := sc.siteInfoService.SaveSiteModeration(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
 // GetSMTPConfig get smtp config
 // @Summary GetSMTPConfig get smtp config
 // @Description GetSMTPConfig get smtp config
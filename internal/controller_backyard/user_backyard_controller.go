@@ -2,6 +2,7 @@ package controller_backyard
 
 import (
 	"answer/internal/base/handler"
+	"answer/internal/base/middleware"
 	"answer/internal/schema"
 	"answer/internal/service/user_backyard"
 
@@ -33,11 +34,32 @@ func (uc *UserBackyardController) UpdateUserStatus(ctx *gin.Context) {
 	if handler.BindAndCheck(ctx, req) {
 		return
 	}
+	req.OperatorID = middleware.GetLoginUserIDFromContext(ctx)
 
 	err := uc.userService.UpdateUserStatus(ctx, req)
 	handler.HandleResponse(ctx, err, nil)
 }
 
+// SignOutUser sign out user everywhere
+// @Summary sign out user everywhere
+// @Description revoke all sessions of the user
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.SignOutUserReq true "user"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/user/sessions [delete]
+func (uc *UserBackyardController) SignOutUser(ctx *gin.Context) {
+	req := &schema.SignOutUserReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	err := uc.userService.SignOutUser(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
 // GetUserPage get user page
 // @Summary get user page
 // @Description get user page
@@ -0,0 +1,81 @@
+package controller_backyard
+
+import (
+	"answer/internal/base/handler"
+	"answer/internal/base/middleware"
+	"answer/internal/schema"
+	"answer/internal/service/user_invite"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserInviteController user invite controller
+type UserInviteController struct {
+	userInviteService *user_invite.UserInviteService
+}
+
+// NewUserInviteController new controller
+func NewUserInviteController(userInviteService *user_invite.UserInviteService) *UserInviteController {
+	return &UserInviteController{userInviteService: userInviteService}
+}
+
+// AddUserInvite add user invite
+// @Summary add user invite
+// @Description add user invite, return the invite link
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.AddUserInviteReq true "user invite"
+// @Success 200 {object} handler.RespBody{data=schema.AddUserInviteResp}
+// @Router /answer/admin/api/user/invite [post]
+func (uc *UserInviteController) AddUserInvite(ctx *gin.Context) {
+	req := &schema.AddUserInviteReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+
+	resp, err := uc.userInviteService.AddUserInvite(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// RemoveUserInvite remove user invite
+// @Summary remove user invite
+// @Description remove user invite, the invite link can not be used anymore
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.RemoveUserInviteReq true "user invite"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/user/invite [delete]
+func (uc *UserInviteController) RemoveUserInvite(ctx *gin.Context) {
+	req := &schema.RemoveUserInviteReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	err := uc.userInviteService.RemoveUserInvite(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// GetUserInvitePage get user invite page
+// @Summary get user invite page
+// @Description get user invite page
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param page query int false "page"
+// @Param page_size query int false "page size"
+// @Success 200 {object} handler.RespBody{data=pager.PageModel{records=[]schema.GetUserInvitePageResp}}
+// @Router /answer/admin/api/user/invites/page [get]
+func (uc *UserInviteController) GetUserInvitePage(ctx *gin.Context) {
+	req := &schema.GetUserInvitePageReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	resp, err := uc.userInviteService.GetUserInvitePage(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
@@ -0,0 +1,97 @@
+package controller_backyard
+
+import (
+	"answer/internal/base/handler"
+	"answer/internal/base/middleware"
+	"answer/internal/schema"
+	"answer/internal/service/user_suspension"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserSuspensionController user suspension controller
+type UserSuspensionController struct {
+	userSuspensionService *user_suspension.UserSuspensionService
+}
+
+// NewUserSuspensionController new controller
+func NewUserSuspensionController(userSuspensionService *user_suspension.UserSuspensionService) *UserSuspensionController {
+	return &UserSuspensionController{userSuspensionService: userSuspensionService}
+}
+
+// GetUserSuspensionList get user suspension list
+// @Summary get user suspension list
+// @Description get the suspension history of the user, the newest first
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param user_id query string true "user id"
+// @Success 200 {object} handler.RespBody{data=[]schema.UserSuspensionResp}
+// @Router /answer/admin/api/user/suspensions [get]
+func (uc *UserSuspensionController) GetUserSuspensionList(ctx *gin.Context) {
+	req := &schema.GetUserSuspensionListReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := uc.userSuspensionService.GetUserSuspensionList(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetUserBanPage get user ban page
+// @Summary get user ban page
+// @Description get the ips and email domains which are not allowed to register
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param ban_type query string false "ban type" Enums(ip, email_domain)
+// @Param page query int false "page"
+// @Param page_size query int false "page size"
+// @Success 200 {object} handler.RespBody{data=pager.PageModel{list=[]schema.GetUserBanResp}}
+// @Router /answer/admin/api/user/bans/page [get]
+func (uc *UserSuspensionController) GetUserBanPage(ctx *gin.Context) {
+	req := &schema.GetUserBanPageReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := uc.userSuspensionService.GetUserBanPage(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// AddUserBan add user ban
+// @Summary add user ban
+// @Description ban the ip or email domain from registering
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.AddUserBanReq true "ban"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/user/ban [post]
+func (uc *UserSuspensionController) AddUserBan(ctx *gin.Context) {
+	req := &schema.AddUserBanReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.OperatorID = middleware.GetLoginUserIDFromContext(ctx)
+	err := uc.userSuspensionService.AddUserBan(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// RemoveUserBan remove user ban
+// @Summary remove user ban
+// @Description remove user ban
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.RemoveUserBanReq true "ban"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/user/ban [delete]
+func (uc *UserSuspensionController) RemoveUserBan(ctx *gin.Context) {
+	req := &schema.RemoveUserBanReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := uc.userSuspensionService.RemoveUserBan(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
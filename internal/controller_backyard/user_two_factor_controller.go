@@ -0,0 +1,38 @@
+package controller_backyard
+
+import (
+	"answer/internal/base/handler"
+	"answer/internal/schema"
+	"answer/internal/service/user_two_factor"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserTwoFactorController user two factor controller
+type UserTwoFactorController struct {
+	userTwoFactorService *user_two_factor.UserTwoFactorService
+}
+
+// NewUserTwoFactorController new controller
+func NewUserTwoFactorController(userTwoFactorService *user_two_factor.UserTwoFactorService) *UserTwoFactorController {
+	return &UserTwoFactorController{userTwoFactorService: userTwoFactorService}
+}
+
+// ResetUserTwoFactor reset user two factor
+// @Summary reset user two factor
+// @Description remove the two factor authentication of the user, such as the user lost the device
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.ResetUserTwoFactorReq true "user"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/user/2fa [delete]
+func (uc *UserTwoFactorController) ResetUserTwoFactor(ctx *gin.Context) {
+	req := &schema.ResetUserTwoFactorReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := uc.userTwoFactorService.ResetUserTwoFactor(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+const (
+	AnswerAcceptActionAccept   = 1
+	AnswerAcceptActionUnaccept = 2
+)
+
+// AnswerAcceptLog the history of accepting and unaccepting the answers of the question
+type AnswerAcceptLog struct {
+	ID         string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt  time.Time `xorm:"created TIMESTAMP created_at"`
+	QuestionID string    `xorm:"not null default 0 BIGINT(20) INDEX question_id"`
+	AnswerID   string    `xorm:"not null default 0 BIGINT(20) INDEX answer_id"`
+	// UserID the user who accepts or unaccepts the answer, it is the moderator if on behalf of the asker
+	UserID string `xorm:"not null default 0 BIGINT(20) user_id"`
+	Action int    `xorm:"not null default 0 TINYINT(4) action"`
+	// OnBehalf the moderator accepts the answer on behalf of the inactive asker
+	OnBehalf bool `xorm:"not null default false BOOL on_behalf"`
+}
+
+// TableName answer accept log table name
+func (AnswerAcceptLog) TableName() string {
+	return "answer_accept_log"
+}
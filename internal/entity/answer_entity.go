@@ -8,11 +8,14 @@ const (
 	AnswerSearchOrderByVote    = "vote"
 
 	AnswerStatusAvailable = 1
-	AnswerStatusDeleted   = 10
+	// AnswerStatusPending the answer is held in the review queue, only the author can see it
+	AnswerStatusPending = 3
+	AnswerStatusDeleted = 10
 )
 
 var CmsAnswerSearchStatus = map[string]int{
 	"available": AnswerStatusAvailable,
+	"pending":   AnswerStatusPending,
 	"deleted":   AnswerStatusDeleted,
 }
 
@@ -31,6 +34,9 @@ type Answer struct {
 	CommentCount   int       `xorm:"not null default 0 INT(11) comment_count"`
 	VoteCount      int       `xorm:"not null default 0 INT(11) vote_count"`
 	RevisionID     string    `xorm:"not null default 0 BIGINT(20) revision_id"`
+	Language       string    `xorm:"not null default '' VARCHAR(16) language"`
+	// CommunityWiki the answer is owned by the community, its votes do not change the reputation of the author
+	CommunityWiki bool `xorm:"not null default false BOOL community_wiki"`
 }
 
 type AnswerSearch struct {
@@ -38,6 +44,8 @@ type AnswerSearch struct {
 	Order    string `json:"order_by" `                  // default or updated
 	Page     int    `json:"page" form:"page"`           // Query number of pages
 	PageSize int    `json:"page_size" form:"page_size"` // Search page size
+	// PendingUserID the pending answers of the user are also searched, the author can see the answers under review
+	PendingUserID string `json:"-"`
 }
 
 type CmsAnswerSearch struct {
@@ -6,4 +6,10 @@ type UserCacheInfo struct {
 	UserStatus  int    `json:"user_status"`
 	EmailStatus int    `json:"email_status"`
 	IsAdmin     bool   `json:"is_admin"`
+	// SessionID the session record of the access token
+	SessionID string `json:"session_id"`
+	// LastSeenAt the unix time of the last request, updated at most once in the session active interval
+	LastSeenAt int64 `json:"last_seen_at"`
+	// IssuedAt the unix milli time the access token is issued, the tokens issued before the sessions have none
+	IssuedAt int64 `json:"issued_at"`
 }
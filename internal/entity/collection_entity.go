@@ -10,6 +10,8 @@ type Collection struct {
 	UserID                string    `xorm:"not null default 0 BIGINT(20) INDEX user_id"`
 	ObjectID              string    `xorm:"not null default 0 BIGINT(20) object_id"`
 	UserCollectionGroupID string    `xorm:"not null default 0 BIGINT(20) user_collection_group_id"`
+	// Note the private note of the user, it is not shown in the public group
+	Note string `xorm:"not null default '' VARCHAR(1000) note"`
 }
 
 type CollectionSearch struct {
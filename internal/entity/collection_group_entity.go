@@ -10,6 +10,11 @@ type CollectionGroup struct {
 	UserID       string    `xorm:"not null default 0 BIGINT(20) INDEX user_id"`
 	Name         string    `xorm:"not null default '' VARCHAR(50) name"`
 	DefaultGroup int       `xorm:"not null default 1 INT(11) default_group"`
+	// SortOrder the groups are listed by it in ascending order
+	SortOrder int `xorm:"not null default 0 INT(11) sort_order"`
+	// IsPublic the public group can be visited by anyone with the share token, and it has a rss feed
+	IsPublic   bool   `xorm:"not null default false BOOL is_public"`
+	ShareToken string `xorm:"not null default '' VARCHAR(64) INDEX share_token"`
 }
 
 // TableName collection group table name
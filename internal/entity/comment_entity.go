@@ -21,12 +21,16 @@ type Comment struct {
 	UserID         string        `xorm:"not null default 0 BIGINT(20) user_id"`
 	ReplyUserID    sql.NullInt64 `xorm:"BIGINT(20) reply_user_id"`
 	ReplyCommentID sql.NullInt64 `xorm:"BIGINT(20) reply_comment_id"`
-	ObjectID       string        `xorm:"not null default 0 BIGINT(20) INDEX object_id"`
-	QuestionID     string        `xorm:"not null default 0 BIGINT(20) question_id"`
-	VoteCount      int           `xorm:"not null default 0 INT(11) vote_count"`
-	Status         int           `xorm:"not null default 0 TINYINT(4) status"`
-	OriginalText   string        `xorm:"not null MEDIUMTEXT original_text"`
-	ParsedText     string        `xorm:"not null MEDIUMTEXT parsed_text"`
+	// ParentCommentID the comment which the comment is nested under in the thread, 0 means the top level
+	ParentCommentID string `xorm:"not null default 0 BIGINT(20) INDEX parent_comment_id"`
+	// Depth the nesting level of the comment in the thread, 0 means the top level
+	Depth        int    `xorm:"not null default 0 INT(11) depth"`
+	ObjectID     string `xorm:"not null default 0 BIGINT(20) INDEX object_id"`
+	QuestionID   string `xorm:"not null default 0 BIGINT(20) question_id"`
+	VoteCount    int    `xorm:"not null default 0 INT(11) vote_count"`
+	Status       int    `xorm:"not null default 0 TINYINT(4) status"`
+	OriginalText string `xorm:"not null MEDIUMTEXT original_text"`
+	ParsedText   string `xorm:"not null MEDIUMTEXT parsed_text"`
 }
 
 // TableName comment table name
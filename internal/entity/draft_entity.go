@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+const (
+	// DraftTypeQuestion the draft of the new question, the object id is 0
+	DraftTypeQuestion = "question"
+	// DraftTypeAnswer the draft of the new answer, the object id is the question id
+	DraftTypeAnswer = "answer"
+	// DraftTypeEdit the draft of editing the post, the object id is the question or answer id
+	DraftTypeEdit = "edit"
+)
+
+// Draft the autosaved draft of the post, the user has only one draft in the same context
+type Draft struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt time.Time `xorm:"updated TIMESTAMP INDEX updated_at"`
+	UserID    string    `xorm:"not null default 0 BIGINT(20) UNIQUE(draft) user_id"`
+	DraftType string    `xorm:"not null default '' VARCHAR(32) UNIQUE(draft) draft_type"`
+	ObjectID  string    `xorm:"not null default 0 BIGINT(20) UNIQUE(draft) object_id"`
+	Title     string    `xorm:"not null default '' VARCHAR(255) title"`
+	Content   string    `xorm:"not null MEDIUMTEXT content"`
+	// Tags the slug names of the tags in json
+	Tags string `xorm:"not null TEXT tags"`
+}
+
+// TableName draft table name
+func (Draft) TableName() string {
+	return "draft"
+}
@@ -0,0 +1,34 @@
+package entity
+
+import "time"
+
+// FeedDigest the weekly digest of the following feed sent to the user, the user gets one digest in the same period
+type FeedDigest struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
+	UserID    string    `xorm:"not null default 0 BIGINT(20) UNIQUE(digest) user_id"`
+	// Period the start date of the period, such as 2006-01-02
+	Period    string `xorm:"not null default '' VARCHAR(16) UNIQUE(digest) period"`
+	ItemCount int    `xorm:"not null default 0 INT(11) item_count"`
+}
+
+// TableName feed digest table name
+func (FeedDigest) TableName() string {
+	return "feed_digest"
+}
+
+// FeedSearch the conditions of the following feed, the activities of the user self are excluded
+type FeedSearch struct {
+	UserID string
+	// FollowedUserIDs the new posts of the users
+	FollowedUserIDs []string
+	// FollowedTagIDs the new questions in the tags
+	FollowedTagIDs []string
+	// FollowedQuestionIDs the new answers and edits on the questions
+	FollowedQuestionIDs []string
+	// Cursor only the activities older than the cursor activity are returned, empty for the newest
+	Cursor string
+	// Since only the activities created after the time are returned if it is set
+	Since time.Time
+	Limit int
+}
@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+const (
+	// ModerationActionPass the post passed the moderation
+	ModerationActionPass = "pass"
+	// ModerationActionFlag the post is flagged into the report queue
+	ModerationActionFlag = "flag"
+	// ModerationActionHold the post is held for review until approved
+	ModerationActionHold = "hold"
+	// ModerationActionReject the post is rejected and not saved
+	ModerationActionReject = "reject"
+)
+
+// ModerationResult the result of the automated moderation of the post, explains which rules fired
+type ModerationResult struct {
+	ID         string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt  time.Time `xorm:"created TIMESTAMP created_at"`
+	ObjectType string    `xorm:"not null default '' VARCHAR(32) object_type"`
+	// ObjectID the id of the post, 0 if the post is rejected
+	ObjectID string `xorm:"not null default 0 BIGINT(20) INDEX object_id"`
+	UserID   string `xorm:"not null default 0 BIGINT(20) user_id"`
+	Score    int    `xorm:"not null default 0 INT(11) score"`
+	Action   string `xorm:"not null default '' VARCHAR(16) INDEX action"`
+	// Hits the json of the fired rules
+	Hits string `xorm:"not null TEXT hits"`
+	// Excerpt the excerpt of the post, it is the only record of the rejected post
+	Excerpt string `xorm:"not null TEXT excerpt"`
+}
+
+// TableName moderation result table name
+func (ModerationResult) TableName() string {
+	return "moderation_result"
+}
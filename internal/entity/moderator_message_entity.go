@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// ModeratorMessage the private message between the moderators and the user.
+// The first message of the thread is always sent by a moderator, the thread id of it is 0,
+// and the replies of the thread refer to the id of the first message.
+type ModeratorMessage struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
+	ThreadID  string    `xorm:"not null default 0 BIGINT(20) INDEX thread_id"`
+	// UserID the user whom the thread is about
+	UserID string `xorm:"not null default 0 BIGINT(20) INDEX user_id"`
+	// SenderID the moderator or the user self who sends the message
+	SenderID    string `xorm:"not null default 0 BIGINT(20) sender_id"`
+	TemplateKey string `xorm:"not null default '' VARCHAR(64) template_key"`
+	Content     string `xorm:"not null TEXT content"`
+}
+
+// TableName moderator message table name
+func (ModeratorMessage) TableName() string {
+	return "moderator_message"
+}
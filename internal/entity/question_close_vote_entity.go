@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+const (
+	// QuestionCloseVoteTypeClose the vote to close the question
+	QuestionCloseVoteTypeClose = 1
+	// QuestionCloseVoteTypeReopen the vote to reopen the question
+	QuestionCloseVoteTypeReopen = 2
+)
+
+const (
+	// QuestionCloseVoteStatusActive the vote is counted until it expires
+	QuestionCloseVoteStatusActive = 1
+	// QuestionCloseVoteStatusCompleted the votes closed or reopened the question, they are not counted any more
+	QuestionCloseVoteStatusCompleted = 2
+)
+
+// QuestionCloseVote the vote of the user to close or reopen the question, the user has one vote of each type on the question
+type QuestionCloseVote struct {
+	ID         string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt  time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt  time.Time `xorm:"updated TIMESTAMP updated_at"`
+	QuestionID string    `xorm:"not null default 0 BIGINT(20) INDEX UNIQUE(question_user_vote) question_id"`
+	UserID     string    `xorm:"not null default 0 BIGINT(20) UNIQUE(question_user_vote) user_id"`
+	VoteType   int       `xorm:"not null default 1 INT(11) UNIQUE(question_user_vote) vote_type"`
+	CloseType  int       `xorm:"not null default 0 INT(11) close_type"`
+	CloseMsg   string    `xorm:"not null default '' VARCHAR(255) close_msg"`
+	Status     int       `xorm:"not null default 1 INT(11) status"`
+}
+
+// TableName question close vote table name
+func (QuestionCloseVote) TableName() string {
+	return "question_close_vote"
+}
@@ -7,18 +7,22 @@ import (
 const (
 	QuestionStatusAvailable = 1
 	QuestionStatusClosed    = 2
-	QuestionStatusDeleted   = 10
+	// QuestionStatusPending the question is held in the review queue, only the author can see it
+	QuestionStatusPending = 3
+	QuestionStatusDeleted = 10
 )
 
 var CmsQuestionSearchStatus = map[string]int{
 	"available": QuestionStatusAvailable,
 	"closed":    QuestionStatusClosed,
+	"pending":   QuestionStatusPending,
 	"deleted":   QuestionStatusDeleted,
 }
 
 var CmsQuestionSearchStatusIntToString = map[int]string{
 	QuestionStatusAvailable: "available",
 	QuestionStatusClosed:    "closed",
+	QuestionStatusPending:   "pending",
 	QuestionStatusDeleted:   "deleted",
 }
 
@@ -48,6 +52,10 @@ type Question struct {
 	LastAnswerID     string    `xorm:"not null default 0 BIGINT(20) last_answer_id"`
 	PostUpdateTime   time.Time `xorm:"post_update_time TIMESTAMP"`
 	RevisionID       string    `xorm:"not null default 0 BIGINT(20) revision_id"`
+	HotScore         float64   `xorm:"not null default 0 DOUBLE INDEX hot_score"`
+	Language         string    `xorm:"not null default '' VARCHAR(16) INDEX language"`
+	// CommunityWiki the question is owned by the community, its votes do not change the reputation of the author
+	CommunityWiki bool `xorm:"not null default false BOOL community_wiki"`
 }
 
 // TableName question table name
@@ -0,0 +1,68 @@
+package entity
+
+import "time"
+
+const (
+	// ReviewStatusPending the review is waiting for the reviewer
+	ReviewStatusPending = 1
+	// ReviewStatusApproved the post is approved by the reviewer
+	ReviewStatusApproved = 2
+	// ReviewStatusRejected the post is rejected by the reviewer
+	ReviewStatusRejected = 3
+)
+
+const (
+	// ReviewQueueFirstPost the first posts of new users and the posts of low-reputation users
+	ReviewQueueFirstPost = "first_post"
+	// ReviewQueueLateAnswer the answers to old questions
+	ReviewQueueLateAnswer = "late_answer"
+	// ReviewQueueLowQuality the posts which are very short or only contain links
+	ReviewQueueLowQuality = "low_quality"
+	// ReviewQueueReopenVote the closed questions which are edited and voted to reopen
+	ReviewQueueReopenVote = "reopen_vote"
+	// ReviewQueueModeration the posts which are held by the automated moderation
+	ReviewQueueModeration = "moderation"
+)
+
+// ReviewQueues all the review queues
+var ReviewQueues = []string{
+	ReviewQueueFirstPost,
+	ReviewQueueLateAnswer,
+	ReviewQueueLowQuality,
+	ReviewQueueReopenVote,
+	ReviewQueueModeration,
+}
+
+// Review the post waiting in the review queue
+type Review struct {
+	ID         string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt  time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt  time.Time `xorm:"updated TIMESTAMP updated_at"`
+	Queue      string    `xorm:"not null default '' VARCHAR(32) INDEX queue"`
+	ObjectType int       `xorm:"not null default 0 INT(11) object_type"`
+	ObjectID   string    `xorm:"not null default 0 BIGINT(20) INDEX object_id"`
+	UserID     string    `xorm:"not null default 0 BIGINT(20) user_id"`
+	ReviewerID string    `xorm:"not null default 0 BIGINT(20) reviewer_id"`
+	Action     string    `xorm:"not null default '' VARCHAR(16) action"`
+	// Hold whether the post is hidden from other users until it is approved
+	Hold   bool `xorm:"not null default false BOOL hold"`
+	Status int  `xorm:"not null default 1 INT(11) status"`
+}
+
+// TableName review table name
+func (Review) TableName() string {
+	return "review"
+}
+
+// ReviewSkip the review skipped by the reviewer, it is not shown to the reviewer again
+type ReviewSkip struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
+	ReviewID  string    `xorm:"not null default 0 BIGINT(20) UNIQUE(review_user) review_id"`
+	UserID    string    `xorm:"not null default 0 BIGINT(20) UNIQUE(review_user) user_id"`
+}
+
+// TableName review skip table name
+func (ReviewSkip) TableName() string {
+	return "review_skip"
+}
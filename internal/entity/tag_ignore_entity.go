@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// TagIgnore the tag which the user ignores, the questions with it are hidden from the question listings of the user
+type TagIgnore struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
+	UserID    string    `xorm:"not null default 0 BIGINT(20) UNIQUE(user_tag) user_id"`
+	TagID     string    `xorm:"not null default 0 BIGINT(20) UNIQUE(user_tag) tag_id"`
+}
+
+// TableName tag ignore table name
+func (TagIgnore) TableName() string {
+	return "tag_ignore"
+}
@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+const (
+	// TagModeratorSourceManual the moderator is assigned by the admin
+	TagModeratorSourceManual = "manual"
+	// TagModeratorSourceAuto the moderator is assigned automatically as one of the top answerers in the tag
+	TagModeratorSourceAuto = "auto"
+	// TagModeratorSourceRemoved the moderator is removed by the admin, the user is not assigned automatically again
+	TagModeratorSourceRemoved = "removed"
+)
+
+// TagModerator the user who moderates the questions with the tag
+type TagModerator struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt time.Time `xorm:"updated TIMESTAMP updated_at"`
+	TagID     string    `xorm:"not null default 0 BIGINT(20) UNIQUE(tag_user) tag_id"`
+	UserID    string    `xorm:"not null default 0 BIGINT(20) UNIQUE(tag_user) INDEX user_id"`
+	// Source manual, auto or removed, the manual and the removed ones are never replaced by the automatic assignment
+	Source string `xorm:"not null default '' VARCHAR(20) source"`
+	// OperatorID the admin who assigns or removes the moderator, 0 if it is assigned automatically
+	OperatorID string `xorm:"not null default 0 BIGINT(20) operator_id"`
+}
+
+// TableName tag moderator table name
+func (TagModerator) TableName() string {
+	return "tag_moderator"
+}
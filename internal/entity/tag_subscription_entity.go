@@ -0,0 +1,83 @@
+package entity
+
+import "time"
+
+const (
+	// TagSubscriptionFrequencyInstant the alert is sent once the question is posted, unless it is in the quiet hours
+	TagSubscriptionFrequencyInstant = "instant"
+	// TagSubscriptionFrequencyDaily the alerts are batched and sent once a day
+	TagSubscriptionFrequencyDaily = "daily"
+)
+
+const (
+	TagAlertStatusPending = 1
+	TagAlertStatusSent    = 2
+)
+
+// TagSubscription the tag expression which the user watches, such as "go AND NOT beginner"
+type TagSubscription struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt time.Time `xorm:"updated TIMESTAMP updated_at"`
+	UserID    string    `xorm:"not null default 0 BIGINT(20) INDEX user_id"`
+	// Expression the normalized tag expression
+	Expression string `xorm:"not null default '' VARCHAR(500) expression"`
+	Frequency  string `xorm:"not null default '' VARCHAR(20) frequency"`
+}
+
+// TableName tag subscription table name
+func (TagSubscription) TableName() string {
+	return "tag_subscription"
+}
+
+// TagSubscriptionTag the tag which is not negated in the expression of the subscription. The question matches the
+// expression only if it has at least one of them, so the candidate subscriptions are found by the tags.
+type TagSubscriptionTag struct {
+	ID             string `xorm:"not null pk autoincr BIGINT(20) id"`
+	SubscriptionID string `xorm:"not null default 0 BIGINT(20) UNIQUE(subscription_tag) subscription_id"`
+	TagName        string `xorm:"not null default '' VARCHAR(35) UNIQUE(subscription_tag) INDEX tag_name"`
+}
+
+// TableName tag subscription tag table name
+func (TagSubscriptionTag) TableName() string {
+	return "tag_subscription_tag"
+}
+
+// TagAlert the new question which matches the subscriptions of the user, the user is alerted once for each question
+type TagAlert struct {
+	ID         string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt  time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt  time.Time `xorm:"updated TIMESTAMP updated_at"`
+	UserID     string    `xorm:"not null default 0 BIGINT(20) UNIQUE(user_question) user_id"`
+	QuestionID string    `xorm:"not null default 0 BIGINT(20) UNIQUE(user_question) question_id"`
+	// Expression the matched expression of the subscription
+	Expression string `xorm:"not null default '' VARCHAR(500) expression"`
+	Frequency  string `xorm:"not null default '' VARCHAR(20) frequency"`
+	Status     int    `xorm:"not null default 1 INT(11) INDEX status"`
+}
+
+// TableName tag alert table name
+func (TagAlert) TableName() string {
+	return "tag_alert"
+}
+
+// TagAlertSetting the time zone and the quiet hours of the user, no alert is sent in the quiet hours
+type TagAlertSetting struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt time.Time `xorm:"updated TIMESTAMP updated_at"`
+	UserID    string    `xorm:"not null default 0 BIGINT(20) UNIQUE user_id"`
+	// TimeZone the IANA time zone name, such as Asia/Shanghai, UTC if it is empty
+	TimeZone string `xorm:"not null default '' VARCHAR(64) time_zone"`
+	// QuietHoursStart QuietHoursEnd the quiet hours from the start hour to the end hour in the time zone,
+	// which may cross the midnight, no quiet hours if they are equal
+	QuietHoursStart int `xorm:"not null default 0 INT(11) quiet_hours_start"`
+	QuietHoursEnd   int `xorm:"not null default 0 INT(11) quiet_hours_end"`
+	// LastDigestAt the time when the last batched alerts are sent
+	LastDigestAt time.Time `xorm:"TIMESTAMP last_digest_at"`
+}
+
+// TableName tag alert setting table name
+func (TagAlertSetting) TableName() string {
+	return "tag_alert_setting"
+}
@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// TagTemplate the question template of the tag, the questions with the tag are asked by filling it
+type TagTemplate struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt time.Time `xorm:"updated TIMESTAMP updated_at"`
+	TagID     string    `xorm:"not null default 0 BIGINT(20) UNIQUE tag_id"`
+	// UserID the user who edits the template last
+	UserID  string `xorm:"not null default 0 BIGINT(20) user_id"`
+	Content string `xorm:"not null MEDIUMTEXT content"`
+	// RequiredSections the headings of the sections which can not be left empty in the question
+	RequiredSections []string `xorm:"TEXT required_sections"`
+}
+
+// TableName tag template table name
+func (TagTemplate) TableName() string {
+	return "tag_template"
+}
@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+const (
+	UserDataExportStatusPending   = 1
+	UserDataExportStatusCompleted = 2
+	UserDataExportStatusFailed    = 3
+)
+
+// UserDataExport the export of all the data of the user, only the latest export of the user is kept
+type UserDataExport struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt time.Time `xorm:"updated TIMESTAMP updated_at"`
+	UserID    string    `xorm:"not null default 0 BIGINT(20) UNIQUE user_id"`
+	Status    int       `xorm:"not null default 1 INT(11) INDEX status"`
+	// FileName the name of the zip file in the export directory, empty until the export is completed
+	FileName  string    `xorm:"not null default '' VARCHAR(100) file_name"`
+	ExpiredAt time.Time `xorm:"TIMESTAMP expired_at"`
+}
+
+// TableName user data export table name
+func (UserDataExport) TableName() string {
+	return "user_data_export"
+}
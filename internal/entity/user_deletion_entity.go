@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+const (
+	UserDeletionStatusPending   = 1
+	UserDeletionStatusCompleted = 2
+	UserDeletionStatusCancelled = 10
+)
+
+const (
+	UserDeletionModeAnonymize = 1
+	UserDeletionModeRemove    = 2
+)
+
+// UserDeletion the account deletion requested by user, it will be executed after the grace period
+type UserDeletion struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt time.Time `xorm:"updated TIMESTAMP updated_at"`
+	UserID    string    `xorm:"not null default 0 BIGINT(20) INDEX user_id"`
+	Mode      int       `xorm:"not null default 1 INT(11) mode"`
+	ExecuteAt time.Time `xorm:"TIMESTAMP INDEX execute_at"`
+	Status    int       `xorm:"not null default 1 INT(11) status"`
+}
+
+// TableName user deletion table name
+func (UserDeletion) TableName() string {
+	return "user_deletion"
+}
@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+const (
+	UserInviteStatusAvailable = 1
+	UserInviteStatusUsed      = 2
+	UserInviteStatusDeleted   = 10
+)
+
+// UserInvite the invite link issued by admin for registration
+type UserInvite struct {
+	ID            string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt     time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt     time.Time `xorm:"updated TIMESTAMP updated_at"`
+	Code          string    `xorm:"not null default '' VARCHAR(64) UNIQUE code"`
+	Email         string    `xorm:"not null default '' VARCHAR(100) email"`
+	CreatorUserID string    `xorm:"not null default 0 BIGINT(20) creator_user_id"`
+	UsedUserID    string    `xorm:"not null default 0 BIGINT(20) used_user_id"`
+	ExpiredAt     time.Time `xorm:"TIMESTAMP expired_at"`
+	Status        int       `xorm:"not null default 1 INT(11) status"`
+}
+
+// TableName user invite table name
+func (UserInvite) TableName() string {
+	return "user_invite"
+}
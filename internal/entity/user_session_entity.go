@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// UserSession the login session of user, one access token one session
+type UserSession struct {
+	ID         string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt  time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt  time.Time `xorm:"updated TIMESTAMP updated_at"`
+	UserID     string    `xorm:"not null default 0 BIGINT(20) INDEX user_id"`
+	Device     string    `xorm:"not null default '' VARCHAR(100) device"`
+	IP         string    `xorm:"not null default '' VARCHAR(64) ip"`
+	UserAgent  string    `xorm:"not null default '' VARCHAR(512) user_agent"`
+	LastSeenAt time.Time `xorm:"TIMESTAMP last_seen_at"`
+}
+
+// TableName user session table name
+func (UserSession) TableName() string {
+	return "user_session"
+}
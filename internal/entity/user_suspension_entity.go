@@ -0,0 +1,53 @@
+package entity
+
+import "time"
+
+const (
+	UserSuspensionStatusActive = 1
+	// UserSuspensionStatusLifted the suspension is lifted by admin before it ends
+	UserSuspensionStatusLifted = 2
+	// UserSuspensionStatusExpired the suspension period ended, the user is reinstated automatically
+	UserSuspensionStatusExpired = 3
+)
+
+const (
+	UserBanTypeIP          = "ip"
+	UserBanTypeEmailDomain = "email_domain"
+)
+
+// UserSuspension the suspension of the user, the history of the suspensions is kept for escalation
+type UserSuspension struct {
+	ID         string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt  time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt  time.Time `xorm:"updated TIMESTAMP updated_at"`
+	UserID     string    `xorm:"not null default 0 BIGINT(20) INDEX user_id"`
+	OperatorID string    `xorm:"not null default 0 BIGINT(20) operator_id"`
+	Reason     string    `xorm:"not null default '' VARCHAR(32) reason"`
+	// Message the message shown to the suspended user
+	Message string `xorm:"not null TEXT message"`
+	// Days the duration of the suspension, 0 means permanent
+	Days      int       `xorm:"not null default 0 INT(11) days"`
+	ExpiredAt time.Time `xorm:"TIMESTAMP INDEX expired_at"`
+	Status    int       `xorm:"not null default 1 INT(11) status"`
+}
+
+// TableName user suspension table name
+func (UserSuspension) TableName() string {
+	return "user_suspension"
+}
+
+// UserBan the ip or email domain which is not allowed to register
+type UserBan struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
+	BanType   string    `xorm:"not null default '' VARCHAR(16) UNIQUE(ban) ban_type"`
+	Value     string    `xorm:"not null default '' VARCHAR(255) UNIQUE(ban) value"`
+	// UserID the user whose suspension caused the ban, 0 if the ban is added by admin directly
+	UserID     string `xorm:"not null default 0 BIGINT(20) user_id"`
+	OperatorID string `xorm:"not null default 0 BIGINT(20) operator_id"`
+}
+
+// TableName user ban table name
+func (UserBan) TableName() string {
+	return "user_ban"
+}
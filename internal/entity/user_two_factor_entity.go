@@ -0,0 +1,40 @@
+package entity
+
+import "time"
+
+// UserTwoFactor the two-factor authentication of user
+type UserTwoFactor struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
+	UpdatedAt time.Time `xorm:"updated TIMESTAMP updated_at"`
+	UserID    string    `xorm:"not null default 0 BIGINT(20) UNIQUE user_id"`
+	// Secret the base32 encoded totp secret
+	Secret string `xorm:"not null default '' VARCHAR(64) secret"`
+	// Enabled the secret is confirmed by user with a valid code, before that it is only an enrollment
+	Enabled bool `xorm:"not null default false BOOL enabled"`
+	// RecoveryCodes the json array of sha256 hashed recovery codes, the used one will be removed
+	RecoveryCodes string `xorm:"not null TEXT recovery_codes"`
+	// LastUsedStep the time step of the last accepted code, the code of it or before is not accepted again
+	LastUsedStep int64 `xorm:"not null default 0 BIGINT(20) last_used_step"`
+}
+
+// TableName user two factor table name
+func (UserTwoFactor) TableName() string {
+	return "user_two_factor"
+}
+
+// UserTwoFactorPending the pending login which is waiting for the two factor code
+type UserTwoFactorPending struct {
+	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
+	Token     string    `xorm:"not null default '' VARCHAR(64) UNIQUE token"`
+	UserID    string    `xorm:"not null default 0 BIGINT(20) user_id"`
+	// Attempts the number of the codes tried, the token is discarded once it reaches the max attempts
+	Attempts  int       `xorm:"not null default 0 INT(11) attempts"`
+	ExpiredAt time.Time `xorm:"TIMESTAMP INDEX expired_at"`
+}
+
+// TableName user two factor pending table name
+func (UserTwoFactorPending) TableName() string {
+	return "user_two_factor_pending"
+}
@@ -28,7 +28,7 @@ import (
 // @Success 200 {object} handler.RespBody{data=[]translator.LangOption}
 // @Router /installation/language/options [get]
 func LangOptions(ctx *gin.Context) {
-	handler.HandleResponse(ctx, nil, translator.LanguageOptions)
+	handler.HandleResponse(ctx, nil, translator.GetLanguageOptions())
 }
 
 // CheckConfigFile check config file if exist when installation
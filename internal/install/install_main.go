@@ -16,7 +16,7 @@ var (
 func Run(configPath string) {
 	confPath = configPath
 	// initialize translator for return internationalization error when installing.
-	_, err := translator.NewTranslator(&translator.I18n{BundleDir: cli.I18nPath})
+	_, err := translator.LoadTranslator(&translator.I18n{BundleDir: cli.I18nPath})
 	if err != nil {
 		panic(err)
 	}
@@ -14,20 +14,43 @@ import (
 var tables = []interface{}{
 	&entity.Activity{},
 	&entity.Answer{},
+	&entity.AnswerAcceptLog{},
 	&entity.Collection{},
 	&entity.CollectionGroup{},
 	&entity.Comment{},
 	&entity.Config{},
+	&entity.Draft{},
+	&entity.FeedDigest{},
 	&entity.Meta{},
+	&entity.ModerationResult{},
+	&entity.ModeratorMessage{},
 	&entity.Notification{},
 	&entity.Question{},
+	&entity.QuestionCloseVote{},
 	&entity.Report{},
+	&entity.Review{},
+	&entity.ReviewSkip{},
 	&entity.Revision{},
 	&entity.SiteInfo{},
 	&entity.Tag{},
+	&entity.TagAlert{},
+	&entity.TagAlertSetting{},
+	&entity.TagIgnore{},
+	&entity.TagModerator{},
 	&entity.TagRel{},
+	&entity.TagSubscription{},
+	&entity.TagSubscriptionTag{},
+	&entity.TagTemplate{},
 	&entity.Uniqid{},
 	&entity.User{},
+	&entity.UserBan{},
+	&entity.UserDataExport{},
+	&entity.UserDeletion{},
+	&entity.UserInvite{},
+	&entity.UserSession{},
+	&entity.UserSuspension{},
+	&entity.UserTwoFactor{},
+	&entity.UserTwoFactorPending{},
 	&entity.Version{},
 }
 
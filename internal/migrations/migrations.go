@@ -46,6 +46,31 @@ var migrations = []Migration{
 	NewMigration("add user language", addUserLanguage),
 	NewMigration("add recommend and reserved tag fields", addTagRecommendedAndReserved),
 	NewMigration("add activity timeline", addActivityTimeline),
+	NewMigration("add user invite", addUserInvite),
+	NewMigration("add user deletion", addUserDeletion),
+	NewMigration("add user two factor", addUserTwoFactor),
+	NewMigration("add user session", addUserSession),
+	NewMigration("add question hot score", addQuestionHotScore),
+	NewMigration("add content language", addContentLanguage),
+	NewMigration("add review queue", addReviewQueue),
+	NewMigration("add question close vote", addQuestionCloseVote),
+	NewMigration("add moderation result", addModerationResult),
+	NewMigration("add user suspension and ban", addUserSuspension),
+	NewMigration("add moderator message", addModeratorMessage),
+	NewMigration("add draft", addDraft),
+	NewMigration("add collection group sharing and note", addCollectionGroupSharing),
+	NewMigration("add feed digest", addFeedDigest),
+	NewMigration("add answer accept log", addAnswerAcceptLog),
+	NewMigration("add community wiki", addCommunityWiki),
+	NewMigration("add comment thread", addCommentThread),
+	NewMigration("add tag template", addTagTemplate),
+	NewMigration("add tag moderator", addTagModerator),
+	NewMigration("add tag subscription", addTagSubscription),
+	NewMigration("add user two factor replay protection", addUserTwoFactorPending),
+	NewMigration("add question close vote unique index", addQuestionCloseVoteUniqueIndex),
+	NewMigration("add answer converted activity", addAnswerConvertedActivity),
+	NewMigration("add tag subscription tag", addTagSubscriptionTag),
+	NewMigration("add user data export", addUserDataExport),
 }
 
 // GetCurrentDBVersion returns the current db version
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"fmt"
+
+	"answer/internal/entity"
+
+	"xorm.io/xorm"
+)
+
+func addReviewQueue(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.Review), new(entity.ReviewSkip)); err != nil {
+		return fmt.Errorf("sync review table failed: %w", err)
+	}
+
+	defaultConfigTable := []*entity.Config{
+		{ID: 115, Key: "review.completed", Value: `1`},
+	}
+	for _, c := range defaultConfigTable {
+		exist, err := x.Get(&entity.Config{ID: c.ID, Key: c.Key})
+		if err != nil {
+			return fmt.Errorf("get config failed: %w", err)
+		}
+		if exist {
+			continue
+		}
+		if _, err = x.Insert(&entity.Config{ID: c.ID, Key: c.Key, Value: c.Value}); err != nil {
+			return fmt.Errorf("add config failed: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"fmt"
+
+	"answer/internal/entity"
+
+	"xorm.io/xorm"
+)
+
+func addQuestionCloseVote(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.QuestionCloseVote)); err != nil {
+		return fmt.Errorf("sync question close vote table failed: %w", err)
+	}
+
+	defaultConfigTable := []*entity.Config{
+		{ID: 116, Key: "rank.question.close_vote", Value: `3000`},
+		{ID: 117, Key: "rank.question.reopen_vote", Value: `3000`},
+		{ID: 118, Key: "question.close_voted", Value: `0`},
+		{ID: 119, Key: "question.reopen_voted", Value: `0`},
+	}
+	for _, c := range defaultConfigTable {
+		exist, err := x.Get(&entity.Config{ID: c.ID, Key: c.Key})
+		if err != nil {
+			return fmt.Errorf("get config failed: %w", err)
+		}
+		if exist {
+			continue
+		}
+		if _, err = x.Insert(&entity.Config{ID: c.ID, Key: c.Key, Value: c.Value}); err != nil {
+			return fmt.Errorf("add config failed: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package migrations
+
+import (
+	"fmt"
+
+	"answer/internal/entity"
+
+	"xorm.io/xorm"
+)
+
+func addModerationResult(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.ModerationResult)); err != nil {
+		return fmt.Errorf("sync moderation result table failed: %w", err)
+	}
+	return nil
+}
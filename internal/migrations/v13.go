@@ -0,0 +1,16 @@
+package migrations
+
+import (
+	"fmt"
+
+	"answer/internal/entity"
+
+	"xorm.io/xorm"
+)
+
+func addUserSuspension(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.UserSuspension), new(entity.UserBan)); err != nil {
+		return fmt.Errorf("sync user suspension table failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package migrations
+
+import (
+	"fmt"
+
+	"answer/internal/entity"
+
+	"xorm.io/xorm"
+)
+
+func addModeratorMessage(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.ModeratorMessage)); err != nil {
+		return fmt.Errorf("sync moderator message table failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package migrations
+
+import (
+	"fmt"
+
+	"answer/internal/entity"
+
+	"xorm.io/xorm"
+)
+
+func addDraft(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.Draft)); err != nil {
+		return fmt.Errorf("sync draft table failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package migrations
+
+import (
+	"fmt"
+
+	"answer/internal/entity"
+
+	"xorm.io/xorm"
+)
+
+func addCollectionGroupSharing(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.CollectionGroup), new(entity.Collection)); err != nil {
+		return fmt.Errorf("sync collection group table failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package migrations
+
+import (
+	"fmt"
+
+	"answer/internal/entity"
+
+	"xorm.io/xorm"
+)
+
+func addFeedDigest(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.FeedDigest)); err != nil {
+		return fmt.Errorf("sync feed digest table failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package migrations
+
+import (
+	"fmt"
+
+	"answer/internal/entity"
+
+	"xorm.io/xorm"
+)
+
+func addAnswerAcceptLog(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.AnswerAcceptLog)); err != nil {
+		return fmt.Errorf("sync answer accept log table failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"fmt"
+
+	"answer/internal/entity"
+
+	"xorm.io/xorm"
+)
+
+func addCommunityWiki(x *xorm.Engine) error {
+	type Question struct {
+		ID            string `xorm:"not null pk BIGINT(20) id"`
+		CommunityWiki bool   `xorm:"not null default false BOOL community_wiki"`
+	}
+	type Answer struct {
+		ID            string `xorm:"not null pk autoincr BIGINT(20) id"`
+		CommunityWiki bool   `xorm:"not null default false BOOL community_wiki"`
+	}
+	if err := x.Sync(new(Question), new(Answer)); err != nil {
+		return fmt.Errorf("sync community wiki column failed: %w", err)
+	}
+
+	// the users whose reputation reaches it can edit the community wiki without review
+	defaultConfigTable := []*entity.Config{
+		{ID: 120, Key: "rank.question.edit_wiki", Value: `100`},
+		{ID: 121, Key: "rank.answer.edit_wiki", Value: `100`},
+	}
+	for _, c := range defaultConfigTable {
+		exist, err := x.Get(&entity.Config{ID: c.ID, Key: c.Key})
+		if err != nil {
+			return fmt.Errorf("get config failed: %w", err)
+		}
+		if exist {
+			continue
+		}
+		if _, err = x.Insert(&entity.Config{ID: c.ID, Key: c.Key, Value: c.Value}); err != nil {
+			return fmt.Errorf("add config failed: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+
+	"answer/internal/base/constant"
+
+	"xorm.io/xorm"
+)
+
+func addCommentThread(x *xorm.Engine) error {
+	type Comment struct {
+		ID              string        `xorm:"not null pk autoincr BIGINT(20) id"`
+		ReplyCommentID  sql.NullInt64 `xorm:"BIGINT(20) reply_comment_id"`
+		ParentCommentID string        `xorm:"not null default 0 BIGINT(20) INDEX parent_comment_id"`
+		Depth           int           `xorm:"not null default 0 INT(11) depth"`
+	}
+	if err := x.Sync(new(Comment)); err != nil {
+		return fmt.Errorf("sync comment thread column failed: %w", err)
+	}
+
+	// the replies are nested under the replied comments, the replied one is always added before the reply
+	replies := make([]*Comment, 0)
+	if err := x.Where("reply_comment_id IS NOT NULL").Asc("id").Find(&replies); err != nil {
+		return fmt.Errorf("get comment replies failed: %w", err)
+	}
+	nested := make(map[string]*Comment, len(replies))
+	for _, reply := range replies {
+		repliedID := fmt.Sprintf("%d", reply.ReplyCommentID.Int64)
+		reply.ParentCommentID, reply.Depth = repliedID, 1
+		if replied, ok := nested[repliedID]; ok {
+			reply.Depth = replied.Depth + 1
+			if reply.Depth >= constant.CommentMaxDepth {
+				reply.ParentCommentID, reply.Depth = replied.ParentCommentID, replied.Depth
+			}
+		}
+		nested[reply.ID] = reply
+		_, err := x.ID(reply.ID).Cols("parent_comment_id", "depth").Update(reply)
+		if err != nil {
+			return fmt.Errorf("update comment thread failed: %w", err)
+		}
+	}
+	return nil
+}
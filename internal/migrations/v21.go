@@ -0,0 +1,16 @@
+package migrations
+
+import (
+	"fmt"
+
+	"answer/internal/entity"
+
+	"xorm.io/xorm"
+)
+
+func addTagTemplate(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.TagTemplate)); err != nil {
+		return fmt.Errorf("sync tag template table failed: %w", err)
+	}
+	return nil
+}
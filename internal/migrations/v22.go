@@ -0,0 +1,16 @@
+package migrations
+
+import (
+	"fmt"
+
+	"answer/internal/entity"
+
+	"xorm.io/xorm"
+)
+
+func addTagModerator(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.TagModerator)); err != nil {
+		return fmt.Errorf("sync tag moderator table failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package migrations
+
+import (
+	"fmt"
+
+	"answer/internal/entity"
+
+	"xorm.io/xorm"
+)
+
+func addTagSubscription(x *xorm.Engine) error {
+	err := x.Sync(new(entity.TagSubscription), new(entity.TagAlert), new(entity.TagAlertSetting), new(entity.TagIgnore))
+	if err != nil {
+		return fmt.Errorf("sync tag subscription tables failed: %w", err)
+	}
+	return nil
+}
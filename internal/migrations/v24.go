@@ -0,0 +1,17 @@
+package migrations
+
+import (
+	"fmt"
+
+	"answer/internal/entity"
+
+	"xorm.io/xorm"
+)
+
+func addUserTwoFactorPending(x *xorm.Engine) error {
+	err := x.Sync(new(entity.UserTwoFactor), new(entity.UserTwoFactorPending))
+	if err != nil {
+		return fmt.Errorf("sync user two factor tables failed: %w", err)
+	}
+	return nil
+}
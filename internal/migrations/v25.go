@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"fmt"
+
+	"answer/internal/entity"
+
+	"xorm.io/xorm"
+)
+
+func addQuestionCloseVoteUniqueIndex(x *xorm.Engine) error {
+	// keep the latest vote of each type of the user on the question, then the unique index can be added
+	_, err := x.Exec("DELETE FROM question_close_vote WHERE id NOT IN " +
+		"(SELECT id FROM (SELECT MAX(id) AS id FROM question_close_vote GROUP BY question_id, user_id, vote_type) latest)")
+	if err != nil {
+		return fmt.Errorf("remove duplicate question close votes failed: %w", err)
+	}
+	err = x.Sync(new(entity.QuestionCloseVote))
+	if err != nil {
+		return fmt.Errorf("sync question close vote table failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"fmt"
+
+	"answer/internal/entity"
+
+	"xorm.io/xorm"
+)
+
+func addAnswerConvertedActivity(x *xorm.Engine) error {
+	// the moderator converts the comment to the answer, it is shown in the timeline of the answer
+	c := &entity.Config{ID: 122, Key: "answer.converted", Value: `0`}
+	exist, err := x.Get(&entity.Config{ID: c.ID, Key: c.Key})
+	if err != nil {
+		return fmt.Errorf("get config failed: %w", err)
+	}
+	if exist {
+		return nil
+	}
+	if _, err = x.Insert(c); err != nil {
+		return fmt.Errorf("add config failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"fmt"
+	"strings"
+
+	"answer/internal/entity"
+
+	"xorm.io/xorm"
+)
+
+func addTagSubscriptionTag(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.TagSubscriptionTag)); err != nil {
+		return fmt.Errorf("sync tag subscription tag table failed: %w", err)
+	}
+
+	// the tags of the subscription were joined as "|go|rust|" in the tags column
+	type TagSubscription struct {
+		ID   string `xorm:"not null pk autoincr BIGINT(20) id"`
+		Tags string `xorm:"not null default '' VARCHAR(1000) tags"`
+	}
+	subscriptions := make([]*TagSubscription, 0)
+	if err := x.Table("tag_subscription").Find(&subscriptions); err != nil {
+		return fmt.Errorf("get tag subscriptions failed: %w", err)
+	}
+	for _, subscription := range subscriptions {
+		for _, tagName := range strings.Split(strings.Trim(subscription.Tags, "|"), "|") {
+			if len(tagName) == 0 {
+				continue
+			}
+			exist, err := x.Exist(&entity.TagSubscriptionTag{SubscriptionID: subscription.ID, TagName: tagName})
+			if err != nil {
+				return fmt.Errorf("get tag subscription tag failed: %w", err)
+			}
+			if exist {
+				continue
+			}
+			_, err = x.Insert(&entity.TagSubscriptionTag{SubscriptionID: subscription.ID, TagName: tagName})
+			if err != nil {
+				return fmt.Errorf("add tag subscription tag failed: %w", err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package migrations
+
+import (
+	"fmt"
+
+	"answer/internal/entity"
+
+	"xorm.io/xorm"
+)
+
+func addUserDataExport(x *xorm.Engine) error {
+	if err := x.Sync(new(entity.UserDataExport)); err != nil {
+		return fmt.Errorf("sync user data export table failed: %w", err)
+	}
+	return nil
+}
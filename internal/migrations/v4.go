@@ -0,0 +1,11 @@
+package migrations
+
+import (
+	"answer/internal/entity"
+
+	"xorm.io/xorm"
+)
+
+func addUserInvite(x *xorm.Engine) error {
+	return x.Sync(new(entity.UserInvite))
+}
@@ -0,0 +1,11 @@
+package migrations
+
+import (
+	"answer/internal/entity"
+
+	"xorm.io/xorm"
+)
+
+func addUserSession(x *xorm.Engine) error {
+	return x.Sync(new(entity.UserSession))
+}
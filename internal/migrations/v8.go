@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"time"
+
+	"answer/pkg/hotscore"
+
+	"xorm.io/xorm"
+)
+
+func addQuestionHotScore(x *xorm.Engine) error {
+	type Question struct {
+		ID             string    `xorm:"not null pk BIGINT(20) id"`
+		CreatedAt      time.Time `xorm:"not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
+		ViewCount      int       `xorm:"not null default 0 INT(11) view_count"`
+		VoteCount      int       `xorm:"not null default 0 INT(11) vote_count"`
+		AnswerCount    int       `xorm:"not null default 0 INT(11) answer_count"`
+		PostUpdateTime time.Time `xorm:"post_update_time TIMESTAMP"`
+		HotScore       float64   `xorm:"not null default 0 DOUBLE INDEX hot_score"`
+	}
+	if err := x.Sync(new(Question)); err != nil {
+		return err
+	}
+
+	// calculate the hot score of the existing questions
+	const pageSize = 1000
+	for page := 0; ; page++ {
+		questions := make([]*Question, 0, pageSize)
+		err := x.Cols("id", "created_at", "view_count", "vote_count", "answer_count", "post_update_time").
+			Asc("id").Limit(pageSize, page*pageSize).Find(&questions)
+		if err != nil {
+			return err
+		}
+		for _, question := range questions {
+			question.HotScore = hotscore.Score(question.VoteCount, question.AnswerCount, question.ViewCount,
+				question.CreatedAt, question.PostUpdateTime)
+			if _, err = x.ID(question.ID).Cols("hot_score").Update(question); err != nil {
+				return err
+			}
+		}
+		if len(questions) < pageSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package migrations
+
+import (
+	"answer/pkg/langdetect"
+
+	"xorm.io/xorm"
+)
+
+func addContentLanguage(x *xorm.Engine) error {
+	type Question struct {
+		ID           string `xorm:"not null pk BIGINT(20) id"`
+		Title        string `xorm:"not null default '' VARCHAR(150) title"`
+		OriginalText string `xorm:"not null MEDIUMTEXT original_text"`
+		Language     string `xorm:"not null default '' VARCHAR(16) INDEX language"`
+	}
+	type Answer struct {
+		ID           string `xorm:"not null pk autoincr BIGINT(20) id"`
+		OriginalText string `xorm:"not null MEDIUMTEXT original_text"`
+		Language     string `xorm:"not null default '' VARCHAR(16) language"`
+	}
+	type User struct {
+		ID               string `xorm:"not null pk autoincr BIGINT(20) id"`
+		ContentLanguages string `xorm:"not null default '' VARCHAR(100) content_languages"`
+	}
+	if err := x.Sync(new(Question), new(Answer), new(User)); err != nil {
+		return err
+	}
+
+	// detect the language of the existing questions and answers
+	const pageSize = 1000
+	for page := 0; ; page++ {
+		questions := make([]*Question, 0, pageSize)
+		err := x.Cols("id", "title", "original_text").Asc("id").Limit(pageSize, page*pageSize).Find(&questions)
+		if err != nil {
+			return err
+		}
+		for _, question := range questions {
+			question.Language = langdetect.Detect(question.Title + "\n" + question.OriginalText)
+			if _, err = x.ID(question.ID).Cols("language").Update(question); err != nil {
+				return err
+			}
+		}
+		if len(questions) < pageSize {
+			break
+		}
+	}
+	for page := 0; ; page++ {
+		answers := make([]*Answer, 0, pageSize)
+		err := x.Cols("id", "original_text").Asc("id").Limit(pageSize, page*pageSize).Find(&answers)
+		if err != nil {
+			return err
+		}
+		for _, answer := range answers {
+			answer.Language = langdetect.Detect(answer.OriginalText)
+			if _, err = x.ID(answer.ID).Cols("language").Update(answer); err != nil {
+				return err
+			}
+		}
+		if len(answers) < pageSize {
+			return nil
+		}
+	}
+}
@@ -157,12 +157,14 @@ func (ar *AnswerActivityRepo) AcceptAnswer(ctx context.Context,
 		addActivityList = append(addActivityList, addActivity)
 	}
 
-	_, err = ar.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+	// the activities join the transaction of the caller which changes the accepted answers
+	err = ar.data.Transaction(ctx, func(ctx context.Context) error {
+		session := ar.data.Session(ctx)
 		for _, addActivity := range addActivityList {
 			existsActivity, exists, e := ar.activityRepo.GetActivity(
 				ctx, session, answerObjID, addActivity.UserID, addActivity.ActivityType)
 			if e != nil {
-				return nil, errors.InternalServer(reason.DatabaseError).WithError(e).WithStack()
+				return errors.InternalServer(reason.DatabaseError).WithError(e).WithStack()
 			}
 			if exists && existsActivity.Cancelled == entity.ActivityAvailable {
 				continue
@@ -171,7 +173,7 @@ func (ar *AnswerActivityRepo) AcceptAnswer(ctx context.Context,
 			reachStandard, e := ar.userRankRepo.TriggerUserRank(
 				ctx, session, addActivity.UserID, addActivity.Rank, addActivity.ActivityType)
 			if e != nil {
-				return nil, errors.InternalServer(reason.DatabaseError).WithError(e).WithStack()
+				return errors.InternalServer(reason.DatabaseError).WithError(e).WithStack()
 			}
 			if reachStandard {
 				addActivity.Rank = 0
@@ -180,15 +182,15 @@ func (ar *AnswerActivityRepo) AcceptAnswer(ctx context.Context,
 			if exists {
 				if _, e = session.Where("id = ?", existsActivity.ID).Cols("`cancelled`").
 					Update(&entity.Activity{Cancelled: entity.ActivityAvailable}); e != nil {
-					return nil, errors.InternalServer(reason.DatabaseError).WithError(e).WithStack()
+					return errors.InternalServer(reason.DatabaseError).WithError(e).WithStack()
 				}
 			} else {
 				if _, e = session.Insert(addActivity); e != nil {
-					return nil, errors.InternalServer(reason.DatabaseError).WithError(e).WithStack()
+					return errors.InternalServer(reason.DatabaseError).WithError(e).WithStack()
 				}
 			}
 		}
-		return nil, nil
+		return nil
 	})
 	if err != nil {
 		return err
@@ -252,12 +254,14 @@ func (ar *AnswerActivityRepo) CancelAcceptAnswer(ctx context.Context,
 		addActivityList = append(addActivityList, addActivity)
 	}
 
-	_, err = ar.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+	// the activities join the transaction of the caller which changes the accepted answers
+	err = ar.data.Transaction(ctx, func(ctx context.Context) error {
+		session := ar.data.Session(ctx)
 		for _, addActivity := range addActivityList {
 			existsActivity, exists, e := ar.activityRepo.GetActivity(
 				ctx, session, answerObjID, addActivity.UserID, addActivity.ActivityType)
 			if e != nil {
-				return nil, errors.InternalServer(reason.DatabaseError).WithError(e).WithStack()
+				return errors.InternalServer(reason.DatabaseError).WithError(e).WithStack()
 			}
 			if exists && existsActivity.Cancelled == entity.ActivityCancelled {
 				continue
@@ -269,15 +273,15 @@ func (ar *AnswerActivityRepo) CancelAcceptAnswer(ctx context.Context,
 			_, e = ar.userRankRepo.TriggerUserRank(
 				ctx, session, addActivity.UserID, addActivity.Rank, addActivity.ActivityType)
 			if e != nil {
-				return nil, errors.InternalServer(reason.DatabaseError).WithError(e).WithStack()
+				return errors.InternalServer(reason.DatabaseError).WithError(e).WithStack()
 			}
 
 			if _, e := session.Where("id = ?", existsActivity.ID).Cols("cancelled", "cancelled_at").
 				Update(&entity.Activity{Cancelled: entity.ActivityCancelled, CancelledAt: time.Now()}); e != nil {
-				return nil, errors.InternalServer(reason.DatabaseError).WithError(e).WithStack()
+				return errors.InternalServer(reason.DatabaseError).WithError(e).WithStack()
 			}
 		}
-		return nil, nil
+		return nil
 	})
 	if err != nil {
 		return err
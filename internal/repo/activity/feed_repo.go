@@ -0,0 +1,134 @@
+package activity
+
+import (
+	"context"
+
+	"answer/internal/base/constant"
+	"answer/internal/base/data"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/service/activity_common"
+	"answer/internal/service/feed"
+	"answer/pkg/converter"
+
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+)
+
+// FeedRepo feed repository
+type FeedRepo struct {
+	data         *data.Data
+	activityRepo activity_common.ActivityRepo
+}
+
+// NewFeedRepo new repository
+func NewFeedRepo(
+	data *data.Data,
+	activityRepo activity_common.ActivityRepo,
+) feed.FeedRepo {
+	return &FeedRepo{
+		data:         data,
+		activityRepo: activityRepo,
+	}
+}
+
+// GetFeedActivities get the activities about the followed objects, the newest first.
+// The new questions in the followed tags, the new answers and edits on the followed questions
+// and all the new posts and edits by the followed users are included.
+func (fr *FeedRepo) GetFeedActivities(ctx context.Context, search *entity.FeedSearch) (
+	activities []*entity.Activity, err error) {
+	activities = make([]*entity.Activity, 0)
+	types, err := fr.getActivityTypes(ctx, constant.ActQuestionAsked, constant.ActQuestionAnswered,
+		constant.ActQuestionEdited, constant.ActAnswerEdited)
+	if err != nil {
+		return nil, err
+	}
+	asked, answered, questionEdited, answerEdited := types[0], types[1], types[2], types[3]
+
+	followed := builder.NewCond()
+	if len(search.FollowedUserIDs) > 0 {
+		followed = followed.Or(builder.In("user_id", search.FollowedUserIDs))
+	}
+	if len(search.FollowedTagIDs) > 0 {
+		followed = followed.Or(builder.Eq{"activity_type": asked}.And(
+			builder.In("object_id", builder.Select("object_id").From(entity.TagRel{}.TableName()).
+				Where(builder.In("tag_id", search.FollowedTagIDs).
+					And(builder.Eq{"status": entity.TagRelStatusAvailable})))))
+	}
+	if len(search.FollowedQuestionIDs) > 0 {
+		followed = followed.Or(
+			builder.Eq{"activity_type": answered}.And(builder.In("original_object_id", search.FollowedQuestionIDs)),
+			builder.Eq{"activity_type": questionEdited}.And(builder.In("object_id", search.FollowedQuestionIDs)),
+			builder.Eq{"activity_type": answerEdited}.And(
+				builder.In("object_id", builder.Select("id").From(entity.Answer{}.TableName()).
+					Where(builder.In("question_id", search.FollowedQuestionIDs)))),
+		)
+	}
+	if !followed.IsValid() {
+		return activities, nil
+	}
+
+	session := fr.data.DB.Where(builder.In("activity_type", types)).
+		And("cancelled = ?", entity.ActivityAvailable).
+		And("user_id <> ?", search.UserID).
+		And(followed)
+	if len(search.Cursor) > 0 {
+		session.And("id < ?", converter.StringToInt64(search.Cursor))
+	}
+	if !search.Since.IsZero() {
+		session.And("created_at >= ?", search.Since)
+	}
+	err = session.Desc("id").Limit(search.Limit).Find(&activities)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return activities, nil
+}
+
+// GetFollowerIDs get the users who follow any tag, question or user in the order of id, after the user id
+func (fr *FeedRepo) GetFollowerIDs(ctx context.Context, afterUserID string, limit int) (userIDs []string, err error) {
+	followTypes, err := fr.getActivityTypes(ctx, constant.ActTagFollow, constant.ActQuestionFollow,
+		constant.ActUserFollow)
+	if err != nil {
+		return nil, err
+	}
+	userIDs = make([]string, 0)
+	err = fr.data.DB.Table(entity.Activity{}.TableName()).Distinct("user_id").
+		Where(builder.In("activity_type", followTypes)).
+		And("cancelled = ?", entity.ActivityAvailable).
+		And("user_id > ?", converter.StringToInt64(afterUserID)).
+		Asc("user_id").Limit(limit).Find(&userIDs)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return userIDs, nil
+}
+
+// AddFeedDigest record the digest of the period, added is false if the user has got the digest of the period
+func (fr *FeedRepo) AddFeedDigest(ctx context.Context, digest *entity.FeedDigest) (added bool, err error) {
+	exist, err := fr.data.DB.Where("user_id = ?", digest.UserID).And("period = ?", digest.Period).
+		Exist(&entity.FeedDigest{})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if exist {
+		return false, nil
+	}
+	_, err = fr.data.DB.Insert(digest)
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return true, nil
+}
+
+func (fr *FeedRepo) getActivityTypes(ctx context.Context, keys ...constant.ActivityTypeKey) (types []int, err error) {
+	types = make([]int, 0, len(keys))
+	for _, key := range keys {
+		activityType, err := fr.activityRepo.GetActivityTypeByConfigKey(ctx, string(key))
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, activityType)
+	}
+	return types, nil
+}
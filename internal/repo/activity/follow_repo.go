@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"answer/internal/base/constant"
 	"answer/internal/service/activity_common"
 	"answer/internal/service/follow"
 	"answer/pkg/obj"
@@ -41,7 +42,20 @@ func NewFollowRepo(
 }
 
 func (ar *FollowRepo) Follow(ctx context.Context, objectID, userID string) error {
-	activityType, _, _, err := ar.activityRepo.GetActivityTypeByObjID(ctx, objectID, "follow")
+	objectType, err := obj.GetObjectTypeStrByObjectID(objectID)
+	if err != nil {
+		return err
+	}
+	return ar.follow(ctx, objectType, objectID, userID)
+}
+
+// FollowUser follow the user, the user id is not an object id so the object type is given
+func (ar *FollowRepo) FollowUser(ctx context.Context, followedUserID, userID string) error {
+	return ar.follow(ctx, constant.UserObjectType, followedUserID, userID)
+}
+
+func (ar *FollowRepo) follow(ctx context.Context, objectType, objectID, userID string) error {
+	activityType, err := ar.activityRepo.GetActivityTypeByObjKey(ctx, objectType, "follow")
 	if err != nil {
 		return err
 	}
@@ -91,7 +105,7 @@ func (ar *FollowRepo) Follow(ctx context.Context, objectID, userID string) error
 		}
 
 		// start update followers when everything is fine
-		err = ar.updateFollows(ctx, session, objectID, 1)
+		err = ar.updateFollows(ctx, session, objectType, objectID, 1)
 		if err != nil {
 			log.Error(err)
 		}
@@ -103,7 +117,20 @@ func (ar *FollowRepo) Follow(ctx context.Context, objectID, userID string) error
 }
 
 func (ar *FollowRepo) FollowCancel(ctx context.Context, objectID, userID string) error {
-	activityType, _, _, err := ar.activityRepo.GetActivityTypeByObjID(ctx, objectID, "follow")
+	objectType, err := obj.GetObjectTypeStrByObjectID(objectID)
+	if err != nil {
+		return err
+	}
+	return ar.followCancel(ctx, objectType, objectID, userID)
+}
+
+// FollowUserCancel cancel following the user
+func (ar *FollowRepo) FollowUserCancel(ctx context.Context, followedUserID, userID string) error {
+	return ar.followCancel(ctx, constant.UserObjectType, followedUserID, userID)
+}
+
+func (ar *FollowRepo) followCancel(ctx context.Context, objectType, objectID, userID string) error {
+	activityType, err := ar.activityRepo.GetActivityTypeByObjKey(ctx, objectType, "follow")
 	if err != nil {
 		return err
 	}
@@ -135,17 +162,14 @@ func (ar *FollowRepo) FollowCancel(ctx context.Context, objectID, userID string)
 			}); err != nil {
 			return
 		}
-		err = ar.updateFollows(ctx, session, objectID, -1)
+		err = ar.updateFollows(ctx, session, objectType, objectID, -1)
 		return
 	})
 	return err
 }
 
-func (ar *FollowRepo) updateFollows(ctx context.Context, session *xorm.Session, objectID string, follows int) error {
-	objectType, err := obj.GetObjectTypeStrByObjectID(objectID)
-	if err != nil {
-		return err
-	}
+func (ar *FollowRepo) updateFollows(ctx context.Context, session *xorm.Session, objectType, objectID string,
+	follows int) (err error) {
 	switch objectType {
 	case "question":
 		_, err = session.Where("id = ?", objectID).Incr("follow_count", follows).Update(&entity.Question{})
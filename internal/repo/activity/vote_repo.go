@@ -71,6 +71,10 @@ var LimitDownActions = map[string][]string{
 
 func (vr *VoteRepo) vote(ctx context.Context, objectID string, userID, objectUserID string, actions []string) (resp *schema.VoteResp, err error) {
 	resp = &schema.VoteResp{}
+	communityWiki, err := vr.isCommunityWiki(ctx, objectID)
+	if err != nil {
+		return
+	}
 	_, err = vr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
 		result = nil
 		for _, action := range actions {
@@ -87,6 +91,10 @@ func (vr *VoteRepo) vote(ctx context.Context, objectID string, userID, objectUse
 			if err != nil {
 				return
 			}
+			// the votes on the community wiki do not change the reputation of the author
+			if communityWiki && activityUserID == objectUserID && strings.Contains(action, "voted") {
+				deltaRank, hasRank = 0, 0
+			}
 
 			triggerUserID = userID
 			if userID == activityUserID {
@@ -171,6 +179,10 @@ func (vr *VoteRepo) vote(ctx context.Context, objectID string, userID, objectUse
 
 func (vr *VoteRepo) voteCancel(ctx context.Context, objectID string, userID, objectUserID string, actions []string) (resp *schema.VoteResp, err error) {
 	resp = &schema.VoteResp{}
+	communityWiki, err := vr.isCommunityWiki(ctx, objectID)
+	if err != nil {
+		return
+	}
 	_, err = vr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
 		for _, action := range actions {
 			var (
@@ -207,6 +219,13 @@ func (vr *VoteRepo) voteCancel(ctx context.Context, objectID string, userID, obj
 			if existsActivity.Cancelled == entity.ActivityCancelled {
 				return
 			}
+			// only the reputation gained before the post became the community wiki is taken back
+			if communityWiki && strings.Contains(action, "voted") {
+				deltaRank, hasRank = existsActivity.Rank, existsActivity.HasRank
+				if deltaRank == 0 {
+					hasRank = 0
+				}
+			}
 
 			if _, err = session.Where("id = ?", existsActivity.ID).Cols("cancelled", "cancelled_at").
 				Update(&entity.Activity{
@@ -390,6 +409,28 @@ func (vr *VoteRepo) ListUserVotes(
 	return
 }
 
+// isCommunityWiki whether the voted question or answer is the community wiki
+func (vr *VoteRepo) isCommunityWiki(ctx context.Context, objectID string) (communityWiki bool, err error) {
+	objectType, err := obj.GetObjectTypeStrByObjectID(objectID)
+	if err != nil {
+		return false, errors.BadRequest(reason.ObjectNotFound)
+	}
+	var post any
+	switch objectType {
+	case "question":
+		post = &entity.Question{}
+	case "answer":
+		post = &entity.Answer{}
+	default:
+		return false, nil
+	}
+	_, err = vr.data.DB.Table(post).Where("id = ?", objectID).Cols("community_wiki").Get(&communityWiki)
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return communityWiki, nil
+}
+
 // updateVotes
 // if votes < 0 Decr object vote_count,otherwise Incr object vote_count
 func (vr *VoteRepo) updateVotes(ctx context.Context, session *xorm.Session, objectID string, votes int) (err error) {
@@ -0,0 +1,55 @@
+package answer
+
+import (
+	"context"
+
+	"answer/internal/base/data"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	answercommon "answer/internal/service/answer_common"
+
+	"github.com/segmentfault/pacman/errors"
+)
+
+// answerAcceptLogRepo answer accept log repository
+type answerAcceptLogRepo struct {
+	data *data.Data
+}
+
+// NewAnswerAcceptLogRepo new repository
+func NewAnswerAcceptLogRepo(data *data.Data) answercommon.AnswerAcceptLogRepo {
+	return &answerAcceptLogRepo{
+		data: data,
+	}
+}
+
+// AddAcceptLog add the accept log
+func (ar *answerAcceptLogRepo) AddAcceptLog(ctx context.Context, acceptLog *entity.AnswerAcceptLog) (err error) {
+	_, err = ar.data.Session(ctx).Insert(acceptLog)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// GetAcceptLogsByQuestionID get the accept logs of all the answers of the question, the newest first
+func (ar *answerAcceptLogRepo) GetAcceptLogsByQuestionID(ctx context.Context, questionID string) (
+	acceptLogs []*entity.AnswerAcceptLog, err error) {
+	acceptLogs = make([]*entity.AnswerAcceptLog, 0)
+	err = ar.data.DB.Where("question_id = ?", questionID).Desc("id").Find(&acceptLogs)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return acceptLogs, nil
+}
+
+// GetAcceptLogsByAnswerID get the accept logs of the answer, the newest first
+func (ar *answerAcceptLogRepo) GetAcceptLogsByAnswerID(ctx context.Context, answerID string) (
+	acceptLogs []*entity.AnswerAcceptLog, err error) {
+	acceptLogs = make([]*entity.AnswerAcceptLog, 0)
+	err = ar.data.DB.Where("answer_id = ?", answerID).Desc("id").Find(&acceptLogs)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return acceptLogs, nil
+}
@@ -52,7 +52,7 @@ func (ar *answerRepo) AddAnswer(ctx context.Context, answer *entity.Answer) (err
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
 	answer.ID = ID
-	_, err = ar.data.DB.Insert(answer)
+	_, err = ar.data.Session(ctx).Insert(answer)
 
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
@@ -134,28 +134,24 @@ func (ar *answerRepo) GetAnswerPage(ctx context.Context, page, pageSize int, ans
 	return
 }
 
-// UpdateAdopted
-// If no answer is selected, the answer id can be 0
-func (ar *answerRepo) UpdateAdopted(ctx context.Context, id string, questionID string) error {
-	if questionID == "" {
-		return nil
+// UpdateAnswerAdopted update the accepted status of the answer only, the other answers of the question are not changed
+func (ar *answerRepo) UpdateAnswerAdopted(ctx context.Context, id string, adopted int) error {
+	_, err := ar.data.Session(ctx).ID(id).Cols("adopted").Update(&entity.Answer{Adopted: adopted})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
-	var data entity.Answer
-	data.ID = id
+	return nil
+}
 
-	data.Adopted = schema.AnswerAdoptedFailed
-	_, err := ar.data.DB.Where("question_id =?", questionID).Cols("adopted").Update(&data)
+// GetAcceptedAnswers get the accepted answers of the question in the order of id
+func (ar *answerRepo) GetAcceptedAnswers(ctx context.Context, questionID string) (answerList []*entity.Answer, err error) {
+	answerList = make([]*entity.Answer, 0)
+	err = ar.data.Session(ctx).Where("question_id = ?", questionID).And("adopted = ?", schema.AnswerAdoptedEnable).
+		Asc("id").Find(&answerList)
 	if err != nil {
-		return err
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
-	if id != "0" {
-		data.Adopted = schema.AnswerAdoptedEnable
-		_, err = ar.data.DB.Where("id = ?", id).Cols("adopted").Update(&data)
-		if err != nil {
-			return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
-		}
-	}
-	return nil
+	return answerList, nil
 }
 
 // GetByID
@@ -207,7 +203,14 @@ func (ar *answerRepo) SearchList(ctx context.Context, search *entity.AnswerSearc
 	default:
 		session = session.OrderBy("adopted desc,vote_count desc,created_at asc")
 	}
-	session = session.And("status = ?", entity.AnswerStatusAvailable)
+	if len(search.PendingUserID) > 0 {
+		session = session.And(builder.Or(
+			builder.Eq{"status": entity.AnswerStatusAvailable},
+			builder.Eq{"status": entity.AnswerStatusPending, "user_id": search.PendingUserID},
+		))
+	} else {
+		session = session.And("status = ?", entity.AnswerStatusAvailable)
+	}
 
 	session = session.Limit(search.PageSize, offset)
 	count, err = session.FindAndCount(&rows)
@@ -92,6 +92,26 @@ func (ar *authRepo) RemoveUserStatus(ctx context.Context, userID string) (err er
 	return nil
 }
 
+// SetUserTokenRevokedAt set the time of revoking all tokens of user
+func (ar *authRepo) SetUserTokenRevokedAt(ctx context.Context, userID string, revokedAt int64) (err error) {
+	// the tokens issued before are removed once used, so none of them outlive the cache time
+	err = ar.data.Cache.SetInt64(ctx, constant.UserTokenRevokedCacheKey+userID, revokedAt, constant.UserTokenCacheTime)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// GetUserTokenRevokedAt get the time of revoking all tokens of user, 0 if never revoked
+func (ar *authRepo) GetUserTokenRevokedAt(ctx context.Context, userID string) (revokedAt int64, err error) {
+	revokedAt, err = ar.data.Cache.GetInt64(ctx, constant.UserTokenRevokedCacheKey+userID)
+	if err != nil {
+		// the cache returns error when key not found
+		return 0, nil
+	}
+	return revokedAt, nil
+}
+
 // GetBackyardUserCacheInfo get backyard user cache info
 func (ar *authRepo) GetBackyardUserCacheInfo(ctx context.Context, accessToken string) (userInfo *entity.UserCacheInfo, err error) {
 	userInfoCache, err := ar.data.Cache.GetString(ctx, constant.AdminTokenCacheKey+accessToken)
@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"answer/internal/base/constant"
+	"answer/internal/base/data"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/service/auth"
+
+	"github.com/segmentfault/pacman/errors"
+)
+
+// userSessionRepo user session repository
+type userSessionRepo struct {
+	data *data.Data
+}
+
+// NewUserSessionRepo new repository
+func NewUserSessionRepo(data *data.Data) auth.UserSessionRepo {
+	return &userSessionRepo{
+		data: data,
+	}
+}
+
+// AddUserSession add user session
+func (ur *userSessionRepo) AddUserSession(ctx context.Context, session *entity.UserSession) (err error) {
+	_, err = ur.data.DB.Insert(session)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// GetUserSession get user session by id
+func (ur *userSessionRepo) GetUserSession(ctx context.Context, sessionID string) (
+	session *entity.UserSession, exist bool, err error) {
+	session = &entity.UserSession{}
+	exist, err = ur.data.DB.ID(sessionID).Get(session)
+	if err != nil {
+		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetUserSessionList get all sessions of user, the latest active first
+func (ur *userSessionRepo) GetUserSessionList(ctx context.Context, userID string) (
+	sessions []*entity.UserSession, err error) {
+	sessions = make([]*entity.UserSession, 0)
+	err = ur.data.DB.Where("user_id = ?", userID).Desc("last_seen_at").Find(&sessions)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UpdateLastSeen update the last seen time of session
+func (ur *userSessionRepo) UpdateLastSeen(ctx context.Context, sessionID string, lastSeenAt time.Time) (err error) {
+	_, err = ur.data.DB.ID(sessionID).Cols("last_seen_at").Update(&entity.UserSession{LastSeenAt: lastSeenAt})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// RemoveUserSession remove user session
+func (ur *userSessionRepo) RemoveUserSession(ctx context.Context, sessionID string) (err error) {
+	_, err = ur.data.DB.ID(sessionID).Delete(&entity.UserSession{})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// SetSessionToken set the access token of session
+func (ur *userSessionRepo) SetSessionToken(ctx context.Context, sessionID, accessToken string) (err error) {
+	err = ur.data.Cache.SetString(ctx, constant.UserSessionCacheKey+sessionID, accessToken,
+		constant.UserTokenCacheTime)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// GetSessionToken get the access token of session, empty if the session is expired
+func (ur *userSessionRepo) GetSessionToken(ctx context.Context, sessionID string) (accessToken string, err error) {
+	accessToken, err = ur.data.Cache.GetString(ctx, constant.UserSessionCacheKey+sessionID)
+	if err != nil {
+		return "", nil
+	}
+	return accessToken, nil
+}
+
+// RemoveSessionToken remove the access token of session
+func (ur *userSessionRepo) RemoveSessionToken(ctx context.Context, sessionID string) (err error) {
+	err = ur.data.Cache.Del(ctx, constant.UserSessionCacheKey+sessionID)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	"answer/internal/service"
 
 	"github.com/segmentfault/pacman/errors"
+	"xorm.io/xorm"
 )
 
 // collectionGroupRepo collectionGroup repository
@@ -95,3 +96,62 @@ func (cr *collectionGroupRepo) GetDefaultID(ctx context.Context, userID string)
 	}
 	return
 }
+
+// GetCollectionGroupList get all the collection groups of the user in order
+func (cr *collectionGroupRepo) GetCollectionGroupList(ctx context.Context, userID string) (
+	collectionGroupList []*entity.CollectionGroup, err error) {
+	collectionGroupList = make([]*entity.CollectionGroup, 0)
+	err = cr.data.DB.Where("user_id = ?", userID).Asc("sort_order", "id").Find(&collectionGroupList)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetPublicCollectionGroupByToken get the public collection group by the share token
+func (cr *collectionGroupRepo) GetPublicCollectionGroupByToken(ctx context.Context, token string) (
+	collectionGroup *entity.CollectionGroup, exist bool, err error) {
+	collectionGroup = &entity.CollectionGroup{}
+	exist, err = cr.data.DB.Where("share_token = ?", token).And("is_public = ?", true).Get(collectionGroup)
+	if err != nil {
+		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// RemoveCollectionGroup move the collections in the group to the target group, then remove the group
+func (cr *collectionGroupRepo) RemoveCollectionGroup(ctx context.Context, collectionGroup *entity.CollectionGroup,
+	targetGroupID string) (err error) {
+	_, err = cr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		_, err = session.Where("user_id = ?", collectionGroup.UserID).
+			And("user_collection_group_id = ?", collectionGroup.ID).
+			Cols("user_collection_group_id").Update(&entity.Collection{UserCollectionGroupID: targetGroupID})
+		if err != nil {
+			return nil, err
+		}
+		_, err = session.ID(collectionGroup.ID).Delete(&entity.CollectionGroup{})
+		return nil, err
+	})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// SortCollectionGroups set the sort order of the groups of the user by the order of the ids
+func (cr *collectionGroupRepo) SortCollectionGroups(ctx context.Context, userID string, ids []string) (err error) {
+	_, err = cr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		for i, id := range ids {
+			_, err = session.Where("id = ?", id).And("user_id = ?", userID).
+				Cols("sort_order").Update(&entity.CollectionGroup{SortOrder: i + 1})
+			if err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
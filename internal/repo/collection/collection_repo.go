@@ -53,7 +53,10 @@ func (cr *collectionRepo) RemoveCollection(ctx context.Context, id string) (err
 // UpdateCollection update collection
 func (cr *collectionRepo) UpdateCollection(ctx context.Context, collection *entity.Collection, cols []string) (err error) {
 	_, err = cr.data.DB.ID(collection.ID).Cols(cols...).Update(collection)
-	return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
 }
 
 // GetCollection get collection one
@@ -164,3 +167,35 @@ func (cr *collectionRepo) SearchList(ctx context.Context, search *entity.Collect
 	}
 	return rows, count, nil
 }
+
+// GetGroupCollectionPage get the collections in the group, the newest first
+func (cr *collectionRepo) GetGroupCollectionPage(ctx context.Context, groupID string, page, pageSize int) (
+	collectionList []*entity.Collection, total int64, err error) {
+	collectionList = make([]*entity.Collection, 0)
+	session := cr.data.DB.Desc("created_at")
+	total, err = pager.Help(page, pageSize, &collectionList, &entity.Collection{UserCollectionGroupID: groupID}, session)
+	if err != nil {
+		return nil, 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// CountGroupCollections count the collections of the user in each group
+func (cr *collectionRepo) CountGroupCollections(ctx context.Context, userID string) (counts map[string]int64, err error) {
+	type groupCount struct {
+		GroupID string `xorm:"user_collection_group_id"`
+		Count   int64  `xorm:"count"`
+	}
+	rows := make([]*groupCount, 0)
+	err = cr.data.DB.Table(entity.Collection{}.TableName()).
+		Select("user_collection_group_id, count(*) AS count").
+		Where("user_id = ?", userID).GroupBy("user_collection_group_id").Find(&rows)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	counts = make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.GroupID] = row.Count
+	}
+	return counts, nil
+}
@@ -51,7 +51,7 @@ func (cr *commentRepo) AddComment(ctx context.Context, comment *entity.Comment)
 
 // RemoveComment delete comment
 func (cr *commentRepo) RemoveComment(ctx context.Context, commentID string) (err error) {
-	session := cr.data.DB.ID(commentID)
+	session := cr.data.Session(ctx).ID(commentID)
 	_, err = session.Update(&entity.Comment{Status: entity.CommentStatusDeleted})
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
@@ -99,10 +99,56 @@ func (cr *commentRepo) GetCommentPage(ctx context.Context, commentQuery *comment
 	session.OrderBy(commentQuery.GetOrderBy())
 	session.Where("status = ?", entity.CommentStatusAvailable)
 
-	cond := &entity.Comment{ObjectID: commentQuery.ObjectID, UserID: commentQuery.UserID}
+	cond := &entity.Comment{ObjectID: commentQuery.ObjectID, UserID: commentQuery.UserID,
+		ParentCommentID: commentQuery.ParentCommentID}
 	total, err = pager.Help(commentQuery.Page, commentQuery.PageSize, &commentList, cond, session)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
 	return
 }
+
+// GetCommentReplies get the first replies nested under each of the comments directly, the oldest first
+func (cr *commentRepo) GetCommentReplies(ctx context.Context, parentCommentIDs []string, limit int) (
+	commentList []*entity.Comment, err error) {
+	commentList = make([]*entity.Comment, 0)
+	if len(parentCommentIDs) == 0 {
+		return commentList, nil
+	}
+	// the reply is in the first ones if less than limit replies of the same comment are earlier than it
+	err = cr.data.DB.In("parent_comment_id", parentCommentIDs).
+		And("status = ?", entity.CommentStatusAvailable).
+		And("(SELECT COUNT(*) FROM comment earlier WHERE earlier.parent_comment_id = comment.parent_comment_id"+
+			" AND earlier.status = ? AND (earlier.created_at < comment.created_at"+
+			" OR (earlier.created_at = comment.created_at AND earlier.id < comment.id))) < ?",
+			entity.CommentStatusAvailable, limit).
+		Asc("created_at", "id").Find(&commentList)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// CountCommentReplies count the replies nested under each of the comments directly
+func (cr *commentRepo) CountCommentReplies(ctx context.Context, parentCommentIDs []string) (
+	counts map[string]int, err error) {
+	counts = make(map[string]int)
+	if len(parentCommentIDs) == 0 {
+		return counts, nil
+	}
+	rows := make([]*struct {
+		ParentCommentID string `xorm:"parent_comment_id"`
+		ReplyCount      int    `xorm:"reply_count"`
+	}, 0)
+	err = cr.data.DB.Table(&entity.Comment{}).Select("parent_comment_id, COUNT(*) AS reply_count").
+		In("parent_comment_id", parentCommentIDs).
+		And("status = ?", entity.CommentStatusAvailable).
+		GroupBy("parent_comment_id").Find(&rows)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	for _, row := range rows {
+		counts[row.ParentCommentID] = row.ReplyCount
+	}
+	return counts, nil
+}
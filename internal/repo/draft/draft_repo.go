@@ -0,0 +1,88 @@
+package draft
+
+import (
+	"context"
+	"time"
+
+	"answer/internal/base/data"
+	"answer/internal/base/pager"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/service/draft"
+
+	"github.com/segmentfault/pacman/errors"
+)
+
+// draftRepo draft repository
+type draftRepo struct {
+	data *data.Data
+}
+
+// NewDraftRepo new repository
+func NewDraftRepo(data *data.Data) draft.DraftRepo {
+	return &draftRepo{
+		data: data,
+	}
+}
+
+// SaveDraft add the draft, or overwrite the draft of the same context
+func (dr *draftRepo) SaveDraft(ctx context.Context, draft *entity.Draft) (err error) {
+	old, exist, err := dr.GetDraft(ctx, draft.UserID, draft.DraftType, draft.ObjectID)
+	if err != nil {
+		return err
+	}
+	if exist {
+		draft.ID = old.ID
+		draft.CreatedAt = old.CreatedAt
+		_, err = dr.data.DB.ID(old.ID).Cols("title", "content", "tags").Update(draft)
+	} else {
+		_, err = dr.data.DB.Insert(draft)
+	}
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// GetDraft get the draft of the context
+func (dr *draftRepo) GetDraft(ctx context.Context, userID, draftType, objectID string) (
+	draft *entity.Draft, exist bool, err error) {
+	draft = &entity.Draft{}
+	exist, err = dr.data.DB.Where("user_id = ?", userID).And("draft_type = ?", draftType).
+		And("object_id = ?", objectID).Get(draft)
+	if err != nil {
+		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// RemoveDraft remove the draft of the context
+func (dr *draftRepo) RemoveDraft(ctx context.Context, userID, draftType, objectID string) (err error) {
+	_, err = dr.data.DB.Where("user_id = ?", userID).And("draft_type = ?", draftType).
+		And("object_id = ?", objectID).Delete(&entity.Draft{})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// GetDraftPage get the drafts of the user, the latest saved first
+func (dr *draftRepo) GetDraftPage(ctx context.Context, userID string, page, pageSize int) (
+	drafts []*entity.Draft, total int64, err error) {
+	drafts = make([]*entity.Draft, 0)
+	session := dr.data.DB.Desc("updated_at")
+	total, err = pager.Help(page, pageSize, &drafts, &entity.Draft{UserID: userID}, session)
+	if err != nil {
+		return nil, 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// RemoveExpiredDrafts remove the drafts which are not saved since the time
+func (dr *draftRepo) RemoveExpiredDrafts(ctx context.Context, before time.Time) (count int64, err error) {
+	count, err = dr.data.DB.Where("updated_at < ?", before).Delete(&entity.Draft{})
+	if err != nil {
+		return 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
@@ -0,0 +1,77 @@
+package mention
+
+import (
+	"context"
+	"strings"
+
+	"answer/internal/base/data"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/service/mention"
+
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+)
+
+// likeEscaper escape the wildcards of LIKE in the keyword, "!" is the escape character which works in all databases
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// mentionRepo mention repository
+type mentionRepo struct {
+	data *data.Data
+}
+
+// NewMentionRepo new repository
+func NewMentionRepo(data *data.Data) mention.MentionRepo {
+	return &mentionRepo{
+		data: data,
+	}
+}
+
+// GetThreadParticipants get the users who take part in the question, the question author, answerers and commenters.
+// The value is the number of the posts of the user in the thread.
+func (mr *mentionRepo) GetThreadParticipants(ctx context.Context, questionID string) (
+	participants map[string]int, err error) {
+	participants = make(map[string]int)
+	question := &entity.Question{}
+	exist, err := mr.data.DB.ID(questionID).Cols("user_id").Get(question)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if !exist {
+		return participants, nil
+	}
+	participants[question.UserID]++
+
+	answerUserIDs := make([]string, 0)
+	err = mr.data.DB.Table(entity.Answer{}.TableName()).Cols("user_id").
+		Where("question_id = ?", questionID).And("status = ?", entity.AnswerStatusAvailable).
+		Find(&answerUserIDs)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	commentUserIDs := make([]string, 0)
+	err = mr.data.DB.Table((&entity.Comment{}).TableName()).Cols("user_id").
+		Where("question_id = ?", questionID).And("status = ?", entity.CommentStatusAvailable).
+		Find(&commentUserIDs)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	for _, userID := range append(answerUserIDs, commentUserIDs...) {
+		participants[userID]++
+	}
+	return participants, nil
+}
+
+// SearchUsers search the available users whose username or display name starts with the keyword, the top rank first
+func (mr *mentionRepo) SearchUsers(ctx context.Context, keyword string, limit int) (users []*entity.User, err error) {
+	users = make([]*entity.User, 0)
+	keyword = likeEscaper.Replace(keyword) + "%"
+	err = mr.data.DB.Where("status = ?", entity.UserStatusAvailable).
+		And(builder.Expr("username LIKE ? ESCAPE '!'", keyword).Or(builder.Expr("display_name LIKE ? ESCAPE '!'", keyword))).
+		Desc("rank").Limit(limit).Find(&users)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return users, nil
+}
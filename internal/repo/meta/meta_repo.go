@@ -26,7 +26,7 @@ func NewMetaRepo(data *data.Data) meta.MetaRepo {
 
 // AddMeta add meta
 func (mr *metaRepo) AddMeta(ctx context.Context, meta *entity.Meta) (err error) {
-	_, err = mr.data.DB.Insert(meta)
+	_, err = mr.data.Session(ctx).Insert(meta)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
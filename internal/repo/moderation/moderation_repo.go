@@ -0,0 +1,62 @@
+package moderation
+
+import (
+	"context"
+
+	"answer/internal/base/constant"
+	"answer/internal/base/data"
+	"answer/internal/base/pager"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/service/moderation"
+
+	"github.com/segmentfault/pacman/errors"
+)
+
+// moderationRepo moderation repository
+type moderationRepo struct {
+	data *data.Data
+}
+
+// NewModerationRepo new repository
+func NewModerationRepo(data *data.Data) moderation.ModerationRepo {
+	return &moderationRepo{
+		data: data,
+	}
+}
+
+// AddModerationResult add moderation result
+func (mr *moderationRepo) AddModerationResult(ctx context.Context, result *entity.ModerationResult) (err error) {
+	_, err = mr.data.Session(ctx).Insert(result)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// GetModerationResultPage get the moderation results, the newest first
+func (mr *moderationRepo) GetModerationResultPage(ctx context.Context, objectID, action string, page, pageSize int) (
+	results []*entity.ModerationResult, total int64, err error) {
+	results = make([]*entity.ModerationResult, 0)
+	session := mr.data.DB.Desc("created_at")
+	cond := &entity.ModerationResult{ObjectID: objectID, Action: action}
+	total, err = pager.Help(page, pageSize, &results, cond, session)
+	if err != nil {
+		return nil, 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// MarkSeen remember the fingerprint of the content, return whether it has been seen recently
+func (mr *moderationRepo) MarkSeen(ctx context.Context, fingerprint string) (seen bool, err error) {
+	key := constant.ModerationContentCacheKey + fingerprint
+	content, err := mr.data.Cache.GetString(ctx, key)
+	if err == nil && len(content) > 0 {
+		return true, nil
+	}
+	err = mr.data.Cache.SetString(ctx, key, "1", constant.ModerationContentCacheTime)
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return false, nil
+}
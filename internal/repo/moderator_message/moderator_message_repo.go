@@ -0,0 +1,66 @@
+package moderator_message
+
+import (
+	"context"
+
+	"answer/internal/base/data"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/service/moderator_message"
+
+	"github.com/segmentfault/pacman/errors"
+)
+
+// moderatorMessageRepo moderator message repository
+type moderatorMessageRepo struct {
+	data *data.Data
+}
+
+// NewModeratorMessageRepo new repository
+func NewModeratorMessageRepo(data *data.Data) moderator_message.ModeratorMessageRepo {
+	return &moderatorMessageRepo{
+		data: data,
+	}
+}
+
+// AddModeratorMessage add moderator message
+func (mr *moderatorMessageRepo) AddModeratorMessage(ctx context.Context, message *entity.ModeratorMessage) (err error) {
+	_, err = mr.data.DB.Insert(message)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// GetModeratorMessage get moderator message by id
+func (mr *moderatorMessageRepo) GetModeratorMessage(ctx context.Context, id string) (
+	message *entity.ModeratorMessage, exist bool, err error) {
+	message = &entity.ModeratorMessage{}
+	exist, err = mr.data.DB.ID(id).Get(message)
+	if err != nil {
+		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetModeratorMessageThread get the first message and the replies of the thread, the oldest first
+func (mr *moderatorMessageRepo) GetModeratorMessageThread(ctx context.Context, threadID string) (
+	messages []*entity.ModeratorMessage, err error) {
+	messages = make([]*entity.ModeratorMessage, 0)
+	err = mr.data.DB.Where("id = ?", threadID).Or("thread_id = ?", threadID).Asc("id").Find(&messages)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetUserModeratorMessages get all the messages about the user, the oldest first
+func (mr *moderatorMessageRepo) GetUserModeratorMessages(ctx context.Context, userID string) (
+	messages []*entity.ModeratorMessage, err error) {
+	messages = make([]*entity.ModeratorMessage, 0)
+	err = mr.data.DB.Where("user_id = ?", userID).Asc("id").Find(&messages)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
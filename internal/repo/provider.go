@@ -11,13 +11,18 @@ import (
 	"answer/internal/repo/comment"
 	"answer/internal/repo/common"
 	"answer/internal/repo/config"
+	"answer/internal/repo/draft"
 	"answer/internal/repo/export"
+	"answer/internal/repo/mention"
 	"answer/internal/repo/meta"
+	"answer/internal/repo/moderation"
+	"answer/internal/repo/moderator_message"
 	"answer/internal/repo/notification"
 	"answer/internal/repo/question"
 	"answer/internal/repo/rank"
 	"answer/internal/repo/reason"
 	"answer/internal/repo/report"
+	"answer/internal/repo/review"
 	"answer/internal/repo/revision"
 	"answer/internal/repo/search_common"
 	"answer/internal/repo/site_info"
@@ -45,9 +50,20 @@ var ProviderSetRepo = wire.NewSet(
 	config.NewConfigRepo,
 	user.NewUserRepo,
 	user.NewUserBackyardRepo,
+	user.NewUserInviteRepo,
+	user.NewUserDataRepo,
+	user.NewUserDeletionRepo,
+	user.NewUserDataExportRepo,
+	user.NewUserTwoFactorRepo,
+	user.NewUserSuspensionRepo,
 	rank.NewUserRankRepo,
+	rank.NewReputationRepo,
 	question.NewQuestionRepo,
+	question.NewQuestionScoreRepo,
+	question.NewQuestionViewRepo,
+	question.NewQuestionCloseVoteRepo,
 	answer.NewAnswerRepo,
+	answer.NewAnswerAcceptLogRepo,
 	activity_common.NewActivityRepo,
 	activity.NewVoteRepo,
 	activity.NewFollowRepo,
@@ -58,10 +74,20 @@ var ProviderSetRepo = wire.NewSet(
 	tag.NewTagRepo,
 	tag_common.NewTagCommonRepo,
 	tag.NewTagRelRepo,
+	tag.NewTagTemplateRepo,
+	tag.NewTagModeratorRepo,
+	tag.NewTagSubscriptionRepo,
 	collection.NewCollectionRepo,
 	collection.NewCollectionGroupRepo,
 	auth.NewAuthRepo,
+	auth.NewUserSessionRepo,
 	revision.NewRevisionRepo,
+	review.NewReviewRepo,
+	moderation.NewModerationRepo,
+	moderator_message.NewModeratorMessageRepo,
+	draft.NewDraftRepo,
+	activity.NewFeedRepo,
+	mention.NewMentionRepo,
 	search_common.NewSearchRepo,
 	meta.NewMetaRepo,
 	export.NewEmailRepo,
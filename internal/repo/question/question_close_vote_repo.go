@@ -0,0 +1,94 @@
+package question
+
+import (
+	"context"
+	"time"
+
+	"answer/internal/base/data"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/service/question_close_vote"
+
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+)
+
+// questionCloseVoteRepo question close vote repository
+type questionCloseVoteRepo struct {
+	data *data.Data
+}
+
+// NewQuestionCloseVoteRepo new repository
+func NewQuestionCloseVoteRepo(data *data.Data) question_close_vote.QuestionCloseVoteRepo {
+	return &questionCloseVoteRepo{
+		data: data,
+	}
+}
+
+// AddCloseVote add the close or reopen vote. The user has one vote of each type on the question, so the expired
+// or completed vote of the user is replaced, and nothing is added if the user already has the active vote.
+// The question is locked first in the transaction, so the votes of the question are added and counted one by one.
+func (qr *questionCloseVoteRepo) AddCloseVote(ctx context.Context, vote *entity.QuestionCloseVote, since time.Time) (
+	added bool, err error) {
+	err = qr.data.Transaction(ctx, func(ctx context.Context) error {
+		session := qr.data.Session(ctx)
+		_, err := session.Where("id = ?", vote.QuestionID).Cols("id").ForUpdate().Get(&entity.Question{})
+		if err != nil {
+			return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+		var cond builder.Cond = builder.Neq{"status": entity.QuestionCloseVoteStatusActive}
+		if !since.IsZero() {
+			cond = builder.Or(cond, builder.Lt{"created_at": since})
+		}
+		_, err = session.Where("question_id = ? AND user_id = ? AND vote_type = ?", vote.QuestionID, vote.UserID, vote.VoteType).
+			And(cond).Delete(&entity.QuestionCloseVote{})
+		if err != nil {
+			return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+		exist, err := session.Where("question_id = ? AND user_id = ? AND vote_type = ?", vote.QuestionID, vote.UserID, vote.VoteType).
+			Exist(&entity.QuestionCloseVote{})
+		if err != nil {
+			return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+		if exist {
+			return nil
+		}
+		if _, err = session.Insert(vote); err != nil {
+			return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+		added = true
+		return nil
+	})
+	return added, err
+}
+
+// GetActiveCloseVoteList get the active votes of the vote type which are cast after the since time,
+// the zero since time means the votes never expire
+func (qr *questionCloseVoteRepo) GetActiveCloseVoteList(ctx context.Context, questionID string, voteType int,
+	since time.Time) (votes []*entity.QuestionCloseVote, err error) {
+	votes = make([]*entity.QuestionCloseVote, 0)
+	session := qr.data.Session(ctx).Where("question_id = ?", questionID).
+		And("vote_type = ?", voteType).
+		And("status = ?", entity.QuestionCloseVoteStatusActive)
+	if !since.IsZero() {
+		session.And("created_at >= ?", since)
+	}
+	err = session.Asc("created_at").Find(&votes)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return votes, nil
+}
+
+// CompleteCloseVotes mark all the active votes of the vote type as completed, they are not counted any more
+func (qr *questionCloseVoteRepo) CompleteCloseVotes(ctx context.Context, questionID string, voteType int) (err error) {
+	_, err = qr.data.Session(ctx).Where("question_id = ?", questionID).
+		And("vote_type = ?", voteType).
+		And("status = ?", entity.QuestionCloseVoteStatusActive).
+		Cols("status").
+		Update(&entity.QuestionCloseVote{Status: entity.QuestionCloseVoteStatusCompleted})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
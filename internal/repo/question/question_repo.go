@@ -2,11 +2,14 @@ package question
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 	"strings"
 	"time"
 	"unicode"
 
 	"xorm.io/builder"
+	"xorm.io/xorm"
 
 	"answer/internal/base/constant"
 	"answer/internal/base/data"
@@ -16,10 +19,28 @@ import (
 	"answer/internal/schema"
 	questioncommon "answer/internal/service/question_common"
 	"answer/internal/service/unique"
+	"answer/pkg/converter"
 
 	"github.com/segmentfault/pacman/errors"
 )
 
+const (
+	// followTagWeight each followed tag of the question lifts it in the for you order,
+	// one point of the hot score is about half a day
+	followTagWeight = 2.0
+	// maxFollowTagCount the followed tags of the question lift it up to the count
+	maxFollowTagCount = 5
+	// maxFollowTagLift the most the followed tags lift the question in the for you order
+	maxFollowTagLift = followTagWeight * maxFollowTagCount
+)
+
+// searchListColumns the columns of the questions in the search list
+const searchListColumns = "question.id,question.user_id,last_edit_user_id,question.title,question.original_text," +
+	"question.parsed_text,question.status,question.view_count,question.unique_view_count,question.vote_count," +
+	"question.answer_count,question.collection_count,question.follow_count,question.accepted_answer_id," +
+	"question.last_answer_id,question.created_at,question.updated_at,question.post_update_time,question.revision_id," +
+	"question.language"
+
 // questionRepo question repository
 type questionRepo struct {
 	data         *data.Data
@@ -50,6 +71,31 @@ func (qr *questionRepo) AddQuestion(ctx context.Context, question *entity.Questi
 	return
 }
 
+// AddQuestionWithAnswer add the question and the answer to it together, both of them are added or neither
+func (qr *questionRepo) AddQuestionWithAnswer(ctx context.Context, question *entity.Question, answer *entity.Answer) (
+	err error) {
+	question.ID, err = qr.uniqueIDRepo.GenUniqueIDStr(ctx, question.TableName())
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	answer.ID, err = qr.uniqueIDRepo.GenUniqueIDStr(ctx, answer.TableName())
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	answer.QuestionID = question.ID
+	_, err = qr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		if _, err = session.Insert(question); err != nil {
+			return nil, err
+		}
+		_, err = session.Insert(answer)
+		return nil, err
+	})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
 // RemoveQuestion delete question
 func (qr *questionRepo) RemoveQuestion(ctx context.Context, id string) (err error) {
 	_, err = qr.data.DB.Where("id =?", id).Delete(&entity.Question{})
@@ -61,25 +107,16 @@ func (qr *questionRepo) RemoveQuestion(ctx context.Context, id string) (err erro
 
 // UpdateQuestion update question
 func (qr *questionRepo) UpdateQuestion(ctx context.Context, question *entity.Question, Cols []string) (err error) {
-	_, err = qr.data.DB.Where("id =?", question.ID).Cols(Cols...).Update(question)
+	_, err = qr.data.Session(ctx).Where("id =?", question.ID).Cols(Cols...).Update(question)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
 	return
 }
 
-func (qr *questionRepo) UpdatePvCount(ctx context.Context, questionID string) (err error) {
-	question := &entity.Question{}
-	_, err = qr.data.DB.Where("id =?", questionID).Incr("view_count", 1).Update(question)
-	if err != nil {
-		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
-	}
-	return nil
-}
-
 func (qr *questionRepo) UpdateAnswerCount(ctx context.Context, questionID string, num int) (err error) {
 	question := &entity.Question{}
-	_, err = qr.data.DB.Where("id =?", questionID).Incr("answer_count", num).Update(question)
+	_, err = qr.data.Session(ctx).Where("id =?", questionID).Incr("answer_count", num).Update(question)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -98,7 +135,7 @@ func (qr *questionRepo) UpdateCollectionCount(ctx context.Context, questionID st
 func (qr *questionRepo) UpdateQuestionStatus(ctx context.Context, question *entity.Question) (err error) {
 	now := time.Now()
 	question.UpdatedAt = now
-	_, err = qr.data.DB.Where("id =?", question.ID).Cols("status", "updated_at").Update(question)
+	_, err = qr.data.Session(ctx).Where("id =?", question.ID).Cols("status", "updated_at").Update(question)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -113,8 +150,20 @@ func (qr *questionRepo) UpdateAccepted(ctx context.Context, question *entity.Que
 	return nil
 }
 
+// ChangeAccepted change the accepted answer id of the question only if it is still the old one,
+// changed is false if another request has changed it first
+func (qr *questionRepo) ChangeAccepted(ctx context.Context, questionID, oldAnswerID, newAnswerID string) (
+	changed bool, err error) {
+	affected, err := qr.data.Session(ctx).Where("id = ?", questionID).And("accepted_answer_id = ?", oldAnswerID).
+		Cols("accepted_answer_id").Update(&entity.Question{AcceptedAnswerID: newAnswerID})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected > 0, nil
+}
+
 func (qr *questionRepo) UpdateLastAnswer(ctx context.Context, question *entity.Question) (err error) {
-	_, err = qr.data.DB.Where("id =?", question.ID).Cols("last_answer_id").Update(question)
+	_, err = qr.data.Session(ctx).Where("id =?", question.ID).Cols("last_answer_id").Update(question)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -127,7 +176,7 @@ func (qr *questionRepo) GetQuestion(ctx context.Context, id string) (
 ) {
 	question = &entity.Question{}
 	question.ID = id
-	exist, err = qr.data.DB.Where("id = ?", id).Get(question)
+	exist, err = qr.data.Session(ctx).Where("id = ?", id).Get(question)
 	if err != nil {
 		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -164,15 +213,35 @@ func (qr *questionRepo) GetQuestionList(ctx context.Context, question *entity.Qu
 }
 
 func (qr *questionRepo) GetQuestionCount(ctx context.Context) (count int64, err error) {
-	questionList := make([]*entity.Question, 0)
-
-	count, err = qr.data.DB.In("question.status", []int{entity.QuestionStatusAvailable, entity.QuestionStatusClosed}).FindAndCount(&questionList)
+	count, err = qr.data.DB.In("question.status", []int{entity.QuestionStatusAvailable, entity.QuestionStatusClosed}).Count(&entity.Question{})
 	if err != nil {
 		return count, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
 	return
 }
 
+// GetQuestionIDsPage get the visible question ids, titles and update time by page, used for sitemap
+func (qr *questionRepo) GetQuestionIDsPage(ctx context.Context, page, pageSize int) (questionList []*entity.Question, err error) {
+	questionList = make([]*entity.Question, 0)
+	if page > 0 {
+		page = page - 1
+	} else {
+		page = 0
+	}
+	if pageSize == 0 {
+		pageSize = constant.DefaultPageSize
+	}
+	offset := page * pageSize
+	session := qr.data.DB.Table("question").Cols("id", "title", "created_at", "updated_at", "post_update_time", "language")
+	session = session.In("question.status", []int{entity.QuestionStatusAvailable, entity.QuestionStatusClosed})
+	session = session.OrderBy("question.created_at asc").Limit(pageSize, offset)
+	err = session.Find(&questionList)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
 // GetQuestionPage get question page
 func (qr *questionRepo) GetQuestionPage(ctx context.Context, page, pageSize int, question *entity.Question) (questionList []*entity.Question, total int64, err error) {
 	questionList = make([]*entity.Question, 0)
@@ -197,25 +266,14 @@ func (qr *questionRepo) SearchList(ctx context.Context, search *schema.QuestionS
 		search.PageSize = constant.DefaultPageSize
 	}
 	offset := search.Page * search.PageSize
-	session := qr.data.DB.Table("question")
-
-	if len(search.TagIDs) > 0 {
-		session = session.Join("LEFT", "tag_rel", "question.id = tag_rel.object_id")
-		session = session.And("tag_rel.tag_id =?", search.TagIDs[0])
-		// session = session.In("tag_rel.tag_id ", search.TagIDs)
-		session = session.And("tag_rel.status =?", entity.TagRelStatusAvailable)
+	tagIDs := followTagIDs(search.FollowTagIDs)
+	if search.Order == "for_you" && len(tagIDs) > 0 {
+		return qr.forYouSearchList(search, tagIDs, offset)
 	}
+	session := qr.searchSession(search)
 
-	if len(search.UserID) > 0 {
-		session = session.And("question.user_id = ?", search.UserID)
-	}
-
-	session = session.In("question.status", []int{entity.QuestionStatusAvailable, entity.QuestionStatusClosed})
-	// if search.Status > 0 {
-	// 	session = session.And("question.status = ?", search.Status)
-	// }
 	// switch
-	// newest, active,frequent,score,unanswered
+	// newest, active,frequent,score,unanswered,unaccepted,hot,for_you
 	switch search.Order {
 	case "newest":
 		session = session.OrderBy("question.created_at desc")
@@ -228,9 +286,15 @@ func (qr *questionRepo) SearchList(ctx context.Context, search *schema.QuestionS
 	case "unanswered":
 		session = session.And("question.last_answer_id = 0")
 		session = session.OrderBy("question.created_at desc")
+	case "unaccepted":
+		session = session.And("question.accepted_answer_id = 0")
+		session = session.OrderBy("question.created_at desc")
+	case "hot", "for_you":
+		// if the user follows no tag, the for you order is the same as the hot order
+		session = session.OrderBy("question.hot_score desc")
 	}
 	session = session.Limit(search.PageSize, offset)
-	session = session.Select("question.id,question.user_id,last_edit_user_id,question.title,question.original_text,question.parsed_text,question.status,question.view_count,question.unique_view_count,question.vote_count,question.answer_count,question.collection_count,question.follow_count,question.accepted_answer_id,question.last_answer_id,question.created_at,question.updated_at,question.post_update_time,question.revision_id")
+	session = session.Select(searchListColumns)
 	count, err = session.FindAndCount(&rows)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
@@ -239,6 +303,93 @@ func (qr *questionRepo) SearchList(ctx context.Context, search *schema.QuestionS
 	return rows, count, nil
 }
 
+// searchSession the session of the questions which match the conditions of the search
+func (qr *questionRepo) searchSession(search *schema.QuestionSearch) *xorm.Session {
+	session := qr.data.DB.Table("question")
+
+	if len(search.TagIDs) > 0 {
+		session = session.Join("LEFT", "tag_rel", "question.id = tag_rel.object_id")
+		session = session.And("tag_rel.tag_id =?", search.TagIDs[0])
+		// session = session.In("tag_rel.tag_id ", search.TagIDs)
+		session = session.And("tag_rel.status =?", entity.TagRelStatusAvailable)
+	}
+
+	if len(search.UserID) > 0 {
+		session = session.And("question.user_id = ?", search.UserID)
+	}
+
+	// the questions whose language is unknown are shown in all languages
+	if len(search.ContentLanguages) > 0 {
+		session = session.And(builder.Or(
+			builder.In("question.language", search.ContentLanguages),
+			builder.Eq{"question.language": ""},
+		))
+	}
+
+	if len(search.IgnoredTagIDs) > 0 {
+		ignored := builder.Select("object_id").From("tag_rel").
+			Where(builder.In("tag_id", search.IgnoredTagIDs).And(builder.Eq{"status": entity.TagRelStatusAvailable}))
+		session = session.And(builder.NotIn("question.id", ignored))
+	}
+
+	session = session.In("question.status", []int{entity.QuestionStatusAvailable, entity.QuestionStatusClosed})
+	// if search.Status > 0 {
+	// 	session = session.And("question.status = ?", search.Status)
+	// }
+	return session
+}
+
+// followTagIDs the followed tag ids which are numbers, as they are concatenated into the sql
+func followTagIDs(tagIDs []string) []string {
+	ids := make([]string, 0, len(tagIDs))
+	for _, tagID := range tagIDs {
+		if id := converter.StringToInt64(tagID); id > 0 {
+			ids = append(ids, strconv.FormatInt(id, 10))
+		}
+	}
+	return ids
+}
+
+// forYouSearchList the questions ordered by the hot score lifted by the followed tags of the question, the questions
+// without the followed tags are ranked by the hot score. The order by the lifted score can't use the index, so only
+// the candidates which may rank in the page are ordered. The lift is at most maxFollowTagLift, so the question whose
+// hot score is lower than the hot score of the last question of the page in the hot order by more than the max lift
+// can't rank in the page, and the candidates are selected by the index of the hot score.
+func (qr *questionRepo) forYouSearchList(search *schema.QuestionSearch, tagIDs []string, offset int) (
+	rows []*entity.QuestionTag, count int64, err error) {
+	rows = make([]*entity.QuestionTag, 0)
+	last := make([]*entity.Question, 0)
+	err = qr.searchSession(search).Cols("question.hot_score").OrderBy("question.hot_score desc").
+		Limit(1, offset+search.PageSize-1).Find(&last)
+	if err != nil {
+		return rows, 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+
+	session := qr.searchSession(search)
+	// if there are fewer questions than the page, all of them are the candidates
+	if len(last) > 0 {
+		session = session.And("question.hot_score >= ?", last[0].HotScore-maxFollowTagLift)
+	}
+	// the followed tags of each candidate are counted once in the joined table, instead of for each row in the order
+	session = session.Join("LEFT", fmt.Sprintf("(SELECT object_id, COUNT(*) AS follow_tag_count FROM tag_rel "+
+		"WHERE tag_id IN (%s) AND status = %d GROUP BY object_id) ftr",
+		strings.Join(tagIDs, ","), entity.TagRelStatusAvailable), "ftr.object_id = question.id")
+	session = session.OrderBy(fmt.Sprintf("question.hot_score + CASE WHEN ftr.follow_tag_count IS NULL THEN 0 "+
+		"WHEN ftr.follow_tag_count > %d THEN %g ELSE %g * ftr.follow_tag_count END desc",
+		maxFollowTagCount, maxFollowTagLift, followTagWeight))
+	err = session.Limit(search.PageSize, offset).Select(searchListColumns).Find(&rows)
+	if err != nil {
+		return rows, 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+
+	// the questions are counted without the candidate condition
+	count, err = qr.searchSession(search).Count(&entity.Question{})
+	if err != nil {
+		return rows, 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return rows, count, nil
+}
+
 func (qr *questionRepo) CmsSearchList(ctx context.Context, search *schema.CmsQuestionSearch) ([]*entity.Question, int64, error) {
 	var (
 		count   int64
@@ -0,0 +1,69 @@
+package question
+
+import (
+	"context"
+
+	"answer/internal/base/constant"
+	"answer/internal/base/data"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/service/activity_common"
+	"answer/pkg/hotscore"
+	"answer/pkg/obj"
+
+	"github.com/segmentfault/pacman/errors"
+)
+
+// questionScoreRepo question score repository
+type questionScoreRepo struct {
+	data *data.Data
+}
+
+// NewQuestionScoreRepo new repository
+func NewQuestionScoreRepo(data *data.Data) activity_common.QuestionScoreRepo {
+	return &questionScoreRepo{
+		data: data,
+	}
+}
+
+// RefreshHotScore recalculate the hot score of the question, or the question of the answer
+func (qr *questionScoreRepo) RefreshHotScore(ctx context.Context, objectID string) (err error) {
+	objectType, err := obj.GetObjectTypeStrByObjectID(objectID)
+	if err != nil {
+		return nil
+	}
+	questionID := objectID
+	switch objectType {
+	case constant.QuestionObjectType:
+	case constant.AnswerObjectType:
+		answer := &entity.Answer{}
+		exist, err := qr.data.DB.ID(objectID).Cols("question_id").Get(answer)
+		if err != nil {
+			return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+		if !exist {
+			return nil
+		}
+		questionID = answer.QuestionID
+	default:
+		return nil
+	}
+
+	question := &entity.Question{}
+	exist, err := qr.data.DB.ID(questionID).
+		Cols("id", "created_at", "view_count", "vote_count", "answer_count", "post_update_time").Get(question)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if !exist {
+		return nil
+	}
+
+	question.HotScore = hotscore.Score(question.VoteCount, question.AnswerCount, question.ViewCount,
+		question.CreatedAt, question.PostUpdateTime)
+	_, err = qr.data.DB.ID(questionID).Cols("hot_score").Update(question)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
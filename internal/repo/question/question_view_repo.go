@@ -0,0 +1,60 @@
+package question
+
+import (
+	"context"
+
+	"answer/internal/base/constant"
+	"answer/internal/base/data"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/service/question_view"
+
+	"github.com/segmentfault/pacman/errors"
+)
+
+// questionViewRepo question view repository
+type questionViewRepo struct {
+	data *data.Data
+}
+
+// NewQuestionViewRepo new repository
+func NewQuestionViewRepo(data *data.Data) question_view.QuestionViewRepo {
+	return &questionViewRepo{
+		data: data,
+	}
+}
+
+// MarkQuestionViewed mark the question has been viewed by the viewer,
+// return whether the view and the unique view should be counted, which are not counted in their windows yet
+func (qr *questionViewRepo) MarkQuestionViewed(ctx context.Context, questionID, viewer string) (
+	view, uniqueView bool, err error) {
+	viewKey := constant.QuestionViewCacheKey + questionID + ":" + viewer
+	if content, _ := qr.data.Cache.GetString(ctx, viewKey); len(content) > 0 {
+		return false, false, nil
+	}
+	err = qr.data.Cache.SetString(ctx, viewKey, "1", constant.QuestionViewCacheTime)
+	if err != nil {
+		return false, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+
+	uniqueViewKey := constant.QuestionUniqueViewCacheKey + questionID + ":" + viewer
+	if content, _ := qr.data.Cache.GetString(ctx, uniqueViewKey); len(content) > 0 {
+		return true, false, nil
+	}
+	err = qr.data.Cache.SetString(ctx, uniqueViewKey, "1", constant.QuestionUniqueViewCacheTime)
+	if err != nil {
+		return false, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return true, true, nil
+}
+
+// AddViewCount add the view count and the unique view count of the question
+func (qr *questionViewRepo) AddViewCount(ctx context.Context, questionID string, viewCount, uniqueViewCount int) (err error) {
+	_, err = qr.data.DB.Where("id = ?", questionID).
+		Incr("view_count", viewCount).Incr("unique_view_count", uniqueViewCount).
+		Update(&entity.Question{})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
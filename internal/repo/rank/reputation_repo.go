@@ -0,0 +1,80 @@
+package rank
+
+import (
+	"context"
+
+	"answer/internal/base/data"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/service/reputation"
+	"answer/pkg/converter"
+
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// reputationRepo reputation recalculation repository
+type reputationRepo struct {
+	data *data.Data
+}
+
+// NewReputationRepo new repository
+func NewReputationRepo(data *data.Data) reputation.ReputationRepo {
+	return &reputationRepo{
+		data: data,
+	}
+}
+
+// GetUsersAfter get the users whose id is greater than the user id, in the order of the id
+func (rr *reputationRepo) GetUsersAfter(ctx context.Context, afterUserID string, limit int) (
+	users []*entity.User, err error) {
+	users = make([]*entity.User, 0)
+	err = rr.data.DB.Cols("id", "username", "rank").
+		Where("id > ?", converter.StringToInt64(afterUserID)).
+		Asc("id").Limit(limit).Find(&users)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetUserRankActivities get the activities of the user of the activity types which have the reputation rules and
+// are not cancelled, in the order they took effect. They are selected by the type rather than has_rank, so the
+// activities recorded when their rule was 0 are counted if the rule is changed.
+func (rr *reputationRepo) GetUserRankActivities(ctx context.Context, userID string, activityTypes []int) (
+	activities []*entity.Activity, err error) {
+	activities = make([]*entity.Activity, 0)
+	err = rr.data.DB.Where(builder.Eq{"user_id": userID}).
+		And(builder.In("activity_type", activityTypes)).
+		And(builder.Eq{"cancelled": entity.ActivityAvailable}).
+		Asc("updated_at", "id").Find(&activities)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// FixUserRank change the reputation of the user by the delta, and set the rank and has_rank of the activities, in one
+// transaction. The delta is applied to the current reputation, so the reputation gained meanwhile is kept.
+func (rr *reputationRepo) FixUserRank(ctx context.Context, userID string, deltaRank int,
+	activities []*entity.Activity) (err error) {
+	_, err = rr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		// the updated time of the activity is kept, which the daily limit is counted by
+		for _, act := range activities {
+			if _, err = session.ID(act.ID).NoAutoTime().Cols("rank", "has_rank").
+				Update(&entity.Activity{Rank: act.Rank, HasRank: act.HasRank}); err != nil {
+				return nil, err
+			}
+		}
+		if deltaRank == 0 {
+			return nil, nil
+		}
+		_, err = session.ID(userID).Incr("`rank`", deltaRank).Update(&entity.User{})
+		return nil, err
+	})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
@@ -17,6 +17,11 @@ import (
 	"xorm.io/xorm"
 )
 
+const (
+	// ReviewCompleted the config key of the reputation rewarded to the reviewer for completing a review
+	ReviewCompleted = "review.completed"
+)
+
 // UserRankRepo user rank repository
 type UserRankRepo struct {
 	data       *data.Data
@@ -44,7 +49,7 @@ func (ur *UserRankRepo) TriggerUserRank(ctx context.Context,
 	if deltaRank < 0 {
 		// if user rank is lower than 1 after this action, then user rank will be set to 1 only.
 		var isReachMin bool
-		isReachMin, err = ur.checkUserMinRank(ctx, session, userID, activityType)
+		isReachMin, err = ur.checkUserMinRank(ctx, session, userID, deltaRank)
 		if err != nil {
 			return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 		}
@@ -71,6 +76,48 @@ func (ur *UserRankRepo) TriggerUserRank(ctx context.Context,
 	return false, nil
 }
 
+// TriggerReviewRank reward the reviewer for reviewing the object, the reviewer is rewarded only once for each object
+func (ur *UserRankRepo) TriggerReviewRank(ctx context.Context, reviewerID, objectID string) (err error) {
+	activityType, err := ur.configRepo.GetConfigType(ReviewCompleted)
+	if err != nil {
+		return err
+	}
+	deltaRank, err := ur.configRepo.GetInt(ReviewCompleted)
+	if err != nil {
+		return err
+	}
+	_, err = ur.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		exist, err := session.Exist(&entity.Activity{
+			UserID:       reviewerID,
+			ObjectID:     objectID,
+			ActivityType: activityType,
+		})
+		if err != nil {
+			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+		if exist {
+			return nil, nil
+		}
+		_, err = ur.TriggerUserRank(ctx, session, reviewerID, deltaRank, activityType)
+		if err != nil {
+			return nil, err
+		}
+		_, err = session.Insert(&entity.Activity{
+			UserID:           reviewerID,
+			ObjectID:         objectID,
+			OriginalObjectID: "0",
+			ActivityType:     activityType,
+			Rank:             deltaRank,
+			HasRank:          1,
+		})
+		if err != nil {
+			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+		return nil, nil
+	})
+	return err
+}
+
 func (ur *UserRankRepo) checkUserMinRank(ctx context.Context, session *xorm.Session, userID string, deltaRank int) (
 	isReachStandard bool, err error,
 ) {
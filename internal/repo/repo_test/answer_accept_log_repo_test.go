@@ -0,0 +1,107 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"answer/internal/entity"
+	"answer/internal/repo/activity_common"
+	"answer/internal/repo/answer"
+	"answer/internal/repo/config"
+	"answer/internal/repo/question"
+	"answer/internal/repo/rank"
+	"answer/internal/repo/unique"
+	"answer/internal/schema"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_answerRepo_GetAcceptedAnswers(t *testing.T) {
+	uniqueIDRepo := unique.NewUniqueIDRepo(testDataSource)
+	configRepo := config.NewConfigRepo(testDataSource)
+	answerRepo := answer.NewAnswerRepo(testDataSource, uniqueIDRepo, rank.NewUserRankRepo(testDataSource, configRepo),
+		activity_common.NewActivityRepo(testDataSource, uniqueIDRepo, configRepo))
+	questionID := "10010000000000701"
+
+	answers := []*entity.Answer{
+		{ID: "10020000000000701", QuestionID: questionID, UserID: "701", Adopted: schema.AnswerAdoptedEnable,
+			Status: entity.AnswerStatusAvailable},
+		{ID: "10020000000000702", QuestionID: questionID, UserID: "702", Adopted: schema.AnswerAdoptedFailed,
+			Status: entity.AnswerStatusAvailable},
+	}
+	for _, answerInfo := range answers {
+		_, err := testDataSource.DB.Insert(answerInfo)
+		assert.NoError(t, err)
+	}
+
+	// accepting the answer does not unaccept the other one
+	err := answerRepo.UpdateAnswerAdopted(context.TODO(), answers[1].ID, schema.AnswerAdoptedEnable)
+	assert.NoError(t, err)
+	got, err := answerRepo.GetAcceptedAnswers(context.TODO(), questionID)
+	assert.NoError(t, err)
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, answers[0].ID, got[0].ID)
+		assert.Equal(t, answers[1].ID, got[1].ID)
+	}
+
+	err = answerRepo.UpdateAnswerAdopted(context.TODO(), answers[0].ID, schema.AnswerAdoptedFailed)
+	assert.NoError(t, err)
+	got, err = answerRepo.GetAcceptedAnswers(context.TODO(), questionID)
+	assert.NoError(t, err)
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, answers[1].ID, got[0].ID)
+	}
+}
+
+func Test_answerAcceptLogRepo_GetAcceptLogs(t *testing.T) {
+	acceptLogRepo := answer.NewAnswerAcceptLogRepo(testDataSource)
+	questionID := "10010000000000711"
+
+	acceptLogs := []*entity.AnswerAcceptLog{
+		{QuestionID: questionID, AnswerID: "10020000000000711", UserID: "711", Action: entity.AnswerAcceptActionAccept},
+		{QuestionID: questionID, AnswerID: "10020000000000711", UserID: "711", Action: entity.AnswerAcceptActionUnaccept},
+		{QuestionID: questionID, AnswerID: "10020000000000712", UserID: "1", Action: entity.AnswerAcceptActionAccept,
+			OnBehalf: true},
+	}
+	for _, acceptLog := range acceptLogs {
+		err := acceptLogRepo.AddAcceptLog(context.TODO(), acceptLog)
+		assert.NoError(t, err)
+	}
+
+	got, err := acceptLogRepo.GetAcceptLogsByQuestionID(context.TODO(), questionID)
+	assert.NoError(t, err)
+	if assert.Len(t, got, 3) {
+		assert.Equal(t, acceptLogs[2].ID, got[0].ID)
+		assert.True(t, got[0].OnBehalf)
+	}
+
+	got, err = acceptLogRepo.GetAcceptLogsByAnswerID(context.TODO(), "10020000000000711")
+	assert.NoError(t, err)
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, entity.AnswerAcceptActionUnaccept, got[0].Action)
+		assert.Equal(t, entity.AnswerAcceptActionAccept, got[1].Action)
+	}
+}
+
+func Test_questionRepo_ChangeAccepted(t *testing.T) {
+	questionRepo := question.NewQuestionRepo(testDataSource, unique.NewUniqueIDRepo(testDataSource))
+	questionID := "10010000000000702"
+	_, err := testDataSource.DB.Insert(&entity.Question{ID: questionID, UserID: "701", Title: "accept",
+		OriginalText: "accept", ParsedText: "accept", AcceptedAnswerID: "0", RevisionID: "0",
+		Status: entity.QuestionStatusAvailable})
+	assert.NoError(t, err)
+
+	// both requests read no accepted answer, only the first one changes it
+	changed, err := questionRepo.ChangeAccepted(context.TODO(), questionID, "0", "10020000000000703")
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	changed, err = questionRepo.ChangeAccepted(context.TODO(), questionID, "0", "10020000000000704")
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	questionInfo, exist, err := questionRepo.GetQuestion(context.TODO(), questionID)
+	assert.NoError(t, err)
+	if assert.True(t, exist) {
+		assert.Equal(t, "10020000000000703", questionInfo.AcceptedAnswerID)
+	}
+}
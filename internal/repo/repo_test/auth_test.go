@@ -85,3 +85,18 @@ func Test_authRepo_RemoveBackyardUserCacheInfo(t *testing.T) {
 	_, err = authRepo.GetBackyardUserCacheInfo(context.TODO(), token)
 	assert.Error(t, err)
 }
+
+func Test_authRepo_SetUserTokenRevokedAt(t *testing.T) {
+	authRepo := auth.NewAuthRepo(testDataSource)
+
+	revokedAt, err := authRepo.GetUserTokenRevokedAt(context.TODO(), "9001")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), revokedAt)
+
+	err = authRepo.SetUserTokenRevokedAt(context.TODO(), "9001", 1678000000000)
+	assert.NoError(t, err)
+
+	revokedAt, err = authRepo.GetUserTokenRevokedAt(context.TODO(), "9001")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1678000000000), revokedAt)
+}
@@ -0,0 +1,102 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"answer/internal/entity"
+	"answer/internal/repo/collection"
+	"answer/internal/repo/unique"
+	"answer/internal/schema"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_collectionGroupRepo_SortCollectionGroups(t *testing.T) {
+	collectionGroupRepo := collection.NewCollectionGroupRepo(testDataSource)
+
+	first := &entity.CollectionGroup{UserID: "300", Name: "first", DefaultGroup: schema.CGDIY, SortOrder: 1}
+	second := &entity.CollectionGroup{UserID: "300", Name: "second", DefaultGroup: schema.CGDIY, SortOrder: 2}
+	assert.NoError(t, collectionGroupRepo.AddCollectionGroup(context.TODO(), first))
+	assert.NoError(t, collectionGroupRepo.AddCollectionGroup(context.TODO(), second))
+
+	err := collectionGroupRepo.SortCollectionGroups(context.TODO(), "300", []string{second.ID, first.ID})
+	assert.NoError(t, err)
+
+	groups, err := collectionGroupRepo.GetCollectionGroupList(context.TODO(), "300")
+	assert.NoError(t, err)
+	if assert.Len(t, groups, 2) {
+		assert.Equal(t, second.ID, groups[0].ID)
+		assert.Equal(t, first.ID, groups[1].ID)
+	}
+
+	// the groups of others are not reordered
+	err = collectionGroupRepo.SortCollectionGroups(context.TODO(), "301", []string{first.ID, second.ID})
+	assert.NoError(t, err)
+	groups, err = collectionGroupRepo.GetCollectionGroupList(context.TODO(), "300")
+	assert.NoError(t, err)
+	if assert.Len(t, groups, 2) {
+		assert.Equal(t, second.ID, groups[0].ID)
+	}
+}
+
+func Test_collectionGroupRepo_GetPublicCollectionGroupByToken(t *testing.T) {
+	collectionGroupRepo := collection.NewCollectionGroupRepo(testDataSource)
+
+	group := &entity.CollectionGroup{
+		UserID:       "302",
+		Name:         "shared",
+		DefaultGroup: schema.CGDIY,
+		IsPublic:     true,
+		ShareToken:   "collection-share-token",
+	}
+	assert.NoError(t, collectionGroupRepo.AddCollectionGroup(context.TODO(), group))
+
+	got, exist, err := collectionGroupRepo.GetPublicCollectionGroupByToken(context.TODO(), "collection-share-token")
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, group.ID, got.ID)
+
+	// the private group is not shared even if it has the token
+	group.IsPublic = false
+	err = collectionGroupRepo.UpdateCollectionGroup(context.TODO(), group, []string{"is_public"})
+	assert.NoError(t, err)
+	_, exist, err = collectionGroupRepo.GetPublicCollectionGroupByToken(context.TODO(), "collection-share-token")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
+
+func Test_collectionGroupRepo_RemoveCollectionGroup(t *testing.T) {
+	uniqueIDRepo := unique.NewUniqueIDRepo(testDataSource)
+	collectionRepo := collection.NewCollectionRepo(testDataSource, uniqueIDRepo)
+	collectionGroupRepo := collection.NewCollectionGroupRepo(testDataSource)
+
+	defaultGroup, err := collectionGroupRepo.AddCollectionDefaultGroup(context.TODO(), "303")
+	assert.NoError(t, err)
+	group := &entity.CollectionGroup{UserID: "303", Name: "later", DefaultGroup: schema.CGDIY}
+	assert.NoError(t, collectionGroupRepo.AddCollectionGroup(context.TODO(), group))
+
+	for _, objectID := range []string{"10010000000000301", "10010000000000302"} {
+		err = collectionRepo.AddCollection(context.TODO(), &entity.Collection{
+			UserID:                "303",
+			ObjectID:              objectID,
+			UserCollectionGroupID: group.ID,
+		})
+		assert.NoError(t, err)
+	}
+	counts, err := collectionRepo.CountGroupCollections(context.TODO(), "303")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), counts[group.ID])
+
+	err = collectionGroupRepo.RemoveCollectionGroup(context.TODO(), group, defaultGroup.ID)
+	assert.NoError(t, err)
+
+	_, exist, err := collectionGroupRepo.GetCollectionGroup(context.TODO(), group.ID)
+	assert.NoError(t, err)
+	assert.False(t, exist)
+
+	collections, total, err := collectionRepo.GetGroupCollectionPage(context.TODO(), defaultGroup.ID, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, collections, 2)
+}
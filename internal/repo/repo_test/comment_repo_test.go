@@ -80,3 +80,60 @@ func Test_commentRepo_UpdateComment(t *testing.T) {
 	assert.NoError(t, err)
 	return
 }
+
+func Test_commentRepo_GetCommentReplies(t *testing.T) {
+	uniqueIDRepo := unique.NewUniqueIDRepo(testDataSource)
+	commentRepo := comment.NewCommentRepo(testDataSource, uniqueIDRepo)
+	topComment := buildCommentEntity()
+	topComment.ParentCommentID = "0"
+	err := commentRepo.AddComment(context.TODO(), topComment)
+	assert.NoError(t, err)
+
+	replies := make([]*entity.Comment, 0)
+	for i := 0; i < 2; i++ {
+		reply := buildCommentEntity()
+		reply.ParentCommentID, reply.Depth = topComment.ID, 1
+		err = commentRepo.AddComment(context.TODO(), reply)
+		assert.NoError(t, err)
+		replies = append(replies, reply)
+	}
+
+	got, err := commentRepo.GetCommentReplies(context.TODO(), []string{topComment.ID}, 3)
+	assert.NoError(t, err)
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, replies[0].ID, got[0].ID)
+		assert.Equal(t, replies[1].ID, got[1].ID)
+	}
+
+	// only the first replies of each comment are loaded, the count has all of them
+	got, err = commentRepo.GetCommentReplies(context.TODO(), []string{topComment.ID}, 1)
+	assert.NoError(t, err)
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, replies[0].ID, got[0].ID)
+	}
+	counts, err := commentRepo.CountCommentReplies(context.TODO(), []string{topComment.ID})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, counts[topComment.ID])
+
+	// only the top level comments are in the page
+	resp, total, err := commentRepo.GetCommentPage(context.TODO(), &commentService.CommentQuery{
+		PageCond:        pager.PageCond{Page: 1, PageSize: 10},
+		ObjectID:        topComment.ObjectID,
+		ParentCommentID: "0",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, topComment.ID, resp[0].ID)
+
+	// the removed reply is not listed
+	err = commentRepo.RemoveComment(context.TODO(), replies[0].ID)
+	assert.NoError(t, err)
+	got, err = commentRepo.GetCommentReplies(context.TODO(), []string{topComment.ID}, 3)
+	assert.NoError(t, err)
+	assert.Len(t, got, 1)
+
+	for _, c := range append(replies[1:], topComment) {
+		err = commentRepo.RemoveComment(context.TODO(), c.ID)
+		assert.NoError(t, err)
+	}
+}
@@ -0,0 +1,75 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"answer/internal/entity"
+	"answer/internal/repo/draft"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_draftRepo_SaveDraft(t *testing.T) {
+	draftRepo := draft.NewDraftRepo(testDataSource)
+
+	first := &entity.Draft{
+		UserID:    "200",
+		DraftType: entity.DraftTypeAnswer,
+		ObjectID:  "10010000000000001",
+		Content:   "first",
+		Tags:      "[]",
+	}
+	err := draftRepo.SaveDraft(context.TODO(), first)
+	assert.NoError(t, err)
+
+	// the draft of the same context is overwritten
+	second := &entity.Draft{
+		UserID:    "200",
+		DraftType: entity.DraftTypeAnswer,
+		ObjectID:  "10010000000000001",
+		Content:   "second",
+		Tags:      "[]",
+	}
+	err = draftRepo.SaveDraft(context.TODO(), second)
+	assert.NoError(t, err)
+	assert.Equal(t, first.ID, second.ID)
+
+	got, exist, err := draftRepo.GetDraft(context.TODO(), "200", entity.DraftTypeAnswer, "10010000000000001")
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, "second", got.Content)
+
+	drafts, total, err := draftRepo.GetDraftPage(context.TODO(), "200", 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Len(t, drafts, 1)
+
+	err = draftRepo.RemoveDraft(context.TODO(), "200", entity.DraftTypeAnswer, "10010000000000001")
+	assert.NoError(t, err)
+	_, exist, err = draftRepo.GetDraft(context.TODO(), "200", entity.DraftTypeAnswer, "10010000000000001")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
+
+func Test_draftRepo_RemoveExpiredDrafts(t *testing.T) {
+	draftRepo := draft.NewDraftRepo(testDataSource)
+
+	err := draftRepo.SaveDraft(context.TODO(), &entity.Draft{
+		UserID:    "201",
+		DraftType: entity.DraftTypeQuestion,
+		ObjectID:  "0",
+		Title:     "stale question",
+		Tags:      "[]",
+	})
+	assert.NoError(t, err)
+
+	count, err := draftRepo.RemoveExpiredDrafts(context.TODO(), time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	count, err = draftRepo.RemoveExpiredDrafts(context.TODO(), time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
@@ -0,0 +1,111 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"answer/internal/base/constant"
+	"answer/internal/entity"
+	"answer/internal/repo/activity"
+	"answer/internal/repo/activity_common"
+	"answer/internal/repo/config"
+	"answer/internal/repo/unique"
+	"answer/internal/service/feed"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newFeedRepo() (feed.FeedRepo, func(key constant.ActivityTypeKey) int) {
+	activityRepo := activity_common.NewActivityRepo(testDataSource, unique.NewUniqueIDRepo(testDataSource),
+		config.NewConfigRepo(testDataSource))
+	activityType := func(key constant.ActivityTypeKey) int {
+		activityType, _ := activityRepo.GetActivityTypeByConfigKey(context.TODO(), string(key))
+		return activityType
+	}
+	return activity.NewFeedRepo(testDataSource, activityRepo), activityType
+}
+
+func Test_feedRepo_GetFeedActivities(t *testing.T) {
+	feedRepo, activityType := newFeedRepo()
+
+	activities := []*entity.Activity{
+		// the new question by the followed user
+		{UserID: "501", ObjectID: "10010000000000501", OriginalObjectID: "10010000000000501",
+			ActivityType: activityType(constant.ActQuestionAsked)},
+		// the new answer on the followed question
+		{UserID: "502", ObjectID: "10020000000000501", OriginalObjectID: "10010000000000502",
+			ActivityType: activityType(constant.ActQuestionAnswered)},
+		// the new answer on the question which is not followed
+		{UserID: "503", ObjectID: "10020000000000502", OriginalObjectID: "10010000000000503",
+			ActivityType: activityType(constant.ActQuestionAnswered)},
+		// the edit by the user self
+		{UserID: "500", ObjectID: "10010000000000502", OriginalObjectID: "10010000000000502",
+			ActivityType: activityType(constant.ActQuestionEdited)},
+	}
+	for _, act := range activities {
+		_, err := testDataSource.DB.Insert(act)
+		assert.NoError(t, err)
+	}
+
+	search := &entity.FeedSearch{
+		UserID:              "500",
+		FollowedUserIDs:     []string{"501"},
+		FollowedQuestionIDs: []string{"10010000000000502"},
+		Limit:               10,
+	}
+	got, err := feedRepo.GetFeedActivities(context.TODO(), search)
+	assert.NoError(t, err)
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, activities[1].ID, got[0].ID)
+		assert.Equal(t, activities[0].ID, got[1].ID)
+	}
+
+	// the next page after the cursor
+	search.Cursor = activities[1].ID
+	got, err = feedRepo.GetFeedActivities(context.TODO(), search)
+	assert.NoError(t, err)
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, activities[0].ID, got[0].ID)
+	}
+
+	// nothing is followed
+	got, err = feedRepo.GetFeedActivities(context.TODO(), &entity.FeedSearch{UserID: "504", Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, got, 0)
+}
+
+func Test_feedRepo_GetFollowerIDs(t *testing.T) {
+	feedRepo, activityType := newFeedRepo()
+
+	follows := []*entity.Activity{
+		{UserID: "511", ObjectID: "512", ActivityType: activityType(constant.ActUserFollow)},
+		{UserID: "511", ObjectID: "10010000000000511", ActivityType: activityType(constant.ActQuestionFollow)},
+		{UserID: "513", ObjectID: "511", ActivityType: activityType(constant.ActUserFollow),
+			Cancelled: entity.ActivityCancelled},
+	}
+	for _, act := range follows {
+		_, err := testDataSource.DB.Insert(act)
+		assert.NoError(t, err)
+	}
+
+	userIDs, err := feedRepo.GetFollowerIDs(context.TODO(), "510", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"511"}, userIDs)
+}
+
+func Test_feedRepo_AddFeedDigest(t *testing.T) {
+	feedRepo, _ := newFeedRepo()
+
+	added, err := feedRepo.AddFeedDigest(context.TODO(), &entity.FeedDigest{UserID: "520", Period: "2026-10-19", ItemCount: 3})
+	assert.NoError(t, err)
+	assert.True(t, added)
+
+	// the user gets one digest in the same period
+	added, err = feedRepo.AddFeedDigest(context.TODO(), &entity.FeedDigest{UserID: "520", Period: "2026-10-19", ItemCount: 5})
+	assert.NoError(t, err)
+	assert.False(t, added)
+
+	added, err = feedRepo.AddFeedDigest(context.TODO(), &entity.FeedDigest{UserID: "520", Period: "2026-10-26", ItemCount: 1})
+	assert.NoError(t, err)
+	assert.True(t, added)
+}
@@ -0,0 +1,83 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"answer/internal/entity"
+	"answer/internal/repo/mention"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_mentionRepo_GetThreadParticipants(t *testing.T) {
+	mentionRepo := mention.NewMentionRepo(testDataSource)
+	questionID := "10010000000000601"
+
+	_, err := testDataSource.DB.Insert(&entity.Question{ID: questionID, UserID: "601", Title: "mention",
+		Status: entity.QuestionStatusAvailable})
+	assert.NoError(t, err)
+	answers := []*entity.Answer{
+		{ID: "10020000000000601", QuestionID: questionID, UserID: "602", Status: entity.AnswerStatusAvailable},
+		{ID: "10020000000000602", QuestionID: questionID, UserID: "603", Status: entity.AnswerStatusDeleted},
+	}
+	for _, answer := range answers {
+		_, err = testDataSource.DB.Insert(answer)
+		assert.NoError(t, err)
+	}
+	comments := []*entity.Comment{
+		{ID: "10040000000000601", QuestionID: questionID, ObjectID: questionID, UserID: "602",
+			Status: entity.CommentStatusAvailable},
+		{ID: "10040000000000602", QuestionID: questionID, ObjectID: questionID, UserID: "601",
+			Status: entity.CommentStatusAvailable},
+	}
+	for _, comment := range comments {
+		_, err = testDataSource.DB.Insert(comment)
+		assert.NoError(t, err)
+	}
+
+	participants, err := mentionRepo.GetThreadParticipants(context.TODO(), questionID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"601": 2, "602": 2}, participants)
+
+	participants, err = mentionRepo.GetThreadParticipants(context.TODO(), "10010000000000602")
+	assert.NoError(t, err)
+	assert.Len(t, participants, 0)
+}
+
+func Test_mentionRepo_SearchUsers(t *testing.T) {
+	mentionRepo := mention.NewMentionRepo(testDataSource)
+	users := []*entity.User{
+		{Username: "mention_low", EMail: "mention_low@example.com", DisplayName: "Low", Rank: 1,
+			Status: entity.UserStatusAvailable},
+		{Username: "high", EMail: "mention_high@example.com", DisplayName: "Mention High", Rank: 100,
+			Status: entity.UserStatusAvailable},
+		{Username: "mention_deleted", EMail: "mention_deleted@example.com", DisplayName: "Deleted",
+			Status: entity.UserStatusDeleted},
+	}
+	for _, user := range users {
+		_, err := testDataSource.DB.Insert(user)
+		assert.NoError(t, err)
+	}
+
+	got, err := mentionRepo.SearchUsers(context.TODO(), "mention", 10)
+	assert.NoError(t, err)
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, "high", got[0].Username)
+		assert.Equal(t, "mention_low", got[1].Username)
+	}
+
+	got, err = mentionRepo.SearchUsers(context.TODO(), "mention", 1)
+	assert.NoError(t, err)
+	assert.Len(t, got, 1)
+
+	// the wildcards in the keyword are matched literally
+	got, err = mentionRepo.SearchUsers(context.TODO(), "mention_", 10)
+	assert.NoError(t, err)
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, "mention_low", got[0].Username)
+	}
+	got, err = mentionRepo.SearchUsers(context.TODO(), "%low", 10)
+	assert.NoError(t, err)
+	assert.Len(t, got, 0)
+}
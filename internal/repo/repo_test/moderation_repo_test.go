@@ -0,0 +1,64 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"answer/internal/base/constant"
+	"answer/internal/entity"
+	"answer/internal/repo/moderation"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_moderationRepo_ModerationResult(t *testing.T) {
+	moderationRepo := moderation.NewModerationRepo(testDataSource)
+
+	flagged := &entity.ModerationResult{
+		ObjectType: constant.AnswerObjectType,
+		ObjectID:   "10020000000000911",
+		UserID:     "911",
+		Score:      5,
+		Action:     entity.ModerationActionFlag,
+		Hits:       `[{"rule":"link","score":5,"detail":"6 links"}]`,
+		Excerpt:    "check out these links",
+	}
+	err := moderationRepo.AddModerationResult(context.TODO(), flagged)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, flagged.ID)
+
+	rejected := &entity.ModerationResult{
+		ObjectType: constant.QuestionObjectType,
+		ObjectID:   "0",
+		UserID:     "912",
+		Score:      10,
+		Action:     entity.ModerationActionReject,
+		Hits:       `[{"rule":"keyword","score":10,"detail":"casino"}]`,
+		Excerpt:    "best casino",
+	}
+	err = moderationRepo.AddModerationResult(context.TODO(), rejected)
+	assert.NoError(t, err)
+
+	results, total, err := moderationRepo.GetModerationResultPage(context.TODO(), "",
+		entity.ModerationActionReject, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, rejected.ID, results[0].ID)
+
+	results, total, err = moderationRepo.GetModerationResultPage(context.TODO(), flagged.ObjectID, "", 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, entity.ModerationActionFlag, results[0].Action)
+}
+
+func Test_moderationRepo_MarkSeen(t *testing.T) {
+	moderationRepo := moderation.NewModerationRepo(testDataSource)
+
+	seen, err := moderationRepo.MarkSeen(context.TODO(), "fingerprint-of-the-content")
+	assert.NoError(t, err)
+	assert.False(t, seen)
+
+	seen, err = moderationRepo.MarkSeen(context.TODO(), "fingerprint-of-the-content")
+	assert.NoError(t, err)
+	assert.True(t, seen)
+}
@@ -0,0 +1,59 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"answer/internal/entity"
+	"answer/internal/repo/moderator_message"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_moderatorMessageRepo_GetModeratorMessageThread(t *testing.T) {
+	moderatorMessageRepo := moderator_message.NewModeratorMessageRepo(testDataSource)
+
+	root := &entity.ModeratorMessage{
+		ThreadID:    "0",
+		UserID:      "100",
+		SenderID:    "1",
+		TemplateKey: "improve_question",
+		Content:     "please improve your question",
+	}
+	err := moderatorMessageRepo.AddModeratorMessage(context.TODO(), root)
+	assert.NoError(t, err)
+
+	reply := &entity.ModeratorMessage{
+		ThreadID: root.ID,
+		UserID:   "100",
+		SenderID: "100",
+		Content:  "done",
+	}
+	err = moderatorMessageRepo.AddModeratorMessage(context.TODO(), reply)
+	assert.NoError(t, err)
+
+	other := &entity.ModeratorMessage{
+		ThreadID: "0",
+		UserID:   "100",
+		SenderID: "2",
+		Content:  "be nice",
+	}
+	err = moderatorMessageRepo.AddModeratorMessage(context.TODO(), other)
+	assert.NoError(t, err)
+
+	got, exist, err := moderatorMessageRepo.GetModeratorMessage(context.TODO(), root.ID)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, "improve_question", got.TemplateKey)
+
+	thread, err := moderatorMessageRepo.GetModeratorMessageThread(context.TODO(), root.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, thread, 2) {
+		assert.Equal(t, root.ID, thread[0].ID)
+		assert.Equal(t, reply.ID, thread[1].ID)
+	}
+
+	messages, err := moderatorMessageRepo.GetUserModeratorMessages(context.TODO(), "100")
+	assert.NoError(t, err)
+	assert.Len(t, messages, 3)
+}
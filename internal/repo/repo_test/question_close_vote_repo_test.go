@@ -0,0 +1,115 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"answer/internal/entity"
+	"answer/internal/repo/question"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_questionCloseVoteRepo_CloseVote(t *testing.T) {
+	closeVoteRepo := question.NewQuestionCloseVoteRepo(testDataSource)
+	questionID := "10010000000000801"
+	for _, userID := range []string{"801", "802"} {
+		added, err := closeVoteRepo.AddCloseVote(context.TODO(), &entity.QuestionCloseVote{
+			QuestionID: questionID,
+			UserID:     userID,
+			VoteType:   entity.QuestionCloseVoteTypeClose,
+			CloseType:  1,
+			Status:     entity.QuestionCloseVoteStatusActive,
+		}, time.Time{})
+		assert.NoError(t, err)
+		assert.True(t, added)
+	}
+
+	// the user has only one active vote of each type
+	added, err := closeVoteRepo.AddCloseVote(context.TODO(), &entity.QuestionCloseVote{
+		QuestionID: questionID,
+		UserID:     "801",
+		VoteType:   entity.QuestionCloseVoteTypeClose,
+		CloseType:  2,
+		Status:     entity.QuestionCloseVoteStatusActive,
+	}, time.Time{})
+	assert.NoError(t, err)
+	assert.False(t, added)
+
+	votes, err := closeVoteRepo.GetActiveCloseVoteList(context.TODO(), questionID, entity.QuestionCloseVoteTypeClose, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, votes, 2)
+	assert.Equal(t, "801", votes[0].UserID)
+
+	// the votes cast before the since time are expired
+	votes, err = closeVoteRepo.GetActiveCloseVoteList(context.TODO(), questionID, entity.QuestionCloseVoteTypeClose,
+		time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, votes, 0)
+
+	votes, err = closeVoteRepo.GetActiveCloseVoteList(context.TODO(), questionID, entity.QuestionCloseVoteTypeReopen, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, votes, 0)
+
+	err = closeVoteRepo.CompleteCloseVotes(context.TODO(), questionID, entity.QuestionCloseVoteTypeClose)
+	assert.NoError(t, err)
+	votes, err = closeVoteRepo.GetActiveCloseVoteList(context.TODO(), questionID, entity.QuestionCloseVoteTypeClose, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, votes, 0)
+
+	// the completed vote is replaced by the new one
+	added, err = closeVoteRepo.AddCloseVote(context.TODO(), &entity.QuestionCloseVote{
+		QuestionID: questionID,
+		UserID:     "801",
+		VoteType:   entity.QuestionCloseVoteTypeClose,
+		CloseType:  2,
+		Status:     entity.QuestionCloseVoteStatusActive,
+	}, time.Time{})
+	assert.NoError(t, err)
+	assert.True(t, added)
+	votes, err = closeVoteRepo.GetActiveCloseVoteList(context.TODO(), questionID, entity.QuestionCloseVoteTypeClose, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, votes, 1)
+	assert.Equal(t, 2, votes[0].CloseType)
+}
+
+func Test_questionCloseVoteRepo_CloseVoteInTransaction(t *testing.T) {
+	closeVoteRepo := question.NewQuestionCloseVoteRepo(testDataSource)
+	questionID := "10010000000000802"
+	addVote := func(ctx context.Context, userID string) (votes []*entity.QuestionCloseVote, err error) {
+		added, err := closeVoteRepo.AddCloseVote(ctx, &entity.QuestionCloseVote{
+			QuestionID: questionID,
+			UserID:     userID,
+			VoteType:   entity.QuestionCloseVoteTypeClose,
+			Status:     entity.QuestionCloseVoteStatusActive,
+		}, time.Time{})
+		assert.NoError(t, err)
+		assert.True(t, added)
+		return closeVoteRepo.GetActiveCloseVoteList(ctx, questionID, entity.QuestionCloseVoteTypeClose, time.Time{})
+	}
+
+	// the vote is counted right after it is added in the transaction
+	err := testDataSource.Transaction(context.TODO(), func(ctx context.Context) error {
+		votes, err := addVote(ctx, "811")
+		assert.Len(t, votes, 1)
+		return err
+	})
+	assert.NoError(t, err)
+
+	// the vote is rolled back with the transaction which fails to complete the votes
+	err = testDataSource.Transaction(context.TODO(), func(ctx context.Context) error {
+		votes, err := addVote(ctx, "812")
+		assert.Len(t, votes, 2)
+		if err != nil {
+			return err
+		}
+		return assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+	votes, err := closeVoteRepo.GetActiveCloseVoteList(context.TODO(), questionID, entity.QuestionCloseVoteTypeClose, time.Time{})
+	assert.NoError(t, err)
+	if assert.Len(t, votes, 1) {
+		assert.Equal(t, "811", votes[0].UserID)
+	}
+}
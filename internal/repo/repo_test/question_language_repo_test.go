@@ -0,0 +1,44 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"answer/internal/entity"
+	"answer/internal/repo/question"
+	"answer/internal/repo/unique"
+	"answer/internal/schema"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_questionRepo_SearchListByLanguage(t *testing.T) {
+	questionRepo := question.NewQuestionRepo(testDataSource, unique.NewUniqueIDRepo(testDataSource))
+
+	now := time.Now()
+	questions := []*entity.Question{
+		{UserID: "language", Title: "english question", Language: "en", Status: entity.QuestionStatusAvailable},
+		{UserID: "language", Title: "chinese question", Language: "zh", Status: entity.QuestionStatusAvailable},
+		{UserID: "language", Title: "unknown question", Status: entity.QuestionStatusAvailable},
+	}
+	for _, q := range questions {
+		q.CreatedAt, q.UpdatedAt, q.PostUpdateTime = now, now, now
+		assert.NoError(t, questionRepo.AddQuestion(context.TODO(), q))
+	}
+
+	list, _, err := questionRepo.SearchList(context.TODO(), &schema.QuestionSearch{
+		Order: "newest", PageSize: 100, ContentLanguages: []string{"zh"}})
+	assert.NoError(t, err)
+	titles := make(map[string]bool)
+	for _, item := range list {
+		titles[item.Title] = true
+	}
+	assert.True(t, titles["chinese question"])
+	assert.True(t, titles["unknown question"])
+	assert.False(t, titles["english question"])
+
+	list, _, err = questionRepo.SearchList(context.TODO(), &schema.QuestionSearch{Order: "newest", PageSize: 100})
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, len(list), 3)
+}
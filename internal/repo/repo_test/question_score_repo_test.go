@@ -0,0 +1,82 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"answer/internal/entity"
+	"answer/internal/repo/question"
+	"answer/internal/repo/tag"
+	"answer/internal/repo/unique"
+	"answer/internal/schema"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_questionScoreRepo_RefreshHotScore(t *testing.T) {
+	questionRepo := question.NewQuestionRepo(testDataSource, unique.NewUniqueIDRepo(testDataSource))
+	questionScoreRepo := question.NewQuestionScoreRepo(testDataSource)
+	tagRelRepo := tag.NewTagRelRepo(testDataSource)
+
+	now := time.Now()
+	hotQuestion := &entity.Question{
+		UserID: "1", Title: "hot question", Status: entity.QuestionStatusAvailable,
+		VoteCount: 10, AnswerCount: 2, ViewCount: 100, CreatedAt: now, UpdatedAt: now, PostUpdateTime: now,
+	}
+	coldQuestion := &entity.Question{
+		UserID: "1", Title: "cold question", Status: entity.QuestionStatusAvailable,
+		CreatedAt: now, UpdatedAt: now, PostUpdateTime: now,
+	}
+	assert.NoError(t, questionRepo.AddQuestion(context.TODO(), hotQuestion))
+	assert.NoError(t, questionRepo.AddQuestion(context.TODO(), coldQuestion))
+	assert.NoError(t, questionScoreRepo.RefreshHotScore(context.TODO(), hotQuestion.ID))
+	assert.NoError(t, questionScoreRepo.RefreshHotScore(context.TODO(), coldQuestion.ID))
+
+	got, exist, err := questionRepo.GetQuestion(context.TODO(), hotQuestion.ID)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Greater(t, got.HotScore, 0.0)
+	// the score refresh keeps the time of the last activity
+	assert.Equal(t, now.Unix(), got.PostUpdateTime.Unix())
+
+	err = tagRelRepo.AddTagRelList(context.TODO(), []*entity.TagRel{
+		{ObjectID: coldQuestion.ID, TagID: "9001", Status: entity.TagRelStatusAvailable},
+	})
+	assert.NoError(t, err)
+
+	indexOf := func(list []*entity.QuestionTag) (hotIndex, coldIndex int) {
+		hotIndex, coldIndex = -1, -1
+		for i, item := range list {
+			switch item.Question.ID {
+			case hotQuestion.ID:
+				hotIndex = i
+			case coldQuestion.ID:
+				coldIndex = i
+			}
+		}
+		return hotIndex, coldIndex
+	}
+
+	list, _, err := questionRepo.SearchList(context.TODO(), &schema.QuestionSearch{Order: "hot", PageSize: 100})
+	assert.NoError(t, err)
+	hotIndex, coldIndex := indexOf(list)
+	assert.True(t, hotIndex >= 0 && coldIndex >= 0)
+	assert.Less(t, hotIndex, coldIndex)
+
+	// the question of the followed tag is lifted, and the questions without the followed tags are kept
+	list, total, err := questionRepo.SearchList(context.TODO(), &schema.QuestionSearch{Order: "for_you",
+		FollowTagIDs: []string{"9001"}, PageSize: 100})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(list)), total)
+	forYouHotIndex, forYouColdIndex := indexOf(list)
+	assert.True(t, forYouHotIndex >= 0)
+	assert.True(t, forYouColdIndex >= 0 && forYouColdIndex < coldIndex)
+
+	// the candidates of the page are limited by the hot score, but the count is not
+	list, total, err = questionRepo.SearchList(context.TODO(), &schema.QuestionSearch{Order: "for_you",
+		FollowTagIDs: []string{"9001"}, PageSize: 1})
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Greater(t, total, int64(1))
+}
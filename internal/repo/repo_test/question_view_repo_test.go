@@ -0,0 +1,50 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"answer/internal/entity"
+	"answer/internal/repo/question"
+	"answer/internal/repo/unique"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_questionViewRepo_MarkQuestionViewed(t *testing.T) {
+	questionViewRepo := question.NewQuestionViewRepo(testDataSource)
+
+	view, uniqueView, err := questionViewRepo.MarkQuestionViewed(context.TODO(), "10010000000000901", "u1")
+	assert.NoError(t, err)
+	assert.True(t, view)
+	assert.True(t, uniqueView)
+
+	view, uniqueView, err = questionViewRepo.MarkQuestionViewed(context.TODO(), "10010000000000901", "u1")
+	assert.NoError(t, err)
+	assert.False(t, view)
+	assert.False(t, uniqueView)
+
+	view, uniqueView, err = questionViewRepo.MarkQuestionViewed(context.TODO(), "10010000000000901", "u2")
+	assert.NoError(t, err)
+	assert.True(t, view)
+	assert.True(t, uniqueView)
+}
+
+func Test_questionViewRepo_AddViewCount(t *testing.T) {
+	questionRepo := question.NewQuestionRepo(testDataSource, unique.NewUniqueIDRepo(testDataSource))
+	questionViewRepo := question.NewQuestionViewRepo(testDataSource)
+	now := time.Now()
+	q := &entity.Question{UserID: "1", Title: "view count", Status: entity.QuestionStatusAvailable,
+		CreatedAt: now, UpdatedAt: now, PostUpdateTime: now}
+	assert.NoError(t, questionRepo.AddQuestion(context.TODO(), q))
+
+	err := questionViewRepo.AddViewCount(context.TODO(), q.ID, 3, 2)
+	assert.NoError(t, err)
+
+	got, exist, err := questionRepo.GetQuestion(context.TODO(), q.ID)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, 3, got.ViewCount)
+	assert.Equal(t, 2, got.UniqueViewCount)
+}
@@ -0,0 +1,42 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"answer/internal/entity"
+	"answer/internal/repo/answer"
+	"answer/internal/repo/question"
+	"answer/internal/repo/unique"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_questionRepo_AddQuestionWithAnswer(t *testing.T) {
+	uniqueIDRepo := unique.NewUniqueIDRepo(testDataSource)
+	questionRepo := question.NewQuestionRepo(testDataSource, uniqueIDRepo)
+	answerRepo := answer.NewAnswerRepo(testDataSource, uniqueIDRepo, nil, nil)
+
+	now := time.Now()
+	q := &entity.Question{UserID: "wiki", Title: "self answered question", OriginalText: "question",
+		Status: entity.QuestionStatusAvailable, CommunityWiki: true,
+		CreatedAt: now, UpdatedAt: now, PostUpdateTime: now}
+	a := &entity.Answer{UserID: "wiki", OriginalText: "answer", Status: entity.AnswerStatusAvailable,
+		CommunityWiki: true}
+	assert.NoError(t, questionRepo.AddQuestionWithAnswer(context.TODO(), q, a))
+	assert.NotEmpty(t, q.ID)
+	assert.NotEmpty(t, a.ID)
+
+	gotQuestion, exist, err := questionRepo.GetQuestion(context.TODO(), q.ID)
+	assert.NoError(t, err)
+	if assert.True(t, exist) {
+		assert.True(t, gotQuestion.CommunityWiki)
+	}
+	gotAnswer, exist, err := answerRepo.GetByID(context.TODO(), a.ID)
+	assert.NoError(t, err)
+	if assert.True(t, exist) {
+		assert.Equal(t, q.ID, gotAnswer.QuestionID)
+		assert.True(t, gotAnswer.CommunityWiki)
+	}
+}
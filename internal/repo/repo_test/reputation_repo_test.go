@@ -0,0 +1,105 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"answer/internal/entity"
+	"answer/internal/repo/config"
+	"answer/internal/repo/rank"
+	"answer/internal/schema"
+	"answer/internal/service/reputation"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_reputationRepo_RecalculateReputation(t *testing.T) {
+	configRepo := config.NewConfigRepo(testDataSource)
+	reputationRepo := rank.NewReputationRepo(testDataSource)
+	reputationService := reputation.NewReputationService(reputationRepo, configRepo)
+
+	_, err := testDataSource.DB.Insert(&entity.User{ID: "9301", Username: "reputation_9301",
+		EMail: "reputation_9301@example.com", Rank: 50, Status: entity.UserStatusAvailable})
+	assert.NoError(t, err)
+	typeOf := func(key string) int {
+		activityType, err := configRepo.GetConfigType(key)
+		assert.NoError(t, err)
+		return activityType
+	}
+	activities := []*entity.Activity{
+		{ActivityType: typeOf("user.activated"), ObjectID: "0", Rank: 1},
+		// the rule is changed from 5 to 10
+		{ActivityType: typeOf("question.voted_up"), ObjectID: "9311", Rank: 5},
+		{ActivityType: typeOf("answer.voted_down"), ObjectID: "9312", Rank: -2},
+		// the user accepts the own answer, which gains nothing
+		{ActivityType: typeOf("answer.accept"), ObjectID: "9313", Rank: 2},
+		{ActivityType: typeOf("answer.accepted"), ObjectID: "9313", Rank: 15},
+		// the cancelled activity is not counted
+		{ActivityType: typeOf("answer.voted_up"), ObjectID: "9314", Rank: 10, Cancelled: entity.ActivityCancelled},
+		// the rule was 0 when it was voted, which is counted now
+		{ActivityType: typeOf("question.voted_up"), ObjectID: "9315", Rank: 0},
+		// the activity without the reputation rule is not counted
+		{ActivityType: typeOf("question.asked"), ObjectID: "9316", Rank: 0},
+	}
+	for _, act := range activities {
+		act.UserID, act.OriginalObjectID = "9301", "0"
+		if act.Rank != 0 {
+			act.HasRank = 1
+		}
+		_, err = testDataSource.DB.Insert(act)
+		assert.NoError(t, err)
+	}
+
+	// the dry run only reports the difference
+	req := &schema.RecalculateReputationReq{DryRun: true, AfterUserID: "9300", Limit: 1}
+	resp, err := reputationService.RecalculateReputation(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resp.Checked)
+	assert.Equal(t, "9301", resp.NextUserID)
+	if assert.Equal(t, 1, len(resp.Diffs)) {
+		assert.Equal(t, 50, resp.Diffs[0].OldRank)
+		assert.Equal(t, 19, resp.Diffs[0].NewRank)
+		assert.Equal(t, 4, resp.Diffs[0].ChangedActivities)
+	}
+	users, err := reputationRepo.GetUsersAfter(context.TODO(), "9300", 1)
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(users)) {
+		assert.Equal(t, 50, users[0].Rank)
+	}
+
+	req.DryRun = false
+	_, err = reputationService.RecalculateReputation(context.TODO(), req)
+	assert.NoError(t, err)
+	users, err = reputationRepo.GetUsersAfter(context.TODO(), "9300", 1)
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(users)) {
+		assert.Equal(t, 19, users[0].Rank)
+	}
+	rankActivities, err := reputationRepo.GetUserRankActivities(context.TODO(), "9301", []int{
+		typeOf("user.activated"), typeOf("question.voted_up"), typeOf("answer.voted_down"),
+		typeOf("answer.accept"), typeOf("answer.accepted"), typeOf("answer.voted_up"),
+	})
+	assert.NoError(t, err)
+	if assert.Equal(t, 6, len(rankActivities)) {
+		assert.Equal(t, 10, rankActivities[1].Rank)
+		assert.Equal(t, 0, rankActivities[3].Rank)
+		assert.Equal(t, 0, rankActivities[4].Rank)
+		assert.Equal(t, 10, rankActivities[5].Rank)
+		assert.Equal(t, 1, rankActivities[5].HasRank)
+	}
+
+	// nothing differs once it is fixed
+	resp, err = reputationService.RecalculateReputation(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(resp.Diffs))
+
+	// the seeded admin has the reputation of 1 without any activity
+	_, err = testDataSource.DB.Insert(&entity.User{ID: "9302", Username: "reputation_9302",
+		EMail: "reputation_9302@example.com", Rank: 1, Status: entity.UserStatusAvailable, IsAdmin: true})
+	assert.NoError(t, err)
+	resp, err = reputationService.RecalculateReputation(context.TODO(),
+		&schema.RecalculateReputationReq{AfterUserID: "9301", Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resp.Checked)
+	assert.Equal(t, 0, len(resp.Diffs))
+}
@@ -0,0 +1,117 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"answer/internal/base/constant"
+	"answer/internal/entity"
+	"answer/internal/repo/review"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_reviewRepo_ReviewQueue(t *testing.T) {
+	reviewRepo := review.NewReviewRepo(testDataSource)
+	questionType := constant.ObjectTypeStrMapping[constant.QuestionObjectType]
+	answerType := constant.ObjectTypeStrMapping[constant.AnswerObjectType]
+
+	firstPost := &entity.Review{
+		Queue:      entity.ReviewQueueFirstPost,
+		ObjectType: questionType,
+		ObjectID:   "10010000000000901",
+		UserID:     "901",
+		ReviewerID: "0",
+		Hold:       true,
+		Status:     entity.ReviewStatusPending,
+	}
+	err := reviewRepo.AddReview(context.TODO(), firstPost)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, firstPost.ID)
+
+	lateAnswer := &entity.Review{
+		Queue:      entity.ReviewQueueLateAnswer,
+		ObjectType: answerType,
+		ObjectID:   "10020000000000902",
+		UserID:     "902",
+		ReviewerID: "0",
+		Status:     entity.ReviewStatusPending,
+	}
+	err = reviewRepo.AddReview(context.TODO(), lateAnswer)
+	assert.NoError(t, err)
+
+	pending, exist, err := reviewRepo.GetPendingReviewByObjectID(context.TODO(), firstPost.ObjectID)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, firstPost.ID, pending.ID)
+
+	// the reviewer can not review the own post, and only the object types which can be reviewed are counted
+	counts, err := reviewRepo.CountPendingReviews(context.TODO(), "901", []int{questionType, answerType}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), counts[entity.ReviewQueueFirstPost])
+	assert.Equal(t, int64(1), counts[entity.ReviewQueueLateAnswer])
+	counts, err = reviewRepo.CountPendingReviews(context.TODO(), "903", []int{questionType}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), counts[entity.ReviewQueueFirstPost])
+	assert.Equal(t, int64(0), counts[entity.ReviewQueueLateAnswer])
+
+	reviews, total, err := reviewRepo.GetReviewPage(context.TODO(), entity.ReviewQueueFirstPost, "903",
+		[]int{questionType, answerType}, nil, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, firstPost.ID, reviews[0].ID)
+
+	// the skipped review is not shown to the reviewer again
+	err = reviewRepo.AddReviewSkip(context.TODO(), firstPost.ID, "903")
+	assert.NoError(t, err)
+	err = reviewRepo.AddReviewSkip(context.TODO(), firstPost.ID, "903")
+	assert.NoError(t, err)
+	_, total, err = reviewRepo.GetReviewPage(context.TODO(), entity.ReviewQueueFirstPost, "903",
+		[]int{questionType, answerType}, nil, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+
+	// the review can only be completed once
+	completed, err := reviewRepo.CompleteReview(context.TODO(), firstPost.ID, entity.ReviewStatusApproved, "904", "approve")
+	assert.NoError(t, err)
+	assert.True(t, completed)
+	completed, err = reviewRepo.CompleteReview(context.TODO(), firstPost.ID, entity.ReviewStatusRejected, "905", "reject")
+	assert.NoError(t, err)
+	assert.False(t, completed)
+
+	got, exist, err := reviewRepo.GetReview(context.TODO(), firstPost.ID)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, entity.ReviewStatusApproved, got.Status)
+	assert.Equal(t, "904", got.ReviewerID)
+	_, exist, err = reviewRepo.GetPendingReviewByObjectID(context.TODO(), firstPost.ObjectID)
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
+
+func Test_reviewRepo_TagModeratorReviewQueue(t *testing.T) {
+	reviewRepo := review.NewReviewRepo(testDataSource)
+	questionType := constant.ObjectTypeStrMapping[constant.QuestionObjectType]
+
+	_, err := testDataSource.DB.Insert(&entity.TagRel{
+		ObjectID: "10010000000000911", TagID: "911", Status: entity.TagRelStatusAvailable})
+	assert.NoError(t, err)
+	for _, objectID := range []string{"10010000000000911", "10010000000000912"} {
+		err = reviewRepo.AddReview(context.TODO(), &entity.Review{
+			Queue:      entity.ReviewQueueLowQuality,
+			ObjectType: questionType,
+			ObjectID:   objectID,
+			UserID:     "911",
+			ReviewerID: "0",
+			Status:     entity.ReviewStatusPending,
+		})
+		assert.NoError(t, err)
+	}
+
+	// the moderator without the reputation only reviews the questions with the moderated tags
+	reviews, total, err := reviewRepo.GetReviewPage(context.TODO(), entity.ReviewQueueLowQuality, "912",
+		[]int{}, []string{"911"}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "10010000000000911", reviews[0].ObjectID)
+}
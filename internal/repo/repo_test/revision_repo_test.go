@@ -98,3 +98,24 @@ func Test_revisionRepo_GetRevisionList(t *testing.T) {
 	assert.NoError(t, err)
 	assert.GreaterOrEqual(t, len(revs), 1)
 }
+
+func Test_revisionRepo_ReadInTransaction(t *testing.T) {
+	var (
+		uniqueIDRepo = unique.NewUniqueIDRepo(testDataSource)
+		revisionRepo = revision.NewRevisionRepo(testDataSource, uniqueIDRepo)
+	)
+	// the revision added in the transaction is read through the same session before it is committed
+	rev := getRev("10010000000009401", "transaction", "{}")
+	err := testDataSource.Transaction(context.TODO(), func(ctx context.Context) error {
+		if err := revisionRepo.AddRevision(ctx, rev, false); err != nil {
+			return err
+		}
+		got, exist, err := revisionRepo.GetLastRevisionByObjectID(ctx, rev.ObjectID)
+		assert.NoError(t, err)
+		if assert.True(t, exist) {
+			assert.Equal(t, rev.ID, got.ID)
+		}
+		return err
+	})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,159 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"answer/internal/entity"
+	"answer/internal/repo/site_info"
+	"answer/internal/repo/tag"
+	"answer/internal/repo/tag_common"
+	"answer/internal/repo/unique"
+	"answer/internal/schema"
+	"answer/internal/service/siteinfo_common"
+	tagser "answer/internal/service/tag"
+	tagcommonser "answer/internal/service/tag_common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_tagModeratorRepo_AddTagModerator(t *testing.T) {
+	tagModeratorRepo := tag.NewTagModeratorRepo(testDataSource)
+	err := tagModeratorRepo.AddTagModerator(context.TODO(), &entity.TagModerator{
+		TagID: "9101", UserID: "9111", Source: entity.TagModeratorSourceManual, OperatorID: "1"})
+	assert.NoError(t, err)
+	err = tagModeratorRepo.AddTagModerator(context.TODO(), &entity.TagModerator{
+		TagID: "9101", UserID: "9111", Source: entity.TagModeratorSourceManual, OperatorID: "1"})
+	assert.NoError(t, err)
+
+	moderators, err := tagModeratorRepo.GetTagModeratorList(context.TODO(), "9101")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(moderators))
+
+	exist, err := tagModeratorRepo.ExistTagModerator(context.TODO(), "9111", []string{"9100", "9101"})
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	exist, err = tagModeratorRepo.ExistTagModerator(context.TODO(), "9112", []string{"9101"})
+	assert.NoError(t, err)
+	assert.False(t, exist)
+
+	err = tagModeratorRepo.RemoveTagModerator(context.TODO(), "9101", "9111", "1")
+	assert.NoError(t, err)
+	moderators, err = tagModeratorRepo.GetUserTagModeratorList(context.TODO(), "9111")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(moderators))
+	exist, err = tagModeratorRepo.ExistTagModerator(context.TODO(), "9111", []string{"9101"})
+	assert.NoError(t, err)
+	assert.False(t, exist)
+
+	// the removed user is not assigned automatically again
+	err = tagModeratorRepo.ReplaceAutoTagModerators(context.TODO(), []*entity.TagModerator{
+		{TagID: "9101", UserID: "9111", Source: entity.TagModeratorSourceAuto},
+	})
+	assert.NoError(t, err)
+	moderators, err = tagModeratorRepo.GetTagModeratorList(context.TODO(), "9101")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(moderators))
+
+	// but the admin can assign the user again
+	err = tagModeratorRepo.AddTagModerator(context.TODO(), &entity.TagModerator{
+		TagID: "9101", UserID: "9111", Source: entity.TagModeratorSourceManual, OperatorID: "1"})
+	assert.NoError(t, err)
+	moderators, err = tagModeratorRepo.GetTagModeratorList(context.TODO(), "9101")
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(moderators)) {
+		assert.Equal(t, entity.TagModeratorSourceManual, moderators[0].Source)
+	}
+}
+
+func Test_tagModeratorRepo_ReplaceAutoTagModerators(t *testing.T) {
+	tagModeratorRepo := tag.NewTagModeratorRepo(testDataSource)
+	err := tagModeratorRepo.AddTagModerator(context.TODO(), &entity.TagModerator{
+		TagID: "9102", UserID: "9121", Source: entity.TagModeratorSourceManual, OperatorID: "1"})
+	assert.NoError(t, err)
+	err = tagModeratorRepo.ReplaceAutoTagModerators(context.TODO(), []*entity.TagModerator{
+		{TagID: "9102", UserID: "9121", Source: entity.TagModeratorSourceAuto},
+		{TagID: "9102", UserID: "9122", Source: entity.TagModeratorSourceAuto},
+	})
+	assert.NoError(t, err)
+
+	// the manual moderator is kept as it is, and the auto one which is not in the top any more is removed
+	err = tagModeratorRepo.ReplaceAutoTagModerators(context.TODO(), []*entity.TagModerator{
+		{TagID: "9102", UserID: "9123", Source: entity.TagModeratorSourceAuto},
+	})
+	assert.NoError(t, err)
+	moderators, err := tagModeratorRepo.GetTagModeratorList(context.TODO(), "9102")
+	assert.NoError(t, err)
+	if assert.Equal(t, 2, len(moderators)) {
+		assert.Equal(t, "9121", moderators[0].UserID)
+		assert.Equal(t, entity.TagModeratorSourceManual, moderators[0].Source)
+		assert.Equal(t, "9123", moderators[1].UserID)
+		assert.Equal(t, entity.TagModeratorSourceAuto, moderators[1].Source)
+	}
+}
+
+func Test_tagModeratorRepo_GetTagAnswerScores(t *testing.T) {
+	_, err := testDataSource.DB.Insert(&entity.TagRel{ObjectID: "9131", TagID: "9103", Status: entity.TagRelStatusAvailable})
+	assert.NoError(t, err)
+	answers := []*entity.Answer{
+		{ID: "9141", QuestionID: "9131", UserID: "9151", VoteCount: 3, Status: entity.AnswerStatusAvailable},
+		{ID: "9142", QuestionID: "9131", UserID: "9152", VoteCount: 5, Status: entity.AnswerStatusAvailable},
+		{ID: "9143", QuestionID: "9131", UserID: "9153", VoteCount: 1, Status: entity.AnswerStatusAvailable},
+		{ID: "9144", QuestionID: "9131", UserID: "9154", VoteCount: 9, Status: entity.AnswerStatusDeleted},
+		{ID: "9145", QuestionID: "9131", UserID: "9155", VoteCount: 9, Status: entity.AnswerStatusAvailable,
+			CommunityWiki: true},
+	}
+	for _, answer := range answers {
+		_, err = testDataSource.DB.Insert(answer)
+		assert.NoError(t, err)
+	}
+
+	tagModeratorRepo := tag.NewTagModeratorRepo(testDataSource)
+	scores, err := tagModeratorRepo.GetTagAnswerScores(context.TODO(), 2)
+	assert.NoError(t, err)
+	userIDs := make([]string, 0)
+	for _, score := range scores {
+		if score.TagID == "9103" {
+			userIDs = append(userIDs, score.UserID)
+		}
+	}
+	assert.Equal(t, []string{"9152", "9151"}, userIDs)
+}
+
+func Test_tagService_UpdateTagSynonymByTagModerator(t *testing.T) {
+	uniqueIDRepo := unique.NewUniqueIDRepo(testDataSource)
+	tagCommonService := tagcommonser.NewTagCommonService(tag_common.NewTagCommonRepo(testDataSource, uniqueIDRepo),
+		tag.NewTagRelRepo(testDataSource), tag.NewTagRepo(testDataSource, uniqueIDRepo), nil,
+		siteinfo_common.NewSiteInfoCommonService(site_info.NewSiteInfo(testDataSource)))
+	tagService := tagser.NewTagService(tag.NewTagRepo(testDataSource, uniqueIDRepo), tagCommonService,
+		nil, nil, nil, nil, nil)
+	for _, item := range []*entity.Tag{
+		{ID: "9131", SlugName: "moderated_9131"},
+		{ID: "9132", SlugName: "other_9132"},
+		{ID: "9133", SlugName: "other_synonym_9133", MainTagID: 9132},
+	} {
+		item.DisplayName, item.Status = item.SlugName, entity.TagStatusAvailable
+		_, err := testDataSource.DB.Insert(item)
+		assert.NoError(t, err)
+	}
+
+	// the moderator of the tag can't take the other tag or the synonym of the other tag
+	for _, slugName := range []string{"other_9132", "other_synonym_9133"} {
+		err := tagService.UpdateTagSynonym(context.TODO(), &schema.UpdateTagSynonymReq{
+			TagID:            "9131",
+			SynonymTagList:   []*schema.TagItem{{SlugName: slugName}},
+			UserID:           "9141",
+			OnlyTagModerator: true,
+		})
+		assert.Error(t, err)
+	}
+	for _, item := range []struct {
+		id        string
+		mainTagID int64
+	}{{"9132", 0}, {"9133", 9132}} {
+		tagInfo := &entity.Tag{}
+		_, err := testDataSource.DB.ID(item.id).Get(tagInfo)
+		assert.NoError(t, err)
+		assert.Equal(t, item.mainTagID, tagInfo.MainTagID)
+	}
+}
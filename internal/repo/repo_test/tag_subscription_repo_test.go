@@ -0,0 +1,179 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"answer/internal/base/scheduler"
+	"answer/internal/entity"
+	"answer/internal/repo/site_info"
+	"answer/internal/repo/tag"
+	"answer/internal/repo/tag_common"
+	"answer/internal/repo/unique"
+	"answer/internal/schema"
+	"answer/internal/service/siteinfo_common"
+	tagcommonser "answer/internal/service/tag_common"
+	"answer/internal/service/tag_subscription"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_tagSubscriptionRepo_GetCandidateSubscriptions(t *testing.T) {
+	tagSubscriptionRepo := tag.NewTagSubscriptionRepo(testDataSource)
+	goSubscription := &entity.TagSubscription{UserID: "9201", Expression: "go AND NOT beginner",
+		Frequency: entity.TagSubscriptionFrequencyInstant}
+	err := tagSubscriptionRepo.AddSubscription(context.TODO(), goSubscription, []string{"go"})
+	assert.NoError(t, err)
+	err = tagSubscriptionRepo.AddSubscription(context.TODO(), &entity.TagSubscription{UserID: "9202",
+		Expression: "golang OR rust", Frequency: entity.TagSubscriptionFrequencyDaily}, []string{"golang", "rust"})
+	assert.NoError(t, err)
+
+	// the tag is matched as a whole, "go" does not match "golang"
+	subscriptions, err := tagSubscriptionRepo.GetCandidateSubscriptions(context.TODO(), []string{"go"})
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(subscriptions)) {
+		assert.Equal(t, "9201", subscriptions[0].UserID)
+	}
+	subscriptions, err = tagSubscriptionRepo.GetCandidateSubscriptions(context.TODO(), []string{"go", "rust"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(subscriptions))
+
+	goSubscription.Expression = "python"
+	err = tagSubscriptionRepo.UpdateSubscription(context.TODO(), goSubscription, []string{"python"})
+	assert.NoError(t, err)
+	subscriptions, err = tagSubscriptionRepo.GetCandidateSubscriptions(context.TODO(), []string{"go"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(subscriptions))
+
+	// the wildcards in the tag name are matched literally
+	err = tagSubscriptionRepo.AddSubscription(context.TODO(), &entity.TagSubscription{UserID: "9203",
+		Expression: "c_lang", Frequency: entity.TagSubscriptionFrequencyDaily}, []string{"c_lang"})
+	assert.NoError(t, err)
+	subscriptions, err = tagSubscriptionRepo.GetCandidateSubscriptions(context.TODO(), []string{"c_lang"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(subscriptions))
+	subscriptions, err = tagSubscriptionRepo.GetCandidateSubscriptions(context.TODO(), []string{"c%"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(subscriptions))
+	subscriptions, err = tagSubscriptionRepo.GetCandidateSubscriptions(context.TODO(), []string{"cxlang"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(subscriptions))
+
+	err = tagSubscriptionRepo.RemoveSubscription(context.TODO(), goSubscription.ID)
+	assert.NoError(t, err)
+	subscriptions, err = tagSubscriptionRepo.GetUserSubscriptions(context.TODO(), "9201")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(subscriptions))
+	exist, err := testDataSource.DB.Where("subscription_id = ?", goSubscription.ID).Exist(&entity.TagSubscriptionTag{})
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
+
+func Test_tagSubscriptionService_ResolveSynonyms(t *testing.T) {
+	uniqueIDRepo := unique.NewUniqueIDRepo(testDataSource)
+	tagCommonService := tagcommonser.NewTagCommonService(tag_common.NewTagCommonRepo(testDataSource, uniqueIDRepo),
+		tag.NewTagRelRepo(testDataSource), tag.NewTagRepo(testDataSource, uniqueIDRepo), nil,
+		siteinfo_common.NewSiteInfoCommonService(site_info.NewSiteInfo(testDataSource)))
+	tagSubscriptionRepo := tag.NewTagSubscriptionRepo(testDataSource)
+	s, _ := scheduler.NewScheduler()
+	tagSubscriptionService := tag_subscription.NewTagSubscriptionService(tagSubscriptionRepo, tagCommonService,
+		nil, nil, nil, s)
+	for _, item := range []*entity.Tag{
+		{ID: "9241", SlugName: "go_9241"},
+		{ID: "9242", SlugName: "golang_9242", MainTagID: 9241, MainTagSlugName: "go_9241"},
+	} {
+		item.DisplayName, item.Status = item.SlugName, entity.TagStatusAvailable
+		_, err := testDataSource.DB.Insert(item)
+		assert.NoError(t, err)
+	}
+
+	// the questions are tagged with the main tag, so the synonym in the expression is replaced by it
+	resp, err := tagSubscriptionService.AddSubscription(context.TODO(), &schema.AddTagSubscriptionReq{
+		Expression: "golang_9242 AND NOT beginner", Frequency: entity.TagSubscriptionFrequencyDaily, UserID: "9243"})
+	assert.NoError(t, err)
+	assert.Equal(t, "go_9241 AND NOT beginner", resp.Expression)
+	subscriptions, err := tagSubscriptionRepo.GetCandidateSubscriptions(context.TODO(), []string{"go_9241"})
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(subscriptions)) {
+		assert.Equal(t, "9243", subscriptions[0].UserID)
+	}
+
+	err = tagSubscriptionService.UpdateIgnoredTags(context.TODO(), &schema.UpdateIgnoredTagsReq{
+		TagNames: []string{"golang_9242"}, UserID: "9243"})
+	assert.NoError(t, err)
+	tagIDs, err := tagSubscriptionRepo.GetIgnoredTagIDs(context.TODO(), "9243")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"9241"}, tagIDs)
+}
+
+func Test_tagSubscriptionRepo_Alerts(t *testing.T) {
+	tagSubscriptionRepo := tag.NewTagSubscriptionRepo(testDataSource)
+	alert := &entity.TagAlert{UserID: "9211", QuestionID: "9291", Expression: "go",
+		Frequency: entity.TagSubscriptionFrequencyDaily, Status: entity.TagAlertStatusPending}
+	added, err := tagSubscriptionRepo.AddAlert(context.TODO(), alert)
+	assert.NoError(t, err)
+	assert.True(t, added)
+	// the user is alerted once for each question
+	added, err = tagSubscriptionRepo.AddAlert(context.TODO(), &entity.TagAlert{UserID: "9211", QuestionID: "9291",
+		Expression: "go", Frequency: entity.TagSubscriptionFrequencyInstant, Status: entity.TagAlertStatusPending})
+	assert.NoError(t, err)
+	assert.False(t, added)
+
+	userIDs, err := tagSubscriptionRepo.GetPendingAlertUserIDs(context.TODO(), "9210", 100)
+	assert.NoError(t, err)
+	assert.Contains(t, userIDs, "9211")
+	alerts, err := tagSubscriptionRepo.GetPendingAlerts(context.TODO(), "9211")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(alerts))
+
+	claimedIDs, err := tagSubscriptionRepo.ClaimAlerts(context.TODO(), []string{alert.ID})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{alert.ID}, claimedIDs)
+	alerts, err = tagSubscriptionRepo.GetPendingAlerts(context.TODO(), "9211")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(alerts))
+	// the alert which is already sent is not claimed again
+	claimedIDs, err = tagSubscriptionRepo.ClaimAlerts(context.TODO(), []string{alert.ID})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(claimedIDs))
+}
+
+func Test_tagSubscriptionRepo_AlertSetting(t *testing.T) {
+	tagSubscriptionRepo := tag.NewTagSubscriptionRepo(testDataSource)
+	_, exist, err := tagSubscriptionRepo.GetAlertSetting(context.TODO(), "9221")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+
+	lastDigestAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	err = tagSubscriptionRepo.UpdateLastDigestAt(context.TODO(), "9221", lastDigestAt)
+	assert.NoError(t, err)
+	err = tagSubscriptionRepo.SaveAlertSetting(context.TODO(), &entity.TagAlertSetting{
+		UserID: "9221", TimeZone: "Asia/Shanghai", QuietHoursStart: 22, QuietHoursEnd: 7})
+	assert.NoError(t, err)
+
+	// saving the quiet hours keeps the time of the last batched alerts
+	setting, exist, err := tagSubscriptionRepo.GetAlertSetting(context.TODO(), "9221")
+	assert.NoError(t, err)
+	if assert.True(t, exist) {
+		assert.Equal(t, "Asia/Shanghai", setting.TimeZone)
+		assert.Equal(t, 22, setting.QuietHoursStart)
+		assert.Equal(t, 7, setting.QuietHoursEnd)
+		assert.Equal(t, lastDigestAt.Unix(), setting.LastDigestAt.Unix())
+	}
+}
+
+func Test_tagSubscriptionRepo_ReplaceIgnoredTags(t *testing.T) {
+	tagSubscriptionRepo := tag.NewTagSubscriptionRepo(testDataSource)
+	err := tagSubscriptionRepo.ReplaceIgnoredTags(context.TODO(), "9231", []string{"9281", "9282", "9281"})
+	assert.NoError(t, err)
+	tagIDs, err := tagSubscriptionRepo.GetIgnoredTagIDs(context.TODO(), "9231")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"9281", "9282"}, tagIDs)
+
+	err = tagSubscriptionRepo.ReplaceIgnoredTags(context.TODO(), "9231", []string{"9283"})
+	assert.NoError(t, err)
+	tagIDs, err = tagSubscriptionRepo.GetIgnoredTagIDs(context.TODO(), "9231")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"9283"}, tagIDs)
+}
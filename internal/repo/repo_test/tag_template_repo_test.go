@@ -0,0 +1,49 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"answer/internal/entity"
+	"answer/internal/repo/tag"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_tagTemplateRepo_SaveTagTemplate(t *testing.T) {
+	tagTemplateRepo := tag.NewTagTemplateRepo(testDataSource)
+	err := tagTemplateRepo.SaveTagTemplate(context.TODO(), &entity.TagTemplate{
+		TagID:            "9001",
+		UserID:           "1",
+		Content:          "## Version\n\n## Logs\n",
+		RequiredSections: []string{"Version", "Logs"},
+	})
+	assert.NoError(t, err)
+
+	template, exist, err := tagTemplateRepo.GetTagTemplate(context.TODO(), "9001")
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, []string{"Version", "Logs"}, template.RequiredSections)
+
+	err = tagTemplateRepo.SaveTagTemplate(context.TODO(), &entity.TagTemplate{
+		TagID:            "9001",
+		UserID:           "2",
+		Content:          "## Steps\n",
+		RequiredSections: []string{"Steps"},
+	})
+	assert.NoError(t, err)
+
+	templates, err := tagTemplateRepo.GetTagTemplateList(context.TODO(), []string{"9001", "9002"})
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(templates)) {
+		assert.Equal(t, "2", templates[0].UserID)
+		assert.Equal(t, "## Steps\n", templates[0].Content)
+		assert.Equal(t, []string{"Steps"}, templates[0].RequiredSections)
+	}
+
+	err = tagTemplateRepo.RemoveTagTemplate(context.TODO(), "9001")
+	assert.NoError(t, err)
+	_, exist, err = tagTemplateRepo.GetTagTemplate(context.TODO(), "9001")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
@@ -0,0 +1,137 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"answer/internal/entity"
+	"answer/internal/repo/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_userDataRepo_GetGhostUser(t *testing.T) {
+	userDataRepo := user.NewUserDataRepo(testDataSource)
+	ghost, err := userDataRepo.GetGhostUser(context.TODO())
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ghost.ID)
+	assert.Equal(t, entity.UserStatusDeleted, ghost.Status)
+
+	got, err := userDataRepo.GetGhostUser(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, ghost.ID, got.ID)
+}
+
+func Test_userDeletionRepo_GetDueUserDeletions(t *testing.T) {
+	userDeletionRepo := user.NewUserDeletionRepo(testDataSource)
+	deletion := &entity.UserDeletion{
+		UserID:    "100",
+		Mode:      entity.UserDeletionModeAnonymize,
+		ExecuteAt: time.Now().Add(time.Hour),
+		Status:    entity.UserDeletionStatusPending,
+	}
+	err := userDeletionRepo.AddUserDeletion(context.TODO(), deletion)
+	assert.NoError(t, err)
+
+	got, exist, err := userDeletionRepo.GetPendingUserDeletion(context.TODO(), deletion.UserID)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, deletion.ID, got.ID)
+
+	deletions, err := userDeletionRepo.GetDueUserDeletions(context.TODO(), time.Now())
+	assert.NoError(t, err)
+	assert.Len(t, deletions, 0)
+
+	deletions, err = userDeletionRepo.GetDueUserDeletions(context.TODO(), time.Now().Add(2*time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, deletions, 1)
+
+	err = userDeletionRepo.UpdateUserDeletionStatus(context.TODO(), deletion.ID, entity.UserDeletionStatusCancelled)
+	assert.NoError(t, err)
+
+	_, exist, err = userDeletionRepo.GetPendingUserDeletion(context.TODO(), deletion.UserID)
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
+
+func Test_userDataRepo_RemoveUserContent(t *testing.T) {
+	userDataRepo := user.NewUserDataRepo(testDataSource)
+	_, err := testDataSource.DB.Insert(
+		&entity.Question{ID: "9501", UserID: "9511", Title: "removed question", Status: entity.QuestionStatusAvailable},
+		&entity.Question{ID: "9502", UserID: "9512", Title: "kept question", AnswerCount: 2,
+			Status: entity.QuestionStatusAvailable},
+		&entity.Answer{ID: "9521", QuestionID: "9502", UserID: "9511", Status: entity.AnswerStatusAvailable},
+		&entity.Answer{ID: "9522", QuestionID: "9502", UserID: "9512", Status: entity.AnswerStatusAvailable},
+		&entity.Tag{ID: "9531", SlugName: "removed-content", DisplayName: "removed-content", QuestionCount: 2,
+			Status: entity.TagStatusAvailable},
+		&entity.TagRel{ObjectID: "9501", TagID: "9531", Status: entity.TagRelStatusAvailable},
+		&entity.TagRel{ObjectID: "9502", TagID: "9531", Status: entity.TagRelStatusAvailable},
+	)
+	assert.NoError(t, err)
+
+	err = userDataRepo.RemoveUserContent(context.TODO(), "9511")
+	assert.NoError(t, err)
+
+	removedQuestion := &entity.Question{}
+	_, err = testDataSource.DB.ID("9501").Get(removedQuestion)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.QuestionStatusDeleted, removedQuestion.Status)
+	keptQuestion := &entity.Question{}
+	_, err = testDataSource.DB.ID("9502").Get(keptQuestion)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, keptQuestion.AnswerCount)
+	tagInfo := &entity.Tag{}
+	_, err = testDataSource.DB.ID("9531").Get(tagInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, tagInfo.QuestionCount)
+}
+
+func Test_userDataExportRepo_FailPendingUserDataExports(t *testing.T) {
+	userDataExportRepo := user.NewUserDataExportRepo(testDataSource)
+	export := &entity.UserDataExport{UserID: "9401", Status: entity.UserDataExportStatusPending}
+	err := userDataExportRepo.AddUserDataExport(context.TODO(), export)
+	assert.NoError(t, err)
+
+	// the export pending after the time is kept
+	err = userDataExportRepo.FailPendingUserDataExports(context.TODO(), time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	got, exist, err := userDataExportRepo.GetUserDataExport(context.TODO(), "9401")
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, entity.UserDataExportStatusPending, got.Status)
+
+	// the export pending before the server restarted is never completed
+	err = userDataExportRepo.FailPendingUserDataExports(context.TODO(), time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	got, exist, err = userDataExportRepo.GetUserDataExport(context.TODO(), "9401")
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, entity.UserDataExportStatusFailed, got.Status)
+
+	// the new export replaces the previous one
+	err = userDataExportRepo.AddUserDataExport(context.TODO(),
+		&entity.UserDataExport{UserID: "9401", Status: entity.UserDataExportStatusPending})
+	assert.NoError(t, err)
+	got, exist, err = userDataExportRepo.GetUserDataExport(context.TODO(), "9401")
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, entity.UserDataExportStatusPending, got.Status)
+	assert.NotEqual(t, export.ID, got.ID)
+
+	got.Status, got.FileName, got.ExpiredAt = entity.UserDataExportStatusCompleted, "9401.zip", time.Now().Add(time.Hour)
+	err = userDataExportRepo.UpdateUserDataExport(context.TODO(), got)
+	assert.NoError(t, err)
+	err = userDataExportRepo.FailPendingUserDataExports(context.TODO(), time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	got, _, err = userDataExportRepo.GetUserDataExport(context.TODO(), "9401")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.UserDataExportStatusCompleted, got.Status)
+	assert.Equal(t, "9401.zip", got.FileName)
+
+	err = userDataExportRepo.RemoveUserDataExport(context.TODO(), "9401")
+	assert.NoError(t, err)
+	_, exist, err = userDataExportRepo.GetUserDataExport(context.TODO(), "9401")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
@@ -0,0 +1,44 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"answer/internal/entity"
+	"answer/internal/repo/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_userInviteRepo_AddUserWithInvite(t *testing.T) {
+	userInviteRepo := user.NewUserInviteRepo(testDataSource)
+	invite := &entity.UserInvite{
+		Code:          "test-invite-register",
+		CreatorUserID: "1",
+		ExpiredAt:     time.Now().Add(time.Hour),
+		Status:        entity.UserInviteStatusAvailable,
+	}
+	err := userInviteRepo.AddUserInvite(context.TODO(), invite)
+	assert.NoError(t, err)
+
+	invited := &entity.User{Username: "invited_user", EMail: "invited_user@example.com",
+		Status: entity.UserStatusAvailable}
+	ok, err := userInviteRepo.AddUserWithInvite(context.TODO(), invite.Code, invited)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	got, exist, err := userInviteRepo.GetUserInviteByCode(context.TODO(), invite.Code)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, entity.UserInviteStatusUsed, got.Status)
+	assert.Equal(t, invited.ID, got.UsedUserID)
+
+	// the used invite adds no user
+	ok, err = userInviteRepo.AddUserWithInvite(context.TODO(), invite.Code, &entity.User{
+		Username: "invited_user_again", EMail: "invited_user_again@example.com", Status: entity.UserStatusAvailable})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	exist, err = testDataSource.DB.Where("username = ?", "invited_user_again").Exist(&entity.User{})
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
@@ -0,0 +1,43 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"answer/internal/entity"
+	"answer/internal/repo/config"
+	"answer/internal/repo/rank"
+
+	"github.com/stretchr/testify/assert"
+	"xorm.io/xorm"
+)
+
+func Test_userRankRepo_TriggerUserRank(t *testing.T) {
+	configRepo := config.NewConfigRepo(testDataSource)
+	userRankRepo := rank.NewUserRankRepo(testDataSource, configRepo)
+	activityType, err := configRepo.GetConfigType("answer.voted_down")
+	assert.NoError(t, err)
+
+	_, err = testDataSource.DB.Insert(&entity.User{ID: "9401", Username: "rank_9401",
+		EMail: "rank_9401@example.com", Rank: 10, Status: entity.UserStatusAvailable})
+	assert.NoError(t, err)
+	trigger := func(deltaRank int) {
+		_, err := testDataSource.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+			return userRankRepo.TriggerUserRank(context.TODO(), session, "9401", deltaRank, activityType)
+		})
+		assert.NoError(t, err)
+	}
+	getRank := func() int {
+		userInfo := &entity.User{}
+		_, err := testDataSource.DB.ID("9401").Cols("rank").Get(userInfo)
+		assert.NoError(t, err)
+		return userInfo.Rank
+	}
+
+	// the reputation is checked against the delta, not the activity type
+	trigger(-2)
+	assert.Equal(t, 8, getRank())
+	// the reputation never drops below 1
+	trigger(-20)
+	assert.Equal(t, 1, getRank())
+}
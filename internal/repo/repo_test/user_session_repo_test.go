@@ -0,0 +1,55 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"answer/internal/entity"
+	"answer/internal/repo/auth"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_userSessionRepo_UserSession(t *testing.T) {
+	userSessionRepo := auth.NewUserSessionRepo(testDataSource)
+	session := &entity.UserSession{
+		UserID:     "1",
+		Device:     "Chrome on Linux",
+		IP:         "127.0.0.1",
+		LastSeenAt: time.Now(),
+	}
+	err := userSessionRepo.AddUserSession(context.TODO(), session)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, session.ID)
+
+	err = userSessionRepo.UpdateLastSeen(context.TODO(), session.ID, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+
+	sessions, err := userSessionRepo.GetUserSessionList(context.TODO(), "1")
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 1)
+	assert.Equal(t, "Chrome on Linux", sessions[0].Device)
+
+	err = userSessionRepo.RemoveUserSession(context.TODO(), session.ID)
+	assert.NoError(t, err)
+	_, exist, err := userSessionRepo.GetUserSession(context.TODO(), session.ID)
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
+
+func Test_userSessionRepo_SessionToken(t *testing.T) {
+	userSessionRepo := auth.NewUserSessionRepo(testDataSource)
+	err := userSessionRepo.SetSessionToken(context.TODO(), "1", "token")
+	assert.NoError(t, err)
+
+	accessToken, err := userSessionRepo.GetSessionToken(context.TODO(), "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "token", accessToken)
+
+	err = userSessionRepo.RemoveSessionToken(context.TODO(), "1")
+	assert.NoError(t, err)
+	accessToken, err = userSessionRepo.GetSessionToken(context.TODO(), "1")
+	assert.NoError(t, err)
+	assert.Empty(t, accessToken)
+}
@@ -0,0 +1,102 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"answer/internal/entity"
+	"answer/internal/repo/auth"
+	"answer/internal/repo/config"
+	"answer/internal/repo/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_userSuspensionRepo_LiftUserSuspension(t *testing.T) {
+	userRepo := user.NewUserRepo(testDataSource, config.NewConfigRepo(testDataSource))
+	userSuspensionRepo := user.NewUserSuspensionRepo(testDataSource, auth.NewAuthRepo(testDataSource))
+
+	userInfo := &entity.User{
+		Username:    "suspended",
+		Pass:        "suspended",
+		EMail:       "suspended@spam.example.com",
+		MailStatus:  entity.EmailStatusAvailable,
+		Status:      entity.UserStatusSuspended,
+		DisplayName: "suspended",
+	}
+	err := userRepo.AddUser(context.TODO(), userInfo)
+	assert.NoError(t, err)
+
+	suspension := &entity.UserSuspension{
+		UserID:     userInfo.ID,
+		OperatorID: "1",
+		Reason:     "spam",
+		Message:    "spam links",
+		Days:       1,
+		ExpiredAt:  time.Now().Add(-time.Minute),
+		Status:     entity.UserSuspensionStatusActive,
+	}
+	err = userSuspensionRepo.AddUserSuspension(context.TODO(), suspension)
+	assert.NoError(t, err)
+
+	count, err := userSuspensionRepo.CountUserSuspensions(context.TODO(), userInfo.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	active, exist, err := userSuspensionRepo.GetActiveUserSuspension(context.TODO(), userInfo.ID)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, suspension.ID, active.ID)
+
+	due, err := userSuspensionRepo.GetDueUserSuspensions(context.TODO(), time.Now())
+	assert.NoError(t, err)
+	assert.Len(t, due, 1)
+
+	lifted, err := userSuspensionRepo.LiftUserSuspension(context.TODO(), suspension, entity.UserSuspensionStatusExpired)
+	assert.NoError(t, err)
+	assert.True(t, lifted)
+
+	// the suspension is only lifted once
+	lifted, err = userSuspensionRepo.LiftUserSuspension(context.TODO(), suspension, entity.UserSuspensionStatusExpired)
+	assert.NoError(t, err)
+	assert.False(t, lifted)
+
+	got, _, err := userRepo.GetByUserID(context.TODO(), userInfo.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.UserStatusAvailable, got.Status)
+
+	_, exist, err = userSuspensionRepo.GetActiveUserSuspension(context.TODO(), userInfo.ID)
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
+
+func Test_userSuspensionRepo_UserBan(t *testing.T) {
+	userSuspensionRepo := user.NewUserSuspensionRepo(testDataSource, auth.NewAuthRepo(testDataSource))
+
+	ban := &entity.UserBan{BanType: entity.UserBanTypeEmailDomain, Value: "spam.example.com", UserID: "0"}
+	err := userSuspensionRepo.AddUserBan(context.TODO(), ban)
+	assert.NoError(t, err)
+	// the existing ban is not added again
+	err = userSuspensionRepo.AddUserBan(context.TODO(),
+		&entity.UserBan{BanType: entity.UserBanTypeEmailDomain, Value: "spam.example.com", UserID: "0"})
+	assert.NoError(t, err)
+
+	bans, total, err := userSuspensionRepo.GetUserBanPage(context.TODO(), entity.UserBanTypeEmailDomain, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, ban.ID, bans[0].ID)
+
+	banned, err := userSuspensionRepo.HasUserBan(context.TODO(), entity.UserBanTypeEmailDomain,
+		[]string{"mail.spam.example.com", "spam.example.com", "example.com"})
+	assert.NoError(t, err)
+	assert.True(t, banned)
+
+	banned, err = userSuspensionRepo.HasUserBan(context.TODO(), entity.UserBanTypeIP, []string{"spam.example.com"})
+	assert.NoError(t, err)
+	assert.False(t, banned)
+
+	removed, err := userSuspensionRepo.RemoveUserBan(context.TODO(), ban.ID)
+	assert.NoError(t, err)
+	assert.True(t, removed)
+}
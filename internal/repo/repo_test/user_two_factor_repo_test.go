@@ -0,0 +1,110 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"answer/internal/entity"
+	"answer/internal/repo/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_userTwoFactorRepo_SaveUserTwoFactor(t *testing.T) {
+	userTwoFactorRepo := user.NewUserTwoFactorRepo(testDataSource)
+	twoFactor := &entity.UserTwoFactor{
+		UserID:        "1",
+		Secret:        "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+		RecoveryCodes: "[]",
+	}
+	err := userTwoFactorRepo.SaveUserTwoFactor(context.TODO(), twoFactor)
+	assert.NoError(t, err)
+
+	twoFactor.Enabled = true
+	twoFactor.RecoveryCodes = `["a","b"]`
+	err = userTwoFactorRepo.SaveUserTwoFactor(context.TODO(), twoFactor)
+	assert.NoError(t, err)
+
+	got, exist, err := userTwoFactorRepo.GetUserTwoFactor(context.TODO(), "1")
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.True(t, got.Enabled)
+	assert.Equal(t, `["a","b"]`, got.RecoveryCodes)
+
+	err = userTwoFactorRepo.UpdateRecoveryCodes(context.TODO(), "1", `["b"]`)
+	assert.NoError(t, err)
+	got, _, err = userTwoFactorRepo.GetUserTwoFactor(context.TODO(), "1")
+	assert.NoError(t, err)
+	assert.Equal(t, `["b"]`, got.RecoveryCodes)
+
+	// the codes are used only once, the second request which read the same codes fails
+	used, err := userTwoFactorRepo.UseRecoveryCodes(context.TODO(), "1", `["b"]`, `[]`)
+	assert.NoError(t, err)
+	assert.True(t, used)
+	used, err = userTwoFactorRepo.UseRecoveryCodes(context.TODO(), "1", `["b"]`, `[]`)
+	assert.NoError(t, err)
+	assert.False(t, used)
+
+	err = userTwoFactorRepo.RemoveUserTwoFactor(context.TODO(), "1")
+	assert.NoError(t, err)
+	_, exist, err = userTwoFactorRepo.GetUserTwoFactor(context.TODO(), "1")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
+
+func Test_userTwoFactorRepo_UseTimeStep(t *testing.T) {
+	userTwoFactorRepo := user.NewUserTwoFactorRepo(testDataSource)
+	err := userTwoFactorRepo.SaveUserTwoFactor(context.TODO(), &entity.UserTwoFactor{
+		UserID:        "2",
+		Secret:        "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+		RecoveryCodes: "[]",
+	})
+	assert.NoError(t, err)
+
+	used, err := userTwoFactorRepo.UseTimeStep(context.TODO(), "2", 100)
+	assert.NoError(t, err)
+	assert.True(t, used)
+	// the same step or an earlier one is not accepted again
+	used, err = userTwoFactorRepo.UseTimeStep(context.TODO(), "2", 100)
+	assert.NoError(t, err)
+	assert.False(t, used)
+	used, err = userTwoFactorRepo.UseTimeStep(context.TODO(), "2", 99)
+	assert.NoError(t, err)
+	assert.False(t, used)
+	used, err = userTwoFactorRepo.UseTimeStep(context.TODO(), "2", 101)
+	assert.NoError(t, err)
+	assert.True(t, used)
+}
+
+func Test_userTwoFactorRepo_PendingLogin(t *testing.T) {
+	userTwoFactorRepo := user.NewUserTwoFactorRepo(testDataSource)
+	err := userTwoFactorRepo.AddPendingLogin(context.TODO(), &entity.UserTwoFactorPending{
+		Token: "token", UserID: "1", ExpiredAt: time.Now().Add(time.Minute)})
+	assert.NoError(t, err)
+
+	pending, exist, err := userTwoFactorRepo.GetPendingLogin(context.TODO(), "token")
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, "1", pending.UserID)
+
+	// no more attempts are taken than allowed
+	for i := 0; i < 2; i++ {
+		increased, err := userTwoFactorRepo.IncreasePendingAttempts(context.TODO(), "token", 2)
+		assert.NoError(t, err)
+		assert.True(t, increased)
+	}
+	increased, err := userTwoFactorRepo.IncreasePendingAttempts(context.TODO(), "token", 2)
+	assert.NoError(t, err)
+	assert.False(t, increased)
+
+	removed, err := userTwoFactorRepo.RemovePendingLogin(context.TODO(), "token")
+	assert.NoError(t, err)
+	assert.True(t, removed)
+	removed, err = userTwoFactorRepo.RemovePendingLogin(context.TODO(), "token")
+	assert.NoError(t, err)
+	assert.False(t, removed)
+	_, exist, err = userTwoFactorRepo.GetPendingLogin(context.TODO(), "token")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
@@ -36,7 +36,7 @@ func (rr *reportRepo) AddReport(ctx context.Context, report *entity.Report) (err
 	if err != nil {
 		return err
 	}
-	_, err = rr.data.DB.Insert(report)
+	_, err = rr.data.Session(ctx).Insert(report)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -0,0 +1,136 @@
+package review
+
+import (
+	"context"
+
+	"answer/internal/base/constant"
+	"answer/internal/base/data"
+	"answer/internal/base/pager"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/service/review"
+
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+)
+
+// reviewRepo review repository
+type reviewRepo struct {
+	data *data.Data
+}
+
+// NewReviewRepo new repository
+func NewReviewRepo(data *data.Data) review.ReviewRepo {
+	return &reviewRepo{
+		data: data,
+	}
+}
+
+// AddReview add review
+func (rr *reviewRepo) AddReview(ctx context.Context, review *entity.Review) (err error) {
+	_, err = rr.data.Session(ctx).Insert(review)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// GetReview get review by id
+func (rr *reviewRepo) GetReview(ctx context.Context, id string) (review *entity.Review, exist bool, err error) {
+	review = &entity.Review{}
+	exist, err = rr.data.DB.ID(id).Get(review)
+	if err != nil {
+		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetPendingReviewByObjectID get the pending review of the object
+func (rr *reviewRepo) GetPendingReviewByObjectID(ctx context.Context, objectID string) (
+	review *entity.Review, exist bool, err error) {
+	review = &entity.Review{}
+	exist, err = rr.data.Session(ctx).Where("object_id = ?", objectID).
+		And("status = ?", entity.ReviewStatusPending).Get(review)
+	if err != nil {
+		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// CompleteReview complete the pending review, completed is false if the review has been completed by others
+func (rr *reviewRepo) CompleteReview(ctx context.Context, id string, status int, reviewerID, action string) (
+	completed bool, err error) {
+	affected, err := rr.data.DB.Where("id = ?", id).And("status = ?", entity.ReviewStatusPending).
+		Cols("status", "reviewer_id", "action").
+		Update(&entity.Review{Status: status, ReviewerID: reviewerID, Action: action})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected > 0, nil
+}
+
+// GetReviewPage get the pending reviews in the queue which the reviewer can review
+func (rr *reviewRepo) GetReviewPage(ctx context.Context, queue, reviewerID string, objectTypes []int,
+	tagIDs []string, page, pageSize int) (reviews []*entity.Review, total int64, err error) {
+	reviews = make([]*entity.Review, 0)
+	session := rr.data.DB.Where(rr.pendingCond(reviewerID, objectTypes, tagIDs)).Asc("created_at")
+	total, err = pager.Help(page, pageSize, &reviews, &entity.Review{Queue: queue}, session)
+	if err != nil {
+		return nil, 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// CountPendingReviews count the pending reviews of each queue which the reviewer can review
+func (rr *reviewRepo) CountPendingReviews(ctx context.Context, reviewerID string, objectTypes []int,
+	tagIDs []string) (counts map[string]int64, err error) {
+	rows := make([]*struct {
+		Queue string `xorm:"queue"`
+		Count int64  `xorm:"count"`
+	}, 0)
+	err = rr.data.DB.Table(entity.Review{}.TableName()).Select("queue, count(*) AS count").
+		Where(rr.pendingCond(reviewerID, objectTypes, tagIDs)).GroupBy("queue").Find(&rows)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	counts = make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Queue] = row.Count
+	}
+	return counts, nil
+}
+
+// AddReviewSkip the reviewer skip the review
+func (rr *reviewRepo) AddReviewSkip(ctx context.Context, reviewID, userID string) (err error) {
+	exist, err := rr.data.DB.Exist(&entity.ReviewSkip{ReviewID: reviewID, UserID: userID})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if exist {
+		return nil
+	}
+	_, err = rr.data.DB.Insert(&entity.ReviewSkip{ReviewID: reviewID, UserID: userID})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// pendingCond the pending reviews which are not posted or skipped by the reviewer,
+// the questions with the tags which the reviewer moderates are included whatever the object types are
+func (rr *reviewRepo) pendingCond(reviewerID string, objectTypes []int, tagIDs []string) builder.Cond {
+	skipped := builder.Select("review_id").From(entity.ReviewSkip{}.TableName()).
+		Where(builder.Eq{"user_id": reviewerID})
+	objectCond := builder.In("object_type", objectTypes)
+	if len(tagIDs) > 0 {
+		tagged := builder.Select("object_id").From(entity.TagRel{}.TableName()).
+			Where(builder.In("tag_id", tagIDs).And(builder.Eq{"status": entity.TagRelStatusAvailable}))
+		objectCond = builder.Or(objectCond, builder.Eq{
+			"object_type": constant.ObjectTypeStrMapping[constant.QuestionObjectType],
+		}.And(builder.In("object_id", tagged)))
+	}
+	return builder.Eq{"status": entity.ReviewStatusPending}.
+		And(objectCond).
+		And(builder.Neq{"user_id": reviewerID}).
+		And(builder.NotIn("id", skipped))
+}
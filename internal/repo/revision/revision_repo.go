@@ -46,23 +46,17 @@ func (rr *revisionRepo) AddRevision(ctx context.Context, revision *entity.Revisi
 	if !rr.allowRecord(revision.ObjectType) {
 		return nil
 	}
-	_, err = rr.data.DB.Transaction(func(session *xorm.Session) (interface{}, error) {
+	return rr.data.Transaction(ctx, func(ctx context.Context) error {
+		session := rr.data.Session(ctx)
 		_, err = session.Insert(revision)
 		if err != nil {
-			_ = session.Rollback()
-			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+			return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 		}
 		if autoUpdateRevisionID {
-			err = rr.UpdateObjectRevisionId(ctx, revision, session)
-			if err != nil {
-				_ = session.Rollback()
-				return nil, err
-			}
+			return rr.UpdateObjectRevisionId(ctx, revision, session)
 		}
-		return nil, nil
+		return nil
 	})
-
-	return err
 }
 
 // UpdateObjectRevisionId updates the object.revision_id field
@@ -91,7 +85,7 @@ func (rr *revisionRepo) UpdateStatus(ctx context.Context, id string, status int,
 	data.ID = id
 	data.Status = status
 	data.ReviewUserID = converter.StringToInt64(reviewUserID)
-	_, err = rr.data.DB.Where("id =?", id).Cols("status", "review_user_id").Update(&data)
+	_, err = rr.data.Session(ctx).Where("id =?", id).Cols("status", "review_user_id").Update(&data)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -103,7 +97,7 @@ func (rr *revisionRepo) GetRevision(ctx context.Context, id string) (
 	revision *entity.Revision, exist bool, err error,
 ) {
 	revision = &entity.Revision{}
-	exist, err = rr.data.DB.ID(id).Get(revision)
+	exist, err = rr.data.Session(ctx).ID(id).Get(revision)
 	if err != nil {
 		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -114,7 +108,7 @@ func (rr *revisionRepo) GetRevision(ctx context.Context, id string) (
 func (rr *revisionRepo) GetRevisionByID(ctx context.Context, revisionID string) (
 	revision *entity.Revision, exist bool, err error) {
 	revision = &entity.Revision{}
-	exist, err = rr.data.DB.Where("id = ?", revisionID).Get(revision)
+	exist, err = rr.data.Session(ctx).Where("id = ?", revisionID).Get(revision)
 	if err != nil {
 		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -124,7 +118,7 @@ func (rr *revisionRepo) GetRevisionByID(ctx context.Context, revisionID string)
 func (rr *revisionRepo) ExistUnreviewedByObjectID(ctx context.Context, objectID string) (
 	revision *entity.Revision, exist bool, err error) {
 	revision = &entity.Revision{}
-	exist, err = rr.data.DB.Where("object_id = ?", objectID).And("status = ?", entity.RevisionUnreviewedStatus).Get(revision)
+	exist, err = rr.data.Session(ctx).Where("object_id = ?", objectID).And("status = ?", entity.RevisionUnreviewedStatus).Get(revision)
 	if err != nil {
 		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -136,7 +130,7 @@ func (rr *revisionRepo) GetLastRevisionByObjectID(ctx context.Context, objectID
 	revision *entity.Revision, exist bool, err error,
 ) {
 	revision = &entity.Revision{}
-	exist, err = rr.data.DB.Where("object_id = ?", objectID).OrderBy("created_at DESC").Get(revision)
+	exist, err = rr.data.Session(ctx).Where("object_id = ?", objectID).OrderBy("created_at DESC").Get(revision)
 	if err != nil {
 		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -146,7 +140,7 @@ func (rr *revisionRepo) GetLastRevisionByObjectID(ctx context.Context, objectID
 // GetRevisionList get revision list all
 func (rr *revisionRepo) GetRevisionList(ctx context.Context, revision *entity.Revision) (revisionList []entity.Revision, err error) {
 	revisionList = []entity.Revision{}
-	err = rr.data.DB.Where(builder.Eq{
+	err = rr.data.Session(ctx).Where(builder.Eq{
 		"object_id": revision.ObjectID,
 	}).OrderBy("created_at DESC").Find(&revisionList)
 	if err != nil {
@@ -164,6 +158,8 @@ func (rr *revisionRepo) allowRecord(objectType int) (ok bool) {
 		return true
 	case constant.ObjectTypeStrMapping["tag"]:
 		return true
+	case constant.ObjectTypeStrMapping["comment"]:
+		return true
 	default:
 		return false
 	}
@@ -176,7 +172,7 @@ func (rr *revisionRepo) GetUnreviewedRevisionPage(ctx context.Context, page int,
 	if len(objectTypeList) == 0 {
 		return revisionList, 0, nil
 	}
-	session := rr.data.DB.NewSession()
+	session := rr.data.Session(ctx)
 	session = session.And("status = ?", entity.RevisionUnreviewedStatus)
 	session = session.In("object_type", objectTypeList)
 	session = session.OrderBy("created_at asc")
@@ -69,7 +69,7 @@ func NewSearchRepo(data *data.Data, uniqueIDRepo unique.UniqueIDRepo, userCommon
 }
 
 // SearchContents search question and answer data
-func (sr *searchRepo) SearchContents(ctx context.Context, words []string, tagIDs []string, userID string, votes int, page, size int, order string) (resp []schema.SearchResp, total int64, err error) {
+func (sr *searchRepo) SearchContents(ctx context.Context, words []string, tagIDs []string, userID string, votes int, languages []string, page, size int, order string) (resp []schema.SearchResp, total int64, err error) {
 	if words = filterWords(words); len(words) == 0 {
 		return
 	}
@@ -90,12 +90,12 @@ func (sr *searchRepo) SearchContents(ctx context.Context, words []string, tagIDs
 	ub = builder.MySQL().Select(afs...).From("`answer`").
 		LeftJoin("`question`", "`question`.id = `answer`.question_id")
 
-	b.Where(builder.Lt{"`question`.`status`": entity.QuestionStatusDeleted})
-	ub.Where(builder.Lt{"`question`.`status`": entity.QuestionStatusDeleted}).
-		And(builder.Lt{"`answer`.`status`": entity.AnswerStatusDeleted})
+	b.Where(builder.In("`question`.`status`", entity.QuestionStatusAvailable, entity.QuestionStatusClosed))
+	ub.Where(builder.In("`question`.`status`", entity.QuestionStatusAvailable, entity.QuestionStatusClosed)).
+		And(builder.Eq{"`answer`.`status`": entity.AnswerStatusAvailable})
 
-	argsQ = append(argsQ, entity.QuestionStatusDeleted)
-	argsA = append(argsA, entity.QuestionStatusDeleted, entity.AnswerStatusDeleted)
+	argsQ = append(argsQ, entity.QuestionStatusAvailable, entity.QuestionStatusClosed)
+	argsA = append(argsA, entity.QuestionStatusAvailable, entity.QuestionStatusClosed, entity.AnswerStatusAvailable)
 
 	for i, word := range words {
 		if i == 0 {
@@ -147,6 +147,14 @@ func (sr *searchRepo) SearchContents(ctx context.Context, words []string, tagIDs
 		argsA = append(argsA, votes)
 	}
 
+	// check language
+	if len(languages) > 0 {
+		b.Where(languageCond("`question`.`language`", languages))
+		ub.Where(languageCond("`answer`.`language`", languages))
+		argsQ = append(argsQ, languageArgs(languages)...)
+		argsA = append(argsA, languageArgs(languages)...)
+	}
+
 	//b = b.Union("all", ub)
 	ubSQL, _, err := ub.ToSQL()
 	if err != nil {
@@ -198,7 +206,7 @@ func (sr *searchRepo) SearchContents(ctx context.Context, words []string, tagIDs
 }
 
 // SearchQuestions search question data
-func (sr *searchRepo) SearchQuestions(ctx context.Context, words []string, notAccepted bool, views, answers int, page, size int, order string) (resp []schema.SearchResp, total int64, err error) {
+func (sr *searchRepo) SearchQuestions(ctx context.Context, words []string, notAccepted bool, views, answers int, languages []string, page, size int, order string) (resp []schema.SearchResp, total int64, err error) {
 	words = filterWords(words)
 	var (
 		qfs  = qFields
@@ -214,8 +222,8 @@ func (sr *searchRepo) SearchQuestions(ctx context.Context, words []string, notAc
 
 	b := builder.MySQL().Select(qfs...).From("question")
 
-	b.Where(builder.Lt{"`question`.`status`": entity.QuestionStatusDeleted})
-	args = append(args, entity.QuestionStatusDeleted)
+	b.Where(builder.In("`question`.`status`", entity.QuestionStatusAvailable, entity.QuestionStatusClosed))
+	args = append(args, entity.QuestionStatusAvailable, entity.QuestionStatusClosed)
 
 	for i, word := range words {
 		if i == 0 {
@@ -258,6 +266,12 @@ func (sr *searchRepo) SearchQuestions(ctx context.Context, words []string, notAc
 		args = append(args, answers)
 	}
 
+	// check language
+	if len(languages) > 0 {
+		b.And(languageCond("`question`.`language`", languages))
+		args = append(args, languageArgs(languages)...)
+	}
+
 	queryArgs := []interface{}{}
 	countArgs := []interface{}{}
 
@@ -297,7 +311,7 @@ func (sr *searchRepo) SearchQuestions(ctx context.Context, words []string, notAc
 }
 
 // SearchAnswers search answer data
-func (sr *searchRepo) SearchAnswers(ctx context.Context, words []string, tagIDs []string, accepted bool, questionID string, page, size int, order string) (resp []schema.SearchResp, total int64, err error) {
+func (sr *searchRepo) SearchAnswers(ctx context.Context, words []string, tagIDs []string, accepted bool, questionID string, languages []string, page, size int, order string) (resp []schema.SearchResp, total int64, err error) {
 	words = filterWords(words)
 
 	var (
@@ -315,9 +329,9 @@ func (sr *searchRepo) SearchAnswers(ctx context.Context, words []string, tagIDs
 	b := builder.MySQL().Select(afs...).From("`answer`").
 		LeftJoin("`question`", "`question`.id = `answer`.question_id")
 
-	b.Where(builder.Lt{"`question`.`status`": entity.QuestionStatusDeleted}).
-		And(builder.Lt{"`answer`.`status`": entity.AnswerStatusDeleted})
-	args = append(args, entity.QuestionStatusDeleted, entity.AnswerStatusDeleted)
+	b.Where(builder.In("`question`.`status`", entity.QuestionStatusAvailable, entity.QuestionStatusClosed)).
+		And(builder.Eq{"`answer`.`status`": entity.AnswerStatusAvailable})
+	args = append(args, entity.QuestionStatusAvailable, entity.QuestionStatusClosed, entity.AnswerStatusAvailable)
 
 	for i, word := range words {
 		if i == 0 {
@@ -351,6 +365,12 @@ func (sr *searchRepo) SearchAnswers(ctx context.Context, words []string, tagIDs
 		args = append(args, questionID)
 	}
 
+	// check language
+	if len(languages) > 0 {
+		b.Where(languageCond("`answer`.`language`", languages))
+		args = append(args, languageArgs(languages)...)
+	}
+
 	queryArgs := []interface{}{}
 	countArgs := []interface{}{}
 
@@ -514,6 +534,19 @@ func addRelevanceField(searchFields, words, fields []string) (res []string, args
 	return
 }
 
+// languageCond the content in the languages, or whose language is unknown
+func languageCond(field string, languages []string) builder.Cond {
+	return builder.Or(builder.In(field, languages), builder.Eq{field: ""})
+}
+
+// languageArgs the args of the language condition
+func languageArgs(languages []string) (args []interface{}) {
+	for _, language := range languages {
+		args = append(args, language)
+	}
+	return append(args, "")
+}
+
 func filterWords(words []string) (res []string) {
 	for _, word := range words {
 		if strings.TrimSpace(word) != "" {
@@ -0,0 +1,157 @@
+package tag
+
+import (
+	"context"
+	"fmt"
+
+	"answer/internal/base/data"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/service/tag_moderator"
+
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// tagModeratorRepo tag moderator repository
+type tagModeratorRepo struct {
+	data *data.Data
+}
+
+// NewTagModeratorRepo new repository
+func NewTagModeratorRepo(data *data.Data) tag_moderator.TagModeratorRepo {
+	return &tagModeratorRepo{
+		data: data,
+	}
+}
+
+// AddTagModerator add the moderator of the tag, the automatic assignment becomes a manual one if it is assigned again
+func (tr *tagModeratorRepo) AddTagModerator(ctx context.Context, moderator *entity.TagModerator) (err error) {
+	old := &entity.TagModerator{}
+	exist, err := tr.data.DB.Where("tag_id = ? AND user_id = ?", moderator.TagID, moderator.UserID).Get(old)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if exist {
+		if old.Source == moderator.Source {
+			return nil
+		}
+		_, err = tr.data.DB.ID(old.ID).Cols("source", "operator_id").Update(moderator)
+	} else {
+		_, err = tr.data.DB.Insert(moderator)
+	}
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// RemoveTagModerator remove the moderator of the tag. The removal is kept, so that the user is not assigned
+// automatically again, until the admin assigns the user again.
+func (tr *tagModeratorRepo) RemoveTagModerator(ctx context.Context, tagID, userID, operatorID string) (err error) {
+	return tr.AddTagModerator(ctx, &entity.TagModerator{
+		TagID:      tagID,
+		UserID:     userID,
+		Source:     entity.TagModeratorSourceRemoved,
+		OperatorID: operatorID,
+	})
+}
+
+// GetTagModeratorList get the moderators of the tag, the earliest assigned first
+func (tr *tagModeratorRepo) GetTagModeratorList(ctx context.Context, tagID string) (
+	moderators []*entity.TagModerator, err error) {
+	moderators = make([]*entity.TagModerator, 0)
+	err = tr.data.DB.Where("tag_id = ?", tagID).And("source <> ?", entity.TagModeratorSourceRemoved).
+		Asc("id").Find(&moderators)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetUserTagModeratorList get the tags which the user moderates
+func (tr *tagModeratorRepo) GetUserTagModeratorList(ctx context.Context, userID string) (
+	moderators []*entity.TagModerator, err error) {
+	moderators = make([]*entity.TagModerator, 0)
+	err = tr.data.DB.Where("user_id = ?", userID).And("source <> ?", entity.TagModeratorSourceRemoved).
+		Asc("id").Find(&moderators)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// ExistTagModerator whether the user moderates any of the tags
+func (tr *tagModeratorRepo) ExistTagModerator(ctx context.Context, userID string, tagIDs []string) (
+	exist bool, err error) {
+	if len(tagIDs) == 0 {
+		return false, nil
+	}
+	exist, err = tr.data.DB.Where("user_id = ?", userID).In("tag_id", tagIDs).
+		And("source <> ?", entity.TagModeratorSourceRemoved).Exist(&entity.TagModerator{})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// ReplaceAutoTagModerators replace all the automatic assignments with the new ones,
+// the user who is already the manual moderator of the tag, or is removed by the admin, is not assigned again
+func (tr *tagModeratorRepo) ReplaceAutoTagModerators(ctx context.Context, moderators []*entity.TagModerator) (err error) {
+	key := func(m *entity.TagModerator) string { return m.TagID + "_" + m.UserID }
+	_, err = tr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		wanted := make(map[string]bool, len(moderators))
+		for _, moderator := range moderators {
+			wanted[key(moderator)] = true
+		}
+		existing := make([]*entity.TagModerator, 0)
+		if err = session.Find(&existing); err != nil {
+			return nil, err
+		}
+		present := make(map[string]bool, len(existing))
+		for _, moderator := range existing {
+			if moderator.Source == entity.TagModeratorSourceAuto && !wanted[key(moderator)] {
+				if _, err = session.ID(moderator.ID).Delete(&entity.TagModerator{}); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			present[key(moderator)] = true
+		}
+		for _, moderator := range moderators {
+			if present[key(moderator)] {
+				continue
+			}
+			if _, err = session.Insert(moderator); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// GetTagAnswerScores get the total score of the answers of each user in each tag, which reaches the min score.
+// The community wiki answers are not counted. Sorted by the tag, then the highest score first.
+func (tr *tagModeratorRepo) GetTagAnswerScores(ctx context.Context, minScore int) (
+	scores []*tag_moderator.TagAnswerScore, err error) {
+	scores = make([]*tag_moderator.TagAnswerScore, 0)
+	err = tr.data.DB.Table(entity.Answer{}.TableName()).Alias("a").
+		Select("tr.tag_id, a.user_id, SUM(a.vote_count) AS score").
+		Join("INNER", []string{entity.TagRel{}.TableName(), "tr"}, "tr.object_id = a.question_id").
+		Where(builder.Eq{"a.status": entity.AnswerStatusAvailable}).
+		And(builder.Eq{"a.community_wiki": false}).
+		And(builder.Eq{"tr.status": entity.TagRelStatusAvailable}).
+		GroupBy("tr.tag_id, a.user_id").
+		Having(fmt.Sprintf("SUM(a.vote_count) >= %d", minScore)).
+		OrderBy("tr.tag_id ASC, score DESC, a.user_id ASC").
+		Find(&scores)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
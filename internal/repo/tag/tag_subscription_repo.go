@@ -0,0 +1,282 @@
+package tag
+
+import (
+	"context"
+	"time"
+
+	"answer/internal/base/data"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/service/tag_subscription"
+	"answer/pkg/converter"
+
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// tagSubscriptionRepo tag subscription repository
+type tagSubscriptionRepo struct {
+	data *data.Data
+}
+
+// NewTagSubscriptionRepo new repository
+func NewTagSubscriptionRepo(data *data.Data) tag_subscription.TagSubscriptionRepo {
+	return &tagSubscriptionRepo{
+		data: data,
+	}
+}
+
+// AddSubscription add the subscription with the tags which are not negated in its expression
+func (tr *tagSubscriptionRepo) AddSubscription(ctx context.Context, subscription *entity.TagSubscription,
+	tagNames []string) (err error) {
+	_, err = tr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		if _, err = session.Insert(subscription); err != nil {
+			return nil, err
+		}
+		return nil, addSubscriptionTags(session, subscription.ID, tagNames)
+	})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// UpdateSubscription update the expression and the frequency of the subscription, and replace its tags
+func (tr *tagSubscriptionRepo) UpdateSubscription(ctx context.Context, subscription *entity.TagSubscription,
+	tagNames []string) (err error) {
+	_, err = tr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		if _, err = session.ID(subscription.ID).Cols("expression", "frequency").Update(subscription); err != nil {
+			return nil, err
+		}
+		if _, err = session.Where("subscription_id = ?", subscription.ID).Delete(&entity.TagSubscriptionTag{}); err != nil {
+			return nil, err
+		}
+		return nil, addSubscriptionTags(session, subscription.ID, tagNames)
+	})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+func addSubscriptionTags(session *xorm.Session, subscriptionID string, tagNames []string) (err error) {
+	added := make(map[string]bool, len(tagNames))
+	for _, tagName := range tagNames {
+		if added[tagName] {
+			continue
+		}
+		added[tagName] = true
+		if _, err = session.Insert(&entity.TagSubscriptionTag{SubscriptionID: subscriptionID, TagName: tagName}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveSubscription remove the subscription and its tags
+func (tr *tagSubscriptionRepo) RemoveSubscription(ctx context.Context, id string) (err error) {
+	_, err = tr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		if _, err = session.Where("subscription_id = ?", id).Delete(&entity.TagSubscriptionTag{}); err != nil {
+			return nil, err
+		}
+		_, err = session.ID(id).Delete(&entity.TagSubscription{})
+		return nil, err
+	})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// GetSubscription get the subscription by id
+func (tr *tagSubscriptionRepo) GetSubscription(ctx context.Context, id string) (
+	subscription *entity.TagSubscription, exist bool, err error) {
+	subscription = &entity.TagSubscription{}
+	exist, err = tr.data.DB.ID(id).Get(subscription)
+	if err != nil {
+		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetUserSubscriptions get the subscriptions of the user, the earliest first
+func (tr *tagSubscriptionRepo) GetUserSubscriptions(ctx context.Context, userID string) (
+	subscriptions []*entity.TagSubscription, err error) {
+	subscriptions = make([]*entity.TagSubscription, 0)
+	err = tr.data.DB.Where("user_id = ?", userID).Asc("id").Find(&subscriptions)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetCandidateSubscriptions get the subscriptions whose expressions have any of the tags which are not negated,
+// only those may match the question with the tags
+func (tr *tagSubscriptionRepo) GetCandidateSubscriptions(ctx context.Context, tagNames []string) (
+	subscriptions []*entity.TagSubscription, err error) {
+	subscriptions = make([]*entity.TagSubscription, 0)
+	if len(tagNames) == 0 {
+		return
+	}
+	// the subscriptions are found by the index of the tag name
+	subscriptionIDs := builder.Select("subscription_id").From("tag_subscription_tag").
+		Where(builder.In("tag_name", tagNames))
+	err = tr.data.DB.Where(builder.In("id", subscriptionIDs)).Asc("id").Find(&subscriptions)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// AddAlert add the alert of the question for the user, nothing is added if the user is already alerted
+func (tr *tagSubscriptionRepo) AddAlert(ctx context.Context, alert *entity.TagAlert) (added bool, err error) {
+	exist, err := tr.data.DB.Where("user_id = ? AND question_id = ?", alert.UserID, alert.QuestionID).
+		Exist(&entity.TagAlert{})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if exist {
+		return false, nil
+	}
+	_, err = tr.data.DB.Insert(alert)
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return true, nil
+}
+
+// GetPendingAlertUserIDs get the users who have the pending alerts after the user id, in the order of the user id
+func (tr *tagSubscriptionRepo) GetPendingAlertUserIDs(ctx context.Context, afterUserID string, limit int) (
+	userIDs []string, err error) {
+	alerts := make([]*entity.TagAlert, 0)
+	err = tr.data.DB.Distinct("user_id").
+		Where("status = ?", entity.TagAlertStatusPending).
+		And("user_id > ?", converter.StringToInt64(afterUserID)).
+		Asc("user_id").Limit(limit).Find(&alerts)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	userIDs = make([]string, 0, len(alerts))
+	for _, alert := range alerts {
+		userIDs = append(userIDs, alert.UserID)
+	}
+	return
+}
+
+// GetPendingAlerts get the pending alerts of the user, the earliest first
+func (tr *tagSubscriptionRepo) GetPendingAlerts(ctx context.Context, userID string) (
+	alerts []*entity.TagAlert, err error) {
+	alerts = make([]*entity.TagAlert, 0)
+	err = tr.data.DB.Where("user_id = ? AND status = ?", userID, entity.TagAlertStatusPending).
+		Asc("id").Find(&alerts)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// ClaimAlerts mark the pending alerts as sent, only the alerts which are still pending are claimed
+func (tr *tagSubscriptionRepo) ClaimAlerts(ctx context.Context, ids []string) (claimedIDs []string, err error) {
+	claimedIDs = make([]string, 0, len(ids))
+	for _, id := range ids {
+		affected, err := tr.data.DB.ID(id).Where("status = ?", entity.TagAlertStatusPending).
+			Cols("status").Update(&entity.TagAlert{Status: entity.TagAlertStatusSent})
+		if err != nil {
+			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+		if affected > 0 {
+			claimedIDs = append(claimedIDs, id)
+		}
+	}
+	return claimedIDs, nil
+}
+
+// GetAlertSetting get the alert setting of the user
+func (tr *tagSubscriptionRepo) GetAlertSetting(ctx context.Context, userID string) (
+	setting *entity.TagAlertSetting, exist bool, err error) {
+	setting = &entity.TagAlertSetting{}
+	exist, err = tr.data.DB.Where("user_id = ?", userID).Get(setting)
+	if err != nil {
+		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if !exist {
+		return nil, false, nil
+	}
+	return
+}
+
+// SaveAlertSetting save the time zone and the quiet hours of the user
+func (tr *tagSubscriptionRepo) SaveAlertSetting(ctx context.Context, setting *entity.TagAlertSetting) (err error) {
+	old := &entity.TagAlertSetting{}
+	exist, err := tr.data.DB.Where("user_id = ?", setting.UserID).Get(old)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if exist {
+		_, err = tr.data.DB.ID(old.ID).Cols("time_zone", "quiet_hours_start", "quiet_hours_end").Update(setting)
+	} else {
+		_, err = tr.data.DB.Insert(setting)
+	}
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// UpdateLastDigestAt update the time when the batched alerts are sent to the user
+func (tr *tagSubscriptionRepo) UpdateLastDigestAt(ctx context.Context, userID string, lastDigestAt time.Time) (
+	err error) {
+	setting := &entity.TagAlertSetting{UserID: userID, LastDigestAt: lastDigestAt}
+	exist, err := tr.data.DB.Where("user_id = ?", userID).Exist(&entity.TagAlertSetting{})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if exist {
+		_, err = tr.data.DB.Where("user_id = ?", userID).Cols("last_digest_at").Update(setting)
+	} else {
+		_, err = tr.data.DB.Insert(setting)
+	}
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// GetIgnoredTagIDs get the ids of the tags which the user ignores
+func (tr *tagSubscriptionRepo) GetIgnoredTagIDs(ctx context.Context, userID string) (tagIDs []string, err error) {
+	ignores := make([]*entity.TagIgnore, 0)
+	err = tr.data.DB.Where("user_id = ?", userID).Asc("id").Find(&ignores)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	tagIDs = make([]string, 0, len(ignores))
+	for _, ignore := range ignores {
+		tagIDs = append(tagIDs, ignore.TagID)
+	}
+	return
+}
+
+// ReplaceIgnoredTags replace the ignored tags of the user with the tags
+func (tr *tagSubscriptionRepo) ReplaceIgnoredTags(ctx context.Context, userID string, tagIDs []string) (err error) {
+	_, err = tr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		if _, err = session.Where("user_id = ?", userID).Delete(&entity.TagIgnore{}); err != nil {
+			return nil, err
+		}
+		added := make(map[string]bool, len(tagIDs))
+		for _, tagID := range tagIDs {
+			if added[tagID] {
+				continue
+			}
+			added[tagID] = true
+			if _, err = session.Insert(&entity.TagIgnore{UserID: userID, TagID: tagID}); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
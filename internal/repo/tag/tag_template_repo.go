@@ -0,0 +1,73 @@
+package tag
+
+import (
+	"context"
+
+	"answer/internal/base/data"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/service/tag_template"
+
+	"github.com/segmentfault/pacman/errors"
+)
+
+// tagTemplateRepo tag template repository
+type tagTemplateRepo struct {
+	data *data.Data
+}
+
+// NewTagTemplateRepo new repository
+func NewTagTemplateRepo(data *data.Data) tag_template.TagTemplateRepo {
+	return &tagTemplateRepo{
+		data: data,
+	}
+}
+
+// SaveTagTemplate insert the template, or replace the content of the template if the tag already has one
+func (tr *tagTemplateRepo) SaveTagTemplate(ctx context.Context, template *entity.TagTemplate) (err error) {
+	old := &entity.TagTemplate{}
+	exist, err := tr.data.DB.Where("tag_id = ?", template.TagID).Get(old)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if exist {
+		_, err = tr.data.DB.ID(old.ID).Cols("user_id", "content", "required_sections").Update(template)
+	} else {
+		_, err = tr.data.DB.Insert(template)
+	}
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// RemoveTagTemplate remove the template of the tag
+func (tr *tagTemplateRepo) RemoveTagTemplate(ctx context.Context, tagID string) (err error) {
+	_, err = tr.data.DB.Where("tag_id = ?", tagID).Delete(&entity.TagTemplate{})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// GetTagTemplate get the template of the tag
+func (tr *tagTemplateRepo) GetTagTemplate(ctx context.Context, tagID string) (
+	template *entity.TagTemplate, exist bool, err error) {
+	template = &entity.TagTemplate{}
+	exist, err = tr.data.DB.Where("tag_id = ?", tagID).Get(template)
+	if err != nil {
+		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetTagTemplateList get the templates of the tags
+func (tr *tagTemplateRepo) GetTagTemplateList(ctx context.Context, tagIDs []string) (
+	templates []*entity.TagTemplate, err error) {
+	templates = make([]*entity.TagTemplate, 0)
+	err = tr.data.DB.In("tag_id", tagIDs).Find(&templates)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
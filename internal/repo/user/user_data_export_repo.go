@@ -0,0 +1,80 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"answer/internal/base/data"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/service/user_data"
+
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/xorm"
+)
+
+// userDataExportRepo user data export repository
+type userDataExportRepo struct {
+	data *data.Data
+}
+
+// NewUserDataExportRepo new repository
+func NewUserDataExportRepo(data *data.Data) user_data.UserDataExportRepo {
+	return &userDataExportRepo{
+		data: data,
+	}
+}
+
+// GetUserDataExport get the latest export of the user
+func (ur *userDataExportRepo) GetUserDataExport(ctx context.Context, userID string) (
+	export *entity.UserDataExport, exist bool, err error) {
+	export = &entity.UserDataExport{}
+	exist, err = ur.data.DB.Where("user_id = ?", userID).Get(export)
+	if err != nil {
+		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// AddUserDataExport replace the previous export of the user with the new one
+func (ur *userDataExportRepo) AddUserDataExport(ctx context.Context, export *entity.UserDataExport) (err error) {
+	_, err = ur.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		if _, err = session.Where("user_id = ?", export.UserID).Delete(&entity.UserDataExport{}); err != nil {
+			return nil, err
+		}
+		_, err = session.Insert(export)
+		return nil, err
+	})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// UpdateUserDataExport update the status, the file and the expired time of the export
+func (ur *userDataExportRepo) UpdateUserDataExport(ctx context.Context, export *entity.UserDataExport) (err error) {
+	_, err = ur.data.DB.ID(export.ID).Cols("status", "file_name", "expired_at").Update(export)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// FailPendingUserDataExports mark the pending exports created before the time as failed
+func (ur *userDataExportRepo) FailPendingUserDataExports(ctx context.Context, before time.Time) (err error) {
+	_, err = ur.data.DB.Where("status = ?", entity.UserDataExportStatusPending).And("created_at < ?", before).
+		Cols("status").Update(&entity.UserDataExport{Status: entity.UserDataExportStatusFailed})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// RemoveUserDataExport remove the export of the user
+func (ur *userDataExportRepo) RemoveUserDataExport(ctx context.Context, userID string) (err error) {
+	_, err = ur.data.DB.Where("user_id = ?", userID).Delete(&entity.UserDataExport{})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
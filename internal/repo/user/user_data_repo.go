@@ -0,0 +1,268 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"answer/internal/base/constant"
+	"answer/internal/base/data"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/service/user_data"
+
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// userDataRepo user data repository, used for exporting and deleting all the data of the user
+type userDataRepo struct {
+	data *data.Data
+}
+
+// NewUserDataRepo new repository
+func NewUserDataRepo(data *data.Data) user_data.UserDataRepo {
+	return &userDataRepo{
+		data: data,
+	}
+}
+
+// GetUserQuestions get all questions of the user
+func (ur *userDataRepo) GetUserQuestions(ctx context.Context, userID string) (questions []*entity.Question, err error) {
+	questions = make([]*entity.Question, 0)
+	err = ur.data.DB.Where("user_id = ?", userID).Asc("created_at").Find(&questions)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetUserAnswers get all answers of the user
+func (ur *userDataRepo) GetUserAnswers(ctx context.Context, userID string) (answers []*entity.Answer, err error) {
+	answers = make([]*entity.Answer, 0)
+	err = ur.data.DB.Where("user_id = ?", userID).Asc("created_at").Find(&answers)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetUserComments get all comments of the user
+func (ur *userDataRepo) GetUserComments(ctx context.Context, userID string) (comments []*entity.Comment, err error) {
+	comments = make([]*entity.Comment, 0)
+	err = ur.data.DB.Where("user_id = ?", userID).Asc("created_at").Find(&comments)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetUserActivities get all available activities of the user by activity types
+func (ur *userDataRepo) GetUserActivities(ctx context.Context, userID string, activityTypes []int) (
+	activities []*entity.Activity, err error) {
+	activities = make([]*entity.Activity, 0)
+	if len(activityTypes) == 0 {
+		return
+	}
+	err = ur.data.DB.Where(builder.And(
+		builder.Eq{"user_id": userID},
+		builder.Eq{"cancelled": entity.ActivityAvailable},
+		builder.In("activity_type", activityTypes),
+	)).Asc("created_at").Find(&activities)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetUserCollections get all collections of the user
+func (ur *userDataRepo) GetUserCollections(ctx context.Context, userID string) (collections []*entity.Collection, err error) {
+	collections = make([]*entity.Collection, 0)
+	err = ur.data.DB.Where("user_id = ?", userID).Asc("created_at").Find(&collections)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetUserNotifications get all notifications of the user
+func (ur *userDataRepo) GetUserNotifications(ctx context.Context, userID string) (
+	notifications []*entity.Notification, err error) {
+	notifications = make([]*entity.Notification, 0)
+	err = ur.data.DB.Where("user_id = ?", userID).Asc("created_at").Find(&notifications)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetGhostUser get the ghost user that the content of deleted users is reassigned to, create it if not exist
+func (ur *userDataRepo) GetGhostUser(ctx context.Context) (ghost *entity.User, err error) {
+	ghost = &entity.User{}
+	exist, err := ur.data.DB.Where("e_mail = ?", constant.GhostUserEmail).Get(ghost)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if exist {
+		return ghost, nil
+	}
+
+	ghost = &entity.User{
+		Username:    constant.GhostUsername,
+		EMail:       constant.GhostUserEmail,
+		DisplayName: constant.GhostUserDisplayName,
+		MailStatus:  entity.EmailStatusAvailable,
+		Status:      entity.UserStatusDeleted,
+		DeletedAt:   time.Now(),
+	}
+	exist, err = ur.data.DB.Where("username = ?", ghost.Username).Exist(&entity.User{})
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if exist {
+		ghost.Username = fmt.Sprintf("%s_%d", constant.GhostUsername, time.Now().Unix())
+	}
+	_, err = ur.data.DB.Insert(ghost)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return ghost, nil
+}
+
+// AnonymizeUserContent reassign the questions, answers, comments and revisions of the user to the ghost user,
+// and remove the private data such as collections and notifications
+func (ur *userDataRepo) AnonymizeUserContent(ctx context.Context, userID, ghostUserID string) (err error) {
+	_, err = ur.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		for _, bean := range []any{&entity.Question{}, &entity.Answer{}, &entity.Comment{}, &entity.Revision{}} {
+			_, err = session.Table(bean).Where("user_id = ?", userID).Update(map[string]any{"user_id": ghostUserID})
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, bean := range []any{&entity.Question{}, &entity.Answer{}} {
+			_, err = session.Table(bean).Where("last_edit_user_id = ?", userID).
+				Update(map[string]any{"last_edit_user_id": ghostUserID})
+			if err != nil {
+				return nil, err
+			}
+		}
+		return nil, ur.removeUserPrivateData(session, userID)
+	})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// RemoveUserContent mark the questions, answers and comments of the user as deleted, take the removed answers off
+// the answer count of the questions and the removed questions off the question count of the tags,
+// and remove the private data such as collections and notifications
+func (ur *userDataRepo) RemoveUserContent(ctx context.Context, userID string) (err error) {
+	_, err = ur.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		if err = ur.removeUserQuestions(session, userID); err != nil {
+			return nil, err
+		}
+		if err = ur.removeUserAnswers(session, userID); err != nil {
+			return nil, err
+		}
+		_, err = session.Where("user_id = ?", userID).Cols("status").
+			Update(&entity.Comment{Status: entity.CommentStatusDeleted})
+		if err != nil {
+			return nil, err
+		}
+		return nil, ur.removeUserPrivateData(session, userID)
+	})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// removeUserQuestions mark the questions of the user as deleted, and recount the questions of their tags
+func (ur *userDataRepo) removeUserQuestions(session *xorm.Session, userID string) (err error) {
+	questionIDs := make([]string, 0)
+	err = session.Table(&entity.Question{}).Where("user_id = ?", userID).
+		And("status <> ?", entity.QuestionStatusDeleted).Cols("id").Find(&questionIDs)
+	if err != nil || len(questionIDs) == 0 {
+		return err
+	}
+	_, err = session.In("id", questionIDs).Cols("status").Update(&entity.Question{Status: entity.QuestionStatusDeleted})
+	if err != nil {
+		return err
+	}
+
+	tagIDs := make([]string, 0)
+	err = session.Table(&entity.TagRel{}).In("object_id", questionIDs).
+		And("status = ?", entity.TagRelStatusAvailable).Distinct("tag_id").Find(&tagIDs)
+	if err != nil {
+		return err
+	}
+	_, err = session.In("object_id", questionIDs).Cols("status").Update(&entity.TagRel{Status: entity.TagRelStatusDeleted})
+	if err != nil {
+		return err
+	}
+	for _, tagID := range tagIDs {
+		count, err := session.Where("tag_id = ?", tagID).And("status = ?", entity.TagRelStatusAvailable).
+			Count(&entity.TagRel{})
+		if err != nil {
+			return err
+		}
+		_, err = session.ID(tagID).MustCols("question_count").Update(&entity.Tag{QuestionCount: int(count)})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeUserAnswers mark the answers of the user as deleted, and take the shown ones off the answer count
+func (ur *userDataRepo) removeUserAnswers(session *xorm.Session, userID string) (err error) {
+	answers := make([]*entity.Answer, 0)
+	err = session.Where("user_id = ?", userID).And("status = ?", entity.AnswerStatusAvailable).
+		Cols("question_id").Find(&answers)
+	if err != nil {
+		return err
+	}
+	_, err = session.Where("user_id = ?", userID).Cols("status").
+		Update(&entity.Answer{Status: entity.AnswerStatusDeleted})
+	if err != nil {
+		return err
+	}
+	removed := make(map[string]int)
+	for _, answer := range answers {
+		removed[answer.QuestionID]++
+	}
+	for questionID, count := range removed {
+		_, err = session.ID(questionID).Decr("answer_count", count).Update(&entity.Question{})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClearUserInfo mark the user as deleted and clear the personal information of the user
+func (ur *userDataRepo) ClearUserInfo(ctx context.Context, userID string) (err error) {
+	_, err = ur.data.DB.ID(userID).MustCols("e_mail", "pass", "mobile", "bio", "bio_html",
+		"website", "location", "ip_info", "avatar").Update(&entity.User{
+		Status:      entity.UserStatusDeleted,
+		DeletedAt:   time.Now(),
+		Username:    fmt.Sprintf("deleted_user_%s", userID),
+		DisplayName: constant.GhostUserDisplayName,
+		EMail:       fmt.Sprintf("%s@%s", userID, constant.DeletedUserEmailDomain),
+	})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+func (ur *userDataRepo) removeUserPrivateData(session *xorm.Session, userID string) (err error) {
+	for _, bean := range []any{&entity.Collection{}, &entity.CollectionGroup{}, &entity.Notification{}} {
+		if _, err = session.Where("user_id = ?", userID).Delete(bean); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"answer/internal/base/data"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/service/user_data"
+
+	"github.com/segmentfault/pacman/errors"
+)
+
+// userDeletionRepo user deletion repository
+type userDeletionRepo struct {
+	data *data.Data
+}
+
+// NewUserDeletionRepo new repository
+func NewUserDeletionRepo(data *data.Data) user_data.UserDeletionRepo {
+	return &userDeletionRepo{
+		data: data,
+	}
+}
+
+// AddUserDeletion add user deletion
+func (ur *userDeletionRepo) AddUserDeletion(ctx context.Context, deletion *entity.UserDeletion) (err error) {
+	_, err = ur.data.DB.Insert(deletion)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// GetPendingUserDeletion get the pending deletion of the user
+func (ur *userDeletionRepo) GetPendingUserDeletion(ctx context.Context, userID string) (
+	deletion *entity.UserDeletion, exist bool, err error) {
+	deletion = &entity.UserDeletion{}
+	exist, err = ur.data.DB.Where("user_id = ?", userID).
+		And("status = ?", entity.UserDeletionStatusPending).Get(deletion)
+	if err != nil {
+		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetDueUserDeletions get the pending deletions which have passed the grace period
+func (ur *userDeletionRepo) GetDueUserDeletions(ctx context.Context, now time.Time) (
+	deletions []*entity.UserDeletion, err error) {
+	deletions = make([]*entity.UserDeletion, 0)
+	err = ur.data.DB.Where("status = ?", entity.UserDeletionStatusPending).
+		And("execute_at <= ?", now).Asc("execute_at").Find(&deletions)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UpdateUserDeletionStatus update user deletion status
+func (ur *userDeletionRepo) UpdateUserDeletionStatus(ctx context.Context, id string, status int) (err error) {
+	_, err = ur.data.DB.ID(id).Cols("status").Update(&entity.UserDeletion{Status: status})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
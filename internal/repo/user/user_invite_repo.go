@@ -0,0 +1,95 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"answer/internal/base/data"
+	"answer/internal/base/pager"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/service/user_invite"
+
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/xorm"
+)
+
+// userInviteRepo user invite repository
+type userInviteRepo struct {
+	data *data.Data
+}
+
+// NewUserInviteRepo new repository
+func NewUserInviteRepo(data *data.Data) user_invite.UserInviteRepo {
+	return &userInviteRepo{
+		data: data,
+	}
+}
+
+// AddUserInvite add user invite
+func (ur *userInviteRepo) AddUserInvite(ctx context.Context, invite *entity.UserInvite) (err error) {
+	_, err = ur.data.DB.Insert(invite)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// GetUserInviteByCode get user invite by code
+func (ur *userInviteRepo) GetUserInviteByCode(ctx context.Context, code string) (
+	invite *entity.UserInvite, exist bool, err error) {
+	invite = &entity.UserInvite{}
+	exist, err = ur.data.DB.Where("code = ?", code).
+		And("status <> ?", entity.UserInviteStatusDeleted).Get(invite)
+	if err != nil {
+		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetUserInvitePage get user invite page
+func (ur *userInviteRepo) GetUserInvitePage(ctx context.Context, page, pageSize int) (
+	invites []*entity.UserInvite, total int64, err error) {
+	invites = make([]*entity.UserInvite, 0)
+	session := ur.data.DB.NewSession()
+	session.Where("status <> ?", entity.UserInviteStatusDeleted).Desc("created_at")
+	total, err = pager.Help(page, pageSize, &invites, &entity.UserInvite{}, session)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// AddUserWithInvite claim the available invite and add the user in one transaction, so the invite is used only
+// once even if it is registered concurrently. Return false and add nothing if the invite is used or expired.
+func (ur *userInviteRepo) AddUserWithInvite(ctx context.Context, code string, user *entity.User) (ok bool, err error) {
+	_, err = ur.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		affected, err := session.Where("code = ?", code).And("status = ?", entity.UserInviteStatusAvailable).
+			And("expired_at > ?", time.Now()).Cols("status").
+			Update(&entity.UserInvite{Status: entity.UserInviteStatusUsed})
+		if err != nil || affected == 0 {
+			return nil, err
+		}
+		if _, err = session.UseBool("is_admin").Insert(user); err != nil {
+			return nil, err
+		}
+		_, err = session.Where("code = ?", code).Cols("used_user_id").
+			Update(&entity.UserInvite{UsedUserID: user.ID})
+		ok = err == nil
+		return nil, err
+	})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return ok, nil
+}
+
+// RemoveUserInvite remove user invite
+func (ur *userInviteRepo) RemoveUserInvite(ctx context.Context, code string) (err error) {
+	_, err = ur.data.DB.Where("code = ?", code).Cols("status").
+		Update(&entity.UserInvite{Status: entity.UserInviteStatusDeleted})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
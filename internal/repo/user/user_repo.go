@@ -39,7 +39,7 @@ func (ur *userRepo) AddUser(ctx context.Context, user *entity.User) (err error)
 // IncreaseAnswerCount increase answer count
 func (ur *userRepo) IncreaseAnswerCount(ctx context.Context, userID string, amount int) (err error) {
 	user := &entity.User{}
-	_, err = ur.data.DB.Where("id = ?", userID).Incr("answer_count", amount).Update(user)
+	_, err = ur.data.Session(ctx).Where("id = ?", userID).Incr("answer_count", amount).Update(user)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -110,6 +110,16 @@ func (ur *userRepo) UpdateLanguage(ctx context.Context, userID, language string)
 	return
 }
 
+// UpdateContentLanguages update the content languages of the user, which are separated by comma
+func (ur *userRepo) UpdateContentLanguages(ctx context.Context, userID, contentLanguages string) (err error) {
+	_, err = ur.data.DB.Where("id = ?", userID).Cols("content_languages").
+		Update(&entity.User{ContentLanguages: contentLanguages})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
 // UpdateInfo update user info
 func (ur *userRepo) UpdateInfo(ctx context.Context, userInfo *entity.User) (err error) {
 	_, err = ur.data.DB.Where("id = ?", userInfo.ID).
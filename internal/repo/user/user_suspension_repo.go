@@ -0,0 +1,189 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"answer/internal/base/data"
+	"answer/internal/base/pager"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/service/auth"
+	"answer/internal/service/user_suspension"
+
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/xorm"
+)
+
+// userSuspensionRepo user suspension repository
+type userSuspensionRepo struct {
+	data     *data.Data
+	authRepo auth.AuthRepo
+}
+
+// NewUserSuspensionRepo new repository
+func NewUserSuspensionRepo(data *data.Data, authRepo auth.AuthRepo) user_suspension.UserSuspensionRepo {
+	return &userSuspensionRepo{
+		data:     data,
+		authRepo: authRepo,
+	}
+}
+
+// AddUserSuspension add user suspension
+func (ur *userSuspensionRepo) AddUserSuspension(ctx context.Context, suspension *entity.UserSuspension) (err error) {
+	_, err = ur.data.DB.Insert(suspension)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// GetActiveUserSuspension get the active suspension of the user
+func (ur *userSuspensionRepo) GetActiveUserSuspension(ctx context.Context, userID string) (
+	suspension *entity.UserSuspension, exist bool, err error) {
+	suspension = &entity.UserSuspension{}
+	exist, err = ur.data.DB.Where("user_id = ?", userID).
+		And("status = ?", entity.UserSuspensionStatusActive).Desc("created_at").Get(suspension)
+	if err != nil {
+		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// CountUserSuspensions count all the suspensions of the user in history
+func (ur *userSuspensionRepo) CountUserSuspensions(ctx context.Context, userID string) (count int64, err error) {
+	count, err = ur.data.DB.Where("user_id = ?", userID).Count(&entity.UserSuspension{})
+	if err != nil {
+		return 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetUserSuspensionList get all the suspensions of the user, the newest first
+func (ur *userSuspensionRepo) GetUserSuspensionList(ctx context.Context, userID string) (
+	suspensions []*entity.UserSuspension, err error) {
+	suspensions = make([]*entity.UserSuspension, 0)
+	err = ur.data.DB.Where("user_id = ?", userID).Desc("created_at").Find(&suspensions)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetDueUserSuspensions get the active suspensions whose period has ended, the permanent ones are excluded
+func (ur *userSuspensionRepo) GetDueUserSuspensions(ctx context.Context, now time.Time) (
+	suspensions []*entity.UserSuspension, err error) {
+	suspensions = make([]*entity.UserSuspension, 0)
+	err = ur.data.DB.Where("status = ?", entity.UserSuspensionStatusActive).And("days > 0").
+		And("expired_at <= ?", now).Asc("expired_at").Find(&suspensions)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UpdateUserSuspensionStatus update user suspension status
+func (ur *userSuspensionRepo) UpdateUserSuspensionStatus(ctx context.Context, id string, status int) (err error) {
+	_, err = ur.data.DB.ID(id).Cols("status").Update(&entity.UserSuspension{Status: status})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// LiftUserSuspension end the active suspension with the status, and reinstate the user if the user is still suspended.
+// Lifted is false if the suspension has been ended by others.
+func (ur *userSuspensionRepo) LiftUserSuspension(ctx context.Context, suspension *entity.UserSuspension, status int) (
+	lifted bool, err error) {
+	reinstated := false
+	_, err = ur.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+		affected, err := session.Where("id = ?", suspension.ID).
+			And("status = ?", entity.UserSuspensionStatusActive).
+			Cols("status").Update(&entity.UserSuspension{Status: status})
+		if err != nil || affected == 0 {
+			return nil, err
+		}
+		lifted = true
+		affected, err = session.Where("id = ?", suspension.UserID).
+			And("status = ?", entity.UserStatusSuspended).
+			Cols("status").Update(&entity.User{Status: entity.UserStatusAvailable})
+		reinstated = affected > 0
+		return nil, err
+	})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if !reinstated {
+		return lifted, nil
+	}
+
+	// the sessions which are signed in during the suspension become available
+	userInfo := &entity.User{}
+	exist, err := ur.data.DB.ID(suspension.UserID).Get(userInfo)
+	if err != nil {
+		return lifted, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if exist {
+		err = ur.authRepo.SetUserStatus(ctx, userInfo.ID, &entity.UserCacheInfo{
+			UserID:      userInfo.ID,
+			EmailStatus: userInfo.MailStatus,
+			UserStatus:  userInfo.Status,
+			IsAdmin:     userInfo.IsAdmin,
+		})
+		if err != nil {
+			return lifted, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+	}
+	return lifted, nil
+}
+
+// AddUserBan add user ban, the existing ban is not added again
+func (ur *userSuspensionRepo) AddUserBan(ctx context.Context, ban *entity.UserBan) (err error) {
+	exist, err := ur.data.DB.Where("ban_type = ?", ban.BanType).And("value = ?", ban.Value).
+		Exist(&entity.UserBan{})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if exist {
+		return nil
+	}
+	_, err = ur.data.DB.Insert(ban)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// RemoveUserBan remove user ban
+func (ur *userSuspensionRepo) RemoveUserBan(ctx context.Context, id string) (removed bool, err error) {
+	affected, err := ur.data.DB.ID(id).Delete(&entity.UserBan{})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected > 0, nil
+}
+
+// HasUserBan whether any of the values of the ban type is banned
+func (ur *userSuspensionRepo) HasUserBan(ctx context.Context, banType string, values []string) (
+	banned bool, err error) {
+	if len(values) == 0 {
+		return false, nil
+	}
+	banned, err = ur.data.DB.Where("ban_type = ?", banType).In("value", values).Exist(&entity.UserBan{})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetUserBanPage get user ban page, the newest first
+func (ur *userSuspensionRepo) GetUserBanPage(ctx context.Context, banType string, page, pageSize int) (
+	bans []*entity.UserBan, total int64, err error) {
+	bans = make([]*entity.UserBan, 0)
+	session := ur.data.DB.Desc("created_at")
+	total, err = pager.Help(page, pageSize, &bans, &entity.UserBan{BanType: banType}, session)
+	if err != nil {
+		return nil, 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
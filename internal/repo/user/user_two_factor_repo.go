@@ -0,0 +1,141 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"answer/internal/base/data"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/service/user_two_factor"
+
+	"github.com/segmentfault/pacman/errors"
+)
+
+// userTwoFactorRepo user two factor repository
+type userTwoFactorRepo struct {
+	data *data.Data
+}
+
+// NewUserTwoFactorRepo new repository
+func NewUserTwoFactorRepo(data *data.Data) user_two_factor.UserTwoFactorRepo {
+	return &userTwoFactorRepo{
+		data: data,
+	}
+}
+
+// GetUserTwoFactor get user two factor by user id
+func (ur *userTwoFactorRepo) GetUserTwoFactor(ctx context.Context, userID string) (
+	twoFactor *entity.UserTwoFactor, exist bool, err error) {
+	twoFactor = &entity.UserTwoFactor{}
+	exist, err = ur.data.DB.Where("user_id = ?", userID).Get(twoFactor)
+	if err != nil {
+		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// SaveUserTwoFactor add or update the two factor of user
+func (ur *userTwoFactorRepo) SaveUserTwoFactor(ctx context.Context, twoFactor *entity.UserTwoFactor) (err error) {
+	exist, err := ur.data.DB.Where("user_id = ?", twoFactor.UserID).Exist(&entity.UserTwoFactor{})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if exist {
+		_, err = ur.data.DB.Where("user_id = ?", twoFactor.UserID).
+			Cols("secret", "enabled", "recovery_codes").Update(twoFactor)
+	} else {
+		_, err = ur.data.DB.Insert(twoFactor)
+	}
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// UpdateRecoveryCodes update the hashed recovery codes of user
+func (ur *userTwoFactorRepo) UpdateRecoveryCodes(ctx context.Context, userID, recoveryCodes string) (err error) {
+	_, err = ur.data.DB.Where("user_id = ?", userID).Cols("recovery_codes").
+		Update(&entity.UserTwoFactor{RecoveryCodes: recoveryCodes})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// UseRecoveryCodes replace the recovery codes only if they are not changed since they are read, used is false
+// if another request has used or regenerated them first, so a recovery code is accepted only once
+func (ur *userTwoFactorRepo) UseRecoveryCodes(ctx context.Context, userID, oldRecoveryCodes, recoveryCodes string) (
+	used bool, err error) {
+	affected, err := ur.data.DB.Where("user_id = ?", userID).And("recovery_codes = ?", oldRecoveryCodes).
+		Cols("recovery_codes").Update(&entity.UserTwoFactor{RecoveryCodes: recoveryCodes})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected > 0, nil
+}
+
+// UseTimeStep claim the time step of the accepted code, it fails if the step or a later one is already used,
+// so the same code is accepted only once even if it is verified concurrently
+func (ur *userTwoFactorRepo) UseTimeStep(ctx context.Context, userID string, step int64) (used bool, err error) {
+	affected, err := ur.data.DB.Where("user_id = ?", userID).And("last_used_step < ?", step).
+		Cols("last_used_step").Update(&entity.UserTwoFactor{LastUsedStep: step})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected > 0, nil
+}
+
+// RemoveUserTwoFactor remove the two factor of user
+func (ur *userTwoFactorRepo) RemoveUserTwoFactor(ctx context.Context, userID string) (err error) {
+	_, err = ur.data.DB.Where("user_id = ?", userID).Delete(&entity.UserTwoFactor{})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// AddPendingLogin add the pending login, the expired ones are cleaned up by the way
+func (ur *userTwoFactorRepo) AddPendingLogin(ctx context.Context, pending *entity.UserTwoFactorPending) (err error) {
+	_, err = ur.data.DB.Where("expired_at < ?", time.Now()).Delete(&entity.UserTwoFactorPending{})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	_, err = ur.data.DB.Insert(pending)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// GetPendingLogin get the pending login of the token which is not expired
+func (ur *userTwoFactorRepo) GetPendingLogin(ctx context.Context, token string) (
+	pending *entity.UserTwoFactorPending, exist bool, err error) {
+	pending = &entity.UserTwoFactorPending{}
+	exist, err = ur.data.DB.Where("token = ?", token).And("expired_at > ?", time.Now()).Get(pending)
+	if err != nil {
+		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// IncreasePendingAttempts take one attempt of the pending login in one update, it fails once the attempts reach
+// the max, so the concurrent requests can not try more codes than allowed
+func (ur *userTwoFactorRepo) IncreasePendingAttempts(ctx context.Context, token string, maxAttempts int) (
+	increased bool, err error) {
+	affected, err := ur.data.DB.Where("token = ?", token).And("attempts < ?", maxAttempts).
+		Incr("attempts").Update(&entity.UserTwoFactorPending{})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected > 0, nil
+}
+
+// RemovePendingLogin remove the pending login of the token, removed is false if it is already removed
+func (ur *userTwoFactorRepo) RemovePendingLogin(ctx context.Context, token string) (removed bool, err error) {
+	affected, err := ur.data.DB.Where("token = ?", token).Delete(&entity.UserTwoFactorPending{})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected > 0, nil
+}
@@ -8,29 +8,44 @@ import (
 )
 
 type AnswerAPIRouter struct {
-	langController           *controller.LangController
-	userController           *controller.UserController
-	commentController        *controller.CommentController
-	reportController         *controller.ReportController
-	voteController           *controller.VoteController
-	tagController            *controller.TagController
-	followController         *controller.FollowController
-	collectionController     *controller.CollectionController
-	questionController       *controller.QuestionController
-	answerController         *controller.AnswerController
-	searchController         *controller.SearchController
-	revisionController       *controller.RevisionController
-	rankController           *controller.RankController
-	backyardReportController *controller_backyard.ReportController
-	backyardUserController   *controller_backyard.UserBackyardController
-	reasonController         *controller.ReasonController
-	themeController          *controller_backyard.ThemeController
-	siteInfoController       *controller_backyard.SiteInfoController
-	siteinfoController       *controller.SiteinfoController
-	notificationController   *controller.NotificationController
-	dashboardController      *controller.DashboardController
-	uploadController         *controller.UploadController
-	activityController       *controller.ActivityController
+	langController               *controller.LangController
+	userController               *controller.UserController
+	commentController            *controller.CommentController
+	reportController             *controller.ReportController
+	voteController               *controller.VoteController
+	tagController                *controller.TagController
+	followController             *controller.FollowController
+	collectionController         *controller.CollectionController
+	questionController           *controller.QuestionController
+	answerController             *controller.AnswerController
+	searchController             *controller.SearchController
+	revisionController           *controller.RevisionController
+	rankController               *controller.RankController
+	backyardReportController     *controller_backyard.ReportController
+	backyardUserController       *controller_backyard.UserBackyardController
+	reasonController             *controller.ReasonController
+	themeController              *controller_backyard.ThemeController
+	siteInfoController           *controller_backyard.SiteInfoController
+	siteinfoController           *controller.SiteinfoController
+	notificationController       *controller.NotificationController
+	dashboardController          *controller.DashboardController
+	uploadController             *controller.UploadController
+	activityController           *controller.ActivityController
+	userInviteController         *controller.UserInviteController
+	backyardInviteController     *controller_backyard.UserInviteController
+	userDataController           *controller.UserDataController
+	userTwoFactorController      *controller.UserTwoFactorController
+	backyardTwoFactorController  *controller_backyard.UserTwoFactorController
+	reviewController             *controller.ReviewController
+	backyardModerationController *controller_backyard.ModerationController
+	backyardSuspensionController *controller_backyard.UserSuspensionController
+	moderatorMessageController   *controller.ModeratorMessageController
+	backyardMessageController    *controller_backyard.ModeratorMessageController
+	draftController              *controller.DraftController
+	feedController               *controller.FeedController
+	mentionController            *controller.MentionController
+	tagSubscriptionController    *controller.TagSubscriptionController
+	backyardReputationController *controller_backyard.ReputationController
 }
 
 func NewAnswerAPIRouter(
@@ -57,57 +72,102 @@ func NewAnswerAPIRouter(
 	dashboardController *controller.DashboardController,
 	uploadController *controller.UploadController,
 	activityController *controller.ActivityController,
+	userInviteController *controller.UserInviteController,
+	backyardInviteController *controller_backyard.UserInviteController,
+	userDataController *controller.UserDataController,
+	userTwoFactorController *controller.UserTwoFactorController,
+	backyardTwoFactorController *controller_backyard.UserTwoFactorController,
+	reviewController *controller.ReviewController,
+	backyardModerationController *controller_backyard.ModerationController,
+	backyardSuspensionController *controller_backyard.UserSuspensionController,
+	moderatorMessageController *controller.ModeratorMessageController,
+	backyardMessageController *controller_backyard.ModeratorMessageController,
+	draftController *controller.DraftController,
+	feedController *controller.FeedController,
+	mentionController *controller.MentionController,
+	tagSubscriptionController *controller.TagSubscriptionController,
+	backyardReputationController *controller_backyard.ReputationController,
 ) *AnswerAPIRouter {
 	return &AnswerAPIRouter{
-		langController:           langController,
-		userController:           userController,
-		commentController:        commentController,
-		reportController:         reportController,
-		voteController:           voteController,
-		tagController:            tagController,
-		followController:         followController,
-		collectionController:     collectionController,
-		questionController:       questionController,
-		answerController:         answerController,
-		searchController:         searchController,
-		revisionController:       revisionController,
-		rankController:           rankController,
-		backyardReportController: backyardReportController,
-		backyardUserController:   backyardUserController,
-		reasonController:         reasonController,
-		themeController:          themeController,
-		siteInfoController:       siteInfoController,
-		notificationController:   notificationController,
-		siteinfoController:       siteinfoController,
-		dashboardController:      dashboardController,
-		uploadController:         uploadController,
-		activityController:       activityController,
+		langController:               langController,
+		userController:               userController,
+		commentController:            commentController,
+		reportController:             reportController,
+		voteController:               voteController,
+		tagController:                tagController,
+		followController:             followController,
+		collectionController:         collectionController,
+		questionController:           questionController,
+		answerController:             answerController,
+		searchController:             searchController,
+		revisionController:           revisionController,
+		rankController:               rankController,
+		backyardReportController:     backyardReportController,
+		backyardUserController:       backyardUserController,
+		reasonController:             reasonController,
+		themeController:              themeController,
+		siteInfoController:           siteInfoController,
+		notificationController:       notificationController,
+		siteinfoController:           siteinfoController,
+		dashboardController:          dashboardController,
+		uploadController:             uploadController,
+		activityController:           activityController,
+		userInviteController:         userInviteController,
+		backyardInviteController:     backyardInviteController,
+		userDataController:           userDataController,
+		userTwoFactorController:      userTwoFactorController,
+		backyardTwoFactorController:  backyardTwoFactorController,
+		reviewController:             reviewController,
+		backyardModerationController: backyardModerationController,
+		backyardSuspensionController: backyardSuspensionController,
+		moderatorMessageController:   moderatorMessageController,
+		backyardMessageController:    backyardMessageController,
+		draftController:              draftController,
+		feedController:               feedController,
+		mentionController:            mentionController,
+		tagSubscriptionController:    tagSubscriptionController,
+		backyardReputationController: backyardReputationController,
 	}
 }
 
-func (a *AnswerAPIRouter) RegisterUnAuthAnswerAPIRouter(r *gin.RouterGroup) {
+// RegisterMustUnAuthAnswerAPIRouter register the api that can be visited even if the site is login required,
+// such as login, register and site info
+func (a *AnswerAPIRouter) RegisterMustUnAuthAnswerAPIRouter(r *gin.RouterGroup) {
 	// i18n
 	r.GET("/language/config", a.langController.GetLangMapping)
 	r.GET("/language/options", a.langController.GetUserLangOptions)
 
-	// comment
-	r.GET("/comment/page", a.commentController.GetCommentWithPage)
-	r.GET("/personal/comment/page", a.commentController.GetCommentPersonalWithPage)
-	r.GET("/comment", a.commentController.GetComment)
-
 	// user
 	r.GET("/user/info", a.userController.GetUserInfoByUserID)
 	r.GET("/user/action/record", a.userController.ActionRecord)
 	r.POST("/user/login/email", a.userController.UserEmailLogin)
+	r.POST("/user/login/2fa", a.userController.UserTwoFactorLogin)
 	r.POST("/user/register/email", a.userController.UserRegisterByEmail)
 	r.POST("/user/email/verification", a.userController.UserVerifyEmail)
 	r.POST("/user/password/reset", a.userController.RetrievePassWord)
 	r.POST("/user/password/replacement", a.userController.UseRePassWord)
-	r.GET("/personal/user/info", a.userController.GetOtherUserInfoByUsername)
 	r.POST("/user/email/verification/send", a.userController.UserVerifyEmailSend)
 	r.GET("/user/logout", a.userController.UserLogout)
 	r.PUT("/user/email", a.userController.UserChangeEmailVerify)
 	r.POST("/user/email/change/code", a.userController.UserChangeEmailSendCode)
+	r.GET("/user/invite", a.userInviteController.GetUserInvite)
+
+	//siteinfo
+	r.GET("/siteinfo", a.siteinfoController.GetSiteInfo)
+	r.GET("/siteinfo/legal", a.siteinfoController.GetSiteLegalInfo)
+}
+
+// RegisterUnAuthAnswerAPIRouter register the api that no need to login,
+// but if the site is login required, those api can only be visited by login user
+func (a *AnswerAPIRouter) RegisterUnAuthAnswerAPIRouter(r *gin.RouterGroup) {
+	// comment
+	r.GET("/comment/page", a.commentController.GetCommentWithPage)
+	r.GET("/comment/replies", a.commentController.GetCommentReplyPage)
+	r.GET("/personal/comment/page", a.commentController.GetCommentPersonalWithPage)
+	r.GET("/comment", a.commentController.GetComment)
+
+	// user
+	r.GET("/personal/user/info", a.userController.GetOtherUserInfoByUsername)
 
 	//answer
 	r.GET("/answer/info", a.answerController.Get)
@@ -124,12 +184,18 @@ func (a *AnswerAPIRouter) RegisterUnAuthAnswerAPIRouter(r *gin.RouterGroup) {
 
 	//revision
 	r.GET("/revisions", a.revisionController.GetRevisionList)
+	r.GET("/revisions/contributors", a.revisionController.GetContributorList)
+
+	// collection
+	r.GET("/collection/group/shared", a.collectionController.GetSharedCollectionGroup)
+	r.GET("/collection/group/rss", a.collectionController.GetCollectionGroupRSS)
 
 	// tag
 	r.GET("/tags/page", a.tagController.GetTagWithPage)
 	r.GET("/tags/following", a.tagController.GetFollowingTags)
 	r.GET("/tag", a.tagController.GetTagInfo)
 	r.GET("/tag/synonyms", a.tagController.GetTagSynonyms)
+	r.GET("/tag/template", a.tagController.GetTagTemplateList)
 	r.GET("/question/index", a.questionController.Index)
 
 	//search
@@ -137,11 +203,6 @@ func (a *AnswerAPIRouter) RegisterUnAuthAnswerAPIRouter(r *gin.RouterGroup) {
 
 	//rank
 	r.GET("/personal/rank/page", a.rankController.GetRankPersonalWithPage)
-
-	//siteinfo
-	r.GET("/siteinfo", a.siteinfoController.GetSiteInfo)
-	r.GET("/siteinfo/legal", a.siteinfoController.GetSiteLegalInfo)
-
 }
 
 func (a *AnswerAPIRouter) RegisterAnswerAPIRouter(r *gin.RouterGroup) {
@@ -150,6 +211,11 @@ func (a *AnswerAPIRouter) RegisterAnswerAPIRouter(r *gin.RouterGroup) {
 	r.PUT("/revisions/audit", a.revisionController.RevisionAudit)
 	r.GET("/revisions/edit/check", a.revisionController.CheckCanUpdateRevision)
 
+	// review
+	r.GET("/review/queues", a.reviewController.GetReviewQueues)
+	r.GET("/review/page", a.reviewController.GetReviewPage)
+	r.PUT("/review", a.reviewController.Review)
+
 	// comment
 	r.POST("/comment", a.commentController.AddComment)
 	r.DELETE("/comment", a.commentController.RemoveComment)
@@ -165,15 +231,28 @@ func (a *AnswerAPIRouter) RegisterAnswerAPIRouter(r *gin.RouterGroup) {
 	// follow
 	r.POST("/follow", a.followController.Follow)
 	r.PUT("/follow/tags", a.followController.UpdateFollowTags)
+	r.POST("/follow/user", a.followController.FollowUser)
+	r.GET("/follow/users", a.followController.GetFollowingUsers)
 
 	// tag
 	r.GET("/question/tags", a.tagController.SearchTagLike)
 	r.PUT("/tag", a.tagController.UpdateTag)
 	r.DELETE("/tag", a.tagController.RemoveTag)
 	r.PUT("/tag/synonym", a.tagController.UpdateTagSynonym)
+	r.PUT("/tag/template", a.tagController.SaveTagTemplate)
+	r.DELETE("/tag/template", a.tagController.RemoveTagTemplate)
+	r.GET("/tags/moderated", a.tagController.GetModeratedTags)
 
 	// collection
 	r.POST("/collection/switch", a.collectionController.CollectionSwitch)
+	r.GET("/collection/groups", a.collectionController.GetCollectionGroupList)
+	r.POST("/collection/group", a.collectionController.AddCollectionGroup)
+	r.PUT("/collection/group", a.collectionController.UpdateCollectionGroup)
+	r.DELETE("/collection/group", a.collectionController.RemoveCollectionGroup)
+	r.PUT("/collection/groups/sort", a.collectionController.SortCollectionGroups)
+	r.GET("/collection/page", a.collectionController.GetCollectionPage)
+	r.PUT("/collection/move", a.collectionController.MoveCollection)
+	r.PUT("/collection/note", a.collectionController.UpdateCollectionNote)
 	r.GET("/personal/collection/page", a.questionController.UserCollectionList)
 
 	// question
@@ -181,19 +260,64 @@ func (a *AnswerAPIRouter) RegisterAnswerAPIRouter(r *gin.RouterGroup) {
 	r.PUT("/question", a.questionController.UpdateQuestion)
 	r.DELETE("/question", a.questionController.RemoveQuestion)
 	r.PUT("/question/status", a.questionController.CloseQuestion)
+	r.GET("/question/close/vote", a.questionController.GetCloseVote)
+	r.POST("/question/close/vote", a.questionController.VoteClose)
+	r.POST("/question/reopen/vote", a.questionController.VoteReopen)
 	r.GET("/question/similar", a.questionController.SearchByTitleLike)
 
 	// answer
 	r.POST("/answer", a.answerController.Add)
 	r.PUT("/answer", a.answerController.Update)
 	r.POST("/answer/acceptance", a.answerController.Adopted)
+	r.DELETE("/answer/acceptance", a.answerController.CancelAdopted)
 	r.DELETE("/answer", a.answerController.RemoveAnswer)
 
+	// draft
+	r.GET("/drafts/page", a.draftController.GetDraftPage)
+	r.GET("/draft", a.draftController.GetDraft)
+	r.PUT("/draft", a.draftController.SaveDraft)
+	r.DELETE("/draft", a.draftController.RemoveDraft)
+
+	// feed
+	r.GET("/feed", a.feedController.GetFeed)
+	r.GET("/feed/digest", a.feedController.GetFeedDigest)
+
+	// mention
+	r.GET("/mention/users", a.mentionController.SearchMentionUsers)
+
+	// tag subscription
+	r.GET("/tag/subscriptions", a.tagSubscriptionController.GetTagSubscriptions)
+	r.POST("/tag/subscription", a.tagSubscriptionController.AddTagSubscription)
+	r.PUT("/tag/subscription", a.tagSubscriptionController.UpdateTagSubscription)
+	r.DELETE("/tag/subscription", a.tagSubscriptionController.RemoveTagSubscription)
+	r.GET("/tag/alert/setting", a.tagSubscriptionController.GetTagAlertSetting)
+	r.PUT("/tag/alert/setting", a.tagSubscriptionController.UpdateTagAlertSetting)
+	r.GET("/tags/ignored", a.tagSubscriptionController.GetIgnoredTags)
+	r.PUT("/tags/ignored", a.tagSubscriptionController.UpdateIgnoredTags)
+
 	// user
 	r.PUT("/user/password", a.userController.UserModifyPassWord)
 	r.PUT("/user/info", a.userController.UserUpdateInfo)
 	r.PUT("/user/interface", a.userController.UserUpdateInterface)
 	r.POST("/user/notice/set", a.userController.UserNoticeSet)
+	r.POST("/user/data/export", a.userDataController.ExportUserData)
+	r.GET("/user/data/export", a.userDataController.GetUserDataExport)
+	r.GET("/user/data/export/download", a.userDataController.DownloadUserDataExport)
+	r.POST("/user/deletion", a.userDataController.AddUserDeletion)
+	r.GET("/user/deletion", a.userDataController.GetUserDeletion)
+	r.DELETE("/user/deletion", a.userDataController.CancelUserDeletion)
+
+	// session
+	r.GET("/user/sessions", a.userController.GetUserSessionList)
+	r.DELETE("/user/session", a.userController.RevokeUserSession)
+	r.DELETE("/user/sessions", a.userController.RevokeOtherUserSessions)
+
+	// two factor authentication
+	r.GET("/user/2fa", a.userTwoFactorController.GetUserTwoFactor)
+	r.POST("/user/2fa/setup", a.userTwoFactorController.SetupUserTwoFactor)
+	r.POST("/user/2fa/enable", a.userTwoFactorController.EnableUserTwoFactor)
+	r.POST("/user/2fa/disable", a.userTwoFactorController.DisableUserTwoFactor)
+	r.POST("/user/2fa/recovery-codes", a.userTwoFactorController.RegenerateRecoveryCodes)
 
 	// vote
 	r.GET("/personal/vote/page", a.voteController.UserVotes)
@@ -208,6 +332,10 @@ func (a *AnswerAPIRouter) RegisterAnswerAPIRouter(r *gin.RouterGroup) {
 	r.PUT("/notification/read/state/all", a.notificationController.ClearUnRead)
 	r.PUT("/notification/read/state", a.notificationController.ClearIDUnRead)
 
+	// moderator message
+	r.GET("/moderator/message/thread", a.moderatorMessageController.GetModeratorMessageThread)
+	r.POST("/moderator/message/reply", a.moderatorMessageController.ReplyModeratorMessage)
+
 	// upload file
 	r.POST("/file", a.uploadController.UploadFile)
 
@@ -222,20 +350,48 @@ func (a *AnswerAPIRouter) RegisterAnswerCmsAPIRouter(r *gin.RouterGroup) {
 	r.PUT("/question/status", a.questionController.AdminSetQuestionStatus)
 	r.GET("/answer/page", a.questionController.CmsSearchAnswerList)
 	r.PUT("/answer/status", a.answerController.AdminSetAnswerStatus)
+	r.POST("/comment/answer", a.answerController.ConvertCommentToAnswer)
+
+	// tag moderator
+	r.POST("/tag/moderator", a.tagController.AddTagModerator)
+	r.DELETE("/tag/moderator", a.tagController.RemoveTagModerator)
 
 	// report
 	r.GET("/reports/page", a.backyardReportController.ListReportPage)
 	r.PUT("/report", a.backyardReportController.Handle)
 
+	// moderation
+	r.GET("/moderation/results/page", a.backyardModerationController.GetModerationResultPage)
+
 	// user
 	r.GET("/users/page", a.backyardUserController.GetUserPage)
 	r.PUT("/user/status", a.backyardUserController.UpdateUserStatus)
+	r.DELETE("/user/sessions", a.backyardUserController.SignOutUser)
+	r.POST("/user/invite", a.backyardInviteController.AddUserInvite)
+	r.DELETE("/user/invite", a.backyardInviteController.RemoveUserInvite)
+	r.GET("/user/invites/page", a.backyardInviteController.GetUserInvitePage)
+	r.DELETE("/user/2fa", a.backyardTwoFactorController.ResetUserTwoFactor)
+	r.GET("/user/suspensions", a.backyardSuspensionController.GetUserSuspensionList)
+	r.GET("/user/bans/page", a.backyardSuspensionController.GetUserBanPage)
+	r.POST("/user/ban", a.backyardSuspensionController.AddUserBan)
+	r.DELETE("/user/ban", a.backyardSuspensionController.RemoveUserBan)
+	r.GET("/user/moderator/messages", a.backyardMessageController.GetModeratorMessageThreadList)
+
+	// reputation
+	r.POST("/reputation/recalculate", a.backyardReputationController.RecalculateReputation)
+
+	// moderator message
+	r.GET("/moderator/message/templates", a.backyardMessageController.GetModeratorMessageTemplates)
+	r.POST("/moderator/message", a.backyardMessageController.AddModeratorMessage)
 
 	// reason
 	r.GET("/reasons", a.reasonController.Reasons)
 
 	// language
 	r.GET("/language/options", a.langController.GetAdminLangOptions)
+	r.GET("/language/bundles", a.langController.GetAdminLangBundles)
+	r.POST("/language/bundle", a.langController.UploadLangBundle)
+	r.DELETE("/language/bundle", a.langController.RemoveLangBundle)
 
 	// theme
 	r.GET("/theme/options", a.themeController.GetThemeOptions)
@@ -246,11 +402,27 @@ func (a *AnswerAPIRouter) RegisterAnswerCmsAPIRouter(r *gin.RouterGroup) {
 	r.GET("/siteinfo/branding", a.siteInfoController.GetSiteBranding)
 	r.GET("/siteinfo/write", a.siteInfoController.GetSiteWrite)
 	r.GET("/siteinfo/legal", a.siteInfoController.GetSiteLegal)
+	r.GET("/siteinfo/seo", a.siteInfoController.GetSeo)
+	r.GET("/siteinfo/login", a.siteInfoController.GetSiteLogin)
+	r.GET("/siteinfo/review", a.siteInfoController.GetSiteReview)
+	r.GET("/siteinfo/close/vote", a.siteInfoController.GetSiteCloseVote)
+	r.GET("/siteinfo/moderation", a.siteInfoController.GetSiteModeration)
+	r.GET("/siteinfo/accept", a.siteInfoController.GetSiteAccept)
+	r.GET("/siteinfo/comment", a.siteInfoController.GetSiteComment)
+	r.GET("/siteinfo/tag/moderator", a.siteInfoController.GetSiteTagModerator)
 	r.PUT("/siteinfo/general", a.siteInfoController.UpdateGeneral)
 	r.PUT("/siteinfo/interface", a.siteInfoController.UpdateInterface)
 	r.PUT("/siteinfo/branding", a.siteInfoController.UpdateBranding)
 	r.PUT("/siteinfo/write", a.siteInfoController.UpdateSiteWrite)
 	r.PUT("/siteinfo/legal", a.siteInfoController.UpdateSiteLegal)
+	r.PUT("/siteinfo/seo", a.siteInfoController.UpdateSeo)
+	r.PUT("/siteinfo/login", a.siteInfoController.UpdateSiteLogin)
+	r.PUT("/siteinfo/review", a.siteInfoController.UpdateSiteReview)
+	r.PUT("/siteinfo/close/vote", a.siteInfoController.UpdateSiteCloseVote)
+	r.PUT("/siteinfo/moderation", a.siteInfoController.UpdateSiteModeration)
+	r.PUT("/siteinfo/accept", a.siteInfoController.UpdateSiteAccept)
+	r.PUT("/siteinfo/comment", a.siteInfoController.UpdateSiteComment)
+	r.PUT("/siteinfo/tag/moderator", a.siteInfoController.UpdateSiteTagModerator)
 	r.GET("/setting/smtp", a.siteInfoController.GetSMTPConfig)
 	r.PUT("/setting/smtp", a.siteInfoController.UpdateSMTPConfig)
 
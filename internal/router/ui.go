@@ -5,8 +5,13 @@ import (
 	"fmt"
 	"io/fs"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
 
+	"answer/internal/base/middleware"
+	"answer/internal/controller"
 	"answer/ui"
 
 	"github.com/gin-gonic/gin"
@@ -17,13 +22,43 @@ const UIIndexFilePath = "build/index.html"
 const UIRootFilePath = "build"
 const UIStaticPath = "build/static"
 
+// UILoginPagePath the page that anonymous user will be redirected to when the site is login required
+const UILoginPagePath = "/users/login"
+
+// uiPublicPathPrefixes the ui pages can be visited by anonymous user even if the site is login required
+var uiPublicPathPrefixes = []string{
+	"/users/login",
+	"/users/register",
+	"/users/account-recovery",
+	"/users/password-reset",
+	"/users/account-activation",
+	"/users/confirm-new-email",
+	"/users/change-email",
+	"/users/account-suspended",
+	"/tos",
+	"/privacy",
+	"/install",
+	"/maintenance",
+	"/50x",
+	"/favicon.ico",
+	"/manifest.json",
+}
+
 // UIRouter is an interface that provides ui static file routers
 type UIRouter struct {
+	seoController      *controller.SeoController
+	authUserMiddleware *middleware.AuthUserMiddleware
 }
 
 // NewUIRouter creates a new UIRouter instance with the embed resources
-func NewUIRouter() *UIRouter {
-	return &UIRouter{}
+func NewUIRouter(
+	seoController *controller.SeoController,
+	authUserMiddleware *middleware.AuthUserMiddleware,
+) *UIRouter {
+	return &UIRouter{
+		seoController:      seoController,
+		authUserMiddleware: authUserMiddleware,
+	}
 }
 
 // _resource is an interface that provides static file, it's a private interface
@@ -40,6 +75,12 @@ func (r *_resource) Open(name string) (fs.File, error) {
 
 // Register a new static resource which generated by ui directory
 func (a *UIRouter) Register(r *gin.Engine) {
+	if a.seoController != nil {
+		r.GET("/robots.txt", a.seoController.Robots)
+		r.GET("/sitemap.xml", a.seoController.Sitemap)
+		r.GET("/sitemap/:page", a.seoController.SitemapPage)
+	}
+
 	staticPath := os.Getenv("ANSWER_STATIC_PATH")
 
 	// if ANSWER_STATIC_PATH is set and not empty, ignore embed resource
@@ -51,10 +92,18 @@ func (a *UIRouter) Register(r *gin.Engine) {
 		} else {
 			log.Debugf("registering static path %s", staticPath)
 
-			r.LoadHTMLGlob(staticPath + "/*.html")
 			r.Static("/static", staticPath+"/static")
 			r.NoRoute(func(c *gin.Context) {
-				c.HTML(http.StatusOK, "index.html", gin.H{})
+				if a.redirectToLogin(c) {
+					return
+				}
+				file, err := os.ReadFile(filepath.Join(staticPath, "index.html"))
+				if err != nil {
+					log.Error(err)
+					c.Status(http.StatusNotFound)
+					return
+				}
+				a.renderIndex(c, string(file))
 			})
 
 			// return immediately if the static path is set
@@ -69,6 +118,9 @@ func (a *UIRouter) Register(r *gin.Engine) {
 
 	// specify the not router for default routes and redirect
 	r.NoRoute(func(c *gin.Context) {
+		if a.redirectToLogin(c) {
+			return
+		}
 		urlPath := c.Request.URL.Path
 		filePath := ""
 		switch urlPath {
@@ -91,6 +143,38 @@ func (a *UIRouter) Register(r *gin.Engine) {
 			c.Status(http.StatusNotFound)
 			return
 		}
+		if filePath == UIIndexFilePath {
+			a.renderIndex(c, string(file))
+			return
+		}
 		c.String(http.StatusOK, string(file))
 	})
 }
+
+// renderIndex render the index page with the page metadata for crawlers
+func (a *UIRouter) renderIndex(c *gin.Context, page string) {
+	if a.seoController == nil {
+		c.Header("content-type", "text/html;charset=utf-8")
+		c.String(http.StatusOK, page)
+		return
+	}
+	a.seoController.RenderPage(c, page)
+}
+
+// redirectToLogin redirect the anonymous user to login page if the site is login required
+func (a *UIRouter) redirectToLogin(c *gin.Context) bool {
+	if a.authUserMiddleware == nil {
+		return false
+	}
+	urlPath := c.Request.URL.Path
+	for _, prefix := range uiPublicPathPrefixes {
+		if urlPath == prefix || strings.HasPrefix(urlPath, prefix+"/") {
+			return false
+		}
+	}
+	if a.authUserMiddleware.CanVisit(c) {
+		return false
+	}
+	c.Redirect(http.StatusFound, UILoginPagePath+"?redirect="+url.QueryEscape(c.Request.URL.RequestURI()))
+	return true
+}
@@ -11,7 +11,7 @@ import (
 func TestUIRouter_Register(t *testing.T) {
 	r := gin.Default()
 
-	NewUIRouter().Register(r)
+	NewUIRouter(nil, nil).Register(r)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/", nil)
@@ -24,7 +24,7 @@ func TestUIRouter_Register(t *testing.T) {
 func TestUIRouter_Static(t *testing.T) {
 	r := gin.Default()
 
-	NewUIRouter().Register(r)
+	NewUIRouter(nil, nil).Register(r)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/static/version.txt", nil)
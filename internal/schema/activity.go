@@ -39,7 +39,9 @@ type ActObjectTimeline struct {
 	ObjectType      string `json:"object_type"`
 	Cancelled       bool   `json:"cancelled"`
 	CancelledAt     int64  `json:"cancelled_at"`
-	UserID          string `json:"-"`
+	// OnBehalf the moderator accepts or unaccepts the answer on behalf of the asker
+	OnBehalf bool   `json:"on_behalf"`
+	UserID   string `json:"-"`
 }
 
 // ActObjectInfo act object info
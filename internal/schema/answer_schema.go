@@ -15,23 +15,34 @@ const (
 )
 
 type AnswerAddReq struct {
-	QuestionID string `json:"question_id" ` // question_id
-	Content    string `json:"content" `     // content
-	HTML       string `json:"html" `        // html
-	UserID     string `json:"-" `           // user_id
+	QuestionID string `json:"question_id" `                         // question_id
+	Content    string `json:"content" `                             // content
+	HTML       string `json:"html" `                                // html
+	Language   string `validate:"omitempty,lte=16" json:"language"` // language
+	UserID     string `json:"-" `                                   // user_id
+	IP         string `json:"-"`                                    // ip
+	UserAgent  string `json:"-"`                                    // user_agent
+	// the answer is posted as a community wiki
+	CommunityWiki bool `validate:"omitempty" json:"community_wiki"`
 }
 
 type AnswerUpdateReq struct {
-	ID           string `json:"id"`                                // id
-	QuestionID   string `json:"question_id" `                      // question_id
-	UserID       string `json:"-" `                                // user_id
-	Title        string `json:"title" `                            // title
-	Content      string `json:"content"`                           // content
-	HTML         string `json:"html" `                             // html
-	EditSummary  string `validate:"omitempty" json:"edit_summary"` // edit_summary
+	ID           string `json:"id"`                                   // id
+	QuestionID   string `json:"question_id" `                         // question_id
+	UserID       string `json:"-" `                                   // user_id
+	Title        string `json:"title" `                               // title
+	Content      string `json:"content"`                              // content
+	HTML         string `json:"html" `                                // html
+	EditSummary  string `validate:"omitempty" json:"edit_summary"`    // edit_summary
+	Language     string `validate:"omitempty,lte=16" json:"language"` // language
 	NoNeedReview bool   `json:"-"`
 	// whether user can edit it
 	CanEdit bool `json:"-"`
+	// turn the answer into a community wiki by the author or admin, the community wiki can not be turned back
+	CommunityWiki bool `validate:"omitempty" json:"community_wiki"`
+	IsAdmin       bool `json:"-"`
+	// whether user can edit the community wiki without review
+	CanEditWiki bool `json:"-"`
 }
 
 // AnswerUpdateResp answer update resp
@@ -60,6 +71,8 @@ type AnswerInfo struct {
 	CreateTime     int64          `json:"create_time" xorm:"created"`     // create_time
 	UpdateTime     int64          `json:"update_time" xorm:"updated"`     // update_time
 	Adopted        int            `json:"adopted"`                        // 1 Failed 2 Adopted
+	Language       string         `json:"language"`                       // language
+	CommunityWiki  bool           `json:"community_wiki"`
 	UserID         string         `json:"-" `
 	UpdateUserID   string         `json:"-" `
 	UserInfo       *UserBasicInfo `json:"user_info,omitempty"`
@@ -93,6 +106,10 @@ type AnswerAdoptedReq struct {
 	QuestionID string `json:"question_id"`
 	AnswerID   string `json:"answer_id"`
 	UserID     string `json:"-" `
+	// IsAdmin and IsTagModerator the admin and the moderator of the tags of the question can accept the answer
+	// on behalf of the inactive asker
+	IsAdmin        bool `json:"-"`
+	IsTagModerator bool `json:"-"`
 }
 
 type AdminSetAnswerStatusRequest struct {
@@ -6,6 +6,19 @@ type UpdateUserStatusReq struct {
 	UserID string `validate:"required" json:"user_id"`
 	// user status
 	Status string `validate:"required,oneof=normal suspended deleted inactive" json:"status" enums:"normal,suspended,deleted,inactive"`
+	// the following fields only work for the suspended status
+	// SuspendDays the days of the suspension, 0 means the days are escalated by the suspension history of the user
+	SuspendDays int `validate:"omitempty,min=0,max=3650" json:"suspend_days"`
+	// SuspendPermanent the user is suspended until admin lifts it
+	SuspendPermanent bool   `json:"suspend_permanent"`
+	SuspendReason    string `validate:"omitempty,oneof=spam abuse low_quality other" json:"suspend_reason" enums:"spam,abuse,low_quality,other"`
+	// SuspendMessage the message shown to the suspended user
+	SuspendMessage string `validate:"omitempty,lte=1000" json:"suspend_message"`
+	// BanIP ban the registration ip of the user, it is usually used for the spam accounts
+	BanIP bool `json:"ban_ip"`
+	// BanEmailDomain ban the email domain of the user
+	BanEmailDomain bool   `json:"ban_email_domain"`
+	OperatorID     string `json:"-"`
 }
 
 const (
@@ -1,7 +1,5 @@
 package schema
 
-import "time"
-
 const (
 	CGDefault = 1
 	CGDIY     = 2
@@ -31,46 +29,89 @@ type CollectionSwitchResp struct {
 
 // AddCollectionGroupReq add collection group request
 type AddCollectionGroupReq struct {
-	//
-	UserID int64 `validate:"required" comment:"" json:"user_id"`
 	// the collection group name
-	Name string `validate:"required,gt=0,lte=50" comment:"the collection group name" json:"name"`
-	// mark this group is default, default 1
-	DefaultGroup int `validate:"required" comment:"mark this group is default, default 1" json:"default_group"`
-	//
-	CreateTime time.Time `validate:"required" comment:"" json:"create_time"`
-	//
-	UpdateTime time.Time `validate:"required" comment:"" json:"update_time"`
+	Name     string `validate:"required,gt=0,lte=50" json:"name"`
+	IsPublic bool   `json:"is_public"`
+	UserID   string `json:"-"`
 }
 
-// UpdateCollectionGroupReq update collection group request
+// UpdateCollectionGroupReq rename the collection group or change its visibility
 type UpdateCollectionGroupReq struct {
-	//
-	ID int64 `validate:"required" comment:"" json:"id"`
-	//
-	UserID int64 `validate:"omitempty" comment:"" json:"user_id"`
-	// the collection group name
-	Name string `validate:"omitempty,gt=0,lte=50" comment:"the collection group name" json:"name"`
-	// mark this group is default, default 1
-	DefaultGroup int `validate:"omitempty" comment:"mark this group is default, default 1" json:"default_group"`
-	//
-	CreateTime time.Time `validate:"omitempty" comment:"" json:"create_time"`
-	//
-	UpdateTime time.Time `validate:"omitempty" comment:"" json:"update_time"`
+	ID       string `validate:"required" json:"id"`
+	Name     string `validate:"required,gt=0,lte=50" json:"name"`
+	IsPublic bool   `json:"is_public"`
+	UserID   string `json:"-"`
+}
+
+// RemoveCollectionGroupReq remove collection group request, the collections in it are moved to the default group
+type RemoveCollectionGroupReq struct {
+	ID     string `validate:"required" json:"id"`
+	UserID string `json:"-"`
+}
+
+// SortCollectionGroupReq sort the collection groups in the order of the ids
+type SortCollectionGroupReq struct {
+	IDs    []string `validate:"required,gt=0,lte=100" json:"ids"`
+	UserID string   `json:"-"`
 }
 
 // GetCollectionGroupResp get collection group response
 type GetCollectionGroupResp struct {
-	//
-	ID int64 `json:"id"`
-	//
-	UserID int64 `json:"user_id"`
-	// the collection group name
+	ID   string `json:"id"`
 	Name string `json:"name"`
-	// mark this group is default, default 1
-	DefaultGroup int `json:"default_group"`
-	//
-	CreateTime time.Time `json:"create_time"`
-	//
-	UpdateTime time.Time `json:"update_time"`
+	// DefaultGroup the default group can not be removed
+	DefaultGroup    bool   `json:"default_group"`
+	IsPublic        bool   `json:"is_public"`
+	ShareURL        string `json:"share_url,omitempty"`
+	RSSURL          string `json:"rss_url,omitempty"`
+	CollectionCount int64  `json:"collection_count"`
+	CreatedAt       int64  `json:"created_at"`
+}
+
+// GetCollectionPageReq get the collections of the login user in the group
+type GetCollectionPageReq struct {
+	GroupID  string `validate:"required" form:"group_id"`
+	Page     int    `validate:"omitempty,min=1" form:"page"`
+	PageSize int    `validate:"omitempty,min=1" form:"page_size"`
+	UserID   string `json:"-"`
+}
+
+// GetCollectionResp get collection response
+type GetCollectionResp struct {
+	ObjectID  string `json:"object_id"`
+	GroupID   string `json:"group_id"`
+	Note      string `json:"note,omitempty"`
+	CreatedAt int64  `json:"created_at"`
+	// Question nil if the question is deleted
+	Question *QuestionInfo `json:"question"`
+}
+
+// MoveCollectionReq move the collection to another group
+type MoveCollectionReq struct {
+	ObjectID string `validate:"required" json:"object_id"`
+	GroupID  string `validate:"required" json:"group_id"`
+	UserID   string `json:"-"`
+}
+
+// UpdateCollectionNoteReq update the private note of the collection
+type UpdateCollectionNoteReq struct {
+	ObjectID string `validate:"required" json:"object_id"`
+	Note     string `validate:"omitempty,lte=1000" json:"note"`
+	UserID   string `json:"-"`
+}
+
+// GetSharedCollectionGroupReq get the public group by the share token
+type GetSharedCollectionGroupReq struct {
+	Token    string `validate:"required" form:"token"`
+	Page     int    `validate:"omitempty,min=1" form:"page"`
+	PageSize int    `validate:"omitempty,min=1" form:"page_size"`
+}
+
+// GetSharedCollectionGroupResp get shared collection group response
+type GetSharedCollectionGroupResp struct {
+	Name     string          `json:"name"`
+	RSSURL   string          `json:"rss_url"`
+	UserInfo *UserBasicInfo  `json:"user_info"`
+	Count    int64           `json:"count"`
+	List     []*QuestionInfo `json:"list"`
 }
@@ -20,6 +20,9 @@ type AddCommentReq struct {
 	MentionUsernameList []string `validate:"omitempty" json:"mention_username_list"`
 	// user id
 	UserID string `json:"-"`
+	// the ip and user agent of the user, used by the automated moderation
+	IP        string `json:"-"`
+	UserAgent string `json:"-"`
 	// whether user can add it
 	CanAdd bool `json:"-"`
 	// whether user can edit it
@@ -45,7 +48,8 @@ type UpdateCommentReq struct {
 	// parsed comment content
 	ParsedText string `validate:"omitempty" json:"parsed_text"`
 	// user id
-	UserID string `json:"-"`
+	UserID  string `json:"-"`
+	IsAdmin bool   `json:"-"`
 }
 
 // GetCommentListReq get comment list all request
@@ -81,19 +85,54 @@ type GetCommentWithPageReq struct {
 	// query condition
 	QueryCond string `validate:"omitempty,oneof=vote" form:"query_cond"`
 	// user id
-	UserID string `json:"-"`
+	UserID  string `json:"-"`
+	IsAdmin bool   `json:"-"`
+	// whether user can edit it
+	CanEdit bool `json:"-"`
+	// whether user can delete it
+	CanDelete bool `json:"-"`
+}
+
+// GetCommentReplyPageReq get the replies of the comment page request
+type GetCommentReplyPageReq struct {
+	// page
+	Page int `validate:"omitempty,min=1" form:"page"`
+	// page size
+	PageSize int `validate:"omitempty,min=1" form:"page_size"`
+	// the comment whose replies are listed
+	CommentID string `validate:"required" form:"comment_id"`
+	// user id
+	UserID  string `json:"-"`
+	IsAdmin bool   `json:"-"`
 	// whether user can edit it
 	CanEdit bool `json:"-"`
 	// whether user can delete it
 	CanDelete bool `json:"-"`
 }
 
+// ConvertCommentToAnswerReq convert the comment to an answer of the question request
+type ConvertCommentToAnswerReq struct {
+	// comment id
+	CommentID string `validate:"required" json:"comment_id"`
+	// user id
+	UserID string `json:"-"`
+}
+
+// ConvertCommentToAnswerResp convert the comment to an answer of the question response
+type ConvertCommentToAnswerResp struct {
+	// the answer converted from the comment
+	AnswerID string `json:"answer_id"`
+	// question id
+	QuestionID string `json:"question_id"`
+}
+
 // GetCommentReq get comment list page request
 type GetCommentReq struct {
 	// object id
 	ID string `validate:"required" form:"id"`
 	// user id
-	UserID string `json:"-"`
+	UserID  string `json:"-"`
+	IsAdmin bool   `json:"-"`
 	// whether user can edit it
 	CanEdit bool `json:"-"`
 	// whether user can delete it
@@ -140,6 +179,15 @@ type GetCommentResp struct {
 	// reply user status
 	ReplyUserStatus string `json:"reply_user_status"`
 
+	// the comment which it is nested under, 0 means the top level
+	ParentCommentID string `json:"parent_comment_id"`
+	// the nesting level in the thread, 0 means the top level
+	Depth int `json:"depth"`
+	// the number of the replies nested under it
+	ReplyCount int `json:"reply_count"`
+	// the first replies nested under it, the others are got by the reply page
+	Replies []*GetCommentResp `json:"replies"`
+
 	// MemberActions
 	MemberActions []*PermissionMemberAction `json:"member_actions"`
 }
@@ -150,6 +198,8 @@ func (r *GetCommentResp) SetFromComment(comment *entity.Comment) {
 	r.CreatedAt = comment.CreatedAt.Unix()
 	r.ReplyUserID = comment.GetReplyUserID()
 	r.ReplyCommentID = comment.GetReplyCommentID()
+	r.ParentCommentID = comment.ParentCommentID
+	r.Depth = comment.Depth
 }
 
 // GetCommentPersonalWithPageReq get comment list page request
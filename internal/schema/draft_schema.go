@@ -0,0 +1,71 @@
+package schema
+
+import (
+	"encoding/json"
+
+	"answer/internal/entity"
+)
+
+// SaveDraftReq autosave the draft, the draft of the same context is overwritten
+type SaveDraftReq struct {
+	// DraftType question, answer or edit
+	DraftType string `validate:"required,oneof=question answer edit" json:"draft_type"`
+	// ObjectID 0 for the new question, the question id for the answer, the post id for the edit
+	ObjectID string   `validate:"required" json:"object_id"`
+	Title    string   `validate:"omitempty,lte=150" json:"title"`
+	Content  string   `validate:"omitempty,lte=65535" json:"content"`
+	Tags     []string `validate:"omitempty,lte=5,dive,lte=35" json:"tags"`
+	UserID   string   `json:"-"`
+}
+
+// SaveDraftResp save draft response
+type SaveDraftResp struct {
+	ID        string `json:"id"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+// GetDraftReq get the draft of the context
+type GetDraftReq struct {
+	DraftType string `validate:"required,oneof=question answer edit" form:"draft_type"`
+	ObjectID  string `validate:"required" form:"object_id"`
+	UserID    string `json:"-"`
+}
+
+// RemoveDraftReq discard the draft of the context
+type RemoveDraftReq struct {
+	DraftType string `validate:"required,oneof=question answer edit" json:"draft_type"`
+	ObjectID  string `validate:"required" json:"object_id"`
+	UserID    string `json:"-"`
+}
+
+// GetDraftPageReq get the drafts of the login user
+type GetDraftPageReq struct {
+	Page     int    `validate:"omitempty,min=1" form:"page"`
+	PageSize int    `validate:"omitempty,min=1" form:"page_size"`
+	UserID   string `json:"-"`
+}
+
+// DraftResp draft response
+type DraftResp struct {
+	ID        string   `json:"id"`
+	DraftType string   `json:"draft_type"`
+	ObjectID  string   `json:"object_id"`
+	Title     string   `json:"title"`
+	Content   string   `json:"content"`
+	Tags      []string `json:"tags"`
+	CreatedAt int64    `json:"created_at"`
+	UpdatedAt int64    `json:"updated_at"`
+}
+
+// GetFromEntity get the response from the draft entity
+func (r *DraftResp) GetFromEntity(draft *entity.Draft) {
+	r.ID = draft.ID
+	r.DraftType = draft.DraftType
+	r.ObjectID = draft.ObjectID
+	r.Title = draft.Title
+	r.Content = draft.Content
+	r.Tags = make([]string, 0)
+	_ = json.Unmarshal([]byte(draft.Tags), &r.Tags)
+	r.CreatedAt = draft.CreatedAt.Unix()
+	r.UpdatedAt = draft.UpdatedAt.Unix()
+}
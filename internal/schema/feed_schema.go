@@ -0,0 +1,52 @@
+package schema
+
+// the types of the feed item
+const (
+	FeedTypeNewQuestion    = "new_question"
+	FeedTypeNewAnswer      = "new_answer"
+	FeedTypeQuestionEdited = "question_edited"
+	FeedTypeAnswerEdited   = "answer_edited"
+)
+
+// the reasons why the item is in the feed
+const (
+	FeedReasonFollowedUser     = "followed_user"
+	FeedReasonFollowedTag      = "followed_tag"
+	FeedReasonFollowedQuestion = "followed_question"
+)
+
+// GetFeedReq get the following feed of the login user
+type GetFeedReq struct {
+	// Cursor the next cursor of the previous page, empty for the first page
+	Cursor   string `validate:"omitempty" form:"cursor"`
+	PageSize int    `validate:"omitempty,min=1,max=50" form:"page_size"`
+	UserID   string `json:"-"`
+}
+
+// GetFeedResp get feed response
+type GetFeedResp struct {
+	List []*FeedItem `json:"list"`
+	// NextCursor empty if there are no more items
+	NextCursor string `json:"next_cursor"`
+}
+
+// FeedItem the new post or edit about the followed objects
+type FeedItem struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Reason     string `json:"reason"`
+	QuestionID string `json:"question_id"`
+	AnswerID   string `json:"answer_id,omitempty"`
+	Title      string `json:"title"`
+	Excerpt    string `json:"excerpt"`
+	CreatedAt  int64  `json:"created_at"`
+	// UserInfo the user who posted or edited
+	UserInfo *UserBasicInfo `json:"user_info"`
+}
+
+// GetFeedDigestResp the digest of the following feed of the last period
+type GetFeedDigestResp struct {
+	StartAt int64       `json:"start_at"`
+	EndAt   int64       `json:"end_at"`
+	List    []*FeedItem `json:"list"`
+}
@@ -32,3 +32,17 @@ type UpdateFollowTagsReq struct {
 	// user id
 	UserID string `json:"-"`
 }
+
+// FollowUserReq follow user request
+type FollowUserReq struct {
+	// the username of the user to follow
+	Username string `validate:"required,gt=0,lte=30" json:"username"`
+	// is cancel
+	IsCancel bool   `validate:"omitempty" json:"is_cancel"`
+	UserID   string `json:"-"`
+}
+
+// GetFollowingUsersReq get the users followed by the login user
+type GetFollowingUsersReq struct {
+	UserID string `json:"-"`
+}
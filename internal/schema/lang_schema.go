@@ -0,0 +1,7 @@
+package schema
+
+// RemoveLangBundleReq remove the custom translation bundle request
+type RemoveLangBundleReq struct {
+	// language name of the bundle, such as pt_BR
+	Language string `validate:"required,gt=0,lte=16" json:"language"`
+}
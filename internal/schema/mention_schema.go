@@ -0,0 +1,10 @@
+package schema
+
+// SearchMentionUsersReq search the users to mention, the participants of the question come first
+type SearchMentionUsersReq struct {
+	// the prefix of the username or display name
+	Keyword string `validate:"omitempty,lte=30" form:"keyword"`
+	// the question which is being answered or edited
+	QuestionID string `validate:"omitempty" form:"question_id"`
+	UserID     string `json:"-"`
+}
@@ -0,0 +1,32 @@
+package schema
+
+import "answer/pkg/moderation"
+
+// ModerationVerdict the verdict of the automated moderation of the new post
+type ModerationVerdict struct {
+	// Action pass, flag, hold or reject
+	Action string
+	Score  int
+	Hits   []*moderation.Hit
+}
+
+// GetModerationResultPageReq get moderation result page request
+type GetModerationResultPageReq struct {
+	ObjectID string `validate:"omitempty" form:"object_id"`
+	Action   string `validate:"omitempty,oneof=flag hold reject" form:"action"`
+	Page     int    `validate:"omitempty,min=1" form:"page"`
+	PageSize int    `validate:"omitempty,min=1" form:"page_size"`
+}
+
+// GetModerationResultResp get moderation result response
+type GetModerationResultResp struct {
+	ID         string            `json:"id"`
+	CreatedAt  int64             `json:"created_at"`
+	ObjectType string            `json:"object_type"`
+	ObjectID   string            `json:"object_id"`
+	Score      int               `json:"score"`
+	Action     string            `json:"action"`
+	Hits       []*moderation.Hit `json:"hits"`
+	Excerpt    string            `json:"excerpt"`
+	UserInfo   *UserBasicInfo    `json:"user_info"`
+}
@@ -0,0 +1,56 @@
+package schema
+
+// ModeratorMessageTemplate the canned message which the moderator can start with
+type ModeratorMessageTemplate struct {
+	Key     string `json:"key"`
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// AddModeratorMessageReq send the message to the user by moderator
+type AddModeratorMessageReq struct {
+	UserID string `validate:"required" json:"user_id"`
+	// ThreadID reply to the existing thread, start a new thread if empty
+	ThreadID    string `validate:"omitempty" json:"thread_id"`
+	TemplateKey string `validate:"omitempty,gt=0,lte=64" json:"template_key"`
+	Content     string `validate:"required,gt=0,lte=5000" json:"content"`
+	// SendEmail also send the message to the email of the user
+	SendEmail bool   `json:"send_email"`
+	SenderID  string `json:"-"`
+}
+
+// ReplyModeratorMessageReq reply the thread by the user
+type ReplyModeratorMessageReq struct {
+	ThreadID string `validate:"required" json:"thread_id"`
+	Content  string `validate:"required,gt=0,lte=5000" json:"content"`
+	UserID   string `json:"-"`
+}
+
+// GetModeratorMessageThreadReq get the thread of the login user
+type GetModeratorMessageThreadReq struct {
+	ThreadID string `validate:"required" form:"thread_id"`
+	UserID   string `json:"-"`
+}
+
+// GetModeratorMessageThreadListReq get all the threads about the user by moderator
+type GetModeratorMessageThreadListReq struct {
+	UserID string `validate:"required" form:"user_id"`
+}
+
+// ModeratorMessageResp moderator message response
+type ModeratorMessageResp struct {
+	ID          string `json:"id"`
+	CreatedAt   int64  `json:"created_at"`
+	TemplateKey string `json:"template_key"`
+	Content     string `json:"content"`
+	// FromModerator false means the message is the reply of the user
+	FromModerator bool           `json:"from_moderator"`
+	SenderInfo    *UserBasicInfo `json:"sender_info"`
+}
+
+// ModeratorMessageThreadResp moderator message thread response
+type ModeratorMessageThreadResp struct {
+	ID        string                  `json:"id"`
+	CreatedAt int64                   `json:"created_at"`
+	Messages  []*ModeratorMessageResp `json:"messages"`
+}
@@ -3,15 +3,18 @@ package schema
 const (
 	NotificationTypeInbox       = 1
 	NotificationTypeAchievement = 2
-	NotificationNotRead         = 1
-	NotificationRead            = 2
-	NotificationStatusNormal    = 1
-	NotificationStatusDelete    = 10
+	// NotificationTypeModeratorMessage the private message between the moderators and the user
+	NotificationTypeModeratorMessage = 3
+	NotificationNotRead              = 1
+	NotificationRead                 = 2
+	NotificationStatusNormal         = 1
+	NotificationStatusDelete         = 10
 )
 
 var NotificationType = map[string]int{
-	"inbox":       NotificationTypeInbox,
-	"achievement": NotificationTypeAchievement,
+	"inbox":             NotificationTypeInbox,
+	"achievement":       NotificationTypeAchievement,
+	"moderator_message": NotificationTypeModeratorMessage,
 }
 
 type NotificationContent struct {
@@ -22,7 +25,7 @@ type NotificationContent struct {
 	ObjectInfo         ObjectInfo     `json:"object_info"`
 	Rank               int            `json:"rank"`
 	NotificationAction string         `json:"notification_action,omitempty"`
-	Type               int            `json:"-"` //	1 inbox 2 achievement
+	Type               int            `json:"-"` //	1 inbox 2 achievement 3 moderator message
 	IsRead             bool           `json:"is_read"`
 	UpdateTime         int64          `json:"update_time"`
 }
@@ -40,7 +43,7 @@ type NotificationMsg struct {
 	TriggerUserID string
 	// receive notification user id
 	ReceiverUserID string
-	// type 1 inbox 2 achievement
+	// type 1 inbox 2 achievement 3 moderator message
 	Type int
 	// notification title
 	Title string
@@ -64,21 +67,23 @@ type ObjectInfo struct {
 type RedDot struct {
 	Inbox       int64 `json:"inbox"`
 	Achievement int64 `json:"achievement"`
-	Revision    int64 `json:"revision"`
-	CanRevision bool  `json:"can_revision"`
+	// ModeratorMessage whether there are new messages from the moderators
+	ModeratorMessage int64 `json:"moderator_message"`
+	Revision         int64 `json:"revision"`
+	CanRevision      bool  `json:"can_revision"`
 }
 
 type NotificationSearch struct {
 	Page     int    `json:"page" form:"page"`           //Query number of pages
 	PageSize int    `json:"page_size" form:"page_size"` //Search page size
 	Type     int    `json:"-" form:"-"`
-	TypeStr  string `json:"type" form:"type"` // inbox achievement
+	TypeStr  string `json:"type" form:"type"` // inbox achievement moderator_message
 	UserID   string `json:"-"`
 }
 
 type NotificationClearRequest struct {
 	UserID            string `json:"-"`
-	TypeStr           string `json:"type" form:"type"` // inbox achievement
+	TypeStr           string `json:"type" form:"type"` // inbox achievement moderator_message
 	CanReviewQuestion bool   `json:"-"`
 	CanReviewAnswer   bool   `json:"-"`
 	CanReviewTag      bool   `json:"-"`
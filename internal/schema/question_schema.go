@@ -14,6 +14,8 @@ type CloseQuestionReq struct {
 	CloseType int    `json:"close_type" ` // close_type
 	CloseMsg  string `json:"close_msg" `  // close_type
 	IsAdmin   bool   `json:"-"`
+	// the moderator of the tags of the question closes it like the admin
+	IsTagModerator bool `json:"-"`
 }
 
 type CloseQuestionMeta struct {
@@ -21,6 +23,42 @@ type CloseQuestionMeta struct {
 	CloseMsg  string `json:"close_msg"`
 }
 
+// QuestionCloseVoteReq vote to close or reopen the question request
+type QuestionCloseVoteReq struct {
+	// question id
+	ID string `validate:"required" json:"id"`
+	// close type, the reason of the close vote
+	CloseType int `validate:"omitempty,min=0" json:"close_type"`
+	// close message, the detail of the reason
+	CloseMsg string `validate:"omitempty,lte=255" json:"close_msg"`
+	UserID   string `json:"-"`
+	// the vote of the moderator of the tags of the question closes or reopens it at once
+	IsTagModerator bool `json:"-"`
+}
+
+// GetQuestionCloseVoteReq get the close or reopen votes of the question request
+type GetQuestionCloseVoteReq struct {
+	// question id
+	ID     string `validate:"required" form:"id"`
+	UserID string `json:"-"`
+}
+
+// QuestionCloseVoteResp the close votes of the available question, or the reopen votes of the closed question
+type QuestionCloseVoteResp struct {
+	// Enabled whether the community close voting is enabled
+	Enabled bool `json:"enabled"`
+	// VoteType close or reopen
+	VoteType string `json:"vote_type"`
+	// Votes the number of the votes which are not expired
+	Votes int `json:"votes"`
+	// Required the number of the votes which close or reopen the question
+	Required int `json:"required"`
+	// Voted whether the user has voted
+	Voted bool `json:"voted"`
+	// Status the status of the question
+	Status int `json:"status"`
+}
+
 type QuestionAdd struct {
 	// question title
 	Title string `validate:"required,gte=6,lte=150" json:"title"`
@@ -30,8 +68,19 @@ type QuestionAdd struct {
 	HTML string `validate:"required,gte=6,lte=65535" json:"html"`
 	// tags
 	Tags []*TagItem `validate:"required,dive" json:"tags"`
+	// language code of the content, such as en or zh, detected from the content if empty
+	Language string `validate:"omitempty,lte=16" json:"language"`
+	// the question is posted as a community wiki
+	CommunityWiki bool `validate:"omitempty" json:"community_wiki"`
+	// the content of the answer to the question self, the question and the answer are posted together
+	AnswerContent string `validate:"omitempty,gte=6,lte=65535" json:"answer_content"`
+	// the html of the answer to the question self
+	AnswerHTML string `validate:"required_with=AnswerContent,omitempty,gte=6,lte=65535" json:"answer_html"`
 	// user id
 	UserID string `json:"-"`
+	// the ip and user agent of the user, used by the automated moderation
+	IP        string `json:"-"`
+	UserAgent string `json:"-"`
 	QuestionPermission
 }
 
@@ -44,6 +93,12 @@ type QuestionPermission struct {
 	CanDelete bool `json:"-"`
 	// whether user can close it
 	CanClose bool `json:"-"`
+	// whether user can review the pending question
+	CanReview bool `json:"-"`
+	// whether user can vote to close it
+	CanCloseVote bool `json:"-"`
+	// whether user can vote to reopen it
+	CanReopenVote bool `json:"-"`
 }
 
 type CheckCanQuestionUpdate struct {
@@ -67,20 +122,27 @@ type QuestionUpdate struct {
 	Tags []*TagItem `validate:"required,dive" json:"tags"`
 	// edit summary
 	EditSummary string `validate:"omitempty" json:"edit_summary"`
+	// language code of the content, such as en or zh, detected from the content if empty
+	Language string `validate:"omitempty,lte=16" json:"language"`
+	// turn the question into a community wiki by the author or admin, the community wiki can not be turned back
+	CommunityWiki bool `validate:"omitempty" json:"community_wiki"`
 	// user id
 	UserID       string `json:"-"`
 	IsAdmin      bool   `json:"-"`
 	NoNeedReview bool   `json:"-"`
+	// whether user can edit the community wiki without review
+	CanEditWiki bool `json:"-"`
 	QuestionPermission
 }
 
 type QuestionBaseInfo struct {
 	ID              string `json:"id" `
-	Title           string `json:"title" xorm:"title"`                       // title
-	ViewCount       int    `json:"view_count" xorm:"view_count"`             // view count
-	AnswerCount     int    `json:"answer_count" xorm:"answer_count"`         // answer count
-	CollectionCount int    `json:"collection_count" xorm:"collection_count"` // collection count
-	FollowCount     int    `json:"follow_count" xorm:"follow_count"`         // follow count
+	Title           string `json:"title" xorm:"title"`                         // title
+	ViewCount       int    `json:"view_count" xorm:"view_count"`               // view count
+	UniqueViewCount int    `json:"unique_view_count" xorm:"unique_view_count"` // unique view count
+	AnswerCount     int    `json:"answer_count" xorm:"answer_count"`           // answer count
+	CollectionCount int    `json:"collection_count" xorm:"collection_count"`   // collection count
+	FollowCount     int    `json:"follow_count" xorm:"follow_count"`           // follow count
 	Status          string `json:"status"`
 	AcceptedAnswer  bool   `json:"accepted_answer"`
 }
@@ -104,6 +166,8 @@ type QuestionInfo struct {
 	PostUpdateTime       int64          `json:"update_time"`
 	QuestionUpdateTime   int64          `json:"edit_time"`
 	Status               int            `json:"status"`
+	Language             string         `json:"language"`
+	CommunityWiki        bool           `json:"community_wiki"`
 	Operation            *Operation     `json:"operation,omitempty"`
 	UserID               string         `json:"-" `
 	LastEditUserID       string         `json:"-" `
@@ -179,6 +243,7 @@ type UserQuestionInfo struct {
 	VoteCount        int           `json:"vote_count"`
 	Tags             []interface{} `json:"tags"`
 	ViewCount        int           `json:"view_count"`
+	UniqueViewCount  int           `json:"unique_view_count"`
 	AnswerCount      int           `json:"answer_count"`
 	CollectionCount  int           `json:"collection_count"`
 	CreateTime       int           `json:"create_time"`
@@ -195,8 +260,20 @@ type QuestionSearch struct {
 	TagIDs   []string `json:"-" form:"-"`               // Search tag
 	UserName string   `json:"username" form:"username"` // Search username
 	UserID   string   `json:"-" form:"-"`
+	// FollowTagIDs the tags followed by the login user, used by the for_you order
+	FollowTagIDs []string `json:"-" form:"-"`
+	// Language filter by the language code of the content, "all" means no filter,
+	// the content languages of the login user are used if empty
+	Language string `json:"language" form:"language"`
+	// ContentLanguages the language codes to filter by, the questions without language are always included
+	ContentLanguages []string `json:"-" form:"-"`
+	// IgnoredTagIDs the tags ignored by the login user, the questions with them are hidden
+	IgnoredTagIDs []string `json:"-" form:"-"`
 }
 
+// QuestionSearchLanguageAll search the questions of all languages
+const QuestionSearchLanguageAll = "all"
+
 type CmsQuestionSearch struct {
 	Page      int    `json:"page" form:"page"`           // Query number of pages
 	PageSize  int    `json:"page_size" form:"page_size"` // Search page size
@@ -33,3 +33,33 @@ type GetRankPersonalWithPageResp struct {
 	// rank type
 	RankType string `json:"rank_type"`
 }
+
+// RecalculateReputationReq recalculate the reputation of a batch of users request
+type RecalculateReputationReq struct {
+	// only report the differences, nothing is changed
+	DryRun bool `json:"dry_run"`
+	// the users after the user id are recalculated, empty for the first batch
+	AfterUserID string `validate:"omitempty" json:"after_user_id"`
+	// the number of the users in the batch, 100 by default
+	Limit int `validate:"omitempty,min=1,max=1000" json:"limit"`
+}
+
+// RecalculateReputationResp recalculate the reputation of a batch of users response
+type RecalculateReputationResp struct {
+	// the number of the users which are checked in the batch
+	Checked int `json:"checked"`
+	// the users whose reputation or activities differ from the recalculation
+	Diffs []*ReputationDiff `json:"diffs"`
+	// pass it as after_user_id to recalculate the next batch, empty if all the users are done
+	NextUserID string `json:"next_user_id"`
+}
+
+// ReputationDiff the difference between the stored and the recalculated reputation of the user
+type ReputationDiff struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	OldRank  int    `json:"old_rank"`
+	NewRank  int    `json:"new_rank"`
+	// the number of the activities whose reputation is changed
+	ChangedActivities int `json:"changed_activities"`
+}
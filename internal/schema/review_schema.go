@@ -0,0 +1,84 @@
+package schema
+
+import "answer/internal/base/constant"
+
+const (
+	// ReviewActionApprove the post looks ok
+	ReviewActionApprove = "approve"
+	// ReviewActionEdit the reviewer edits the held post, then it is approved
+	ReviewActionEdit = "edit"
+	// ReviewActionReject the post should be deleted, or the closed question should stay closed
+	ReviewActionReject = "reject"
+	// ReviewActionSkip the reviewer is not sure, the review is not shown to the reviewer again
+	ReviewActionSkip = "skip"
+)
+
+// ReviewPermission the object types which the reviewer can review
+type ReviewPermission struct {
+	CanReviewQuestion bool `json:"-"`
+	CanReviewAnswer   bool `json:"-"`
+}
+
+// GetCanReviewObjectTypes get the object types which the reviewer can review
+func (r ReviewPermission) GetCanReviewObjectTypes() []int {
+	objectTypes := make([]int, 0)
+	if r.CanReviewQuestion {
+		objectTypes = append(objectTypes, constant.ObjectTypeStrMapping[constant.QuestionObjectType])
+	}
+	if r.CanReviewAnswer {
+		objectTypes = append(objectTypes, constant.ObjectTypeStrMapping[constant.AnswerObjectType])
+	}
+	return objectTypes
+}
+
+// GetReviewQueuesReq get review queues request
+type GetReviewQueuesReq struct {
+	ReviewPermission
+	UserID string `json:"-"`
+}
+
+// GetReviewQueueResp get review queue response
+type GetReviewQueueResp struct {
+	// Queue first_post, late_answer, low_quality, reopen_vote or moderation
+	Queue string `json:"queue"`
+	// Count the number of the pending reviews in the queue
+	Count int64 `json:"count"`
+}
+
+// GetReviewPageReq get review page request
+type GetReviewPageReq struct {
+	Queue    string `validate:"required,oneof=first_post late_answer low_quality reopen_vote moderation" form:"queue"`
+	Page     int    `validate:"omitempty,min=1" form:"page"`
+	PageSize int    `validate:"omitempty,min=1" form:"page_size"`
+	ReviewPermission
+	UserID string `json:"-"`
+}
+
+// GetReviewResp get review response
+type GetReviewResp struct {
+	ID         string `json:"id"`
+	Queue      string `json:"queue"`
+	ObjectType string `json:"object_type"`
+	ObjectID   string `json:"object_id"`
+	QuestionID string `json:"question_id"`
+	Title      string `json:"title"`
+	Content    string `json:"content"`
+	// Hold whether the post is hidden from other users until it is approved
+	Hold      bool           `json:"hold"`
+	CreatedAt int64          `json:"created_at"`
+	UserInfo  *UserBasicInfo `json:"user_info"`
+}
+
+// ReviewReq review request
+type ReviewReq struct {
+	ID     string `validate:"required" json:"id"`
+	Action string `validate:"required,oneof=approve edit reject skip" json:"action"`
+	// Title the edited title of the question, the title is kept if it is empty
+	Title string `validate:"omitempty,gte=6,lte=150" json:"title"`
+	// Content the edited content of the post, it is required by the edit action
+	Content string `validate:"required_if=Action edit,omitempty,gte=6,lte=65535" json:"content"`
+	// HTML the edited html of the post
+	HTML string `validate:"required_if=Action edit,omitempty,gte=6,lte=65535" json:"html"`
+	ReviewPermission
+	UserID string `json:"-"`
+}
@@ -93,3 +93,18 @@ type GetRevisionResp struct {
 	UserInfo        UserBasicInfo `json:"user_info"`
 	Log             string        `json:"reason"`
 }
+
+// GetContributorListReq get contributor list request
+type GetContributorListReq struct {
+	// object id
+	ObjectID string `validate:"required" form:"object_id"`
+}
+
+// ContributorInfo the user who contributes to the post by the revisions
+type ContributorInfo struct {
+	UserInfo *UserBasicInfo `json:"user_info"`
+	// the number of the revisions by the user
+	EditCount int `json:"edit_count"`
+	// the time of the last revision by the user
+	LastEditAt int64 `json:"last_edit_at"`
+}
@@ -6,6 +6,9 @@ type SearchDTO struct {
 	Page   int    `validate:"omitempty,min=1" form:"page,default=1" json:"page"`         //Query number of pages
 	Size   int    `validate:"omitempty,min=1,max=50" form:"size,default=30" json:"size"` //Search page size
 	Order  string `validate:"required,oneof=newest active score relevance" form:"order,default=relevance" json:"order" enums:"newest,active,score,relevance"`
+	// Language filter by the language code of the content, "all" means no filter,
+	// the content languages of the login user are used if empty
+	Language string `validate:"omitempty,lte=16" form:"language" json:"language"`
 }
 
 type SearchObject struct {
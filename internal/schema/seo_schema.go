@@ -0,0 +1,97 @@
+package schema
+
+import "encoding/xml"
+
+const (
+	SitemapXMLNS      = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	SitemapXHTMLNS    = "http://www.w3.org/1999/xhtml"
+	SitemapDateFormat = "2006-01-02T15:04:05Z07:00"
+)
+
+// SitemapURLSet sitemap url set, the root of a sitemap file
+type SitemapURLSet struct {
+	XMLName xml.Name      `xml:"urlset"`
+	XMLNS   string        `xml:"xmlns,attr"`
+	XHTMLNS string        `xml:"xmlns:xhtml,attr,omitempty"`
+	URLs    []*SitemapURL `xml:"url"`
+}
+
+// SitemapURL sitemap url
+type SitemapURL struct {
+	Loc        string                 `xml:"loc"`
+	LastMod    string                 `xml:"lastmod,omitempty"`
+	Alternates []*SitemapAlternateURL `xml:"xhtml:link,omitempty"`
+}
+
+// SitemapAlternateURL the alternate url of the page in the language, annotated with hreflang
+type SitemapAlternateURL struct {
+	Rel      string `xml:"rel,attr"`
+	HrefLang string `xml:"hreflang,attr"`
+	Href     string `xml:"href,attr"`
+}
+
+// SitemapIndex sitemap index, the root of a sitemap index file
+type SitemapIndex struct {
+	XMLName  xml.Name           `xml:"sitemapindex"`
+	XMLNS    string             `xml:"xmlns,attr"`
+	Sitemaps []*SitemapIndexURL `xml:"sitemap"`
+}
+
+// SitemapIndexURL sitemap index url
+type SitemapIndexURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// PageMeta page metadata rendered into html head for crawlers
+type PageMeta struct {
+	Title        string
+	Description  string
+	Keywords     string
+	CanonicalURL string
+	// Type open graph type, such as website or article
+	Type     string
+	Image    string
+	SiteName string
+	// JSONLD schema.org structured data
+	JSONLD string
+	// Language the language of the page content, rendered as hreflang
+	Language string
+}
+
+// QAPageJSONLD schema.org QAPage structured data
+type QAPageJSONLD struct {
+	Context    string          `json:"@context"`
+	Type       string          `json:"@type"`
+	MainEntity *QAPageQuestion `json:"mainEntity"`
+}
+
+// QAPageQuestion schema.org Question
+type QAPageQuestion struct {
+	Type            string          `json:"@type"`
+	Name            string          `json:"name"`
+	Text            string          `json:"text"`
+	AnswerCount     int             `json:"answerCount"`
+	UpvoteCount     int             `json:"upvoteCount"`
+	DateCreated     string          `json:"dateCreated"`
+	InLanguage      string          `json:"inLanguage,omitempty"`
+	Author          *QAPageAuthor   `json:"author,omitempty"`
+	AcceptedAnswer  *QAPageAnswer   `json:"acceptedAnswer,omitempty"`
+	SuggestedAnswer []*QAPageAnswer `json:"suggestedAnswer,omitempty"`
+}
+
+// QAPageAnswer schema.org Answer
+type QAPageAnswer struct {
+	Type        string        `json:"@type"`
+	Text        string        `json:"text"`
+	UpvoteCount int           `json:"upvoteCount"`
+	DateCreated string        `json:"dateCreated"`
+	URL         string        `json:"url"`
+	Author      *QAPageAuthor `json:"author,omitempty"`
+}
+
+// QAPageAuthor schema.org Person
+type QAPageAuthor struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+}
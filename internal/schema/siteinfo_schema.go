@@ -3,6 +3,7 @@ package schema
 import (
 	"fmt"
 	"net/url"
+	"strings"
 )
 
 // SiteGeneralReq site general request
@@ -53,6 +54,138 @@ type SiteLegalReq struct {
 	PrivacyPolicyParsedText    string `json:"privacy_policy_parsed_text"`
 }
 
+// SiteSeoReq site seo request
+type SiteSeoReq struct {
+	// Robots the content of robots.txt, if empty the default content will be used
+	Robots string `validate:"omitempty,lte=65535" form:"robots" json:"robots"`
+}
+
+// SiteLoginReq site login request
+type SiteLoginReq struct {
+	// LoginRequired if true, anonymous user can not visit any content of the site
+	LoginRequired bool `validate:"omitempty" form:"login_required" json:"login_required"`
+	// InviteOnly if true, user can only register by the invite link which is issued by admin
+	InviteOnly bool `validate:"omitempty" form:"invite_only" json:"invite_only"`
+	// AllowEmailDomains only the email of those domains can register, empty means no limit
+	AllowEmailDomains []string `validate:"omitempty,dive,gt=0,lte=256" form:"allow_email_domains" json:"allow_email_domains"`
+	// RequireAdminTwoFactor if true, admin must set up two factor authentication before using the admin pages
+	RequireAdminTwoFactor bool `validate:"omitempty" form:"require_admin_two_factor" json:"require_admin_two_factor"`
+}
+
+// SiteReviewReq site review queue request
+type SiteReviewReq struct {
+	// FirstPostsEnabled if true, the first posts of new users are held in the first posts queue until approved
+	FirstPostsEnabled bool `validate:"omitempty" form:"first_posts_enabled" json:"first_posts_enabled"`
+	// FirstPostsCount the number of the first posts of a user which are held for review
+	FirstPostsCount int `validate:"omitempty,min=0,max=100" form:"first_posts_count" json:"first_posts_count"`
+	// LowReputation the posts of the users whose reputation is lower than it are held in the first posts queue, 0 means disabled
+	LowReputation int `validate:"omitempty,min=0" form:"low_reputation" json:"low_reputation"`
+	// LateAnswersEnabled if true, the answers to old questions are put into the late answers queue
+	LateAnswersEnabled bool `validate:"omitempty" form:"late_answers_enabled" json:"late_answers_enabled"`
+	// LateAnswerDays the answer is late if the question was asked more than these days ago
+	LateAnswerDays int `validate:"omitempty,min=0" form:"late_answer_days" json:"late_answer_days"`
+	// LowQualityEnabled if true, the very short or link-only posts are held in the low quality queue until approved
+	LowQualityEnabled bool `validate:"omitempty" form:"low_quality_enabled" json:"low_quality_enabled"`
+	// LowQualityMinLength the post whose text is shorter than it is low quality
+	LowQualityMinLength int `validate:"omitempty,min=0,max=1000" form:"low_quality_min_length" json:"low_quality_min_length"`
+	// ReopenVotesEnabled if true, the closed questions edited by their authors are put into the reopen votes queue
+	ReopenVotesEnabled bool `validate:"omitempty" form:"reopen_votes_enabled" json:"reopen_votes_enabled"`
+}
+
+// SiteModerationReq site automated moderation request.
+// The score of the post is the sum of the scores of the fired rules, the post is flagged, held or rejected
+// if the score reaches the threshold, 0 threshold means the action is disabled.
+type SiteModerationReq struct {
+	Enabled bool `validate:"omitempty" form:"enabled" json:"enabled"`
+	// BlockedKeywords the post containing the keywords fires the keyword rule
+	BlockedKeywords []string `validate:"omitempty,dive,gt=0,lte=100" form:"blocked_keywords" json:"blocked_keywords"`
+	// BlockedPatterns the post matching the regular expressions fires the keyword rule
+	BlockedPatterns []string `validate:"omitempty,dive,gt=0,lte=500" form:"blocked_patterns" json:"blocked_patterns"`
+	KeywordScore    int      `validate:"omitempty,min=0" form:"keyword_score" json:"keyword_score"`
+	// MaxLinks the post containing more links than it fires the link rule, 0 means no limit
+	MaxLinks  int `validate:"omitempty,min=0" form:"max_links" json:"max_links"`
+	LinkScore int `validate:"omitempty,min=0" form:"link_score" json:"link_score"`
+	// BlockedDomains the post linking to the domains or their sub domains fires the link rule
+	BlockedDomains []string `validate:"omitempty,dive,gt=0,lte=256" form:"blocked_domains" json:"blocked_domains"`
+	DomainScore    int      `validate:"omitempty,min=0" form:"domain_score" json:"domain_score"`
+	// RepeatScore the score of the post which repeats a recent post, 0 means the repeat rule is disabled
+	RepeatScore int `validate:"omitempty,min=0" form:"repeat_score" json:"repeat_score"`
+	// ClassifierEndpoint the url of the Akismet-style spam classifier, empty means the classifier rule is disabled
+	ClassifierEndpoint string `validate:"omitempty,url,lte=512" form:"classifier_endpoint" json:"classifier_endpoint"`
+	ClassifierAPIKey   string `validate:"omitempty,lte=256" form:"classifier_api_key" json:"classifier_api_key"`
+	ClassifierScore    int    `validate:"omitempty,min=0" form:"classifier_score" json:"classifier_score"`
+	FlagThreshold      int    `validate:"omitempty,min=0" form:"flag_threshold" json:"flag_threshold"`
+	HoldThreshold      int    `validate:"omitempty,min=0" form:"hold_threshold" json:"hold_threshold"`
+	RejectThreshold    int    `validate:"omitempty,min=0" form:"reject_threshold" json:"reject_threshold"`
+}
+
+// SiteCloseVoteReq site close vote request
+type SiteCloseVoteReq struct {
+	// Enabled if true, the users whose reputation is high enough can vote to close or reopen questions
+	Enabled bool `validate:"omitempty" form:"enabled" json:"enabled"`
+	// CloseVotes the number of the votes which close the question
+	CloseVotes int `validate:"omitempty,min=1,max=50" form:"close_votes" json:"close_votes"`
+	// ReopenVotes the number of the votes which reopen the question
+	ReopenVotes int `validate:"omitempty,min=1,max=50" form:"reopen_votes" json:"reopen_votes"`
+	// ExpireDays the votes expire after these days, 0 means never
+	ExpireDays int `validate:"omitempty,min=0,max=365" form:"expire_days" json:"expire_days"`
+}
+
+// SiteAcceptReq site accepted answer request
+type SiteAcceptReq struct {
+	// MultipleAccepted if true, the asker can accept more than one answer of the question
+	MultipleAccepted bool `validate:"omitempty" form:"multiple_accepted" json:"multiple_accepted"`
+	// ModeratorAcceptDays the moderators can accept the answer on behalf of the asker
+	// who has been inactive for these days, 0 means disabled
+	ModeratorAcceptDays int `validate:"omitempty,min=0,max=3650" form:"moderator_accept_days" json:"moderator_accept_days"`
+}
+
+// SiteCommentReq site comment request
+type SiteCommentReq struct {
+	// EditWindowMinutes the author can edit the comment in these minutes after posting it, 0 means always
+	EditWindowMinutes int `validate:"omitempty,min=0,max=10080" form:"edit_window_minutes" json:"edit_window_minutes"`
+}
+
+// SiteTagModeratorReq site tag moderator request
+type SiteTagModeratorReq struct {
+	// AutoAssignEnabled the top answerers of each tag are assigned as its moderators periodically
+	AutoAssignEnabled bool `validate:"omitempty" form:"auto_assign_enabled" json:"auto_assign_enabled"`
+	// AutoAssignCount the number of the top answerers assigned in each tag
+	AutoAssignCount int `validate:"omitempty,min=0,max=20" form:"auto_assign_count" json:"auto_assign_count"`
+	// AutoAssignMinScore the answerer is assigned only if the total score of the answers in the tag reaches it
+	AutoAssignMinScore int `validate:"omitempty,min=0" form:"auto_assign_min_score" json:"auto_assign_min_score"`
+}
+
+// FormatAllowEmailDomains trim and lower the email domains, remove the empty one
+func (r *SiteLoginReq) FormatAllowEmailDomains() {
+	domains := make([]string, 0, len(r.AllowEmailDomains))
+	for _, domain := range r.AllowEmailDomains {
+		domain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "@"))
+		if len(domain) > 0 {
+			domains = append(domains, domain)
+		}
+	}
+	r.AllowEmailDomains = domains
+}
+
+// IsAllowedEmail check the email whether belongs to the allowed email domains
+func (r *SiteLoginResp) IsAllowedEmail(email string) bool {
+	if len(r.AllowEmailDomains) == 0 {
+		return true
+	}
+	idx := strings.LastIndex(email, "@")
+	if idx < 0 {
+		return false
+	}
+	emailDomain := strings.ToLower(email[idx+1:])
+	for _, domain := range r.AllowEmailDomains {
+		if emailDomain == domain {
+			return true
+		}
+	}
+	return false
+}
+
 // GetSiteLegalInfoReq site site legal request
 type GetSiteLegalInfoReq struct {
 	InfoType string `validate:"required,oneof=tos privacy" form:"info_type"`
@@ -89,11 +222,38 @@ type SiteWriteResp SiteWriteReq
 // SiteLegalResp site write response
 type SiteLegalResp SiteLegalReq
 
+// SiteSeoResp site seo response
+type SiteSeoResp SiteSeoReq
+
+// SiteLoginResp site login response
+type SiteLoginResp SiteLoginReq
+
+// SiteReviewResp site review queue response
+type SiteReviewResp SiteReviewReq
+
+// SiteCloseVoteResp site close vote response
+type SiteCloseVoteResp SiteCloseVoteReq
+
+// SiteModerationResp site automated moderation response
+type SiteModerationResp SiteModerationReq
+
+// SiteAcceptResp site accepted answer response
+type SiteAcceptResp SiteAcceptReq
+
+// SiteCommentResp site comment response
+type SiteCommentResp SiteCommentReq
+
+// SiteTagModeratorResp site tag moderator response
+type SiteTagModeratorResp SiteTagModeratorReq
+
 // SiteInfoResp get site info response
 type SiteInfoResp struct {
 	General   *SiteGeneralResp   `json:"general"`
 	Interface *SiteInterfaceResp `json:"interface"`
 	Branding  *SiteBrandingResp  `json:"branding"`
+	Login     *SiteLoginResp     `json:"login"`
+	Accept    *SiteAcceptResp    `json:"accept"`
+	Comment   *SiteCommentResp   `json:"comment"`
 }
 
 // UpdateSMTPConfigReq get smtp config request
@@ -0,0 +1,38 @@
+package schema
+
+// AddTagModeratorReq assign the user as the moderator of the tag request
+type AddTagModeratorReq struct {
+	// tag id
+	TagID string `validate:"required" json:"tag_id"`
+	// the user who is assigned as the moderator
+	UserID string `validate:"required" json:"user_id"`
+	// the admin who assigns the moderator
+	OperatorID string `json:"-"`
+}
+
+// RemoveTagModeratorReq remove the moderator of the tag request
+type RemoveTagModeratorReq struct {
+	// tag id
+	TagID string `validate:"required" json:"tag_id"`
+	// the moderator who is removed
+	UserID string `validate:"required" json:"user_id"`
+	// the admin who removes the moderator
+	OperatorID string `json:"-"`
+}
+
+// TagModeratorInfo the moderator of the tag
+type TagModeratorInfo struct {
+	UserInfo *UserBasicInfo `json:"user_info"`
+	// manual or auto
+	Source    string `json:"source"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// GetModeratedTagsResp the tags which the user moderates response
+type GetModeratedTagsResp struct {
+	TagID       string `json:"tag_id"`
+	SlugName    string `json:"slug_name"`
+	DisplayName string `json:"display_name"`
+	// manual or auto
+	Source string `json:"source"`
+}
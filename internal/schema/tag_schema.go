@@ -68,6 +68,10 @@ type GetTagResp struct {
 	MainTagSlugName string `json:"main_tag_slug_name"`
 	Recommend       bool   `json:"recommend"`
 	Reserved        bool   `json:"reserved"`
+	// the question template of the tag, nil if the tag has no template
+	QuestionTemplate *TagTemplateResp `json:"question_template"`
+	// the moderators of the tag
+	Moderators []*TagModeratorInfo `json:"moderators"`
 }
 
 func (tr *GetTagResp) GetExcerpt() {
@@ -226,6 +230,8 @@ type UpdateTagSynonymReq struct {
 	SynonymTagList []*TagItem `validate:"required,dive" json:"synonym_tag_list"`
 	// user id
 	UserID string `json:"-"`
+	// the user is only the moderator of the tag, who can't take the existing tags from the others
+	OnlyTagModerator bool `json:"-"`
 }
 
 func (req *UpdateTagSynonymReq) Format() {
@@ -0,0 +1,68 @@
+package schema
+
+// AddTagSubscriptionReq watch the tag expression request
+type AddTagSubscriptionReq struct {
+	// the tag expression, such as "go AND NOT beginner"
+	Expression string `validate:"required,gt=0,lte=500" json:"expression"`
+	// instant or daily
+	Frequency string `validate:"required,oneof=instant daily" json:"frequency"`
+	UserID    string `json:"-"`
+}
+
+// UpdateTagSubscriptionReq update the watched tag expression request
+type UpdateTagSubscriptionReq struct {
+	// subscription id
+	ID string `validate:"required" json:"id"`
+	// the tag expression, such as "go AND NOT beginner"
+	Expression string `validate:"required,gt=0,lte=500" json:"expression"`
+	// instant or daily
+	Frequency string `validate:"required,oneof=instant daily" json:"frequency"`
+	UserID    string `json:"-"`
+}
+
+// RemoveTagSubscriptionReq stop watching the tag expression request
+type RemoveTagSubscriptionReq struct {
+	// subscription id
+	ID     string `validate:"required" json:"id"`
+	UserID string `json:"-"`
+}
+
+// TagSubscriptionResp the watched tag expression response
+type TagSubscriptionResp struct {
+	ID string `json:"id"`
+	// the normalized tag expression
+	Expression string `json:"expression"`
+	Frequency  string `json:"frequency"`
+	CreatedAt  int64  `json:"created_at"`
+}
+
+// UpdateTagAlertSettingReq update the time zone and the quiet hours of the tag alerts request
+type UpdateTagAlertSettingReq struct {
+	// the IANA time zone name, such as Asia/Shanghai, UTC if it is empty
+	TimeZone string `validate:"omitempty,lte=64" json:"time_zone"`
+	// no alert is sent from the start hour to the end hour, no quiet hours if they are equal
+	QuietHoursStart int    `validate:"omitempty,min=0,max=23" json:"quiet_hours_start"`
+	QuietHoursEnd   int    `validate:"omitempty,min=0,max=23" json:"quiet_hours_end"`
+	UserID          string `json:"-"`
+}
+
+// GetTagAlertSettingResp the time zone and the quiet hours of the tag alerts response
+type GetTagAlertSettingResp struct {
+	TimeZone        string `json:"time_zone"`
+	QuietHoursStart int    `json:"quiet_hours_start"`
+	QuietHoursEnd   int    `json:"quiet_hours_end"`
+}
+
+// UpdateIgnoredTagsReq replace the ignored tags of the user request
+type UpdateIgnoredTagsReq struct {
+	// the slug names of the ignored tags, the not existing ones are skipped
+	TagNames []string `validate:"omitempty,lte=50,dive,gt=0,lte=35" json:"tag_names"`
+	UserID   string   `json:"-"`
+}
+
+// GetIgnoredTagResp the ignored tag response
+type GetIgnoredTagResp struct {
+	TagID       string `json:"tag_id"`
+	SlugName    string `json:"slug_name"`
+	DisplayName string `json:"display_name"`
+}
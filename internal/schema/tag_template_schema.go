@@ -0,0 +1,38 @@
+package schema
+
+// SaveTagTemplateReq save the question template of the tag request
+type SaveTagTemplateReq struct {
+	// tag id
+	TagID string `validate:"required" json:"tag_id"`
+	// the body skeleton which the question is pre-filled with
+	Content string `validate:"required,gt=0,lte=65535" json:"content"`
+	// the headings of the sections which can not be left empty in the question
+	RequiredSections []string `validate:"omitempty,lte=20,dive,gt=0,lte=100" json:"required_sections"`
+	// user id
+	UserID  string `json:"-"`
+	IsAdmin bool   `json:"-"`
+}
+
+// RemoveTagTemplateReq remove the question template of the tag request
+type RemoveTagTemplateReq struct {
+	// tag id
+	TagID string `validate:"required" json:"tag_id"`
+	// user id
+	UserID  string `json:"-"`
+	IsAdmin bool   `json:"-"`
+}
+
+// GetTagTemplateReq get the question templates of the selected tags request
+type GetTagTemplateReq struct {
+	// the slug names of the selected tags
+	TagNames []string `validate:"required,gt=0,lte=5" form:"tag_names"`
+}
+
+// TagTemplateResp the question template of the tag response
+type TagTemplateResp struct {
+	TagID            string   `json:"tag_id"`
+	SlugName         string   `json:"slug_name"`
+	Content          string   `json:"content"`
+	RequiredSections []string `json:"required_sections"`
+	UpdatedAt        int64    `json:"updated_at"`
+}
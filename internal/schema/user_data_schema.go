@@ -0,0 +1,108 @@
+package schema
+
+const (
+	UserDataExportStatusPending   = "pending"
+	UserDataExportStatusCompleted = "completed"
+	UserDataExportStatusFailed    = "failed"
+)
+
+const (
+	// UserDeletionModeAnonymize keep the content and reassign it to the ghost user
+	UserDeletionModeAnonymize = "anonymize"
+	// UserDeletionModeRemove remove all the content of the user
+	UserDeletionModeRemove = "remove"
+)
+
+// GetUserDataExportResp get user data export response
+type GetUserDataExportResp struct {
+	// pending completed failed, empty if no export job
+	Status    string `json:"status"`
+	CreatedAt int64  `json:"created_at"`
+	ExpiredAt int64  `json:"expired_at"`
+}
+
+// UserDataExportQuestion the question in the export file
+type UserDataExportQuestion struct {
+	ID           string `json:"id"`
+	Title        string `json:"title"`
+	Content      string `json:"content"`
+	HTML         string `json:"html"`
+	Status       int    `json:"status"`
+	ViewCount    int    `json:"view_count"`
+	VoteCount    int    `json:"vote_count"`
+	AnswerCount  int    `json:"answer_count"`
+	CreatedAt    int64  `json:"created_at"`
+	UpdatedAt    int64  `json:"updated_at"`
+	AcceptedID   string `json:"accepted_answer_id"`
+	LastAnswerID string `json:"last_answer_id"`
+}
+
+// UserDataExportAnswer the answer in the export file
+type UserDataExportAnswer struct {
+	ID         string `json:"id"`
+	QuestionID string `json:"question_id"`
+	Content    string `json:"content"`
+	HTML       string `json:"html"`
+	Status     int    `json:"status"`
+	Adopted    int    `json:"adopted"`
+	VoteCount  int    `json:"vote_count"`
+	CreatedAt  int64  `json:"created_at"`
+	UpdatedAt  int64  `json:"updated_at"`
+}
+
+// UserDataExportComment the comment in the export file
+type UserDataExportComment struct {
+	ID             string `json:"id"`
+	ObjectID       string `json:"object_id"`
+	QuestionID     string `json:"question_id"`
+	ReplyUserID    string `json:"reply_user_id"`
+	ReplyCommentID string `json:"reply_comment_id"`
+	Content        string `json:"content"`
+	HTML           string `json:"html"`
+	Status         int    `json:"status"`
+	VoteCount      int    `json:"vote_count"`
+	CreatedAt      int64  `json:"created_at"`
+}
+
+// UserDataExportVote the vote in the export file
+type UserDataExportVote struct {
+	ObjectID  string `json:"object_id"`
+	VoteType  string `json:"vote_type"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// UserDataExportCollection the collection in the export file
+type UserDataExportCollection struct {
+	ObjectID  string `json:"object_id"`
+	GroupID   string `json:"group_id"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// UserDataExportNotification the notification in the export file
+type UserDataExportNotification struct {
+	ID        string `json:"id"`
+	ObjectID  string `json:"object_id"`
+	Type      int    `json:"type"`
+	Content   string `json:"content"`
+	IsRead    int    `json:"is_read"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// AddUserDeletionReq request to delete the account of the user
+type AddUserDeletionReq struct {
+	// the password of the user, confirm the deletion
+	Pass string `validate:"required,gte=8,lte=32" json:"pass"`
+	// anonymize: reassign the content to the ghost user, remove: remove all the content
+	Mode   string `validate:"required,oneof=anonymize remove" json:"mode" enums:"anonymize,remove"`
+	UserID string `json:"-"`
+}
+
+// GetUserDeletionResp get user deletion response
+type GetUserDeletionResp struct {
+	// if false, the user has not requested to delete the account
+	Pending   bool   `json:"pending"`
+	Mode      string `json:"mode"`
+	CreatedAt int64  `json:"created_at"`
+	// the account will be deleted at this time, user can cancel the deletion before
+	ExecuteAt int64 `json:"execute_at"`
+}
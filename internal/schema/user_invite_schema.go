@@ -0,0 +1,59 @@
+package schema
+
+// AddUserInviteReq add user invite request
+type AddUserInviteReq struct {
+	// if email is set, only this email can register by the invite link
+	Email string `validate:"omitempty,email,gt=0,lte=500" json:"email"`
+	// the invite link will expire after those days
+	ExpireDays int    `validate:"omitempty,min=1,max=365" json:"expire_days"`
+	UserID     string `json:"-"`
+}
+
+// AddUserInviteResp add user invite response
+type AddUserInviteResp struct {
+	Code      string `json:"code"`
+	InviteURL string `json:"invite_url"`
+	ExpiredAt int64  `json:"expired_at"`
+}
+
+// RemoveUserInviteReq remove user invite request
+type RemoveUserInviteReq struct {
+	Code string `validate:"required,gt=0,lte=64" json:"code"`
+}
+
+// GetUserInvitePageReq get user invite page request
+type GetUserInvitePageReq struct {
+	// page
+	Page int `validate:"omitempty,min=1" form:"page"`
+	// page size
+	PageSize int `validate:"omitempty,min=1" form:"page_size"`
+}
+
+// GetUserInvitePageResp get user invite page response
+type GetUserInvitePageResp struct {
+	Code      string `json:"code"`
+	InviteURL string `json:"invite_url"`
+	Email     string `json:"email"`
+	CreatedAt int64  `json:"created_at"`
+	ExpiredAt int64  `json:"expired_at"`
+	// status: available used expired
+	Status     string `json:"status"`
+	UsedUserID string `json:"used_user_id"`
+}
+
+const (
+	UserInviteAvailable = "available"
+	UserInviteUsed      = "used"
+	UserInviteExpired   = "expired"
+)
+
+// CheckUserInviteReq check user invite request
+type CheckUserInviteReq struct {
+	Code string `validate:"required,gt=0,lte=64" form:"code"`
+}
+
+// CheckUserInviteResp check user invite response
+type CheckUserInviteResp struct {
+	Email     string `json:"email"`
+	ExpiredAt int64  `json:"expired_at"`
+}
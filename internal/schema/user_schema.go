@@ -3,6 +3,7 @@ package schema
 import (
 	"encoding/json"
 	"regexp"
+	"strings"
 
 	"answer/internal/base/reason"
 	"answer/internal/base/validator"
@@ -18,7 +19,9 @@ type UserVerifyEmailReq struct {
 	// code
 	Code string `validate:"required,gt=0,lte=500" form:"code"`
 	// content
-	Content string `json:"-"`
+	Content   string `json:"-"`
+	IP        string `json:"-"`
+	UserAgent string `json:"-"`
 }
 
 // GetUserResp get user response
@@ -65,12 +68,20 @@ type GetUserResp struct {
 	IPInfo string `json:"ip_info"`
 	// language
 	Language string `json:"language"`
+	// the language codes of the content which the user prefers to browse
+	ContentLanguages []string `json:"content_languages"`
 	// access token
 	AccessToken string `json:"access_token"`
 	// is admin
 	IsAdmin bool `json:"is_admin"`
 	// user status
 	Status string `json:"status"`
+	// two factor required, the login is pending until the code is verified with the two factor token
+	TwoFactorRequired bool `json:"two_factor_required"`
+	// two factor token, the short-lived token of the pending login
+	TwoFactorToken string `json:"two_factor_token"`
+	// two factor setup required, the admin must set up two factor authentication before using the admin pages
+	TwoFactorSetupRequired bool `json:"two_factor_setup_required"`
 }
 
 func (r *GetUserResp) GetFromUserEntity(userInfo *entity.User) {
@@ -78,15 +89,29 @@ func (r *GetUserResp) GetFromUserEntity(userInfo *entity.User) {
 	r.Avatar = FormatAvatarInfo(userInfo.Avatar)
 	r.CreatedAt = userInfo.CreatedAt.Unix()
 	r.LastLoginDate = userInfo.LastLoginDate.Unix()
+	r.ContentLanguages = SplitContentLanguages(userInfo.ContentLanguages)
 	statusShow, ok := UserStatusShow[userInfo.Status]
 	if ok {
 		r.Status = statusShow
 	}
 }
 
+// SplitContentLanguages split the content languages of the user which are separated by comma
+func SplitContentLanguages(contentLanguages string) (languages []string) {
+	languages = make([]string, 0)
+	for _, language := range strings.Split(contentLanguages, ",") {
+		if language = strings.TrimSpace(language); len(language) > 0 {
+			languages = append(languages, language)
+		}
+	}
+	return languages
+}
+
 type GetUserToSetShowResp struct {
 	*GetUserResp
 	Avatar *AvatarInfo `json:"avatar"`
+	// Suspension the active suspension of the user, it is shown to the suspended user
+	Suspension *UserSuspensionResp `json:"suspension,omitempty"`
 }
 
 func (r *GetUserToSetShowResp) GetFromUserEntity(userInfo *entity.User) {
@@ -224,6 +249,8 @@ type UserEmailLogin struct {
 	Pass        string `validate:"required,gte=8,lte=32" json:"pass"`         // password
 	CaptchaID   string `json:"captcha_id"`                                    // captcha_id
 	CaptchaCode string `json:"captcha_code"`                                  // captcha_code
+	IP          string `json:"-"`
+	UserAgent   string `json:"-"`
 }
 
 // UserRegisterReq user register request
@@ -234,7 +261,10 @@ type UserRegisterReq struct {
 	Email string `validate:"required,email,gt=0,lte=500" json:"e_mail" `
 	// password
 	Pass string `validate:"required,gte=8,lte=32" json:"pass"`
-	IP   string `json:"-" `
+	// invite code, required when the site is invite only
+	InviteCode string `validate:"omitempty,lte=64" json:"invite_code"`
+	IP         string `json:"-" `
+	UserAgent  string `json:"-"`
 }
 
 func (u *UserRegisterReq) Check() (errFields []*validator.FormErrorField, err error) {
@@ -256,6 +286,8 @@ type UserModifyPassWordRequest struct {
 	UserID  string `json:"-" `        // user_id
 	OldPass string `json:"old_pass" ` // old password
 	Pass    string `json:"pass" `     // password
+	// the current session is kept, the other sessions are signed out after password changed
+	SessionID string `json:"-"`
 }
 
 func (u *UserModifyPassWordRequest) Check() (errFields []*validator.FormErrorField, err error) {
@@ -317,6 +349,9 @@ func (u *UpdateInfoRequest) Check() (errFields []*validator.FormErrorField, err
 type UpdateUserInterfaceRequest struct {
 	// language
 	Language string `validate:"required,gt=1,lte=100" json:"language"`
+	// the language codes of the content which the user prefers to browse, such as en or zh,
+	// empty means all languages, keep unchanged if it is not present
+	ContentLanguages []string `validate:"omitempty,lte=10,dive,gt=1,lte=16" json:"content_languages"`
 	// user id
 	UserId string `json:"-" `
 }
@@ -0,0 +1,24 @@
+package schema
+
+// GetUserSessionResp get user session response
+type GetUserSessionResp struct {
+	ID         string `json:"id"`
+	Device     string `json:"device"`
+	IP         string `json:"ip"`
+	UserAgent  string `json:"user_agent"`
+	CreatedAt  int64  `json:"created_at"`
+	LastSeenAt int64  `json:"last_seen_at"`
+	// current is the session of the request
+	Current bool `json:"current"`
+}
+
+// RevokeUserSessionReq revoke user session request
+type RevokeUserSessionReq struct {
+	SessionID string `validate:"required" json:"session_id"`
+	UserID    string `json:"-"`
+}
+
+// SignOutUserReq admin sign out the user everywhere
+type SignOutUserReq struct {
+	UserID string `validate:"required" json:"user_id"`
+}
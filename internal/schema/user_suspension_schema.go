@@ -0,0 +1,75 @@
+package schema
+
+import "answer/internal/entity"
+
+// UserSuspensionReasonOther the default reason of the suspension
+const UserSuspensionReasonOther = "other"
+
+// UserSuspensionStatus the display name of the suspension status
+var UserSuspensionStatus = map[int]string{
+	entity.UserSuspensionStatusActive:  "active",
+	entity.UserSuspensionStatusLifted:  "lifted",
+	entity.UserSuspensionStatusExpired: "expired",
+}
+
+// UserSuspensionResp user suspension response
+type UserSuspensionResp struct {
+	ID string `json:"id"`
+	// Reason spam, abuse, low_quality or other
+	Reason      string `json:"reason"`
+	Message     string `json:"message"`
+	Days        int    `json:"days"`
+	SuspendedAt int64  `json:"suspended_at"`
+	// ExpiredAt 0 means the suspension is permanent
+	ExpiredAt int64 `json:"expired_at"`
+	// Status active, lifted or expired
+	Status string `json:"status"`
+}
+
+// GetFromEntity get the response from the suspension entity
+func (r *UserSuspensionResp) GetFromEntity(suspension *entity.UserSuspension) {
+	r.ID = suspension.ID
+	r.Reason = suspension.Reason
+	r.Message = suspension.Message
+	r.Days = suspension.Days
+	r.SuspendedAt = suspension.CreatedAt.Unix()
+	if suspension.Days > 0 {
+		r.ExpiredAt = suspension.ExpiredAt.Unix()
+	}
+	r.Status = UserSuspensionStatus[suspension.Status]
+}
+
+// GetUserSuspensionListReq get user suspension list request
+type GetUserSuspensionListReq struct {
+	UserID string `validate:"required" form:"user_id"`
+}
+
+// GetUserBanPageReq get user ban page request
+type GetUserBanPageReq struct {
+	BanType  string `validate:"omitempty,oneof=ip email_domain" form:"ban_type"`
+	Page     int    `validate:"omitempty,min=1" form:"page"`
+	PageSize int    `validate:"omitempty,min=1" form:"page_size"`
+}
+
+// GetUserBanResp get user ban response
+type GetUserBanResp struct {
+	ID        string `json:"id"`
+	CreatedAt int64  `json:"created_at"`
+	// BanType ip or email_domain
+	BanType string `json:"ban_type"`
+	Value   string `json:"value"`
+	// UserID the user whose suspension caused the ban, 0 if the ban is added by admin directly
+	UserID string `json:"user_id"`
+}
+
+// AddUserBanReq add user ban request
+type AddUserBanReq struct {
+	BanType    string `validate:"required,oneof=ip email_domain" json:"ban_type"`
+	Value      string `validate:"required,gt=0,lte=255" json:"value"`
+	OperatorID string `json:"-"`
+}
+
+// RemoveUserBanReq remove user ban request
+type RemoveUserBanReq struct {
+	ID string `validate:"required" json:"id"`
+}
@@ -0,0 +1,63 @@
+package schema
+
+// GetUserTwoFactorResp get user two factor response
+type GetUserTwoFactorResp struct {
+	Enabled bool `json:"enabled"`
+	// required by site setting, the user can not disable it
+	Required bool `json:"required"`
+	// the number of recovery codes which are not used
+	RecoveryCodesLeft int `json:"recovery_codes_left"`
+}
+
+// SetupUserTwoFactorReq setup user two factor request
+type SetupUserTwoFactorReq struct {
+	UserID string `json:"-"`
+}
+
+// SetupUserTwoFactorResp setup user two factor response
+type SetupUserTwoFactorResp struct {
+	// the base32 encoded secret, for manual entry
+	Secret string `json:"secret"`
+	// the otpauth uri, rendered as qr-code for authenticator apps
+	KeyURI string `json:"key_uri"`
+}
+
+// EnableUserTwoFactorReq confirm the setup with a code generated by the authenticator app
+type EnableUserTwoFactorReq struct {
+	Code   string `validate:"required,len=6" json:"code"`
+	UserID string `json:"-"`
+}
+
+// DisableUserTwoFactorReq disable user two factor request
+type DisableUserTwoFactorReq struct {
+	Pass         string `validate:"required,gte=8,lte=32" json:"pass"`
+	Code         string `validate:"omitempty,len=6" json:"code"`
+	RecoveryCode string `validate:"omitempty,gt=0,lte=32" json:"recovery_code"`
+	UserID       string `json:"-"`
+}
+
+// RegenerateRecoveryCodesReq regenerate recovery codes request, the old codes will be invalid
+type RegenerateRecoveryCodesReq struct {
+	Code   string `validate:"required,len=6" json:"code"`
+	UserID string `json:"-"`
+}
+
+// UserTwoFactorRecoveryCodesResp the recovery codes, only shown once
+type UserTwoFactorRecoveryCodesResp struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// UserTwoFactorLoginReq the second step of login
+type UserTwoFactorLoginReq struct {
+	// the two factor token returned by the first step
+	Token        string `validate:"required,gt=0,lte=64" json:"token"`
+	Code         string `validate:"omitempty,len=6" json:"code"`
+	RecoveryCode string `validate:"omitempty,gt=0,lte=32" json:"recovery_code"`
+	IP           string `json:"-"`
+	UserAgent    string `json:"-"`
+}
+
+// ResetUserTwoFactorReq admin reset user two factor request
+type ResetUserTwoFactorReq struct {
+	UserID string `validate:"required" json:"user_id"`
+}
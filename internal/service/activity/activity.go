@@ -3,6 +3,7 @@ package activity
 import (
 	"context"
 	"encoding/json"
+	"sort"
 	"strings"
 
 	"answer/internal/base/constant"
@@ -10,6 +11,7 @@ import (
 	"answer/internal/repo/config"
 	"answer/internal/schema"
 	"answer/internal/service/activity_common"
+	answercommon "answer/internal/service/answer_common"
 	"answer/internal/service/comment_common"
 	"answer/internal/service/meta"
 	"answer/internal/service/object_info"
@@ -37,6 +39,7 @@ type ActivityService struct {
 	commentCommonService  *comment_common.CommentCommonService
 	revisionService       *revision_common.RevisionService
 	metaService           *meta.MetaService
+	answerAcceptLogRepo   answercommon.AnswerAcceptLogRepo
 }
 
 // NewActivityService new activity service
@@ -49,6 +52,7 @@ func NewActivityService(
 	commentCommonService *comment_common.CommentCommonService,
 	revisionService *revision_common.RevisionService,
 	metaService *meta.MetaService,
+	answerAcceptLogRepo answercommon.AnswerAcceptLogRepo,
 ) *ActivityService {
 	return &ActivityService{
 		objectInfoService:     objectInfoService,
@@ -59,6 +63,7 @@ func NewActivityService(
 		commentCommonService:  commentCommonService,
 		revisionService:       revisionService,
 		metaService:           metaService,
+		answerAcceptLogRepo:   answerAcceptLogRepo,
 	}
 }
 
@@ -79,6 +84,10 @@ func (as *ActivityService) GetObjectTimeline(ctx context.Context, req *schema.Ge
 	if err != nil {
 		return nil, err
 	}
+	acceptTimeline, acceptHistoryAnswers, err := as.getAcceptTimeline(ctx, resp.ObjectInfo.ObjectType, req.ObjectID)
+	if err != nil {
+		return nil, err
+	}
 	for _, act := range activityList {
 		item := &schema.ActObjectTimeline{
 			ActivityID: act.ID,
@@ -101,6 +110,10 @@ func (as *ActivityService) GetObjectTimeline(ctx context.Context, req *schema.Ge
 		} else {
 			item.ActivityType = formattedActivityType
 		}
+		// the accept activity only keeps the last status, the accept history shows all the changes instead
+		if item.ActivityType == constant.ActAccept && acceptHistoryAnswers[item.ObjectID] {
+			continue
+		}
 
 		// if activity is down vote, only admin can see who does it.
 		if item.ActivityType == constant.ActDownVote && !req.IsAdmin {
@@ -113,10 +126,55 @@ func (as *ActivityService) GetObjectTimeline(ctx context.Context, req *schema.Ge
 		item.Comment = as.getTimelineActivityComment(ctx, item.ObjectID, item.ObjectType, item.ActivityType, item.RevisionID)
 		resp.Timeline = append(resp.Timeline, item)
 	}
+	if len(acceptTimeline) > 0 {
+		resp.Timeline = append(resp.Timeline, acceptTimeline...)
+		sort.SliceStable(resp.Timeline, func(i, j int) bool {
+			return resp.Timeline[i].CreatedAt > resp.Timeline[j].CreatedAt
+		})
+	}
 	as.formatTimelineUserInfo(ctx, resp.Timeline)
 	return
 }
 
+// getAcceptTimeline get the accept history of the answers of the question or the answer,
+// the answers which have the history are returned too.
+func (as *ActivityService) getAcceptTimeline(ctx context.Context, objectType, objectID string) (
+	timeline []*schema.ActObjectTimeline, answerIDs map[string]bool, err error) {
+	var acceptLogs []*entity.AnswerAcceptLog
+	switch objectType {
+	case constant.QuestionObjectType:
+		acceptLogs, err = as.answerAcceptLogRepo.GetAcceptLogsByQuestionID(ctx, objectID)
+	case constant.AnswerObjectType:
+		acceptLogs, err = as.answerAcceptLogRepo.GetAcceptLogsByAnswerID(ctx, objectID)
+	default:
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	timeline = make([]*schema.ActObjectTimeline, 0, len(acceptLogs))
+	answerIDs = make(map[string]bool)
+	for _, acceptLog := range acceptLogs {
+		item := &schema.ActObjectTimeline{
+			ActivityID:   acceptLog.ID,
+			RevisionID:   "0",
+			CreatedAt:    acceptLog.CreatedAt.Unix(),
+			ActivityType: constant.ActAccept,
+			ObjectID:     acceptLog.AnswerID,
+			ObjectType:   constant.AnswerObjectType,
+			OnBehalf:     acceptLog.OnBehalf,
+			UserID:       acceptLog.UserID,
+		}
+		if acceptLog.Action == entity.AnswerAcceptActionUnaccept {
+			item.ActivityType = constant.ActUnaccept
+		}
+		timeline = append(timeline, item)
+		answerIDs[acceptLog.AnswerID] = true
+	}
+	return timeline, answerIDs, nil
+}
+
 func (as *ActivityService) getTimelineMainObjInfo(ctx context.Context, objectID string) (
 	resp *schema.ActObjectInfo, err error) {
 	resp = &schema.ActObjectInfo{}
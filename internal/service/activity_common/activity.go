@@ -3,9 +3,11 @@ package activity_common
 import (
 	"context"
 
+	"answer/internal/base/constant"
 	"answer/internal/entity"
 	"answer/internal/service/activity_queue"
 	"answer/pkg/converter"
+	"answer/pkg/obj"
 
 	"github.com/segmentfault/pacman/log"
 	"xorm.io/xorm"
@@ -22,15 +24,18 @@ type ActivityRepo interface {
 }
 
 type ActivityCommon struct {
-	activityRepo ActivityRepo
+	activityRepo      ActivityRepo
+	questionScoreRepo QuestionScoreRepo
 }
 
 // NewActivityCommon new activity common
 func NewActivityCommon(
 	activityRepo ActivityRepo,
+	questionScoreRepo QuestionScoreRepo,
 ) *ActivityCommon {
 	activity := &ActivityCommon{
-		activityRepo: activityRepo,
+		activityRepo:      activityRepo,
+		questionScoreRepo: questionScoreRepo,
 	}
 	activity.HandleActivity()
 	return activity
@@ -67,6 +72,15 @@ func (ac *ActivityCommon) HandleActivity() {
 			if err := ac.activityRepo.AddActivity(context.TODO(), act); err != nil {
 				log.Error(err)
 			}
+
+			// the activity of question or its answers makes the question hot
+			objectType, _ := obj.GetObjectTypeStrByObjectID(msg.OriginalObjectID)
+			if objectType == constant.QuestionObjectType || objectType == constant.AnswerObjectType {
+				err = ac.questionScoreRepo.RefreshHotScore(context.TODO(), msg.OriginalObjectID)
+				if err != nil {
+					log.Error(err)
+				}
+			}
 		}
 	}()
 }
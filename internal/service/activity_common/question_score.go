@@ -0,0 +1,12 @@
+package activity_common
+
+import (
+	"context"
+)
+
+// QuestionScoreRepo question score repository
+type QuestionScoreRepo interface {
+	// RefreshHotScore recalculate the hot score of the question, or the question of the answer,
+	// with the latest interactions. The time of the last activity is kept, it is set by the edit and answer paths.
+	RefreshHotScore(ctx context.Context, objectID string) (err error)
+}
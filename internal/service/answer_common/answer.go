@@ -15,7 +15,8 @@ type AnswerRepo interface {
 	GetAnswer(ctx context.Context, id string) (answer *entity.Answer, exist bool, err error)
 	GetAnswerList(ctx context.Context, answer *entity.Answer) (answerList []*entity.Answer, err error)
 	GetAnswerPage(ctx context.Context, page, pageSize int, answer *entity.Answer) (answerList []*entity.Answer, total int64, err error)
-	UpdateAdopted(ctx context.Context, id string, questionID string) error
+	UpdateAnswerAdopted(ctx context.Context, id string, adopted int) error
+	GetAcceptedAnswers(ctx context.Context, questionID string) (answerList []*entity.Answer, err error)
 	GetByID(ctx context.Context, id string) (*entity.Answer, bool, error)
 	GetByUserIDQuestionID(ctx context.Context, userID string, questionID string) (*entity.Answer, bool, error)
 	SearchList(ctx context.Context, search *entity.AnswerSearch) ([]*entity.Answer, int64, error)
@@ -24,6 +25,13 @@ type AnswerRepo interface {
 	GetAnswerCount(ctx context.Context) (count int64, err error)
 }
 
+// AnswerAcceptLogRepo the history of accepting the answers
+type AnswerAcceptLogRepo interface {
+	AddAcceptLog(ctx context.Context, acceptLog *entity.AnswerAcceptLog) (err error)
+	GetAcceptLogsByQuestionID(ctx context.Context, questionID string) (acceptLogs []*entity.AnswerAcceptLog, err error)
+	GetAcceptLogsByAnswerID(ctx context.Context, answerID string) (acceptLogs []*entity.AnswerAcceptLog, err error)
+}
+
 // AnswerCommon user service
 type AnswerCommon struct {
 	answerRepo AnswerRepo
@@ -65,6 +73,8 @@ func (as *AnswerCommon) ShowFormat(ctx context.Context, data *entity.Answer) *sc
 	info.Content = data.OriginalText
 	info.HTML = data.ParsedText
 	info.Adopted = data.Adopted
+	info.Language = data.Language
+	info.CommunityWiki = data.CommunityWiki
 	info.VoteCount = data.VoteCount
 	info.CreateTime = data.CreatedAt.Unix()
 	info.UpdateTime = data.UpdatedAt.Unix()
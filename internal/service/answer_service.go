@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"answer/internal/base/constant"
+	"answer/internal/base/data"
 	"answer/internal/base/reason"
 	"answer/internal/entity"
 	"answer/internal/schema"
@@ -15,11 +16,19 @@ import (
 	"answer/internal/service/activity_queue"
 	answercommon "answer/internal/service/answer_common"
 	collectioncommon "answer/internal/service/collection_common"
+	"answer/internal/service/comment"
+	"answer/internal/service/draft"
+	"answer/internal/service/mention"
+	moderationservice "answer/internal/service/moderation"
 	"answer/internal/service/notice_queue"
 	"answer/internal/service/permission"
 	questioncommon "answer/internal/service/question_common"
+	"answer/internal/service/review"
 	"answer/internal/service/revision_common"
+	"answer/internal/service/siteinfo_common"
 	usercommon "answer/internal/service/user_common"
+	"answer/pkg/langdetect"
+	"answer/pkg/moderation"
 
 	"github.com/segmentfault/pacman/errors"
 	"github.com/segmentfault/pacman/log"
@@ -37,6 +46,14 @@ type AnswerService struct {
 	revisionService       *revision_common.RevisionService
 	AnswerCommon          *answercommon.AnswerCommon
 	voteRepo              activity_common.VoteRepo
+	reviewService         *review.ReviewService
+	moderationService     *moderationservice.ModerationService
+	draftService          *draft.DraftService
+	mentionService        *mention.MentionService
+	answerAcceptLogRepo   answercommon.AnswerAcceptLogRepo
+	siteInfoService       *siteinfo_common.SiteInfoCommonService
+	commentRepo           comment.CommentRepo
+	data                  *data.Data
 }
 
 func NewAnswerService(
@@ -50,6 +67,14 @@ func NewAnswerService(
 	answerAcceptActivityRepo *activity.AnswerActivityService,
 	answerCommon *answercommon.AnswerCommon,
 	voteRepo activity_common.VoteRepo,
+	reviewService *review.ReviewService,
+	moderationService *moderationservice.ModerationService,
+	draftService *draft.DraftService,
+	mentionService *mention.MentionService,
+	answerAcceptLogRepo answercommon.AnswerAcceptLogRepo,
+	siteInfoService *siteinfo_common.SiteInfoCommonService,
+	commentRepo comment.CommentRepo,
+	data *data.Data,
 ) *AnswerService {
 	return &AnswerService{
 		answerRepo:            answerRepo,
@@ -62,6 +87,14 @@ func NewAnswerService(
 		answerActivityService: answerAcceptActivityRepo,
 		AnswerCommon:          answerCommon,
 		voteRepo:              voteRepo,
+		reviewService:         reviewService,
+		moderationService:     moderationService,
+		draftService:          draftService,
+		mentionService:        mentionService,
+		answerAcceptLogRepo:   answerAcceptLogRepo,
+		siteInfoService:       siteInfoService,
+		commentRepo:           commentRepo,
+		data:                  data,
 	}
 }
 
@@ -135,37 +168,151 @@ func (as *AnswerService) Insert(ctx context.Context, req *schema.AnswerAddReq) (
 	if !exist {
 		return "", errors.BadRequest(reason.QuestionNotFound)
 	}
+	newAnswer, err := as.prepareAnswer(ctx, req, questionInfo)
+	if err != nil {
+		return "", err
+	}
+	//insertData.UpdatedAt = now
+	if err = as.answerRepo.AddAnswer(ctx, newAnswer.answer); err != nil {
+		return "", err
+	}
+	if err = as.afterAddAnswer(ctx, req, questionInfo, newAnswer); err != nil {
+		return newAnswer.answer.ID, err
+	}
+	as.draftService.RemoveSubmittedDraft(ctx, req.UserID, entity.DraftTypeAnswer, req.QuestionID)
+	return newAnswer.answer.ID, nil
+}
+
+// ConvertCommentToAnswer the moderator converts the comment which answers the question to an answer by its author,
+// the comment is removed after the answer is added
+func (as *AnswerService) ConvertCommentToAnswer(ctx context.Context, req *schema.ConvertCommentToAnswerReq) (
+	resp *schema.ConvertCommentToAnswerResp, err error) {
+	comment, exist, err := as.commentRepo.GetComment(ctx, req.CommentID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist || comment.Status != entity.CommentStatusAvailable {
+		return nil, errors.BadRequest(reason.CommentNotFound)
+	}
+	if len(comment.QuestionID) == 0 || comment.QuestionID == "0" {
+		return nil, errors.BadRequest(reason.CommentCannotConvert)
+	}
+	questionInfo, exist, err := as.questionRepo.GetQuestion(ctx, comment.QuestionID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.BadRequest(reason.QuestionNotFound)
+	}
+
+	answerReq := &schema.AnswerAddReq{
+		QuestionID: comment.QuestionID,
+		Content:    comment.OriginalText,
+		HTML:       comment.ParsedText,
+		UserID:     comment.UserID,
+	}
+	newAnswer, err := as.prepareAnswer(ctx, answerReq, questionInfo)
+	if err != nil {
+		return nil, err
+	}
+	// the answer is added and the comment is removed together, the notifications are sent after both are done
+	err = as.data.Transaction(ctx, func(ctx context.Context) error {
+		if err := as.answerRepo.AddAnswer(ctx, newAnswer.answer); err != nil {
+			return err
+		}
+		if err := as.commentRepo.RemoveComment(ctx, comment.ID); err != nil {
+			return err
+		}
+		return as.recordAnswer(ctx, newAnswer)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !newAnswer.hold {
+		as.reviewService.NotifyAnswer(newAnswer.answer, questionInfo, newAnswer.mentionUserIDs)
+	}
+	activity_queue.AddActivity(&schema.ActivityMsg{
+		UserID:           req.UserID,
+		ObjectID:         newAnswer.answer.ID,
+		OriginalObjectID: newAnswer.answer.ID,
+		ActivityTypeKey:  constant.ActAnswerConverted,
+	})
+	return &schema.ConvertCommentToAnswerResp{AnswerID: newAnswer.answer.ID, QuestionID: comment.QuestionID}, nil
+}
+
+// newAnswer the answer to be added and the result of the checks before adding
+type newAnswer struct {
+	answer            *entity.Answer
+	moderationContent *moderation.Content
+	verdict           *schema.ModerationVerdict
+	reviewQueue       string
+	hold              bool
+	mentionUserIDs    []string
+}
+
+// prepareAnswer build the answer and check whether it should be held for review
+func (as *AnswerService) prepareAnswer(ctx context.Context, req *schema.AnswerAddReq, questionInfo *entity.Question) (
+	na *newAnswer, err error) {
+	na = &newAnswer{}
+	req.HTML, na.mentionUserIDs, err = as.mentionService.Resolve(ctx, req.Content, req.HTML, "")
+	if err != nil {
+		return nil, err
+	}
 	insertData := new(entity.Answer)
 	insertData.UserID = req.UserID
 	insertData.OriginalText = req.Content
 	insertData.ParsedText = req.HTML
+	insertData.Language = langdetect.Resolve(req.Language, req.Content)
 	insertData.Adopted = schema.AnswerAdoptedFailed
 	insertData.QuestionID = req.QuestionID
 	insertData.RevisionID = "0"
 	insertData.LastEditUserID = "0"
 	insertData.Status = entity.AnswerStatusAvailable
-	//insertData.UpdatedAt = now
-	if err = as.answerRepo.AddAnswer(ctx, insertData); err != nil {
-		return "", err
-	}
-	err = as.questionCommon.UpdateAnswerCount(ctx, req.QuestionID, 1)
+	insertData.CommunityWiki = req.CommunityWiki
+	na.answer = insertData
+	na.moderationContent = &moderation.Content{
+		ObjectType: constant.AnswerObjectType,
+		UserID:     req.UserID,
+		HTML:       req.HTML,
+		IP:         req.IP,
+		UserAgent:  req.UserAgent,
+	}
+	na.verdict, err = as.moderationService.Check(ctx, na.moderationContent)
 	if err != nil {
-		log.Error("IncreaseAnswerCount error", err.Error())
+		return nil, err
 	}
-	err = as.questionCommon.UpdateLastAnswer(ctx, req.QuestionID, insertData.ID)
-	if err != nil {
-		log.Error("UpdateLastAnswer error", err.Error())
+	na.reviewQueue, na.hold = as.reviewService.CheckAnswer(ctx, req.UserID, req.HTML, questionInfo)
+	if na.verdict.Action == entity.ModerationActionHold {
+		na.reviewQueue, na.hold = entity.ReviewQueueModeration, true
 	}
-	err = as.questionCommon.UpdataPostTime(ctx, req.QuestionID)
-	if err != nil {
-		return insertData.ID, err
+	if na.hold {
+		insertData.Status = entity.AnswerStatusPending
 	}
+	return na, nil
+}
 
-	err = as.userCommon.UpdateAnswerCount(ctx, req.UserID, 1)
-	if err != nil {
-		log.Error("user IncreaseAnswerCount error", err.Error())
+// afterAddAnswer update the counts, revision, activities and notifications of the added answer
+func (as *AnswerService) afterAddAnswer(ctx context.Context, req *schema.AnswerAddReq, questionInfo *entity.Question,
+	na *newAnswer) (err error) {
+	if err = as.recordAnswer(ctx, na); err != nil {
+		return err
+	}
+	if !na.hold {
+		as.reviewService.NotifyAnswer(na.answer, questionInfo, na.mentionUserIDs)
 	}
+	return nil
+}
 
+// recordAnswer add the moderation result, review and revision of the added answer, and update the counts of it.
+// The held answer is counted once it is approved.
+func (as *AnswerService) recordAnswer(ctx context.Context, na *newAnswer) (err error) {
+	insertData := na.answer
+	as.moderationService.Record(ctx, na.moderationContent, insertData.ID, na.verdict)
+	if len(na.reviewQueue) > 0 {
+		if err = as.reviewService.AddReview(ctx, na.reviewQueue, insertData.ID, insertData.UserID, na.hold); err != nil {
+			return err
+		}
+	}
 	revisionDTO := &schema.AddRevisionDTO{
 		UserID:   insertData.UserID,
 		ObjectID: insertData.ID,
@@ -175,24 +322,13 @@ func (as *AnswerService) Insert(ctx context.Context, req *schema.AnswerAddReq) (
 	revisionDTO.Content = string(infoJSON)
 	revisionID, err := as.revisionService.AddRevision(ctx, revisionDTO, true)
 	if err != nil {
-		return insertData.ID, err
+		return err
 	}
-	as.notificationAnswerTheQuestion(ctx, questionInfo.UserID, insertData.ID, req.UserID)
-
-	activity_queue.AddActivity(&schema.ActivityMsg{
-		UserID:           insertData.UserID,
-		ObjectID:         insertData.ID,
-		OriginalObjectID: insertData.ID,
-		ActivityTypeKey:  constant.ActAnswerAnswered,
-		RevisionID:       revisionID,
-	})
-	activity_queue.AddActivity(&schema.ActivityMsg{
-		UserID:           insertData.UserID,
-		ObjectID:         insertData.ID,
-		OriginalObjectID: questionInfo.ID,
-		ActivityTypeKey:  constant.ActQuestionAnswered,
-	})
-	return insertData.ID, nil
+	if na.hold {
+		return nil
+	}
+	insertData.RevisionID = revisionID
+	return as.reviewService.CountAnswer(ctx, insertData)
 }
 
 func (as *AnswerService) Update(ctx context.Context, req *schema.AnswerUpdateReq) (string, error) {
@@ -222,12 +358,32 @@ func (as *AnswerService) Update(ctx context.Context, req *schema.AnswerUpdateReq
 	if !exist {
 		return "", nil
 	}
+	if answerInfo.CommunityWiki {
+		// the users who meet the threshold of the community wiki edit it without review
+		if req.CanEditWiki {
+			req.NoNeedReview = true
+		}
+	} else if !req.CanEdit {
+		return "", errors.Forbidden(reason.RankFailToMeetTheCondition)
+	}
+	// only the author or admin turns the answer into a community wiki, and it can not be turned back
+	communityWiki := answerInfo.CommunityWiki ||
+		(req.CommunityWiki && (req.IsAdmin || answerInfo.UserID == req.UserID))
 
 	//If the content is the same, ignore it
-	if answerInfo.OriginalText == req.Content {
+	language := langdetect.Resolve(req.Language, req.Content)
+	if answerInfo.OriginalText == req.Content && answerInfo.Language == language &&
+		answerInfo.CommunityWiki == communityWiki {
 		return "", nil
 	}
 
+	// only the users who are newly mentioned by the edit are notified
+	var mentionUserIDs []string
+	req.HTML, mentionUserIDs, err = as.mentionService.Resolve(ctx, req.Content, req.HTML, answerInfo.OriginalText)
+	if err != nil {
+		return "", err
+	}
+
 	now := time.Now()
 	insertData := new(entity.Answer)
 	insertData.ID = req.ID
@@ -235,6 +391,8 @@ func (as *AnswerService) Update(ctx context.Context, req *schema.AnswerUpdateReq
 	insertData.QuestionID = req.QuestionID
 	insertData.OriginalText = req.Content
 	insertData.ParsedText = req.HTML
+	insertData.Language = language
+	insertData.CommunityWiki = communityWiki
 	insertData.UpdatedAt = now
 
 	insertData.LastEditUserID = "0"
@@ -256,7 +414,7 @@ func (as *AnswerService) Update(ctx context.Context, req *schema.AnswerUpdateReq
 	if !canUpdate {
 		revisionDTO.Status = entity.RevisionUnreviewedStatus
 	} else {
-		if err = as.answerRepo.UpdateAnswer(ctx, insertData, []string{"original_text", "parsed_text", "updated_at", "last_edit_user_id"}); err != nil {
+		if err = as.answerRepo.UpdateAnswer(ctx, insertData, []string{"original_text", "parsed_text", "language", "updated_at", "last_edit_user_id", "community_wiki"}); err != nil {
 			return "", err
 		}
 		err = as.questionCommon.UpdataPostTime(ctx, req.QuestionID)
@@ -264,6 +422,7 @@ func (as *AnswerService) Update(ctx context.Context, req *schema.AnswerUpdateReq
 			return insertData.ID, err
 		}
 		as.notificationUpdateAnswer(ctx, questionInfo.UserID, insertData.ID, req.UserID)
+		as.mentionService.Notify(mentionUserIDs, req.UserID, insertData.ID, constant.AnswerObjectType)
 		revisionDTO.Status = entity.RevisionReviewPassStatus
 	}
 
@@ -273,6 +432,7 @@ func (as *AnswerService) Update(ctx context.Context, req *schema.AnswerUpdateReq
 	if err != nil {
 		return insertData.ID, err
 	}
+	as.draftService.RemoveSubmittedDraft(ctx, req.UserID, entity.DraftTypeEdit, insertData.ID)
 	if canUpdate {
 		activity_queue.AddActivity(&schema.ActivityMsg{
 			UserID:           insertData.UserID,
@@ -286,7 +446,8 @@ func (as *AnswerService) Update(ctx context.Context, req *schema.AnswerUpdateReq
 	return insertData.ID, nil
 }
 
-// UpdateAdopted
+// UpdateAdopted accept the answer, the answer id 0 unaccepts all the answers of the question.
+// The answer accepted before is unaccepted unless the site allows multiple accepted answers.
 func (as *AnswerService) UpdateAdopted(ctx context.Context, req *schema.AnswerAdoptedReq) error {
 	if req.AnswerID == "" {
 		req.AnswerID = "0"
@@ -304,64 +465,169 @@ func (as *AnswerService) UpdateAdopted(ctx context.Context, req *schema.AnswerAd
 		if err != nil {
 			return err
 		}
-		if !newAnswerInfoexist {
+		if !newAnswerInfoexist || newAnswerInfo.QuestionID != req.QuestionID {
 			return errors.BadRequest(reason.AnswerNotFound)
 		}
 	}
 
-	questionInfo, exist, err := as.questionRepo.GetQuestion(ctx, req.QuestionID)
+	questionInfo, acceptConfig, onBehalf, err := as.getAcceptQuestion(ctx, req)
 	if err != nil {
 		return err
 	}
-	if !exist {
-		return errors.BadRequest(reason.QuestionNotFound)
+
+	// the answers are unaccepted and accepted in one transaction, which starts by changing the accepted answer id
+	// of the question from the one read above, so the concurrent requests can't leave two answers accepted
+	return as.data.Transaction(ctx, func(ctx context.Context) error {
+		if questionInfo.AcceptedAnswerID != req.AnswerID {
+			changed, err := as.questionRepo.ChangeAccepted(ctx, questionInfo.ID, questionInfo.AcceptedAnswerID, req.AnswerID)
+			if err != nil {
+				return err
+			}
+			if !changed {
+				return errors.BadRequest(reason.AnswerAcceptChanged)
+			}
+		}
+		acceptedList, err := as.answerRepo.GetAcceptedAnswers(ctx, req.QuestionID)
+		if err != nil {
+			return err
+		}
+
+		alreadyAccepted := false
+		for _, answer := range acceptedList {
+			if answer.ID == req.AnswerID {
+				alreadyAccepted = true
+				continue
+			}
+			if acceptConfig.MultipleAccepted && req.AnswerID != "0" {
+				continue
+			}
+			if err = as.changeAccepted(ctx, req.UserID, questionInfo, answer, false, onBehalf); err != nil {
+				return err
+			}
+		}
+		if req.AnswerID != "0" && !alreadyAccepted {
+			return as.changeAccepted(ctx, req.UserID, questionInfo, newAnswerInfo, true, onBehalf)
+		}
+		return nil
+	})
+}
+
+// CancelAdopted unaccept the answer, the other accepted answers of the question are kept
+func (as *AnswerService) CancelAdopted(ctx context.Context, req *schema.AnswerAdoptedReq) error {
+	if req.UserID == "" {
+		return nil
 	}
-	if questionInfo.UserID != req.UserID {
-		return fmt.Errorf("no permission to set answer")
+	questionInfo, _, onBehalf, err := as.getAcceptQuestion(ctx, req)
+	if err != nil {
+		return err
 	}
-	if questionInfo.AcceptedAnswerID == req.AnswerID {
-		return nil
+	acceptedList, err := as.answerRepo.GetAcceptedAnswers(ctx, req.QuestionID)
+	if err != nil {
+		return err
 	}
 
-	var oldAnswerInfo *entity.Answer
-	if len(questionInfo.AcceptedAnswerID) > 0 && questionInfo.AcceptedAnswerID != "0" {
-		oldAnswerInfo, _, err = as.answerRepo.GetByID(ctx, questionInfo.AcceptedAnswerID)
-		if err != nil {
-			return err
+	// the question keeps its accepted answer id if it is still accepted
+	acceptedAnswerID := "0"
+	for _, answer := range acceptedList {
+		if answer.ID == req.AnswerID {
+			if err = as.changeAccepted(ctx, req.UserID, questionInfo, answer, false, onBehalf); err != nil {
+				return err
+			}
+			continue
 		}
+		if acceptedAnswerID == "0" || answer.ID == questionInfo.AcceptedAnswerID {
+			acceptedAnswerID = answer.ID
+		}
+	}
+	as.updateQuestionAccepted(ctx, questionInfo, acceptedAnswerID)
+	return nil
+}
+
+// getAcceptQuestion get the question whose answers are accepted and check the permission.
+// The asker accepts the answers, and the admin or the moderator of the tags of the question can accept them
+// on behalf of the asker who has been inactive for the configured days.
+func (as *AnswerService) getAcceptQuestion(ctx context.Context, req *schema.AnswerAdoptedReq) (
+	questionInfo *entity.Question, acceptConfig *schema.SiteAcceptResp, onBehalf bool, err error) {
+	questionInfo, exist, err := as.questionRepo.GetQuestion(ctx, req.QuestionID)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if !exist {
+		return nil, nil, false, errors.BadRequest(reason.QuestionNotFound)
+	}
+	acceptConfig, err = as.siteInfoService.GetSiteAccept(ctx)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if questionInfo.UserID == req.UserID {
+		return questionInfo, acceptConfig, false, nil
+	}
+	if !req.IsAdmin && !req.IsTagModerator {
+		return nil, nil, false, fmt.Errorf("no permission to set answer")
 	}
 
-	err = as.answerRepo.UpdateAdopted(ctx, req.AnswerID, req.QuestionID)
+	if acceptConfig.ModeratorAcceptDays <= 0 {
+		return nil, nil, false, errors.BadRequest(reason.AnswerCannotAcceptOnBehalf)
+	}
+	inactiveSince := time.Now().AddDate(0, 0, -acceptConfig.ModeratorAcceptDays)
+	if questionInfo.CreatedAt.After(inactiveSince) {
+		return nil, nil, false, errors.BadRequest(reason.AnswerCannotAcceptOnBehalf)
+	}
+	asker, exist, err := as.userRepo.GetByUserID(ctx, questionInfo.UserID)
 	if err != nil {
+		return nil, nil, false, err
+	}
+	if exist && asker.Status == entity.UserStatusAvailable && asker.LastLoginDate.After(inactiveSince) {
+		return nil, nil, false, errors.BadRequest(reason.AnswerCannotAcceptOnBehalf)
+	}
+	return questionInfo, acceptConfig, true, nil
+}
+
+// changeAccepted accept or unaccept the answer, adjust the reputation and record the history
+func (as *AnswerService) changeAccepted(ctx context.Context, userID string, questionInfo *entity.Question,
+	answerInfo *entity.Answer, accept, onBehalf bool) (err error) {
+	adopted, action := schema.AnswerAdoptedFailed, entity.AnswerAcceptActionUnaccept
+	if accept {
+		adopted, action = schema.AnswerAdoptedEnable, entity.AnswerAcceptActionAccept
+	}
+	if err = as.answerRepo.UpdateAnswerAdopted(ctx, answerInfo.ID, adopted); err != nil {
 		return err
 	}
 
-	err = as.questionCommon.UpdateAccepted(ctx, req.QuestionID, req.AnswerID)
+	// the reputation is changed for the asker and the answerer, even if the moderator accepts it on behalf of the asker
+	if accept {
+		err = as.answerActivityService.AcceptAnswer(ctx, answerInfo.ID, questionInfo.ID, questionInfo.UserID,
+			answerInfo.UserID, answerInfo.UserID == questionInfo.UserID)
+	} else {
+		err = as.answerActivityService.CancelAcceptAnswer(ctx, answerInfo.ID, questionInfo.ID, questionInfo.UserID,
+			answerInfo.UserID)
+	}
 	if err != nil {
-		log.Error("UpdateLastAnswer error", err.Error())
+		log.Error(err)
 	}
 
-	as.updateAnswerRank(ctx, req.UserID, questionInfo, newAnswerInfo, oldAnswerInfo)
+	err = as.answerAcceptLogRepo.AddAcceptLog(ctx, &entity.AnswerAcceptLog{
+		QuestionID: questionInfo.ID,
+		AnswerID:   answerInfo.ID,
+		UserID:     userID,
+		Action:     action,
+		OnBehalf:   onBehalf,
+	})
+	if err != nil {
+		log.Error(err)
+	}
 	return nil
 }
 
-func (as *AnswerService) updateAnswerRank(ctx context.Context, userID string,
-	questionInfo *entity.Question, newAnswerInfo *entity.Answer, oldAnswerInfo *entity.Answer,
-) {
-	// if this question is already been answered, should cancel old answer rank
-	if oldAnswerInfo != nil {
-		err := as.answerActivityService.CancelAcceptAnswer(
-			ctx, questionInfo.AcceptedAnswerID, questionInfo.ID, questionInfo.UserID, oldAnswerInfo.UserID)
-		if err != nil {
-			log.Error(err)
-		}
+// updateQuestionAccepted the question keeps one of its accepted answers as the accepted answer id
+func (as *AnswerService) updateQuestionAccepted(ctx context.Context, questionInfo *entity.Question,
+	acceptedAnswerID string) {
+	if questionInfo.AcceptedAnswerID == acceptedAnswerID {
+		return
 	}
-	if newAnswerInfo.ID != "" {
-		err := as.answerActivityService.AcceptAnswer(
-			ctx, newAnswerInfo.ID, questionInfo.ID, questionInfo.UserID, newAnswerInfo.UserID, newAnswerInfo.UserID == userID)
-		if err != nil {
-			log.Error(err)
-		}
+	err := as.questionCommon.UpdateAccepted(ctx, questionInfo.ID, acceptedAnswerID)
+	if err != nil {
+		log.Error("UpdateAccepted error", err.Error())
 	}
 }
 
@@ -370,6 +636,10 @@ func (as *AnswerService) Get(ctx context.Context, answerID, loginUserID string)
 	if err != nil {
 		return nil, nil, has, err
 	}
+	// the pending answer is only visible to its author
+	if has && answerInfo.Status == entity.AnswerStatusPending && answerInfo.UserID != loginUserID {
+		return nil, nil, false, errors.BadRequest(reason.AnswerNotFound)
+	}
 	info := as.ShowFormat(ctx, answerInfo)
 	// todo questionFunc
 	questionInfo, err := as.questionCommon.Info(ctx, answerInfo.QuestionID, loginUserID)
@@ -464,6 +734,7 @@ func (as *AnswerService) SearchList(ctx context.Context, req *schema.AnswerListR
 	dbSearch.Page = req.Page
 	dbSearch.PageSize = req.PageSize
 	dbSearch.Order = req.Order
+	dbSearch.PendingUserID = req.UserID
 	answerOriginalList, count, err := as.answerRepo.SearchList(ctx, &dbSearch)
 	if err != nil {
 		return list, count, err
@@ -542,15 +813,3 @@ func (as *AnswerService) notificationUpdateAnswer(ctx context.Context, questionU
 	msg.NotificationAction = constant.UpdateAnswer
 	notice_queue.AddNotification(msg)
 }
-
-func (as *AnswerService) notificationAnswerTheQuestion(ctx context.Context, questionUserID, answerID, answerUserID string) {
-	msg := &schema.NotificationMsg{
-		TriggerUserID:  answerUserID,
-		ReceiverUserID: questionUserID,
-		Type:           schema.NotificationTypeInbox,
-		ObjectID:       answerID,
-	}
-	msg.ObjectType = constant.AnswerObjectType
-	msg.NotificationAction = constant.AnswerTheQuestion
-	notice_queue.AddNotification(msg)
-}
@@ -2,10 +2,15 @@ package auth
 
 import (
 	"context"
+	"strings"
+	"time"
 
+	"answer/internal/base/constant"
+	"answer/internal/base/reason"
 	"answer/internal/entity"
 	"answer/pkg/token"
 
+	"github.com/segmentfault/pacman/errors"
 	"github.com/segmentfault/pacman/log"
 )
 
@@ -17,20 +22,36 @@ type AuthRepo interface {
 	SetUserStatus(ctx context.Context, userID string, userInfo *entity.UserCacheInfo) (err error)
 	GetUserStatus(ctx context.Context, userID string) (userInfo *entity.UserCacheInfo, err error)
 	RemoveUserStatus(ctx context.Context, userID string) (err error)
+	SetUserTokenRevokedAt(ctx context.Context, userID string, revokedAt int64) (err error)
+	GetUserTokenRevokedAt(ctx context.Context, userID string) (revokedAt int64, err error)
 	GetBackyardUserCacheInfo(ctx context.Context, accessToken string) (userInfo *entity.UserCacheInfo, err error)
 	SetBackyardUserCacheInfo(ctx context.Context, accessToken string, userInfo *entity.UserCacheInfo) error
 	RemoveBackyardUserCacheInfo(ctx context.Context, accessToken string) (err error)
 }
 
+// UserSessionRepo user session repository
+type UserSessionRepo interface {
+	AddUserSession(ctx context.Context, session *entity.UserSession) (err error)
+	GetUserSession(ctx context.Context, sessionID string) (session *entity.UserSession, exist bool, err error)
+	GetUserSessionList(ctx context.Context, userID string) (sessions []*entity.UserSession, err error)
+	UpdateLastSeen(ctx context.Context, sessionID string, lastSeenAt time.Time) (err error)
+	RemoveUserSession(ctx context.Context, sessionID string) (err error)
+	SetSessionToken(ctx context.Context, sessionID, accessToken string) (err error)
+	GetSessionToken(ctx context.Context, sessionID string) (accessToken string, err error)
+	RemoveSessionToken(ctx context.Context, sessionID string) (err error)
+}
+
 // AuthService kit service
 type AuthService struct {
-	authRepo AuthRepo
+	authRepo        AuthRepo
+	userSessionRepo UserSessionRepo
 }
 
 // NewAuthService email service
-func NewAuthService(authRepo AuthRepo) *AuthService {
+func NewAuthService(authRepo AuthRepo, userSessionRepo UserSessionRepo) *AuthService {
 	return &AuthService{
-		authRepo: authRepo,
+		authRepo:        authRepo,
+		userSessionRepo: userSessionRepo,
 	}
 }
 
@@ -39,6 +60,9 @@ func (as *AuthService) GetUserCacheInfo(ctx context.Context, accessToken string)
 	if err != nil {
 		return nil, err
 	}
+	if err = as.checkTokenRevoked(ctx, accessToken, userCacheInfo); err != nil {
+		return nil, err
+	}
 	cacheInfo, _ := as.authRepo.GetUserStatus(ctx, userCacheInfo.UserID)
 	if cacheInfo != nil {
 		log.Debugf("user status updated: %+v", cacheInfo)
@@ -51,15 +75,80 @@ func (as *AuthService) GetUserCacheInfo(ctx context.Context, accessToken string)
 			return nil, err
 		}
 	}
+	as.refreshSession(ctx, accessToken, userCacheInfo)
 	return userCacheInfo, nil
 }
 
-func (as *AuthService) SetUserCacheInfo(ctx context.Context, userInfo *entity.UserCacheInfo) (accessToken string, err error) {
+// refreshSession update the last seen time of the session, at most once in the session active interval
+func (as *AuthService) refreshSession(ctx context.Context, accessToken string, userCacheInfo *entity.UserCacheInfo) {
+	if len(userCacheInfo.SessionID) == 0 {
+		return
+	}
+	now := time.Now()
+	if now.Sub(time.Unix(userCacheInfo.LastSeenAt, 0)) < constant.UserSessionActiveInterval {
+		return
+	}
+	userCacheInfo.LastSeenAt = now.Unix()
+	if err := as.userSessionRepo.UpdateLastSeen(ctx, userCacheInfo.SessionID, now); err != nil {
+		log.Error(err)
+		return
+	}
+	// the token expiration is extended when the cache info is set, so does the session
+	if err := as.authRepo.SetUserCacheInfo(ctx, accessToken, userCacheInfo); err != nil {
+		log.Error(err)
+	}
+	if err := as.userSessionRepo.SetSessionToken(ctx, userCacheInfo.SessionID, accessToken); err != nil {
+		log.Error(err)
+	}
+}
+
+// SetUserCacheInfo issue a new access token for user, and record the session of the client
+func (as *AuthService) SetUserCacheInfo(ctx context.Context, userInfo *entity.UserCacheInfo, clientIP, userAgent string) (
+	accessToken string, err error) {
 	accessToken = token.GenerateToken()
+	now := time.Now()
+	session := &entity.UserSession{
+		UserID:     userInfo.UserID,
+		Device:     parseDevice(userAgent),
+		IP:         clientIP,
+		UserAgent:  userAgent,
+		LastSeenAt: now,
+	}
+	if len(session.UserAgent) > 512 {
+		session.UserAgent = session.UserAgent[:512]
+	}
+	if err = as.userSessionRepo.AddUserSession(ctx, session); err != nil {
+		return "", err
+	}
+	if err = as.userSessionRepo.SetSessionToken(ctx, session.ID, accessToken); err != nil {
+		return "", err
+	}
+	userInfo.SessionID = session.ID
+	userInfo.LastSeenAt = now.Unix()
+	userInfo.IssuedAt = now.UnixMilli()
 	err = as.authRepo.SetUserCacheInfo(ctx, accessToken, userInfo)
 	return accessToken, err
 }
 
+// checkTokenRevoked the tokens issued before all tokens of user are revoked are removed,
+// including the tokens issued before the sessions, which have no issued time
+func (as *AuthService) checkTokenRevoked(ctx context.Context, accessToken string, userCacheInfo *entity.UserCacheInfo) (err error) {
+	revokedAt, err := as.authRepo.GetUserTokenRevokedAt(ctx, userCacheInfo.UserID)
+	if err != nil {
+		return err
+	}
+	if userCacheInfo.IssuedAt >= revokedAt {
+		return nil
+	}
+	if err = as.authRepo.RemoveUserCacheInfo(ctx, accessToken); err != nil {
+		log.Error(err)
+	}
+	if err = as.authRepo.RemoveBackyardUserCacheInfo(ctx, accessToken); err != nil {
+		log.Error(err)
+	}
+	return errors.Unauthorized(reason.UnauthorizedError)
+}
+
 func (as *AuthService) SetUserStatus(ctx context.Context, userInfo *entity.UserCacheInfo) (err error) {
 	return as.authRepo.SetUserStatus(ctx, userInfo.UserID, userInfo)
 }
@@ -76,16 +165,140 @@ func (as *AuthService) UpdateUserCacheInfo(ctx context.Context, token string, us
 }
 
 func (as *AuthService) RemoveUserCacheInfo(ctx context.Context, accessToken string) (err error) {
+	userCacheInfo, err := as.authRepo.GetUserCacheInfo(ctx, accessToken)
+	if err == nil && len(userCacheInfo.SessionID) > 0 {
+		if err = as.userSessionRepo.RemoveSessionToken(ctx, userCacheInfo.SessionID); err != nil {
+			log.Error(err)
+		}
+		if err = as.userSessionRepo.RemoveUserSession(ctx, userCacheInfo.SessionID); err != nil {
+			log.Error(err)
+		}
+	}
 	return as.authRepo.RemoveUserCacheInfo(ctx, accessToken)
 }
 
+// GetUserSessionList get the active sessions of user, the expired sessions are cleared
+func (as *AuthService) GetUserSessionList(ctx context.Context, userID string) (
+	sessions []*entity.UserSession, err error) {
+	allSessions, err := as.userSessionRepo.GetUserSessionList(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sessions = make([]*entity.UserSession, 0, len(allSessions))
+	for _, session := range allSessions {
+		accessToken, err := as.userSessionRepo.GetSessionToken(ctx, session.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(accessToken) > 0 {
+			sessions = append(sessions, session)
+			continue
+		}
+		if err = as.userSessionRepo.RemoveUserSession(ctx, session.ID); err != nil {
+			log.Error(err)
+		}
+	}
+	return sessions, nil
+}
+
+// RevokeUserSession revoke the session of user, the access token of the session will be invalid
+func (as *AuthService) RevokeUserSession(ctx context.Context, userID, sessionID string) (err error) {
+	session, exist, err := as.userSessionRepo.GetUserSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if !exist || session.UserID != userID {
+		return nil
+	}
+	return as.revokeSession(ctx, sessionID)
+}
+
+// RevokeUserSessions revoke all sessions of user except the given one, such as the current session.
+// Pass empty exceptSessionID to sign out the user everywhere.
+// The tokens without session, issued before the sessions, are revoked too.
+func (as *AuthService) RevokeUserSessions(ctx context.Context, userID, exceptSessionID string) (err error) {
+	revokedAt := time.Now().UnixMilli()
+	// reissue the kept token before the revocation, or it is revoked by the requests in the meantime
+	if len(exceptSessionID) > 0 {
+		if err = as.reissueSessionToken(ctx, exceptSessionID, revokedAt); err != nil {
+			return err
+		}
+	}
+	if err = as.authRepo.SetUserTokenRevokedAt(ctx, userID, revokedAt); err != nil {
+		return err
+	}
+	sessions, err := as.userSessionRepo.GetUserSessionList(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if session.ID == exceptSessionID {
+			continue
+		}
+		if err = as.revokeSession(ctx, session.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reissueSessionToken update the issued time of the access token of session
+func (as *AuthService) reissueSessionToken(ctx context.Context, sessionID string, issuedAt int64) (err error) {
+	accessToken, err := as.userSessionRepo.GetSessionToken(ctx, sessionID)
+	if err != nil || len(accessToken) == 0 {
+		return err
+	}
+	if userCacheInfo, err := as.authRepo.GetUserCacheInfo(ctx, accessToken); err == nil {
+		userCacheInfo.IssuedAt = issuedAt
+		if err = as.authRepo.SetUserCacheInfo(ctx, accessToken, userCacheInfo); err != nil {
+			return err
+		}
+	}
+	if userCacheInfo, err := as.authRepo.GetBackyardUserCacheInfo(ctx, accessToken); err == nil {
+		userCacheInfo.IssuedAt = issuedAt
+		if err = as.authRepo.SetBackyardUserCacheInfo(ctx, accessToken, userCacheInfo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (as *AuthService) revokeSession(ctx context.Context, sessionID string) (err error) {
+	accessToken, err := as.userSessionRepo.GetSessionToken(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if len(accessToken) > 0 {
+		if err = as.authRepo.RemoveUserCacheInfo(ctx, accessToken); err != nil {
+			return err
+		}
+		if err = as.authRepo.RemoveBackyardUserCacheInfo(ctx, accessToken); err != nil {
+			return err
+		}
+		if err = as.userSessionRepo.RemoveSessionToken(ctx, sessionID); err != nil {
+			return err
+		}
+	}
+	return as.userSessionRepo.RemoveUserSession(ctx, sessionID)
+}
+
 //cms
 
 func (as *AuthService) GetCmsUserCacheInfo(ctx context.Context, accessToken string) (userInfo *entity.UserCacheInfo, err error) {
-	return as.authRepo.GetBackyardUserCacheInfo(ctx, accessToken)
+	userInfo, err = as.authRepo.GetBackyardUserCacheInfo(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if err = as.checkTokenRevoked(ctx, accessToken, userInfo); err != nil {
+		return nil, err
+	}
+	return userInfo, nil
 }
 
 func (as *AuthService) SetCmsUserCacheInfo(ctx context.Context, accessToken string, userInfo *entity.UserCacheInfo) (err error) {
+	if userInfo.IssuedAt == 0 {
+		userInfo.IssuedAt = time.Now().UnixMilli()
+	}
 	err = as.authRepo.SetBackyardUserCacheInfo(ctx, accessToken, userInfo)
 	return err
 }
@@ -93,3 +306,40 @@ func (as *AuthService) SetCmsUserCacheInfo(ctx context.Context, accessToken stri
 func (as *AuthService) RemoveCmsUserCacheInfo(ctx context.Context, accessToken string) (err error) {
 	return as.authRepo.RemoveBackyardUserCacheInfo(ctx, accessToken)
 }
+
+// parseDevice parse a readable device name from user agent, such as "Chrome on Windows"
+func parseDevice(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	if len(ua) == 0 {
+		return ""
+	}
+	browser := "Unknown browser"
+	switch {
+	case strings.Contains(ua, "edg/"):
+		browser = "Edge"
+	case strings.Contains(ua, "opr/") || strings.Contains(ua, "opera"):
+		browser = "Opera"
+	case strings.Contains(ua, "firefox/"):
+		browser = "Firefox"
+	case strings.Contains(ua, "chrome/") || strings.Contains(ua, "crios/"):
+		browser = "Chrome"
+	case strings.Contains(ua, "safari/"):
+		browser = "Safari"
+	case strings.Contains(ua, "curl/"):
+		browser = "curl"
+	}
+	platform := "Unknown OS"
+	switch {
+	case strings.Contains(ua, "android"):
+		platform = "Android"
+	case strings.Contains(ua, "iphone") || strings.Contains(ua, "ipad"):
+		platform = "iOS"
+	case strings.Contains(ua, "windows"):
+		platform = "Windows"
+	case strings.Contains(ua, "mac os"):
+		platform = "macOS"
+	case strings.Contains(ua, "linux"):
+		platform = "Linux"
+	}
+	return browser + " on " + platform
+}
@@ -19,6 +19,9 @@ type CollectionRepo interface {
 	GetCollectionPage(ctx context.Context, page, pageSize int, collection *entity.Collection) (collectionList []*entity.Collection, total int64, err error)
 	SearchObjectCollected(ctx context.Context, userId string, objectIds []string) (collectedMap map[string]bool, err error)
 	SearchList(ctx context.Context, search *entity.CollectionSearch) ([]*entity.Collection, int64, error)
+	GetGroupCollectionPage(ctx context.Context, groupID string, page, pageSize int) (
+		collectionList []*entity.Collection, total int64, err error)
+	CountGroupCollections(ctx context.Context, userID string) (counts map[string]int64, err error)
 }
 
 // CollectionCommon user service
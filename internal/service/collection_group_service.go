@@ -2,15 +2,29 @@ package service
 
 import (
 	"context"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
 
 	"answer/internal/base/reason"
 	"answer/internal/entity"
 	"answer/internal/schema"
+	collectioncommon "answer/internal/service/collection_common"
+	questioncommon "answer/internal/service/question_common"
+	"answer/internal/service/siteinfo_common"
+	usercommon "answer/internal/service/user_common"
+	"answer/pkg/htmltext"
+	"answer/pkg/token"
 
-	"github.com/jinzhu/copier"
 	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
 )
 
+// collectionGroupRSSItemCount the number of the newest collections in the rss feed
+const collectionGroupRSSItemCount = 50
+
 // CollectionGroupRepo collectionGroup repository
 type CollectionGroupRepo interface {
 	AddCollectionGroup(ctx context.Context, collectionGroup *entity.CollectionGroup) (err error)
@@ -19,33 +33,124 @@ type CollectionGroupRepo interface {
 	GetCollectionGroup(ctx context.Context, id string) (collectionGroup *entity.CollectionGroup, exist bool, err error)
 	GetCollectionGroupPage(ctx context.Context, page, pageSize int, collectionGroup *entity.CollectionGroup) (collectionGroupList []*entity.CollectionGroup, total int64, err error)
 	GetDefaultID(ctx context.Context, userID string) (collectionGroup *entity.CollectionGroup, has bool, err error)
+	GetCollectionGroupList(ctx context.Context, userID string) (collectionGroupList []*entity.CollectionGroup, err error)
+	GetPublicCollectionGroupByToken(ctx context.Context, token string) (
+		collectionGroup *entity.CollectionGroup, exist bool, err error)
+	RemoveCollectionGroup(ctx context.Context, collectionGroup *entity.CollectionGroup, targetGroupID string) (err error)
+	SortCollectionGroups(ctx context.Context, userID string, ids []string) (err error)
 }
 
 // CollectionGroupService user service
 type CollectionGroupService struct {
 	collectionGroupRepo CollectionGroupRepo
+	collectionRepo      collectioncommon.CollectionRepo
+	questionCommon      *questioncommon.QuestionCommon
+	userCommon          *usercommon.UserCommon
+	siteInfoService     *siteinfo_common.SiteInfoCommonService
 }
 
-func NewCollectionGroupService(collectionGroupRepo CollectionGroupRepo) *CollectionGroupService {
+func NewCollectionGroupService(
+	collectionGroupRepo CollectionGroupRepo,
+	collectionRepo collectioncommon.CollectionRepo,
+	questionCommon *questioncommon.QuestionCommon,
+	userCommon *usercommon.UserCommon,
+	siteInfoService *siteinfo_common.SiteInfoCommonService,
+) *CollectionGroupService {
 	return &CollectionGroupService{
 		collectionGroupRepo: collectionGroupRepo,
+		collectionRepo:      collectionRepo,
+		questionCommon:      questionCommon,
+		userCommon:          userCommon,
+		siteInfoService:     siteInfoService,
 	}
 }
 
-// AddCollectionGroup add collection group
-func (cs *CollectionGroupService) AddCollectionGroup(ctx context.Context, req *schema.AddCollectionGroupReq) (err error) {
-	collectionGroup := &entity.CollectionGroup{}
-	_ = copier.Copy(collectionGroup, req)
-	return cs.collectionGroupRepo.AddCollectionGroup(ctx, collectionGroup)
+// AddCollectionGroup add collection group, the new group is placed at the end
+func (cs *CollectionGroupService) AddCollectionGroup(ctx context.Context, req *schema.AddCollectionGroupReq) (
+	resp *schema.GetCollectionGroupResp, err error) {
+	groups, err := cs.collectionGroupRepo.GetCollectionGroupList(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	collectionGroup := &entity.CollectionGroup{
+		UserID:       req.UserID,
+		Name:         req.Name,
+		DefaultGroup: schema.CGDIY,
+		SortOrder:    len(groups) + 1,
+		IsPublic:     req.IsPublic,
+	}
+	if collectionGroup.IsPublic {
+		collectionGroup.ShareToken = token.GenerateToken()
+	}
+	if err = cs.collectionGroupRepo.AddCollectionGroup(ctx, collectionGroup); err != nil {
+		return nil, err
+	}
+	return cs.formatCollectionGroup(collectionGroup, cs.getSiteURL(ctx), 0), nil
 }
 
-// UpdateCollectionGroup update collection group
-func (cs *CollectionGroupService) UpdateCollectionGroup(ctx context.Context, req *schema.UpdateCollectionGroupReq, cols []string) (err error) {
-	collectionGroup := &entity.CollectionGroup{}
-	_ = copier.Copy(collectionGroup, req)
+// UpdateCollectionGroup rename the collection group or change its visibility.
+// The share token is kept when the group becomes private, so the shared url works again if it becomes public.
+func (cs *CollectionGroupService) UpdateCollectionGroup(ctx context.Context, req *schema.UpdateCollectionGroupReq) (
+	err error) {
+	collectionGroup, err := cs.getUserCollectionGroup(ctx, req.UserID, req.ID)
+	if err != nil {
+		return err
+	}
+	collectionGroup.Name = req.Name
+	collectionGroup.IsPublic = req.IsPublic
+	cols := []string{"name", "is_public"}
+	if collectionGroup.IsPublic && len(collectionGroup.ShareToken) == 0 {
+		collectionGroup.ShareToken = token.GenerateToken()
+		cols = append(cols, "share_token")
+	}
 	return cs.collectionGroupRepo.UpdateCollectionGroup(ctx, collectionGroup, cols)
 }
 
+// RemoveCollectionGroup remove the collection group, the collections in it are moved to the default group
+func (cs *CollectionGroupService) RemoveCollectionGroup(ctx context.Context, req *schema.RemoveCollectionGroupReq) (
+	err error) {
+	collectionGroup, err := cs.getUserCollectionGroup(ctx, req.UserID, req.ID)
+	if err != nil {
+		return err
+	}
+	if collectionGroup.DefaultGroup == schema.CGDefault {
+		return errors.BadRequest(reason.CollectionGroupCannotRemove)
+	}
+	defaultGroup, err := cs.getDefaultGroup(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+	return cs.collectionGroupRepo.RemoveCollectionGroup(ctx, collectionGroup, defaultGroup.ID)
+}
+
+// SortCollectionGroups reorder the collection groups of the user, the groups of others are ignored
+func (cs *CollectionGroupService) SortCollectionGroups(ctx context.Context, req *schema.SortCollectionGroupReq) (
+	err error) {
+	return cs.collectionGroupRepo.SortCollectionGroups(ctx, req.UserID, req.IDs)
+}
+
+// GetCollectionGroupList get the collection groups of the user in order, the default group is created if not exist
+func (cs *CollectionGroupService) GetCollectionGroupList(ctx context.Context, userID string) (
+	resp []*schema.GetCollectionGroupResp, err error) {
+	if _, err = cs.getDefaultGroup(ctx, userID); err != nil {
+		return nil, err
+	}
+	groups, err := cs.collectionGroupRepo.GetCollectionGroupList(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := cs.collectionRepo.CountGroupCollections(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	siteURL := cs.getSiteURL(ctx)
+	resp = make([]*schema.GetCollectionGroupResp, 0, len(groups))
+	for _, group := range groups {
+		resp = append(resp, cs.formatCollectionGroup(group, siteURL, counts[group.ID]))
+	}
+	return resp, nil
+}
+
 // GetCollectionGroup get collection group one
 func (cs *CollectionGroupService) GetCollectionGroup(ctx context.Context, id string) (resp *schema.GetCollectionGroupResp, err error) {
 	collectionGroup, exist, err := cs.collectionGroupRepo.GetCollectionGroup(ctx, id)
@@ -53,10 +158,201 @@ func (cs *CollectionGroupService) GetCollectionGroup(ctx context.Context, id str
 		return
 	}
 	if !exist {
-		return nil, errors.BadRequest(reason.UnknownError)
+		return nil, errors.BadRequest(reason.CollectionGroupNotFound)
 	}
+	return cs.formatCollectionGroup(collectionGroup, cs.getSiteURL(ctx), 0), nil
+}
 
-	resp = &schema.GetCollectionGroupResp{}
-	_ = copier.Copy(resp, collectionGroup)
+// GetSharedCollectionGroup get the public collection group by the share token, the notes are not shown
+func (cs *CollectionGroupService) GetSharedCollectionGroup(ctx context.Context,
+	req *schema.GetSharedCollectionGroupReq) (resp *schema.GetSharedCollectionGroupResp, err error) {
+	collectionGroup, exist, err := cs.collectionGroupRepo.GetPublicCollectionGroupByToken(ctx, req.Token)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.BadRequest(reason.CollectionGroupNotFound)
+	}
+	userInfo, _, err := cs.userCommon.GetUserBasicInfoByID(ctx, collectionGroup.UserID)
+	if err != nil {
+		return nil, err
+	}
+	collections, total, err := cs.collectionRepo.GetGroupCollectionPage(ctx, collectionGroup.ID, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	questions, err := cs.getVisibleQuestions(ctx, collections)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &schema.GetSharedCollectionGroupResp{
+		Name:     collectionGroup.Name,
+		RSSURL:   collectionGroupRSSURL(cs.getSiteURL(ctx), collectionGroup.ShareToken),
+		UserInfo: userInfo,
+		Count:    total,
+		List:     make([]*schema.QuestionInfo, 0, len(questions)),
+	}
+	for _, question := range questions {
+		question.Content = ""
+		question.HTML = ""
+		resp.List = append(resp.List, question)
+	}
 	return resp, nil
 }
+
+// GetCollectionGroupRSS get the rss feed of the newest collections in the public collection group
+func (cs *CollectionGroupService) GetCollectionGroupRSS(ctx context.Context, shareToken string) (
+	content string, err error) {
+	collectionGroup, exist, err := cs.collectionGroupRepo.GetPublicCollectionGroupByToken(ctx, shareToken)
+	if err != nil {
+		return "", err
+	}
+	if !exist {
+		return "", errors.NotFound(reason.CollectionGroupNotFound)
+	}
+	collections, _, err := cs.collectionRepo.GetGroupCollectionPage(ctx, collectionGroup.ID, 1,
+		collectionGroupRSSItemCount)
+	if err != nil {
+		return "", err
+	}
+	questions, err := cs.getVisibleQuestions(ctx, collections)
+	if err != nil {
+		return "", err
+	}
+	collectedAt := make(map[string]time.Time, len(collections))
+	for _, collection := range collections {
+		collectedAt[collection.ObjectID] = collection.CreatedAt
+	}
+
+	siteURL := cs.getSiteURL(ctx)
+	siteName := ""
+	if siteGeneral, err := cs.siteInfoService.GetSiteGeneral(ctx); err == nil {
+		siteName = siteGeneral.Name
+	}
+	channel := &rssChannel{
+		Title:       strings.TrimSpace(collectionGroup.Name + " - " + siteName),
+		Link:        fmt.Sprintf("%s/collections/%s", siteURL, collectionGroup.ShareToken),
+		Description: collectionGroup.Name,
+		Items:       make([]*rssItem, 0, len(questions)),
+	}
+	for _, question := range questions {
+		link := fmt.Sprintf("%s/questions/%s", siteURL, question.ID)
+		channel.Items = append(channel.Items, &rssItem{
+			Title:       question.Title,
+			Link:        link,
+			GUID:        link,
+			Description: htmltext.FetchExcerpt(question.HTML, "...", 240),
+			PubDate:     collectedAt[question.ID].Format(time.RFC1123Z),
+		})
+	}
+	body, err := xml.MarshalIndent(&rss{Version: "2.0", Channel: channel}, "", "  ")
+	if err != nil {
+		return "", errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	return xml.Header + string(body), nil
+}
+
+// getVisibleQuestions get the questions of the collections in order, the deleted and pending questions are skipped
+func (cs *CollectionGroupService) getVisibleQuestions(ctx context.Context, collections []*entity.Collection) (
+	questions []*schema.QuestionInfo, err error) {
+	questionIDs := make([]string, 0, len(collections))
+	for _, collection := range collections {
+		questionIDs = append(questionIDs, collection.ObjectID)
+	}
+	questionMapping, err := cs.questionCommon.FindInfoByID(ctx, questionIDs, "")
+	if err != nil {
+		return nil, err
+	}
+	questions = make([]*schema.QuestionInfo, 0, len(questionIDs))
+	for _, id := range questionIDs {
+		question, ok := questionMapping[id]
+		if !ok {
+			continue
+		}
+		if question.Status != entity.QuestionStatusAvailable && question.Status != entity.QuestionStatusClosed {
+			continue
+		}
+		question.UpdateUserInfo = nil
+		question.LastAnsweredUserInfo = nil
+		questions = append(questions, question)
+	}
+	return questions, nil
+}
+
+// getUserCollectionGroup get the collection group which belongs to the user
+func (cs *CollectionGroupService) getUserCollectionGroup(ctx context.Context, userID, id string) (
+	collectionGroup *entity.CollectionGroup, err error) {
+	collectionGroup, exist, err := cs.collectionGroupRepo.GetCollectionGroup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !exist || collectionGroup.UserID != userID {
+		return nil, errors.BadRequest(reason.CollectionGroupNotFound)
+	}
+	return collectionGroup, nil
+}
+
+func (cs *CollectionGroupService) getDefaultGroup(ctx context.Context, userID string) (
+	collectionGroup *entity.CollectionGroup, err error) {
+	collectionGroup, exist, err := cs.collectionGroupRepo.GetDefaultID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		return collectionGroup, nil
+	}
+	return cs.collectionGroupRepo.AddCollectionDefaultGroup(ctx, userID)
+}
+
+func (cs *CollectionGroupService) formatCollectionGroup(collectionGroup *entity.CollectionGroup, siteURL string,
+	collectionCount int64) *schema.GetCollectionGroupResp {
+	resp := &schema.GetCollectionGroupResp{
+		ID:              collectionGroup.ID,
+		Name:            collectionGroup.Name,
+		DefaultGroup:    collectionGroup.DefaultGroup == schema.CGDefault,
+		IsPublic:        collectionGroup.IsPublic,
+		CollectionCount: collectionCount,
+		CreatedAt:       collectionGroup.CreatedAt.Unix(),
+	}
+	if collectionGroup.IsPublic {
+		resp.ShareURL = fmt.Sprintf("%s/collections/%s", siteURL, collectionGroup.ShareToken)
+		resp.RSSURL = collectionGroupRSSURL(siteURL, collectionGroup.ShareToken)
+	}
+	return resp
+}
+
+func (cs *CollectionGroupService) getSiteURL(ctx context.Context) string {
+	siteGeneral, err := cs.siteInfoService.GetSiteGeneral(ctx)
+	if err != nil {
+		log.Errorf("get site general failed: %s", err)
+		return ""
+	}
+	return siteGeneral.SiteUrl
+}
+
+// collectionGroupRSSURL the rss feed url of the public collection group
+func collectionGroupRSSURL(siteURL, shareToken string) string {
+	return fmt.Sprintf("%s/answer/api/v1/collection/group/rss?token=%s", siteURL, url.QueryEscape(shareToken))
+}
+
+type rss struct {
+	XMLName xml.Name    `xml:"rss"`
+	Version string      `xml:"version,attr"`
+	Channel *rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title       string     `xml:"title"`
+	Link        string     `xml:"link"`
+	Description string     `xml:"description"`
+	Items       []*rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	GUID        string `xml:"guid"`
+	Description string `xml:"description"`
+	PubDate     string `xml:"pubDate"`
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 
+	"answer/internal/base/pager"
+	"answer/internal/base/reason"
 	"answer/internal/entity"
 	"answer/internal/schema"
 	collectioncommon "answer/internal/service/collection_common"
@@ -76,6 +78,8 @@ func (cs *CollectionService) CollectionSwitch(ctx context.Context, dto *schema.C
 		} else {
 			dto.GroupID = defaultGroup.ID
 		}
+	} else if err = cs.checkCollectionGroup(ctx, dto.UserID, dto.GroupID); err != nil {
+		return nil, err
 	}
 	collection := &entity.Collection{
 		UserCollectionGroupID: dto.GroupID,
@@ -100,6 +104,84 @@ func (cs *CollectionService) CollectionSwitch(ctx context.Context, dto *schema.C
 	return
 }
 
+// MoveCollection move the collection of the login user to another group
+func (cs *CollectionService) MoveCollection(ctx context.Context, req *schema.MoveCollectionReq) (err error) {
+	collection, exist, err := cs.collectionRepo.GetOneByObjectIDAndUser(ctx, req.UserID, req.ObjectID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.CollectionNotFound)
+	}
+	if err = cs.checkCollectionGroup(ctx, req.UserID, req.GroupID); err != nil {
+		return err
+	}
+	collection.UserCollectionGroupID = req.GroupID
+	return cs.collectionRepo.UpdateCollection(ctx, collection, []string{"user_collection_group_id"})
+}
+
+// UpdateCollectionNote update the private note of the collection, only the owner can see the note
+func (cs *CollectionService) UpdateCollectionNote(ctx context.Context, req *schema.UpdateCollectionNoteReq) (err error) {
+	collection, exist, err := cs.collectionRepo.GetOneByObjectIDAndUser(ctx, req.UserID, req.ObjectID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.CollectionNotFound)
+	}
+	collection.Note = req.Note
+	return cs.collectionRepo.UpdateCollection(ctx, collection, []string{"note"})
+}
+
+// GetCollectionPage get the collections in the group of the login user with the notes, the newest first
+func (cs *CollectionService) GetCollectionPage(ctx context.Context, req *schema.GetCollectionPageReq) (
+	pageModel *pager.PageModel, err error) {
+	if err = cs.checkCollectionGroup(ctx, req.UserID, req.GroupID); err != nil {
+		return nil, err
+	}
+	collections, total, err := cs.collectionRepo.GetGroupCollectionPage(ctx, req.GroupID, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	questionIDs := make([]string, 0, len(collections))
+	for _, collection := range collections {
+		questionIDs = append(questionIDs, collection.ObjectID)
+	}
+	questionMapping, err := cs.questionCommon.FindInfoByID(ctx, questionIDs, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*schema.GetCollectionResp, 0, len(collections))
+	for _, collection := range collections {
+		item := &schema.GetCollectionResp{
+			ObjectID:  collection.ObjectID,
+			GroupID:   collection.UserCollectionGroupID,
+			Note:      collection.Note,
+			CreatedAt: collection.CreatedAt.Unix(),
+		}
+		if question, ok := questionMapping[collection.ObjectID]; ok && question.Status != entity.QuestionStatusDeleted {
+			question.Content = ""
+			question.HTML = ""
+			item.Question = question
+		}
+		list = append(list, item)
+	}
+	return pager.NewPageModel(total, list), nil
+}
+
+// checkCollectionGroup the collection group should belong to the user
+func (cs *CollectionService) checkCollectionGroup(ctx context.Context, userID, groupID string) (err error) {
+	collectionGroup, exist, err := cs.collectionGroupRepo.GetCollectionGroup(ctx, groupID)
+	if err != nil {
+		return err
+	}
+	if !exist || collectionGroup.UserID != userID {
+		return errors.BadRequest(reason.CollectionGroupNotFound)
+	}
+	return nil
+}
+
 func (cs *CollectionService) objectCollectionCount(ctx context.Context, objectID string) (int64, error) {
 	count, err := cs.collectionRepo.CountByObjectID(ctx, objectID)
 	return count, err
@@ -2,6 +2,8 @@ package comment
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"answer/internal/base/constant"
 	"answer/internal/base/pager"
@@ -11,10 +13,14 @@ import (
 	"answer/internal/service/activity_common"
 	"answer/internal/service/activity_queue"
 	"answer/internal/service/comment_common"
+	moderationservice "answer/internal/service/moderation"
 	"answer/internal/service/notice_queue"
 	"answer/internal/service/object_info"
 	"answer/internal/service/permission"
+	"answer/internal/service/revision_common"
+	"answer/internal/service/siteinfo_common"
 	usercommon "answer/internal/service/user_common"
+	"answer/pkg/moderation"
 
 	"github.com/jinzhu/copier"
 	"github.com/segmentfault/pacman/errors"
@@ -29,6 +35,8 @@ type CommentRepo interface {
 	GetComment(ctx context.Context, commentID string) (comment *entity.Comment, exist bool, err error)
 	GetCommentPage(ctx context.Context, commentQuery *CommentQuery) (
 		comments []*entity.Comment, total int64, err error)
+	GetCommentReplies(ctx context.Context, parentCommentIDs []string, limit int) (comments []*entity.Comment, err error)
+	CountCommentReplies(ctx context.Context, parentCommentIDs []string) (counts map[string]int, err error)
 }
 
 // CommentService user service
@@ -38,12 +46,17 @@ type CommentService struct {
 	userCommon        *usercommon.UserCommon
 	voteCommon        activity_common.VoteRepo
 	objectInfoService *object_info.ObjService
+	moderationService *moderationservice.ModerationService
+	siteInfoService   *siteinfo_common.SiteInfoCommonService
+	revisionService   *revision_common.RevisionService
 }
 
 type CommentQuery struct {
 	pager.PageCond
 	// object id
 	ObjectID string
+	// parent comment id, 0 means the top level comments
+	ParentCommentID string
 	// query condition
 	QueryCond string
 	// user id
@@ -66,22 +79,32 @@ func NewCommentService(
 	commentCommonRepo comment_common.CommentCommonRepo,
 	userCommon *usercommon.UserCommon,
 	objectInfoService *object_info.ObjService,
-	voteCommon activity_common.VoteRepo) *CommentService {
+	voteCommon activity_common.VoteRepo,
+	moderationService *moderationservice.ModerationService,
+	siteInfoService *siteinfo_common.SiteInfoCommonService,
+	revisionService *revision_common.RevisionService) *CommentService {
 	return &CommentService{
 		commentRepo:       commentRepo,
 		commentCommonRepo: commentCommonRepo,
 		userCommon:        userCommon,
 		voteCommon:        voteCommon,
 		objectInfoService: objectInfoService,
+		moderationService: moderationService,
+		siteInfoService:   siteInfoService,
+		revisionService:   revisionService,
 	}
 }
 
 // AddComment add comment
 func (cs *CommentService) AddComment(ctx context.Context, req *schema.AddCommentReq) (
 	resp *schema.GetCommentResp, err error) {
+	if len(req.MentionUsernameList) > constant.MentionMaxPerPost {
+		return nil, errors.BadRequest(reason.MentionTooMany)
+	}
 	comment := &entity.Comment{}
 	_ = copier.Copy(comment, req)
 	comment.Status = entity.CommentStatusAvailable
+	comment.ParentCommentID = "0"
 
 	// add question id
 	objInfo, err := cs.objectInfoService.GetInfo(ctx, req.ObjectID)
@@ -97,20 +120,39 @@ func (cs *CommentService) AddComment(ctx context.Context, req *schema.AddComment
 		if err != nil {
 			return nil, err
 		}
-		if !exist {
+		if !exist || replyComment.ObjectID != req.ObjectID {
 			return nil, errors.BadRequest(reason.CommentNotFound)
 		}
 		comment.SetReplyUserID(replyComment.UserID)
 		comment.SetReplyCommentID(replyComment.ID)
+		// the reply deeper than the limit is put beside the replied comment
+		comment.ParentCommentID, comment.Depth = replyComment.ID, replyComment.Depth+1
+		if comment.Depth >= constant.CommentMaxDepth {
+			comment.ParentCommentID, comment.Depth = replyComment.ParentCommentID, replyComment.Depth
+		}
 	} else {
 		comment.SetReplyUserID("")
 		comment.SetReplyCommentID("")
 	}
 
+	moderationContent := &moderation.Content{
+		ObjectType: constant.CommentObjectType,
+		UserID:     req.UserID,
+		HTML:       req.ParsedText,
+		IP:         req.IP,
+		UserAgent:  req.UserAgent,
+	}
+	verdict, err := cs.moderationService.Check(ctx, moderationContent)
+	if err != nil {
+		return nil, err
+	}
+
 	err = cs.commentRepo.AddComment(ctx, comment)
 	if err != nil {
 		return nil, err
 	}
+	cs.moderationService.Record(ctx, moderationContent, comment.ID, verdict)
+	cs.addCommentRevision(ctx, comment, req.UserID)
 
 	if objInfo.ObjectType == constant.QuestionObjectType {
 		cs.notificationQuestionComment(ctx, objInfo.ObjectCreatorUserID, comment.ID, req.UserID)
@@ -169,21 +211,41 @@ func (cs *CommentService) AddComment(ctx context.Context, req *schema.AddComment
 
 // RemoveComment delete comment
 func (cs *CommentService) RemoveComment(ctx context.Context, req *schema.RemoveCommentReq) (err error) {
-	if err := cs.checkCommentWhetherOwner(ctx, req.UserID, req.CommentID); err != nil {
+	if _, err := cs.checkCommentWhetherOwner(ctx, req.UserID, req.CommentID); err != nil {
 		return err
 	}
 	return cs.commentRepo.RemoveComment(ctx, req.CommentID)
 }
 
-// UpdateComment update comment
+// UpdateComment update comment, the author can not edit it after the edit window except the admin.
+// Each edit is recorded as a revision of the comment.
 func (cs *CommentService) UpdateComment(ctx context.Context, req *schema.UpdateCommentReq) (err error) {
-	if err := cs.checkCommentWhetherOwner(ctx, req.UserID, req.CommentID); err != nil {
+	oldComment, err := cs.checkCommentWhetherOwner(ctx, req.UserID, req.CommentID)
+	if err != nil {
 		return err
 	}
+	if !req.IsAdmin && !inEditWindow(oldComment, cs.getEditWindow(ctx)) {
+		return errors.BadRequest(reason.CommentEditExpired)
+	}
 	comment := &entity.Comment{}
 	_ = copier.Copy(comment, req)
 	comment.ID = req.CommentID
-	return cs.commentRepo.UpdateComment(ctx, comment)
+	if err = cs.commentRepo.UpdateComment(ctx, comment); err != nil {
+		return err
+	}
+
+	// the comment posted before the revisions are recorded keeps its original content as the first revision
+	exist, err := cs.revisionService.ExistRevisionByObjectID(ctx, oldComment.ID)
+	if err != nil {
+		log.Error(err)
+	} else if !exist {
+		cs.addCommentRevision(ctx, oldComment, oldComment.UserID)
+	}
+	newComment := *oldComment
+	newComment.OriginalText, newComment.ParsedText = req.OriginalText, req.ParsedText
+	newComment.UpdatedAt = time.Now()
+	cs.addCommentRevision(ctx, &newComment, req.UserID)
+	return nil
 }
 
 // GetComment get comment one
@@ -197,15 +259,17 @@ func (cs *CommentService) GetComment(ctx context.Context, req *schema.GetComment
 	}
 
 	resp = &schema.GetCommentResp{
-		CommentID:      comment.ID,
-		CreatedAt:      comment.CreatedAt.Unix(),
-		UserID:         comment.UserID,
-		ReplyUserID:    comment.GetReplyUserID(),
-		ReplyCommentID: comment.GetReplyCommentID(),
-		ObjectID:       comment.ObjectID,
-		VoteCount:      comment.VoteCount,
-		OriginalText:   comment.OriginalText,
-		ParsedText:     comment.ParsedText,
+		CommentID:       comment.ID,
+		CreatedAt:       comment.CreatedAt.Unix(),
+		UserID:          comment.UserID,
+		ReplyUserID:     comment.GetReplyUserID(),
+		ReplyCommentID:  comment.GetReplyCommentID(),
+		ParentCommentID: comment.ParentCommentID,
+		Depth:           comment.Depth,
+		ObjectID:        comment.ObjectID,
+		VoteCount:       comment.VoteCount,
+		OriginalText:    comment.OriginalText,
+		ParsedText:      comment.ParsedText,
 	}
 
 	// get comment user info
@@ -238,30 +302,36 @@ func (cs *CommentService) GetComment(ctx context.Context, req *schema.GetComment
 	// check if current user vote this comment
 	resp.IsVote = cs.checkIsVote(ctx, req.UserID, resp.CommentID)
 
-	resp.MemberActions = permission.GetCommentPermission(ctx, req.UserID, resp.UserID, req.CanEdit, req.CanDelete)
+	resp.MemberActions = getMemberActions(ctx, req.UserID, req.IsAdmin, req.CanEdit, req.CanDelete,
+		comment, cs.getEditWindow(ctx))
 	return resp, nil
 }
 
-// GetCommentWithPage get comment list page
+// GetCommentWithPage get the top level comment list page, the first replies are nested under each comment
 func (cs *CommentService) GetCommentWithPage(ctx context.Context, req *schema.GetCommentWithPageReq) (
 	pageModel *pager.PageModel, err error) {
 	dto := &CommentQuery{
-		PageCond:  pager.PageCond{Page: req.Page, PageSize: req.PageSize},
-		ObjectID:  req.ObjectID,
-		QueryCond: req.QueryCond,
+		PageCond:        pager.PageCond{Page: req.Page, PageSize: req.PageSize},
+		ObjectID:        req.ObjectID,
+		ParentCommentID: "0",
+		QueryCond:       req.QueryCond,
 	}
 	commentList, total, err := cs.commentRepo.GetCommentPage(ctx, dto)
 	if err != nil {
 		return nil, err
 	}
+	editWindow := cs.getEditWindow(ctx)
 	resp := make([]*schema.GetCommentResp, 0)
 	for _, comment := range commentList {
-		commentResp, err := cs.convertCommentEntity2Resp(ctx, req, comment)
+		commentResp, err := cs.convertCommentEntity2Resp(ctx, req, comment, editWindow)
 		if err != nil {
 			return nil, err
 		}
 		resp = append(resp, commentResp)
 	}
+	if err = cs.nestReplies(ctx, req, resp, editWindow); err != nil {
+		return nil, err
+	}
 
 	// if user request the specific comment, add it if not exist.
 	if len(req.CommentID) > 0 {
@@ -278,7 +348,7 @@ func (cs *CommentService) GetCommentWithPage(ctx context.Context, req *schema.Ge
 				return nil, err
 			}
 			if exist && comment.ObjectID == req.ObjectID {
-				commentResp, err := cs.convertCommentEntity2Resp(ctx, req, comment)
+				commentResp, err := cs.convertCommentEntity2Resp(ctx, req, comment, editWindow)
 				if err != nil {
 					return nil, err
 				}
@@ -289,18 +359,92 @@ func (cs *CommentService) GetCommentWithPage(ctx context.Context, req *schema.Ge
 	return pager.NewPageModel(total, resp), nil
 }
 
+// GetCommentReplyPage get the replies nested under the comment page, the oldest first
+func (cs *CommentService) GetCommentReplyPage(ctx context.Context, req *schema.GetCommentReplyPageReq) (
+	pageModel *pager.PageModel, err error) {
+	dto := &CommentQuery{
+		PageCond:        pager.PageCond{Page: req.Page, PageSize: req.PageSize},
+		ParentCommentID: req.CommentID,
+	}
+	commentList, total, err := cs.commentRepo.GetCommentPage(ctx, dto)
+	if err != nil {
+		return nil, err
+	}
+	pageReq := &schema.GetCommentWithPageReq{
+		UserID:    req.UserID,
+		IsAdmin:   req.IsAdmin,
+		CanEdit:   req.CanEdit,
+		CanDelete: req.CanDelete,
+	}
+	editWindow := cs.getEditWindow(ctx)
+	resp := make([]*schema.GetCommentResp, 0)
+	for _, comment := range commentList {
+		commentResp, err := cs.convertCommentEntity2Resp(ctx, pageReq, comment, editWindow)
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, commentResp)
+	}
+	if err = cs.nestReplies(ctx, pageReq, resp, editWindow); err != nil {
+		return nil, err
+	}
+	return pager.NewPageModel(total, resp), nil
+}
+
+// nestReplies nest the replies under the comments level by level, only the first replies of each comment are loaded and nested
+func (cs *CommentService) nestReplies(ctx context.Context, req *schema.GetCommentWithPageReq,
+	comments []*schema.GetCommentResp, editWindow time.Duration) (err error) {
+	for len(comments) > 0 {
+		parentIDs := make([]string, 0, len(comments))
+		for _, comment := range comments {
+			parentIDs = append(parentIDs, comment.CommentID)
+		}
+		replies, err := cs.commentRepo.GetCommentReplies(ctx, parentIDs, constant.CommentReplyPreviewSize)
+		if err != nil {
+			return err
+		}
+		replyCounts, err := cs.commentRepo.CountCommentReplies(ctx, parentIDs)
+		if err != nil {
+			return err
+		}
+		repliesMapping := make(map[string][]*entity.Comment)
+		for _, reply := range replies {
+			repliesMapping[reply.ParentCommentID] = append(repliesMapping[reply.ParentCommentID], reply)
+		}
+
+		nested := make([]*schema.GetCommentResp, 0)
+		for _, comment := range comments {
+			replies := repliesMapping[comment.CommentID]
+			comment.ReplyCount = replyCounts[comment.CommentID]
+			comment.Replies = make([]*schema.GetCommentResp, 0, len(replies))
+			for _, reply := range replies {
+				replyResp, err := cs.convertCommentEntity2Resp(ctx, req, reply, editWindow)
+				if err != nil {
+					return err
+				}
+				comment.Replies = append(comment.Replies, replyResp)
+			}
+			nested = append(nested, comment.Replies...)
+		}
+		comments = nested
+	}
+	return nil
+}
+
 func (cs *CommentService) convertCommentEntity2Resp(ctx context.Context, req *schema.GetCommentWithPageReq,
-	comment *entity.Comment) (commentResp *schema.GetCommentResp, err error) {
+	comment *entity.Comment, editWindow time.Duration) (commentResp *schema.GetCommentResp, err error) {
 	commentResp = &schema.GetCommentResp{
-		CommentID:      comment.ID,
-		CreatedAt:      comment.CreatedAt.Unix(),
-		UserID:         comment.UserID,
-		ReplyUserID:    comment.GetReplyUserID(),
-		ReplyCommentID: comment.GetReplyCommentID(),
-		ObjectID:       comment.ObjectID,
-		VoteCount:      comment.VoteCount,
-		OriginalText:   comment.OriginalText,
-		ParsedText:     comment.ParsedText,
+		CommentID:       comment.ID,
+		CreatedAt:       comment.CreatedAt.Unix(),
+		UserID:          comment.UserID,
+		ReplyUserID:     comment.GetReplyUserID(),
+		ReplyCommentID:  comment.GetReplyCommentID(),
+		ParentCommentID: comment.ParentCommentID,
+		Depth:           comment.Depth,
+		ObjectID:        comment.ObjectID,
+		VoteCount:       comment.VoteCount,
+		OriginalText:    comment.OriginalText,
+		ParsedText:      comment.ParsedText,
 	}
 
 	// get comment user info
@@ -333,24 +477,69 @@ func (cs *CommentService) convertCommentEntity2Resp(ctx context.Context, req *sc
 	// check if current user vote this comment
 	commentResp.IsVote = cs.checkIsVote(ctx, req.UserID, commentResp.CommentID)
 
-	commentResp.MemberActions = permission.GetCommentPermission(ctx,
-		req.UserID, commentResp.UserID, req.CanEdit, req.CanDelete)
+	commentResp.MemberActions = getMemberActions(ctx, req.UserID, req.IsAdmin, req.CanEdit, req.CanDelete,
+		comment, editWindow)
 	return commentResp, nil
 }
 
-func (cs *CommentService) checkCommentWhetherOwner(ctx context.Context, userID, commentID string) error {
+// getEditWindow the author can edit the comment in the window after posting it, 0 means always
+func (cs *CommentService) getEditWindow(ctx context.Context) time.Duration {
+	setting, err := cs.siteInfoService.GetSiteComment(ctx)
+	if err != nil {
+		log.Error(err)
+		return 0
+	}
+	return time.Duration(setting.EditWindowMinutes) * time.Minute
+}
+
+// addCommentRevision record the content of the comment as a revision, the failure does not stop the edit
+func (cs *CommentService) addCommentRevision(ctx context.Context, comment *entity.Comment, userID string) {
+	content, _ := json.Marshal(comment)
+	_, err := cs.revisionService.AddRevision(ctx, &schema.AddRevisionDTO{
+		UserID:   userID,
+		ObjectID: comment.ID,
+		Content:  string(content),
+	}, false)
+	if err != nil {
+		log.Error(err)
+	}
+}
+
+// inEditWindow whether the comment is still in the edit window
+func inEditWindow(comment *entity.Comment, editWindow time.Duration) bool {
+	return editWindow <= 0 || time.Since(comment.CreatedAt) <= editWindow
+}
+
+// getMemberActions the edit action of the comment is removed after the edit window except for the admin
+func getMemberActions(ctx context.Context, userID string, isAdmin, canEdit, canDelete bool,
+	comment *entity.Comment, editWindow time.Duration) (actions []*schema.PermissionMemberAction) {
+	actions = permission.GetCommentPermission(ctx, userID, comment.UserID, canEdit, canDelete)
+	if isAdmin || inEditWindow(comment, editWindow) {
+		return actions
+	}
+	available := make([]*schema.PermissionMemberAction, 0, len(actions))
+	for _, action := range actions {
+		if action.Action != "edit" {
+			available = append(available, action)
+		}
+	}
+	return available
+}
+
+func (cs *CommentService) checkCommentWhetherOwner(ctx context.Context, userID, commentID string) (
+	comment *entity.Comment, err error) {
 	// check comment if user self
 	comment, exist, err := cs.commentCommonRepo.GetComment(ctx, commentID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if !exist {
-		return errors.BadRequest(reason.CommentNotFound)
+		return nil, errors.BadRequest(reason.CommentNotFound)
 	}
 	if comment.UserID != userID {
-		return errors.BadRequest(reason.CommentEditWithoutPermission)
+		return nil, errors.BadRequest(reason.CommentEditWithoutPermission)
 	}
-	return nil
+	return comment, nil
 }
 
 func (cs *CommentService) checkIsVote(ctx context.Context, userID, commentID string) (isVote bool) {
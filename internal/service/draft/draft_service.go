@@ -0,0 +1,121 @@
+package draft
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"answer/internal/base/constant"
+	"answer/internal/base/pager"
+	"answer/internal/base/scheduler"
+	"answer/internal/entity"
+	"answer/internal/schema"
+
+	"github.com/segmentfault/pacman/log"
+)
+
+// DraftRepo draft repository
+type DraftRepo interface {
+	SaveDraft(ctx context.Context, draft *entity.Draft) (err error)
+	GetDraft(ctx context.Context, userID, draftType, objectID string) (draft *entity.Draft, exist bool, err error)
+	RemoveDraft(ctx context.Context, userID, draftType, objectID string) (err error)
+	GetDraftPage(ctx context.Context, userID string, page, pageSize int) (
+		drafts []*entity.Draft, total int64, err error)
+	RemoveExpiredDrafts(ctx context.Context, before time.Time) (count int64, err error)
+}
+
+// DraftService draft service
+type DraftService struct {
+	draftRepo DraftRepo
+}
+
+// NewDraftService new draft service
+func NewDraftService(draftRepo DraftRepo, scheduler *scheduler.Scheduler) *DraftService {
+	ds := &DraftService{
+		draftRepo: draftRepo,
+	}
+	scheduler.AddJob("draft_expiry", constant.DraftCleanInterval, false, ds.RemoveExpiredDrafts)
+	return ds
+}
+
+// SaveDraft autosave the draft of the context
+func (ds *DraftService) SaveDraft(ctx context.Context, req *schema.SaveDraftReq) (
+	resp *schema.SaveDraftResp, err error) {
+	if req.Tags == nil {
+		req.Tags = make([]string, 0)
+	}
+	tags, _ := json.Marshal(req.Tags)
+	draft := &entity.Draft{
+		UserID:    req.UserID,
+		DraftType: req.DraftType,
+		ObjectID:  draftObjectID(req.DraftType, req.ObjectID),
+		Title:     req.Title,
+		Content:   req.Content,
+		Tags:      string(tags),
+	}
+	if err = ds.draftRepo.SaveDraft(ctx, draft); err != nil {
+		return nil, err
+	}
+	return &schema.SaveDraftResp{ID: draft.ID, UpdatedAt: draft.UpdatedAt.Unix()}, nil
+}
+
+// GetDraft get the draft of the context, nil if there is no draft
+func (ds *DraftService) GetDraft(ctx context.Context, req *schema.GetDraftReq) (resp *schema.DraftResp, err error) {
+	draft, exist, err := ds.draftRepo.GetDraft(ctx, req.UserID, req.DraftType,
+		draftObjectID(req.DraftType, req.ObjectID))
+	if err != nil || !exist {
+		return nil, err
+	}
+	resp = &schema.DraftResp{}
+	resp.GetFromEntity(draft)
+	return resp, nil
+}
+
+// RemoveDraft discard the draft of the context by user
+func (ds *DraftService) RemoveDraft(ctx context.Context, req *schema.RemoveDraftReq) (err error) {
+	return ds.draftRepo.RemoveDraft(ctx, req.UserID, req.DraftType, draftObjectID(req.DraftType, req.ObjectID))
+}
+
+// RemoveSubmittedDraft remove the draft after the post is submitted, the failure does not affect the post
+func (ds *DraftService) RemoveSubmittedDraft(ctx context.Context, userID, draftType, objectID string) {
+	err := ds.draftRepo.RemoveDraft(ctx, userID, draftType, draftObjectID(draftType, objectID))
+	if err != nil {
+		log.Error(err)
+	}
+}
+
+// GetDraftPage get the drafts of the login user
+func (ds *DraftService) GetDraftPage(ctx context.Context, req *schema.GetDraftPageReq) (
+	pageModel *pager.PageModel, err error) {
+	drafts, total, err := ds.draftRepo.GetDraftPage(ctx, req.UserID, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*schema.DraftResp, 0, len(drafts))
+	for _, draft := range drafts {
+		item := &schema.DraftResp{}
+		item.GetFromEntity(draft)
+		list = append(list, item)
+	}
+	return pager.NewPageModel(total, list), nil
+}
+
+// RemoveExpiredDrafts remove the drafts which are not saved for a long time
+func (ds *DraftService) RemoveExpiredDrafts(ctx context.Context) {
+	count, err := ds.draftRepo.RemoveExpiredDrafts(ctx, time.Now().Add(-constant.DraftExpiration))
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if count > 0 {
+		log.Infof("removed %d expired drafts", count)
+	}
+}
+
+// draftObjectID the new question has no object
+func draftObjectID(draftType, objectID string) string {
+	if draftType == entity.DraftTypeQuestion {
+		return "0"
+	}
+	return objectID
+}
@@ -60,6 +60,13 @@ type EmailConfig struct {
 	ChangeBody     string `json:"change_body"`
 	TestTitle      string `json:"test_title"`
 	TestBody       string `json:"test_body"`
+
+	ModeratorMessageTitle string `json:"moderator_message_title"`
+	ModeratorMessageBody  string `json:"moderator_message_body"`
+	FeedDigestTitle       string `json:"feed_digest_title"`
+	FeedDigestBody        string `json:"feed_digest_body"`
+	TagAlertTitle         string `json:"tag_alert_title"`
+	TagAlertBody          string `json:"tag_alert_body"`
 }
 
 func (e *EmailConfig) IsSSL() bool {
@@ -85,6 +92,62 @@ type TestTemplateData struct {
 	SiteName string
 }
 
+type ModeratorMessageTemplateData struct {
+	SiteName   string
+	Content    string
+	MessageUrl string
+}
+
+type FeedDigestTemplateData struct {
+	SiteName string
+	FeedUrl  string
+	Items    []*FeedDigestTemplateItem
+}
+
+type FeedDigestTemplateItem struct {
+	Title   string
+	Url     string
+	Excerpt string
+}
+
+type TagAlertTemplateData struct {
+	SiteName   string
+	SettingUrl string
+	Items      []*TagAlertTemplateItem
+}
+
+type TagAlertTemplateItem struct {
+	Title      string
+	Url        string
+	Tags       string
+	Expression string
+	Excerpt    string
+}
+
+// the moderator message templates are used if they are not configured in the email config
+const (
+	defaultModeratorMessageTitle = "[{{.SiteName}}] You have a message from the moderators"
+	defaultModeratorMessageBody  = "<p style=\"white-space: pre-wrap;\">{{.Content}}</p>" +
+		"<p>View and reply to the message at <a href='{{.MessageUrl}}' target='_blank'>{{.MessageUrl}}</a></p>"
+)
+
+// the feed digest templates are used if they are not configured in the email config
+const (
+	defaultFeedDigestTitle = "[{{.SiteName}}] Your weekly feed"
+	defaultFeedDigestBody  = "<p>Here is what happened this week in the tags, questions and users you follow:</p><ul>" +
+		"{{range .Items}}<li><a href='{{.Url}}' target='_blank'>{{.Title}}</a><br>{{.Excerpt}}</li>{{end}}</ul>" +
+		"<p>See your full feed at <a href='{{.FeedUrl}}' target='_blank'>{{.FeedUrl}}</a></p>"
+)
+
+// the tag alert templates are used if they are not configured in the email config
+const (
+	defaultTagAlertTitle = "[{{.SiteName}}] New questions in your watched tags"
+	defaultTagAlertBody  = "<p>New questions are posted in the tags you watch:</p><ul>" +
+		"{{range .Items}}<li><a href='{{.Url}}' target='_blank'>{{.Title}}</a><br>{{.Tags}} (watching {{.Expression}})" +
+		"<br>{{.Excerpt}}</li>{{end}}</ul>" +
+		"<p>Manage your watched tags and quiet hours at <a href='{{.SettingUrl}}' target='_blank'>{{.SettingUrl}}</a></p>"
+)
+
 // Send email send
 func (es *EmailService) Send(ctx context.Context, toEmailAddr, subject, body, code, codeContent string) {
 	log.Infof("try to send email to %s", toEmailAddr)
@@ -285,6 +348,138 @@ func (es *EmailService) TestTemplate(ctx context.Context) (title, body string, e
 	return titleBuf.String(), bodyBuf.String(), nil
 }
 
+func (es *EmailService) ModeratorMessageTemplate(ctx context.Context, content, messageUrl string) (
+	title, body string, err error) {
+	ec, err := es.GetEmailConfig()
+	if err != nil {
+		return
+	}
+	if len(ec.ModeratorMessageTitle) == 0 {
+		ec.ModeratorMessageTitle = defaultModeratorMessageTitle
+	}
+	if len(ec.ModeratorMessageBody) == 0 {
+		ec.ModeratorMessageBody = defaultModeratorMessageBody
+	}
+
+	siteinfo, err := es.GetSiteGeneral(ctx)
+	if err != nil {
+		return
+	}
+	templateData := ModeratorMessageTemplateData{
+		SiteName:   siteinfo.Name,
+		Content:    content,
+		MessageUrl: messageUrl,
+	}
+	tmpl, err := template.New("moderator_message_title").Parse(ec.ModeratorMessageTitle)
+	if err != nil {
+		return "", "", err
+	}
+	titleBuf := &bytes.Buffer{}
+	bodyBuf := &bytes.Buffer{}
+	err = tmpl.Execute(titleBuf, templateData)
+	if err != nil {
+		return "", "", err
+	}
+
+	tmpl, err = template.New("moderator_message_body").Parse(ec.ModeratorMessageBody)
+	if err != nil {
+		return "", "", err
+	}
+	err = tmpl.Execute(bodyBuf, templateData)
+	if err != nil {
+		return "", "", err
+	}
+	return titleBuf.String(), bodyBuf.String(), nil
+}
+
+func (es *EmailService) FeedDigestTemplate(ctx context.Context, feedUrl string, items []*FeedDigestTemplateItem) (
+	title, body string, err error) {
+	ec, err := es.GetEmailConfig()
+	if err != nil {
+		return
+	}
+	if len(ec.FeedDigestTitle) == 0 {
+		ec.FeedDigestTitle = defaultFeedDigestTitle
+	}
+	if len(ec.FeedDigestBody) == 0 {
+		ec.FeedDigestBody = defaultFeedDigestBody
+	}
+
+	siteinfo, err := es.GetSiteGeneral(ctx)
+	if err != nil {
+		return
+	}
+	templateData := FeedDigestTemplateData{
+		SiteName: siteinfo.Name,
+		FeedUrl:  feedUrl,
+		Items:    items,
+	}
+	tmpl, err := template.New("feed_digest_title").Parse(ec.FeedDigestTitle)
+	if err != nil {
+		return "", "", err
+	}
+	titleBuf := &bytes.Buffer{}
+	bodyBuf := &bytes.Buffer{}
+	err = tmpl.Execute(titleBuf, templateData)
+	if err != nil {
+		return "", "", err
+	}
+
+	tmpl, err = template.New("feed_digest_body").Parse(ec.FeedDigestBody)
+	if err != nil {
+		return "", "", err
+	}
+	err = tmpl.Execute(bodyBuf, templateData)
+	if err != nil {
+		return "", "", err
+	}
+	return titleBuf.String(), bodyBuf.String(), nil
+}
+
+func (es *EmailService) TagAlertTemplate(ctx context.Context, settingUrl string, items []*TagAlertTemplateItem) (
+	title, body string, err error) {
+	ec, err := es.GetEmailConfig()
+	if err != nil {
+		return
+	}
+	if len(ec.TagAlertTitle) == 0 {
+		ec.TagAlertTitle = defaultTagAlertTitle
+	}
+	if len(ec.TagAlertBody) == 0 {
+		ec.TagAlertBody = defaultTagAlertBody
+	}
+
+	siteinfo, err := es.GetSiteGeneral(ctx)
+	if err != nil {
+		return
+	}
+	templateData := TagAlertTemplateData{
+		SiteName:   siteinfo.Name,
+		SettingUrl: settingUrl,
+		Items:      items,
+	}
+	tmpl, err := template.New("tag_alert_title").Parse(ec.TagAlertTitle)
+	if err != nil {
+		return "", "", err
+	}
+	titleBuf := &bytes.Buffer{}
+	bodyBuf := &bytes.Buffer{}
+	err = tmpl.Execute(titleBuf, templateData)
+	if err != nil {
+		return "", "", err
+	}
+
+	tmpl, err = template.New("tag_alert_body").Parse(ec.TagAlertBody)
+	if err != nil {
+		return "", "", err
+	}
+	err = tmpl.Execute(bodyBuf, templateData)
+	if err != nil {
+		return "", "", err
+	}
+	return titleBuf.String(), bodyBuf.String(), nil
+}
+
 func (es *EmailService) GetEmailConfig() (ec *EmailConfig, err error) {
 	emailConf, err := es.configRepo.GetString("email.config")
 	if err != nil {
@@ -0,0 +1,332 @@
+package feed
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"answer/internal/base/constant"
+	"answer/internal/base/scheduler"
+	"answer/internal/entity"
+	"answer/internal/schema"
+	"answer/internal/service/activity_common"
+	answercommon "answer/internal/service/answer_common"
+	"answer/internal/service/export"
+	questioncommon "answer/internal/service/question_common"
+	usercommon "answer/internal/service/user_common"
+	"answer/pkg/htmltext"
+
+	"github.com/segmentfault/pacman/log"
+)
+
+// feedDigestBatchSize the number of the users whose digests are generated in one batch
+const feedDigestBatchSize = 100
+
+// FeedRepo feed repository
+type FeedRepo interface {
+	GetFeedActivities(ctx context.Context, search *entity.FeedSearch) (activities []*entity.Activity, err error)
+	GetFollowerIDs(ctx context.Context, afterUserID string, limit int) (userIDs []string, err error)
+	AddFeedDigest(ctx context.Context, digest *entity.FeedDigest) (added bool, err error)
+}
+
+// FeedService feed service
+type FeedService struct {
+	feedRepo     FeedRepo
+	followRepo   activity_common.FollowRepo
+	activityRepo activity_common.ActivityRepo
+	questionRepo questioncommon.QuestionRepo
+	answerRepo   answercommon.AnswerRepo
+	userRepo     usercommon.UserRepo
+	userCommon   *usercommon.UserCommon
+	emailService *export.EmailService
+	// lastDigestPeriod the period whose digests have been generated by this instance
+	lastDigestPeriod string
+}
+
+// NewFeedService new feed service
+func NewFeedService(
+	feedRepo FeedRepo,
+	followRepo activity_common.FollowRepo,
+	activityRepo activity_common.ActivityRepo,
+	questionRepo questioncommon.QuestionRepo,
+	answerRepo answercommon.AnswerRepo,
+	userRepo usercommon.UserRepo,
+	userCommon *usercommon.UserCommon,
+	emailService *export.EmailService,
+	scheduler *scheduler.Scheduler,
+) *FeedService {
+	fs := &FeedService{
+		feedRepo:     feedRepo,
+		followRepo:   followRepo,
+		activityRepo: activityRepo,
+		questionRepo: questionRepo,
+		answerRepo:   answerRepo,
+		userRepo:     userRepo,
+		userCommon:   userCommon,
+		emailService: emailService,
+	}
+	scheduler.AddJob("feed_digest", constant.FeedDigestCheckInterval, false, fs.SendFeedDigests)
+	return fs
+}
+
+// GetFeed get the following feed of the user, the newest first.
+// The items which are not visible are skipped, so a page may have fewer items than the page size.
+func (fs *FeedService) GetFeed(ctx context.Context, req *schema.GetFeedReq) (resp *schema.GetFeedResp, err error) {
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = constant.DefaultPageSize
+	}
+	if pageSize > constant.FeedMaxPageSize {
+		pageSize = constant.FeedMaxPageSize
+	}
+	search, err := fs.buildSearch(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	search.Cursor = req.Cursor
+	search.Limit = pageSize + 1
+	activities, err := fs.feedRepo.GetFeedActivities(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &schema.GetFeedResp{}
+	if len(activities) > pageSize {
+		activities = activities[:pageSize]
+		resp.NextCursor = activities[pageSize-1].ID
+	}
+	resp.List, err = fs.formatFeed(ctx, search, activities)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetFeedDigest get the digest of the following feed in the last period
+func (fs *FeedService) GetFeedDigest(ctx context.Context, userID string) (resp *schema.GetFeedDigestResp, err error) {
+	now := time.Now()
+	search, err := fs.buildSearch(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	search.Since = now.Add(-constant.FeedDigestPeriod)
+	search.Limit = constant.FeedDigestMaxItems
+	activities, err := fs.feedRepo.GetFeedActivities(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+	resp = &schema.GetFeedDigestResp{
+		StartAt: search.Since.Unix(),
+		EndAt:   now.Unix(),
+	}
+	resp.List, err = fs.formatFeed(ctx, search, activities)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// SendFeedDigests send the digest of the current period to the users who follow anything.
+// Only the users who turn on the email notification get the digest, and each of them gets it once a period.
+func (fs *FeedService) SendFeedDigests(ctx context.Context) {
+	period := feedDigestPeriod(time.Now())
+	if fs.lastDigestPeriod == period {
+		return
+	}
+	siteGeneral, err := fs.emailService.GetSiteGeneral(ctx)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	feedURL := fmt.Sprintf("%s/users/following", siteGeneral.SiteUrl)
+
+	afterUserID := "0"
+	for {
+		userIDs, err := fs.feedRepo.GetFollowerIDs(ctx, afterUserID, feedDigestBatchSize)
+		if err != nil {
+			log.Error(err)
+			return
+		}
+		for _, userID := range userIDs {
+			fs.sendFeedDigest(ctx, userID, period, siteGeneral.SiteUrl, feedURL)
+		}
+		if len(userIDs) < feedDigestBatchSize {
+			break
+		}
+		afterUserID = userIDs[len(userIDs)-1]
+	}
+	fs.lastDigestPeriod = period
+	log.Infof("the feed digests of period %s are sent", period)
+}
+
+func (fs *FeedService) sendFeedDigest(ctx context.Context, userID, period, siteURL, feedURL string) {
+	userInfo, exist, err := fs.userRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if !exist || userInfo.Status != entity.UserStatusAvailable ||
+		userInfo.MailStatus != entity.EmailStatusAvailable || userInfo.NoticeStatus != schema.NoticeStatusOn {
+		return
+	}
+	digest, err := fs.GetFeedDigest(ctx, userID)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if len(digest.List) == 0 {
+		return
+	}
+	added, err := fs.feedRepo.AddFeedDigest(ctx, &entity.FeedDigest{
+		UserID:    userID,
+		Period:    period,
+		ItemCount: len(digest.List),
+	})
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if !added {
+		return
+	}
+
+	items := make([]*export.FeedDigestTemplateItem, 0, len(digest.List))
+	for _, item := range digest.List {
+		itemURL := fmt.Sprintf("%s/questions/%s", siteURL, item.QuestionID)
+		if len(item.AnswerID) > 0 {
+			itemURL = fmt.Sprintf("%s/%s", itemURL, item.AnswerID)
+		}
+		items = append(items, &export.FeedDigestTemplateItem{
+			Title:   item.Title,
+			Url:     itemURL,
+			Excerpt: item.Excerpt,
+		})
+	}
+	title, body, err := fs.emailService.FeedDigestTemplate(ctx, feedURL, items)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	fs.emailService.Send(ctx, userInfo.EMail, title, body, "", "")
+}
+
+// buildSearch the followed objects of the user
+func (fs *FeedService) buildSearch(ctx context.Context, userID string) (search *entity.FeedSearch, err error) {
+	search = &entity.FeedSearch{UserID: userID}
+	search.FollowedUserIDs, err = fs.followRepo.GetFollowIDs(ctx, userID, constant.UserObjectType)
+	if err != nil {
+		return nil, err
+	}
+	search.FollowedTagIDs, err = fs.followRepo.GetFollowIDs(ctx, userID, constant.TagObjectType)
+	if err != nil {
+		return nil, err
+	}
+	search.FollowedQuestionIDs, err = fs.followRepo.GetFollowIDs(ctx, userID, constant.QuestionObjectType)
+	if err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+// formatFeed the activities about the deleted or pending posts are skipped
+func (fs *FeedService) formatFeed(ctx context.Context, search *entity.FeedSearch, activities []*entity.Activity) (
+	list []*schema.FeedItem, err error) {
+	activityTypes := make(map[int]constant.ActivityTypeKey)
+	for _, key := range []constant.ActivityTypeKey{constant.ActQuestionAsked, constant.ActQuestionAnswered,
+		constant.ActQuestionEdited, constant.ActAnswerEdited} {
+		activityType, err := fs.activityRepo.GetActivityTypeByConfigKey(ctx, string(key))
+		if err != nil {
+			return nil, err
+		}
+		activityTypes[activityType] = key
+	}
+	followedUsers := make(map[string]bool, len(search.FollowedUserIDs))
+	for _, id := range search.FollowedUserIDs {
+		followedUsers[id] = true
+	}
+
+	items := make([]*schema.FeedItem, 0, len(activities))
+	answers := make(map[string]*entity.Answer)
+	questionIDs := make([]string, 0, len(activities))
+	userIDs := make([]string, 0, len(activities))
+	for _, act := range activities {
+		item := &schema.FeedItem{
+			ID:        act.ID,
+			CreatedAt: act.CreatedAt.Unix(),
+		}
+		switch activityTypes[act.ActivityType] {
+		case constant.ActQuestionAsked:
+			item.Type, item.QuestionID = schema.FeedTypeNewQuestion, act.ObjectID
+		case constant.ActQuestionEdited:
+			item.Type, item.QuestionID = schema.FeedTypeQuestionEdited, act.ObjectID
+		case constant.ActQuestionAnswered:
+			item.Type, item.AnswerID = schema.FeedTypeNewAnswer, act.ObjectID
+		case constant.ActAnswerEdited:
+			item.Type, item.AnswerID = schema.FeedTypeAnswerEdited, act.ObjectID
+		default:
+			continue
+		}
+		if len(item.AnswerID) > 0 {
+			answer, ok := answers[item.AnswerID]
+			if !ok {
+				answer, _, err = fs.answerRepo.GetByID(ctx, item.AnswerID)
+				if err != nil {
+					return nil, err
+				}
+				answers[item.AnswerID] = answer
+			}
+			if answer.Status != entity.AnswerStatusAvailable {
+				continue
+			}
+			item.QuestionID = answer.QuestionID
+			item.Excerpt = htmltext.FetchExcerpt(answer.ParsedText, "...", 120)
+		}
+
+		switch {
+		case followedUsers[act.UserID]:
+			item.Reason = schema.FeedReasonFollowedUser
+		case item.Type == schema.FeedTypeNewQuestion:
+			item.Reason = schema.FeedReasonFollowedTag
+		default:
+			item.Reason = schema.FeedReasonFollowedQuestion
+		}
+		items = append(items, item)
+		questionIDs = append(questionIDs, item.QuestionID)
+		userIDs = append(userIDs, act.UserID)
+	}
+
+	questionList, err := fs.questionRepo.FindByID(ctx, questionIDs)
+	if err != nil {
+		return nil, err
+	}
+	questions := make(map[string]*entity.Question, len(questionList))
+	for _, question := range questionList {
+		questions[question.ID] = question
+	}
+	userInfoMapping, err := fs.userCommon.BatchUserBasicInfoByID(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	list = make([]*schema.FeedItem, 0, len(items))
+	for i, item := range items {
+		question, ok := questions[item.QuestionID]
+		if !ok || (question.Status != entity.QuestionStatusAvailable && question.Status != entity.QuestionStatusClosed) {
+			continue
+		}
+		item.Title = question.Title
+		if len(item.AnswerID) == 0 {
+			item.Excerpt = htmltext.FetchExcerpt(question.ParsedText, "...", 120)
+		}
+		item.UserInfo = userInfoMapping[userIDs[i]]
+		list = append(list, item)
+	}
+	return list, nil
+}
+
+// feedDigestPeriod the start date of the week of the time, the week starts on Monday
+func feedDigestPeriod(t time.Time) string {
+	t = t.UTC()
+	offset := (int(t.Weekday()) + 6) % 7
+	return t.AddDate(0, 0, -offset).Format("2006-01-02")
+}
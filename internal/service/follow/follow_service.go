@@ -3,32 +3,45 @@ package follow
 import (
 	"context"
 
+	"answer/internal/base/constant"
+	"answer/internal/base/reason"
 	"answer/internal/entity"
 	"answer/internal/schema"
 	"answer/internal/service/activity_common"
 	tagcommon "answer/internal/service/tag_common"
+	usercommon "answer/internal/service/user_common"
+
+	"github.com/segmentfault/pacman/errors"
 )
 
 type FollowRepo interface {
 	Follow(ctx context.Context, objectId, userId string) error
 	FollowCancel(ctx context.Context, objectId, userId string) error
+	FollowUser(ctx context.Context, followedUserID, userID string) error
+	FollowUserCancel(ctx context.Context, followedUserID, userID string) error
 }
 
 type FollowService struct {
 	tagRepo          tagcommon.TagCommonRepo
 	followRepo       FollowRepo
 	followCommonRepo activity_common.FollowRepo
+	userRepo         usercommon.UserRepo
+	userCommon       *usercommon.UserCommon
 }
 
 func NewFollowService(
 	followRepo FollowRepo,
 	followCommonRepo activity_common.FollowRepo,
 	tagRepo tagcommon.TagCommonRepo,
+	userRepo usercommon.UserRepo,
+	userCommon *usercommon.UserCommon,
 ) *FollowService {
 	return &FollowService{
 		followRepo:       followRepo,
 		followCommonRepo: followCommonRepo,
 		tagRepo:          tagRepo,
+		userRepo:         userRepo,
+		userCommon:       userCommon,
 	}
 }
 
@@ -52,6 +65,57 @@ func (fs *FollowService) Follow(ctx context.Context, dto *schema.FollowDTO) (res
 	return resp, nil
 }
 
+// FollowUser follow or cancel following the user, the user can not follow itself
+func (fs *FollowService) FollowUser(ctx context.Context, req *schema.FollowUserReq) (resp schema.FollowResp, err error) {
+	userInfo, exist, err := fs.userRepo.GetByUsername(ctx, req.Username)
+	if err != nil {
+		return resp, err
+	}
+	if !exist || userInfo.Status == entity.UserStatusDeleted {
+		return resp, errors.BadRequest(reason.UserNotFound)
+	}
+	if userInfo.ID == req.UserID {
+		return resp, errors.BadRequest(reason.DisallowFollow)
+	}
+
+	if req.IsCancel {
+		err = fs.followRepo.FollowUserCancel(ctx, userInfo.ID, req.UserID)
+	} else {
+		err = fs.followRepo.FollowUser(ctx, userInfo.ID, req.UserID)
+	}
+	if err != nil {
+		return resp, err
+	}
+	userInfo, _, err = fs.userRepo.GetByUserID(ctx, userInfo.ID)
+	if err != nil {
+		return resp, err
+	}
+
+	resp.Follows = userInfo.FollowCount
+	resp.IsFollowed = !req.IsCancel
+	return resp, nil
+}
+
+// GetFollowingUsers get the users followed by the user
+func (fs *FollowService) GetFollowingUsers(ctx context.Context, req *schema.GetFollowingUsersReq) (
+	resp []*schema.UserBasicInfo, err error) {
+	userIDs, err := fs.followCommonRepo.GetFollowIDs(ctx, req.UserID, constant.UserObjectType)
+	if err != nil {
+		return nil, err
+	}
+	userInfoMapping, err := fs.userCommon.BatchUserBasicInfoByID(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	resp = make([]*schema.UserBasicInfo, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if userInfo, ok := userInfoMapping[userID]; ok {
+			resp = append(resp, userInfo)
+		}
+	}
+	return resp, nil
+}
+
 // UpdateFollowTags update user follow tags
 func (fs *FollowService) UpdateFollowTags(ctx context.Context, req *schema.UpdateFollowTagsReq) (err error) {
 	objIDs, err := fs.followCommonRepo.GetFollowIDs(ctx, req.UserID, entity.Tag{}.TableName())
@@ -0,0 +1,155 @@
+package mention
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"answer/internal/base/constant"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/schema"
+	"answer/internal/service/notice_queue"
+	usercommon "answer/internal/service/user_common"
+	"answer/pkg/mention"
+
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// MentionRepo mention repository
+type MentionRepo interface {
+	GetThreadParticipants(ctx context.Context, questionID string) (participants map[string]int, err error)
+	SearchUsers(ctx context.Context, keyword string, limit int) (users []*entity.User, err error)
+}
+
+// MentionService mention service
+type MentionService struct {
+	mentionRepo MentionRepo
+	userRepo    usercommon.UserRepo
+	userCommon  *usercommon.UserCommon
+}
+
+// NewMentionService new mention service
+func NewMentionService(
+	mentionRepo MentionRepo,
+	userRepo usercommon.UserRepo,
+	userCommon *usercommon.UserCommon,
+) *MentionService {
+	return &MentionService{
+		mentionRepo: mentionRepo,
+		userRepo:    userRepo,
+		userCommon:  userCommon,
+	}
+}
+
+// Resolve find the users mentioned in the markdown content and render the mentions in the html as profile links.
+// The users who are mentioned in the previous content are not returned, so the edit does not notify them again.
+func (ms *MentionService) Resolve(ctx context.Context, content, html, previousContent string) (
+	renderedHTML string, userIDs []string, err error) {
+	usernames := mention.Parse(content)
+	if len(usernames) > constant.MentionMaxPerPost {
+		return "", nil, errors.BadRequest(reason.MentionTooMany)
+	}
+	if len(usernames) == 0 {
+		return html, nil, nil
+	}
+	previous := make(map[string]bool)
+	for _, username := range mention.Parse(previousContent) {
+		previous[username] = true
+	}
+
+	mentioned := make(map[string]bool, len(usernames))
+	userIDs = make([]string, 0, len(usernames))
+	for _, username := range usernames {
+		userInfo, exist, err := ms.userRepo.GetByUsername(ctx, username)
+		if err != nil {
+			return "", nil, err
+		}
+		if !exist || userInfo.Status != entity.UserStatusAvailable {
+			continue
+		}
+		mentioned[username] = true
+		if !previous[username] {
+			userIDs = append(userIDs, userInfo.ID)
+		}
+	}
+	return mention.Render(html, mentioned), userIDs, nil
+}
+
+// Notify notify the mentioned users, the user who mentions itself is not notified
+func (ms *MentionService) Notify(userIDs []string, triggerUserID, objectID, objectType string) {
+	for _, userID := range userIDs {
+		if userID == triggerUserID {
+			continue
+		}
+		notice_queue.AddNotification(&schema.NotificationMsg{
+			ReceiverUserID:     userID,
+			TriggerUserID:      triggerUserID,
+			Type:               schema.NotificationTypeInbox,
+			ObjectID:           objectID,
+			ObjectType:         objectType,
+			NotificationAction: constant.MentionYou,
+		})
+	}
+}
+
+// SearchMentionUsers suggest the users to mention by the prefix of the username or display name.
+// The participants of the question are ranked by the number of their posts in the thread, then the others by rank.
+func (ms *MentionService) SearchMentionUsers(ctx context.Context, req *schema.SearchMentionUsersReq) (
+	resp []*schema.UserBasicInfo, err error) {
+	keyword := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(req.Keyword), "@"))
+	candidates := make([]*entity.User, 0)
+
+	if len(req.QuestionID) > 0 {
+		participants, err := ms.mentionRepo.GetThreadParticipants(ctx, req.QuestionID)
+		if err != nil {
+			return nil, err
+		}
+		userIDs := make([]string, 0, len(participants))
+		for userID := range participants {
+			userIDs = append(userIDs, userID)
+		}
+		users, err := ms.userRepo.BatchGetByID(ctx, userIDs)
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(users, func(i, j int) bool {
+			if participants[users[i].ID] != participants[users[j].ID] {
+				return participants[users[i].ID] > participants[users[j].ID]
+			}
+			return users[i].Rank > users[j].Rank
+		})
+		for _, user := range users {
+			if user.Status == entity.UserStatusAvailable && matchUser(user, keyword) {
+				candidates = append(candidates, user)
+			}
+		}
+	}
+	if len(candidates) < constant.MentionSearchLimit {
+		users, err := ms.mentionRepo.SearchUsers(ctx, keyword, constant.MentionSearchLimit+1)
+		if err != nil {
+			log.Error(err)
+		}
+		candidates = append(candidates, users...)
+	}
+
+	resp = make([]*schema.UserBasicInfo, 0, constant.MentionSearchLimit)
+	added := make(map[string]bool)
+	for _, user := range candidates {
+		if user.ID == req.UserID || added[user.ID] {
+			continue
+		}
+		added[user.ID] = true
+		resp = append(resp, ms.userCommon.FormatUserBasicInfo(ctx, user))
+		if len(resp) == constant.MentionSearchLimit {
+			break
+		}
+	}
+	return resp, nil
+}
+
+func matchUser(user *entity.User, keyword string) bool {
+	return strings.HasPrefix(strings.ToLower(user.Username), keyword) ||
+		strings.HasPrefix(strings.ToLower(user.DisplayName), keyword)
+}
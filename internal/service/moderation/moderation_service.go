@@ -0,0 +1,214 @@
+package moderation
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"answer/internal/base/constant"
+	"answer/internal/base/pager"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/schema"
+	"answer/internal/service/report_common"
+	"answer/internal/service/siteinfo_common"
+	usercommon "answer/internal/service/user_common"
+	"answer/pkg/htmltext"
+	"answer/pkg/moderation"
+
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// moderationReportType the report type of the flag added by the automated moderation, it is "something else"
+const moderationReportType = 59
+
+// ModerationRepo moderation repository
+type ModerationRepo interface {
+	AddModerationResult(ctx context.Context, result *entity.ModerationResult) (err error)
+	GetModerationResultPage(ctx context.Context, objectID, action string, page, pageSize int) (
+		results []*entity.ModerationResult, total int64, err error)
+	moderation.SeenStore
+}
+
+// ModerationService moderation service
+type ModerationService struct {
+	moderationRepo  ModerationRepo
+	reportRepo      report_common.ReportRepo
+	userCommon      *usercommon.UserCommon
+	siteInfoService *siteinfo_common.SiteInfoCommonService
+}
+
+// NewModerationService new moderation service
+func NewModerationService(
+	moderationRepo ModerationRepo,
+	reportRepo report_common.ReportRepo,
+	userCommon *usercommon.UserCommon,
+	siteInfoService *siteinfo_common.SiteInfoCommonService,
+) *ModerationService {
+	return &ModerationService{
+		moderationRepo:  moderationRepo,
+		reportRepo:      reportRepo,
+		userCommon:      userCommon,
+		siteInfoService: siteInfoService,
+	}
+}
+
+// Check score the new post with the checks configured by admin.
+// The rejected post is recorded here and the error is returned, so it should not be saved.
+func (ms *ModerationService) Check(ctx context.Context, content *moderation.Content) (
+	verdict *schema.ModerationVerdict, err error) {
+	verdict = &schema.ModerationVerdict{Action: entity.ModerationActionPass}
+	setting, err := ms.siteInfoService.GetSiteModeration(ctx)
+	if err != nil {
+		log.Error(err)
+		return verdict, nil
+	}
+	if !setting.Enabled {
+		return verdict, nil
+	}
+
+	result := ms.buildPipeline(ctx, setting).Run(ctx, content)
+	for _, checkErr := range result.Errors {
+		log.Errorf("moderation check failed: %s", checkErr)
+	}
+	verdict.Score = result.Score
+	verdict.Hits = result.Hits
+	switch {
+	case reachThreshold(result.Score, setting.RejectThreshold):
+		verdict.Action = entity.ModerationActionReject
+	case reachThreshold(result.Score, setting.HoldThreshold):
+		verdict.Action = entity.ModerationActionHold
+	case reachThreshold(result.Score, setting.FlagThreshold):
+		verdict.Action = entity.ModerationActionFlag
+	}
+
+	if verdict.Action == entity.ModerationActionReject {
+		if err = ms.addResult(ctx, content, "0", verdict); err != nil {
+			log.Error(err)
+		}
+		return verdict, errors.BadRequest(reason.ModerationRejected)
+	}
+	return verdict, nil
+}
+
+// Record record the verdict of the saved post, the flagged post is put into the report queue.
+// The comment can not be held, so the held comment is flagged instead.
+func (ms *ModerationService) Record(ctx context.Context, content *moderation.Content, objectID string,
+	verdict *schema.ModerationVerdict) {
+	if verdict == nil || verdict.Action == entity.ModerationActionPass {
+		return
+	}
+	if err := ms.addResult(ctx, content, objectID, verdict); err != nil {
+		log.Error(err)
+	}
+	if verdict.Action == entity.ModerationActionHold && content.ObjectType != constant.CommentObjectType {
+		return
+	}
+	report := &entity.Report{
+		UserID:         "0",
+		ReportedUserID: content.UserID,
+		ObjectID:       objectID,
+		ObjectType:     constant.ObjectTypeStrMapping[content.ObjectType],
+		ReportType:     moderationReportType,
+		Content:        explain(verdict.Hits),
+		Status:         entity.ReportStatusPending,
+	}
+	if err := ms.reportRepo.AddReport(ctx, report); err != nil {
+		log.Error(err)
+	}
+}
+
+// GetModerationResultPage get the moderation results of the posts which are not passed
+func (ms *ModerationService) GetModerationResultPage(ctx context.Context, req *schema.GetModerationResultPageReq) (
+	resp *pager.PageModel, err error) {
+	results, total, err := ms.moderationRepo.GetModerationResultPage(ctx, req.ObjectID, req.Action,
+		req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]string, 0, len(results))
+	for _, result := range results {
+		userIDs = append(userIDs, result.UserID)
+	}
+	userInfoMapping, err := ms.userCommon.BatchUserBasicInfoByID(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*schema.GetModerationResultResp, 0, len(results))
+	for _, result := range results {
+		item := &schema.GetModerationResultResp{
+			ID:         result.ID,
+			CreatedAt:  result.CreatedAt.Unix(),
+			ObjectType: result.ObjectType,
+			ObjectID:   result.ObjectID,
+			Score:      result.Score,
+			Action:     result.Action,
+			Hits:       make([]*moderation.Hit, 0),
+			Excerpt:    result.Excerpt,
+			UserInfo:   userInfoMapping[result.UserID],
+		}
+		_ = json.Unmarshal([]byte(result.Hits), &item.Hits)
+		list = append(list, item)
+	}
+	return pager.NewPageModel(total, list), nil
+}
+
+// buildPipeline only the checks which have scores configured are added
+func (ms *ModerationService) buildPipeline(ctx context.Context, setting *schema.SiteModerationResp) *moderation.Pipeline {
+	checkers := make([]moderation.Checker, 0)
+	if len(setting.BlockedKeywords) > 0 || len(setting.BlockedPatterns) > 0 {
+		checker, err := moderation.NewKeywordChecker(setting.BlockedKeywords, setting.BlockedPatterns,
+			setting.KeywordScore)
+		if err != nil {
+			log.Error(err)
+		} else {
+			checkers = append(checkers, checker)
+		}
+	}
+	if setting.MaxLinks > 0 || len(setting.BlockedDomains) > 0 {
+		checkers = append(checkers, moderation.NewLinkChecker(setting.MaxLinks, setting.LinkScore,
+			setting.BlockedDomains, setting.DomainScore))
+	}
+	if setting.RepeatScore > 0 {
+		checkers = append(checkers, moderation.NewRepeatChecker(ms.moderationRepo, setting.RepeatScore))
+	}
+	if len(setting.ClassifierEndpoint) > 0 {
+		siteURL := ""
+		if general, err := ms.siteInfoService.GetSiteGeneral(ctx); err == nil {
+			siteURL = general.SiteUrl
+		}
+		checkers = append(checkers, moderation.NewClassifierChecker(setting.ClassifierEndpoint,
+			setting.ClassifierAPIKey, siteURL, setting.ClassifierScore, nil))
+	}
+	return moderation.NewPipeline(checkers...)
+}
+
+func (ms *ModerationService) addResult(ctx context.Context, content *moderation.Content, objectID string,
+	verdict *schema.ModerationVerdict) (err error) {
+	hits, _ := json.Marshal(verdict.Hits)
+	return ms.moderationRepo.AddModerationResult(ctx, &entity.ModerationResult{
+		ObjectType: content.ObjectType,
+		ObjectID:   objectID,
+		UserID:     content.UserID,
+		Score:      verdict.Score,
+		Action:     verdict.Action,
+		Hits:       string(hits),
+		Excerpt:    htmltext.FetchExcerpt(content.HTML, "...", 240),
+	})
+}
+
+// reachThreshold 0 threshold means the action is disabled
+func reachThreshold(score, threshold int) bool {
+	return threshold > 0 && score >= threshold
+}
+
+// explain explain which rules are fired, such as "keyword: casino; link: 12 links"
+func explain(hits []*moderation.Hit) string {
+	details := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		details = append(details, hit.Rule+": "+hit.Detail)
+	}
+	return strings.Join(details, "; ")
+}
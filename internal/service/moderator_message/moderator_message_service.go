@@ -0,0 +1,311 @@
+package moderator_message
+
+import (
+	"context"
+	"fmt"
+
+	"answer/internal/base/constant"
+	"answer/internal/base/reason"
+	"answer/internal/base/translator"
+	"answer/internal/entity"
+	"answer/internal/schema"
+	"answer/internal/service/export"
+	"answer/internal/service/notice_queue"
+	usercommon "answer/internal/service/user_common"
+	"answer/pkg/htmltext"
+
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/i18n"
+	"github.com/segmentfault/pacman/log"
+)
+
+// moderatorMessageTemplates the keys of the canned messages, the name and content of them are translated
+var moderatorMessageTemplates = []string{
+	"improve_question",
+	"off_topic",
+	"be_nice",
+	"duplicate",
+	"spam",
+}
+
+// ModeratorMessageRepo moderator message repository
+type ModeratorMessageRepo interface {
+	AddModeratorMessage(ctx context.Context, message *entity.ModeratorMessage) (err error)
+	GetModeratorMessage(ctx context.Context, id string) (message *entity.ModeratorMessage, exist bool, err error)
+	GetModeratorMessageThread(ctx context.Context, threadID string) (messages []*entity.ModeratorMessage, err error)
+	GetUserModeratorMessages(ctx context.Context, userID string) (messages []*entity.ModeratorMessage, err error)
+}
+
+// ModeratorMessageService moderator message service
+type ModeratorMessageService struct {
+	moderatorMessageRepo ModeratorMessageRepo
+	userRepo             usercommon.UserRepo
+	userCommon           *usercommon.UserCommon
+	emailService         *export.EmailService
+}
+
+// NewModeratorMessageService new moderator message service
+func NewModeratorMessageService(
+	moderatorMessageRepo ModeratorMessageRepo,
+	userRepo usercommon.UserRepo,
+	userCommon *usercommon.UserCommon,
+	emailService *export.EmailService,
+) *ModeratorMessageService {
+	return &ModeratorMessageService{
+		moderatorMessageRepo: moderatorMessageRepo,
+		userRepo:             userRepo,
+		userCommon:           userCommon,
+		emailService:         emailService,
+	}
+}
+
+// GetTemplates get the canned messages in the language of the moderator
+func (ms *ModeratorMessageService) GetTemplates(lang i18n.Language) (resp []*schema.ModeratorMessageTemplate) {
+	resp = make([]*schema.ModeratorMessageTemplate, 0, len(moderatorMessageTemplates))
+	for _, key := range moderatorMessageTemplates {
+		resp = append(resp, &schema.ModeratorMessageTemplate{
+			Key:     key,
+			Name:    translator.GlobalTrans.Tr(lang, templateTransKey(key, "name")),
+			Content: translator.GlobalTrans.Tr(lang, templateTransKey(key, "content")),
+		})
+	}
+	return resp
+}
+
+// AddMessage send the message to the user by moderator, a new thread is started if the thread is not specified
+func (ms *ModeratorMessageService) AddMessage(ctx context.Context, req *schema.AddModeratorMessageReq) (
+	resp *schema.ModeratorMessageResp, err error) {
+	if len(req.TemplateKey) > 0 && !isTemplate(req.TemplateKey) {
+		return nil, errors.BadRequest(reason.ModeratorMessageTemplateNotFound)
+	}
+	userInfo, exist, err := ms.userRepo.GetByUserID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.BadRequest(reason.UserNotFound)
+	}
+
+	message := &entity.ModeratorMessage{
+		ThreadID:    "0",
+		UserID:      req.UserID,
+		SenderID:    req.SenderID,
+		TemplateKey: req.TemplateKey,
+		Content:     req.Content,
+	}
+	if len(req.ThreadID) > 0 {
+		root, err := ms.getThreadRoot(ctx, req.ThreadID)
+		if err != nil {
+			return nil, err
+		}
+		if root.UserID != req.UserID {
+			return nil, errors.BadRequest(reason.ModeratorMessageNotFound)
+		}
+		message.ThreadID = root.ID
+	}
+	if err = ms.moderatorMessageRepo.AddModeratorMessage(ctx, message); err != nil {
+		return nil, err
+	}
+	threadID := threadIDOf(message)
+
+	ms.notify(req.SenderID, req.UserID, threadID, constant.ModeratorSentYouAMessage, message.Content)
+	if req.SendEmail && userInfo.MailStatus == entity.EmailStatusAvailable {
+		ms.sendEmail(ctx, userInfo.EMail, message.Content)
+	}
+
+	resp = ms.formatMessage(message)
+	resp.SenderInfo, _, err = ms.userCommon.GetUserBasicInfoByID(ctx, message.SenderID)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ReplyMessage reply the thread by the user, the moderators in the thread are notified
+func (ms *ModeratorMessageService) ReplyMessage(ctx context.Context, req *schema.ReplyModeratorMessageReq) (
+	resp *schema.ModeratorMessageResp, err error) {
+	root, err := ms.getThreadRoot(ctx, req.ThreadID)
+	if err != nil {
+		return nil, err
+	}
+	if root.UserID != req.UserID {
+		return nil, errors.BadRequest(reason.ModeratorMessageNotFound)
+	}
+	messages, err := ms.moderatorMessageRepo.GetModeratorMessageThread(ctx, root.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	message := &entity.ModeratorMessage{
+		ThreadID: root.ID,
+		UserID:   req.UserID,
+		SenderID: req.UserID,
+		Content:  req.Content,
+	}
+	if err = ms.moderatorMessageRepo.AddModeratorMessage(ctx, message); err != nil {
+		return nil, err
+	}
+
+	notified := make(map[string]bool)
+	for _, item := range messages {
+		if item.SenderID == item.UserID || notified[item.SenderID] {
+			continue
+		}
+		notified[item.SenderID] = true
+		ms.notify(req.UserID, item.SenderID, root.ID, constant.UserRepliedToModeratorMessage, message.Content)
+	}
+
+	resp = ms.formatMessage(message)
+	resp.SenderInfo, _, err = ms.userCommon.GetUserBasicInfoByID(ctx, message.SenderID)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetThread get the thread about the login user
+func (ms *ModeratorMessageService) GetThread(ctx context.Context, req *schema.GetModeratorMessageThreadReq) (
+	resp *schema.ModeratorMessageThreadResp, err error) {
+	root, err := ms.getThreadRoot(ctx, req.ThreadID)
+	if err != nil {
+		return nil, err
+	}
+	if root.UserID != req.UserID {
+		return nil, errors.BadRequest(reason.ModeratorMessageNotFound)
+	}
+	messages, err := ms.moderatorMessageRepo.GetModeratorMessageThread(ctx, root.ID)
+	if err != nil {
+		return nil, err
+	}
+	threads, err := ms.formatThreads(ctx, messages)
+	if err != nil {
+		return nil, err
+	}
+	return threads[0], nil
+}
+
+// GetThreadList get all the threads about the user by moderator, the newest first.
+// All the moderators can see the threads, no matter who sent the messages.
+func (ms *ModeratorMessageService) GetThreadList(ctx context.Context, req *schema.GetModeratorMessageThreadListReq) (
+	resp []*schema.ModeratorMessageThreadResp, err error) {
+	messages, err := ms.moderatorMessageRepo.GetUserModeratorMessages(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	threads, err := ms.formatThreads(ctx, messages)
+	if err != nil {
+		return nil, err
+	}
+	resp = make([]*schema.ModeratorMessageThreadResp, 0, len(threads))
+	for i := len(threads) - 1; i >= 0; i-- {
+		resp = append(resp, threads[i])
+	}
+	return resp, nil
+}
+
+// getThreadRoot get the first message of the thread
+func (ms *ModeratorMessageService) getThreadRoot(ctx context.Context, threadID string) (
+	root *entity.ModeratorMessage, err error) {
+	root, exist, err := ms.moderatorMessageRepo.GetModeratorMessage(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist || root.ThreadID != "0" {
+		return nil, errors.BadRequest(reason.ModeratorMessageNotFound)
+	}
+	return root, nil
+}
+
+// formatThreads group the messages which are the oldest first by thread, the threads keep the order of the messages
+func (ms *ModeratorMessageService) formatThreads(ctx context.Context, messages []*entity.ModeratorMessage) (
+	threads []*schema.ModeratorMessageThreadResp, err error) {
+	senderIDs := make([]string, 0, len(messages))
+	for _, message := range messages {
+		senderIDs = append(senderIDs, message.SenderID)
+	}
+	userInfoMapping, err := ms.userCommon.BatchUserBasicInfoByID(ctx, senderIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	threads = make([]*schema.ModeratorMessageThreadResp, 0)
+	threadMapping := make(map[string]*schema.ModeratorMessageThreadResp)
+	for _, message := range messages {
+		threadID := threadIDOf(message)
+		thread, ok := threadMapping[threadID]
+		if !ok {
+			thread = &schema.ModeratorMessageThreadResp{
+				ID:        threadID,
+				CreatedAt: message.CreatedAt.Unix(),
+				Messages:  make([]*schema.ModeratorMessageResp, 0),
+			}
+			threadMapping[threadID] = thread
+			threads = append(threads, thread)
+		}
+		item := ms.formatMessage(message)
+		item.SenderInfo = userInfoMapping[message.SenderID]
+		thread.Messages = append(thread.Messages, item)
+	}
+	return threads, nil
+}
+
+func (ms *ModeratorMessageService) formatMessage(message *entity.ModeratorMessage) *schema.ModeratorMessageResp {
+	return &schema.ModeratorMessageResp{
+		ID:            message.ID,
+		CreatedAt:     message.CreatedAt.Unix(),
+		TemplateKey:   message.TemplateKey,
+		Content:       message.Content,
+		FromModerator: message.SenderID != message.UserID,
+	}
+}
+
+// notify send the notification of the message, the title is the excerpt of the message
+func (ms *ModeratorMessageService) notify(triggerUserID, receiverUserID, threadID, action, content string) {
+	notice_queue.AddNotification(&schema.NotificationMsg{
+		TriggerUserID:       triggerUserID,
+		ReceiverUserID:      receiverUserID,
+		Type:                schema.NotificationTypeModeratorMessage,
+		Title:               htmltext.FetchExcerpt(content, "...", 120),
+		ObjectID:            threadID,
+		ObjectType:          constant.ModeratorMessageObjectType,
+		NotificationAction:  action,
+		NoNeedPushAllFollow: true,
+	})
+}
+
+// sendEmail send the message to the email of the user, the link leads to the moderator messages of the user
+func (ms *ModeratorMessageService) sendEmail(ctx context.Context, email, content string) {
+	siteGeneral, err := ms.emailService.GetSiteGeneral(ctx)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	messageURL := fmt.Sprintf("%s/users/notifications/moderator_message", siteGeneral.SiteUrl)
+	title, body, err := ms.emailService.ModeratorMessageTemplate(ctx, content, messageURL)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	go ms.emailService.Send(context.Background(), email, title, body, "", "")
+}
+
+// threadIDOf the first message of the thread is the thread itself
+func threadIDOf(message *entity.ModeratorMessage) string {
+	if message.ThreadID == "0" || len(message.ThreadID) == 0 {
+		return message.ID
+	}
+	return message.ThreadID
+}
+
+func isTemplate(key string) bool {
+	for _, template := range moderatorMessageTemplates {
+		if template == key {
+			return true
+		}
+	}
+	return false
+}
+
+func templateTransKey(key, field string) string {
+	return fmt.Sprintf("moderator_message.template.%s.%s", key, field)
+}
@@ -45,6 +45,7 @@ func (ns *NotificationService) GetRedDot(ctx context.Context, req *schema.GetRed
 	redBot := &schema.RedDot{}
 	inboxKey := fmt.Sprintf("answer_RedDot_%d_%s", schema.NotificationTypeInbox, req.UserID)
 	achievementKey := fmt.Sprintf("answer_RedDot_%d_%s", schema.NotificationTypeAchievement, req.UserID)
+	moderatorMessageKey := fmt.Sprintf("answer_RedDot_%d_%s", schema.NotificationTypeModeratorMessage, req.UserID)
 	inboxValue, err := ns.data.Cache.GetInt64(ctx, inboxKey)
 	if err != nil {
 		redBot.Inbox = 0
@@ -57,6 +58,12 @@ func (ns *NotificationService) GetRedDot(ctx context.Context, req *schema.GetRed
 	} else {
 		redBot.Achievement = achievementValue
 	}
+	moderatorMessageValue, err := ns.data.Cache.GetInt64(ctx, moderatorMessageKey)
+	if err != nil {
+		redBot.ModeratorMessage = 0
+	} else {
+		redBot.ModeratorMessage = moderatorMessageValue
+	}
 	revisionCount := &schema.RevisionSearch{}
 	_ = copier.Copy(revisionCount, req)
 	if req.CanReviewAnswer || req.CanReviewQuestion || req.CanReviewTag {
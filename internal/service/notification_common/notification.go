@@ -75,7 +75,7 @@ func (ns *NotificationCommon) HandleNotification() {
 // AddNotification
 // need set
 // UserID
-// Type  1 inbox 2 achievement
+// Type  1 inbox 2 achievement 3 moderator message
 // [inbox] Activity
 // [achievement] Rank
 // ObjectInfo.Title
@@ -94,10 +94,15 @@ func (ns *NotificationCommon) AddNotification(ctx context.Context, msg *schema.N
 		Type:               msg.Type,
 	}
 	var questionID string // just for notify all followers
-	objInfo, err := ns.objectInfoService.GetInfo(ctx, req.ObjectInfo.ObjectID)
+	var objInfo *schema.SimpleObjectInfo
+	var err error
+	// the object of the moderator message is the thread, the title is the excerpt of the message
+	if msg.Type != schema.NotificationTypeModeratorMessage {
+		objInfo, err = ns.objectInfoService.GetInfo(ctx, req.ObjectInfo.ObjectID)
+	}
 	if err != nil {
 		log.Error(err)
-	} else {
+	} else if objInfo != nil {
 		req.ObjectInfo.Title = objInfo.Title
 		questionID = objInfo.QuestionID
 		objectMap := make(map[string]string)
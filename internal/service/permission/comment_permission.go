@@ -3,6 +3,7 @@ package permission
 import (
 	"context"
 
+	"answer/internal/entity"
 	"answer/internal/schema"
 )
 
@@ -121,6 +122,27 @@ func GetQuestionPermission(ctx context.Context, userID string, creatorUserID str
 	return actions
 }
 
+// GetQuestionCloseVotePermission get the permission to vote to close the available question or reopen the closed question
+func GetQuestionCloseVotePermission(ctx context.Context, questionStatus int, canCloseVote, canReopenVote bool) (
+	actions []*schema.PermissionMemberAction) {
+	actions = make([]*schema.PermissionMemberAction, 0)
+	if canCloseVote && questionStatus == entity.QuestionStatusAvailable {
+		actions = append(actions, &schema.PermissionMemberAction{
+			Action: "close_vote",
+			Name:   "Vote to close",
+			Type:   "reason",
+		})
+	}
+	if canReopenVote && questionStatus == entity.QuestionStatusClosed {
+		actions = append(actions, &schema.PermissionMemberAction{
+			Action: "reopen_vote",
+			Name:   "Vote to reopen",
+			Type:   "confirm",
+		})
+	}
+	return actions
+}
+
 // GetTagSynonymPermission get tag synonym permission
 func GetTagSynonymPermission(ctx context.Context, canEdit bool) (
 	actions []*schema.PermissionMemberAction) {
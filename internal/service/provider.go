@@ -10,27 +10,44 @@ import (
 	"answer/internal/service/comment"
 	"answer/internal/service/comment_common"
 	"answer/internal/service/dashboard"
+	"answer/internal/service/draft"
 	"answer/internal/service/export"
+	"answer/internal/service/feed"
 	"answer/internal/service/follow"
+	"answer/internal/service/mention"
 	"answer/internal/service/meta"
+	"answer/internal/service/moderation"
+	"answer/internal/service/moderator_message"
 	"answer/internal/service/notification"
 	notficationcommon "answer/internal/service/notification_common"
 	"answer/internal/service/object_info"
+	"answer/internal/service/question_close_vote"
 	questioncommon "answer/internal/service/question_common"
+	"answer/internal/service/question_view"
 	"answer/internal/service/rank"
 	"answer/internal/service/reason"
 	"answer/internal/service/report"
 	"answer/internal/service/report_backyard"
 	"answer/internal/service/report_handle_backyard"
+	"answer/internal/service/reputation"
+	"answer/internal/service/review"
 	"answer/internal/service/revision_common"
 	"answer/internal/service/search_parser"
+	"answer/internal/service/seo"
 	"answer/internal/service/siteinfo"
 	"answer/internal/service/siteinfo_common"
 	"answer/internal/service/tag"
 	tagcommon "answer/internal/service/tag_common"
+	"answer/internal/service/tag_moderator"
+	"answer/internal/service/tag_subscription"
+	"answer/internal/service/tag_template"
 	"answer/internal/service/uploader"
 	"answer/internal/service/user_backyard"
 	usercommon "answer/internal/service/user_common"
+	"answer/internal/service/user_data"
+	"answer/internal/service/user_invite"
+	"answer/internal/service/user_suspension"
+	"answer/internal/service/user_two_factor"
 
 	"github.com/google/wire"
 )
@@ -60,6 +77,7 @@ var ProviderSetService = wire.NewSet(
 	revision_common.NewRevisionService,
 	NewRevisionService,
 	rank.NewRankService,
+	reputation.NewReputationService,
 	search_parser.NewSearchParser,
 	NewSearchService,
 	meta.NewMetaService,
@@ -76,4 +94,20 @@ var ProviderSetService = wire.NewSet(
 	dashboard.NewDashboardService,
 	activity_common.NewActivityCommon,
 	activity.NewActivityService,
+	seo.NewSeoService,
+	user_invite.NewUserInviteService,
+	user_data.NewUserDataService,
+	question_view.NewQuestionViewService,
+	user_two_factor.NewUserTwoFactorService,
+	review.NewReviewService,
+	question_close_vote.NewQuestionCloseVoteService,
+	moderation.NewModerationService,
+	user_suspension.NewUserSuspensionService,
+	moderator_message.NewModeratorMessageService,
+	draft.NewDraftService,
+	feed.NewFeedService,
+	mention.NewMentionService,
+	tag_template.NewTagTemplateService,
+	tag_moderator.NewTagModeratorService,
+	tag_subscription.NewTagSubscriptionService,
 )
@@ -0,0 +1,272 @@
+package question_close_vote
+
+import (
+	"context"
+	"time"
+
+	"answer/internal/base/constant"
+	"answer/internal/base/data"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/schema"
+	"answer/internal/service/activity_queue"
+	questioncommon "answer/internal/service/question_common"
+	"answer/internal/service/siteinfo_common"
+
+	"github.com/segmentfault/pacman/errors"
+)
+
+const (
+	// VoteTypeClose the vote to close the available question
+	VoteTypeClose = "close"
+	// VoteTypeReopen the vote to reopen the closed question
+	VoteTypeReopen = "reopen"
+)
+
+// QuestionCloseVoteRepo question close vote repository
+type QuestionCloseVoteRepo interface {
+	AddCloseVote(ctx context.Context, vote *entity.QuestionCloseVote, since time.Time) (added bool, err error)
+	GetActiveCloseVoteList(ctx context.Context, questionID string, voteType int, since time.Time) (
+		votes []*entity.QuestionCloseVote, err error)
+	CompleteCloseVotes(ctx context.Context, questionID string, voteType int) (err error)
+}
+
+// QuestionCloseVoteService the community votes to close or reopen the question,
+// the question is closed or reopened automatically when the votes reach the threshold
+type QuestionCloseVoteService struct {
+	questionCloseVoteRepo QuestionCloseVoteRepo
+	questionRepo          questioncommon.QuestionRepo
+	questionCommon        *questioncommon.QuestionCommon
+	siteInfoService       *siteinfo_common.SiteInfoCommonService
+	data                  *data.Data
+}
+
+// NewQuestionCloseVoteService new question close vote service
+func NewQuestionCloseVoteService(
+	questionCloseVoteRepo QuestionCloseVoteRepo,
+	questionRepo questioncommon.QuestionRepo,
+	questionCommon *questioncommon.QuestionCommon,
+	siteInfoService *siteinfo_common.SiteInfoCommonService,
+	data *data.Data,
+) *QuestionCloseVoteService {
+	return &QuestionCloseVoteService{
+		questionCloseVoteRepo: questionCloseVoteRepo,
+		questionRepo:          questionRepo,
+		questionCommon:        questionCommon,
+		siteInfoService:       siteInfoService,
+		data:                  data,
+	}
+}
+
+// GetCloseVote get the close votes of the available question, or the reopen votes of the closed question
+func (qs *QuestionCloseVoteService) GetCloseVote(ctx context.Context, req *schema.GetQuestionCloseVoteReq) (
+	resp *schema.QuestionCloseVoteResp, err error) {
+	setting, err := qs.siteInfoService.GetSiteCloseVote(ctx)
+	if err != nil {
+		return nil, err
+	}
+	questionInfo, exist, err := qs.questionRepo.GetQuestion(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist || questionInfo.Status == entity.QuestionStatusDeleted || questionInfo.Status == entity.QuestionStatusPending {
+		return nil, errors.BadRequest(reason.QuestionNotFound)
+	}
+
+	resp = &schema.QuestionCloseVoteResp{Enabled: setting.Enabled, Status: questionInfo.Status}
+	voteType, required := getVoteType(setting, questionInfo.Status)
+	if voteType == 0 {
+		return resp, nil
+	}
+	votes, err := qs.questionCloseVoteRepo.GetActiveCloseVoteList(ctx, questionInfo.ID, voteType, voteSince(setting))
+	if err != nil {
+		return nil, err
+	}
+	formatCloseVote(resp, votes, voteType, required, req.UserID)
+	return resp, nil
+}
+
+// VoteClose vote to close the available question
+func (qs *QuestionCloseVoteService) VoteClose(ctx context.Context, req *schema.QuestionCloseVoteReq) (
+	resp *schema.QuestionCloseVoteResp, err error) {
+	return qs.vote(ctx, req, entity.QuestionCloseVoteTypeClose)
+}
+
+// VoteReopen vote to reopen the closed question
+func (qs *QuestionCloseVoteService) VoteReopen(ctx context.Context, req *schema.QuestionCloseVoteReq) (
+	resp *schema.QuestionCloseVoteResp, err error) {
+	return qs.vote(ctx, req, entity.QuestionCloseVoteTypeReopen)
+}
+
+func (qs *QuestionCloseVoteService) vote(ctx context.Context, req *schema.QuestionCloseVoteReq, voteType int) (
+	resp *schema.QuestionCloseVoteResp, err error) {
+	setting, err := qs.siteInfoService.GetSiteCloseVote(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !setting.Enabled {
+		return nil, errors.BadRequest(reason.QuestionCloseVoteDisabled)
+	}
+	questionInfo, exist, err := qs.questionRepo.GetQuestion(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist || questionInfo.Status == entity.QuestionStatusDeleted || questionInfo.Status == entity.QuestionStatusPending {
+		return nil, errors.BadRequest(reason.QuestionNotFound)
+	}
+	currentVoteType, required := getVoteType(setting, questionInfo.Status)
+	if currentVoteType != voteType {
+		return nil, errors.BadRequest(reason.QuestionCloseVoteInvalid)
+	}
+
+	vote := &entity.QuestionCloseVote{
+		QuestionID: questionInfo.ID,
+		UserID:     req.UserID,
+		VoteType:   voteType,
+		Status:     entity.QuestionCloseVoteStatusActive,
+	}
+	activityTypeKey := constant.ActQuestionReopenVoted
+	if voteType == entity.QuestionCloseVoteTypeClose {
+		vote.CloseType = req.CloseType
+		vote.CloseMsg = req.CloseMsg
+		activityTypeKey = constant.ActQuestionCloseVoted
+	}
+
+	// the vote is added and counted, and the question is closed or reopened in one transaction,
+	// so the concurrent votes are counted one by one and the last one which reaches the threshold completes them
+	since, status := voteSince(setting), questionInfo.Status
+	var votes []*entity.QuestionCloseVote
+	completed := false
+	err = qs.data.Transaction(ctx, func(ctx context.Context) error {
+		added, err := qs.questionCloseVoteRepo.AddCloseVote(ctx, vote, since)
+		if err != nil {
+			return err
+		}
+		if !added {
+			return errors.BadRequest(reason.QuestionCloseVoteAlready)
+		}
+		// the question may be closed or reopened by the other votes before it is locked
+		questionInfo, exist, err = qs.questionRepo.GetQuestion(ctx, questionInfo.ID)
+		if err != nil {
+			return err
+		}
+		if !exist || questionInfo.Status != status {
+			return errors.BadRequest(reason.QuestionCloseVoteInvalid)
+		}
+		votes, err = qs.questionCloseVoteRepo.GetActiveCloseVoteList(ctx, questionInfo.ID, voteType, since)
+		if err != nil {
+			return err
+		}
+		if len(votes) < required && !req.IsTagModerator {
+			return nil
+		}
+
+		// the votes reach the threshold, or the moderator of the tags votes, close or reopen the question,
+		// then the votes are not counted any more
+		completed = true
+		if err = qs.questionCloseVoteRepo.CompleteCloseVotes(ctx, questionInfo.ID, voteType); err != nil {
+			return err
+		}
+		if voteType == entity.QuestionCloseVoteTypeClose {
+			closeType, closeMsg := mostVotedCloseReason(votes)
+			return qs.questionCommon.CloseQuestion(ctx, &schema.CloseQuestionReq{
+				ID:        questionInfo.ID,
+				UserID:    req.UserID,
+				CloseType: closeType,
+				CloseMsg:  closeMsg,
+			})
+		}
+		return qs.questionRepo.UpdateQuestionStatus(ctx, &entity.Question{
+			ID: questionInfo.ID, Status: entity.QuestionStatusAvailable})
+	})
+	if err != nil {
+		return nil, err
+	}
+	activity_queue.AddActivity(&schema.ActivityMsg{
+		UserID:           req.UserID,
+		ObjectID:         questionInfo.ID,
+		OriginalObjectID: questionInfo.ID,
+		ActivityTypeKey:  activityTypeKey,
+	})
+
+	resp = &schema.QuestionCloseVoteResp{Enabled: setting.Enabled, Status: questionInfo.Status}
+	formatCloseVote(resp, votes, voteType, required, req.UserID)
+	if !completed {
+		return resp, nil
+	}
+	if voteType == entity.QuestionCloseVoteTypeClose {
+		resp.Status = entity.QuestionStatusClosed
+	} else {
+		activity_queue.AddActivity(&schema.ActivityMsg{
+			UserID:           req.UserID,
+			ObjectID:         questionInfo.ID,
+			OriginalObjectID: questionInfo.ID,
+			ActivityTypeKey:  constant.ActQuestionReopened,
+		})
+		resp.Status = entity.QuestionStatusAvailable
+	}
+	return resp, nil
+}
+
+func formatCloseVote(resp *schema.QuestionCloseVoteResp,
+	votes []*entity.QuestionCloseVote, voteType, required int, userID string) {
+	resp.VoteType = VoteTypeClose
+	if voteType == entity.QuestionCloseVoteTypeReopen {
+		resp.VoteType = VoteTypeReopen
+	}
+	resp.Votes = len(votes)
+	resp.Required = required
+	for _, vote := range votes {
+		if vote.UserID == userID {
+			resp.Voted = true
+		}
+	}
+}
+
+// getVoteType the available question can be voted to close, and the closed question can be voted to reopen
+func getVoteType(setting *schema.SiteCloseVoteResp, questionStatus int) (voteType, required int) {
+	switch questionStatus {
+	case entity.QuestionStatusAvailable:
+		voteType, required = entity.QuestionCloseVoteTypeClose, setting.CloseVotes
+	case entity.QuestionStatusClosed:
+		voteType, required = entity.QuestionCloseVoteTypeReopen, setting.ReopenVotes
+	default:
+		return 0, 0
+	}
+	if required < 1 {
+		required = 1
+	}
+	return voteType, required
+}
+
+// voteSince the votes cast before it are expired, the zero time means the votes never expire
+func voteSince(setting *schema.SiteCloseVoteResp) time.Time {
+	if setting.ExpireDays <= 0 {
+		return time.Time{}
+	}
+	return time.Now().AddDate(0, 0, -setting.ExpireDays)
+}
+
+// mostVotedCloseReason get the close reason which has the most votes, the earliest one wins the tie
+func mostVotedCloseReason(votes []*entity.QuestionCloseVote) (closeType int, closeMsg string) {
+	counts := make(map[int]int)
+	most := 0
+	for _, vote := range votes {
+		counts[vote.CloseType]++
+		if counts[vote.CloseType] > most {
+			most = counts[vote.CloseType]
+		}
+	}
+	for _, vote := range votes {
+		if counts[vote.CloseType] == most {
+			closeType = vote.CloseType
+			break
+		}
+	}
+	for _, vote := range votes {
+		if vote.CloseType == closeType && len(vote.CloseMsg) > 0 {
+			return closeType, vote.CloseMsg
+		}
+	}
+	return closeType, ""
+}
@@ -27,6 +27,7 @@ import (
 // QuestionRepo question repository
 type QuestionRepo interface {
 	AddQuestion(ctx context.Context, question *entity.Question) (err error)
+	AddQuestionWithAnswer(ctx context.Context, question *entity.Question, answer *entity.Answer) (err error)
 	RemoveQuestion(ctx context.Context, id string) (err error)
 	UpdateQuestion(ctx context.Context, question *entity.Question, Cols []string) (err error)
 	GetQuestion(ctx context.Context, id string) (question *entity.Question, exist bool, err error)
@@ -35,14 +36,15 @@ type QuestionRepo interface {
 	SearchList(ctx context.Context, search *schema.QuestionSearch) ([]*entity.QuestionTag, int64, error)
 	UpdateQuestionStatus(ctx context.Context, question *entity.Question) (err error)
 	SearchByTitleLike(ctx context.Context, title string) (questionList []*entity.Question, err error)
-	UpdatePvCount(ctx context.Context, questionID string) (err error)
 	UpdateAnswerCount(ctx context.Context, questionID string, num int) (err error)
 	UpdateCollectionCount(ctx context.Context, questionID string, num int) (err error)
 	UpdateAccepted(ctx context.Context, question *entity.Question) (err error)
+	ChangeAccepted(ctx context.Context, questionID, oldAnswerID, newAnswerID string) (changed bool, err error)
 	UpdateLastAnswer(ctx context.Context, question *entity.Question) (err error)
 	FindByID(ctx context.Context, id []string) (questionList []*entity.Question, err error)
 	CmsSearchList(ctx context.Context, search *schema.CmsQuestionSearch) ([]*entity.Question, int64, error)
 	GetQuestionCount(ctx context.Context) (count int64, err error)
+	GetQuestionIDsPage(ctx context.Context, page, pageSize int) (questionList []*entity.Question, err error)
 }
 
 // QuestionCommon user service
@@ -84,8 +86,9 @@ func NewQuestionCommon(questionRepo QuestionRepo,
 	}
 }
 
-func (qs *QuestionCommon) UpdataPv(ctx context.Context, questionID string) error {
-	return qs.questionRepo.UpdatePvCount(ctx, questionID)
+// GetFollowTagIDs get the ids of the tags which the user follows
+func (qs *QuestionCommon) GetFollowTagIDs(ctx context.Context, userID string) (tagIDs []string, err error) {
+	return qs.followCommon.GetFollowIDs(ctx, userID, entity.Tag{}.TableName())
 }
 
 func (qs *QuestionCommon) UpdateAnswerCount(ctx context.Context, questionID string, num int) error {
@@ -399,6 +402,8 @@ func (qs *QuestionCommon) ShowFormat(ctx context.Context, data *entity.Question)
 	info.HTML = data.ParsedText
 	info.ViewCount = data.ViewCount
 	info.UniqueViewCount = data.UniqueViewCount
+	info.Language = data.Language
+	info.CommunityWiki = data.CommunityWiki
 	info.VoteCount = data.VoteCount
 	info.AnswerCount = data.AnswerCount
 	info.CollectionCount = data.CollectionCount
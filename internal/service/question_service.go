@@ -16,13 +16,22 @@ import (
 	"answer/internal/service/activity"
 	"answer/internal/service/activity_queue"
 	collectioncommon "answer/internal/service/collection_common"
+	"answer/internal/service/draft"
+	"answer/internal/service/mention"
 	"answer/internal/service/meta"
+	moderationservice "answer/internal/service/moderation"
 	"answer/internal/service/notice_queue"
 	"answer/internal/service/permission"
 	questioncommon "answer/internal/service/question_common"
+	"answer/internal/service/question_view"
+	"answer/internal/service/review"
 	"answer/internal/service/revision_common"
 	tagcommon "answer/internal/service/tag_common"
+	"answer/internal/service/tag_subscription"
+	"answer/internal/service/tag_template"
 	usercommon "answer/internal/service/user_common"
+	"answer/pkg/langdetect"
+	"answer/pkg/moderation"
 
 	"github.com/jinzhu/copier"
 	"github.com/segmentfault/pacman/errors"
@@ -35,14 +44,22 @@ import (
 
 // QuestionService user service
 type QuestionService struct {
-	questionRepo          questioncommon.QuestionRepo
-	tagCommon             *tagcommon.TagCommonService
-	questioncommon        *questioncommon.QuestionCommon
-	userCommon            *usercommon.UserCommon
-	revisionService       *revision_common.RevisionService
-	metaService           *meta.MetaService
-	collectionCommon      *collectioncommon.CollectionCommon
-	answerActivityService *activity.AnswerActivityService
+	questionRepo           questioncommon.QuestionRepo
+	tagCommon              *tagcommon.TagCommonService
+	questioncommon         *questioncommon.QuestionCommon
+	userCommon             *usercommon.UserCommon
+	revisionService        *revision_common.RevisionService
+	metaService            *meta.MetaService
+	collectionCommon       *collectioncommon.CollectionCommon
+	answerActivityService  *activity.AnswerActivityService
+	questionViewService    *question_view.QuestionViewService
+	reviewService          *review.ReviewService
+	moderationService      *moderationservice.ModerationService
+	draftService           *draft.DraftService
+	mentionService         *mention.MentionService
+	answerService          *AnswerService
+	tagTemplateService     *tag_template.TagTemplateService
+	tagSubscriptionService *tag_subscription.TagSubscriptionService
 }
 
 func NewQuestionService(
@@ -54,16 +71,32 @@ func NewQuestionService(
 	metaService *meta.MetaService,
 	collectionCommon *collectioncommon.CollectionCommon,
 	answerActivityService *activity.AnswerActivityService,
+	questionViewService *question_view.QuestionViewService,
+	reviewService *review.ReviewService,
+	moderationService *moderationservice.ModerationService,
+	draftService *draft.DraftService,
+	mentionService *mention.MentionService,
+	answerService *AnswerService,
+	tagTemplateService *tag_template.TagTemplateService,
+	tagSubscriptionService *tag_subscription.TagSubscriptionService,
 ) *QuestionService {
 	return &QuestionService{
-		questionRepo:          questionRepo,
-		tagCommon:             tagCommon,
-		questioncommon:        questioncommon,
-		userCommon:            userCommon,
-		revisionService:       revisionService,
-		metaService:           metaService,
-		collectionCommon:      collectionCommon,
-		answerActivityService: answerActivityService,
+		questionRepo:           questionRepo,
+		tagCommon:              tagCommon,
+		questioncommon:         questioncommon,
+		userCommon:             userCommon,
+		revisionService:        revisionService,
+		metaService:            metaService,
+		collectionCommon:       collectionCommon,
+		answerActivityService:  answerActivityService,
+		questionViewService:    questionViewService,
+		reviewService:          reviewService,
+		moderationService:      moderationService,
+		draftService:           draftService,
+		mentionService:         mentionService,
+		answerService:          answerService,
+		tagTemplateService:     tagTemplateService,
+		tagSubscriptionService: tagSubscriptionService,
 	}
 }
 
@@ -76,7 +109,7 @@ func (qs *QuestionService) CloseQuestion(ctx context.Context, req *schema.CloseQ
 		return nil
 	}
 
-	if !req.IsAdmin {
+	if !req.IsAdmin && !req.IsTagModerator {
 		if questionInfo.UserID != req.UserID {
 			return errors.BadRequest(reason.QuestionCannotClose)
 		}
@@ -136,6 +169,14 @@ func (qs *QuestionService) AddQuestion(ctx context.Context, req *schema.Question
 		err = errors.BadRequest(reason.RecommendTagEnter)
 		return errorlist, err
 	}
+	if errorlist, err := qs.checkQuestionTemplate(ctx, req.Tags, req.Content); err != nil {
+		return errorlist, err
+	}
+	var mentionUserIDs []string
+	req.HTML, mentionUserIDs, err = qs.mentionService.Resolve(ctx, req.Content, req.HTML, "")
+	if err != nil {
+		return
+	}
 
 	question := &entity.Question{}
 	now := time.Now()
@@ -143,6 +184,7 @@ func (qs *QuestionService) AddQuestion(ctx context.Context, req *schema.Question
 	question.Title = req.Title
 	question.OriginalText = req.Content
 	question.ParsedText = req.HTML
+	question.Language = langdetect.Resolve(req.Language, req.Title+"\n"+req.Content)
 	question.AcceptedAnswerID = "0"
 	question.LastAnswerID = "0"
 	question.LastEditUserID = "0"
@@ -150,11 +192,68 @@ func (qs *QuestionService) AddQuestion(ctx context.Context, req *schema.Question
 	question.Status = entity.QuestionStatusAvailable
 	question.RevisionID = "0"
 	question.CreatedAt = now
+	question.CommunityWiki = req.CommunityWiki
 	//question.UpdatedAt = nil
-	err = qs.questionRepo.AddQuestion(ctx, question)
+	moderationContent := &moderation.Content{
+		ObjectType: constant.QuestionObjectType,
+		UserID:     req.UserID,
+		Title:      req.Title,
+		HTML:       req.HTML,
+		IP:         req.IP,
+		UserAgent:  req.UserAgent,
+	}
+	verdict, err := qs.moderationService.Check(ctx, moderationContent)
+	if err != nil {
+		return
+	}
+	reviewQueue, hold := qs.reviewService.CheckQuestion(ctx, req.UserID, req.HTML)
+	if verdict.Action == entity.ModerationActionHold {
+		reviewQueue, hold = entity.ReviewQueueModeration, true
+	}
+	if hold {
+		question.Status = entity.QuestionStatusPending
+	}
+
+	// the answer by the author self is posted with the question, it inherits the community wiki flag
+	var answerReq *schema.AnswerAddReq
+	var selfAnswer *newAnswer
+	if len(req.AnswerContent) > 0 {
+		answerReq = &schema.AnswerAddReq{
+			Content:       req.AnswerContent,
+			HTML:          req.AnswerHTML,
+			Language:      req.Language,
+			UserID:        req.UserID,
+			IP:            req.IP,
+			UserAgent:     req.UserAgent,
+			CommunityWiki: req.CommunityWiki,
+		}
+		selfAnswer, err = qs.answerService.prepareAnswer(ctx, answerReq, question)
+		if err != nil {
+			return
+		}
+		// the answer shares the status and the review of the question, they are approved or rejected together
+		if selfAnswer.hold && !hold {
+			reviewQueue, hold = selfAnswer.reviewQueue, true
+			question.Status = entity.QuestionStatusPending
+		}
+		selfAnswer.reviewQueue, selfAnswer.hold = "", hold
+		selfAnswer.answer.Status = entity.AnswerStatusAvailable
+		if hold {
+			selfAnswer.answer.Status = entity.AnswerStatusPending
+		}
+		err = qs.questionRepo.AddQuestionWithAnswer(ctx, question, selfAnswer.answer)
+	} else {
+		err = qs.questionRepo.AddQuestion(ctx, question)
+	}
 	if err != nil {
 		return
 	}
+	qs.moderationService.Record(ctx, moderationContent, question.ID, verdict)
+	if len(reviewQueue) > 0 {
+		if err = qs.reviewService.AddReview(ctx, reviewQueue, question.ID, question.UserID, hold); err != nil {
+			return
+		}
+	}
 	objectTagData := schema.TagChange{}
 	objectTagData.ObjectID = question.ID
 	objectTagData.Tags = req.Tags
@@ -190,19 +289,20 @@ func (qs *QuestionService) AddQuestion(ctx context.Context, req *schema.Question
 		return
 	}
 
-	// user add question count
-	err = qs.userCommon.UpdateQuestionCount(ctx, question.UserID, 1)
-	if err != nil {
-		log.Error("user IncreaseQuestionCount error", err.Error())
+	// the held question is published once it is approved
+	if !hold {
+		question.RevisionID = revisionID
+		if err = qs.reviewService.PublishQuestion(ctx, question, mentionUserIDs); err != nil {
+			return
+		}
+	}
+	qs.draftService.RemoveSubmittedDraft(ctx, req.UserID, entity.DraftTypeQuestion, "0")
+	if selfAnswer != nil {
+		answerReq.QuestionID = question.ID
+		if err = qs.answerService.afterAddAnswer(ctx, answerReq, question, selfAnswer); err != nil {
+			return
+		}
 	}
-
-	activity_queue.AddActivity(&schema.ActivityMsg{
-		UserID:           question.UserID,
-		ObjectID:         question.ID,
-		OriginalObjectID: question.ID,
-		ActivityTypeKey:  constant.ActQuestionAsked,
-		RevisionID:       revisionID,
-	})
 
 	questionInfo, err = qs.GetQuestion(ctx, question.ID, question.UserID, req.QuestionPermission)
 	return
@@ -269,6 +369,25 @@ func (qs *QuestionService) RemoveQuestion(ctx context.Context, req *schema.Remov
 	return nil
 }
 
+// checkQuestionTemplate check the question content against the templates of the tags,
+// the required sections which are left empty are returned as the form error of the content
+func (qs *QuestionService) checkQuestionTemplate(ctx context.Context, tags []*schema.TagItem, content string) (
+	errorlist []*validator.FormErrorField, err error) {
+	missingSections, err := qs.tagTemplateService.CheckQuestionContent(ctx, tags, content)
+	if err != nil {
+		return nil, err
+	}
+	if len(missingSections) == 0 {
+		return nil, nil
+	}
+	errorlist = append(errorlist, &validator.FormErrorField{
+		ErrorField: "content",
+		ErrorMsg: translator.GlobalTrans.Tr(handler.GetLangByCtx(ctx), reason.QuestionTemplateSectionRequired) +
+			": " + strings.Join(missingSections, ", "),
+	})
+	return errorlist, errors.BadRequest(reason.QuestionTemplateSectionRequired)
+}
+
 // UpdateQuestion update question
 func (qs *QuestionService) UpdateQuestion(ctx context.Context, req *schema.QuestionUpdate) (questionInfo any, err error) {
 	var canUpdate bool
@@ -291,12 +410,29 @@ func (qs *QuestionService) UpdateQuestion(ctx context.Context, req *schema.Quest
 	if !has {
 		return
 	}
+	if dbinfo.CommunityWiki {
+		// the users who meet the threshold of the community wiki edit it without review
+		if req.CanEditWiki {
+			req.NoNeedReview = true
+		}
+	} else if !req.CanEdit {
+		err = errors.Forbidden(reason.RankFailToMeetTheCondition)
+		return
+	}
+
+	// only the users who are newly mentioned by the edit are notified
+	var mentionUserIDs []string
+	req.HTML, mentionUserIDs, err = qs.mentionService.Resolve(ctx, req.Content, req.HTML, dbinfo.OriginalText)
+	if err != nil {
+		return
+	}
 
 	now := time.Now()
 	question := &entity.Question{}
 	question.Title = req.Title
 	question.OriginalText = req.Content
 	question.ParsedText = req.HTML
+	question.Language = langdetect.Resolve(req.Language, req.Title+"\n"+req.Content)
 	question.ID = req.ID
 	question.UpdatedAt = now
 	question.PostUpdateTime = now
@@ -306,6 +442,9 @@ func (qs *QuestionService) UpdateQuestion(ctx context.Context, req *schema.Quest
 	if dbinfo.UserID != req.UserID {
 		question.LastEditUserID = req.UserID
 	}
+	// only the author or admin turns the question into a community wiki, and it can not be turned back
+	question.CommunityWiki = dbinfo.CommunityWiki ||
+		(req.CommunityWiki && (req.IsAdmin || dbinfo.UserID == req.UserID))
 
 	oldTags, tagerr := qs.tagCommon.GetObjectEntityTag(ctx, question.ID)
 	if tagerr != nil {
@@ -324,7 +463,8 @@ func (qs *QuestionService) UpdateQuestion(ctx context.Context, req *schema.Quest
 	isChange := qs.tagCommon.CheckTagsIsChange(ctx, tagNameList, oldtagNameList)
 
 	//If the content is the same, ignore it
-	if dbinfo.Title == req.Title && dbinfo.OriginalText == req.Content && !isChange {
+	if dbinfo.Title == req.Title && dbinfo.OriginalText == req.Content && dbinfo.Language == question.Language && !isChange &&
+		dbinfo.CommunityWiki == question.CommunityWiki {
 		return
 	}
 
@@ -364,11 +504,18 @@ func (qs *QuestionService) UpdateQuestion(ctx context.Context, req *schema.Quest
 		err = errors.BadRequest(reason.RecommendTagEnter)
 		return errorlist, err
 	}
+	// the templates are checked only if the tags are changed,
+	// so the questions asked before their tags had the templates can still be edited
+	if isChange {
+		if errorlist, err := qs.checkQuestionTemplate(ctx, req.Tags, req.Content); err != nil {
+			return errorlist, err
+		}
+	}
 
 	//Administrators and themselves do not need to be audited
 
 	revisionDTO := &schema.AddRevisionDTO{
-		UserID:   question.UserID,
+		UserID:   req.UserID,
 		ObjectID: question.ID,
 		Title:    question.Title,
 		Log:      req.EditSummary,
@@ -385,7 +532,7 @@ func (qs *QuestionService) UpdateQuestion(ctx context.Context, req *schema.Quest
 		//Direct modification
 		revisionDTO.Status = entity.RevisionReviewPassStatus
 		//update question to db
-		saveerr := qs.questionRepo.UpdateQuestion(ctx, question, []string{"title", "original_text", "parsed_text", "updated_at", "post_update_time", "last_edit_user_id"})
+		saveerr := qs.questionRepo.UpdateQuestion(ctx, question, []string{"title", "original_text", "parsed_text", "language", "updated_at", "post_update_time", "last_edit_user_id", "community_wiki"})
 		if saveerr != nil {
 			return questionInfo, saveerr
 		}
@@ -409,6 +556,7 @@ func (qs *QuestionService) UpdateQuestion(ctx context.Context, req *schema.Quest
 	if err != nil {
 		return
 	}
+	qs.draftService.RemoveSubmittedDraft(ctx, req.UserID, entity.DraftTypeEdit, question.ID)
 	if canUpdate {
 		activity_queue.AddActivity(&schema.ActivityMsg{
 			UserID:           req.UserID,
@@ -417,6 +565,13 @@ func (qs *QuestionService) UpdateQuestion(ctx context.Context, req *schema.Quest
 			RevisionID:       revisionID,
 			OriginalObjectID: question.ID,
 		})
+		qs.mentionService.Notify(mentionUserIDs, req.UserID, question.ID, constant.QuestionObjectType)
+		// the author edits the closed question, ask the reviewers to vote for reopening it
+		if dbinfo.Status == entity.QuestionStatusClosed && dbinfo.UserID == req.UserID {
+			if err = qs.reviewService.AddReopenReview(ctx, question.ID, req.UserID); err != nil {
+				log.Error(err)
+			}
+		}
 	}
 
 	questionInfo, err = qs.GetQuestion(ctx, question.ID, question.UserID, req.QuestionPermission)
@@ -430,19 +585,22 @@ func (qs *QuestionService) GetQuestion(ctx context.Context, questionID, userID s
 	if err != nil {
 		return
 	}
+	// the pending question is only visible to its author and the reviewers
+	if question.Status == entity.QuestionStatusPending && question.UserID != userID && !per.CanReview {
+		return nil, errors.BadRequest(reason.QuestionNotFound)
+	}
 	question.MemberActions = permission.GetQuestionPermission(ctx, userID, question.UserID,
 		per.CanEdit, per.CanDelete, per.CanClose)
+	question.MemberActions = append(question.MemberActions,
+		permission.GetQuestionCloseVotePermission(ctx, question.Status, per.CanCloseVote, per.CanReopenVote)...)
 	return question, nil
 }
 
-// GetQuestionAndAddPV get question one
-func (qs *QuestionService) GetQuestionAndAddPV(ctx context.Context, questionID, loginUserID string,
+// GetQuestionAndAddPV get question one, and count the view of the visitor
+func (qs *QuestionService) GetQuestionAndAddPV(ctx context.Context, questionID, loginUserID, ip, userAgent string,
 	per schema.QuestionPermission) (
 	resp *schema.QuestionInfo, err error) {
-	err = qs.questioncommon.UpdataPv(ctx, questionID)
-	if err != nil {
-		log.Error(err)
-	}
+	qs.questionViewService.AddView(ctx, questionID, loginUserID, ip, userAgent)
 	return qs.GetQuestion(ctx, questionID, loginUserID, per)
 }
 
@@ -469,6 +627,7 @@ func (qs *QuestionService) SearchUserList(ctx context.Context, userName, order s
 	search.Page = page
 	search.PageSize = pageSize
 	search.UserID = userinfo.ID
+	search.Language = schema.QuestionSearchLanguageAll
 	questionlist, count, err := qs.SearchList(ctx, search, loginUserID)
 	if err != nil {
 		return userlist, 0, err
@@ -592,6 +751,7 @@ func (qs *QuestionService) SearchUserTopList(ctx context.Context, userName strin
 	search.Page = 0
 	search.PageSize = 5
 	search.UserID = userinfo.ID
+	search.Language = schema.QuestionSearchLanguageAll
 	questionlist, _, err := qs.SearchList(ctx, search, loginUserID)
 	if err != nil {
 		return userQuestionlist, userAnswerlist, err
@@ -650,6 +810,7 @@ func (qs *QuestionService) SearchByTitleLike(ctx context.Context, title string,
 		item.ID = question.ID
 		item.Title = question.Title
 		item.ViewCount = question.ViewCount
+		item.UniqueViewCount = question.UniqueViewCount
 		item.AnswerCount = question.AnswerCount
 		item.CollectionCount = question.CollectionCount
 		item.FollowCount = question.FollowCount
@@ -709,6 +870,35 @@ func (qs *QuestionService) SearchList(ctx context.Context, req *schema.QuestionS
 		}
 		req.UserID = userinfo.ID
 	}
+	// the questions with the ignored tags are hidden, unless the tag or the user is browsed explicitly
+	if len(req.Tag) == 0 && len(req.UserName) == 0 && len(loginUserID) > 0 {
+		ignoredTagIDs, err := qs.tagSubscriptionService.GetIgnoredTagIDs(ctx, loginUserID)
+		if err != nil {
+			return list, 0, err
+		}
+		req.IgnoredTagIDs = ignoredTagIDs
+	}
+	if req.Order == "for_you" && len(loginUserID) > 0 {
+		followTagIDs, err := qs.questioncommon.GetFollowTagIDs(ctx, loginUserID)
+		if err != nil {
+			return list, 0, err
+		}
+		req.FollowTagIDs = followTagIDs
+	}
+	switch {
+	case req.Language == schema.QuestionSearchLanguageAll:
+	case len(req.Language) > 0:
+		if language := langdetect.Normalize(req.Language); len(language) > 0 {
+			req.ContentLanguages = []string{language}
+		}
+	case len(loginUserID) > 0 && len(req.UserID) == 0:
+		// browse in the content languages of the user, except the questions of the specified user
+		contentLanguages, err := qs.userCommon.GetUserContentLanguages(ctx, loginUserID)
+		if err != nil {
+			return list, 0, err
+		}
+		req.ContentLanguages = contentLanguages
+	}
 	questionList, count, err := qs.questionRepo.SearchList(ctx, req)
 	if err != nil {
 		return list, count, err
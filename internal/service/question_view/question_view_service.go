@@ -0,0 +1,107 @@
+package question_view
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"sync"
+
+	"answer/internal/base/constant"
+	"answer/internal/base/scheduler"
+	"answer/internal/service/activity_common"
+	"answer/pkg/checker"
+
+	"github.com/segmentfault/pacman/log"
+)
+
+// QuestionViewRepo question view repository
+type QuestionViewRepo interface {
+	MarkQuestionViewed(ctx context.Context, questionID, viewer string) (view, uniqueView bool, err error)
+	AddViewCount(ctx context.Context, questionID string, viewCount, uniqueViewCount int) (err error)
+}
+
+// pendingView the view counts of the question which are not flushed to the database yet
+type pendingView struct {
+	viewCount       int
+	uniqueViewCount int
+}
+
+// QuestionViewService question view service, count the views of the question without the duplicate views and bots
+type QuestionViewService struct {
+	questionViewRepo  QuestionViewRepo
+	questionScoreRepo activity_common.QuestionScoreRepo
+	lock              sync.Mutex
+	pending           map[string]*pendingView
+}
+
+// NewQuestionViewService new question view service, the cleanup flushes the buffered view counts
+func NewQuestionViewService(
+	questionViewRepo QuestionViewRepo,
+	questionScoreRepo activity_common.QuestionScoreRepo,
+	scheduler *scheduler.Scheduler,
+) (qs *QuestionViewService, cleanup func()) {
+	qs = &QuestionViewService{
+		questionViewRepo:  questionViewRepo,
+		questionScoreRepo: questionScoreRepo,
+		pending:           make(map[string]*pendingView),
+	}
+	scheduler.AddJob("question_view_flush", constant.QuestionViewFlushInterval, false, qs.FlushViewCount)
+	return qs, func() {
+		log.Info("flushing the buffered question view counts")
+		qs.FlushViewCount(context.Background())
+	}
+}
+
+// AddView count the view of the question, the view of the same viewer is counted once in the window.
+// The viewer is the login user, or the fingerprint of the ip and user agent for the anonymous user.
+func (qs *QuestionViewService) AddView(ctx context.Context, questionID, userID, ip, userAgent string) {
+	if checker.IsBot(userAgent) {
+		return
+	}
+	view, uniqueView, err := qs.questionViewRepo.MarkQuestionViewed(ctx, questionID, viewerFingerprint(userID, ip, userAgent))
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if !view {
+		return
+	}
+
+	qs.lock.Lock()
+	defer qs.lock.Unlock()
+	pv, ok := qs.pending[questionID]
+	if !ok {
+		pv = &pendingView{}
+		qs.pending[questionID] = pv
+	}
+	pv.viewCount++
+	if uniqueView {
+		pv.uniqueViewCount++
+	}
+}
+
+// FlushViewCount write the buffered view counts to the database and refresh the hot score of the questions
+func (qs *QuestionViewService) FlushViewCount(ctx context.Context) {
+	qs.lock.Lock()
+	pending := qs.pending
+	qs.pending = make(map[string]*pendingView)
+	qs.lock.Unlock()
+
+	for questionID, pv := range pending {
+		if err := qs.questionViewRepo.AddViewCount(ctx, questionID, pv.viewCount, pv.uniqueViewCount); err != nil {
+			log.Error(err)
+			continue
+		}
+		if err := qs.questionScoreRepo.RefreshHotScore(ctx, questionID); err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+func viewerFingerprint(userID, ip, userAgent string) string {
+	if len(userID) > 0 {
+		return "u" + userID
+	}
+	sum := md5.Sum([]byte(ip + "|" + userAgent))
+	return "a" + hex.EncodeToString(sum[:])
+}
@@ -22,12 +22,16 @@ const (
 	QuestionAddRank               = "rank.question.add"
 	QuestionEditRank              = "rank.question.edit"
 	QuestionEditWithoutReviewRank = "rank.question.edit_without_review"
+	QuestionEditWikiRank          = "rank.question.edit_wiki"
 	QuestionDeleteRank            = "rank.question.delete"
+	QuestionCloseVoteRank         = "rank.question.close_vote"
+	QuestionReopenVoteRank        = "rank.question.reopen_vote"
 	QuestionVoteUpRank            = "rank.question.vote_up"
 	QuestionVoteDownRank          = "rank.question.vote_down"
 	AnswerAddRank                 = "rank.answer.add"
 	AnswerEditRank                = "rank.answer.edit"
 	AnswerEditWithoutReviewRank   = "rank.answer.edit_without_review"
+	AnswerEditWikiRank            = "rank.answer.edit_wiki"
 	AnswerDeleteRank              = "rank.answer.delete"
 	AnswerAcceptRank              = "rank.answer.accept"
 	AnswerVoteUpRank              = "rank.answer.vote_up"
@@ -53,6 +57,7 @@ const (
 type UserRankRepo interface {
 	TriggerUserRank(ctx context.Context, session *xorm.Session, userId string, rank int, activityType int) (isReachStandard bool, err error)
 	UserRankPage(ctx context.Context, userId string, page, pageSize int) (rankPage []*entity.Activity, total int64, err error)
+	TriggerReviewRank(ctx context.Context, reviewerID, objectID string) (err error)
 }
 
 // RankService rank service
@@ -209,6 +214,11 @@ func (rs *RankService) CheckVotePermission(ctx context.Context, userID, objectID
 	return meetRank, nil
 }
 
+// TriggerReviewRank reward the reviewer who completed the review of the object
+func (rs *RankService) TriggerReviewRank(ctx context.Context, reviewerID, objectID string) (err error) {
+	return rs.userRankRepo.TriggerReviewRank(ctx, reviewerID, objectID)
+}
+
 // CheckRankPermission verify that the user meets the prestige criteria
 func (rs *RankService) checkUserRank(ctx context.Context, userID string, userRank int, action string) (
 	can bool, err error) {
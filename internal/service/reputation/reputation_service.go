@@ -0,0 +1,226 @@
+package reputation
+
+import (
+	"context"
+	"time"
+
+	"answer/internal/entity"
+	configrepo "answer/internal/repo/config"
+	"answer/internal/schema"
+	"answer/internal/service/config"
+
+	"github.com/segmentfault/pacman/log"
+)
+
+const (
+	// defaultBatchSize the number of the users recalculated in one batch if it is not specified
+	defaultBatchSize = 100
+
+	// MaxBatchSize the max number of the users recalculated in one batch
+	MaxBatchSize = 1000
+
+	dailyRankLimit        = "daily_rank_limit"
+	dailyRankLimitExclude = "daily_rank_limit.exclude"
+	answerAccept          = "answer.accept"
+	answerAccepted        = "answer.accepted"
+	userActivated         = "user.activated"
+)
+
+// rankActivityKeys the activity types which the reputation rules are configured for
+var rankActivityKeys = []string{
+	"answer.accepted", "answer.voted_up", "question.voted_up", "tag.edit_accepted", "answer.accept",
+	"answer.voted_down_cancel", "question.voted_down_cancel", "answer.vote_down_cancel", "question.vote_down_cancel",
+	"user.activated", "edit.accepted", "answer.vote_down", "question.voted_down", "answer.voted_down",
+	"answer.accept_cancel", "answer.deleted", "question.voted_up_cancel", "answer.voted_up_cancel",
+	"answer.accepted_cancel", "object.reported", "edit.rejected", "user.follow", "comment.vote_up",
+	"comment.vote_up_cancel", "question.vote_down", "question.vote_up", "question.vote_up_cancel", "answer.vote_up",
+	"answer.vote_up_cancel", "question.follow", "tag.follow", "review.completed",
+}
+
+// ReputationRepo reputation recalculation repository
+type ReputationRepo interface {
+	GetUsersAfter(ctx context.Context, afterUserID string, limit int) (users []*entity.User, err error)
+	GetUserRankActivities(ctx context.Context, userID string, activityTypes []int) (
+		activities []*entity.Activity, err error)
+	FixUserRank(ctx context.Context, userID string, deltaRank int, activities []*entity.Activity) (err error)
+}
+
+// ReputationService recalculate the reputation of the users from their activities under the current rules
+type ReputationService struct {
+	reputationRepo ReputationRepo
+	configRepo     config.ConfigRepo
+}
+
+// NewReputationService new reputation service
+func NewReputationService(reputationRepo ReputationRepo, configRepo config.ConfigRepo) *ReputationService {
+	return &ReputationService{
+		reputationRepo: reputationRepo,
+		configRepo:     configRepo,
+	}
+}
+
+// rules the current reputation rules in the config table
+type rules struct {
+	activityTypes []int
+	deltas        map[int]int
+	exclude       map[int]bool
+	maxDaily      int
+	acceptType    int
+	acceptedType  int
+	activatedType int
+}
+
+// RecalculateReputation recalculate the reputation of the batch of users after the user id. The differences are
+// reported, and unless it is a dry run, the rank of the user and the rank of the changed activities are fixed.
+// Pass the next user id of the response to continue with the next batch, so that it can be resumed at any batch.
+func (rs *ReputationService) RecalculateReputation(ctx context.Context, req *schema.RecalculateReputationReq) (
+	resp *schema.RecalculateReputationResp, err error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultBatchSize
+	}
+	r, err := rs.getRules()
+	if err != nil {
+		return nil, err
+	}
+	users, err := rs.reputationRepo.GetUsersAfter(ctx, req.AfterUserID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &schema.RecalculateReputationResp{Checked: len(users), Diffs: make([]*schema.ReputationDiff, 0)}
+	for _, user := range users {
+		activities, err := rs.reputationRepo.GetUserRankActivities(ctx, user.ID, r.activityTypes)
+		if err != nil {
+			return nil, err
+		}
+		rank, changed := r.replay(r.seededRank(user, activities), activities)
+		if rank == user.Rank && len(changed) == 0 {
+			continue
+		}
+		resp.Diffs = append(resp.Diffs, &schema.ReputationDiff{
+			UserID:            user.ID,
+			Username:          user.Username,
+			OldRank:           user.Rank,
+			NewRank:           rank,
+			ChangedActivities: len(changed),
+		})
+		if req.DryRun {
+			continue
+		}
+		if err = rs.reputationRepo.FixUserRank(ctx, user.ID, rank-user.Rank, changed); err != nil {
+			return nil, err
+		}
+		log.Infof("user %s reputation is recalculated from %d to %d", user.ID, user.Rank, rank)
+	}
+	if len(users) == limit {
+		resp.NextUserID = users[len(users)-1].ID
+	}
+	return resp, nil
+}
+
+// getRules get the reputation of each activity type, the activity types not limited daily and the daily limit
+func (rs *ReputationService) getRules() (r *rules, err error) {
+	r = &rules{deltas: make(map[int]int), exclude: make(map[int]bool)}
+	for _, key := range rankActivityKeys {
+		activityType, err := rs.configRepo.GetConfigType(key)
+		if err != nil {
+			continue
+		}
+		value, err := rs.configRepo.GetInt(key)
+		if err != nil {
+			continue
+		}
+		r.activityTypes = append(r.activityTypes, activityType)
+		r.deltas[activityType] = value
+	}
+	r.acceptType = configrepo.Key2IDMapping[answerAccept]
+	r.acceptedType = configrepo.Key2IDMapping[answerAccepted]
+	r.activatedType = configrepo.Key2IDMapping[userActivated]
+	r.maxDaily, err = rs.configRepo.GetInt(dailyRankLimit)
+	if err != nil {
+		return nil, err
+	}
+	exclude, _ := rs.configRepo.GetArrayString(dailyRankLimitExclude)
+	for _, key := range exclude {
+		if activityType, err := rs.configRepo.GetConfigType(key); err == nil {
+			r.exclude[activityType] = true
+		}
+	}
+	return r, nil
+}
+
+// seededRank the reputation the user has without any activity. The user activated by email gains the reputation of
+// the activation activity, but the admin seeded at the installation has the reputation of 1 without any activity.
+func (r *rules) seededRank(user *entity.User, activities []*entity.Activity) int {
+	for _, act := range activities {
+		if act.ActivityType == r.activatedType {
+			return 0
+		}
+	}
+	if user.Rank > 0 {
+		return 1
+	}
+	return 0
+}
+
+// replay the activities in the order they took effect, the same as TriggerUserRank does: the reputation never drops
+// below 1, and no more is gained in the day once the daily limit is reached. The day is of the updated time of the
+// activity, which is what the daily limit is counted by. The self accepted answer gains nothing.
+// The rank of the changed activities is set to the recalculated value, and has_rank is set if the rule of the
+// activity type is not 0, the same as it is set when the activity is added.
+func (r *rules) replay(base int, activities []*entity.Activity) (rank int, changed []*entity.Activity) {
+	accepts := make(map[string]bool)
+	for _, act := range activities {
+		if act.ActivityType == r.acceptType {
+			accepts[act.ObjectID] = true
+		}
+	}
+	selfAccepts := make(map[string]bool)
+	for _, act := range activities {
+		if act.ActivityType == r.acceptedType && accepts[act.ObjectID] {
+			selfAccepts[act.ObjectID] = true
+		}
+	}
+
+	changed = make([]*entity.Activity, 0)
+	rank = base
+	earned := make(map[time.Time]int)
+	for _, act := range activities {
+		delta, ok := r.deltas[act.ActivityType]
+		if !ok {
+			delta = act.Rank
+		}
+		hasRank := 0
+		if delta != 0 {
+			hasRank = 1
+		}
+		if (act.ActivityType == r.acceptType || act.ActivityType == r.acceptedType) && selfAccepts[act.ObjectID] {
+			delta = 0
+		}
+		updated := act.UpdatedAt.In(time.Local)
+		day := time.Date(updated.Year(), updated.Month(), updated.Day(), 0, 0, 0, 0, time.Local)
+
+		switch {
+		case delta < 0:
+			if rank+delta < 1 {
+				rank = 1
+			} else {
+				rank += delta
+			}
+		case delta > 0:
+			if !r.exclude[act.ActivityType] && earned[day] >= r.maxDaily {
+				delta = 0
+			} else {
+				rank += delta
+			}
+		}
+		earned[day] += delta
+
+		if act.Rank != delta || act.HasRank != hasRank {
+			act.Rank, act.HasRank = delta, hasRank
+			changed = append(changed, act)
+		}
+	}
+	return rank, changed
+}
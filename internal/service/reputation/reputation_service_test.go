@@ -0,0 +1,155 @@
+package reputation
+
+import (
+	"testing"
+	"time"
+
+	"answer/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testAcceptedType  = 1
+	testVotedUpType   = 2
+	testAcceptType    = 5
+	testActivatedType = 10
+	testVotedDownType = 14
+)
+
+func testRules() *rules {
+	return &rules{
+		deltas: map[int]int{
+			testAcceptedType:  15,
+			testVotedUpType:   10,
+			testAcceptType:    2,
+			testActivatedType: 1,
+			testVotedDownType: -2,
+		},
+		exclude:       map[int]bool{testAcceptedType: true},
+		maxDaily:      20,
+		acceptType:    testAcceptType,
+		acceptedType:  testAcceptedType,
+		activatedType: testActivatedType,
+	}
+}
+
+func testActivity(activityType int, objectID string, updatedAt time.Time) *entity.Activity {
+	return &entity.Activity{ActivityType: activityType, ObjectID: objectID, UpdatedAt: updatedAt}
+}
+
+func Test_rules_replay(t *testing.T) {
+	day := time.Date(2023, 3, 1, 10, 0, 0, 0, time.Local)
+	nextDay := day.Add(24 * time.Hour)
+	tests := []struct {
+		name       string
+		base       int
+		activities []*entity.Activity
+		wantRank   int
+		wantRanks  []int
+	}{
+		{
+			name: "the daily limit is counted by the day",
+			base: 1,
+			activities: []*entity.Activity{
+				testActivity(testVotedUpType, "1", day),
+				testActivity(testVotedUpType, "2", day.Add(time.Hour)),
+				testActivity(testVotedUpType, "3", day.Add(2*time.Hour)),
+				testActivity(testVotedUpType, "4", nextDay),
+			},
+			wantRank:  31,
+			wantRanks: []int{10, 10, 0, 10},
+		},
+		{
+			name: "the excluded activity type is not limited daily",
+			base: 1,
+			activities: []*entity.Activity{
+				testActivity(testVotedUpType, "1", day),
+				testActivity(testVotedUpType, "2", day),
+				testActivity(testAcceptedType, "3", day),
+			},
+			wantRank:  36,
+			wantRanks: []int{10, 10, 15},
+		},
+		{
+			name: "the reputation never drops below 1",
+			base: 1,
+			activities: []*entity.Activity{
+				testActivity(testVotedDownType, "1", day),
+				testActivity(testVotedUpType, "2", day),
+				testActivity(testVotedDownType, "3", day),
+			},
+			wantRank:  9,
+			wantRanks: []int{-2, 10, -2},
+		},
+		{
+			name: "the self accepted answer gains nothing",
+			base: 1,
+			activities: []*entity.Activity{
+				testActivity(testAcceptType, "1", day),
+				testActivity(testAcceptedType, "1", day),
+				testActivity(testAcceptedType, "2", day),
+			},
+			wantRank:  16,
+			wantRanks: []int{0, 0, 15},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rank, _ := testRules().replay(tt.base, tt.activities)
+			assert.Equal(t, tt.wantRank, rank)
+			for i, act := range tt.activities {
+				assert.Equal(t, tt.wantRanks[i], act.Rank)
+			}
+		})
+	}
+}
+
+func Test_rules_replayChanged(t *testing.T) {
+	day := time.Date(2023, 3, 1, 10, 0, 0, 0, time.Local)
+	activities := []*entity.Activity{
+		{ActivityType: testVotedUpType, ObjectID: "1", UpdatedAt: day, Rank: 10, HasRank: 1},
+		// the rule was 0 when it was voted
+		{ActivityType: testVotedUpType, ObjectID: "2", UpdatedAt: day},
+	}
+	rank, changed := testRules().replay(1, activities)
+	assert.Equal(t, 21, rank)
+	if assert.Equal(t, 1, len(changed)) {
+		assert.Equal(t, "2", changed[0].ObjectID)
+		assert.Equal(t, 10, changed[0].Rank)
+		assert.Equal(t, 1, changed[0].HasRank)
+	}
+}
+
+func Test_rules_seededRank(t *testing.T) {
+	tests := []struct {
+		name       string
+		user       *entity.User
+		activities []*entity.Activity
+		want       int
+	}{
+		{
+			name:       "the activated user gains the reputation by the activation",
+			user:       &entity.User{Rank: 11},
+			activities: []*entity.Activity{{ActivityType: testActivatedType}, {ActivityType: testVotedUpType}},
+			want:       0,
+		},
+		{
+			name:       "the seeded admin has the reputation of 1",
+			user:       &entity.User{Rank: 1, IsAdmin: true},
+			activities: []*entity.Activity{},
+			want:       1,
+		},
+		{
+			name:       "the user without the reputation",
+			user:       &entity.User{Rank: 0},
+			activities: []*entity.Activity{},
+			want:       0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, testRules().seededRank(tt.user, tt.activities))
+		})
+	}
+}
@@ -0,0 +1,643 @@
+package review
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+
+	"answer/internal/base/constant"
+	"answer/internal/base/pager"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/schema"
+	"answer/internal/service/activity_queue"
+	answercommon "answer/internal/service/answer_common"
+	"answer/internal/service/mention"
+	"answer/internal/service/notice_queue"
+	"answer/internal/service/object_info"
+	questioncommon "answer/internal/service/question_common"
+	"answer/internal/service/rank"
+	"answer/internal/service/revision_common"
+	"answer/internal/service/siteinfo_common"
+	"answer/internal/service/tag_alert_queue"
+	tagcommon "answer/internal/service/tag_common"
+	"answer/internal/service/tag_moderator"
+	usercommon "answer/internal/service/user_common"
+	"answer/pkg/htmltext"
+	"answer/pkg/obj"
+
+	"github.com/jinzhu/copier"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// linkRegexp the links in the plain text of the post
+var linkRegexp = regexp.MustCompile(`(?i)(https?|ftp)://\S+`)
+
+// ReviewRepo review repository
+type ReviewRepo interface {
+	AddReview(ctx context.Context, review *entity.Review) (err error)
+	GetReview(ctx context.Context, id string) (review *entity.Review, exist bool, err error)
+	GetPendingReviewByObjectID(ctx context.Context, objectID string) (review *entity.Review, exist bool, err error)
+	CompleteReview(ctx context.Context, id string, status int, reviewerID, action string) (completed bool, err error)
+	GetReviewPage(ctx context.Context, queue, reviewerID string, objectTypes []int, tagIDs []string,
+		page, pageSize int) (reviews []*entity.Review, total int64, err error)
+	CountPendingReviews(ctx context.Context, reviewerID string, objectTypes []int, tagIDs []string) (
+		counts map[string]int64, err error)
+	AddReviewSkip(ctx context.Context, reviewID, userID string) (err error)
+}
+
+// ReviewService review queue service
+type ReviewService struct {
+	reviewRepo          ReviewRepo
+	userRepo            usercommon.UserRepo
+	userCommon          *usercommon.UserCommon
+	questionRepo        questioncommon.QuestionRepo
+	answerRepo          answercommon.AnswerRepo
+	questionCommon      *questioncommon.QuestionCommon
+	siteInfoService     *siteinfo_common.SiteInfoCommonService
+	rankService         *rank.RankService
+	objectInfoService   *object_info.ObjService
+	tagModeratorService *tag_moderator.TagModeratorService
+	mentionService      *mention.MentionService
+	revisionService     *revision_common.RevisionService
+	tagCommon           *tagcommon.TagCommonService
+}
+
+// NewReviewService new review service
+func NewReviewService(
+	reviewRepo ReviewRepo,
+	userRepo usercommon.UserRepo,
+	userCommon *usercommon.UserCommon,
+	questionRepo questioncommon.QuestionRepo,
+	answerRepo answercommon.AnswerRepo,
+	questionCommon *questioncommon.QuestionCommon,
+	siteInfoService *siteinfo_common.SiteInfoCommonService,
+	rankService *rank.RankService,
+	objectInfoService *object_info.ObjService,
+	tagModeratorService *tag_moderator.TagModeratorService,
+	mentionService *mention.MentionService,
+	revisionService *revision_common.RevisionService,
+	tagCommon *tagcommon.TagCommonService,
+) *ReviewService {
+	return &ReviewService{
+		reviewRepo:          reviewRepo,
+		userRepo:            userRepo,
+		userCommon:          userCommon,
+		questionRepo:        questionRepo,
+		answerRepo:          answerRepo,
+		questionCommon:      questionCommon,
+		siteInfoService:     siteInfoService,
+		rankService:         rankService,
+		objectInfoService:   objectInfoService,
+		tagModeratorService: tagModeratorService,
+		mentionService:      mentionService,
+		revisionService:     revisionService,
+		tagCommon:           tagCommon,
+	}
+}
+
+// CheckQuestion check which review queue the new question of the user should be put into,
+// hold is true if the question should be hidden until it is approved. The queue is empty if no review is needed.
+func (rs *ReviewService) CheckQuestion(ctx context.Context, userID, html string) (queue string, hold bool) {
+	setting, ok := rs.getReviewSetting(ctx)
+	if !ok {
+		return "", false
+	}
+	return rs.checkPost(ctx, setting, userID, html)
+}
+
+// CheckAnswer check which review queue the new answer of the user to the question should be put into,
+// hold is true if the answer should be hidden until it is approved. The queue is empty if no review is needed.
+func (rs *ReviewService) CheckAnswer(ctx context.Context, userID, html string, question *entity.Question) (
+	queue string, hold bool) {
+	setting, ok := rs.getReviewSetting(ctx)
+	if !ok {
+		return "", false
+	}
+	if queue, hold = rs.checkPost(ctx, setting, userID, html); len(queue) > 0 {
+		return queue, hold
+	}
+	if setting.LateAnswersEnabled && question != nil &&
+		time.Since(question.CreatedAt) > time.Duration(setting.LateAnswerDays)*24*time.Hour {
+		return entity.ReviewQueueLateAnswer, false
+	}
+	return "", false
+}
+
+// AddReview put the post into the review queue
+func (rs *ReviewService) AddReview(ctx context.Context, queue, objectID, userID string, hold bool) (err error) {
+	objectType, err := obj.GetObjectTypeStrByObjectID(objectID)
+	if err != nil {
+		return err
+	}
+	_, exist, err := rs.reviewRepo.GetPendingReviewByObjectID(ctx, objectID)
+	if err != nil || exist {
+		return err
+	}
+	return rs.reviewRepo.AddReview(ctx, &entity.Review{
+		Queue:      queue,
+		ObjectType: constant.ObjectTypeStrMapping[objectType],
+		ObjectID:   objectID,
+		UserID:     userID,
+		ReviewerID: "0",
+		Hold:       hold,
+		Status:     entity.ReviewStatusPending,
+	})
+}
+
+// AddReopenReview put the closed question into the reopen votes queue, if the queue is enabled
+func (rs *ReviewService) AddReopenReview(ctx context.Context, questionID, userID string) (err error) {
+	setting, ok := rs.getReviewSetting(ctx)
+	if !ok || !setting.ReopenVotesEnabled {
+		return nil
+	}
+	return rs.AddReview(ctx, entity.ReviewQueueReopenVote, questionID, userID, false)
+}
+
+// GetReviewQueues get all the review queues with the number of the pending reviews
+func (rs *ReviewService) GetReviewQueues(ctx context.Context, req *schema.GetReviewQueuesReq) (
+	resp []*schema.GetReviewQueueResp, err error) {
+	counts := make(map[string]int64)
+	objectTypes := req.GetCanReviewObjectTypes()
+	tagIDs, err := rs.tagModeratorService.GetModeratedTagIDs(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(objectTypes) > 0 || len(tagIDs) > 0 {
+		counts, err = rs.reviewRepo.CountPendingReviews(ctx, req.UserID, objectTypes, tagIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	resp = make([]*schema.GetReviewQueueResp, 0, len(entity.ReviewQueues))
+	for _, queue := range entity.ReviewQueues {
+		resp = append(resp, &schema.GetReviewQueueResp{Queue: queue, Count: counts[queue]})
+	}
+	return resp, nil
+}
+
+// GetReviewPage get the pending reviews of the queue
+func (rs *ReviewService) GetReviewPage(ctx context.Context, req *schema.GetReviewPageReq) (
+	resp *pager.PageModel, err error) {
+	list := make([]*schema.GetReviewResp, 0)
+	objectTypes := req.GetCanReviewObjectTypes()
+	tagIDs, err := rs.tagModeratorService.GetModeratedTagIDs(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(objectTypes) == 0 && len(tagIDs) == 0 {
+		return pager.NewPageModel(0, list), nil
+	}
+	reviews, total, err := rs.reviewRepo.GetReviewPage(ctx, req.Queue, req.UserID, objectTypes, tagIDs,
+		req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]string, 0, len(reviews))
+	for _, review := range reviews {
+		userIDs = append(userIDs, review.UserID)
+	}
+	userInfoMapping, err := rs.userCommon.BatchUserBasicInfoByID(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, review := range reviews {
+		objInfo, err := rs.objectInfoService.GetInfo(ctx, review.ObjectID)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		list = append(list, &schema.GetReviewResp{
+			ID:         review.ID,
+			Queue:      review.Queue,
+			ObjectType: objInfo.ObjectType,
+			ObjectID:   review.ObjectID,
+			QuestionID: objInfo.QuestionID,
+			Title:      objInfo.Title,
+			Content:    objInfo.Content,
+			Hold:       review.Hold,
+			CreatedAt:  review.CreatedAt.Unix(),
+			UserInfo:   userInfoMapping[review.UserID],
+		})
+	}
+	return pager.NewPageModel(total, list), nil
+}
+
+// Review handle the pending review with the action of the reviewer
+func (rs *ReviewService) Review(ctx context.Context, req *schema.ReviewReq) (err error) {
+	review, exist, err := rs.reviewRepo.GetReview(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.ReviewNotFound)
+	}
+	if review.Status != entity.ReviewStatusPending {
+		return errors.BadRequest(reason.ReviewCompleted)
+	}
+	if review.UserID == req.UserID {
+		return errors.BadRequest(reason.ReviewNoPermission)
+	}
+	canReview := canReviewObjectType(req.GetCanReviewObjectTypes(), review.ObjectType)
+	// the moderators of the tags review the questions with the tags
+	if !canReview && constant.ObjectTypeNumberMapping[review.ObjectType] == constant.QuestionObjectType {
+		canReview, err = rs.tagModeratorService.IsQuestionTagModerator(ctx, req.UserID, review.ObjectID)
+		if err != nil {
+			return err
+		}
+	}
+	if !canReview {
+		return errors.BadRequest(reason.ReviewNoPermission)
+	}
+	if req.Action == schema.ReviewActionSkip {
+		return rs.reviewRepo.AddReviewSkip(ctx, review.ID, req.UserID)
+	}
+	// only the held post is edited in the review, the others are edited as usual
+	if req.Action == schema.ReviewActionEdit && (!review.Hold || review.Queue == entity.ReviewQueueReopenVote) {
+		return errors.BadRequest(reason.ReviewCannotEdit)
+	}
+
+	status := entity.ReviewStatusApproved
+	if req.Action == schema.ReviewActionReject {
+		status = entity.ReviewStatusRejected
+	}
+	completed, err := rs.reviewRepo.CompleteReview(ctx, review.ID, status, req.UserID, req.Action)
+	if err != nil {
+		return err
+	}
+	if !completed {
+		return errors.BadRequest(reason.ReviewCompleted)
+	}
+
+	var editActivity *schema.ActivityMsg
+	if req.Action == schema.ReviewActionEdit {
+		editActivity, err = rs.editPost(ctx, review, req)
+		if err != nil {
+			return err
+		}
+	}
+	if status == entity.ReviewStatusApproved {
+		err = rs.approvePost(ctx, review, req.UserID)
+	} else {
+		err = rs.rejectPost(ctx, review)
+	}
+	if err != nil {
+		return err
+	}
+	// the edit is shown in the timeline after the post is published
+	if editActivity != nil {
+		activity_queue.AddActivity(editActivity)
+	}
+
+	// the reviewer is rewarded for any completed review, the rejection is as useful as the approval,
+	// and rewarding only the approvals would push the reviewers to approve the bad posts
+	if err = rs.rankService.TriggerReviewRank(ctx, req.UserID, review.ObjectID); err != nil {
+		log.Errorf("reward reviewer %s failed: %s", req.UserID, err)
+	}
+	return nil
+}
+
+// approvePost show the held post, or reopen the question in the reopen votes queue
+func (rs *ReviewService) approvePost(ctx context.Context, review *entity.Review, reviewerID string) (err error) {
+	if review.Queue == entity.ReviewQueueReopenVote {
+		questionInfo, exist, err := rs.questionRepo.GetQuestion(ctx, review.ObjectID)
+		if err != nil || !exist || questionInfo.Status != entity.QuestionStatusClosed {
+			return err
+		}
+		err = rs.questionRepo.UpdateQuestionStatus(ctx, &entity.Question{
+			ID: questionInfo.ID, Status: entity.QuestionStatusAvailable})
+		if err != nil {
+			return err
+		}
+		activity_queue.AddActivity(&schema.ActivityMsg{
+			UserID:           reviewerID,
+			ObjectID:         questionInfo.ID,
+			OriginalObjectID: questionInfo.ID,
+			ActivityTypeKey:  constant.ActQuestionReopened,
+		})
+		return nil
+	}
+	if !review.Hold {
+		return nil
+	}
+
+	switch constant.ObjectTypeNumberMapping[review.ObjectType] {
+	case constant.QuestionObjectType:
+		questionInfo, exist, err := rs.questionRepo.GetQuestion(ctx, review.ObjectID)
+		if err != nil || !exist || questionInfo.Status != entity.QuestionStatusPending {
+			return err
+		}
+		questionInfo.Status = entity.QuestionStatusAvailable
+		if err = rs.questionRepo.UpdateQuestionStatus(ctx, questionInfo); err != nil {
+			return err
+		}
+		if err = rs.PublishQuestion(ctx, questionInfo, rs.resolveMentions(ctx, questionInfo.OriginalText)); err != nil {
+			return err
+		}
+		// the answer posted with the question is held together with it
+		selfAnswer, exist, err := rs.getPendingSelfAnswer(ctx, questionInfo)
+		if err != nil || !exist {
+			return err
+		}
+		return rs.approveAnswer(ctx, selfAnswer, questionInfo)
+	case constant.AnswerObjectType:
+		answerInfo, exist, err := rs.answerRepo.GetByID(ctx, review.ObjectID)
+		if err != nil || !exist || answerInfo.Status != entity.AnswerStatusPending {
+			return err
+		}
+		questionInfo, exist, err := rs.questionRepo.GetQuestion(ctx, answerInfo.QuestionID)
+		if err != nil || !exist {
+			return err
+		}
+		return rs.approveAnswer(ctx, answerInfo, questionInfo)
+	}
+	return nil
+}
+
+// editPost save the content edited by the reviewer as a new revision of the held post, which is approved then.
+// The activity of the edit is returned to be added once the post is published.
+func (rs *ReviewService) editPost(ctx context.Context, review *entity.Review, req *schema.ReviewReq) (
+	activity *schema.ActivityMsg, err error) {
+	html, _, err := rs.mentionService.Resolve(ctx, req.Content, req.HTML, "")
+	if err != nil {
+		return nil, err
+	}
+	revisionDTO := &schema.AddRevisionDTO{
+		UserID:   req.UserID,
+		ObjectID: review.ObjectID,
+		Status:   entity.RevisionReviewPassStatus,
+		Log:      "edited in the review",
+	}
+	activity = &schema.ActivityMsg{
+		UserID:           req.UserID,
+		ObjectID:         review.ObjectID,
+		OriginalObjectID: review.ObjectID,
+	}
+	now := time.Now()
+
+	switch constant.ObjectTypeNumberMapping[review.ObjectType] {
+	case constant.QuestionObjectType:
+		questionInfo, exist, err := rs.questionRepo.GetQuestion(ctx, review.ObjectID)
+		if err != nil {
+			return nil, err
+		}
+		if !exist || questionInfo.Status != entity.QuestionStatusPending {
+			return nil, errors.BadRequest(reason.ReviewCannotEdit)
+		}
+		if len(req.Title) > 0 {
+			questionInfo.Title = req.Title
+		}
+		questionInfo.OriginalText = req.Content
+		questionInfo.ParsedText = html
+		questionInfo.LastEditUserID = req.UserID
+		questionInfo.UpdatedAt = now
+		questionInfo.PostUpdateTime = now
+		err = rs.questionRepo.UpdateQuestion(ctx, questionInfo,
+			[]string{"title", "original_text", "parsed_text", "updated_at", "post_update_time", "last_edit_user_id"})
+		if err != nil {
+			return nil, err
+		}
+		tags, err := rs.tagCommon.GetObjectEntityTag(ctx, questionInfo.ID)
+		if err != nil {
+			return nil, err
+		}
+		questionRevision := &entity.QuestionWithTagsRevision{Question: *questionInfo}
+		for _, tag := range tags {
+			item := &entity.TagSimpleInfoForRevision{}
+			_ = copier.Copy(item, tag)
+			questionRevision.Tags = append(questionRevision.Tags, item)
+		}
+		infoJSON, _ := json.Marshal(questionRevision)
+		revisionDTO.Title = questionInfo.Title
+		revisionDTO.Content = string(infoJSON)
+		activity.ActivityTypeKey = constant.ActQuestionEdited
+	case constant.AnswerObjectType:
+		answerInfo, exist, err := rs.answerRepo.GetByID(ctx, review.ObjectID)
+		if err != nil {
+			return nil, err
+		}
+		if !exist || answerInfo.Status != entity.AnswerStatusPending {
+			return nil, errors.BadRequest(reason.ReviewCannotEdit)
+		}
+		answerInfo.OriginalText = req.Content
+		answerInfo.ParsedText = html
+		answerInfo.LastEditUserID = req.UserID
+		answerInfo.UpdatedAt = now
+		err = rs.answerRepo.UpdateAnswer(ctx, answerInfo,
+			[]string{"original_text", "parsed_text", "updated_at", "last_edit_user_id"})
+		if err != nil {
+			return nil, err
+		}
+		infoJSON, _ := json.Marshal(answerInfo)
+		revisionDTO.Content = string(infoJSON)
+		activity.ActivityTypeKey = constant.ActAnswerEdited
+	default:
+		return nil, errors.BadRequest(reason.ReviewCannotEdit)
+	}
+
+	activity.RevisionID, err = rs.revisionService.AddRevision(ctx, revisionDTO, true)
+	if err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+// approveAnswer show the pending answer and publish it
+func (rs *ReviewService) approveAnswer(ctx context.Context, answerInfo *entity.Answer, questionInfo *entity.Question) (
+	err error) {
+	answerInfo.Status = entity.AnswerStatusAvailable
+	if err = rs.answerRepo.UpdateAnswerStatus(ctx, answerInfo); err != nil {
+		return err
+	}
+	return rs.PublishAnswer(ctx, answerInfo, questionInfo, rs.resolveMentions(ctx, answerInfo.OriginalText))
+}
+
+// getPendingSelfAnswer get the pending answer which the author posted with the held question
+func (rs *ReviewService) getPendingSelfAnswer(ctx context.Context, questionInfo *entity.Question) (
+	answerInfo *entity.Answer, exist bool, err error) {
+	answerInfo, exist, err = rs.answerRepo.GetByUserIDQuestionID(ctx, questionInfo.UserID, questionInfo.ID)
+	if err != nil || !exist {
+		return nil, false, err
+	}
+	return answerInfo, answerInfo.Status == entity.AnswerStatusPending, nil
+}
+
+// PublishQuestion apply the side effects of the question which is shown to everyone: the question count of the
+// author, the activity, and the notifications of the mentioned users and the tag watchers.
+// The held question is published once it is approved, so that nothing of it leaks before.
+func (rs *ReviewService) PublishQuestion(ctx context.Context, questionInfo *entity.Question,
+	mentionUserIDs []string) (err error) {
+	if err = rs.userCommon.UpdateQuestionCount(ctx, questionInfo.UserID, 1); err != nil {
+		log.Error("user IncreaseQuestionCount error", err.Error())
+	}
+	activity_queue.AddActivity(&schema.ActivityMsg{
+		UserID:           questionInfo.UserID,
+		ObjectID:         questionInfo.ID,
+		OriginalObjectID: questionInfo.ID,
+		ActivityTypeKey:  constant.ActQuestionAsked,
+		RevisionID:       questionInfo.RevisionID,
+	})
+	rs.mentionService.Notify(mentionUserIDs, questionInfo.UserID, questionInfo.ID, constant.QuestionObjectType)
+	tag_alert_queue.AddQuestion(questionInfo.ID)
+	return nil
+}
+
+// PublishAnswer apply the side effects of the answer which is shown to everyone: the answer count and the last
+// answer of the question, the answer count of the author, the activities and the notifications.
+// The held answer is published once it is approved, so that nothing of it leaks before.
+func (rs *ReviewService) PublishAnswer(ctx context.Context, answerInfo *entity.Answer, questionInfo *entity.Question,
+	mentionUserIDs []string) (err error) {
+	if err = rs.CountAnswer(ctx, answerInfo); err != nil {
+		return err
+	}
+	rs.NotifyAnswer(answerInfo, questionInfo, mentionUserIDs)
+	return nil
+}
+
+// CountAnswer update the answer count and the last answer of the question, and the answer count of the author
+func (rs *ReviewService) CountAnswer(ctx context.Context, answerInfo *entity.Answer) (err error) {
+	if err = rs.questionCommon.UpdateAnswerCount(ctx, answerInfo.QuestionID, 1); err != nil {
+		log.Error("IncreaseAnswerCount error", err.Error())
+	}
+	if err = rs.questionCommon.UpdateLastAnswer(ctx, answerInfo.QuestionID, answerInfo.ID); err != nil {
+		log.Error("UpdateLastAnswer error", err.Error())
+	}
+	if err = rs.questionCommon.UpdataPostTime(ctx, answerInfo.QuestionID); err != nil {
+		return err
+	}
+	if err = rs.userCommon.UpdateAnswerCount(ctx, answerInfo.UserID, 1); err != nil {
+		log.Error("user IncreaseAnswerCount error", err.Error())
+	}
+	return nil
+}
+
+// NotifyAnswer add the activities of the answer, and notify the question author and the mentioned users
+func (rs *ReviewService) NotifyAnswer(answerInfo *entity.Answer, questionInfo *entity.Question, mentionUserIDs []string) {
+	// the question author is not notified of the answer by self
+	if questionInfo.UserID != answerInfo.UserID {
+		notice_queue.AddNotification(&schema.NotificationMsg{
+			TriggerUserID:      answerInfo.UserID,
+			ReceiverUserID:     questionInfo.UserID,
+			Type:               schema.NotificationTypeInbox,
+			ObjectID:           answerInfo.ID,
+			ObjectType:         constant.AnswerObjectType,
+			NotificationAction: constant.AnswerTheQuestion,
+		})
+	}
+	rs.mentionService.Notify(mentionUserIDs, answerInfo.UserID, answerInfo.ID, constant.AnswerObjectType)
+
+	activity_queue.AddActivity(&schema.ActivityMsg{
+		UserID:           answerInfo.UserID,
+		ObjectID:         answerInfo.ID,
+		OriginalObjectID: answerInfo.ID,
+		ActivityTypeKey:  constant.ActAnswerAnswered,
+		RevisionID:       answerInfo.RevisionID,
+	})
+	activity_queue.AddActivity(&schema.ActivityMsg{
+		UserID:           answerInfo.UserID,
+		ObjectID:         answerInfo.ID,
+		OriginalObjectID: questionInfo.ID,
+		ActivityTypeKey:  constant.ActQuestionAnswered,
+	})
+}
+
+// resolveMentions find the users mentioned in the approved post, the failure only skips the notifications
+func (rs *ReviewService) resolveMentions(ctx context.Context, content string) (userIDs []string) {
+	_, userIDs, err := rs.mentionService.Resolve(ctx, content, "", "")
+	if err != nil {
+		log.Error(err)
+	}
+	return userIDs
+}
+
+// rejectPost delete the post, the closed question in the reopen votes queue stays closed.
+// The held post is never published, so only its status is changed and no count is taken back.
+func (rs *ReviewService) rejectPost(ctx context.Context, review *entity.Review) (err error) {
+	if review.Queue == entity.ReviewQueueReopenVote {
+		return nil
+	}
+	switch constant.ObjectTypeNumberMapping[review.ObjectType] {
+	case constant.QuestionObjectType:
+		if review.Hold {
+			questionInfo, exist, err := rs.questionRepo.GetQuestion(ctx, review.ObjectID)
+			if err != nil || !exist {
+				return err
+			}
+			selfAnswer, exist, err := rs.getPendingSelfAnswer(ctx, questionInfo)
+			if err != nil {
+				return err
+			}
+			if exist {
+				selfAnswer.Status = entity.AnswerStatusDeleted
+				if err = rs.answerRepo.UpdateAnswerStatus(ctx, selfAnswer); err != nil {
+					return err
+				}
+			}
+			questionInfo.Status = entity.QuestionStatusDeleted
+			return rs.questionRepo.UpdateQuestionStatus(ctx, questionInfo)
+		}
+		return rs.questionCommon.RemoveQuestion(ctx, &schema.RemoveQuestionReq{ID: review.ObjectID})
+	case constant.AnswerObjectType:
+		if review.Hold {
+			return rs.answerRepo.UpdateAnswerStatus(ctx, &entity.Answer{
+				ID: review.ObjectID, Status: entity.AnswerStatusDeleted})
+		}
+		return rs.questionCommon.RemoveAnswer(ctx, review.ObjectID)
+	}
+	return nil
+}
+
+// checkPost check whether the post should be held in the first posts queue or the low quality queue
+func (rs *ReviewService) checkPost(ctx context.Context, setting *schema.SiteReviewResp, userID, html string) (
+	queue string, hold bool) {
+	userInfo, exist, err := rs.userRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		log.Error(err)
+		return "", false
+	}
+	if !exist || userInfo.IsAdmin {
+		return "", false
+	}
+	if setting.FirstPostsEnabled && userInfo.QuestionCount+userInfo.AnswerCount < setting.FirstPostsCount {
+		return entity.ReviewQueueFirstPost, true
+	}
+	if setting.LowReputation > 0 && userInfo.Rank < setting.LowReputation {
+		return entity.ReviewQueueFirstPost, true
+	}
+	if setting.LowQualityEnabled && isLowQuality(html, setting.LowQualityMinLength) {
+		return entity.ReviewQueueLowQuality, true
+	}
+	return "", false
+}
+
+func (rs *ReviewService) getReviewSetting(ctx context.Context) (setting *schema.SiteReviewResp, ok bool) {
+	setting, err := rs.siteInfoService.GetSiteReview(ctx)
+	if err != nil {
+		log.Error(err)
+		return nil, false
+	}
+	return setting, true
+}
+
+// isLowQuality the post is low quality if the text without links is shorter than the min length, or it only contains links
+func isLowQuality(html string, minLength int) bool {
+	text := strings.TrimSpace(htmltext.ClearText(html))
+	withoutLinks := strings.TrimSpace(linkRegexp.ReplaceAllString(text, ""))
+	if len(withoutLinks) == 0 && len(text) > 0 {
+		return true
+	}
+	return len([]rune(withoutLinks)) < minLength
+}
+
+func canReviewObjectType(objectTypes []int, objectType int) bool {
+	for _, t := range objectTypes {
+		if t == objectType {
+			return true
+		}
+	}
+	return false
+}
@@ -67,6 +67,12 @@ func (rs *RevisionService) GetRevision(ctx context.Context, revisionID string) (
 	return revisionInfo, nil
 }
 
+// ExistRevisionByObjectID whether the object has any revision
+func (rs *RevisionService) ExistRevisionByObjectID(ctx context.Context, objectID string) (exist bool, err error) {
+	_, exist, err = rs.revisionRepo.GetLastRevisionByObjectID(ctx, objectID)
+	return exist, err
+}
+
 // ExistUnreviewedByObjectID
 func (rs *RevisionService) ExistUnreviewedByObjectID(ctx context.Context, objectID string) (revision *entity.Revision, exist bool, err error) {
 	revision, exist, err = rs.revisionRepo.ExistUnreviewedByObjectID(ctx, objectID)
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"sort"
 	"time"
 
 	"answer/internal/base/constant"
@@ -12,6 +13,7 @@ import (
 	"answer/internal/schema"
 	"answer/internal/service/activity_queue"
 	answercommon "answer/internal/service/answer_common"
+	"answer/internal/service/mention"
 	"answer/internal/service/notice_queue"
 	"answer/internal/service/object_info"
 	questioncommon "answer/internal/service/question_common"
@@ -38,6 +40,7 @@ type RevisionService struct {
 	answerRepo        answercommon.AnswerRepo
 	tagRepo           tag_common.TagRepo
 	tagCommon         *tagcommon.TagCommonService
+	mentionService    *mention.MentionService
 }
 
 func NewRevisionService(
@@ -50,6 +53,7 @@ func NewRevisionService(
 	answerRepo answercommon.AnswerRepo,
 	tagRepo tag_common.TagRepo,
 	tagCommon *tagcommon.TagCommonService,
+	mentionService *mention.MentionService,
 ) *RevisionService {
 	return &RevisionService{
 		revisionRepo:      revisionRepo,
@@ -61,6 +65,7 @@ func NewRevisionService(
 		answerRepo:        answerRepo,
 		tagRepo:           tagRepo,
 		tagCommon:         tagCommon,
+		mentionService:    mentionService,
 	}
 }
 
@@ -131,15 +136,17 @@ func (rs *RevisionService) revisionAuditQuestion(ctx context.Context, revisionit
 		if dbquestion.PostUpdateTime.Unix() > PostUpdateTime.Unix() {
 			PostUpdateTime = dbquestion.PostUpdateTime
 		}
+		mentionUserIDs := rs.resolveMentions(ctx, questioninfo.Content, dbquestion.OriginalText)
 		question := &entity.Question{}
 		question.ID = questioninfo.ID
 		question.Title = questioninfo.Title
 		question.OriginalText = questioninfo.Content
 		question.ParsedText = questioninfo.HTML
+		question.Language = questioninfo.Language
 		question.UpdatedAt = time.Unix(questioninfo.UpdateTime, 0)
 		question.PostUpdateTime = PostUpdateTime
 		question.LastEditUserID = revisionitem.UserID
-		saveerr := rs.questionRepo.UpdateQuestion(ctx, question, []string{"title", "original_text", "parsed_text", "updated_at", "post_update_time", "last_edit_user_id"})
+		saveerr := rs.questionRepo.UpdateQuestion(ctx, question, []string{"title", "original_text", "parsed_text", "language", "updated_at", "post_update_time", "last_edit_user_id"})
 		if saveerr != nil {
 			return saveerr
 		}
@@ -163,6 +170,7 @@ func (rs *RevisionService) revisionAuditQuestion(ctx context.Context, revisionit
 			RevisionID:       revisionitem.ID,
 			OriginalObjectID: revisionitem.ObjectID,
 		})
+		rs.mentionService.Notify(mentionUserIDs, revisionitem.UserID, question.ID, constant.QuestionObjectType)
 	}
 	return nil
 }
@@ -182,13 +190,20 @@ func (rs *RevisionService) revisionAuditAnswer(ctx context.Context, revisionitem
 			PostUpdateTime = dbquestion.PostUpdateTime
 		}
 
+		dbanswer, exist, dberr := rs.answerRepo.GetAnswer(ctx, answerinfo.ID)
+		if dberr != nil || !exist {
+			return
+		}
+		mentionUserIDs := rs.resolveMentions(ctx, answerinfo.Content, dbanswer.OriginalText)
+
 		insertData := new(entity.Answer)
 		insertData.ID = answerinfo.ID
 		insertData.OriginalText = answerinfo.Content
 		insertData.ParsedText = answerinfo.HTML
+		insertData.Language = answerinfo.Language
 		insertData.UpdatedAt = time.Unix(answerinfo.UpdateTime, 0)
 		insertData.LastEditUserID = revisionitem.UserID
-		saveerr := rs.answerRepo.UpdateAnswer(ctx, insertData, []string{"original_text", "parsed_text", "updated_at", "last_edit_user_id"})
+		saveerr := rs.answerRepo.UpdateAnswer(ctx, insertData, []string{"original_text", "parsed_text", "language", "updated_at", "last_edit_user_id"})
 		if saveerr != nil {
 			return saveerr
 		}
@@ -220,10 +235,20 @@ func (rs *RevisionService) revisionAuditAnswer(ctx context.Context, revisionitem
 			ActivityTypeKey:  constant.ActAnswerEdited,
 			RevisionID:       revisionitem.ID,
 		})
+		rs.mentionService.Notify(mentionUserIDs, revisionitem.UserID, insertData.ID, constant.AnswerObjectType)
 	}
 	return nil
 }
 
+// resolveMentions find the users newly mentioned by the approved edit, the failure only skips the notifications
+func (rs *RevisionService) resolveMentions(ctx context.Context, content, previousContent string) (userIDs []string) {
+	_, userIDs, err := rs.mentionService.Resolve(ctx, content, "", previousContent)
+	if err != nil {
+		log.Error(err)
+	}
+	return userIDs
+}
+
 func (rs *RevisionService) revisionAuditTag(ctx context.Context, revisionitem *schema.GetRevisionResp) (err error) {
 	taginfo, ok := revisionitem.ContentParsed.(*schema.GetTagResp)
 	if ok {
@@ -352,6 +377,55 @@ func (rs *RevisionService) GetRevisionList(ctx context.Context, req *schema.GetR
 	return
 }
 
+// GetContributors get the users who contribute to the post, the one with the most revisions first.
+// The unreviewed and rejected revisions are not contributions.
+func (rs *RevisionService) GetContributors(ctx context.Context, req *schema.GetContributorListReq) (
+	resp []*schema.ContributorInfo, err error) {
+	revs, err := rs.revisionRepo.GetRevisionList(ctx, &entity.Revision{ObjectID: req.ObjectID})
+	if err != nil {
+		return nil, err
+	}
+	contributors := make(map[string]*schema.ContributorInfo)
+	userIDs := make([]string, 0)
+	for _, r := range revs {
+		if r.Status == entity.RevisionUnreviewedStatus || r.Status == entity.RevisionReviewRejectStatus {
+			continue
+		}
+		contributor, ok := contributors[r.UserID]
+		if !ok {
+			contributor = &schema.ContributorInfo{}
+			contributors[r.UserID] = contributor
+			userIDs = append(userIDs, r.UserID)
+		}
+		contributor.EditCount++
+		if r.CreatedAt.Unix() > contributor.LastEditAt {
+			contributor.LastEditAt = r.CreatedAt.Unix()
+		}
+	}
+	userInfoMapping, err := rs.userCommon.BatchUserBasicInfoByID(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = make([]*schema.ContributorInfo, 0, len(userIDs))
+	for _, userID := range userIDs {
+		userInfo, ok := userInfoMapping[userID]
+		if !ok {
+			continue
+		}
+		contributor := contributors[userID]
+		contributor.UserInfo = userInfo
+		resp = append(resp, contributor)
+	}
+	sort.SliceStable(resp, func(i, j int) bool {
+		if resp[i].EditCount != resp[j].EditCount {
+			return resp[i].EditCount > resp[j].EditCount
+		}
+		return resp[i].LastEditAt > resp[j].LastEditAt
+	})
+	return resp, nil
+}
+
 func (rs *RevisionService) parseItem(ctx context.Context, item *schema.GetRevisionResp) {
 	var (
 		err          error
@@ -361,6 +435,8 @@ func (rs *RevisionService) parseItem(ctx context.Context, item *schema.GetRevisi
 		answerInfo   *schema.AnswerInfo
 		tag          entity.Tag
 		tagInfo      *schema.GetTagResp
+		comment      entity.Comment
+		commentInfo  *schema.GetCommentResp
 	)
 
 	switch item.ObjectType {
@@ -398,6 +474,14 @@ func (rs *RevisionService) parseItem(ctx context.Context, item *schema.GetRevisi
 		}
 		tagInfo.GetExcerpt()
 		item.ContentParsed = tagInfo
+	case constant.ObjectTypeStrMapping["comment"]:
+		err = json.Unmarshal([]byte(item.Content), &comment)
+		if err != nil {
+			break
+		}
+		commentInfo = &schema.GetCommentResp{}
+		commentInfo.SetFromComment(&comment)
+		item.ContentParsed = commentInfo
 	}
 
 	if err != nil {
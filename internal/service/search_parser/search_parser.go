@@ -9,6 +9,7 @@ import (
 	"answer/internal/service/tag_common"
 	usercommon "answer/internal/service/user_common"
 	"answer/pkg/converter"
+	"answer/pkg/langdetect"
 )
 
 type SearchParser struct {
@@ -42,6 +43,7 @@ func (sp *SearchParser) ParseStructure(dto *schema.SearchDTO) (
 	// common fields
 	tags,
 	words []string,
+	language string,
 ) {
 	var (
 		query         = dto.Query
@@ -55,6 +57,7 @@ func (sp *SearchParser) ParseStructure(dto *schema.SearchDTO) (
 
 	// match tags
 	tags = sp.parseTags(&query)
+	language = sp.parseLanguage(&query)
 
 	// match all
 	userID = sp.parseUserID(&query, currentUserID)
@@ -179,6 +182,24 @@ func (sp *SearchParser) parseTags(query *string) (tags []string) {
 	return
 }
 
+// parseLanguage parse the language code of the content like: lang:zh
+func (sp *SearchParser) parseLanguage(query *string) (language string) {
+	var (
+		expr = `(?m)(^|\s)lang:([a-zA-Z_-]+)`
+		q    = *query
+	)
+
+	re := regexp.MustCompile(expr)
+	res := re.FindStringSubmatch(q)
+	if len(res) == 3 {
+		language = langdetect.Normalize(res[2])
+		q = re.ReplaceAllString(q, "")
+	}
+
+	*query = strings.TrimSpace(q)
+	return
+}
+
 // parseUserID return user id or current login user id
 func (sp *SearchParser) parseUserID(query *string, currentUserID string) (userID string) {
 	var (
@@ -4,21 +4,26 @@ import (
 	"answer/internal/schema"
 	"answer/internal/service/search_common"
 	"answer/internal/service/search_parser"
+	usercommon "answer/internal/service/user_common"
+	"answer/pkg/langdetect"
 	"context"
 )
 
 type SearchService struct {
 	searchParser *search_parser.SearchParser
 	searchRepo   search_common.SearchRepo
+	userCommon   *usercommon.UserCommon
 }
 
 func NewSearchService(
 	searchParser *search_parser.SearchParser,
 	searchRepo search_common.SearchRepo,
+	userCommon *usercommon.UserCommon,
 ) *SearchService {
 	return &SearchService{
 		searchParser: searchParser,
 		searchRepo:   searchRepo,
+		userCommon:   userCommon,
 	}
 }
 
@@ -45,18 +50,44 @@ func (ss *SearchService) Search(ctx context.Context, dto *schema.SearchDTO) (res
 		_,
 		// common fields
 		tags,
-		words := ss.searchParser.ParseStructure(dto)
+		words,
+		language := ss.searchParser.ParseStructure(dto)
+
+	languages, err := ss.searchLanguages(ctx, dto, language)
+	if err != nil {
+		return nil, 0, nil, err
+	}
 
 	switch searchType {
 	case "all":
-		resp, total, err = ss.searchRepo.SearchContents(ctx, words, tags, userID, votes, dto.Page, dto.Size, dto.Order)
+		resp, total, err = ss.searchRepo.SearchContents(ctx, words, tags, userID, votes, languages, dto.Page, dto.Size, dto.Order)
 		if err != nil {
 			return nil, 0, nil, err
 		}
 	case "question":
-		resp, total, err = ss.searchRepo.SearchQuestions(ctx, words, notAccepted, views, answers, dto.Page, dto.Size, dto.Order)
+		resp, total, err = ss.searchRepo.SearchQuestions(ctx, words, notAccepted, views, answers, languages, dto.Page, dto.Size, dto.Order)
 	case "answer":
-		resp, total, err = ss.searchRepo.SearchAnswers(ctx, words, tags, accepted, questionID, dto.Page, dto.Size, dto.Order)
+		resp, total, err = ss.searchRepo.SearchAnswers(ctx, words, tags, accepted, questionID, languages, dto.Page, dto.Size, dto.Order)
 	}
 	return
 }
+
+// searchLanguages get the languages of the content to search, the language in the query like lang:zh comes first,
+// then the language of the request, at last the content languages of the login user
+func (ss *SearchService) searchLanguages(ctx context.Context, dto *schema.SearchDTO, queryLanguage string) (
+	languages []string, err error) {
+	switch {
+	case len(queryLanguage) > 0:
+		return []string{queryLanguage}, nil
+	case dto.Language == schema.QuestionSearchLanguageAll:
+		return nil, nil
+	case len(dto.Language) > 0:
+		if language := langdetect.Normalize(dto.Language); len(language) > 0 {
+			return []string{language}, nil
+		}
+		return nil, nil
+	case len(dto.UserID) > 0:
+		return ss.userCommon.GetUserContentLanguages(ctx, dto.UserID)
+	}
+	return nil, nil
+}
@@ -0,0 +1,420 @@
+package seo
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"math"
+	"strings"
+	"time"
+
+	"answer/internal/base/constant"
+	"answer/internal/base/data"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/schema"
+	answercommon "answer/internal/service/answer_common"
+	questioncommon "answer/internal/service/question_common"
+	"answer/internal/service/siteinfo_common"
+	tagcommon "answer/internal/service/tag_common"
+	usercommon "answer/internal/service/user_common"
+	"answer/pkg/htmltext"
+
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+const (
+	// pageDescriptionLength the max length of the meta description
+	pageDescriptionLength = 160
+	// suggestedAnswerLimit the max number of suggested answers in QAPage structured data
+	suggestedAnswerLimit = 5
+)
+
+// defaultRobots used when the admin has not configured robots.txt
+const defaultRobots = `User-agent: *
+Disallow: /admin
+Disallow: /search
+Disallow: /install
+Disallow: /review
+Disallow: /users/login
+Disallow: /users/register
+Disallow: /users/account-recovery
+Disallow: /users/settings
+Disallow: /users/notifications
+Disallow: /*/edit
+`
+
+// privateRobots used when the site is login required, nothing should be crawled
+const privateRobots = `User-agent: *
+Disallow: /
+`
+
+// SeoService seo service, provides sitemap, robots and page metadata
+type SeoService struct {
+	data             *data.Data
+	siteInfoService  *siteinfo_common.SiteInfoCommonService
+	questionRepo     questioncommon.QuestionRepo
+	answerRepo       answercommon.AnswerRepo
+	tagCommonService *tagcommon.TagCommonService
+	userCommon       *usercommon.UserCommon
+}
+
+// NewSeoService new seo service
+func NewSeoService(
+	data *data.Data,
+	siteInfoService *siteinfo_common.SiteInfoCommonService,
+	questionRepo questioncommon.QuestionRepo,
+	answerRepo answercommon.AnswerRepo,
+	tagCommonService *tagcommon.TagCommonService,
+	userCommon *usercommon.UserCommon,
+) *SeoService {
+	return &SeoService{
+		data:             data,
+		siteInfoService:  siteInfoService,
+		questionRepo:     questionRepo,
+		answerRepo:       answerRepo,
+		tagCommonService: tagCommonService,
+		userCommon:       userCommon,
+	}
+}
+
+// GetSitemap get sitemap.xml content. If the number of questions exceeds the sitemap limit,
+// a sitemap index that points to each sitemap page is returned.
+func (ss *SeoService) GetSitemap(ctx context.Context) (content string, err error) {
+	cacheKey := constant.SitemapCacheKey + constant.SitemapIndexFileName
+	if content, err = ss.data.Cache.GetString(ctx, cacheKey); err == nil && len(content) > 0 {
+		return content, nil
+	}
+
+	siteURL := ss.getSiteURL(ctx)
+	questionCount, err := ss.questionRepo.GetQuestionCount(ctx)
+	if err != nil {
+		return "", err
+	}
+	if questionCount <= constant.SitemapMaxSize {
+		content, err = ss.buildSitemapPage(ctx, siteURL, 1)
+	} else {
+		content, err = ss.buildSitemapIndex(siteURL, questionCount)
+	}
+	if err != nil {
+		return "", err
+	}
+	ss.setSitemapCache(ctx, cacheKey, content)
+	return content, nil
+}
+
+// GetSitemapPage get the sitemap of the page, page starts from 1
+func (ss *SeoService) GetSitemapPage(ctx context.Context, page int) (content string, exist bool, err error) {
+	questionCount, err := ss.questionRepo.GetQuestionCount(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	if page < 1 || page > sitemapPageCount(questionCount) {
+		return "", false, nil
+	}
+
+	cacheKey := fmt.Sprintf("%s%s%d.xml", constant.SitemapCacheKey, constant.SitemapPageFilePrefix, page)
+	if content, err = ss.data.Cache.GetString(ctx, cacheKey); err == nil && len(content) > 0 {
+		return content, true, nil
+	}
+	content, err = ss.buildSitemapPage(ctx, ss.getSiteURL(ctx), page)
+	if err != nil {
+		return "", false, err
+	}
+	ss.setSitemapCache(ctx, cacheKey, content)
+	return content, true, nil
+}
+
+// IsLoginRequired whether the site is login required, the private site should not be indexed
+func (ss *SeoService) IsLoginRequired(ctx context.Context) bool {
+	siteLogin, err := ss.siteInfoService.GetSiteLogin(ctx)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	return siteLogin.LoginRequired
+}
+
+// GetRobots get robots.txt content
+func (ss *SeoService) GetRobots(ctx context.Context) (content string, err error) {
+	if ss.IsLoginRequired(ctx) {
+		return privateRobots, nil
+	}
+	seo, err := ss.siteInfoService.GetSiteSeo(ctx)
+	if err != nil {
+		log.Error(err)
+	}
+	if seo != nil && len(strings.TrimSpace(seo.Robots)) > 0 {
+		return seo.Robots, nil
+	}
+	content = defaultRobots
+	if siteURL := ss.getSiteURL(ctx); len(siteURL) > 0 {
+		content += fmt.Sprintf("\nSitemap: %s/%s\n", siteURL, constant.SitemapIndexFileName)
+	}
+	return content, nil
+}
+
+// GetDefaultPageMeta get the page metadata which used for pages that have no special metadata
+func (ss *SeoService) GetDefaultPageMeta(ctx context.Context) (meta *schema.PageMeta) {
+	meta = &schema.PageMeta{Type: "website"}
+	general, err := ss.siteInfoService.GetSiteGeneral(ctx)
+	if err != nil {
+		log.Error(err)
+		return meta
+	}
+	meta.SiteName = general.Name
+	meta.Title = general.Name
+	if len(general.ShortDescription) > 0 {
+		meta.Title = fmt.Sprintf("%s - %s", general.Name, general.ShortDescription)
+	}
+	meta.Description = general.Description
+	if len(meta.Description) == 0 {
+		meta.Description = general.ShortDescription
+	}
+	meta.Image = ss.getSiteImage(ctx, general.SiteUrl)
+	return meta
+}
+
+// GetQuestionPageMeta get question page metadata
+func (ss *SeoService) GetQuestionPageMeta(ctx context.Context, questionID string) (meta *schema.PageMeta, exist bool) {
+	question, exist, err := ss.questionRepo.GetQuestion(ctx, questionID)
+	if err != nil {
+		log.Error(err)
+		return nil, false
+	}
+	if !exist || question.Status == entity.QuestionStatusDeleted || question.Status == entity.QuestionStatusPending {
+		return nil, false
+	}
+
+	meta = ss.GetDefaultPageMeta(ctx)
+	siteURL := ss.getSiteURL(ctx)
+	meta.Type = "article"
+	meta.Title = fmt.Sprintf("%s - %s", question.Title, meta.SiteName)
+	meta.Description = htmltext.FetchExcerpt(question.ParsedText, "", pageDescriptionLength)
+	meta.CanonicalURL = fmt.Sprintf("%s/questions/%s", siteURL, question.ID)
+	meta.Language = question.Language
+
+	tags, err := ss.tagCommonService.GetObjectEntityTag(ctx, question.ID)
+	if err != nil {
+		log.Error(err)
+	}
+	tagNames := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tagNames = append(tagNames, tag.SlugName)
+	}
+	meta.Keywords = strings.Join(tagNames, ",")
+	meta.JSONLD = ss.buildQAPageJSONLD(ctx, siteURL, question)
+	return meta, true
+}
+
+// GetTagPageMeta get tag page metadata
+func (ss *SeoService) GetTagPageMeta(ctx context.Context, slugName string) (meta *schema.PageMeta, exist bool) {
+	tag, exist, err := ss.tagCommonService.GetTagBySlugName(ctx, strings.ToLower(slugName))
+	if err != nil {
+		log.Error(err)
+		return nil, false
+	}
+	if !exist {
+		return nil, false
+	}
+
+	meta = ss.GetDefaultPageMeta(ctx)
+	displayName := tag.DisplayName
+	if len(displayName) == 0 {
+		displayName = tag.SlugName
+	}
+	meta.Title = fmt.Sprintf("%s - %s", displayName, meta.SiteName)
+	if description := htmltext.FetchExcerpt(tag.ParsedText, "", pageDescriptionLength); len(description) > 0 {
+		meta.Description = description
+	}
+	meta.Keywords = tag.SlugName
+	meta.CanonicalURL = fmt.Sprintf("%s/tags/%s", ss.getSiteURL(ctx), tag.SlugName)
+	return meta, true
+}
+
+// RenderPageMeta render page metadata to html head tags
+func (ss *SeoService) RenderPageMeta(meta *schema.PageMeta) (title, tags string) {
+	if meta == nil {
+		return "", ""
+	}
+	b := &strings.Builder{}
+	writeMeta := func(attr, key, value string) {
+		if len(value) == 0 {
+			return
+		}
+		b.WriteString(fmt.Sprintf(`<meta %s="%s" content="%s">`, attr, key, html.EscapeString(value)))
+	}
+	writeMeta("name", "description", meta.Description)
+	writeMeta("name", "keywords", meta.Keywords)
+	if len(meta.CanonicalURL) > 0 {
+		b.WriteString(fmt.Sprintf(`<link rel="canonical" href="%s">`, html.EscapeString(meta.CanonicalURL)))
+		if len(meta.Language) > 0 {
+			b.WriteString(fmt.Sprintf(`<link rel="alternate" hreflang="%s" href="%s">`,
+				html.EscapeString(meta.Language), html.EscapeString(meta.CanonicalURL)))
+		}
+	}
+	writeMeta("property", "og:type", meta.Type)
+	writeMeta("property", "og:title", meta.Title)
+	writeMeta("property", "og:description", meta.Description)
+	writeMeta("property", "og:url", meta.CanonicalURL)
+	writeMeta("property", "og:site_name", meta.SiteName)
+	writeMeta("property", "og:image", meta.Image)
+	writeMeta("name", "twitter:card", "summary")
+	writeMeta("name", "twitter:title", meta.Title)
+	writeMeta("name", "twitter:description", meta.Description)
+	writeMeta("name", "twitter:image", meta.Image)
+	if len(meta.JSONLD) > 0 {
+		b.WriteString(`<script type="application/ld+json">` + meta.JSONLD + `</script>`)
+	}
+	return html.EscapeString(meta.Title), b.String()
+}
+
+func (ss *SeoService) buildSitemapIndex(siteURL string, questionCount int64) (content string, err error) {
+	index := &schema.SitemapIndex{XMLNS: schema.SitemapXMLNS}
+	for page := 1; page <= sitemapPageCount(questionCount); page++ {
+		index.Sitemaps = append(index.Sitemaps, &schema.SitemapIndexURL{
+			Loc: fmt.Sprintf("%s/sitemap/%s%d.xml", siteURL, constant.SitemapPageFilePrefix, page),
+		})
+	}
+	return marshalSitemap(index)
+}
+
+func (ss *SeoService) buildSitemapPage(ctx context.Context, siteURL string, page int) (content string, err error) {
+	questionList, err := ss.questionRepo.GetQuestionIDsPage(ctx, page, constant.SitemapMaxSize)
+	if err != nil {
+		return "", err
+	}
+	urlSet := &schema.SitemapURLSet{XMLNS: schema.SitemapXMLNS, URLs: make([]*schema.SitemapURL, 0, len(questionList))}
+	for _, question := range questionList {
+		lastMod := question.PostUpdateTime
+		if lastMod.IsZero() {
+			lastMod = question.CreatedAt
+		}
+		sitemapURL := &schema.SitemapURL{
+			Loc:     fmt.Sprintf("%s/questions/%s", siteURL, question.ID),
+			LastMod: lastMod.Format(schema.SitemapDateFormat),
+		}
+		if len(question.Language) > 0 {
+			urlSet.XHTMLNS = schema.SitemapXHTMLNS
+			sitemapURL.Alternates = []*schema.SitemapAlternateURL{
+				{Rel: "alternate", HrefLang: question.Language, Href: sitemapURL.Loc},
+			}
+		}
+		urlSet.URLs = append(urlSet.URLs, sitemapURL)
+	}
+	return marshalSitemap(urlSet)
+}
+
+func (ss *SeoService) buildQAPageJSONLD(ctx context.Context, siteURL string, question *entity.Question) string {
+	questionURL := fmt.Sprintf("%s/questions/%s", siteURL, question.ID)
+	answers, _, err := ss.answerRepo.SearchList(ctx, &entity.AnswerSearch{
+		Answer:   entity.Answer{QuestionID: question.ID},
+		Order:    entity.AnswerSearchOrderByDefault,
+		PageSize: suggestedAnswerLimit + 1,
+	})
+	if err != nil {
+		log.Error(err)
+	}
+
+	userIDs := []string{question.UserID}
+	for _, answer := range answers {
+		userIDs = append(userIDs, answer.UserID)
+	}
+	users, err := ss.userCommon.BatchUserBasicInfoByID(ctx, userIDs)
+	if err != nil {
+		log.Error(err)
+	}
+	getAuthor := func(userID string) *schema.QAPageAuthor {
+		user, ok := users[userID]
+		if !ok {
+			return nil
+		}
+		return &schema.QAPageAuthor{Type: "Person", Name: user.DisplayName}
+	}
+
+	mainEntity := &schema.QAPageQuestion{
+		Type:        "Question",
+		Name:        question.Title,
+		Text:        htmltext.ClearText(question.ParsedText),
+		AnswerCount: question.AnswerCount,
+		UpvoteCount: question.VoteCount,
+		DateCreated: question.CreatedAt.Format(time.RFC3339),
+		InLanguage:  question.Language,
+		Author:      getAuthor(question.UserID),
+	}
+	for _, answer := range answers {
+		item := &schema.QAPageAnswer{
+			Type:        "Answer",
+			Text:        htmltext.ClearText(answer.ParsedText),
+			UpvoteCount: answer.VoteCount,
+			DateCreated: answer.CreatedAt.Format(time.RFC3339),
+			URL:         fmt.Sprintf("%s/%s", questionURL, answer.ID),
+			Author:      getAuthor(answer.UserID),
+		}
+		if answer.ID == question.AcceptedAnswerID {
+			mainEntity.AcceptedAnswer = item
+		} else if len(mainEntity.SuggestedAnswer) < suggestedAnswerLimit {
+			mainEntity.SuggestedAnswer = append(mainEntity.SuggestedAnswer, item)
+		}
+	}
+
+	content, err := json.Marshal(&schema.QAPageJSONLD{
+		Context:    "https://schema.org",
+		Type:       "QAPage",
+		MainEntity: mainEntity,
+	})
+	if err != nil {
+		log.Error(err)
+		return ""
+	}
+	return string(content)
+}
+
+func (ss *SeoService) getSiteURL(ctx context.Context) string {
+	general, err := ss.siteInfoService.GetSiteGeneral(ctx)
+	if err != nil {
+		log.Error(err)
+		return ""
+	}
+	return strings.TrimSuffix(general.SiteUrl, "/")
+}
+
+func (ss *SeoService) getSiteImage(ctx context.Context, siteURL string) string {
+	branding, err := ss.siteInfoService.GetSiteBranding(ctx)
+	if err != nil {
+		log.Error(err)
+		return ""
+	}
+	image := branding.SquareIcon
+	if len(image) == 0 {
+		image = branding.Logo
+	}
+	if len(image) > 0 && strings.HasPrefix(image, "/") {
+		image = strings.TrimSuffix(siteURL, "/") + image
+	}
+	return image
+}
+
+func (ss *SeoService) setSitemapCache(ctx context.Context, key, content string) {
+	if err := ss.data.Cache.SetString(ctx, key, content, constant.SitemapCacheTime); err != nil {
+		log.Error(err)
+	}
+}
+
+func sitemapPageCount(questionCount int64) int {
+	if questionCount == 0 {
+		return 1
+	}
+	return int(math.Ceil(float64(questionCount) / float64(constant.SitemapMaxSize)))
+}
+
+func marshalSitemap(v interface{}) (content string, err error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	return xml.Header + string(body), nil
+}
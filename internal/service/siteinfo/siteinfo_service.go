@@ -12,6 +12,7 @@ import (
 	"answer/internal/service/export"
 	"answer/internal/service/siteinfo_common"
 	tagcommon "answer/internal/service/tag_common"
+	"answer/pkg/moderation"
 
 	"github.com/jinzhu/copier"
 	"github.com/segmentfault/pacman/errors"
@@ -117,6 +118,118 @@ func (s *SiteInfoService) GetSiteLegal(ctx context.Context) (resp *schema.SiteLe
 	return resp, nil
 }
 
+// GetSiteSeo get site seo config
+func (s *SiteInfoService) GetSiteSeo(ctx context.Context) (resp *schema.SiteSeoResp, err error) {
+	resp = &schema.SiteSeoResp{}
+	siteInfo, exist, err := s.siteInfoRepo.GetByType(ctx, constant.SiteTypeSeo)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return resp, nil
+	}
+	_ = json.Unmarshal([]byte(siteInfo.Content), resp)
+	return resp, nil
+}
+
+// GetSiteLogin get site login config
+func (s *SiteInfoService) GetSiteLogin(ctx context.Context) (resp *schema.SiteLoginResp, err error) {
+	resp = &schema.SiteLoginResp{}
+	siteInfo, exist, err := s.siteInfoRepo.GetByType(ctx, constant.SiteTypeLogin)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return resp, nil
+	}
+	_ = json.Unmarshal([]byte(siteInfo.Content), resp)
+	return resp, nil
+}
+
+// GetSiteReview get site review queue config
+func (s *SiteInfoService) GetSiteReview(ctx context.Context) (resp *schema.SiteReviewResp, err error) {
+	resp = &schema.SiteReviewResp{}
+	siteInfo, exist, err := s.siteInfoRepo.GetByType(ctx, constant.SiteTypeReview)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return resp, nil
+	}
+	_ = json.Unmarshal([]byte(siteInfo.Content), resp)
+	return resp, nil
+}
+
+// GetSiteCloseVote get site close vote config
+func (s *SiteInfoService) GetSiteCloseVote(ctx context.Context) (resp *schema.SiteCloseVoteResp, err error) {
+	resp = &schema.SiteCloseVoteResp{}
+	siteInfo, exist, err := s.siteInfoRepo.GetByType(ctx, constant.SiteTypeCloseVote)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return resp, nil
+	}
+	_ = json.Unmarshal([]byte(siteInfo.Content), resp)
+	return resp, nil
+}
+
+// GetSiteAccept get site accepted answer config
+func (s *SiteInfoService) GetSiteAccept(ctx context.Context) (resp *schema.SiteAcceptResp, err error) {
+	resp = &schema.SiteAcceptResp{}
+	siteInfo, exist, err := s.siteInfoRepo.GetByType(ctx, constant.SiteTypeAccept)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return resp, nil
+	}
+	_ = json.Unmarshal([]byte(siteInfo.Content), resp)
+	return resp, nil
+}
+
+// GetSiteComment get site comment config
+func (s *SiteInfoService) GetSiteComment(ctx context.Context) (resp *schema.SiteCommentResp, err error) {
+	resp = &schema.SiteCommentResp{}
+	siteInfo, exist, err := s.siteInfoRepo.GetByType(ctx, constant.SiteTypeComment)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return resp, nil
+	}
+	_ = json.Unmarshal([]byte(siteInfo.Content), resp)
+	return resp, nil
+}
+
+// GetSiteTagModerator get site tag moderator config
+func (s *SiteInfoService) GetSiteTagModerator(ctx context.Context) (resp *schema.SiteTagModeratorResp, err error) {
+	resp = &schema.SiteTagModeratorResp{}
+	siteInfo, exist, err := s.siteInfoRepo.GetByType(ctx, constant.SiteTypeTagModerator)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return resp, nil
+	}
+	_ = json.Unmarshal([]byte(siteInfo.Content), resp)
+	return resp, nil
+}
+
+// GetSiteModeration get site automated moderation config
+func (s *SiteInfoService) GetSiteModeration(ctx context.Context) (resp *schema.SiteModerationResp, err error) {
+	resp = &schema.SiteModerationResp{}
+	siteInfo, exist, err := s.siteInfoRepo.GetByType(ctx, constant.SiteTypeModeration)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return resp, nil
+	}
+	_ = json.Unmarshal([]byte(siteInfo.Content), resp)
+	return resp, nil
+}
+
 func (s *SiteInfoService) SaveSiteGeneral(ctx context.Context, req schema.SiteGeneralReq) (err error) {
 	req.FormatSiteUrl()
 	var (
@@ -208,6 +321,98 @@ func (s *SiteInfoService) SaveSiteLegal(ctx context.Context, req *schema.SiteLeg
 	return s.siteInfoRepo.SaveByType(ctx, constant.SiteTypeLegal, data)
 }
 
+// SaveSiteSeo save site seo configuration
+func (s *SiteInfoService) SaveSiteSeo(ctx context.Context, req *schema.SiteSeoReq) (err error) {
+	content, _ := json.Marshal(req)
+	data := &entity.SiteInfo{
+		Type:    constant.SiteTypeSeo,
+		Content: string(content),
+		Status:  1,
+	}
+	return s.siteInfoRepo.SaveByType(ctx, constant.SiteTypeSeo, data)
+}
+
+// SaveSiteLogin save site login configuration
+func (s *SiteInfoService) SaveSiteLogin(ctx context.Context, req *schema.SiteLoginReq) (err error) {
+	req.FormatAllowEmailDomains()
+	content, _ := json.Marshal(req)
+	data := &entity.SiteInfo{
+		Type:    constant.SiteTypeLogin,
+		Content: string(content),
+		Status:  1,
+	}
+	return s.siteInfoRepo.SaveByType(ctx, constant.SiteTypeLogin, data)
+}
+
+// SaveSiteReview save site review queue configuration
+func (s *SiteInfoService) SaveSiteReview(ctx context.Context, req *schema.SiteReviewReq) (err error) {
+	content, _ := json.Marshal(req)
+	data := &entity.SiteInfo{
+		Type:    constant.SiteTypeReview,
+		Content: string(content),
+		Status:  1,
+	}
+	return s.siteInfoRepo.SaveByType(ctx, constant.SiteTypeReview, data)
+}
+
+// SaveSiteCloseVote save site close vote configuration
+func (s *SiteInfoService) SaveSiteCloseVote(ctx context.Context, req *schema.SiteCloseVoteReq) (err error) {
+	content, _ := json.Marshal(req)
+	data := &entity.SiteInfo{
+		Type:    constant.SiteTypeCloseVote,
+		Content: string(content),
+		Status:  1,
+	}
+	return s.siteInfoRepo.SaveByType(ctx, constant.SiteTypeCloseVote, data)
+}
+
+// SaveSiteAccept save site accepted answer configuration
+func (s *SiteInfoService) SaveSiteAccept(ctx context.Context, req *schema.SiteAcceptReq) (err error) {
+	content, _ := json.Marshal(req)
+	data := &entity.SiteInfo{
+		Type:    constant.SiteTypeAccept,
+		Content: string(content),
+		Status:  1,
+	}
+	return s.siteInfoRepo.SaveByType(ctx, constant.SiteTypeAccept, data)
+}
+
+// SaveSiteComment save site comment configuration
+func (s *SiteInfoService) SaveSiteComment(ctx context.Context, req *schema.SiteCommentReq) (err error) {
+	content, _ := json.Marshal(req)
+	data := &entity.SiteInfo{
+		Type:    constant.SiteTypeComment,
+		Content: string(content),
+		Status:  1,
+	}
+	return s.siteInfoRepo.SaveByType(ctx, constant.SiteTypeComment, data)
+}
+
+// SaveSiteTagModerator save site tag moderator configuration
+func (s *SiteInfoService) SaveSiteTagModerator(ctx context.Context, req *schema.SiteTagModeratorReq) (err error) {
+	content, _ := json.Marshal(req)
+	data := &entity.SiteInfo{
+		Type:    constant.SiteTypeTagModerator,
+		Content: string(content),
+		Status:  1,
+	}
+	return s.siteInfoRepo.SaveByType(ctx, constant.SiteTypeTagModerator, data)
+}
+
+// SaveSiteModeration save site automated moderation configuration
+func (s *SiteInfoService) SaveSiteModeration(ctx context.Context, req *schema.SiteModerationReq) (err error) {
+	if _, err = moderation.NewKeywordChecker(req.BlockedKeywords, req.BlockedPatterns, req.KeywordScore); err != nil {
+		return errors.BadRequest(reason.ModerationPatternInvalid).WithError(err)
+	}
+	content, _ := json.Marshal(req)
+	data := &entity.SiteInfo{
+		Type:    constant.SiteTypeModeration,
+		Content: string(content),
+		Status:  1,
+	}
+	return s.siteInfoRepo.SaveByType(ctx, constant.SiteTypeModeration, data)
+}
+
 // GetSMTPConfig get smtp config
 func (s *SiteInfoService) GetSMTPConfig(ctx context.Context) (
 	resp *schema.GetSMTPConfigResp, err error,
@@ -87,3 +87,115 @@ func (s *SiteInfoCommonService) GetSiteLegal(ctx context.Context) (resp *schema.
 	_ = json.Unmarshal([]byte(siteInfo.Content), resp)
 	return resp, nil
 }
+
+// GetSiteSeo get site seo config
+func (s *SiteInfoCommonService) GetSiteSeo(ctx context.Context) (resp *schema.SiteSeoResp, err error) {
+	resp = &schema.SiteSeoResp{}
+	siteInfo, exist, err := s.siteInfoRepo.GetByType(ctx, constant.SiteTypeSeo)
+	if err != nil {
+		return resp, err
+	}
+	if !exist {
+		return resp, nil
+	}
+	_ = json.Unmarshal([]byte(siteInfo.Content), resp)
+	return resp, nil
+}
+
+// GetSiteReview get site review queue config
+func (s *SiteInfoCommonService) GetSiteReview(ctx context.Context) (resp *schema.SiteReviewResp, err error) {
+	resp = &schema.SiteReviewResp{}
+	siteInfo, exist, err := s.siteInfoRepo.GetByType(ctx, constant.SiteTypeReview)
+	if err != nil {
+		return resp, err
+	}
+	if !exist {
+		return resp, nil
+	}
+	_ = json.Unmarshal([]byte(siteInfo.Content), resp)
+	return resp, nil
+}
+
+// GetSiteCloseVote get site close vote config
+func (s *SiteInfoCommonService) GetSiteCloseVote(ctx context.Context) (resp *schema.SiteCloseVoteResp, err error) {
+	resp = &schema.SiteCloseVoteResp{}
+	siteInfo, exist, err := s.siteInfoRepo.GetByType(ctx, constant.SiteTypeCloseVote)
+	if err != nil {
+		return resp, err
+	}
+	if !exist {
+		return resp, nil
+	}
+	_ = json.Unmarshal([]byte(siteInfo.Content), resp)
+	return resp, nil
+}
+
+// GetSiteModeration get site automated moderation config
+func (s *SiteInfoCommonService) GetSiteModeration(ctx context.Context) (resp *schema.SiteModerationResp, err error) {
+	resp = &schema.SiteModerationResp{}
+	siteInfo, exist, err := s.siteInfoRepo.GetByType(ctx, constant.SiteTypeModeration)
+	if err != nil {
+		return resp, err
+	}
+	if !exist {
+		return resp, nil
+	}
+	_ = json.Unmarshal([]byte(siteInfo.Content), resp)
+	return resp, nil
+}
+
+// GetSiteAccept get site accepted answer config
+func (s *SiteInfoCommonService) GetSiteAccept(ctx context.Context) (resp *schema.SiteAcceptResp, err error) {
+	resp = &schema.SiteAcceptResp{}
+	siteInfo, exist, err := s.siteInfoRepo.GetByType(ctx, constant.SiteTypeAccept)
+	if err != nil {
+		return resp, err
+	}
+	if !exist {
+		return resp, nil
+	}
+	_ = json.Unmarshal([]byte(siteInfo.Content), resp)
+	return resp, nil
+}
+
+// GetSiteLogin get site login config
+func (s *SiteInfoCommonService) GetSiteLogin(ctx context.Context) (resp *schema.SiteLoginResp, err error) {
+	resp = &schema.SiteLoginResp{}
+	siteInfo, exist, err := s.siteInfoRepo.GetByType(ctx, constant.SiteTypeLogin)
+	if err != nil {
+		return resp, err
+	}
+	if !exist {
+		return resp, nil
+	}
+	_ = json.Unmarshal([]byte(siteInfo.Content), resp)
+	return resp, nil
+}
+
+// GetSiteComment get site comment config
+func (s *SiteInfoCommonService) GetSiteComment(ctx context.Context) (resp *schema.SiteCommentResp, err error) {
+	resp = &schema.SiteCommentResp{}
+	siteInfo, exist, err := s.siteInfoRepo.GetByType(ctx, constant.SiteTypeComment)
+	if err != nil {
+		return resp, err
+	}
+	if !exist {
+		return resp, nil
+	}
+	_ = json.Unmarshal([]byte(siteInfo.Content), resp)
+	return resp, nil
+}
+
+// GetSiteTagModerator get site tag moderator config
+func (s *SiteInfoCommonService) GetSiteTagModerator(ctx context.Context) (resp *schema.SiteTagModeratorResp, err error) {
+	resp = &schema.SiteTagModeratorResp{}
+	siteInfo, exist, err := s.siteInfoRepo.GetByType(ctx, constant.SiteTypeTagModerator)
+	if err != nil {
+		return resp, err
+	}
+	if !exist {
+		return resp, nil
+	}
+	_ = json.Unmarshal([]byte(siteInfo.Content), resp)
+	return resp, nil
+}
@@ -9,6 +9,8 @@ import (
 	"answer/internal/service/revision_common"
 	"answer/internal/service/siteinfo_common"
 	tagcommonser "answer/internal/service/tag_common"
+	"answer/internal/service/tag_moderator"
+	"answer/internal/service/tag_template"
 	"answer/pkg/htmltext"
 
 	"answer/internal/base/pager"
@@ -26,11 +28,13 @@ import (
 
 // TagService user service
 type TagService struct {
-	tagRepo          tagcommonser.TagRepo
-	tagCommonService *tagcommonser.TagCommonService
-	revisionService  *revision_common.RevisionService
-	followCommon     activity_common.FollowRepo
-	siteInfoService  *siteinfo_common.SiteInfoCommonService
+	tagRepo             tagcommonser.TagRepo
+	tagCommonService    *tagcommonser.TagCommonService
+	revisionService     *revision_common.RevisionService
+	followCommon        activity_common.FollowRepo
+	siteInfoService     *siteinfo_common.SiteInfoCommonService
+	tagTemplateService  *tag_template.TagTemplateService
+	tagModeratorService *tag_moderator.TagModeratorService
 }
 
 // NewTagService new tag service
@@ -39,13 +43,17 @@ func NewTagService(
 	tagCommonService *tagcommonser.TagCommonService,
 	revisionService *revision_common.RevisionService,
 	followCommon activity_common.FollowRepo,
-	siteInfoService *siteinfo_common.SiteInfoCommonService) *TagService {
+	siteInfoService *siteinfo_common.SiteInfoCommonService,
+	tagTemplateService *tag_template.TagTemplateService,
+	tagModeratorService *tag_moderator.TagModeratorService) *TagService {
 	return &TagService{
-		tagRepo:          tagRepo,
-		tagCommonService: tagCommonService,
-		revisionService:  revisionService,
-		followCommon:     followCommon,
-		siteInfoService:  siteInfoService,
+		tagRepo:             tagRepo,
+		tagCommonService:    tagCommonService,
+		revisionService:     revisionService,
+		followCommon:        followCommon,
+		siteInfoService:     siteInfoService,
+		tagTemplateService:  tagTemplateService,
+		tagModeratorService: tagModeratorService,
 	}
 }
 
@@ -111,7 +119,22 @@ func (ts *TagService) GetTagInfo(ctx context.Context, req *schema.GetTagInfoReq)
 	resp.Recommend = tagInfo.Recommend
 	resp.Reserved = tagInfo.Reserved
 	resp.IsFollower = ts.checkTagIsFollow(ctx, req.UserID, tagInfo.ID)
+	resp.Moderators, err = ts.tagModeratorService.GetTagModerators(ctx, tagInfo.ID)
+	if err != nil {
+		return nil, err
+	}
+	// the moderators of the tag can edit it whatever their reputation is
+	if !req.CanEdit {
+		req.CanEdit, err = ts.tagModeratorService.IsTagModerator(ctx, req.UserID, tagInfo.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
 	resp.MemberActions = permission.GetTagPermission(ctx, req.CanEdit, req.CanDelete)
+	resp.QuestionTemplate, err = ts.tagTemplateService.GetTagTemplate(ctx, tagInfo)
+	if err != nil {
+		return nil, err
+	}
 	resp.GetExcerpt()
 	return resp, nil
 }
@@ -196,6 +219,13 @@ func (ts *TagService) GetTagSynonyms(ctx context.Context, req *schema.GetTagSyno
 			MainTagSlugName: mainTagSlugName,
 		})
 	}
+	// the moderators of the tag can manage its synonyms whatever their reputation is
+	if !req.CanEdit {
+		req.CanEdit, err = ts.tagModeratorService.IsTagModerator(ctx, req.UserID, tag.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
 	resp.MemberActions = permission.GetTagSynonymPermission(ctx, req.CanEdit)
 	return
 }
@@ -224,6 +254,10 @@ func (ts *TagService) UpdateTagSynonym(ctx context.Context, req *schema.UpdateTa
 	}
 	existTagMapping := make(map[string]*entity.Tag, 0)
 	for _, tag := range tagListInDB {
+		// the tag moderator only keeps the synonyms of the tag or adds the new ones
+		if req.OnlyTagModerator && tag.MainTagID != converter.StringToInt64(mainTagInfo.ID) {
+			return errors.Forbidden(reason.RankFailToMeetTheCondition)
+		}
 		existTagMapping[tag.SlugName] = tag
 	}
 
@@ -0,0 +1,10 @@
+package tag_alert_queue
+
+var (
+	TagAlertQueue = make(chan string, 128)
+)
+
+// AddQuestion add the id of the published question, the users who watch its tags are alerted
+func AddQuestion(questionID string) {
+	TagAlertQueue <- questionID
+}
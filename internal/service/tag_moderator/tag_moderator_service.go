@@ -0,0 +1,274 @@
+package tag_moderator
+
+import (
+	"context"
+
+	"answer/internal/base/constant"
+	"answer/internal/base/reason"
+	"answer/internal/base/scheduler"
+	"answer/internal/entity"
+	"answer/internal/schema"
+	"answer/internal/service/siteinfo_common"
+	tagcommon "answer/internal/service/tag_common"
+	usercommon "answer/internal/service/user_common"
+	"answer/pkg/converter"
+
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// TagAnswerScore the total score of the answers of the user in the tag
+type TagAnswerScore struct {
+	TagID  string `xorm:"tag_id"`
+	UserID string `xorm:"user_id"`
+	Score  int    `xorm:"score"`
+}
+
+// TagModeratorRepo tag moderator repository
+type TagModeratorRepo interface {
+	AddTagModerator(ctx context.Context, moderator *entity.TagModerator) (err error)
+	RemoveTagModerator(ctx context.Context, tagID, userID, operatorID string) (err error)
+	GetTagModeratorList(ctx context.Context, tagID string) (moderators []*entity.TagModerator, err error)
+	GetUserTagModeratorList(ctx context.Context, userID string) (moderators []*entity.TagModerator, err error)
+	ExistTagModerator(ctx context.Context, userID string, tagIDs []string) (exist bool, err error)
+	ReplaceAutoTagModerators(ctx context.Context, moderators []*entity.TagModerator) (err error)
+	GetTagAnswerScores(ctx context.Context, minScore int) (scores []*TagAnswerScore, err error)
+}
+
+// TagModeratorService the moderators who are in charge of the tags
+type TagModeratorService struct {
+	tagModeratorRepo TagModeratorRepo
+	tagCommonService *tagcommon.TagCommonService
+	userCommon       *usercommon.UserCommon
+	siteInfoService  *siteinfo_common.SiteInfoCommonService
+}
+
+// NewTagModeratorService new tag moderator service
+func NewTagModeratorService(
+	tagModeratorRepo TagModeratorRepo,
+	tagCommonService *tagcommon.TagCommonService,
+	userCommon *usercommon.UserCommon,
+	siteInfoService *siteinfo_common.SiteInfoCommonService,
+	scheduler *scheduler.Scheduler,
+) *TagModeratorService {
+	ts := &TagModeratorService{
+		tagModeratorRepo: tagModeratorRepo,
+		tagCommonService: tagCommonService,
+		userCommon:       userCommon,
+		siteInfoService:  siteInfoService,
+	}
+	scheduler.AddJob("tag_moderator_auto_assign", constant.TagModeratorAssignInterval, true, func(ctx context.Context) {
+		if err := ts.AutoAssign(ctx); err != nil {
+			log.Error(err)
+		}
+	})
+	return ts
+}
+
+// AddTagModerator the admin assigns the user as the moderator of the tag
+func (ts *TagModeratorService) AddTagModerator(ctx context.Context, req *schema.AddTagModeratorReq) (err error) {
+	tagInfo, err := ts.getMainTag(ctx, req.TagID)
+	if err != nil {
+		return err
+	}
+	_, exist, err := ts.userCommon.GetUserBasicInfoByID(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.UserNotFound)
+	}
+	return ts.tagModeratorRepo.AddTagModerator(ctx, &entity.TagModerator{
+		TagID:      tagInfo.ID,
+		UserID:     req.UserID,
+		Source:     entity.TagModeratorSourceManual,
+		OperatorID: req.OperatorID,
+	})
+}
+
+// RemoveTagModerator the admin removes the moderator of the tag
+func (ts *TagModeratorService) RemoveTagModerator(ctx context.Context, req *schema.RemoveTagModeratorReq) (err error) {
+	tagInfo, err := ts.getMainTag(ctx, req.TagID)
+	if err != nil {
+		return err
+	}
+	return ts.tagModeratorRepo.RemoveTagModerator(ctx, tagInfo.ID, req.UserID, req.OperatorID)
+}
+
+// GetTagModerators get the moderators of the tag
+func (ts *TagModeratorService) GetTagModerators(ctx context.Context, tagID string) (
+	resp []*schema.TagModeratorInfo, err error) {
+	resp = make([]*schema.TagModeratorInfo, 0)
+	moderators, err := ts.tagModeratorRepo.GetTagModeratorList(ctx, tagID)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]string, 0, len(moderators))
+	for _, moderator := range moderators {
+		userIDs = append(userIDs, moderator.UserID)
+	}
+	userInfoMapping, err := ts.userCommon.BatchUserBasicInfoByID(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, moderator := range moderators {
+		userInfo, ok := userInfoMapping[moderator.UserID]
+		if !ok {
+			continue
+		}
+		resp = append(resp, &schema.TagModeratorInfo{
+			UserInfo:  userInfo,
+			Source:    moderator.Source,
+			CreatedAt: moderator.CreatedAt.Unix(),
+		})
+	}
+	return resp, nil
+}
+
+// GetModeratedTags get the tags which the user moderates
+func (ts *TagModeratorService) GetModeratedTags(ctx context.Context, userID string) (
+	resp []*schema.GetModeratedTagsResp, err error) {
+	resp = make([]*schema.GetModeratedTagsResp, 0)
+	moderators, err := ts.tagModeratorRepo.GetUserTagModeratorList(ctx, userID)
+	if err != nil || len(moderators) == 0 {
+		return resp, err
+	}
+	tagIDs := make([]string, 0, len(moderators))
+	for _, moderator := range moderators {
+		tagIDs = append(tagIDs, moderator.TagID)
+	}
+	tagList, err := ts.tagCommonService.GetTagListByIDs(ctx, tagIDs)
+	if err != nil {
+		return nil, err
+	}
+	tagMapping := make(map[string]*entity.Tag, len(tagList))
+	for _, tag := range tagList {
+		tagMapping[tag.ID] = tag
+	}
+	for _, moderator := range moderators {
+		tag, ok := tagMapping[moderator.TagID]
+		if !ok {
+			continue
+		}
+		resp = append(resp, &schema.GetModeratedTagsResp{
+			TagID:       tag.ID,
+			SlugName:    tag.SlugName,
+			DisplayName: tag.DisplayName,
+			Source:      moderator.Source,
+		})
+	}
+	return resp, nil
+}
+
+// GetModeratedTagIDs get the ids of the tags which the user moderates
+func (ts *TagModeratorService) GetModeratedTagIDs(ctx context.Context, userID string) (tagIDs []string, err error) {
+	tagIDs = make([]string, 0)
+	if len(userID) == 0 {
+		return tagIDs, nil
+	}
+	moderators, err := ts.tagModeratorRepo.GetUserTagModeratorList(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, moderator := range moderators {
+		tagIDs = append(tagIDs, moderator.TagID)
+	}
+	return tagIDs, nil
+}
+
+// IsTagModerator whether the user moderates the tag, the synonym is moderated by the moderators of the main tag
+func (ts *TagModeratorService) IsTagModerator(ctx context.Context, userID, tagID string) (ok bool, err error) {
+	if len(userID) == 0 || len(tagID) == 0 {
+		return false, nil
+	}
+	tagInfo, exist, err := ts.tagCommonService.GetTagByID(ctx, tagID)
+	if err != nil || !exist {
+		return false, err
+	}
+	return ts.tagModeratorRepo.ExistTagModerator(ctx, userID, []string{mainTagID(tagInfo)})
+}
+
+// IsQuestionTagModerator whether the user moderates any tag of the question
+func (ts *TagModeratorService) IsQuestionTagModerator(ctx context.Context, userID, questionID string) (
+	ok bool, err error) {
+	if len(userID) == 0 || len(questionID) == 0 {
+		return false, nil
+	}
+	tags, err := ts.tagCommonService.GetObjectEntityTag(ctx, questionID)
+	if err != nil || len(tags) == 0 {
+		return false, err
+	}
+	tagIDs := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tagIDs = append(tagIDs, mainTagID(tag))
+	}
+	return ts.tagModeratorRepo.ExistTagModerator(ctx, userID, tagIDs)
+}
+
+// AutoAssign assign the answerers with the highest answer scores in each tag as its moderators.
+// The previous automatic assignments which are not in the top any more are removed, the manual ones are kept.
+func (ts *TagModeratorService) AutoAssign(ctx context.Context) (err error) {
+	setting, err := ts.siteInfoService.GetSiteTagModerator(ctx)
+	if err != nil {
+		return err
+	}
+	if !setting.AutoAssignEnabled || setting.AutoAssignCount <= 0 {
+		return nil
+	}
+	// the answer with no score does not show any expertise
+	minScore := setting.AutoAssignMinScore
+	if minScore < 1 {
+		minScore = 1
+	}
+	scores, err := ts.tagModeratorRepo.GetTagAnswerScores(ctx, minScore)
+	if err != nil {
+		return err
+	}
+	moderators := make([]*entity.TagModerator, 0)
+	assigned := make(map[string]int)
+	for _, score := range scores {
+		if assigned[score.TagID] >= setting.AutoAssignCount {
+			continue
+		}
+		assigned[score.TagID]++
+		moderators = append(moderators, &entity.TagModerator{
+			TagID:  score.TagID,
+			UserID: score.UserID,
+			Source: entity.TagModeratorSourceAuto,
+		})
+	}
+	if err = ts.tagModeratorRepo.ReplaceAutoTagModerators(ctx, moderators); err != nil {
+		return err
+	}
+	log.Infof("%d tag moderators are assigned automatically in %d tags", len(moderators), len(assigned))
+	return nil
+}
+
+// getMainTag get the tag, the main tag is returned if it is a synonym
+func (ts *TagModeratorService) getMainTag(ctx context.Context, tagID string) (tagInfo *entity.Tag, err error) {
+	tagInfo, exist, err := ts.tagCommonService.GetTagByID(ctx, tagID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.BadRequest(reason.TagNotFound)
+	}
+	if tagInfo.MainTagID == 0 {
+		return tagInfo, nil
+	}
+	tagInfo, exist, err = ts.tagCommonService.GetTagByID(ctx, converter.IntToString(tagInfo.MainTagID))
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.BadRequest(reason.TagNotFound)
+	}
+	return tagInfo, nil
+}
+
+func mainTagID(tag *entity.Tag) string {
+	if tag.MainTagID > 0 {
+		return converter.IntToString(tag.MainTagID)
+	}
+	return tag.ID
+}
@@ -0,0 +1,528 @@
+package tag_subscription
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"answer/internal/base/constant"
+	"answer/internal/base/reason"
+	"answer/internal/base/scheduler"
+	"answer/internal/entity"
+	"answer/internal/schema"
+	"answer/internal/service/export"
+	questioncommon "answer/internal/service/question_common"
+	"answer/internal/service/tag_alert_queue"
+	tagcommon "answer/internal/service/tag_common"
+	usercommon "answer/internal/service/user_common"
+	"answer/pkg/htmltext"
+	"answer/pkg/tagexpr"
+
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// tagAlertBatchSize the number of the users whose pending alerts are sent in one batch
+const tagAlertBatchSize = 100
+
+// TagSubscriptionRepo tag subscription repository
+type TagSubscriptionRepo interface {
+	AddSubscription(ctx context.Context, subscription *entity.TagSubscription, tagNames []string) (err error)
+	UpdateSubscription(ctx context.Context, subscription *entity.TagSubscription, tagNames []string) (err error)
+	RemoveSubscription(ctx context.Context, id string) (err error)
+	GetSubscription(ctx context.Context, id string) (subscription *entity.TagSubscription, exist bool, err error)
+	GetUserSubscriptions(ctx context.Context, userID string) (subscriptions []*entity.TagSubscription, err error)
+	GetCandidateSubscriptions(ctx context.Context, tagNames []string) (subscriptions []*entity.TagSubscription, err error)
+
+	AddAlert(ctx context.Context, alert *entity.TagAlert) (added bool, err error)
+	GetPendingAlertUserIDs(ctx context.Context, afterUserID string, limit int) (userIDs []string, err error)
+	GetPendingAlerts(ctx context.Context, userID string) (alerts []*entity.TagAlert, err error)
+	ClaimAlerts(ctx context.Context, ids []string) (claimedIDs []string, err error)
+
+	GetAlertSetting(ctx context.Context, userID string) (setting *entity.TagAlertSetting, exist bool, err error)
+	SaveAlertSetting(ctx context.Context, setting *entity.TagAlertSetting) (err error)
+	UpdateLastDigestAt(ctx context.Context, userID string, lastDigestAt time.Time) (err error)
+
+	GetIgnoredTagIDs(ctx context.Context, userID string) (tagIDs []string, err error)
+	ReplaceIgnoredTags(ctx context.Context, userID string, tagIDs []string) (err error)
+}
+
+// TagSubscriptionService the tag expressions watched by the users, the alerts of the new questions, and the ignored tags
+type TagSubscriptionService struct {
+	tagSubscriptionRepo TagSubscriptionRepo
+	tagCommonService    *tagcommon.TagCommonService
+	questionRepo        questioncommon.QuestionRepo
+	userRepo            usercommon.UserRepo
+	emailService        *export.EmailService
+}
+
+// NewTagSubscriptionService new tag subscription service
+func NewTagSubscriptionService(
+	tagSubscriptionRepo TagSubscriptionRepo,
+	tagCommonService *tagcommon.TagCommonService,
+	questionRepo questioncommon.QuestionRepo,
+	userRepo usercommon.UserRepo,
+	emailService *export.EmailService,
+	scheduler *scheduler.Scheduler,
+) *TagSubscriptionService {
+	ts := &TagSubscriptionService{
+		tagSubscriptionRepo: tagSubscriptionRepo,
+		tagCommonService:    tagCommonService,
+		questionRepo:        questionRepo,
+		userRepo:            userRepo,
+		emailService:        emailService,
+	}
+	scheduler.AddJob("tag_alert", constant.TagAlertCheckInterval, false, ts.SendPendingAlerts)
+	ts.HandleNewQuestion()
+	return ts
+}
+
+// HandleNewQuestion alert the users of the published questions in the queue
+func (ts *TagSubscriptionService) HandleNewQuestion() {
+	go func() {
+		for questionID := range tag_alert_queue.TagAlertQueue {
+			log.Debugf("received the published question %s", questionID)
+			ts.handleNewQuestion(questionID)
+		}
+	}()
+}
+
+// handleNewQuestion the panic of one question does not stop the queue
+func (ts *TagSubscriptionService) handleNewQuestion(questionID string) {
+	defer func() {
+		if err := recover(); err != nil {
+			log.Errorf("alert the question %s panic: %v", questionID, err)
+		}
+	}()
+	ts.NotifyNewQuestion(context.Background(), questionID)
+}
+
+// GetSubscriptions get the tag expressions which the user watches
+func (ts *TagSubscriptionService) GetSubscriptions(ctx context.Context, userID string) (
+	resp []*schema.TagSubscriptionResp, err error) {
+	subscriptions, err := ts.tagSubscriptionRepo.GetUserSubscriptions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp = make([]*schema.TagSubscriptionResp, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		resp = append(resp, formatSubscription(subscription))
+	}
+	return resp, nil
+}
+
+// AddSubscription watch the tag expression
+func (ts *TagSubscriptionService) AddSubscription(ctx context.Context, req *schema.AddTagSubscriptionReq) (
+	resp *schema.TagSubscriptionResp, err error) {
+	expr, err := ts.parseExpression(ctx, req.Expression)
+	if err != nil {
+		return nil, err
+	}
+	subscriptions, err := ts.tagSubscriptionRepo.GetUserSubscriptions(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(subscriptions) >= constant.TagSubscriptionMaxPerUser {
+		return nil, errors.BadRequest(reason.TagSubscriptionTooMany)
+	}
+	subscription := &entity.TagSubscription{
+		UserID:     req.UserID,
+		Expression: expr.String(),
+		Frequency:  req.Frequency,
+	}
+	if err = ts.tagSubscriptionRepo.AddSubscription(ctx, subscription, expr.PositiveTags()); err != nil {
+		return nil, err
+	}
+	return formatSubscription(subscription), nil
+}
+
+// UpdateSubscription change the expression or the frequency of the watched tag expression
+func (ts *TagSubscriptionService) UpdateSubscription(ctx context.Context, req *schema.UpdateTagSubscriptionReq) (
+	err error) {
+	expr, err := ts.parseExpression(ctx, req.Expression)
+	if err != nil {
+		return err
+	}
+	subscription, err := ts.getUserSubscription(ctx, req.ID, req.UserID)
+	if err != nil {
+		return err
+	}
+	subscription.Expression = expr.String()
+	subscription.Frequency = req.Frequency
+	return ts.tagSubscriptionRepo.UpdateSubscription(ctx, subscription, expr.PositiveTags())
+}
+
+// RemoveSubscription stop watching the tag expression
+func (ts *TagSubscriptionService) RemoveSubscription(ctx context.Context, req *schema.RemoveTagSubscriptionReq) (
+	err error) {
+	subscription, err := ts.getUserSubscription(ctx, req.ID, req.UserID)
+	if err != nil {
+		return err
+	}
+	return ts.tagSubscriptionRepo.RemoveSubscription(ctx, subscription.ID)
+}
+
+// GetAlertSetting get the time zone and the quiet hours of the user
+func (ts *TagSubscriptionService) GetAlertSetting(ctx context.Context, userID string) (
+	resp *schema.GetTagAlertSettingResp, err error) {
+	setting, exist, err := ts.tagSubscriptionRepo.GetAlertSetting(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp = &schema.GetTagAlertSettingResp{}
+	if exist {
+		resp.TimeZone = setting.TimeZone
+		resp.QuietHoursStart = setting.QuietHoursStart
+		resp.QuietHoursEnd = setting.QuietHoursEnd
+	}
+	return resp, nil
+}
+
+// UpdateAlertSetting update the time zone and the quiet hours of the user
+func (ts *TagSubscriptionService) UpdateAlertSetting(ctx context.Context, req *schema.UpdateTagAlertSettingReq) (
+	err error) {
+	if _, err = time.LoadLocation(req.TimeZone); err != nil {
+		return errors.BadRequest(reason.TagAlertTimeZoneInvalid)
+	}
+	return ts.tagSubscriptionRepo.SaveAlertSetting(ctx, &entity.TagAlertSetting{
+		UserID:          req.UserID,
+		TimeZone:        req.TimeZone,
+		QuietHoursStart: req.QuietHoursStart,
+		QuietHoursEnd:   req.QuietHoursEnd,
+	})
+}
+
+// GetIgnoredTags get the tags which the user ignores
+func (ts *TagSubscriptionService) GetIgnoredTags(ctx context.Context, userID string) (
+	resp []*schema.GetIgnoredTagResp, err error) {
+	resp = make([]*schema.GetIgnoredTagResp, 0)
+	tagIDs, err := ts.tagSubscriptionRepo.GetIgnoredTagIDs(ctx, userID)
+	if err != nil || len(tagIDs) == 0 {
+		return resp, err
+	}
+	tagList, err := ts.tagCommonService.GetTagListByIDs(ctx, tagIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range tagList {
+		resp = append(resp, &schema.GetIgnoredTagResp{
+			TagID:       tag.ID,
+			SlugName:    tag.SlugName,
+			DisplayName: tag.DisplayName,
+		})
+	}
+	return resp, nil
+}
+
+// UpdateIgnoredTags replace the tags which the user ignores, the not existing tags are skipped.
+// The synonym is replaced by its main tag, which the questions are tagged with.
+func (ts *TagSubscriptionService) UpdateIgnoredTags(ctx context.Context, req *schema.UpdateIgnoredTagsReq) (err error) {
+	tagIDs := make([]string, 0, len(req.TagNames))
+	if len(req.TagNames) > 0 {
+		tagNames := make([]string, 0, len(req.TagNames))
+		for _, name := range req.TagNames {
+			tagNames = append(tagNames, strings.ToLower(name))
+		}
+		tagList, err := ts.tagCommonService.GetTagListByNames(ctx, tagNames)
+		if err != nil {
+			return err
+		}
+		for _, tag := range tagList {
+			if tag.MainTagID != 0 {
+				tagIDs = append(tagIDs, strconv.FormatInt(tag.MainTagID, 10))
+				continue
+			}
+			tagIDs = append(tagIDs, tag.ID)
+		}
+	}
+	return ts.tagSubscriptionRepo.ReplaceIgnoredTags(ctx, req.UserID, tagIDs)
+}
+
+// GetIgnoredTagIDs get the ids of the tags which the user ignores
+func (ts *TagSubscriptionService) GetIgnoredTagIDs(ctx context.Context, userID string) (tagIDs []string, err error) {
+	if len(userID) == 0 {
+		return make([]string, 0), nil
+	}
+	return ts.tagSubscriptionRepo.GetIgnoredTagIDs(ctx, userID)
+}
+
+// NotifyNewQuestion alert the users whose watched tag expressions match the new question. The instant alert is
+// sent at once unless it is in the quiet hours of the user, the others are sent by the periodic check.
+func (ts *TagSubscriptionService) NotifyNewQuestion(ctx context.Context, questionID string) {
+	questionInfo, exist, err := ts.questionRepo.GetQuestion(ctx, questionID)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if !exist || questionInfo.Status != entity.QuestionStatusAvailable {
+		return
+	}
+	tags, err := ts.tagCommonService.GetObjectEntityTag(ctx, questionID)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	tagNames := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tagNames = append(tagNames, tag.SlugName)
+	}
+	if len(tagNames) == 0 {
+		return
+	}
+	subscriptions, err := ts.tagSubscriptionRepo.GetCandidateSubscriptions(ctx, tagNames)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	// the user is alerted once for the question, the instant subscription wins if the user has both
+	matched := make(map[string]*entity.TagSubscription)
+	userIDs := make([]string, 0)
+	for _, subscription := range subscriptions {
+		if subscription.UserID == questionInfo.UserID {
+			continue
+		}
+		expr, err := tagexpr.Parse(subscription.Expression)
+		if err != nil || !expr.Match(tagNames) {
+			continue
+		}
+		current, ok := matched[subscription.UserID]
+		if !ok {
+			userIDs = append(userIDs, subscription.UserID)
+		}
+		if !ok || (current.Frequency != entity.TagSubscriptionFrequencyInstant &&
+			subscription.Frequency == entity.TagSubscriptionFrequencyInstant) {
+			matched[subscription.UserID] = subscription
+		}
+	}
+
+	for _, userID := range userIDs {
+		subscription := matched[userID]
+		alert := &entity.TagAlert{
+			UserID:     userID,
+			QuestionID: questionInfo.ID,
+			Expression: subscription.Expression,
+			Frequency:  subscription.Frequency,
+			Status:     entity.TagAlertStatusPending,
+		}
+		added, err := ts.tagSubscriptionRepo.AddAlert(ctx, alert)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		if !added || alert.Frequency != entity.TagSubscriptionFrequencyInstant {
+			continue
+		}
+		setting, _, err := ts.tagSubscriptionRepo.GetAlertSetting(ctx, userID)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		if inQuietHours(setting, time.Now()) {
+			continue
+		}
+		ts.sendAlerts(ctx, userID, []*entity.TagAlert{alert})
+	}
+}
+
+// SendPendingAlerts send the pending alerts of the users who are not in the quiet hours. The instant alerts which
+// are deferred by the quiet hours are sent at once, and the daily ones are sent once a day with them.
+func (ts *TagSubscriptionService) SendPendingAlerts(ctx context.Context) {
+	now := time.Now()
+	afterUserID := "0"
+	for {
+		userIDs, err := ts.tagSubscriptionRepo.GetPendingAlertUserIDs(ctx, afterUserID, tagAlertBatchSize)
+		if err != nil {
+			log.Error(err)
+			return
+		}
+		for _, userID := range userIDs {
+			ts.sendPendingAlerts(ctx, userID, now)
+		}
+		if len(userIDs) < tagAlertBatchSize {
+			return
+		}
+		afterUserID = userIDs[len(userIDs)-1]
+	}
+}
+
+func (ts *TagSubscriptionService) sendPendingAlerts(ctx context.Context, userID string, now time.Time) {
+	setting, exist, err := ts.tagSubscriptionRepo.GetAlertSetting(ctx, userID)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if inQuietHours(setting, now) {
+		return
+	}
+	digestDue := !exist || now.Sub(setting.LastDigestAt) >= constant.TagAlertDigestPeriod
+	alerts, err := ts.tagSubscriptionRepo.GetPendingAlerts(ctx, userID)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	due := make([]*entity.TagAlert, 0, len(alerts))
+	hasDaily := false
+	for _, alert := range alerts {
+		if alert.Frequency != entity.TagSubscriptionFrequencyInstant {
+			if !digestDue {
+				continue
+			}
+			hasDaily = true
+		}
+		due = append(due, alert)
+	}
+	if len(due) == 0 {
+		return
+	}
+	ts.sendAlerts(ctx, userID, due)
+	if hasDaily {
+		if err = ts.tagSubscriptionRepo.UpdateLastDigestAt(ctx, userID, now); err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+// sendAlerts send the alerts of the questions to the user in one email. The alerts are claimed as sent before,
+// so that the alert which is sent by the other at the same time is skipped.
+// The alerts of the questions which are not available any more are marked as sent without being sent.
+func (ts *TagSubscriptionService) sendAlerts(ctx context.Context, userID string, alerts []*entity.TagAlert) {
+	alertIDs := make([]string, 0, len(alerts))
+	for _, alert := range alerts {
+		alertIDs = append(alertIDs, alert.ID)
+	}
+	claimedIDs, err := ts.tagSubscriptionRepo.ClaimAlerts(ctx, alertIDs)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	claimed := make(map[string]bool, len(claimedIDs))
+	for _, id := range claimedIDs {
+		claimed[id] = true
+	}
+	claimedAlerts := make([]*entity.TagAlert, 0, len(claimedIDs))
+	for _, alert := range alerts {
+		if claimed[alert.ID] {
+			claimedAlerts = append(claimedAlerts, alert)
+		}
+	}
+	if len(claimedAlerts) == 0 {
+		return
+	}
+	alerts = claimedAlerts
+
+	userInfo, exist, err := ts.userRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if !exist || userInfo.Status != entity.UserStatusAvailable || userInfo.MailStatus != entity.EmailStatusAvailable {
+		return
+	}
+	siteGeneral, err := ts.emailService.GetSiteGeneral(ctx)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	items := make([]*export.TagAlertTemplateItem, 0, len(alerts))
+	for _, alert := range alerts {
+		questionInfo, exist, err := ts.questionRepo.GetQuestion(ctx, alert.QuestionID)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		if !exist || questionInfo.Status != entity.QuestionStatusAvailable {
+			continue
+		}
+		tags, err := ts.tagCommonService.GetObjectEntityTag(ctx, questionInfo.ID)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		tagNames := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			tagNames = append(tagNames, tag.SlugName)
+		}
+		items = append(items, &export.TagAlertTemplateItem{
+			Title:      questionInfo.Title,
+			Url:        fmt.Sprintf("%s/questions/%s", siteGeneral.SiteUrl, questionInfo.ID),
+			Tags:       strings.Join(tagNames, ", "),
+			Expression: alert.Expression,
+			Excerpt:    htmltext.FetchExcerpt(questionInfo.ParsedText, "...", 120),
+		})
+	}
+	if len(items) == 0 {
+		return
+	}
+	settingURL := fmt.Sprintf("%s/users/settings/notify", siteGeneral.SiteUrl)
+	title, body, err := ts.emailService.TagAlertTemplate(ctx, settingURL, items)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	ts.emailService.Send(ctx, userInfo.EMail, title, body, "", "")
+}
+
+// getUserSubscription get the subscription of the user, the subscription of the others is regarded as not found
+func (ts *TagSubscriptionService) getUserSubscription(ctx context.Context, id, userID string) (
+	subscription *entity.TagSubscription, err error) {
+	subscription, exist, err := ts.tagSubscriptionRepo.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !exist || subscription.UserID != userID {
+		return nil, errors.BadRequest(reason.TagSubscriptionNotFound)
+	}
+	return subscription, nil
+}
+
+// parseExpression parse the expression, and replace the synonyms in it by their main tags,
+// as the questions are tagged with the main tags
+func (ts *TagSubscriptionService) parseExpression(ctx context.Context, expression string) (
+	expr *tagexpr.Expr, err error) {
+	expr, err = tagexpr.Parse(expression)
+	if err != nil {
+		return nil, errors.BadRequest(reason.TagSubscriptionExpressionInvalid).WithError(err)
+	}
+	tagList, err := ts.tagCommonService.GetTagListByNames(ctx, expr.Tags())
+	if err != nil {
+		return nil, err
+	}
+	mainTags := make(map[string]string)
+	for _, tag := range tagList {
+		if tag.MainTagID != 0 && len(tag.MainTagSlugName) > 0 {
+			mainTags[tag.SlugName] = tag.MainTagSlugName
+		}
+	}
+	expr.ReplaceTags(mainTags)
+	return expr, nil
+}
+
+func formatSubscription(subscription *entity.TagSubscription) *schema.TagSubscriptionResp {
+	return &schema.TagSubscriptionResp{
+		ID:         subscription.ID,
+		Expression: subscription.Expression,
+		Frequency:  subscription.Frequency,
+		CreatedAt:  subscription.CreatedAt.Unix(),
+	}
+}
+
+// inQuietHours whether the time is in the quiet hours of the user, the invalid time zone is regarded as UTC
+func inQuietHours(setting *entity.TagAlertSetting, now time.Time) bool {
+	if setting == nil || setting.QuietHoursStart == setting.QuietHoursEnd {
+		return false
+	}
+	location, err := time.LoadLocation(setting.TimeZone)
+	if err != nil {
+		location = time.UTC
+	}
+	hour := now.In(location).Hour()
+	if setting.QuietHoursStart < setting.QuietHoursEnd {
+		return hour >= setting.QuietHoursStart && hour < setting.QuietHoursEnd
+	}
+	// the quiet hours cross the midnight, such as from 22 to 7
+	return hour >= setting.QuietHoursStart || hour < setting.QuietHoursEnd
+}
@@ -0,0 +1,250 @@
+package tag_template
+
+import (
+	"context"
+
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/schema"
+	tagcommon "answer/internal/service/tag_common"
+	"answer/internal/service/tag_moderator"
+	"answer/pkg/converter"
+	"answer/pkg/mdsection"
+
+	"github.com/segmentfault/pacman/errors"
+)
+
+// TagTemplateRepo tag template repository
+type TagTemplateRepo interface {
+	SaveTagTemplate(ctx context.Context, template *entity.TagTemplate) (err error)
+	RemoveTagTemplate(ctx context.Context, tagID string) (err error)
+	GetTagTemplate(ctx context.Context, tagID string) (template *entity.TagTemplate, exist bool, err error)
+	GetTagTemplateList(ctx context.Context, tagIDs []string) (templates []*entity.TagTemplate, err error)
+}
+
+// TagTemplateService the question templates of the tags
+type TagTemplateService struct {
+	tagTemplateRepo     TagTemplateRepo
+	tagCommonService    *tagcommon.TagCommonService
+	tagModeratorService *tag_moderator.TagModeratorService
+}
+
+// NewTagTemplateService new tag template service
+func NewTagTemplateService(
+	tagTemplateRepo TagTemplateRepo,
+	tagCommonService *tagcommon.TagCommonService,
+	tagModeratorService *tag_moderator.TagModeratorService,
+) *TagTemplateService {
+	return &TagTemplateService{
+		tagTemplateRepo:     tagTemplateRepo,
+		tagCommonService:    tagCommonService,
+		tagModeratorService: tagModeratorService,
+	}
+}
+
+// SaveTagTemplate create or replace the question template of the tag
+func (ts *TagTemplateService) SaveTagTemplate(ctx context.Context, req *schema.SaveTagTemplateReq) (err error) {
+	if err = ts.checkCanManage(ctx, req.UserID, req.TagID, req.IsAdmin); err != nil {
+		return err
+	}
+	tagInfo, err := ts.getMainTag(ctx, req.TagID)
+	if err != nil {
+		return err
+	}
+	requiredSections := make([]string, 0, len(req.RequiredSections))
+	for _, section := range req.RequiredSections {
+		if len(mdsection.Normalize(section)) > 0 {
+			requiredSections = append(requiredSections, section)
+		}
+	}
+	return ts.tagTemplateRepo.SaveTagTemplate(ctx, &entity.TagTemplate{
+		TagID:            tagInfo.ID,
+		UserID:           req.UserID,
+		Content:          req.Content,
+		RequiredSections: requiredSections,
+	})
+}
+
+// RemoveTagTemplate remove the question template of the tag
+func (ts *TagTemplateService) RemoveTagTemplate(ctx context.Context, req *schema.RemoveTagTemplateReq) (err error) {
+	if err = ts.checkCanManage(ctx, req.UserID, req.TagID, req.IsAdmin); err != nil {
+		return err
+	}
+	tagInfo, err := ts.getMainTag(ctx, req.TagID)
+	if err != nil {
+		return err
+	}
+	return ts.tagTemplateRepo.RemoveTagTemplate(ctx, tagInfo.ID)
+}
+
+// GetTagTemplate get the question template of the tag, nil if the tag has no template
+func (ts *TagTemplateService) GetTagTemplate(ctx context.Context, tagInfo *entity.Tag) (
+	resp *schema.TagTemplateResp, err error) {
+	template, exist, err := ts.tagTemplateRepo.GetTagTemplate(ctx, tagInfo.ID)
+	if err != nil || !exist {
+		return nil, err
+	}
+	return ts.formatTagTemplate(template, tagInfo), nil
+}
+
+// GetTagTemplateList get the question templates of the selected tags, the tags without template are skipped
+func (ts *TagTemplateService) GetTagTemplateList(ctx context.Context, req *schema.GetTagTemplateReq) (
+	resp []*schema.TagTemplateResp, err error) {
+	resp = make([]*schema.TagTemplateResp, 0)
+	tagList, err := ts.getMainTagList(ctx, req.TagNames)
+	if err != nil {
+		return nil, err
+	}
+	templates, err := ts.getTemplates(ctx, tagList)
+	if err != nil {
+		return nil, err
+	}
+	for _, tagInfo := range tagList {
+		if template, ok := templates[tagInfo.ID]; ok {
+			resp = append(resp, ts.formatTagTemplate(template, tagInfo))
+		}
+	}
+	return resp, nil
+}
+
+// CheckQuestionContent check the question content against the templates of the tags,
+// return the required sections which are left empty
+func (ts *TagTemplateService) CheckQuestionContent(ctx context.Context, tags []*schema.TagItem, content string) (
+	missing []string, err error) {
+	tagNames := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tagNames = append(tagNames, tag.SlugName)
+	}
+	tagList, err := ts.getMainTagList(ctx, tagNames)
+	if err != nil {
+		return nil, err
+	}
+	templates, err := ts.getTemplates(ctx, tagList)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	for _, tagInfo := range tagList {
+		template, ok := templates[tagInfo.ID]
+		if !ok {
+			continue
+		}
+		for _, section := range mdsection.Missing(content, template.Content, template.RequiredSections) {
+			if key := mdsection.Normalize(section); !seen[key] {
+				seen[key] = true
+				missing = append(missing, section)
+			}
+		}
+	}
+	return missing, nil
+}
+
+// checkCanManage the template of the tag is managed by the admins and the moderators of the tag
+func (ts *TagTemplateService) checkCanManage(ctx context.Context, userID, tagID string, isAdmin bool) (err error) {
+	if isAdmin {
+		return nil
+	}
+	isModerator, err := ts.tagModeratorService.IsTagModerator(ctx, userID, tagID)
+	if err != nil {
+		return err
+	}
+	if !isModerator {
+		return errors.Forbidden(reason.RankFailToMeetTheCondition)
+	}
+	return nil
+}
+
+// getMainTag get the tag, the main tag is returned if it is a synonym
+func (ts *TagTemplateService) getMainTag(ctx context.Context, tagID string) (tagInfo *entity.Tag, err error) {
+	tagInfo, exist, err := ts.tagCommonService.GetTagByID(ctx, tagID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.BadRequest(reason.TagNotFound)
+	}
+	if tagInfo.MainTagID == 0 {
+		return tagInfo, nil
+	}
+	tagInfo, exist, err = ts.tagCommonService.GetTagByID(ctx, converter.IntToString(tagInfo.MainTagID))
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.BadRequest(reason.TagNotFound)
+	}
+	return tagInfo, nil
+}
+
+// getMainTagList get the tags by the slug names, the synonyms are replaced by the main tags
+func (ts *TagTemplateService) getMainTagList(ctx context.Context, tagNames []string) (tagList []*entity.Tag, err error) {
+	tagList = make([]*entity.Tag, 0)
+	if len(tagNames) == 0 {
+		return tagList, nil
+	}
+	tags, err := ts.tagCommonService.GetTagListByNames(ctx, tagNames)
+	if err != nil {
+		return nil, err
+	}
+	mainTagIDs := make([]string, 0)
+	for _, tag := range tags {
+		if tag.MainTagID > 0 {
+			mainTagIDs = append(mainTagIDs, converter.IntToString(tag.MainTagID))
+		} else {
+			tagList = append(tagList, tag)
+		}
+	}
+	if len(mainTagIDs) == 0 {
+		return tagList, nil
+	}
+	mainTags, err := ts.tagCommonService.GetTagListByIDs(ctx, mainTagIDs)
+	if err != nil {
+		return nil, err
+	}
+	added := make(map[string]bool, len(tagList))
+	for _, tag := range tagList {
+		added[tag.ID] = true
+	}
+	for _, tag := range mainTags {
+		if !added[tag.ID] {
+			added[tag.ID] = true
+			tagList = append(tagList, tag)
+		}
+	}
+	return tagList, nil
+}
+
+// getTemplates get the templates of the tags, the key is the tag id
+func (ts *TagTemplateService) getTemplates(ctx context.Context, tagList []*entity.Tag) (
+	templates map[string]*entity.TagTemplate, err error) {
+	templates = make(map[string]*entity.TagTemplate)
+	if len(tagList) == 0 {
+		return templates, nil
+	}
+	tagIDs := make([]string, 0, len(tagList))
+	for _, tag := range tagList {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	list, err := ts.tagTemplateRepo.GetTagTemplateList(ctx, tagIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, template := range list {
+		templates[template.TagID] = template
+	}
+	return templates, nil
+}
+
+func (ts *TagTemplateService) formatTagTemplate(template *entity.TagTemplate, tagInfo *entity.Tag) *schema.TagTemplateResp {
+	requiredSections := template.RequiredSections
+	if requiredSections == nil {
+		requiredSections = make([]string, 0)
+	}
+	return &schema.TagTemplateResp{
+		TagID:            tagInfo.ID,
+		SlugName:         tagInfo.SlugName,
+		Content:          template.Content,
+		RequiredSections: requiredSections,
+		UpdatedAt:        template.UpdatedAt.Unix(),
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"answer/internal/base/reason"
 	"answer/internal/entity"
 	"answer/internal/schema"
+	"answer/internal/service/auth"
+	"answer/internal/service/user_suspension"
 
 	"github.com/jinzhu/copier"
 	"github.com/segmentfault/pacman/errors"
@@ -23,12 +25,20 @@ type UserBackyardRepo interface {
 
 // UserBackyardService user service
 type UserBackyardService struct {
-	userRepo UserBackyardRepo
+	userRepo              UserBackyardRepo
+	authService           *auth.AuthService
+	userSuspensionService *user_suspension.UserSuspensionService
 }
 
-func NewUserBackyardService(userRepo UserBackyardRepo) *UserBackyardService {
+func NewUserBackyardService(
+	userRepo UserBackyardRepo,
+	authService *auth.AuthService,
+	userSuspensionService *user_suspension.UserSuspensionService,
+) *UserBackyardService {
 	return &UserBackyardService{
-		userRepo: userRepo,
+		userRepo:              userRepo,
+		authService:           authService,
+		userSuspensionService: userSuspensionService,
 	}
 }
 
@@ -59,8 +69,36 @@ func (us *UserBackyardService) UpdateUserStatus(ctx context.Context, req *schema
 	if req.IsNormal() {
 		userInfo.Status = entity.UserStatusAvailable
 		userInfo.MailStatus = entity.EmailStatusAvailable
+		if err = us.userSuspensionService.LiftSuspension(ctx, userInfo.ID, req.OperatorID); err != nil {
+			return err
+		}
+	}
+	err = us.userRepo.UpdateUserStatus(ctx, userInfo.ID, userInfo.Status, userInfo.MailStatus, userInfo.EMail)
+	if err != nil {
+		return err
+	}
+	if req.IsSuspended() {
+		if err = us.userSuspensionService.SuspendUser(ctx, userInfo, req); err != nil {
+			return err
+		}
+	}
+	// the suspended or deleted user is signed out everywhere
+	if req.IsSuspended() || req.IsDeleted() {
+		return us.authService.RevokeUserSessions(ctx, userInfo.ID, "")
+	}
+	return nil
+}
+
+// SignOutUser sign out the user everywhere
+func (us *UserBackyardService) SignOutUser(ctx context.Context, req *schema.SignOutUserReq) (err error) {
+	_, exist, err := us.userRepo.GetUserInfo(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.UserNotFound)
 	}
-	return us.userRepo.UpdateUserStatus(ctx, userInfo.ID, userInfo.Status, userInfo.MailStatus, userInfo.EMail)
+	return us.authService.RevokeUserSessions(ctx, req.UserID, "")
 }
 
 // GetUserInfo get user one
@@ -16,6 +16,7 @@ type UserRepo interface {
 	UpdateNoticeStatus(ctx context.Context, userID string, noticeStatus int) error
 	UpdateEmail(ctx context.Context, userID, email string) error
 	UpdateLanguage(ctx context.Context, userID, language string) error
+	UpdateContentLanguages(ctx context.Context, userID, contentLanguages string) error
 	UpdatePass(ctx context.Context, userID, pass string) error
 	UpdateInfo(ctx context.Context, userInfo *entity.User) (err error)
 	GetByUserID(ctx context.Context, userID string) (userInfo *entity.User, exist bool, err error)
@@ -55,6 +56,15 @@ func (us *UserCommon) GetUserBasicInfoByUserName(ctx context.Context, username s
 	return info, exist, nil
 }
 
+// GetUserContentLanguages get the language codes of the content which the user prefers to browse
+func (us *UserCommon) GetUserContentLanguages(ctx context.Context, userID string) (languages []string, err error) {
+	userInfo, exist, err := us.userRepo.GetByUserID(ctx, userID)
+	if err != nil || !exist {
+		return nil, err
+	}
+	return schema.SplitContentLanguages(userInfo.ContentLanguages), nil
+}
+
 func (us *UserCommon) UpdateAnswerCount(ctx context.Context, userID string, num int) error {
 	return us.userRepo.IncreaseAnswerCount(ctx, userID, num)
 }
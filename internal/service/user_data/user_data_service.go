@@ -0,0 +1,541 @@
+package user_data
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"answer/internal/base/constant"
+	"answer/internal/base/reason"
+	"answer/internal/base/scheduler"
+	"answer/internal/entity"
+	"answer/internal/schema"
+	"answer/internal/service/activity_type"
+	"answer/internal/service/auth"
+	"answer/internal/service/config"
+	usercommon "answer/internal/service/user_common"
+
+	"github.com/google/uuid"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// voteActivityTypeKeys the activity types of votes which will be exported
+var voteActivityTypeKeys = []string{
+	"question.vote_up",
+	"question.vote_down",
+	"answer.vote_up",
+	"answer.vote_down",
+}
+
+// UserDataRepo user data repository
+type UserDataRepo interface {
+	GetUserQuestions(ctx context.Context, userID string) (questions []*entity.Question, err error)
+	GetUserAnswers(ctx context.Context, userID string) (answers []*entity.Answer, err error)
+	GetUserComments(ctx context.Context, userID string) (comments []*entity.Comment, err error)
+	GetUserActivities(ctx context.Context, userID string, activityTypes []int) (activities []*entity.Activity, err error)
+	GetUserCollections(ctx context.Context, userID string) (collections []*entity.Collection, err error)
+	GetUserNotifications(ctx context.Context, userID string) (notifications []*entity.Notification, err error)
+	GetGhostUser(ctx context.Context) (ghost *entity.User, err error)
+	AnonymizeUserContent(ctx context.Context, userID, ghostUserID string) (err error)
+	RemoveUserContent(ctx context.Context, userID string) (err error)
+	ClearUserInfo(ctx context.Context, userID string) (err error)
+}
+
+// UserDeletionRepo user deletion repository
+type UserDeletionRepo interface {
+	AddUserDeletion(ctx context.Context, deletion *entity.UserDeletion) (err error)
+	GetPendingUserDeletion(ctx context.Context, userID string) (deletion *entity.UserDeletion, exist bool, err error)
+	GetDueUserDeletions(ctx context.Context, now time.Time) (deletions []*entity.UserDeletion, err error)
+	UpdateUserDeletionStatus(ctx context.Context, id string, status int) (err error)
+}
+
+// UserDataExportRepo user data export repository
+type UserDataExportRepo interface {
+	GetUserDataExport(ctx context.Context, userID string) (export *entity.UserDataExport, exist bool, err error)
+	AddUserDataExport(ctx context.Context, export *entity.UserDataExport) (err error)
+	UpdateUserDataExport(ctx context.Context, export *entity.UserDataExport) (err error)
+	FailPendingUserDataExports(ctx context.Context, before time.Time) (err error)
+	RemoveUserDataExport(ctx context.Context, userID string) (err error)
+}
+
+// UserDataService user data service, export the data of the user and delete the account
+type UserDataService struct {
+	userDataExportRepo UserDataExportRepo
+	userRepo           usercommon.UserRepo
+	userDataRepo       UserDataRepo
+	userDeletionRepo   UserDeletionRepo
+	configRepo         config.ConfigRepo
+	authService        *auth.AuthService
+	// startedAt the exports which are pending before the server started are interrupted by the restart
+	startedAt time.Time
+}
+
+// NewUserDataService new user data service
+func NewUserDataService(
+	userDataExportRepo UserDataExportRepo,
+	userRepo usercommon.UserRepo,
+	userDataRepo UserDataRepo,
+	userDeletionRepo UserDeletionRepo,
+	configRepo config.ConfigRepo,
+	authService *auth.AuthService,
+	scheduler *scheduler.Scheduler,
+) *UserDataService {
+	us := &UserDataService{
+		userDataExportRepo: userDataExportRepo,
+		userRepo:           userRepo,
+		userDataRepo:       userDataRepo,
+		userDeletionRepo:   userDeletionRepo,
+		configRepo:         configRepo,
+		authService:        authService,
+		startedAt:          time.Now().Truncate(time.Second),
+	}
+	scheduler.AddJob("user_deletion", constant.UserDeletionCheckInterval, true, us.ExecuteUserDeletions)
+	return us
+}
+
+// ExportUserData start a job to export all the data of the user as a zip file
+func (us *UserDataService) ExportUserData(ctx context.Context, userID string) (
+	resp *schema.GetUserDataExportResp, err error) {
+	exportInfo, exist, err := us.userDataExportRepo.GetUserDataExport(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if exist && us.exportStatus(exportInfo) == schema.UserDataExportStatusPending {
+		return us.formatExportInfo(exportInfo), nil
+	}
+	if exist {
+		us.removeExportFile(exportInfo.FileName)
+	}
+
+	exportInfo = &entity.UserDataExport{
+		UserID: userID,
+		Status: entity.UserDataExportStatusPending,
+	}
+	if err = us.userDataExportRepo.AddUserDataExport(ctx, exportInfo); err != nil {
+		return nil, err
+	}
+	go us.buildExport(context.Background(), exportInfo)
+	return us.formatExportInfo(exportInfo), nil
+}
+
+// GetUserDataExport get the status of the user data export job
+func (us *UserDataService) GetUserDataExport(ctx context.Context, userID string) (
+	resp *schema.GetUserDataExportResp, err error) {
+	exportInfo, exist, err := us.userDataExportRepo.GetUserDataExport(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return &schema.GetUserDataExportResp{}, nil
+	}
+	return us.formatExportInfo(exportInfo), nil
+}
+
+// GetUserDataExportFile get the file path of the completed user data export
+func (us *UserDataService) GetUserDataExportFile(ctx context.Context, userID string) (filePath string, err error) {
+	exportInfo, exist, err := us.userDataExportRepo.GetUserDataExport(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if !exist || exportInfo.Status != entity.UserDataExportStatusCompleted {
+		return "", errors.BadRequest(reason.UserDataExportNotFound)
+	}
+	if exportInfo.ExpiredAt.Before(time.Now()) {
+		us.removeExportFile(exportInfo.FileName)
+		return "", errors.BadRequest(reason.UserDataExportNotFound)
+	}
+	filePath = filepath.Join(us.exportDir(), exportInfo.FileName)
+	if _, err = os.Stat(filePath); err != nil {
+		return "", errors.BadRequest(reason.UserDataExportNotFound)
+	}
+	return filePath, nil
+}
+
+// AddUserDeletion request to delete the account, the account will be deleted after the grace period
+func (us *UserDataService) AddUserDeletion(ctx context.Context, req *schema.AddUserDeletionReq) (
+	resp *schema.GetUserDeletionResp, err error) {
+	userInfo, exist, err := us.userRepo.GetByUserID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.BadRequest(reason.UserNotFound)
+	}
+	if userInfo.IsAdmin {
+		return nil, errors.BadRequest(reason.AdminCannotDeleteAccount)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(userInfo.Pass), []byte(req.Pass)) != nil {
+		return nil, errors.BadRequest(reason.OldPasswordVerificationFailed)
+	}
+
+	deletion, exist, err := us.userDeletionRepo.GetPendingUserDeletion(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		return us.formatDeletion(deletion), nil
+	}
+	deletion = &entity.UserDeletion{
+		UserID:    req.UserID,
+		Mode:      entity.UserDeletionModeAnonymize,
+		ExecuteAt: time.Now().Add(constant.UserDeletionGracePeriod),
+		Status:    entity.UserDeletionStatusPending,
+	}
+	if req.Mode == schema.UserDeletionModeRemove {
+		deletion.Mode = entity.UserDeletionModeRemove
+	}
+	if err = us.userDeletionRepo.AddUserDeletion(ctx, deletion); err != nil {
+		return nil, err
+	}
+	deletion.CreatedAt = time.Now()
+	return us.formatDeletion(deletion), nil
+}
+
+// GetUserDeletion get the pending deletion of the user
+func (us *UserDataService) GetUserDeletion(ctx context.Context, userID string) (
+	resp *schema.GetUserDeletionResp, err error) {
+	deletion, exist, err := us.userDeletionRepo.GetPendingUserDeletion(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return &schema.GetUserDeletionResp{}, nil
+	}
+	return us.formatDeletion(deletion), nil
+}
+
+// CancelUserDeletion cancel the pending deletion of the user
+func (us *UserDataService) CancelUserDeletion(ctx context.Context, userID string) (err error) {
+	deletion, exist, err := us.userDeletionRepo.GetPendingUserDeletion(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return nil
+	}
+	return us.userDeletionRepo.UpdateUserDeletionStatus(ctx, deletion.ID, entity.UserDeletionStatusCancelled)
+}
+
+// ExecuteUserDeletions execute the user deletions which have passed the grace period,
+// and delete the expired export files and fail the exports interrupted by the restart by the way
+func (us *UserDataService) ExecuteUserDeletions(ctx context.Context) {
+	us.removeExpiredExportFiles()
+	if err := us.userDataExportRepo.FailPendingUserDataExports(ctx, us.startedAt); err != nil {
+		log.Error(err)
+	}
+	deletions, err := us.userDeletionRepo.GetDueUserDeletions(ctx, time.Now())
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	for _, deletion := range deletions {
+		if err := us.DeleteUser(ctx, deletion); err != nil {
+			log.Errorf("delete user %s failed: %s", deletion.UserID, err)
+		}
+	}
+}
+
+// DeleteUser delete the user account and the content by the deletion mode, and purge the sessions of the user
+func (us *UserDataService) DeleteUser(ctx context.Context, deletion *entity.UserDeletion) (err error) {
+	log.Infof("user %s account deletion is executing, mode %d", deletion.UserID, deletion.Mode)
+	if deletion.Mode == entity.UserDeletionModeRemove {
+		err = us.userDataRepo.RemoveUserContent(ctx, deletion.UserID)
+	} else {
+		var ghost *entity.User
+		ghost, err = us.userDataRepo.GetGhostUser(ctx)
+		if err != nil {
+			return err
+		}
+		err = us.userDataRepo.AnonymizeUserContent(ctx, deletion.UserID, ghost.ID)
+	}
+	if err != nil {
+		return err
+	}
+	if err = us.userDataRepo.ClearUserInfo(ctx, deletion.UserID); err != nil {
+		return err
+	}
+
+	// all the sessions of the user will be unauthorized when the user status is deleted
+	err = us.authService.SetUserStatus(ctx, &entity.UserCacheInfo{
+		UserID:     deletion.UserID,
+		UserStatus: entity.UserStatusDeleted,
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	if err = us.authService.RevokeUserSessions(ctx, deletion.UserID, ""); err != nil {
+		log.Error(err)
+	}
+	exportInfo, exist, err := us.userDataExportRepo.GetUserDataExport(ctx, deletion.UserID)
+	if err != nil {
+		return err
+	}
+	if exist {
+		us.removeExportFile(exportInfo.FileName)
+		if err = us.userDataExportRepo.RemoveUserDataExport(ctx, deletion.UserID); err != nil {
+			return err
+		}
+	}
+	return us.userDeletionRepo.UpdateUserDeletionStatus(ctx, deletion.ID, entity.UserDeletionStatusCompleted)
+}
+
+func (us *UserDataService) buildExport(ctx context.Context, exportInfo *entity.UserDataExport) {
+	fileName := fmt.Sprintf("%s-%s.zip", exportInfo.UserID, uuid.NewString())
+	if err := us.writeExportFile(ctx, exportInfo.UserID, fileName); err != nil {
+		log.Errorf("export user %s data failed: %s", exportInfo.UserID, err)
+		us.removeExportFile(fileName)
+		exportInfo.Status = entity.UserDataExportStatusFailed
+	} else {
+		exportInfo.Status = entity.UserDataExportStatusCompleted
+		exportInfo.FileName = fileName
+		exportInfo.ExpiredAt = time.Now().Add(constant.UserDataExportExpiration)
+	}
+	if err := us.userDataExportRepo.UpdateUserDataExport(ctx, exportInfo); err != nil {
+		log.Error(err)
+	}
+}
+
+func (us *UserDataService) writeExportFile(ctx context.Context, userID, fileName string) (err error) {
+	files, err := us.collectUserData(ctx, userID)
+	if err != nil {
+		return err
+	}
+	// the export files are only readable by the server, they are downloaded through the api
+	if err = os.MkdirAll(us.exportDir(), 0o700); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(filepath.Join(us.exportDir(), fileName), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	zipWriter := zip.NewWriter(file)
+	for _, name := range []string{"profile", "questions", "answers", "comments", "votes", "collections", "notifications"} {
+		w, err := zipWriter.Create(name + ".json")
+		if err != nil {
+			return err
+		}
+		content, err := json.MarshalIndent(files[name], "", "  ")
+		if err != nil {
+			return err
+		}
+		if _, err = w.Write(content); err != nil {
+			return err
+		}
+	}
+	return zipWriter.Close()
+}
+
+// collectUserData collect all the data of the user, the key is the file name in the zip
+func (us *UserDataService) collectUserData(ctx context.Context, userID string) (files map[string]any, err error) {
+	files = make(map[string]any)
+	userInfo, exist, err := us.userRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.BadRequest(reason.UserNotFound)
+	}
+	profile := &schema.GetUserResp{}
+	profile.GetFromUserEntity(userInfo)
+	files["profile"] = profile
+
+	questions, err := us.userDataRepo.GetUserQuestions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	questionList := make([]*schema.UserDataExportQuestion, 0, len(questions))
+	for _, q := range questions {
+		questionList = append(questionList, &schema.UserDataExportQuestion{
+			ID:           q.ID,
+			Title:        q.Title,
+			Content:      q.OriginalText,
+			HTML:         q.ParsedText,
+			Status:       q.Status,
+			ViewCount:    q.ViewCount,
+			VoteCount:    q.VoteCount,
+			AnswerCount:  q.AnswerCount,
+			CreatedAt:    q.CreatedAt.Unix(),
+			UpdatedAt:    q.UpdatedAt.Unix(),
+			AcceptedID:   q.AcceptedAnswerID,
+			LastAnswerID: q.LastAnswerID,
+		})
+	}
+	files["questions"] = questionList
+
+	answers, err := us.userDataRepo.GetUserAnswers(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	answerList := make([]*schema.UserDataExportAnswer, 0, len(answers))
+	for _, a := range answers {
+		answerList = append(answerList, &schema.UserDataExportAnswer{
+			ID:         a.ID,
+			QuestionID: a.QuestionID,
+			Content:    a.OriginalText,
+			HTML:       a.ParsedText,
+			Status:     a.Status,
+			Adopted:    a.Adopted,
+			VoteCount:  a.VoteCount,
+			CreatedAt:  a.CreatedAt.Unix(),
+			UpdatedAt:  a.UpdatedAt.Unix(),
+		})
+	}
+	files["answers"] = answerList
+
+	comments, err := us.userDataRepo.GetUserComments(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	commentList := make([]*schema.UserDataExportComment, 0, len(comments))
+	for _, c := range comments {
+		commentList = append(commentList, &schema.UserDataExportComment{
+			ID:             c.ID,
+			ObjectID:       c.ObjectID,
+			QuestionID:     c.QuestionID,
+			ReplyUserID:    c.GetReplyUserID(),
+			ReplyCommentID: c.GetReplyCommentID(),
+			Content:        c.OriginalText,
+			HTML:           c.ParsedText,
+			Status:         c.Status,
+			VoteCount:      c.VoteCount,
+			CreatedAt:      c.CreatedAt.Unix(),
+		})
+	}
+	files["comments"] = commentList
+
+	activityTypes := make([]int, 0, len(voteActivityTypeKeys))
+	for _, key := range voteActivityTypeKeys {
+		t, err := us.configRepo.GetConfigType(key)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		activityTypes = append(activityTypes, t)
+	}
+	votes, err := us.userDataRepo.GetUserActivities(ctx, userID, activityTypes)
+	if err != nil {
+		return nil, err
+	}
+	voteList := make([]*schema.UserDataExportVote, 0, len(votes))
+	for _, v := range votes {
+		voteList = append(voteList, &schema.UserDataExportVote{
+			ObjectID:  v.ObjectID,
+			VoteType:  activity_type.Format(v.ActivityType),
+			CreatedAt: v.CreatedAt.Unix(),
+		})
+	}
+	files["votes"] = voteList
+
+	collections, err := us.userDataRepo.GetUserCollections(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	collectionList := make([]*schema.UserDataExportCollection, 0, len(collections))
+	for _, c := range collections {
+		collectionList = append(collectionList, &schema.UserDataExportCollection{
+			ObjectID:  c.ObjectID,
+			GroupID:   c.UserCollectionGroupID,
+			CreatedAt: c.CreatedAt.Unix(),
+		})
+	}
+	files["collections"] = collectionList
+
+	notifications, err := us.userDataRepo.GetUserNotifications(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	notificationList := make([]*schema.UserDataExportNotification, 0, len(notifications))
+	for _, n := range notifications {
+		notificationList = append(notificationList, &schema.UserDataExportNotification{
+			ID:        n.ID,
+			ObjectID:  n.ObjectID,
+			Type:      n.Type,
+			Content:   n.Content,
+			IsRead:    n.IsRead,
+			CreatedAt: n.CreatedAt.Unix(),
+		})
+	}
+	files["notifications"] = notificationList
+	return files, nil
+}
+
+// exportStatus the status of the export, the export which is pending before the server started will never be
+// completed, it is failed even before the periodic check marks it
+func (us *UserDataService) exportStatus(exportInfo *entity.UserDataExport) string {
+	switch exportInfo.Status {
+	case entity.UserDataExportStatusCompleted:
+		return schema.UserDataExportStatusCompleted
+	case entity.UserDataExportStatusPending:
+		if exportInfo.CreatedAt.Before(us.startedAt) {
+			return schema.UserDataExportStatusFailed
+		}
+		return schema.UserDataExportStatusPending
+	default:
+		return schema.UserDataExportStatusFailed
+	}
+}
+
+func (us *UserDataService) formatExportInfo(exportInfo *entity.UserDataExport) *schema.GetUserDataExportResp {
+	resp := &schema.GetUserDataExportResp{
+		Status:    us.exportStatus(exportInfo),
+		CreatedAt: exportInfo.CreatedAt.Unix(),
+	}
+	if !exportInfo.ExpiredAt.IsZero() {
+		resp.ExpiredAt = exportInfo.ExpiredAt.Unix()
+	}
+	return resp
+}
+
+func (us *UserDataService) formatDeletion(deletion *entity.UserDeletion) *schema.GetUserDeletionResp {
+	resp := &schema.GetUserDeletionResp{
+		Pending:   true,
+		Mode:      schema.UserDeletionModeAnonymize,
+		CreatedAt: deletion.CreatedAt.Unix(),
+		ExecuteAt: deletion.ExecuteAt.Unix(),
+	}
+	if deletion.Mode == entity.UserDeletionModeRemove {
+		resp.Mode = schema.UserDeletionModeRemove
+	}
+	return resp
+}
+
+// exportDir the export files are kept in the temporary directory, out of any directory served as static files
+func (us *UserDataService) exportDir() string {
+	return filepath.Join(os.TempDir(), "answer", constant.UserDataExportDirName)
+}
+
+func (us *UserDataService) removeExportFile(fileName string) {
+	if len(fileName) == 0 {
+		return
+	}
+	if err := os.Remove(filepath.Join(us.exportDir(), fileName)); err != nil && !os.IsNotExist(err) {
+		log.Error(err)
+	}
+}
+
+// removeExpiredExportFiles delete the export files which are expired, including the ones no longer referred to
+func (us *UserDataService) removeExpiredExportFiles() {
+	entries, err := os.ReadDir(us.exportDir())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Error(err)
+		}
+		return
+	}
+	expiredAt := time.Now().Add(-constant.UserDataExportExpiration)
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() || info.ModTime().After(expiredAt) {
+			continue
+		}
+		us.removeExportFile(entry.Name())
+	}
+}
@@ -0,0 +1,161 @@
+package user_invite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"answer/internal/base/pager"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/schema"
+	"answer/internal/service/siteinfo_common"
+
+	"github.com/google/uuid"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// defaultInviteExpireDays the invite link will expire after 7 days by default
+const defaultInviteExpireDays = 7
+
+// UserInviteRepo user invite repository
+type UserInviteRepo interface {
+	AddUserInvite(ctx context.Context, invite *entity.UserInvite) (err error)
+	GetUserInviteByCode(ctx context.Context, code string) (invite *entity.UserInvite, exist bool, err error)
+	GetUserInvitePage(ctx context.Context, page, pageSize int) (invites []*entity.UserInvite, total int64, err error)
+	AddUserWithInvite(ctx context.Context, code string, user *entity.User) (ok bool, err error)
+	RemoveUserInvite(ctx context.Context, code string) (err error)
+}
+
+// UserInviteService user invite service
+type UserInviteService struct {
+	userInviteRepo        UserInviteRepo
+	siteInfoCommonService *siteinfo_common.SiteInfoCommonService
+}
+
+// NewUserInviteService new user invite service
+func NewUserInviteService(
+	userInviteRepo UserInviteRepo,
+	siteInfoCommonService *siteinfo_common.SiteInfoCommonService,
+) *UserInviteService {
+	return &UserInviteService{
+		userInviteRepo:        userInviteRepo,
+		siteInfoCommonService: siteInfoCommonService,
+	}
+}
+
+// AddUserInvite add user invite
+func (us *UserInviteService) AddUserInvite(ctx context.Context, req *schema.AddUserInviteReq) (
+	resp *schema.AddUserInviteResp, err error) {
+	if req.ExpireDays == 0 {
+		req.ExpireDays = defaultInviteExpireDays
+	}
+	invite := &entity.UserInvite{
+		Code:          strings.ReplaceAll(uuid.NewString(), "-", ""),
+		Email:         strings.ToLower(req.Email),
+		CreatorUserID: req.UserID,
+		ExpiredAt:     time.Now().AddDate(0, 0, req.ExpireDays),
+		Status:        entity.UserInviteStatusAvailable,
+	}
+	if err = us.userInviteRepo.AddUserInvite(ctx, invite); err != nil {
+		return nil, err
+	}
+	resp = &schema.AddUserInviteResp{
+		Code:      invite.Code,
+		InviteURL: us.getInviteURL(ctx, invite.Code),
+		ExpiredAt: invite.ExpiredAt.Unix(),
+	}
+	return resp, nil
+}
+
+// RemoveUserInvite remove user invite
+func (us *UserInviteService) RemoveUserInvite(ctx context.Context, req *schema.RemoveUserInviteReq) (err error) {
+	return us.userInviteRepo.RemoveUserInvite(ctx, req.Code)
+}
+
+// GetUserInvitePage get user invite page
+func (us *UserInviteService) GetUserInvitePage(ctx context.Context, req *schema.GetUserInvitePageReq) (
+	pageModel *pager.PageModel, err error) {
+	invites, total, err := us.userInviteRepo.GetUserInvitePage(ctx, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]*schema.GetUserInvitePageResp, 0, len(invites))
+	for _, invite := range invites {
+		t := &schema.GetUserInvitePageResp{
+			Code:       invite.Code,
+			InviteURL:  us.getInviteURL(ctx, invite.Code),
+			Email:      invite.Email,
+			CreatedAt:  invite.CreatedAt.Unix(),
+			ExpiredAt:  invite.ExpiredAt.Unix(),
+			UsedUserID: invite.UsedUserID,
+		}
+		switch {
+		case invite.Status == entity.UserInviteStatusUsed:
+			t.Status = schema.UserInviteUsed
+		case invite.ExpiredAt.Before(time.Now()):
+			t.Status = schema.UserInviteExpired
+		default:
+			t.Status = schema.UserInviteAvailable
+		}
+		resp = append(resp, t)
+	}
+	return pager.NewPageModel(total, resp), nil
+}
+
+// CheckUserInvite check the invite code is available and can be used by this email.
+// If email is empty, the email will not be checked.
+func (us *UserInviteService) CheckUserInvite(ctx context.Context, code, email string) (
+	invite *entity.UserInvite, err error) {
+	if len(code) == 0 {
+		return nil, errors.BadRequest(reason.UserInviteRequired)
+	}
+	invite, exist, err := us.userInviteRepo.GetUserInviteByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if !exist || invite.Status != entity.UserInviteStatusAvailable || invite.ExpiredAt.Before(time.Now()) {
+		return nil, errors.BadRequest(reason.UserInviteInvalid)
+	}
+	if len(email) > 0 && len(invite.Email) > 0 && !strings.EqualFold(invite.Email, email) {
+		return nil, errors.BadRequest(reason.UserInviteEmailMismatch)
+	}
+	return invite, nil
+}
+
+// GetUserInvite get the user invite information by code for the register page
+func (us *UserInviteService) GetUserInvite(ctx context.Context, req *schema.CheckUserInviteReq) (
+	resp *schema.CheckUserInviteResp, err error) {
+	invite, err := us.CheckUserInvite(ctx, req.Code, "")
+	if err != nil {
+		return nil, err
+	}
+	return &schema.CheckUserInviteResp{
+		Email:     invite.Email,
+		ExpiredAt: invite.ExpiredAt.Unix(),
+	}, nil
+}
+
+// AddUserWithInvite add the user and mark the invite code as used by the user, neither is done if the invite
+// has been used by others
+func (us *UserInviteService) AddUserWithInvite(ctx context.Context, code string, user *entity.User) (err error) {
+	ok, err := us.userInviteRepo.AddUserWithInvite(ctx, code, user)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.BadRequest(reason.UserInviteInvalid)
+	}
+	return nil
+}
+
+func (us *UserInviteService) getInviteURL(ctx context.Context, code string) string {
+	siteGeneral, err := us.siteInfoCommonService.GetSiteGeneral(ctx)
+	if err != nil {
+		log.Error(err)
+		return ""
+	}
+	return fmt.Sprintf("%s/users/register?invite_code=%s", siteGeneral.SiteUrl, code)
+}
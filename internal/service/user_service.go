@@ -21,7 +21,11 @@ import (
 	"answer/internal/service/service_config"
 	"answer/internal/service/siteinfo_common"
 	usercommon "answer/internal/service/user_common"
+	"answer/internal/service/user_invite"
+	"answer/internal/service/user_suspension"
+	"answer/internal/service/user_two_factor"
 	"answer/pkg/checker"
+	"answer/pkg/langdetect"
 
 	"github.com/Chain-Zhang/pinyin"
 	"github.com/google/uuid"
@@ -34,12 +38,15 @@ import (
 
 // UserService user service
 type UserService struct {
-	userRepo        usercommon.UserRepo
-	userActivity    activity.UserActiveActivityRepo
-	serviceConfig   *service_config.ServiceConfig
-	emailService    *export.EmailService
-	authService     *auth.AuthService
-	siteInfoService *siteinfo_common.SiteInfoCommonService
+	userRepo          usercommon.UserRepo
+	userActivity      activity.UserActiveActivityRepo
+	serviceConfig     *service_config.ServiceConfig
+	emailService      *export.EmailService
+	authService       *auth.AuthService
+	siteInfoService   *siteinfo_common.SiteInfoCommonService
+	userInviteService *user_invite.UserInviteService
+	twoFactorService  *user_two_factor.UserTwoFactorService
+	suspensionService *user_suspension.UserSuspensionService
 }
 
 func NewUserService(userRepo usercommon.UserRepo,
@@ -48,14 +55,20 @@ func NewUserService(userRepo usercommon.UserRepo,
 	authService *auth.AuthService,
 	serviceConfig *service_config.ServiceConfig,
 	siteInfoService *siteinfo_common.SiteInfoCommonService,
+	userInviteService *user_invite.UserInviteService,
+	twoFactorService *user_two_factor.UserTwoFactorService,
+	suspensionService *user_suspension.UserSuspensionService,
 ) *UserService {
 	return &UserService{
-		userRepo:        userRepo,
-		userActivity:    userActivity,
-		emailService:    emailService,
-		serviceConfig:   serviceConfig,
-		authService:     authService,
-		siteInfoService: siteInfoService,
+		userRepo:          userRepo,
+		userActivity:      userActivity,
+		emailService:      emailService,
+		serviceConfig:     serviceConfig,
+		authService:       authService,
+		siteInfoService:   siteInfoService,
+		userInviteService: userInviteService,
+		twoFactorService:  twoFactorService,
+		suspensionService: suspensionService,
 	}
 }
 
@@ -71,6 +84,13 @@ func (us *UserService) GetUserInfoByUserID(ctx context.Context, token, userID st
 	resp = &schema.GetUserToSetShowResp{}
 	resp.GetFromUserEntity(userInfo)
 	resp.AccessToken = token
+	// the suspended user can see why and until when the account is suspended
+	if userInfo.Status == entity.UserStatusSuspended {
+		resp.Suspension, err = us.suspensionService.GetActiveSuspension(ctx, userInfo.ID)
+		if err != nil {
+			log.Error(err)
+		}
+	}
 	return resp, nil
 }
 
@@ -104,6 +124,43 @@ func (us *UserService) EmailLogin(ctx context.Context, req *schema.UserEmailLogi
 		return nil, errors.BadRequest(reason.EmailOrPasswordWrong)
 	}
 
+	// the login is pending until the two factor code is verified
+	twoFactorEnabled, err := us.twoFactorService.IsEnabled(ctx, userInfo.ID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactorEnabled {
+		resp = &schema.GetUserResp{TwoFactorRequired: true}
+		resp.TwoFactorToken, err = us.twoFactorService.AddPendingLogin(ctx, userInfo.ID)
+		if err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+	return us.login(ctx, userInfo, false, req.IP, req.UserAgent)
+}
+
+// TwoFactorLogin the second step of login, verify the two factor code of the pending login
+func (us *UserService) TwoFactorLogin(ctx context.Context, req *schema.UserTwoFactorLoginReq) (
+	resp *schema.GetUserResp, err error) {
+	userID, err := us.twoFactorService.VerifyPendingLogin(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	userInfo, exist, err := us.userRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist || userInfo.Status == entity.UserStatusDeleted {
+		return nil, errors.BadRequest(reason.UserNotFound)
+	}
+	return us.login(ctx, userInfo, true, req.IP, req.UserAgent)
+}
+
+// login issue the access token for user. If the site requires two factor authentication for admin,
+// the admin token is not issued until the admin has set it up.
+func (us *UserService) login(ctx context.Context, userInfo *entity.User, twoFactorEnabled bool,
+	clientIP, userAgent string) (resp *schema.GetUserResp, err error) {
 	err = us.userRepo.UpdateLastLoginDate(ctx, userInfo.ID)
 	if err != nil {
 		log.Error("UpdateLastLoginDate", err.Error())
@@ -117,11 +174,15 @@ func (us *UserService) EmailLogin(ctx context.Context, req *schema.UserEmailLogi
 		UserStatus:  userInfo.Status,
 		IsAdmin:     userInfo.IsAdmin,
 	}
-	resp.AccessToken, err = us.authService.SetUserCacheInfo(ctx, userCacheInfo)
+	resp.AccessToken, err = us.authService.SetUserCacheInfo(ctx, userCacheInfo, clientIP, userAgent)
 	if err != nil {
 		return nil, err
 	}
 	resp.IsAdmin = userInfo.IsAdmin
+	if resp.IsAdmin && !twoFactorEnabled && us.twoFactorService.IsRequired(ctx, userInfo) {
+		resp.TwoFactorSetupRequired = true
+		return resp, nil
+	}
 	if resp.IsAdmin {
 		err = us.authService.SetCmsUserCacheInfo(ctx, resp.AccessToken, userCacheInfo)
 		if err != nil {
@@ -132,6 +193,28 @@ func (us *UserService) EmailLogin(ctx context.Context, req *schema.UserEmailLogi
 	return resp, nil
 }
 
+// GetUserSessionList get the active sessions of user
+func (us *UserService) GetUserSessionList(ctx context.Context, userID, currentSessionID string) (
+	resp []*schema.GetUserSessionResp, err error) {
+	sessions, err := us.authService.GetUserSessionList(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp = make([]*schema.GetUserSessionResp, 0, len(sessions))
+	for _, session := range sessions {
+		resp = append(resp, &schema.GetUserSessionResp{
+			ID:         session.ID,
+			Device:     session.Device,
+			IP:         session.IP,
+			UserAgent:  session.UserAgent,
+			CreatedAt:  session.CreatedAt.Unix(),
+			LastSeenAt: session.LastSeenAt.Unix(),
+			Current:    session.ID == currentSessionID,
+		})
+	}
+	return resp, nil
+}
+
 // RetrievePassWord .
 func (us *UserService) RetrievePassWord(ctx context.Context, req *schema.UserRetrievePassWordRequest) (string, error) {
 	userInfo, has, err := us.userRepo.GetByEmail(ctx, req.Email)
@@ -180,6 +263,10 @@ func (us *UserService) UseRePassword(ctx context.Context, req *schema.UserRePass
 	if err != nil {
 		return nil, err
 	}
+	// the password is reset, maybe because the account is stolen, so sign out everywhere
+	if err = us.authService.RevokeUserSessions(ctx, userInfo.ID, ""); err != nil {
+		return nil, err
+	}
 	resp = &schema.GetUserResp{}
 	return resp, nil
 }
@@ -221,7 +308,8 @@ func (us *UserService) UserModifyPassword(ctx context.Context, request *schema.U
 	if err != nil {
 		return err
 	}
-	return nil
+	// sign out the other sessions, the current session is kept
+	return us.authService.RevokeUserSessions(ctx, userInfo.ID, request.SessionID)
 }
 
 // UpdateInfo update user info
@@ -272,13 +360,52 @@ func (us *UserService) UserUpdateInterface(ctx context.Context, req *schema.Upda
 	if err != nil {
 		return
 	}
-	return nil
+	if req.ContentLanguages == nil {
+		return nil
+	}
+	languages := make([]string, 0, len(req.ContentLanguages))
+	added := make(map[string]bool)
+	for _, language := range req.ContentLanguages {
+		language = langdetect.Normalize(language)
+		if len(language) == 0 {
+			return errors.BadRequest(reason.LangNotFound)
+		}
+		if !added[language] {
+			added[language] = true
+			languages = append(languages, language)
+		}
+	}
+	return us.userRepo.UpdateContentLanguages(ctx, req.UserId, strings.Join(languages, ","))
 }
 
 // UserRegisterByEmail user register
 func (us *UserService) UserRegisterByEmail(ctx context.Context, registerUserInfo *schema.UserRegisterReq) (
 	resp *schema.GetUserResp, err error,
 ) {
+	siteLogin, err := us.siteInfoService.GetSiteLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// a valid invite link is required when the site is invite only,
+	// and the user invited by admin is not restricted by the allowed email domains
+	hasInvite := false
+	if siteLogin.InviteOnly || len(registerUserInfo.InviteCode) > 0 {
+		_, err = us.userInviteService.CheckUserInvite(ctx, registerUserInfo.InviteCode, registerUserInfo.Email)
+		if err != nil {
+			return nil, err
+		}
+		hasInvite = true
+	}
+	if !hasInvite && !siteLogin.IsAllowedEmail(registerUserInfo.Email) {
+		return nil, errors.BadRequest(reason.EmailIllegalDomainError)
+	}
+	if !hasInvite {
+		err = us.suspensionService.CheckRegistration(ctx, registerUserInfo.Email, registerUserInfo.IP)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	_, has, err := us.userRepo.GetByEmail(ctx, registerUserInfo.Email)
 	if err != nil {
 		return nil, err
@@ -301,7 +428,11 @@ func (us *UserService) UserRegisterByEmail(ctx context.Context, registerUserInfo
 	userInfo.IPInfo = registerUserInfo.IP
 	userInfo.MailStatus = entity.EmailStatusToBeVerified
 	userInfo.Status = entity.UserStatusAvailable
-	err = us.userRepo.AddUser(ctx, userInfo)
+	if hasInvite {
+		err = us.userInviteService.AddUserWithInvite(ctx, registerUserInfo.InviteCode, userInfo)
+	} else {
+		err = us.userRepo.AddUser(ctx, userInfo)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -328,7 +459,7 @@ func (us *UserService) UserRegisterByEmail(ctx context.Context, registerUserInfo
 		UserStatus:  userInfo.Status,
 		IsAdmin:     userInfo.IsAdmin,
 	}
-	resp.AccessToken, err = us.authService.SetUserCacheInfo(ctx, userCacheInfo)
+	resp.AccessToken, err = us.authService.SetUserCacheInfo(ctx, userCacheInfo, registerUserInfo.IP, registerUserInfo.UserAgent)
 	if err != nil {
 		return nil, err
 	}
@@ -407,30 +538,32 @@ func (us *UserService) UserVerifyEmail(ctx context.Context, req *schema.UserVeri
 		log.Error(err)
 	}
 
-	resp = &schema.GetUserResp{}
-	resp.GetFromUserEntity(userInfo)
 	userCacheInfo := &entity.UserCacheInfo{
 		UserID:      userInfo.ID,
 		EmailStatus: userInfo.MailStatus,
 		UserStatus:  userInfo.Status,
 		IsAdmin:     userInfo.IsAdmin,
 	}
-	resp.AccessToken, err = us.authService.SetUserCacheInfo(ctx, userCacheInfo)
-	if err != nil {
-		return nil, err
-	}
 	// User verified email will update user email status. So user status cache should be updated.
 	if err = us.authService.SetUserStatus(ctx, userCacheInfo); err != nil {
 		return nil, err
 	}
-	resp.IsAdmin = userInfo.IsAdmin
-	if resp.IsAdmin {
-		err = us.authService.SetCmsUserCacheInfo(ctx, resp.AccessToken, &entity.UserCacheInfo{UserID: userInfo.ID})
+
+	// the verify email link can not bypass the two factor authentication
+	twoFactorEnabled, err := us.twoFactorService.IsEnabled(ctx, userInfo.ID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactorEnabled {
+		resp = &schema.GetUserResp{TwoFactorRequired: true}
+		resp.TwoFactorToken, err = us.twoFactorService.AddPendingLogin(ctx, userInfo.ID)
 		if err != nil {
 			return nil, err
 		}
+		return resp, nil
 	}
-	return resp, nil
+	// the admin token is issued the same as the login, it is held until the required two factor is set up
+	return us.login(ctx, userInfo, false, req.IP, req.UserAgent)
 }
 
 // makeUsername
@@ -497,6 +630,18 @@ func (us *UserService) UserChangeEmailSendCode(ctx context.Context, req *schema.
 		return nil, errors.BadRequest(reason.UserNotFound)
 	}
 
+	siteLogin, err := us.siteInfoService.GetSiteLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !siteLogin.IsAllowedEmail(req.Email) {
+		resp = append([]*validator.FormErrorField{}, &validator.FormErrorField{
+			ErrorField: "e_mail",
+			ErrorMsg:   translator.GlobalTrans.Tr(handler.GetLangByCtx(ctx), reason.EmailIllegalDomainError),
+		})
+		return resp, errors.BadRequest(reason.EmailIllegalDomainError)
+	}
+
 	_, exist, err = us.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
 		return nil, err
@@ -531,7 +676,7 @@ func (us *UserService) UserChangeEmailSendCode(ctx context.Context, req *schema.
 }
 
 // UserChangeEmailVerify user change email verify code
-func (us *UserService) UserChangeEmailVerify(ctx context.Context, content string) (err error) {
+func (us *UserService) UserChangeEmailVerify(ctx context.Context, content, sessionID string) (err error) {
 	data := &schema.EmailCodeContent{}
 	err = data.FromJSONString(content)
 	if err != nil {
@@ -561,7 +706,8 @@ func (us *UserService) UserChangeEmailVerify(ctx context.Context, content string
 	if err != nil {
 		return err
 	}
-	return nil
+	// sign out the other sessions, the current session is kept
+	return us.authService.RevokeUserSessions(ctx, data.UserID, sessionID)
 }
 
 // getSiteUrl get site url
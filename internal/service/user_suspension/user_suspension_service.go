@@ -0,0 +1,294 @@
+package user_suspension
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"answer/internal/base/constant"
+	"answer/internal/base/pager"
+	"answer/internal/base/reason"
+	"answer/internal/base/scheduler"
+	"answer/internal/entity"
+	"answer/internal/schema"
+	"answer/internal/service/notice_queue"
+
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// suspensionEscalationDays the days of the suspension escalated by the number of the previous suspensions of the user,
+// the user who has been suspended more times than it is suspended permanently
+var suspensionEscalationDays = []int{1, 7, 30, 365}
+
+// UserSuspensionRepo user suspension repository
+type UserSuspensionRepo interface {
+	AddUserSuspension(ctx context.Context, suspension *entity.UserSuspension) (err error)
+	GetActiveUserSuspension(ctx context.Context, userID string) (
+		suspension *entity.UserSuspension, exist bool, err error)
+	CountUserSuspensions(ctx context.Context, userID string) (count int64, err error)
+	GetUserSuspensionList(ctx context.Context, userID string) (suspensions []*entity.UserSuspension, err error)
+	GetDueUserSuspensions(ctx context.Context, now time.Time) (suspensions []*entity.UserSuspension, err error)
+	UpdateUserSuspensionStatus(ctx context.Context, id string, status int) (err error)
+	LiftUserSuspension(ctx context.Context, suspension *entity.UserSuspension, status int) (lifted bool, err error)
+	AddUserBan(ctx context.Context, ban *entity.UserBan) (err error)
+	RemoveUserBan(ctx context.Context, id string) (removed bool, err error)
+	HasUserBan(ctx context.Context, banType string, values []string) (banned bool, err error)
+	GetUserBanPage(ctx context.Context, banType string, page, pageSize int) (
+		bans []*entity.UserBan, total int64, err error)
+}
+
+// UserSuspensionService user suspension service
+type UserSuspensionService struct {
+	userSuspensionRepo UserSuspensionRepo
+}
+
+// NewUserSuspensionService new user suspension service
+func NewUserSuspensionService(userSuspensionRepo UserSuspensionRepo, scheduler *scheduler.Scheduler) *UserSuspensionService {
+	us := &UserSuspensionService{
+		userSuspensionRepo: userSuspensionRepo,
+	}
+	scheduler.AddJob("user_suspension_expiry", constant.UserSuspensionCheckInterval, false, us.ExpireSuspensions)
+	return us
+}
+
+// SuspendUser record the suspension of the user, the active suspension is replaced by the new one.
+// If the days are not specified, the days are escalated by the suspension history of the user.
+func (us *UserSuspensionService) SuspendUser(ctx context.Context, userInfo *entity.User,
+	req *schema.UpdateUserStatusReq) (err error) {
+	active, exist, err := us.userSuspensionRepo.GetActiveUserSuspension(ctx, userInfo.ID)
+	if err != nil {
+		return err
+	}
+	if exist {
+		err = us.userSuspensionRepo.UpdateUserSuspensionStatus(ctx, active.ID, entity.UserSuspensionStatusLifted)
+		if err != nil {
+			return err
+		}
+	}
+
+	days := req.SuspendDays
+	if req.SuspendPermanent {
+		days = 0
+	} else if days == 0 {
+		count, err := us.userSuspensionRepo.CountUserSuspensions(ctx, userInfo.ID)
+		if err != nil {
+			return err
+		}
+		days = escalateDays(count)
+	}
+	suspension := &entity.UserSuspension{
+		UserID:     userInfo.ID,
+		OperatorID: req.OperatorID,
+		Reason:     req.SuspendReason,
+		Message:    req.SuspendMessage,
+		Days:       days,
+		Status:     entity.UserSuspensionStatusActive,
+	}
+	if len(suspension.Reason) == 0 {
+		suspension.Reason = schema.UserSuspensionReasonOther
+	}
+	if days > 0 {
+		suspension.ExpiredAt = time.Now().Add(time.Duration(days) * 24 * time.Hour)
+	}
+	if err = us.userSuspensionRepo.AddUserSuspension(ctx, suspension); err != nil {
+		return err
+	}
+
+	if req.BanIP && len(userInfo.IPInfo) > 0 {
+		err = us.userSuspensionRepo.AddUserBan(ctx, &entity.UserBan{
+			BanType:    entity.UserBanTypeIP,
+			Value:      userInfo.IPInfo,
+			UserID:     userInfo.ID,
+			OperatorID: req.OperatorID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if domain := emailDomain(userInfo.EMail); req.BanEmailDomain && len(domain) > 0 {
+		err = us.userSuspensionRepo.AddUserBan(ctx, &entity.UserBan{
+			BanType:    entity.UserBanTypeEmailDomain,
+			Value:      domain,
+			UserID:     userInfo.ID,
+			OperatorID: req.OperatorID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	us.notify(userInfo.ID, req.OperatorID, constant.YourAccountWasSuspended, suspension.Message)
+	return nil
+}
+
+// LiftSuspension lift the active suspension of the user by admin
+func (us *UserSuspensionService) LiftSuspension(ctx context.Context, userID, operatorID string) (err error) {
+	suspension, exist, err := us.userSuspensionRepo.GetActiveUserSuspension(ctx, userID)
+	if err != nil || !exist {
+		return err
+	}
+	lifted, err := us.userSuspensionRepo.LiftUserSuspension(ctx, suspension, entity.UserSuspensionStatusLifted)
+	if err != nil {
+		return err
+	}
+	if lifted {
+		us.notify(userID, operatorID, constant.YourSuspensionWasLifted, "")
+	}
+	return nil
+}
+
+// ExpireSuspensions end the suspensions whose period has ended, and reinstate the users
+func (us *UserSuspensionService) ExpireSuspensions(ctx context.Context) {
+	suspensions, err := us.userSuspensionRepo.GetDueUserSuspensions(ctx, time.Now())
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	for _, suspension := range suspensions {
+		lifted, err := us.userSuspensionRepo.LiftUserSuspension(ctx, suspension, entity.UserSuspensionStatusExpired)
+		if err != nil {
+			log.Errorf("lift the suspension of user %s failed: %s", suspension.UserID, err)
+			continue
+		}
+		if lifted {
+			log.Infof("the suspension of user %s is ended", suspension.UserID)
+			us.notify(suspension.UserID, suspension.UserID, constant.YourSuspensionWasLifted, "")
+		}
+	}
+}
+
+// GetActiveSuspension get the active suspension of the user, nil if the user is not suspended
+func (us *UserSuspensionService) GetActiveSuspension(ctx context.Context, userID string) (
+	resp *schema.UserSuspensionResp, err error) {
+	suspension, exist, err := us.userSuspensionRepo.GetActiveUserSuspension(ctx, userID)
+	if err != nil || !exist {
+		return nil, err
+	}
+	resp = &schema.UserSuspensionResp{}
+	resp.GetFromEntity(suspension)
+	return resp, nil
+}
+
+// GetUserSuspensionList get the suspension history of the user
+func (us *UserSuspensionService) GetUserSuspensionList(ctx context.Context, req *schema.GetUserSuspensionListReq) (
+	resp []*schema.UserSuspensionResp, err error) {
+	suspensions, err := us.userSuspensionRepo.GetUserSuspensionList(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	resp = make([]*schema.UserSuspensionResp, 0, len(suspensions))
+	for _, suspension := range suspensions {
+		item := &schema.UserSuspensionResp{}
+		item.GetFromEntity(suspension)
+		resp = append(resp, item)
+	}
+	return resp, nil
+}
+
+// CheckRegistration check whether the ip or the email domain of the new user is banned
+func (us *UserSuspensionService) CheckRegistration(ctx context.Context, email, ip string) (err error) {
+	if len(ip) > 0 {
+		banned, err := us.userSuspensionRepo.HasUserBan(ctx, entity.UserBanTypeIP, []string{ip})
+		if err != nil {
+			return err
+		}
+		if banned {
+			return errors.BadRequest(reason.UserBanned)
+		}
+	}
+	banned, err := us.userSuspensionRepo.HasUserBan(ctx, entity.UserBanTypeEmailDomain, parentDomains(emailDomain(email)))
+	if err != nil {
+		return err
+	}
+	if banned {
+		return errors.BadRequest(reason.UserBanned)
+	}
+	return nil
+}
+
+// AddUserBan add the ip or email domain ban by admin
+func (us *UserSuspensionService) AddUserBan(ctx context.Context, req *schema.AddUserBanReq) (err error) {
+	value := strings.TrimSpace(req.Value)
+	if req.BanType == entity.UserBanTypeEmailDomain {
+		value = strings.TrimPrefix(strings.ToLower(value), "@")
+	}
+	return us.userSuspensionRepo.AddUserBan(ctx, &entity.UserBan{
+		BanType:    req.BanType,
+		Value:      value,
+		UserID:     "0",
+		OperatorID: req.OperatorID,
+	})
+}
+
+// RemoveUserBan remove the ban
+func (us *UserSuspensionService) RemoveUserBan(ctx context.Context, req *schema.RemoveUserBanReq) (err error) {
+	removed, err := us.userSuspensionRepo.RemoveUserBan(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return errors.BadRequest(reason.UserBanNotFound)
+	}
+	return nil
+}
+
+// GetUserBanPage get the bans
+func (us *UserSuspensionService) GetUserBanPage(ctx context.Context, req *schema.GetUserBanPageReq) (
+	pageModel *pager.PageModel, err error) {
+	bans, total, err := us.userSuspensionRepo.GetUserBanPage(ctx, req.BanType, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*schema.GetUserBanResp, 0, len(bans))
+	for _, ban := range bans {
+		list = append(list, &schema.GetUserBanResp{
+			ID:        ban.ID,
+			CreatedAt: ban.CreatedAt.Unix(),
+			BanType:   ban.BanType,
+			Value:     ban.Value,
+			UserID:    ban.UserID,
+		})
+	}
+	return pager.NewPageModel(total, list), nil
+}
+
+// notify send the notification about the suspension to the user, the suspended user can still read the notifications
+func (us *UserSuspensionService) notify(userID, triggerUserID, action, title string) {
+	notice_queue.AddNotification(&schema.NotificationMsg{
+		TriggerUserID:       triggerUserID,
+		ReceiverUserID:      userID,
+		Type:                schema.NotificationTypeInbox,
+		Title:               title,
+		ObjectID:            userID,
+		ObjectType:          constant.UserObjectType,
+		NotificationAction:  action,
+		NoNeedPushAllFollow: true,
+	})
+}
+
+// escalateDays the days of the suspension after the count of the previous suspensions, 0 means permanent
+func escalateDays(count int64) int {
+	if count < int64(len(suspensionEscalationDays)) {
+		return suspensionEscalationDays[count]
+	}
+	return 0
+}
+
+// emailDomain the lower case domain of the email address
+func emailDomain(email string) string {
+	idx := strings.LastIndex(email, "@")
+	if idx < 0 {
+		return ""
+	}
+	return strings.ToLower(email[idx+1:])
+}
+
+// parentDomains the domain and its parent domains, such as a.b.com -> a.b.com, b.com
+func parentDomains(domain string) (domains []string) {
+	for len(domain) > 0 && strings.Contains(domain, ".") {
+		domains = append(domains, domain)
+		domain = domain[strings.Index(domain, ".")+1:]
+	}
+	return domains
+}
@@ -0,0 +1,378 @@
+package user_two_factor
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"answer/internal/base/constant"
+	"answer/internal/base/reason"
+	"answer/internal/entity"
+	"answer/internal/schema"
+	"answer/internal/service/siteinfo_common"
+	usercommon "answer/internal/service/user_common"
+	"answer/pkg/totp"
+
+	"github.com/google/uuid"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// UserTwoFactorRepo user two factor repository
+type UserTwoFactorRepo interface {
+	GetUserTwoFactor(ctx context.Context, userID string) (twoFactor *entity.UserTwoFactor, exist bool, err error)
+	SaveUserTwoFactor(ctx context.Context, twoFactor *entity.UserTwoFactor) (err error)
+	UpdateRecoveryCodes(ctx context.Context, userID, recoveryCodes string) (err error)
+	UseRecoveryCodes(ctx context.Context, userID, oldRecoveryCodes, recoveryCodes string) (used bool, err error)
+	UseTimeStep(ctx context.Context, userID string, step int64) (used bool, err error)
+	RemoveUserTwoFactor(ctx context.Context, userID string) (err error)
+	AddPendingLogin(ctx context.Context, pending *entity.UserTwoFactorPending) (err error)
+	GetPendingLogin(ctx context.Context, token string) (pending *entity.UserTwoFactorPending, exist bool, err error)
+	IncreasePendingAttempts(ctx context.Context, token string, maxAttempts int) (increased bool, err error)
+	RemovePendingLogin(ctx context.Context, token string) (removed bool, err error)
+}
+
+// UserTwoFactorService user two factor service
+type UserTwoFactorService struct {
+	userTwoFactorRepo     UserTwoFactorRepo
+	userRepo              usercommon.UserRepo
+	siteInfoCommonService *siteinfo_common.SiteInfoCommonService
+}
+
+// NewUserTwoFactorService new user two factor service
+func NewUserTwoFactorService(
+	userTwoFactorRepo UserTwoFactorRepo,
+	userRepo usercommon.UserRepo,
+	siteInfoCommonService *siteinfo_common.SiteInfoCommonService,
+) *UserTwoFactorService {
+	return &UserTwoFactorService{
+		userTwoFactorRepo:     userTwoFactorRepo,
+		userRepo:              userRepo,
+		siteInfoCommonService: siteInfoCommonService,
+	}
+}
+
+// GetUserTwoFactor get the two factor status of user
+func (us *UserTwoFactorService) GetUserTwoFactor(ctx context.Context, userID string) (
+	resp *schema.GetUserTwoFactorResp, err error) {
+	userInfo, exist, err := us.userRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.BadRequest(reason.UserNotFound)
+	}
+	resp = &schema.GetUserTwoFactorResp{Required: us.IsRequired(ctx, userInfo)}
+	twoFactor, exist, err := us.userTwoFactorRepo.GetUserTwoFactor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if exist && twoFactor.Enabled {
+		resp.Enabled = true
+		resp.RecoveryCodesLeft = len(decodeRecoveryCodes(twoFactor.RecoveryCodes))
+	}
+	return resp, nil
+}
+
+// IsEnabled check whether the user has enabled two factor authentication
+func (us *UserTwoFactorService) IsEnabled(ctx context.Context, userID string) (enabled bool, err error) {
+	twoFactor, exist, err := us.userTwoFactorRepo.GetUserTwoFactor(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return exist && twoFactor.Enabled, nil
+}
+
+// IsRequired check whether the user must set up two factor authentication according to the site setting.
+// Only the admins are required, because the setting guards the admin token which is held until it is set up.
+// The moderators of the tags act with the normal token through the tags they moderate, so they are left out.
+func (us *UserTwoFactorService) IsRequired(ctx context.Context, userInfo *entity.User) bool {
+	if !userInfo.IsAdmin {
+		return false
+	}
+	siteLogin, err := us.siteInfoCommonService.GetSiteLogin(ctx)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	return siteLogin.RequireAdminTwoFactor
+}
+
+// SetupUserTwoFactor generate a new secret for user, it will not take effect until confirmed by EnableUserTwoFactor
+func (us *UserTwoFactorService) SetupUserTwoFactor(ctx context.Context, req *schema.SetupUserTwoFactorReq) (
+	resp *schema.SetupUserTwoFactorResp, err error) {
+	userInfo, exist, err := us.userRepo.GetByUserID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.BadRequest(reason.UserNotFound)
+	}
+	enabled, err := us.IsEnabled(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		return nil, errors.BadRequest(reason.TwoFactorAlreadyEnabled)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	err = us.userTwoFactorRepo.SaveUserTwoFactor(ctx, &entity.UserTwoFactor{
+		UserID:        req.UserID,
+		Secret:        secret,
+		RecoveryCodes: "[]",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	issuer := "Answer"
+	if siteGeneral, err := us.siteInfoCommonService.GetSiteGeneral(ctx); err == nil && len(siteGeneral.Name) > 0 {
+		issuer = siteGeneral.Name
+	}
+	return &schema.SetupUserTwoFactorResp{
+		Secret: secret,
+		KeyURI: totp.KeyURI(issuer, userInfo.EMail, secret),
+	}, nil
+}
+
+// EnableUserTwoFactor confirm the secret with a valid code, then enable it and return the recovery codes
+func (us *UserTwoFactorService) EnableUserTwoFactor(ctx context.Context, req *schema.EnableUserTwoFactorReq) (
+	resp *schema.UserTwoFactorRecoveryCodesResp, err error) {
+	twoFactor, exist, err := us.userTwoFactorRepo.GetUserTwoFactor(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist || len(twoFactor.Secret) == 0 {
+		return nil, errors.BadRequest(reason.TwoFactorNotSetup)
+	}
+	if twoFactor.Enabled {
+		return nil, errors.BadRequest(reason.TwoFactorAlreadyEnabled)
+	}
+	pass, err := us.validateCode(ctx, twoFactor, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !pass {
+		return nil, errors.BadRequest(reason.TwoFactorCodeInvalid)
+	}
+
+	codes, hashedCodes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	twoFactor.Enabled = true
+	twoFactor.RecoveryCodes = hashedCodes
+	if err = us.userTwoFactorRepo.SaveUserTwoFactor(ctx, twoFactor); err != nil {
+		return nil, err
+	}
+	return &schema.UserTwoFactorRecoveryCodesResp{RecoveryCodes: codes}, nil
+}
+
+// DisableUserTwoFactor disable the two factor authentication of user, password and a code are both required
+func (us *UserTwoFactorService) DisableUserTwoFactor(ctx context.Context, req *schema.DisableUserTwoFactorReq) (
+	err error) {
+	userInfo, exist, err := us.userRepo.GetByUserID(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.UserNotFound)
+	}
+	if us.IsRequired(ctx, userInfo) {
+		return errors.BadRequest(reason.TwoFactorRequiredByAdmin)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(userInfo.Pass), []byte(req.Pass)) != nil {
+		return errors.BadRequest(reason.OldPasswordVerificationFailed)
+	}
+	twoFactor, exist, err := us.userTwoFactorRepo.GetUserTwoFactor(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+	if !exist || !twoFactor.Enabled {
+		return errors.BadRequest(reason.TwoFactorNotSetup)
+	}
+	pass, err := us.verifyCode(ctx, twoFactor, req.Code, req.RecoveryCode)
+	if err != nil {
+		return err
+	}
+	if !pass {
+		return errors.BadRequest(reason.TwoFactorCodeInvalid)
+	}
+	return us.userTwoFactorRepo.RemoveUserTwoFactor(ctx, req.UserID)
+}
+
+// RegenerateRecoveryCodes generate new recovery codes, the old ones will be invalid
+func (us *UserTwoFactorService) RegenerateRecoveryCodes(ctx context.Context, req *schema.RegenerateRecoveryCodesReq) (
+	resp *schema.UserTwoFactorRecoveryCodesResp, err error) {
+	twoFactor, exist, err := us.userTwoFactorRepo.GetUserTwoFactor(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist || !twoFactor.Enabled {
+		return nil, errors.BadRequest(reason.TwoFactorNotSetup)
+	}
+	pass, err := us.validateCode(ctx, twoFactor, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !pass {
+		return nil, errors.BadRequest(reason.TwoFactorCodeInvalid)
+	}
+	codes, hashedCodes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	if err = us.userTwoFactorRepo.UpdateRecoveryCodes(ctx, req.UserID, hashedCodes); err != nil {
+		return nil, err
+	}
+	return &schema.UserTwoFactorRecoveryCodesResp{RecoveryCodes: codes}, nil
+}
+
+// ResetUserTwoFactor admin reset the two factor authentication of user, such as the user lost the device
+func (us *UserTwoFactorService) ResetUserTwoFactor(ctx context.Context, req *schema.ResetUserTwoFactorReq) (err error) {
+	_, exist, err := us.userRepo.GetByUserID(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.UserNotFound)
+	}
+	return us.userTwoFactorRepo.RemoveUserTwoFactor(ctx, req.UserID)
+}
+
+// AddPendingLogin issue a short-lived token for the login which is waiting for the two factor code
+func (us *UserTwoFactorService) AddPendingLogin(ctx context.Context, userID string) (token string, err error) {
+	token = strings.ReplaceAll(uuid.NewString(), "-", "")
+	err = us.userTwoFactorRepo.AddPendingLogin(ctx, &entity.UserTwoFactorPending{
+		Token:     token,
+		UserID:    userID,
+		ExpiredAt: time.Now().Add(constant.UserTwoFactorPendingTime),
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// VerifyPendingLogin verify the code of the pending login, return the user id if passed.
+// The token is single-use and will be discarded after too many wrong codes.
+func (us *UserTwoFactorService) VerifyPendingLogin(ctx context.Context, req *schema.UserTwoFactorLoginReq) (
+	userID string, err error) {
+	pending, exist, err := us.userTwoFactorRepo.GetPendingLogin(ctx, req.Token)
+	if err != nil {
+		return "", err
+	}
+	if !exist {
+		return "", errors.BadRequest(reason.TwoFactorTokenExpired)
+	}
+	twoFactor, exist, err := us.userTwoFactorRepo.GetUserTwoFactor(ctx, pending.UserID)
+	if err != nil {
+		return "", err
+	}
+	if !exist || !twoFactor.Enabled {
+		_, _ = us.userTwoFactorRepo.RemovePendingLogin(ctx, req.Token)
+		return "", errors.BadRequest(reason.TwoFactorTokenExpired)
+	}
+
+	// the attempt is taken before the code is verified, so no more codes than allowed are tried
+	increased, err := us.userTwoFactorRepo.IncreasePendingAttempts(ctx, req.Token, constant.UserTwoFactorMaxAttempts)
+	if err != nil {
+		return "", err
+	}
+	if !increased {
+		if _, err = us.userTwoFactorRepo.RemovePendingLogin(ctx, req.Token); err != nil {
+			log.Error(err)
+		}
+		return "", errors.BadRequest(reason.TwoFactorTokenExpired)
+	}
+	pass, err := us.verifyCode(ctx, twoFactor, req.Code, req.RecoveryCode)
+	if err != nil {
+		return "", err
+	}
+	if !pass {
+		return "", errors.BadRequest(reason.TwoFactorCodeInvalid)
+	}
+	// only the request which removes the token logs in
+	removed, err := us.userTwoFactorRepo.RemovePendingLogin(ctx, req.Token)
+	if err != nil {
+		return "", err
+	}
+	if !removed {
+		return "", errors.BadRequest(reason.TwoFactorTokenExpired)
+	}
+	return pending.UserID, nil
+}
+
+// validateCode validate the totp code, the time step of the accepted code is used up so it can not be replayed
+func (us *UserTwoFactorService) validateCode(ctx context.Context, twoFactor *entity.UserTwoFactor, code string) (
+	pass bool, err error) {
+	step, ok := totp.ValidateStep(twoFactor.Secret, code, time.Now(), twoFactor.LastUsedStep)
+	if !ok {
+		return false, nil
+	}
+	return us.userTwoFactorRepo.UseTimeStep(ctx, twoFactor.UserID, step)
+}
+
+// verifyCode verify the totp code, or the recovery code if the code is empty. The used recovery code is removed.
+func (us *UserTwoFactorService) verifyCode(ctx context.Context, twoFactor *entity.UserTwoFactor,
+	code, recoveryCode string) (pass bool, err error) {
+	if len(code) > 0 {
+		return us.validateCode(ctx, twoFactor, code)
+	}
+	if len(recoveryCode) == 0 {
+		return false, nil
+	}
+	hashedCode := hashRecoveryCode(recoveryCode)
+	hashedCodes := decodeRecoveryCodes(twoFactor.RecoveryCodes)
+	for i, c := range hashedCodes {
+		if c != hashedCode {
+			continue
+		}
+		hashedCodes = append(hashedCodes[:i], hashedCodes[i+1:]...)
+		content, _ := json.Marshal(hashedCodes)
+		return us.userTwoFactorRepo.UseRecoveryCodes(ctx, twoFactor.UserID, twoFactor.RecoveryCodes, string(content))
+	}
+	return false, nil
+}
+
+// generateRecoveryCodes generate the plain recovery codes and the json of the hashed ones
+func generateRecoveryCodes() (codes []string, hashedCodes string, err error) {
+	hashed := make([]string, 0, constant.UserTwoFactorRecoveryCodes)
+	for i := 0; i < constant.UserTwoFactorRecoveryCodes; i++ {
+		buf := make([]byte, 5)
+		if _, err = rand.Read(buf); err != nil {
+			return nil, "", err
+		}
+		code := hex.EncodeToString(buf)
+		code = code[:5] + "-" + code[5:]
+		codes = append(codes, code)
+		hashed = append(hashed, hashRecoveryCode(code))
+	}
+	content, err := json.Marshal(hashed)
+	if err != nil {
+		return nil, "", err
+	}
+	return codes, string(content), nil
+}
+
+// hashRecoveryCode the recovery codes are random enough, so sha256 is used instead of slow hash
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+func decodeRecoveryCodes(content string) (hashedCodes []string) {
+	hashedCodes = make([]string, 0)
+	_ = json.Unmarshal([]byte(content), &hashedCodes)
+	return hashedCodes
+}
@@ -5,6 +5,7 @@ import (
 
 	"answer/internal/base/pager"
 	"answer/internal/entity"
+	"answer/internal/service/activity_common"
 	"answer/internal/service/activity_type"
 	"answer/internal/service/comment_common"
 	"answer/internal/service/config"
@@ -41,6 +42,7 @@ type VoteService struct {
 	answerRepo        answercommon.AnswerRepo
 	commentCommonRepo comment_common.CommentCommonRepo
 	objectService     *object_info.ObjService
+	questionScoreRepo activity_common.QuestionScoreRepo
 }
 
 func NewVoteService(
@@ -51,6 +53,7 @@ func NewVoteService(
 	answerRepo answercommon.AnswerRepo,
 	commentCommonRepo comment_common.CommentCommonRepo,
 	objectService *object_info.ObjService,
+	questionScoreRepo activity_common.QuestionScoreRepo,
 ) *VoteService {
 	return &VoteService{
 		voteRepo:          VoteRepo,
@@ -60,6 +63,7 @@ func NewVoteService(
 		answerRepo:        answerRepo,
 		commentCommonRepo: commentCommonRepo,
 		objectService:     objectService,
+		questionScoreRepo: questionScoreRepo,
 	}
 }
 
@@ -81,10 +85,17 @@ func (as *VoteService) VoteUp(ctx context.Context, dto *schema.VoteDTO) (voteRes
 	}
 
 	if dto.IsCancel {
-		return as.voteRepo.VoteUpCancel(ctx, dto.ObjectID, dto.UserID, objectUserID)
+		voteResp, err = as.voteRepo.VoteUpCancel(ctx, dto.ObjectID, dto.UserID, objectUserID)
 	} else {
-		return as.voteRepo.VoteUp(ctx, dto.ObjectID, dto.UserID, objectUserID)
+		voteResp, err = as.voteRepo.VoteUp(ctx, dto.ObjectID, dto.UserID, objectUserID)
 	}
+	if err != nil {
+		return nil, err
+	}
+	if err = as.questionScoreRepo.RefreshHotScore(ctx, dto.ObjectID); err != nil {
+		log.Error(err)
+	}
+	return voteResp, nil
 }
 
 // VoteDown vote down
@@ -105,10 +116,17 @@ func (as *VoteService) VoteDown(ctx context.Context, dto *schema.VoteDTO) (voteR
 	}
 
 	if dto.IsCancel {
-		return as.voteRepo.VoteDownCancel(ctx, dto.ObjectID, dto.UserID, objectUserID)
+		voteResp, err = as.voteRepo.VoteDownCancel(ctx, dto.ObjectID, dto.UserID, objectUserID)
 	} else {
-		return as.voteRepo.VoteDown(ctx, dto.ObjectID, dto.UserID, objectUserID)
+		voteResp, err = as.voteRepo.VoteDown(ctx, dto.ObjectID, dto.UserID, objectUserID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = as.questionScoreRepo.RefreshHotScore(ctx, dto.ObjectID); err != nil {
+		log.Error(err)
 	}
+	return voteResp, nil
 }
 
 func (vs *VoteService) GetObjectUserID(ctx context.Context, objectID string) (userID string, err error) {
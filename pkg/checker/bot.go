@@ -0,0 +1,24 @@
+package checker
+
+import "strings"
+
+// botUserAgentKeywords the keywords of the user agents of the known crawlers, spiders and http clients
+var botUserAgentKeywords = []string{
+	"bot", "spider", "crawl", "slurp", "archiver", "facebookexternalhit", "mediapartners-google",
+	"headlesschrome", "phantomjs", "lighthouse", "curl/", "wget/", "python-requests", "python-urllib",
+	"go-http-client", "java/", "okhttp", "libwww-perl", "httpclient", "scrapy", "axios/", "node-fetch",
+}
+
+// IsBot check whether the user agent belongs to a known bot, the empty user agent is regarded as a bot
+func IsBot(userAgent string) bool {
+	ua := strings.ToLower(strings.TrimSpace(userAgent))
+	if len(ua) == 0 {
+		return true
+	}
+	for _, keyword := range botUserAgentKeywords {
+		if strings.Contains(ua, keyword) {
+			return true
+		}
+	}
+	return false
+}
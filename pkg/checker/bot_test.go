@@ -0,0 +1,26 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsBot(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		want      bool
+	}{
+		{userAgent: "", want: true},
+		{userAgent: "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", want: true},
+		{userAgent: "Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)", want: true},
+		{userAgent: "Mozilla/5.0 (compatible; Baiduspider/2.0; +http://www.baidu.com/search/spider.html)", want: true},
+		{userAgent: "curl/7.79.1", want: true},
+		{userAgent: "python-requests/2.28.1", want: true},
+		{userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/107.0.0.0 Safari/537.36", want: false},
+		{userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 16_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.1 Mobile/15E148 Safari/604.1", want: false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, IsBot(tt.userAgent), tt.userAgent)
+	}
+}
@@ -0,0 +1,45 @@
+package hotscore
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// VoteWeight AnswerWeight ViewWeight the weight of each interaction in the score
+	VoteWeight   = 1.0
+	AnswerWeight = 2.0
+	ViewWeight   = 1.0
+	// DecaySeconds every DecaySeconds the newer question gets one more order of magnitude of the score
+	DecaySeconds = 45000
+)
+
+// epoch the start time of the score, keeps the time part of the score small
+var epoch = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+
+// Score calculate the hot score of a question.
+// The interactions are scaled by log10, and the time part grows linearly, so the newer questions
+// rank higher unless the older ones have much more interactions. The score is a constant until
+// the next interaction, which means it can be stored and indexed, no need to recalculate periodically.
+// activeAt is the time of the last activity, such as a new answer, it lifts the question half as much as a new one.
+func Score(votes, answers, views int, createdAt, activeAt time.Time) float64 {
+	weight := float64(votes)*VoteWeight + float64(answers)*AnswerWeight +
+		math.Log10(float64(max(views, 0))+1)*ViewWeight
+	order := math.Log10(math.Max(math.Abs(weight), 1))
+	if weight < 0 {
+		order = -order
+	}
+
+	seconds := createdAt.Unix()
+	if activeAt.After(createdAt) {
+		seconds += (activeAt.Unix() - seconds) / 2
+	}
+	return order + float64(seconds-epoch)/DecaySeconds
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,26 @@
+package hotscore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScore(t *testing.T) {
+	now := time.Now()
+	yesterday := now.Add(-24 * time.Hour)
+
+	// the newer question ranks higher with the same interactions
+	assert.Greater(t, Score(1, 1, 10, now, now), Score(1, 1, 10, yesterday, yesterday))
+	// more interactions rank higher at the same time
+	assert.Greater(t, Score(10, 2, 100, now, now), Score(1, 0, 10, now, now))
+	// the down voted question ranks lower
+	assert.Less(t, Score(-10, 0, 0, now, now), Score(0, 0, 0, now, now))
+	// the recent activity lifts the old question
+	assert.Greater(t, Score(1, 1, 10, yesterday, now), Score(1, 1, 10, yesterday, yesterday))
+	// but not as much as a new question
+	assert.Less(t, Score(1, 1, 10, yesterday, now), Score(1, 1, 10, now, now))
+	// the activity time before creation is ignored
+	assert.Equal(t, Score(1, 1, 10, now, now), Score(1, 1, 10, now, yesterday))
+}
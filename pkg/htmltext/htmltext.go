@@ -58,3 +58,23 @@ func FetchExcerpt(html, trimMarker string, limit int) (text string) {
 	text += trimMarker
 	return
 }
+
+// InjectHead replace the title of the HTML page and insert the tags before the end of head
+func InjectHead(page, title, tags string) string {
+	if len(title) > 0 {
+		re := regexp.MustCompile(`(?is)<title>.*?</title>`)
+		if re.MatchString(page) {
+			page = re.ReplaceAllLiteralString(page, "<title>"+title+"</title>")
+		} else {
+			tags = "<title>" + title + "</title>" + tags
+		}
+	}
+	if len(tags) == 0 {
+		return page
+	}
+	idx := strings.Index(strings.ToLower(page), "</head>")
+	if idx < 0 {
+		return page
+	}
+	return page[:idx] + tags + page[idx:]
+}
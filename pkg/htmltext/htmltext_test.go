@@ -49,3 +49,22 @@ func TestFetchExcerpt(t *testing.T) {
 	text = FetchExcerpt("<p>hello你好😂world</p>", "...", 8)
 	assert.Equal(t, expected, text)
 }
+
+func TestInjectHead(t *testing.T) {
+	page := "<html><head><meta charset=\"utf-8\"><title>Answer</title></head><body></body></html>"
+
+	// test replace title and append tags
+	expected := "<html><head><meta charset=\"utf-8\"><title>hello $1</title><meta name=\"a\"></head><body></body></html>"
+	assert.Equal(t, expected, InjectHead(page, "hello $1", "<meta name=\"a\">"))
+
+	// test keep title
+	expected = "<html><head><meta charset=\"utf-8\"><title>Answer</title><meta name=\"a\"></head><body></body></html>"
+	assert.Equal(t, expected, InjectHead(page, "", "<meta name=\"a\">"))
+
+	// test page without title
+	expected = "<html><head><title>hello</title></head></html>"
+	assert.Equal(t, expected, InjectHead("<html><head></head></html>", "hello", ""))
+
+	// test page without head
+	assert.Equal(t, "<p>hello</p>", InjectHead("<p>hello</p>", "hello", "<meta name=\"a\">"))
+}
@@ -0,0 +1,89 @@
+package langdetect
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Language codes, ISO 639-1
+const (
+	English  = "en"
+	Chinese  = "zh"
+	Japanese = "ja"
+	Korean   = "ko"
+	Russian  = "ru"
+	Arabic   = "ar"
+)
+
+// minLetters the minimum letters needed to detect the language, the text which is too short returns empty
+const minLetters = 3
+
+var codeRegexp = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+// Detect detect the language of the text by the writing script of its letters.
+// The text written in latin letters is regarded as English, because the script can not tell the languages apart.
+// An empty string is returned if the language can not be detected.
+func Detect(text string) string {
+	var han, kana, hangul, cyrillic, arabic, latin, total int
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			kana++
+		case unicode.Is(unicode.Hangul, r):
+			hangul++
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Arabic, r):
+			arabic++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		default:
+			continue
+		}
+		total++
+	}
+	if total < minLetters {
+		return ""
+	}
+	switch {
+	// the japanese text mixes kana with han, a few kana are enough to tell it from chinese
+	case kana*10 >= total:
+		return Japanese
+	case hangul*3 >= total:
+		return Korean
+	// the chinese text often mixes with latin words, such as the names of programming languages
+	case han*5 >= total:
+		return Chinese
+	case cyrillic*2 >= total:
+		return Russian
+	case arabic*2 >= total:
+		return Arabic
+	case latin*2 >= total:
+		return English
+	}
+	return ""
+}
+
+// Normalize normalize the language code to ISO 639-1 code, such as zh_CN or zh-Hans -> zh.
+// An empty string is returned if the code is not valid.
+func Normalize(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if idx := strings.IndexAny(code, "_-"); idx > 0 {
+		code = code[:idx]
+	}
+	if !codeRegexp.MatchString(code) {
+		return ""
+	}
+	return code
+}
+
+// Resolve get the language code specified by the user, or detect it from the text if it is not specified
+func Resolve(code, text string) string {
+	if language := Normalize(code); len(language) > 0 {
+		return language
+	}
+	return Detect(text)
+}
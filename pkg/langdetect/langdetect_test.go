@@ -0,0 +1,33 @@
+package langdetect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "How to use goroutines in Go?", want: English},
+		{text: "如何在 Go 语言中使用 goroutine？", want: Chinese},
+		{text: "Goでgoroutineを使う方法は？", want: Japanese},
+		{text: "Go에서 고루틴을 사용하는 방법", want: Korean},
+		{text: "Как использовать горутины в Go?", want: Russian},
+		{text: "", want: ""},
+		{text: "12345 !!", want: ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Detect(tt.text), tt.text)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "zh", Normalize("zh_CN"))
+	assert.Equal(t, "zh", Normalize("zh-Hans"))
+	assert.Equal(t, "en", Normalize(" EN "))
+	assert.Equal(t, "", Normalize("english"))
+	assert.Equal(t, "", Normalize(""))
+}
@@ -0,0 +1,72 @@
+// Package mdsection splits the markdown into the sections by the headings, and finds the sections left empty.
+package mdsection
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	headingReg = regexp.MustCompile(`^ {0,3}#{1,6}[ \t]+(.*?)[ \t#]*$`)
+	fenceReg   = regexp.MustCompile("^ {0,3}(```|~~~)")
+	commentReg = regexp.MustCompile(`(?s)<!--.*?-->`)
+)
+
+// Parse the sections of the markdown, the key is the normalized heading and the value is the trimmed body.
+// The headings in the code blocks are ignored, and the first one wins if the heading is repeated.
+func Parse(markdown string) (sections map[string]string) {
+	sections = make(map[string]string)
+	heading, body := "", make([]string, 0)
+	inFence := false
+	flush := func() {
+		if len(heading) == 0 {
+			return
+		}
+		if _, ok := sections[heading]; !ok {
+			sections[heading] = strings.TrimSpace(strings.Join(body, "\n"))
+		}
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n") {
+		if fenceReg.MatchString(line) {
+			inFence = !inFence
+		}
+		if !inFence {
+			if match := headingReg.FindStringSubmatch(line); match != nil {
+				flush()
+				heading, body = Normalize(match[1]), make([]string, 0)
+				continue
+			}
+		}
+		body = append(body, line)
+	}
+	flush()
+	return sections
+}
+
+// Missing the required sections which are absent in the markdown, or empty,
+// or left as they are in the template. The comments in the sections are the hints and they are not the content.
+func Missing(markdown, template string, required []string) (missing []string) {
+	missing = make([]string, 0)
+	sections := Parse(markdown)
+	templateSections := Parse(template)
+	for _, name := range required {
+		key := Normalize(name)
+		body, ok := sections[key]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		content := strings.TrimSpace(commentReg.ReplaceAllString(body, ""))
+		if len(content) == 0 || content == strings.TrimSpace(commentReg.ReplaceAllString(templateSections[key], "")) {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// Normalize the heading is compared in lower case, without the emphasis and the trailing colon
+func Normalize(heading string) string {
+	heading = strings.Trim(strings.TrimSpace(heading), "*_")
+	heading = strings.TrimRight(heading, ": ")
+	return strings.ToLower(strings.TrimSpace(heading))
+}
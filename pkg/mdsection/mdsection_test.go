@@ -0,0 +1,26 @@
+package mdsection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	markdown := "intro\n## Version:\n1.2.0\n\n### **Logs**\n```\n# not a heading\n```\n## Version\nrepeated"
+	sections := Parse(markdown)
+	assert.Equal(t, "1.2.0", sections["version"])
+	assert.Equal(t, "```\n# not a heading\n```", sections["logs"])
+	assert.Len(t, sections, 2)
+}
+
+func TestMissing(t *testing.T) {
+	template := "## Version\n<!-- the version you use -->\n## Logs\npaste the logs here\n## Notes\n"
+	required := []string{"Version", "Logs", "Steps"}
+
+	markdown := "## Version\n<!-- the version you use -->\n## Logs\npaste the logs here"
+	assert.Equal(t, []string{"Version", "Logs", "Steps"}, Missing(markdown, template, required))
+
+	markdown = "## version\n1.2.0 <!-- the version you use -->\n## Logs\npanic: nil map\n## Steps\nrun it"
+	assert.Len(t, Missing(markdown, template, required), 0)
+}
@@ -0,0 +1,105 @@
+// Package mention finds the @username mentions in the posts and renders them as links to the profiles.
+package mention
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var (
+	// mentionReg the mention should not follow a word, so the email address is not a mention
+	mentionReg    = regexp.MustCompile(`(^|[^\w@./-])@([A-Za-z0-9._-]+)`)
+	codeBlockReg  = regexp.MustCompile("(?s)```.*?```|~~~.*?~~~")
+	inlineCodeReg = regexp.MustCompile("`[^`\n]*`")
+)
+
+// maxUsernameLen the longer name is not a username
+const maxUsernameLen = 30
+
+// Parse the usernames mentioned in the markdown in order, the usernames are lower case and not repeated.
+// The mentions in the code are ignored.
+func Parse(markdown string) (usernames []string) {
+	markdown = codeBlockReg.ReplaceAllString(markdown, " ")
+	markdown = inlineCodeReg.ReplaceAllString(markdown, " ")
+
+	usernames = make([]string, 0)
+	seen := make(map[string]bool)
+	for _, match := range mentionReg.FindAllStringSubmatch(markdown, -1) {
+		username := normalize(match[2])
+		if len(username) == 0 || len(username) > maxUsernameLen || seen[username] {
+			continue
+		}
+		seen[username] = true
+		usernames = append(usernames, username)
+	}
+	return usernames
+}
+
+// Render wrap the mentions of the users in the html with the links to their profiles.
+// Only the usernames in the set are linked, the mentions in the code and links are kept as they are.
+func Render(content string, usernames map[string]bool) string {
+	if len(usernames) == 0 || !strings.Contains(content, "@") {
+		return content
+	}
+	buf := &bytes.Buffer{}
+	skipDepth := 0
+	tokenizer := html.NewTokenizer(strings.NewReader(content))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			if tokenizer.Err() != io.EOF {
+				return content
+			}
+			return buf.String()
+		}
+		raw := tokenizer.Raw()
+		switch tokenType {
+		case html.StartTagToken:
+			if isSkippedTag(tokenizer) {
+				skipDepth++
+			}
+		case html.EndTagToken:
+			if isSkippedTag(tokenizer) && skipDepth > 0 {
+				skipDepth--
+			}
+		case html.TextToken:
+			if skipDepth == 0 {
+				raw = []byte(renderText(string(raw), usernames))
+			}
+		}
+		buf.Write(raw)
+	}
+}
+
+func renderText(text string, usernames map[string]bool) string {
+	return mentionReg.ReplaceAllStringFunc(text, func(match string) string {
+		idx := strings.Index(match, "@")
+		prefix, name := match[:idx], match[idx+1:]
+		username := normalize(name)
+		if !usernames[username] {
+			return match
+		}
+		// the trailing punctuation of the sentence is not a part of the username
+		suffix := name[len(username):]
+		return prefix + `<a href="/users/` + username + `" class="mention">@` + name[:len(username)] + `</a>` + suffix
+	})
+}
+
+// isSkippedTag the mentions in the links and code are not rendered
+func isSkippedTag(tokenizer *html.Tokenizer) bool {
+	name, _ := tokenizer.TagName()
+	switch string(name) {
+	case "a", "code", "pre":
+		return true
+	}
+	return false
+}
+
+// normalize the username is lower case, and the trailing dots or dashes are the punctuation of the sentence
+func normalize(name string) string {
+	return strings.ToLower(strings.TrimRight(name, ".-"))
+}
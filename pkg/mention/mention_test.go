@@ -0,0 +1,27 @@
+package mention
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	markdown := "Thanks @Alice and @bob_1, cc @alice.\n" +
+		"Mail me at someone@example.com, see `@code` and\n```\n@block\n```\n(@carol-)"
+	assert.Equal(t, []string{"alice", "bob_1", "carol"}, Parse(markdown))
+	assert.Len(t, Parse("no mentions here"), 0)
+}
+
+func TestRender(t *testing.T) {
+	usernames := map[string]bool{"alice": true, "bob": true}
+
+	html := `<p>Hi @Alice, @bob. and @nobody</p><pre><code>@alice</code></pre><a href="/x">@bob</a>`
+	expected := `<p>Hi <a href="/users/alice" class="mention">@Alice</a>, ` +
+		`<a href="/users/bob" class="mention">@bob</a>. and @nobody</p>` +
+		`<pre><code>@alice</code></pre><a href="/x">@bob</a>`
+	assert.Equal(t, expected, Render(html, usernames))
+
+	assert.Equal(t, "<p>mail alice@example.com</p>", Render("<p>mail alice@example.com</p>", usernames))
+	assert.Equal(t, "<p>@alice</p>", Render("<p>@alice</p>", nil))
+}
@@ -0,0 +1,71 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ClassifierChecker ask the remote spam classifier, such as Akismet, whether the post is spam.
+// The form of the post is sent to the endpoint, and the classifier responds "true" for spam and "false" for ham.
+type ClassifierChecker struct {
+	endpoint string
+	apiKey   string
+	siteURL  string
+	score    int
+	client   *http.Client
+}
+
+// NewClassifierChecker new classifier checker, the http client can be replaced for testing
+func NewClassifierChecker(endpoint, apiKey, siteURL string, score int, client *http.Client) *ClassifierChecker {
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	return &ClassifierChecker{endpoint: endpoint, apiKey: apiKey, siteURL: siteURL, score: score, client: client}
+}
+
+// Name the name of the checker
+func (cc *ClassifierChecker) Name() string {
+	return "classifier"
+}
+
+// Check send the post to the classifier
+func (cc *ClassifierChecker) Check(ctx context.Context, content *Content) (hits []*Hit, err error) {
+	form := url.Values{}
+	form.Set("api_key", cc.apiKey)
+	form.Set("blog", cc.siteURL)
+	form.Set("user_ip", content.IP)
+	form.Set("user_agent", content.UserAgent)
+	form.Set("comment_type", content.ObjectType)
+	form.Set("comment_content", content.Text())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cc.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := cc.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("classifier responds status %d", resp.StatusCode)
+	}
+	switch strings.TrimSpace(string(body)) {
+	case "true":
+		hits = append(hits, &Hit{Rule: cc.Name(), Score: cc.score, Detail: "classified as spam"})
+	case "false":
+	default:
+		return nil, fmt.Errorf("classifier responds invalid result %q", string(body))
+	}
+	return hits, nil
+}
@@ -0,0 +1,61 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// KeywordChecker the post containing the blocked keywords or matching the blocked patterns fires the rule,
+// every matched keyword or pattern adds the score
+type KeywordChecker struct {
+	keywords []string
+	patterns []*regexp.Regexp
+	score    int
+}
+
+// NewKeywordChecker new keyword checker, the keywords are matched case-insensitively.
+// The error is returned if any of the patterns is not a valid regular expression.
+func NewKeywordChecker(keywords, patterns []string, score int) (checker *KeywordChecker, err error) {
+	checker = &KeywordChecker{score: score}
+	for _, keyword := range keywords {
+		keyword = strings.ToLower(strings.TrimSpace(keyword))
+		if len(keyword) > 0 {
+			checker.keywords = append(checker.keywords, keyword)
+		}
+	}
+	for _, pattern := range patterns {
+		if len(strings.TrimSpace(pattern)) == 0 {
+			continue
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %w", pattern, err)
+		}
+		checker.patterns = append(checker.patterns, re)
+	}
+	return checker, nil
+}
+
+// Name the name of the checker
+func (kc *KeywordChecker) Name() string {
+	return "keyword"
+}
+
+// Check check the text of the post
+func (kc *KeywordChecker) Check(ctx context.Context, content *Content) (hits []*Hit, err error) {
+	text := content.Text()
+	lowerText := strings.ToLower(text)
+	for _, keyword := range kc.keywords {
+		if strings.Contains(lowerText, keyword) {
+			hits = append(hits, &Hit{Rule: kc.Name(), Score: kc.score, Detail: "blocked keyword: " + keyword})
+		}
+	}
+	for _, re := range kc.patterns {
+		if re.MatchString(text) {
+			hits = append(hits, &Hit{Rule: kc.Name(), Score: kc.score, Detail: "blocked pattern: " + re.String()})
+		}
+	}
+	return hits, nil
+}
@@ -0,0 +1,82 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// linkRegexp the links in the html, both in the attributes and in the text
+var linkRegexp = regexp.MustCompile(`(?i)https?://[^\s"'<>]+`)
+
+// LinkChecker the post containing too many links, or linking to the blocked domains fires the rule
+type LinkChecker struct {
+	// maxLinks the post containing more links than it fires the rule, 0 means no limit
+	maxLinks  int
+	linkScore int
+	// blockedDomains the blocked domains, their sub domains are blocked too
+	blockedDomains []string
+	domainScore    int
+}
+
+// NewLinkChecker new link checker
+func NewLinkChecker(maxLinks, linkScore int, blockedDomains []string, domainScore int) *LinkChecker {
+	checker := &LinkChecker{maxLinks: maxLinks, linkScore: linkScore, domainScore: domainScore}
+	for _, domain := range blockedDomains {
+		domain = strings.Trim(strings.ToLower(strings.TrimSpace(domain)), ".")
+		if len(domain) > 0 {
+			checker.blockedDomains = append(checker.blockedDomains, domain)
+		}
+	}
+	return checker
+}
+
+// Name the name of the checker
+func (lc *LinkChecker) Name() string {
+	return "link"
+}
+
+// Check check the links of the post
+func (lc *LinkChecker) Check(ctx context.Context, content *Content) (hits []*Hit, err error) {
+	links := ExtractLinks(content.HTML)
+	if lc.maxLinks > 0 && len(links) > lc.maxLinks {
+		hits = append(hits, &Hit{
+			Rule:   lc.Name(),
+			Score:  lc.linkScore,
+			Detail: fmt.Sprintf("too many links: %d > %d", len(links), lc.maxLinks),
+		})
+	}
+
+	blocked := make(map[string]bool)
+	for _, link := range links {
+		u, err := url.Parse(link)
+		if err != nil {
+			continue
+		}
+		host := strings.ToLower(u.Hostname())
+		for _, domain := range lc.blockedDomains {
+			if blocked[domain] || (host != domain && !strings.HasSuffix(host, "."+domain)) {
+				continue
+			}
+			blocked[domain] = true
+			hits = append(hits, &Hit{Rule: lc.Name(), Score: lc.domainScore, Detail: "blocked domain: " + domain})
+		}
+	}
+	return hits, nil
+}
+
+// ExtractLinks get the distinct links in the html
+func ExtractLinks(html string) (links []string) {
+	exist := make(map[string]bool)
+	for _, link := range linkRegexp.FindAllString(html, -1) {
+		link = strings.TrimRight(link, ".,;:!?)")
+		if exist[link] {
+			continue
+		}
+		exist[link] = true
+		links = append(links, link)
+	}
+	return links
+}
@@ -0,0 +1,81 @@
+package moderation
+
+import (
+	"context"
+	"strings"
+
+	"answer/pkg/htmltext"
+)
+
+// Content the post to be checked
+type Content struct {
+	// ObjectType question, answer or comment
+	ObjectType string
+	UserID     string
+	Title      string
+	// HTML the parsed html of the post
+	HTML      string
+	IP        string
+	UserAgent string
+}
+
+// Text the plain text of the title and the content
+func (c *Content) Text() string {
+	text := htmltext.ClearText(c.HTML)
+	if len(c.Title) > 0 {
+		text = c.Title + "\n" + text
+	}
+	return strings.TrimSpace(text)
+}
+
+// Hit the rule fired by the post
+type Hit struct {
+	// Rule the name of the checker
+	Rule string `json:"rule"`
+	// Score the score added by the rule
+	Score int `json:"score"`
+	// Detail explain why the rule is fired, such as the matched keyword
+	Detail string `json:"detail"`
+}
+
+// Checker check the post, return the fired rules. The checker is pluggable, the pipeline runs all of them
+type Checker interface {
+	Name() string
+	Check(ctx context.Context, content *Content) (hits []*Hit, err error)
+}
+
+// Result the result of running all the checkers
+type Result struct {
+	// Score the sum of the scores of the fired rules
+	Score int
+	Hits  []*Hit
+	// Errors the errors of the checkers which failed, the failed checker is skipped
+	Errors []error
+}
+
+// Pipeline run the checkers one by one
+type Pipeline struct {
+	checkers []Checker
+}
+
+// NewPipeline new pipeline with the checkers
+func NewPipeline(checkers ...Checker) *Pipeline {
+	return &Pipeline{checkers: checkers}
+}
+
+// Run check the post with all the checkers, the score is the sum of the scores of all the fired rules
+func (p *Pipeline) Run(ctx context.Context, content *Content) (result *Result) {
+	result = &Result{Hits: make([]*Hit, 0)}
+	for _, checker := range p.checkers {
+		hits, err := checker.Check(ctx, content)
+		if err != nil {
+			result.Errors = append(result.Errors, err)
+			continue
+		}
+		for _, hit := range hits {
+			result.Score += hit.Score
+			result.Hits = append(result.Hits, hit)
+		}
+	}
+	return result
+}
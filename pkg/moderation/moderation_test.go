@@ -0,0 +1,101 @@
+package moderation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryStore map[string]bool
+
+func (m memoryStore) MarkSeen(ctx context.Context, fingerprint string) (seen bool, err error) {
+	seen = m[fingerprint]
+	m[fingerprint] = true
+	return seen, nil
+}
+
+func TestKeywordChecker(t *testing.T) {
+	_, err := NewKeywordChecker(nil, []string{"(unclosed"}, 10)
+	assert.Error(t, err)
+
+	checker, err := NewKeywordChecker([]string{"Casino", " "}, []string{`\d{3}-\d{4}`}, 10)
+	assert.NoError(t, err)
+	hits, err := checker.Check(context.TODO(), &Content{Title: "Best CASINO", HTML: "<p>call 555-1234</p>"})
+	assert.NoError(t, err)
+	assert.Len(t, hits, 2)
+	assert.Equal(t, "blocked keyword: casino", hits[0].Detail)
+
+	hits, err = checker.Check(context.TODO(), &Content{HTML: "<p>How to sort a slice?</p>"})
+	assert.NoError(t, err)
+	assert.Len(t, hits, 0)
+}
+
+func TestLinkChecker(t *testing.T) {
+	checker := NewLinkChecker(2, 5, []string{"spam.com"}, 20)
+	html := `<p><a href="https://a.com/1">https://a.com/1</a> https://b.com, http://shop.spam.com/x http://spam.com</p>`
+	assert.Len(t, ExtractLinks(html), 4)
+
+	hits, err := checker.Check(context.TODO(), &Content{HTML: html})
+	assert.NoError(t, err)
+	assert.Len(t, hits, 2)
+	assert.Equal(t, 5, hits[0].Score)
+	assert.Equal(t, "blocked domain: spam.com", hits[1].Detail)
+
+	hits, err = checker.Check(context.TODO(), &Content{HTML: `<p>https://notspam.com</p>`})
+	assert.NoError(t, err)
+	assert.Len(t, hits, 0)
+}
+
+func TestRepeatChecker(t *testing.T) {
+	checker := NewRepeatChecker(memoryStore{}, 30)
+	content := &Content{HTML: "<p>Buy the cheapest watches in the world now</p>"}
+	hits, err := checker.Check(context.TODO(), content)
+	assert.NoError(t, err)
+	assert.Len(t, hits, 0)
+	hits, err = checker.Check(context.TODO(), &Content{HTML: "<p>buy the cheapest  watches in the world NOW</p>"})
+	assert.NoError(t, err)
+	assert.Len(t, hits, 1)
+
+	// the short text is ignored
+	for i := 0; i < 2; i++ {
+		hits, err = checker.Check(context.TODO(), &Content{HTML: "<p>thanks</p>"})
+		assert.NoError(t, err)
+		assert.Len(t, hits, 0)
+	}
+}
+
+func TestClassifierChecker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "key", r.PostForm.Get("api_key"))
+		if r.PostForm.Get("comment_content") == "spam" {
+			_, _ = w.Write([]byte("true"))
+			return
+		}
+		_, _ = w.Write([]byte("false"))
+	}))
+	defer server.Close()
+
+	checker := NewClassifierChecker(server.URL, "key", "https://example.com", 50, server.Client())
+	hits, err := checker.Check(context.TODO(), &Content{HTML: "<p>spam</p>"})
+	assert.NoError(t, err)
+	assert.Len(t, hits, 1)
+	hits, err = checker.Check(context.TODO(), &Content{HTML: "<p>ham</p>"})
+	assert.NoError(t, err)
+	assert.Len(t, hits, 0)
+}
+
+func TestPipeline(t *testing.T) {
+	keywordChecker, err := NewKeywordChecker([]string{"casino"}, nil, 10)
+	assert.NoError(t, err)
+	failed := NewClassifierChecker("http://127.0.0.1:0", "", "", 50, nil)
+	pipeline := NewPipeline(keywordChecker, NewLinkChecker(0, 0, []string{"spam.com"}, 20), failed)
+
+	result := pipeline.Run(context.TODO(), &Content{HTML: `<p>casino https://spam.com</p>`})
+	assert.Equal(t, 30, result.Score)
+	assert.Len(t, result.Hits, 2)
+	assert.Len(t, result.Errors, 1)
+}
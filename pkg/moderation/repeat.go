@@ -0,0 +1,51 @@
+package moderation
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode/utf8"
+)
+
+// repeatMinLength the short text such as "thanks" is not regarded as repeated content
+const repeatMinLength = 20
+
+// SeenStore remember the fingerprints of the recent contents
+type SeenStore interface {
+	// MarkSeen remember the fingerprint, return whether it has been seen recently
+	MarkSeen(ctx context.Context, fingerprint string) (seen bool, err error)
+}
+
+// RepeatChecker the post whose text is the same as a recent post fires the rule
+type RepeatChecker struct {
+	store SeenStore
+	score int
+}
+
+// NewRepeatChecker new repeat checker
+func NewRepeatChecker(store SeenStore, score int) *RepeatChecker {
+	return &RepeatChecker{store: store, score: score}
+}
+
+// Name the name of the checker
+func (rc *RepeatChecker) Name() string {
+	return "repeat"
+}
+
+// Check check whether the text of the post has been posted recently
+func (rc *RepeatChecker) Check(ctx context.Context, content *Content) (hits []*Hit, err error) {
+	text := strings.Join(strings.Fields(strings.ToLower(content.Text())), " ")
+	if utf8.RuneCountInString(text) < repeatMinLength {
+		return nil, nil
+	}
+	sum := sha256.Sum256([]byte(text))
+	seen, err := rc.store.MarkSeen(ctx, hex.EncodeToString(sum[:]))
+	if err != nil {
+		return nil, err
+	}
+	if seen {
+		hits = append(hits, &Hit{Rule: rc.Name(), Score: rc.score, Detail: "repeated content"})
+	}
+	return hits, nil
+}
@@ -0,0 +1,299 @@
+// Package tagexpr parses the boolean expressions of the tags, such as "go AND NOT beginner", and matches them
+// against the tags of the questions.
+//
+// The operators are AND, OR and NOT in any case, and the parentheses group the terms. The terms next to each
+// other without an operator are joined by AND. NOT binds tighter than AND, and AND binds tighter than OR.
+package tagexpr
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MaxTerms the expression with more tags is rejected
+const MaxTerms = 20
+
+var (
+	// ErrEmpty the expression has no tag
+	ErrEmpty = errors.New("tagexpr: empty expression")
+	// ErrTooManyTerms the expression has more than MaxTerms tags
+	ErrTooManyTerms = fmt.Errorf("tagexpr: more than %d tags", MaxTerms)
+	// ErrMatchAll the expression matches the questions without any tag of it, such as "NOT beginner"
+	ErrMatchAll = errors.New("tagexpr: the expression matches the questions without any of its tags")
+)
+
+type opType int
+
+const (
+	opTag opType = iota
+	opAnd
+	opOr
+	opNot
+)
+
+// Expr the parsed expression
+type Expr struct {
+	op       opType
+	tag      string
+	operands []*Expr
+}
+
+// Parse the expression, the tags are lowered. The expression which matches the questions
+// without any of its tags is rejected, because it would match nearly all the questions.
+func Parse(s string) (expr *Expr, err error) {
+	p := &parser{tokens: tokenize(s)}
+	if len(p.tokens) == 0 {
+		return nil, ErrEmpty
+	}
+	expr, err = p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("tagexpr: unexpected %q", p.tokens[p.pos])
+	}
+	if p.terms > MaxTerms {
+		return nil, ErrTooManyTerms
+	}
+	if expr.Match(nil) {
+		return nil, ErrMatchAll
+	}
+	return expr, nil
+}
+
+// Match whether the tags satisfy the expression, the tags are compared in lower case
+func (e *Expr) Match(tags []string) bool {
+	set := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		set[strings.ToLower(tag)] = true
+	}
+	return e.match(set)
+}
+
+func (e *Expr) match(set map[string]bool) bool {
+	switch e.op {
+	case opTag:
+		return set[e.tag]
+	case opNot:
+		return !e.operands[0].match(set)
+	case opAnd:
+		for _, operand := range e.operands {
+			if !operand.match(set) {
+				return false
+			}
+		}
+		return true
+	default:
+		for _, operand := range e.operands {
+			if operand.match(set) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// PositiveTags the sorted tags which are not negated. The question matches the expression only if it has
+// at least one of them, so they are used to find the candidate expressions of the question.
+func (e *Expr) PositiveTags() (tags []string) {
+	set := make(map[string]bool)
+	e.collect(set, false)
+	for tag := range set {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+func (e *Expr) collect(set map[string]bool, negated bool) {
+	switch e.op {
+	case opTag:
+		if !negated {
+			set[e.tag] = true
+		}
+	case opNot:
+		e.operands[0].collect(set, !negated)
+	default:
+		for _, operand := range e.operands {
+			operand.collect(set, negated)
+		}
+	}
+}
+
+// Tags the sorted tags of the expression, including the negated ones
+func (e *Expr) Tags() (tags []string) {
+	set := make(map[string]bool)
+	e.collect(set, false)
+	e.collect(set, true)
+	for tag := range set {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// ReplaceTags replace the tags of the expression by the mapping, such as the synonyms by their main tags.
+// The tags not in the mapping are kept.
+func (e *Expr) ReplaceTags(mapping map[string]string) {
+	if e.op == opTag {
+		if tag, ok := mapping[e.tag]; ok {
+			e.tag = strings.ToLower(tag)
+		}
+		return
+	}
+	for _, operand := range e.operands {
+		operand.ReplaceTags(mapping)
+	}
+}
+
+// String the normalized expression, the operators are in upper case and the parentheses are only kept if needed
+func (e *Expr) String() string {
+	return e.format(opOr)
+}
+
+func (e *Expr) format(parent opType) string {
+	switch e.op {
+	case opTag:
+		return e.tag
+	case opNot:
+		return "NOT " + e.operands[0].format(opNot)
+	}
+	parts := make([]string, 0, len(e.operands))
+	for _, operand := range e.operands {
+		parts = append(parts, operand.format(e.op))
+	}
+	sep := " AND "
+	if e.op == opOr {
+		sep = " OR "
+	}
+	s := strings.Join(parts, sep)
+	// OR in AND, or anything in NOT, needs the parentheses
+	if (e.op == opOr && parent != opOr) || (e.op == opAnd && parent == opNot) {
+		return "(" + s + ")"
+	}
+	return s
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+	terms  int
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *parser) parseOr() (*Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	operands := []*Expr{left}
+	for strings.EqualFold(p.peek(), "OR") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, right)
+	}
+	if len(operands) == 1 {
+		return left, nil
+	}
+	return &Expr{op: opOr, operands: operands}, nil
+}
+
+func (p *parser) parseAnd() (*Expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	operands := []*Expr{left}
+	for {
+		token := p.peek()
+		if strings.EqualFold(token, "AND") {
+			p.pos++
+		} else if len(token) == 0 || token == ")" || strings.EqualFold(token, "OR") {
+			break
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, right)
+	}
+	if len(operands) == 1 {
+		return left, nil
+	}
+	return &Expr{op: opAnd, operands: operands}, nil
+}
+
+func (p *parser) parseNot() (*Expr, error) {
+	if strings.EqualFold(p.peek(), "NOT") {
+		p.pos++
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &Expr{op: opNot, operands: []*Expr{operand}}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (*Expr, error) {
+	token := p.peek()
+	switch {
+	case len(token) == 0:
+		return nil, errors.New("tagexpr: unexpected end of expression")
+	case token == "(":
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, errors.New("tagexpr: missing )")
+		}
+		p.pos++
+		return expr, nil
+	case token == ")" || isOperator(token):
+		return nil, fmt.Errorf("tagexpr: unexpected %q", token)
+	}
+	p.pos++
+	p.terms++
+	return &Expr{op: opTag, tag: strings.ToLower(token)}, nil
+}
+
+func isOperator(token string) bool {
+	return strings.EqualFold(token, "AND") || strings.EqualFold(token, "OR") || strings.EqualFold(token, "NOT")
+}
+
+// tokenize split the expression by the spaces, and the parentheses are the tokens themselves
+func tokenize(s string) (tokens []string) {
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')':
+			flush()
+			tokens = append(tokens, string(r))
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
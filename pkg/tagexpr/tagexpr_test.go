@@ -0,0 +1,46 @@
+package tagexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	expr, err := Parse("Go AND NOT beginner")
+	assert.NoError(t, err)
+	assert.Equal(t, "go AND NOT beginner", expr.String())
+	assert.True(t, expr.Match([]string{"go", "concurrency"}))
+	assert.False(t, expr.Match([]string{"go", "Beginner"}))
+	assert.False(t, expr.Match([]string{"rust"}))
+
+	expr, err = Parse("(go or rust) wasm")
+	assert.NoError(t, err)
+	assert.Equal(t, "(go OR rust) AND wasm", expr.String())
+	assert.True(t, expr.Match([]string{"rust", "wasm"}))
+	assert.False(t, expr.Match([]string{"rust"}))
+	assert.Equal(t, []string{"go", "rust", "wasm"}, expr.PositiveTags())
+
+	expr, err = Parse("c++ AND NOT (beginner OR homework)")
+	assert.NoError(t, err)
+	assert.Equal(t, "c++ AND NOT (beginner OR homework)", expr.String())
+	assert.Equal(t, []string{"c++"}, expr.PositiveTags())
+}
+
+func TestParseError(t *testing.T) {
+	for _, s := range []string{"", "  ", "go AND", "(go", "go)", "OR go", "NOT beginner", "go OR NOT beginner"} {
+		_, err := Parse(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestExpr_ReplaceTags(t *testing.T) {
+	expr, err := Parse("golang AND NOT (js OR beginner)")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"beginner", "golang", "js"}, expr.Tags())
+	expr.ReplaceTags(map[string]string{"golang": "go", "js": "JavaScript"})
+	assert.Equal(t, "go AND NOT (javascript OR beginner)", expr.String())
+	assert.Equal(t, []string{"go"}, expr.PositiveTags())
+	assert.True(t, expr.Match([]string{"go"}))
+	assert.False(t, expr.Match([]string{"go", "javascript"}))
+}
@@ -0,0 +1,102 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// Period the time step of the one-time password, in seconds
+	Period = 30
+	// Digits the length of the one-time password
+	Digits = 6
+	// Skew the number of time steps before and after the current one that are still accepted
+	Skew = 1
+
+	secretSize = 20
+)
+
+var b32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret generate a random base32 encoded secret
+func GenerateSecret() (secret string, err error) {
+	buf := make([]byte, secretSize)
+	if _, err = rand.Read(buf); err != nil {
+		return "", err
+	}
+	return b32NoPadding.EncodeToString(buf), nil
+}
+
+// GenerateCode generate the one-time password of the secret at the time, as defined in RFC 6238
+func GenerateCode(secret string, t time.Time) (code string, err error) {
+	return generateCode(secret, uint64(t.Unix()/Period), Digits)
+}
+
+// Validate check the code against the secret, the adjacent time steps are accepted to tolerate clock drift
+func Validate(secret, code string, t time.Time) bool {
+	_, ok := ValidateStep(secret, code, t, -1)
+	return ok
+}
+
+// ValidateStep check the code like Validate, but only the time steps after the last used one are accepted,
+// so that the code can not be replayed. The matched time step is returned to be stored as the last used one.
+func ValidateStep(secret, code string, t time.Time, lastStep int64) (step int64, ok bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != Digits {
+		return 0, false
+	}
+	counter := t.Unix() / Period
+	for i := -Skew; i <= Skew; i++ {
+		step = counter + int64(i)
+		if step <= lastStep {
+			continue
+		}
+		expected, err := generateCode(secret, uint64(step), Digits)
+		if err != nil {
+			return 0, false
+		}
+		if hmac.Equal([]byte(expected), []byte(code)) {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// KeyURI build the otpauth uri used for qr-code enrollment in authenticator apps
+func KeyURI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer + ":" + account)
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprintf("%d", Digits))
+	query.Set("period", fmt.Sprintf("%d", Period))
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+func generateCode(secret string, counter uint64, digits int) (code string, err error) {
+	key, err := b32NoPadding.DecodeString(strings.ToUpper(strings.TrimRight(strings.TrimSpace(secret), "=")))
+	if err != nil {
+		return "", err
+	}
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, value%mod), nil
+}
@@ -0,0 +1,71 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rfcSecret the base32 encoded secret "12345678901234567890" used by RFC 6238 test vectors
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateCodeRFC6238(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "94287082"},
+		{unix: 1111111109, want: "07081804"},
+		{unix: 1111111111, want: "14050471"},
+		{unix: 1234567890, want: "89005924"},
+		{unix: 2000000000, want: "69279037"},
+	}
+	for _, tt := range tests {
+		code, err := generateCode(rfcSecret, uint64(tt.unix/Period), 8)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, code)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.NoError(t, err)
+
+	now := time.Now()
+	code, err := GenerateCode(secret, now)
+	assert.NoError(t, err)
+	assert.Len(t, code, Digits)
+
+	assert.True(t, Validate(secret, code, now))
+	assert.True(t, Validate(secret, code, now.Add(Period*time.Second)))
+	assert.False(t, Validate(secret, code, now.Add(3*Period*time.Second)))
+	assert.False(t, Validate(secret, "", now))
+	assert.False(t, Validate("not base32!", code, now))
+}
+
+func TestValidateStep(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.NoError(t, err)
+
+	now := time.Now()
+	code, err := GenerateCode(secret, now)
+	assert.NoError(t, err)
+
+	step, ok := ValidateStep(secret, code, now, 0)
+	assert.True(t, ok)
+	assert.Equal(t, now.Unix()/Period, step)
+	// the used code can not be replayed
+	_, ok = ValidateStep(secret, code, now, step)
+	assert.False(t, ok)
+	_, ok = ValidateStep(secret, code, now.Add(Period*time.Second), step)
+	assert.False(t, ok)
+}
+
+func TestKeyURI(t *testing.T) {
+	uri := KeyURI("Answer", "answer@answer.com", rfcSecret)
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Answer:answer@answer.com?"))
+	assert.Contains(t, uri, "secret="+rfcSecret)
+	assert.Contains(t, uri, "issuer=Answer")
+}